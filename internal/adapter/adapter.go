@@ -1,11 +1,41 @@
 package adapter
 
+import (
+	"errors"
+	"fmt"
+
+	"skill-hub/internal/diff"
+)
+
+// ErrNotFound 表示 Extract 时目标文件或其中的技能不存在，使用 errors.Is 判断
+var ErrNotFound = errors.New("技能不存在")
+
+// NotFoundf 按 format 生成错误信息，返回的错误满足 errors.Is(err, ErrNotFound)
+func NotFoundf(format string, args ...interface{}) error {
+	return &notFoundError{msg: fmt.Sprintf(format, args...)}
+}
+
+// notFoundError 保留原有错误信息的 ErrNotFound
+type notFoundError struct {
+	msg string
+}
+
+func (e *notFoundError) Error() string { return e.msg }
+
+func (e *notFoundError) Is(target error) bool { return target == ErrNotFound }
+
 // Adapter 定义所有适配器的统一接口
+//
+// Apply 和 Plan 的 content 是 engine.RenderContent 渲染后的内容，适配器原样写入，不再替换变量；
+// variables 只用于记录，不参与渲染。
 type Adapter interface {
 	// Apply 应用技能到目标文件
 	Apply(skillID string, content string, variables map[string]string) error
 
-	// Extract 从目标文件提取技能内容
+	// Plan 预览应用技能后目标文件的变化（不修改文件）
+	Plan(skillID string, content string, variables map[string]string) (*Plan, error)
+
+	// Extract 从目标文件提取技能内容，目标文件或技能不存在时返回 ErrNotFound（或空内容）
 	Extract(skillID string) (string, error)
 
 	// Remove 从目标文件移除技能
@@ -17,3 +47,99 @@ type Adapter interface {
 	// Supports 检查是否支持当前环境
 	Supports() bool
 }
+
+// Orderer 由把多个技能写入同一个文件的适配器实现，按应用顺序排列目标文件中的技能
+type Orderer interface {
+	// PlanOrder 预览按 skillIDs 的顺序排列目标文件中的技能后的变化（不修改文件）
+	PlanOrder(skillIDs []string) (*Plan, error)
+
+	// Order 按 skillIDs 的顺序排列目标文件中的技能，未列出的技能排在最后，顺序不变时不修改文件
+	Order(skillIDs []string) error
+}
+
+// Verifier 由能校验目标文件格式的适配器实现，移除技能后确认目标文件没有损坏
+type Verifier interface {
+	// TargetFiles 返回适配器写入的目标文件（如 .cursorrules、opencode.json），用于修改前备份
+	TargetFiles() ([]string, error)
+
+	// Verify 检查目标文件能否正确解析：标记块成对、JSON 有效，文件不存在时返回nil
+	Verify() error
+}
+
+// DirRemover 由把技能安装为独立目录的适配器实现（如OpenCode的 skills/<名称>），移除技能会删除整个目录
+type DirRemover interface {
+	// SkillDir 返回技能的安装目录，用于移除前备份
+	SkillDir(skillID string) (string, error)
+}
+
+// PlanOrder 预览按 skillIDs 的顺序排列适配器目标文件中的技能后的变化，适配器不需要排列时返回nil
+func PlanOrder(adpt Adapter, skillIDs []string) (*Plan, error) {
+	if orderer, ok := adpt.(Orderer); ok {
+		return orderer.PlanOrder(skillIDs)
+	}
+	return nil, nil
+}
+
+// Order 按 skillIDs 的顺序排列适配器目标文件中的技能，适配器不需要排列时不做任何操作
+func Order(adpt Adapter, skillIDs []string) error {
+	if orderer, ok := adpt.(Orderer); ok {
+		return orderer.Order(skillIDs)
+	}
+	return nil
+}
+
+// Plan 表示一次应用操作对目标文件的预期变更
+type Plan struct {
+	SkillID    string  // 技能ID
+	FilePath   string  // 受影响的文件路径
+	Before     string  // 应用前的文件内容（文件不存在时为空）
+	After      string  // 应用后的文件内容
+	Additional []*Plan // 同一次应用涉及的其他文件（如OpenCode的opencode.json）
+}
+
+// HasChanges 检查计划是否会修改目标文件
+func (p *Plan) HasChanges() bool {
+	if p.Before != p.After {
+		return true
+	}
+	for _, extra := range p.Additional {
+		if extra.HasChanges() {
+			return true
+		}
+	}
+	return false
+}
+
+// Files 返回计划涉及的所有文件路径
+func (p *Plan) Files() []string {
+	files := []string{p.FilePath}
+	for _, extra := range p.Additional {
+		files = append(files, extra.Files()...)
+	}
+	return files
+}
+
+// ChangedFiles 返回计划会修改的文件路径
+func (p *Plan) ChangedFiles() []string {
+	var files []string
+	if p.Before != p.After {
+		files = append(files, p.FilePath)
+	}
+	for _, extra := range p.Additional {
+		files = append(files, extra.ChangedFiles()...)
+	}
+	return files
+}
+
+// Diff 返回计划的统一差异格式文本
+func (p *Plan) Diff() string {
+	fromName := p.FilePath
+	if p.Before == "" {
+		fromName = "/dev/null"
+	}
+	result := diff.Unified(fromName, p.FilePath, p.Before, p.After, diff.DefaultContext)
+	for _, extra := range p.Additional {
+		result += extra.Diff()
+	}
+	return result
+}
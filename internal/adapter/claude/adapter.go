@@ -3,17 +3,21 @@ package claude
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"skill-hub/internal/adapter"
+	"skill-hub/internal/adapter/marker"
 	"skill-hub/internal/config"
 )
 
 // ClaudeAdapter 实现Claude配置文件的适配器
 type ClaudeAdapter struct {
-	configPath string
-	mode       string // "global" 或 "project"
+	configPath  string
+	mode        string // "global" 或 "project"
+	projectPath string // 项目目录，为空时使用当前工作目录
 }
 
 // NewClaudeAdapter 创建新的Claude适配器
@@ -29,6 +33,13 @@ func (a *ClaudeAdapter) WithProjectMode() *ClaudeAdapter {
 	return a
 }
 
+// WithProjectPath 设置为指定项目目录的项目模式
+func (a *ClaudeAdapter) WithProjectPath(path string) *ClaudeAdapter {
+	a.mode = "project"
+	a.projectPath = path
+	return a
+}
+
 // WithGlobalMode 设置为全局模式
 func (a *ClaudeAdapter) WithGlobalMode() *ClaudeAdapter {
 	a.mode = "global"
@@ -37,19 +48,65 @@ func (a *ClaudeAdapter) WithGlobalMode() *ClaudeAdapter {
 
 // Apply 应用技能到Claude配置文件
 func (a *ClaudeAdapter) Apply(skillID string, content string, variables map[string]string) error {
-	// 获取配置文件路径
-	configPath, err := a.getConfigPath()
+	configData, _, settingsPlan, err := a.prepareApply(skillID, content, variables)
 	if err != nil {
 		return err
 	}
-	a.configPath = configPath
 
-	fmt.Printf("应用技能到Claude配置文件: %s\n", configPath)
+	fmt.Printf("应用技能到Claude配置文件: %s\n", a.configPath)
+	slog.Debug("写入技能", "adapter", "claude_code", "skill", skillID, "file", a.configPath)
+
+	// 写入配置文件
+	if err := a.writeConfig(configData); err != nil {
+		return err
+	}
+
+	// 更新技能声明的工具权限
+	if settingsPlan != nil && settingsPlan.HasChanges() {
+		if err := writeSettings(settingsPlan.FilePath, settingsPlan.After); err != nil {
+			return fmt.Errorf("更新%s失败: %w", settingsFile, err)
+		}
+	}
+	return nil
+}
+
+// Plan 预览应用技能后Claude配置文件的变化
+func (a *ClaudeAdapter) Plan(skillID string, content string, variables map[string]string) (*adapter.Plan, error) {
+	configData, before, settingsPlan, err := a.prepareApply(skillID, content, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := json.MarshalIndent(configData, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化JSON失败: %w", err)
+	}
+
+	plan := &adapter.Plan{
+		SkillID:  skillID,
+		FilePath: a.configPath,
+		Before:   before,
+		After:    string(after),
+	}
+	if settingsPlan != nil {
+		plan.Additional = []*adapter.Plan{settingsPlan}
+	}
+	return plan, nil
+}
 
-	// 渲染模板内容
-	renderedContent, err := a.renderTemplate(content, variables)
+// prepareApply 计算应用技能后的配置数据，同时返回原始文件内容和 settings.json 的变化（技能没有声明工具权限时为nil）
+func (a *ClaudeAdapter) prepareApply(skillID string, content string, variables map[string]string) (map[string]interface{}, string, *adapter.Plan, error) {
+	// 获取配置文件路径
+	configPath, err := a.getConfigPath()
 	if err != nil {
-		return fmt.Errorf("渲染模板失败: %w", err)
+		return nil, "", nil, err
+	}
+	a.configPath = configPath
+
+	// 记录原始文件内容
+	var before string
+	if data, err := os.ReadFile(configPath); err == nil {
+		before = string(data)
 	}
 
 	// 读取现有配置
@@ -59,17 +116,26 @@ func (a *ClaudeAdapter) Apply(skillID string, content string, variables map[stri
 			// 文件不存在，创建默认配置
 			configData = a.createDefaultConfig()
 		} else {
-			return fmt.Errorf("读取配置文件失败: %w", err)
+			return nil, "", nil, fmt.Errorf("读取配置文件失败: %w", err)
 		}
 	}
 
 	// 注入技能内容
-	if err := a.injectSkill(configData, skillID, renderedContent); err != nil {
-		return fmt.Errorf("注入技能失败: %w", err)
+	if err := a.injectSkill(configData, skillID, content); err != nil {
+		return nil, "", nil, fmt.Errorf("注入技能失败: %w", err)
 	}
 
-	// 写入配置文件
-	return a.writeConfig(configData)
+	// 按技能声明的 allowed-tools 更新 settings.json 的 permissions.allow
+	allowedTools, err := parseAllowedTools(content)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	settingsPlan, err := a.planPermissions(configData, skillID, allowedTools)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return configData, before, settingsPlan, nil
 }
 
 // Extract 从Claude配置文件提取技能内容
@@ -84,7 +150,7 @@ func (a *ClaudeAdapter) Extract(skillID string) (string, error) {
 	configData, err := a.readConfig()
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", fmt.Errorf("配置文件不存在: %s", configPath)
+			return "", adapter.NotFoundf("配置文件不存在: %s", configPath)
 		}
 		return "", fmt.Errorf("读取配置文件失败: %w", err)
 	}
@@ -110,15 +176,126 @@ func (a *ClaudeAdapter) Remove(skillID string) error {
 		return fmt.Errorf("读取配置文件失败: %w", err)
 	}
 
+	// 收回技能添加的工具权限
+	if entry := findEntry(configData, skillID); entry != nil {
+		if granted := stringList(entry[entryGrantedPermissions]); len(granted) > 0 {
+			if err := a.revokePermissions(configData, skillID, granted); err != nil {
+				return err
+			}
+		}
+	}
+
 	// 移除技能
 	if err := a.removeSkill(configData, skillID); err != nil {
 		return err
 	}
+	slog.Debug("移除技能", "adapter", "claude_code", "skill", skillID, "file", a.configPath)
 
 	// 写入配置文件
 	return a.writeConfig(configData)
 }
 
+// PlanOrder 预览按应用顺序排列Claude配置文件customInstructions中的技能后的变化
+func (a *ClaudeAdapter) PlanOrder(skillIDs []string) (*adapter.Plan, error) {
+	configData, before, err := a.readOrderConfig()
+	if err != nil {
+		return nil, err
+	}
+	plan := &adapter.Plan{FilePath: a.configPath, Before: before, After: before}
+	if configData != nil && orderInstructions(configData, skillIDs) {
+		data, err := json.MarshalIndent(configData, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化JSON失败: %w", err)
+		}
+		plan.After = string(data)
+	}
+	return plan, nil
+}
+
+// Order 按应用顺序排列Claude配置文件customInstructions中的技能，其他指令保持原位
+func (a *ClaudeAdapter) Order(skillIDs []string) error {
+	configData, _, err := a.readOrderConfig()
+	if err != nil {
+		return err
+	}
+	if configData == nil || !orderInstructions(configData, skillIDs) {
+		return nil
+	}
+	return a.writeConfig(configData)
+}
+
+// readOrderConfig 读取排列技能时使用的配置和文件原始内容，文件不存在时配置为nil
+func (a *ClaudeAdapter) readOrderConfig() (map[string]interface{}, string, error) {
+	configPath, err := a.getConfigPath()
+	if err != nil {
+		return nil, "", err
+	}
+	a.configPath = configPath
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	var configData map[string]interface{}
+	if err := json.Unmarshal(data, &configData); err != nil {
+		return nil, "", fmt.Errorf("读取配置文件失败: 解析JSON失败: %w", err)
+	}
+	return configData, string(data), nil
+}
+
+// orderInstructions 按 skillIDs 的顺序交换customInstructions中技能指令的位置，返回顺序是否有变化
+func orderInstructions(configData map[string]interface{}, skillIDs []string) bool {
+	instructions, ok := configData["customInstructions"].([]interface{})
+	if !ok {
+		return false
+	}
+	managed := make(map[string]interface{})
+	var current []string
+	for _, instr := range instructions {
+		if name, ok := managedInstruction(instr); ok {
+			managed[name] = instr
+			current = append(current, name)
+		}
+	}
+	ordered := marker.Order(current, skillIDs)
+
+	// 只交换技能指令所在的位置
+	changed := false
+	next := 0
+	for i, instr := range instructions {
+		name, ok := managedInstruction(instr)
+		if !ok {
+			continue
+		}
+		if next >= len(ordered) {
+			break // 同名技能重复出现时其余位置保持不变
+		}
+		if name != ordered[next] {
+			instructions[i] = managed[ordered[next]]
+			changed = true
+		}
+		next++
+	}
+	return changed
+}
+
+// managedInstruction 检查指令是否为 Skill Hub 写入的技能，返回技能ID
+func managedInstruction(instr interface{}) (string, bool) {
+	instrMap, ok := instr.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	name, ok := instrMap["name"].(string)
+	if !ok {
+		return "", false
+	}
+	content, _ := instrMap["content"].(string)
+	return name, strings.Contains(content, "SKILL-HUB BEGIN:")
+}
+
 // List 列出Claude配置文件中的所有技能
 func (a *ClaudeAdapter) List() ([]string, error) {
 	configPath, err := a.getConfigPath()
@@ -140,6 +317,58 @@ func (a *ClaudeAdapter) List() ([]string, error) {
 	return a.listSkills(configData), nil
 }
 
+// TargetFiles 返回Claude配置文件和 settings.json 的路径
+func (a *ClaudeAdapter) TargetFiles() ([]string, error) {
+	configPath, err := a.getConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	settingsPath, err := a.getSettingsPath()
+	if err != nil {
+		return nil, err
+	}
+	return []string{configPath, settingsPath}, nil
+}
+
+// Verify 检查Claude配置文件和 settings.json 是有效的JSON，且每个技能指令的标记块完整
+func (a *ClaudeAdapter) Verify() error {
+	configPath, err := a.getConfigPath()
+	if err != nil {
+		return err
+	}
+	a.configPath = configPath
+
+	configData, err := a.readConfig()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("%s: %w", configPath, err)
+	}
+	if instructions, exists := configData["customInstructions"]; exists && instructions != nil {
+		instructionsList, ok := instructions.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: customInstructions不是数组", configPath)
+		}
+		for _, instr := range instructionsList {
+			name, managed := managedInstruction(instr)
+			if !managed {
+				continue
+			}
+			content, _ := instr.(map[string]interface{})["content"].(string)
+			if _, err := extractMarkedContent(content, name); err != nil {
+				return fmt.Errorf("%s 中技能 %s 的标记块不完整: %w", configPath, name, err)
+			}
+		}
+	}
+
+	settingsPath, err := a.getSettingsPath()
+	if err != nil {
+		return err
+	}
+	if _, _, err := readSettings(settingsPath); err != nil {
+		return err
+	}
+	return nil
+}
+
 // Supports 检查是否支持当前环境
 func (a *ClaudeAdapter) Supports() bool {
 	// 总是返回true，因为Claude适配器总是可用的
@@ -155,6 +384,9 @@ func (a *ClaudeAdapter) GetConfigPath() (string, error) {
 func (a *ClaudeAdapter) getConfigPath() (string, error) {
 	if a.mode == "project" {
 		// 项目级配置
+		if a.projectPath != "" {
+			return filepath.Join(a.projectPath, ".clauderc"), nil
+		}
 		cwd, err := os.Getwd()
 		if err != nil {
 			return "", fmt.Errorf("获取当前目录失败: %w", err)
@@ -272,17 +504,6 @@ func (a *ClaudeAdapter) createDefaultConfig() map[string]interface{} {
 	}
 }
 
-// renderTemplate 渲染模板内容
-func (a *ClaudeAdapter) renderTemplate(content string, variables map[string]string) (string, error) {
-	// 简单替换变量
-	result := content
-	for key, value := range variables {
-		placeholder := "{{." + key + "}}"
-		result = strings.ReplaceAll(result, placeholder, value)
-	}
-	return result, nil
-}
-
 // injectSkill 注入技能到配置
 func (a *ClaudeAdapter) injectSkill(configData map[string]interface{}, skillID string, content string) error {
 	// 创建带标记块的内容
@@ -331,7 +552,7 @@ func (a *ClaudeAdapter) injectSkill(configData map[string]interface{}, skillID s
 func (a *ClaudeAdapter) extractSkill(configData map[string]interface{}, skillID string) (string, error) {
 	instructions, exists := configData["customInstructions"]
 	if !exists {
-		return "", fmt.Errorf("未找到customInstructions字段")
+		return "", adapter.NotFoundf("未找到customInstructions字段")
 	}
 
 	instructionsList, ok := instructions.([]interface{})
@@ -351,7 +572,7 @@ func (a *ClaudeAdapter) extractSkill(configData map[string]interface{}, skillID
 		}
 	}
 
-	return "", fmt.Errorf("未找到技能 '%s'", skillID)
+	return "", adapter.NotFoundf("未找到技能 '%s'", skillID)
 }
 
 // removeSkill 从配置移除技能
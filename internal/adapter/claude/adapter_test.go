@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -113,50 +114,17 @@ func TestClaudeAdapter(t *testing.T) {
 		}
 	})
 
-	t.Run("Template rendering", func(t *testing.T) {
-		adapter := NewClaudeAdapter()
-
-		tests := []struct {
-			name      string
-			content   string
-			variables map[string]string
-			expected  string
-		}{
-			{
-				name:      "Simple replacement",
-				content:   "Hello {{.Name}}",
-				variables: map[string]string{"Name": "World"},
-				expected:  "Hello World",
-			},
-			{
-				name:      "Multiple replacements",
-				content:   "Project: {{.Project}}, Port: {{.Port}}",
-				variables: map[string]string{"Project": "test", "Port": "8080"},
-				expected:  "Project: test, Port: 8080",
-			},
-			{
-				name:      "No variables",
-				content:   "Static content",
-				variables: map[string]string{},
-				expected:  "Static content",
-			},
+	t.Run("Rendered content is written as is", func(t *testing.T) {
+		// 内容已由 engine.RenderContent 渲染，变量值中的 {{.OTHER}} 不能再被替换
+		adapter := NewClaudeAdapter().WithProjectPath(t.TempDir())
+		plan, err := adapter.Plan("test-skill", "Use {{.OTHER}} literally", map[string]string{"OTHER": "x"})
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
 		}
-
-		for _, tt := range tests {
-			t.Run(tt.name, func(t *testing.T) {
-				result, err := adapter.renderTemplate(tt.content, tt.variables)
-				if err != nil {
-					t.Errorf("renderTemplate() error = %v", err)
-					return
-				}
-
-				if result != tt.expected {
-					t.Errorf("renderTemplate() = %v, want %v", result, tt.expected)
-				}
-			})
+		if !strings.Contains(plan.After, "Use {{.OTHER}} literally") {
+			t.Errorf("Plan().After = %q, want content unchanged", plan.After)
 		}
 	})
-
 	t.Run("Skill injection and extraction", func(t *testing.T) {
 		adapter := NewClaudeAdapter()
 
@@ -269,6 +237,43 @@ func TestClaudeAdapter(t *testing.T) {
 		}
 	})
 
+	t.Run("Order skills", func(t *testing.T) {
+		projectDir := t.TempDir()
+		adapter := NewClaudeAdapter().WithProjectPath(projectDir)
+		configData := adapter.createDefaultConfig()
+		for _, id := range []string{"skill-1", "skill-2"} {
+			if err := adapter.injectSkill(configData, id, "Content for "+id); err != nil {
+				t.Fatalf("injectSkill(%s) error = %v", id, err)
+			}
+		}
+		// 用户自己的指令位于两个技能之间，排序后保持原位
+		instructions := configData["customInstructions"].([]interface{})
+		configData["customInstructions"] = []interface{}{
+			instructions[0],
+			map[string]interface{}{"name": "mine", "content": "my instruction"},
+			instructions[1],
+		}
+		adapter.configPath = filepath.Join(projectDir, ".clauderc")
+		if err := adapter.writeConfig(configData); err != nil {
+			t.Fatalf("writeConfig() error = %v", err)
+		}
+
+		if err := adapter.Order([]string{"skill-2", "skill-1"}); err != nil {
+			t.Fatalf("Order() error = %v", err)
+		}
+		configData, err := adapter.readConfig()
+		if err != nil {
+			t.Fatalf("readConfig() error = %v", err)
+		}
+		var names []string
+		for _, instr := range configData["customInstructions"].([]interface{}) {
+			names = append(names, instr.(map[string]interface{})["name"].(string))
+		}
+		if got := strings.Join(names, ","); got != "skill-2,mine,skill-1" {
+			t.Errorf("Order() instructions = %s, want skill-2,mine,skill-1", got)
+		}
+	})
+
 	t.Run("Supports check", func(t *testing.T) {
 		adapter := NewClaudeAdapter()
 
@@ -0,0 +1,307 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/internal/adapter"
+	"skill-hub/pkg/spec"
+)
+
+// Claude Code 的设置文件，permissions.allow 中的规则预先授权工具调用
+const (
+	settingsDir  = ".claude"
+	settingsFile = "settings.json"
+)
+
+// 技能指令条目中记录工具权限的字段
+const (
+	entryAllowedTools       = "allowedTools"       // 技能声明的 allowed-tools
+	entryGrantedPermissions = "grantedPermissions" // 由 skill-hub 添加到 settings.json 的规则，移除技能时收回
+)
+
+// getSettingsPath 获取 settings.json 路径：项目模式为 <项目>/.claude/settings.json，全局模式为 ~/.claude/settings.json
+func (a *ClaudeAdapter) getSettingsPath() (string, error) {
+	if a.mode == "project" {
+		base := a.projectPath
+		if base == "" {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return "", fmt.Errorf("获取当前目录失败: %w", err)
+			}
+			base = cwd
+		}
+		return filepath.Join(base, settingsDir, settingsFile), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return filepath.Join(homeDir, settingsDir, settingsFile), nil
+}
+
+// parseAllowedTools 从技能内容的frontmatter中读取 allowed-tools，没有frontmatter时返回nil
+func parseAllowedTools(content string) ([]string, error) {
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, nil
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return nil, nil
+	}
+
+	var frontmatter map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content[4:4+end]), &frontmatter); err != nil {
+		return nil, fmt.Errorf("解析frontmatter失败: %w", err)
+	}
+	return spec.ParseAllowedTools(frontmatter["allowed-tools"]), nil
+}
+
+// readSettings 读取 settings.json，文件不存在时返回空配置和空内容
+func readSettings(path string) (map[string]interface{}, string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]interface{}{}, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("读取%s失败: %w", path, err)
+	}
+
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, "", fmt.Errorf("解析%s失败: %w", path, err)
+	}
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+	return settings, string(data), nil
+}
+
+// allowRules 返回 settings.json 中 permissions.allow 的规则
+func allowRules(settings map[string]interface{}) []string {
+	permissions, _ := settings["permissions"].(map[string]interface{})
+	return stringList(permissions["allow"])
+}
+
+// setAllowRules 设置 permissions.allow，规则为空时删除该字段及空的 permissions
+func setAllowRules(settings map[string]interface{}, rules []string) {
+	permissions, _ := settings["permissions"].(map[string]interface{})
+	if permissions == nil {
+		if len(rules) == 0 {
+			return
+		}
+		permissions = map[string]interface{}{}
+		settings["permissions"] = permissions
+	}
+	if len(rules) == 0 {
+		delete(permissions, "allow")
+		if len(permissions) == 0 {
+			delete(settings, "permissions")
+		}
+		return
+	}
+	allow := make([]interface{}, len(rules))
+	for i, rule := range rules {
+		allow[i] = rule
+	}
+	permissions["allow"] = allow
+}
+
+// stringList 将JSON数组转换为字符串列表，忽略非字符串元素
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var list []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// setEntryList 设置指令条目中的列表字段，列表为空时删除该字段
+func setEntryList(entry map[string]interface{}, key string, list []string) {
+	if len(list) == 0 {
+		delete(entry, key)
+		return
+	}
+	values := make([]interface{}, len(list))
+	for i, item := range list {
+		values[i] = item
+	}
+	entry[key] = values
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, value string) []string {
+	var result []string
+	for _, item := range list {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// findEntry 查找技能的指令条目
+func findEntry(configData map[string]interface{}, skillID string) map[string]interface{} {
+	instructions, _ := configData["customInstructions"].([]interface{})
+	for _, instr := range instructions {
+		if entry, ok := instr.(map[string]interface{}); ok {
+			if name, _ := entry["name"].(string); name == skillID {
+				return entry
+			}
+		}
+	}
+	return nil
+}
+
+// releasePermissions 收回技能添加的规则：仍被其他技能声明的规则转交给该技能，否则从 allow 中删除
+func releasePermissions(configData map[string]interface{}, skillID string, rules, allow []string) []string {
+	instructions, _ := configData["customInstructions"].([]interface{})
+	for _, rule := range rules {
+		transferred := false
+		for _, instr := range instructions {
+			entry, ok := instr.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, _ := entry["name"].(string); name == skillID {
+				continue
+			}
+			if containsString(stringList(entry[entryAllowedTools]), rule) {
+				granted := stringList(entry[entryGrantedPermissions])
+				if !containsString(granted, rule) {
+					setEntryList(entry, entryGrantedPermissions, append(granted, rule))
+				}
+				transferred = true
+				break
+			}
+		}
+		if !transferred {
+			allow = removeString(allow, rule)
+		}
+	}
+	return allow
+}
+
+// planPermissions 根据技能声明的 allowed-tools 更新 settings.json 和技能的指令条目，返回 settings.json 的变化
+//
+// 只添加 settings.json 中还没有的规则，并记录在指令条目中；技能不再声明的规则按 releasePermissions 收回。
+// 技能没有声明也没有添加过规则时返回nil。
+func (a *ClaudeAdapter) planPermissions(configData map[string]interface{}, skillID string, declared []string) (*adapter.Plan, error) {
+	entry := findEntry(configData, skillID)
+	if entry == nil {
+		return nil, nil
+	}
+	previous := stringList(entry[entryGrantedPermissions])
+	if len(declared) == 0 && len(previous) == 0 {
+		delete(entry, entryAllowedTools)
+		return nil, nil
+	}
+
+	settingsPath, err := a.getSettingsPath()
+	if err != nil {
+		return nil, err
+	}
+	settings, before, err := readSettings(settingsPath)
+	if err != nil {
+		return nil, err
+	}
+	allow := allowRules(settings)
+
+	var released, granted []string
+	for _, rule := range previous {
+		if containsString(declared, rule) {
+			granted = append(granted, rule)
+		} else {
+			released = append(released, rule)
+		}
+	}
+	setEntryList(entry, entryAllowedTools, declared)
+	allow = releasePermissions(configData, skillID, released, allow)
+	for _, rule := range declared {
+		if !containsString(allow, rule) {
+			allow = append(allow, rule)
+			if !containsString(granted, rule) {
+				granted = append(granted, rule)
+			}
+		}
+	}
+	setEntryList(entry, entryGrantedPermissions, granted)
+	setAllowRules(settings, allow)
+
+	after := before
+	if len(settings) > 0 || before != "" {
+		data, err := json.MarshalIndent(settings, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化JSON失败: %w", err)
+		}
+		if string(data) != strings.TrimSpace(before) {
+			after = string(data) + "\n"
+		}
+	}
+
+	return &adapter.Plan{
+		SkillID:  skillID,
+		FilePath: settingsPath,
+		Before:   before,
+		After:    after,
+	}, nil
+}
+
+// revokePermissions 从 settings.json 收回技能添加的规则
+func (a *ClaudeAdapter) revokePermissions(configData map[string]interface{}, skillID string, granted []string) error {
+	settingsPath, err := a.getSettingsPath()
+	if err != nil {
+		return err
+	}
+	settings, before, err := readSettings(settingsPath)
+	if err != nil || before == "" {
+		return err
+	}
+	allow := allowRules(settings)
+	remaining := releasePermissions(configData, skillID, granted, allow)
+	if len(remaining) == len(allow) {
+		return nil
+	}
+	setAllowRules(settings, remaining)
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化JSON失败: %w", err)
+	}
+	if err := writeSettings(settingsPath, string(data)+"\n"); err != nil {
+		return fmt.Errorf("更新%s失败: %w", settingsFile, err)
+	}
+	return nil
+}
+
+// writeSettings 写入 settings.json（原子操作）
+func writeSettings(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("重命名文件失败: %w", err)
+	}
+	return nil
+}
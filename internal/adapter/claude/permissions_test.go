@@ -0,0 +1,78 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSkillPermissions(t *testing.T) {
+	project := t.TempDir()
+	settingsPath := filepath.Join(project, settingsDir, settingsFile)
+	newAdapter := func() *ClaudeAdapter { return NewClaudeAdapter().WithProjectPath(project) }
+	skill := func(tools string) string {
+		return "---\nname: demo\nallowed-tools: " + tools + "\n---\n# Demo\n"
+	}
+	readAllow := func() []string {
+		settings, _, err := readSettings(settingsPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return allowRules(settings)
+	}
+
+	// 用户已有的规则不记录为技能添加的规则
+	if err := writeSettings(settingsPath, "{\n  \"permissions\": {\n    \"allow\": [\"Read\"]\n  },\n  \"model\": \"opus\"\n}\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := newAdapter().Plan("git-expert", skill("Bash(git:*) Read"), nil)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Additional) != 1 || plan.Additional[0].FilePath != settingsPath || !plan.Additional[0].HasChanges() {
+		t.Fatalf("Plan() should include settings.json changes: %+v", plan.Additional)
+	}
+	if got := strings.Join(readAllow(), ","); got != "Read" {
+		t.Errorf("Plan() must not modify settings.json, allow = %s", got)
+	}
+
+	if err := newAdapter().Apply("git-expert", skill("Bash(git:*) Read"), nil); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if err := newAdapter().Apply("git-review", skill("Bash(git:*)"), nil); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := strings.Join(readAllow(), ","); got != "Read,Bash(git:*)" {
+		t.Errorf("allow after apply = %s", got)
+	}
+
+	// 仍被 git-review 声明的规则保留
+	if err := newAdapter().Remove("git-expert"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if got := strings.Join(readAllow(), ","); got != "Read,Bash(git:*)" {
+		t.Errorf("allow after removing git-expert = %s", got)
+	}
+
+	if err := newAdapter().Remove("git-review"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if got := strings.Join(readAllow(), ","); got != "Read" {
+		t.Errorf("allow after removing all skills = %s", got)
+	}
+	data, _ := os.ReadFile(settingsPath)
+	if !strings.Contains(string(data), `"model": "opus"`) {
+		t.Errorf("other settings should be preserved:\n%s", data)
+	}
+
+	// 没有声明 allowed-tools 的技能不涉及 settings.json
+	plan, err = newAdapter().Plan("plain", "# Plain\n", nil)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Additional) != 0 {
+		t.Errorf("Plan() without allowed-tools = %+v", plan.Additional)
+	}
+}
@@ -2,18 +2,21 @@ package cursor
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
+	"skill-hub/internal/adapter"
+	"skill-hub/internal/adapter/marker"
 	"skill-hub/internal/config"
 )
 
 // CursorAdapter 实现Cursor规则的适配器
 type CursorAdapter struct {
-	filePath string
-	mode     string // "global" 或 "project"
+	filePath    string
+	mode        string // "global" 或 "project"
+	projectPath string // 项目目录，为空时使用当前工作目录
 }
 
 // NewCursorAdapter 创建新的Cursor适配器
@@ -29,46 +32,64 @@ func (a *CursorAdapter) WithProjectMode() *CursorAdapter {
 	return a
 }
 
+// WithProjectPath 设置为指定项目目录的项目模式
+func (a *CursorAdapter) WithProjectPath(path string) *CursorAdapter {
+	a.mode = "project"
+	a.projectPath = path
+	return a
+}
+
 // WithGlobalMode 设置为全局模式
 func (a *CursorAdapter) WithGlobalMode() *CursorAdapter {
 	a.mode = "global"
 	return a
 }
 
-// markerPattern 匹配技能标记块的正则表达式
-var markerPattern = regexp.MustCompile(`(?s)# === SKILL-HUB BEGIN: (?P<id>.*?) ===\n(?P<content>.*?)\n# === SKILL-HUB END: (?P<id2>.*?) ===`)
-
 // Apply 应用技能到.cursorrules文件
 func (a *CursorAdapter) Apply(skillID string, content string, variables map[string]string) error {
-	// 获取配置文件路径
-	filePath, err := a.getFilePath()
+	plan, err := a.Plan(skillID, content, variables)
 	if err != nil {
 		return err
 	}
-	a.filePath = filePath
 
-	fmt.Printf("应用技能到Cursor配置文件: %s\n", filePath)
+	fmt.Printf("应用技能到Cursor配置文件: %s\n", plan.FilePath)
+	slog.Debug("写入技能", "adapter", "cursor", "skill", skillID, "file", plan.FilePath)
+
+	// 写入文件
+	return a.writeFile(plan.After)
+}
 
-	// 渲染模板内容
-	renderedContent, err := a.renderTemplate(content, variables)
+// Plan 预览应用技能后.cursorrules文件的变化
+func (a *CursorAdapter) Plan(skillID string, content string, variables map[string]string) (*adapter.Plan, error) {
+	// 获取配置文件路径
+	filePath, err := a.getFilePath()
 	if err != nil {
-		return fmt.Errorf("渲染模板失败: %w", err)
+		return nil, err
 	}
-
-	// 创建标记块
-	markerBlock := a.createMarkerBlock(skillID, renderedContent)
+	a.filePath = filePath
 
 	// 读取现有文件内容
 	existingContent, err := a.readFile()
 	if err != nil && !os.IsNotExist(err) {
-		return err
+		return nil, err
 	}
 
-	// 替换或添加标记块
-	newContent := a.replaceOrAddMarker(existingContent, skillID, markerBlock)
+	// 解析文档，保留标记块之外的用户内容
+	doc := marker.Parse(existingContent)
+	for _, issue := range doc.Issues() {
+		fmt.Printf("⚠️  修复标记 %s: %s\n", filePath, issue)
+	}
 
-	// 写入文件
-	return a.writeFile(newContent)
+	// 替换或添加标记块
+	doc.Upsert(skillID, content)
+	newContent := doc.String()
+
+	return &adapter.Plan{
+		SkillID:  skillID,
+		FilePath: filePath,
+		Before:   existingContent,
+		After:    newContent,
+	}, nil
 }
 
 // Extract 从.cursorrules文件提取技能内容
@@ -82,21 +103,18 @@ func (a *CursorAdapter) Extract(skillID string) (string, error) {
 	content, err := a.readFile()
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", fmt.Errorf("文件不存在: %s", filePath)
+			return "", adapter.NotFoundf("文件不存在: %s", filePath)
 		}
 		return "", err
 	}
 
 	// 查找标记块
-	matches := markerPattern.FindAllStringSubmatch(content, -1)
-	for _, match := range matches {
-		if len(match) >= 4 && match[1] == skillID && match[3] == skillID {
-			// 提取标记块内的内容
-			return a.extractMarkedContent(content, skillID)
-		}
+	body, ok := marker.Parse(content).Get(skillID)
+	if !ok {
+		return "", adapter.NotFoundf("未找到技能 '%s' 的标记块", skillID)
 	}
 
-	return "", fmt.Errorf("未找到技能 '%s' 的标记块", skillID)
+	return strings.TrimSpace(body), nil
 }
 
 // Remove 从.cursorrules文件移除技能
@@ -115,19 +133,54 @@ func (a *CursorAdapter) Remove(skillID string) error {
 		return err
 	}
 
-	// 移除指定技能的标记块
-	pattern := regexp.MustCompile(fmt.Sprintf(`(?s)# === SKILL-HUB BEGIN: %s ===\n.*?\n# === SKILL-HUB END: %s ===\n?`, regexp.QuoteMeta(skillID), regexp.QuoteMeta(skillID)))
-	newContent := pattern.ReplaceAllString(content, "")
+	// 移除指定技能的标记块，其余内容保持原样
+	doc := marker.Parse(content)
+	if !doc.Remove(skillID) && !doc.HasIssues() {
+		return nil // 技能不存在，无需修改
+	}
+	newContent := doc.String()
+	slog.Debug("移除技能", "adapter", "cursor", "skill", skillID, "file", filePath)
 
 	// 如果内容为空，删除文件
-	newContent = strings.TrimSpace(newContent)
-	if newContent == "" {
+	if strings.TrimSpace(newContent) == "" {
 		return os.Remove(filePath)
 	}
 
 	return a.writeFile(newContent)
 }
 
+// PlanOrder 预览按应用顺序排列.cursorrules文件中的技能标记块后的变化
+func (a *CursorAdapter) PlanOrder(skillIDs []string) (*adapter.Plan, error) {
+	filePath, err := a.getFilePath()
+	if err != nil {
+		return nil, err
+	}
+	a.filePath = filePath
+
+	content, err := a.readFile()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	plan := &adapter.Plan{FilePath: filePath, Before: content, After: content}
+	if doc := marker.Parse(content); doc.Reorder(skillIDs) {
+		plan.After = doc.String()
+	}
+	return plan, nil
+}
+
+// Order 按应用顺序排列.cursorrules文件中的技能标记块，标记块之外的内容保持原位
+func (a *CursorAdapter) Order(skillIDs []string) error {
+	plan, err := a.PlanOrder(skillIDs)
+	if err != nil {
+		return err
+	}
+	if !plan.HasChanges() {
+		return nil
+	}
+	return a.writeFile(plan.After)
+}
+
 // List 列出.cursorrules文件中的所有技能
 func (a *CursorAdapter) List() ([]string, error) {
 	filePath, err := a.getFilePath()
@@ -144,37 +197,53 @@ func (a *CursorAdapter) List() ([]string, error) {
 		return nil, err
 	}
 
-	var skillIDs []string
-	matches := markerPattern.FindAllStringSubmatch(content, -1)
-	for _, match := range matches {
-		if len(match) >= 2 && match[1] == match[3] { // 确保BEGIN和END的ID匹配
-			skillIDs = append(skillIDs, match[1])
-		}
+	skillIDs := marker.Parse(content).IDs()
+	if skillIDs == nil {
+		skillIDs = []string{}
 	}
 
 	return skillIDs, nil
 }
 
+// TargetFiles 返回.cursorrules文件路径
+func (a *CursorAdapter) TargetFiles() ([]string, error) {
+	filePath, err := a.getFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return []string{filePath}, nil
+}
+
+// Verify 检查.cursorrules文件中的标记块是否成对且没有重复
+func (a *CursorAdapter) Verify() error {
+	filePath, err := a.getFilePath()
+	if err != nil {
+		return err
+	}
+	a.filePath = filePath
+
+	content, err := a.readFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if issues := marker.Parse(content).Issues(); len(issues) > 0 {
+		return fmt.Errorf("%s 中的标记块不完整: %s", filePath, issues[0])
+	}
+	return nil
+}
+
 // Supports 检查是否支持当前环境
 func (a *CursorAdapter) Supports() bool {
 	// Cursor适配器总是可用
 	return true
 }
 
-// renderTemplate 渲染模板内容
-func (a *CursorAdapter) renderTemplate(content string, variables map[string]string) (string, error) {
-	// 简单替换变量
-	result := content
-	for key, value := range variables {
-		placeholder := "{{." + key + "}}"
-		result = strings.ReplaceAll(result, placeholder, value)
-	}
-	return result, nil
-}
-
 // createMarkerBlock 创建标记块
 func (a *CursorAdapter) createMarkerBlock(skillID string, content string) string {
-	return fmt.Sprintf("# === SKILL-HUB BEGIN: %s ===\n%s\n# === SKILL-HUB END: %s ===\n", skillID, content, skillID)
+	return marker.Block(skillID, content)
 }
 
 // readFile 读取文件内容
@@ -237,42 +306,23 @@ func fileExists(path string) bool {
 
 // extractMarkedContent 从标记块中提取内容
 func (a *CursorAdapter) extractMarkedContent(content, skillID string) (string, error) {
-	beginMarker := fmt.Sprintf("# === SKILL-HUB BEGIN: %s ===", skillID)
-	endMarker := fmt.Sprintf("# === SKILL-HUB END: %s ===", skillID)
-
-	beginIdx := strings.Index(content, beginMarker)
-	if beginIdx == -1 {
-		return "", fmt.Errorf("未找到开始标记")
+	body, ok := marker.Parse(content).Get(skillID)
+	if !ok {
+		return "", adapter.NotFoundf("未找到技能 '%s' 的标记块", skillID)
 	}
-
-	endIdx := strings.Index(content, endMarker)
-	if endIdx == -1 {
-		return "", fmt.Errorf("未找到结束标记")
-	}
-
-	// 提取标记块内的内容
-	start := beginIdx + len(beginMarker)
-	extracted := strings.TrimSpace(content[start:endIdx])
-
-	return extracted, nil
+	return strings.TrimSpace(body), nil
 }
 
-// replaceOrAddMarker 替换或添加标记块
+// replaceOrAddMarker 替换或添加标记块，标记块之外的内容保持不变
 func (a *CursorAdapter) replaceOrAddMarker(existingContent, skillID, markerBlock string) string {
-	// 尝试替换现有标记块
-	pattern := regexp.MustCompile(fmt.Sprintf(`(?s)# === SKILL-HUB BEGIN: %s ===\n.*?\n# === SKILL-HUB END: %s ===`, regexp.QuoteMeta(skillID), regexp.QuoteMeta(skillID)))
-
-	if pattern.MatchString(existingContent) {
-		return pattern.ReplaceAllString(existingContent, markerBlock)
+	body, ok := marker.Parse(markerBlock).Get(skillID)
+	if !ok {
+		return existingContent
 	}
 
-	// 没有现有标记块，添加到文件末尾
-	existingContent = strings.TrimSpace(existingContent)
-	if existingContent == "" {
-		return markerBlock
-	}
-
-	return existingContent + "\n\n" + markerBlock
+	doc := marker.Parse(existingContent)
+	doc.Upsert(skillID, body)
+	return doc.String()
 }
 
 // GetFilePath 获取适配器管理的文件路径（公开方法）
@@ -284,6 +334,9 @@ func (a *CursorAdapter) GetFilePath() (string, error) {
 func (a *CursorAdapter) getFilePath() (string, error) {
 	if a.mode == "project" {
 		// 项目级配置
+		if a.projectPath != "" {
+			return filepath.Join(a.projectPath, ".cursorrules"), nil
+		}
 		cwd, err := os.Getwd()
 		if err != nil {
 			return "", fmt.Errorf("获取当前目录失败: %w", err)
@@ -87,50 +87,17 @@ func TestCursorAdapter(t *testing.T) {
 		}
 	})
 
-	t.Run("Template rendering", func(t *testing.T) {
-		adapter := NewCursorAdapter()
-
-		tests := []struct {
-			name      string
-			content   string
-			variables map[string]string
-			expected  string
-		}{
-			{
-				name:      "Simple replacement",
-				content:   "Hello {{.Name}}",
-				variables: map[string]string{"Name": "World"},
-				expected:  "Hello World",
-			},
-			{
-				name:      "Multiple replacements",
-				content:   "Project: {{.Project}}, Port: {{.Port}}",
-				variables: map[string]string{"Project": "test", "Port": "8080"},
-				expected:  "Project: test, Port: 8080",
-			},
-			{
-				name:      "No variables",
-				content:   "Static content",
-				variables: map[string]string{},
-				expected:  "Static content",
-			},
-		}
-
-		for _, tt := range tests {
-			t.Run(tt.name, func(t *testing.T) {
-				result, err := adapter.renderTemplate(tt.content, tt.variables)
-				if err != nil {
-					t.Errorf("renderTemplate() error = %v", err)
-					return
-				}
-
-				if result != tt.expected {
-					t.Errorf("renderTemplate() = %v, want %v", result, tt.expected)
-				}
-			})
+	t.Run("Rendered content is written as is", func(t *testing.T) {
+		// 内容已由 engine.RenderContent 渲染，变量值中的 {{.OTHER}} 不能再被替换
+		adapter := NewCursorAdapter().WithProjectPath(t.TempDir())
+		plan, err := adapter.Plan("test-skill", "Use {{.OTHER}} literally", map[string]string{"OTHER": "x"})
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		if !contains(plan.After, "Use {{.OTHER}} literally") {
+			t.Errorf("Plan().After = %q, want content unchanged", plan.After)
 		}
 	})
-
 	t.Run("Marker block operations", func(t *testing.T) {
 		adapter := NewCursorAdapter()
 
@@ -199,6 +166,33 @@ func TestCursorAdapter(t *testing.T) {
 		}
 	})
 
+	t.Run("Verify markers", func(t *testing.T) {
+		dir := t.TempDir()
+		adapter := NewCursorAdapter().WithProjectPath(dir)
+		if err := adapter.Verify(); err != nil {
+			t.Errorf("Verify() without file error = %v", err)
+		}
+
+		path := filepath.Join(dir, ".cursorrules")
+		valid := "user rules\n# === SKILL-HUB BEGIN: a ===\nrule\n# === SKILL-HUB END: a ===\n"
+		if err := os.WriteFile(path, []byte(valid), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := adapter.Verify(); err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+		if files, err := adapter.TargetFiles(); err != nil || len(files) != 1 || files[0] != path {
+			t.Errorf("TargetFiles() = %v, %v, want [%s]", files, err, path)
+		}
+
+		if err := os.WriteFile(path, []byte("# === SKILL-HUB BEGIN: a ===\nrule\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := adapter.Verify(); err == nil {
+			t.Error("Verify() expected error for unterminated marker")
+		}
+	})
+
 	t.Run("Supports check", func(t *testing.T) {
 		adapter := NewCursorAdapter()
 
@@ -0,0 +1,351 @@
+package marker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	beginPattern = regexp.MustCompile(`^# === SKILL-HUB BEGIN: (.+?) ===\s*$`)
+	endPattern   = regexp.MustCompile(`^# === SKILL-HUB END: (.+?) ===\s*$`)
+)
+
+// BeginLine 返回技能标记块的开始行
+func BeginLine(skillID string) string {
+	return fmt.Sprintf("# === SKILL-HUB BEGIN: %s ===", skillID)
+}
+
+// EndLine 返回技能标记块的结束行
+func EndLine(skillID string) string {
+	return fmt.Sprintf("# === SKILL-HUB END: %s ===", skillID)
+}
+
+// Block 生成完整的技能标记块（以换行结尾）
+func Block(skillID, body string) string {
+	return BeginLine(skillID) + "\n" + body + "\n" + EndLine(skillID) + "\n"
+}
+
+// IssueKind 标记问题类型
+type IssueKind int
+
+const (
+	// IssueUnterminated 只有BEGIN没有匹配的END
+	IssueUnterminated IssueKind = iota
+	// IssueOrphanEnd 没有匹配BEGIN的END
+	IssueOrphanEnd
+	// IssueDuplicate 同一技能出现多个标记块
+	IssueDuplicate
+)
+
+// Issue 描述解析时发现并修复的标记问题
+type Issue struct {
+	Kind    IssueKind
+	SkillID string
+	Line    int // 1起始的行号
+}
+
+// String 返回问题的可读描述
+func (i Issue) String() string {
+	switch i.Kind {
+	case IssueUnterminated:
+		return fmt.Sprintf("第%d行: 技能 %s 的BEGIN标记缺少END，已移除该标记并保留内容", i.Line, i.SkillID)
+	case IssueOrphanEnd:
+		return fmt.Sprintf("第%d行: 技能 %s 的END标记没有对应的BEGIN，已移除", i.Line, i.SkillID)
+	case IssueDuplicate:
+		return fmt.Sprintf("第%d行: 技能 %s 的标记块重复，已移除重复块", i.Line, i.SkillID)
+	}
+	return fmt.Sprintf("第%d行: 技能 %s 的标记异常", i.Line, i.SkillID)
+}
+
+// segment 文档中的一段内容，ID为空表示用户文本
+type segment struct {
+	id    string
+	lines []string
+}
+
+// Document 由用户文本和技能标记块组成的文档
+//
+// 解析时会修复不成对的标记：缺少END的BEGIN标记行被移除，其后内容作为用户文本保留；
+// 孤立的END标记行被移除；同一技能的重复标记块只保留第一个。
+type Document struct {
+	segments        []segment
+	issues          []Issue
+	trailingNewline bool
+}
+
+// Parse 解析文档内容
+func Parse(content string) *Document {
+	doc := &Document{trailingNewline: content == "" || strings.HasSuffix(content, "\n")}
+	if content == "" {
+		return doc
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	var (
+		openID    string
+		openLine  int
+		openBody  []string
+		inBlock   bool
+		seenBlock = make(map[string]bool)
+	)
+
+	// closeUnterminated 将未闭合块的内容降级为用户文本
+	closeUnterminated := func() {
+		doc.issues = append(doc.issues, Issue{Kind: IssueUnterminated, SkillID: openID, Line: openLine})
+		doc.appendText(openBody...)
+		inBlock, openBody = false, nil
+	}
+
+	for idx, line := range lines {
+		lineNo := idx + 1
+
+		if m := beginPattern.FindStringSubmatch(line); m != nil {
+			if inBlock {
+				closeUnterminated()
+			}
+			inBlock, openID, openLine, openBody = true, m[1], lineNo, nil
+			continue
+		}
+
+		if m := endPattern.FindStringSubmatch(line); m != nil {
+			if inBlock && m[1] == openID {
+				if seenBlock[openID] {
+					doc.issues = append(doc.issues, Issue{Kind: IssueDuplicate, SkillID: openID, Line: openLine})
+				} else {
+					seenBlock[openID] = true
+					doc.segments = append(doc.segments, segment{id: openID, lines: openBody})
+				}
+				inBlock, openBody = false, nil
+				continue
+			}
+			doc.issues = append(doc.issues, Issue{Kind: IssueOrphanEnd, SkillID: m[1], Line: lineNo})
+			continue
+		}
+
+		if inBlock {
+			openBody = append(openBody, line)
+		} else {
+			doc.appendText(line)
+		}
+	}
+
+	if inBlock {
+		closeUnterminated()
+	}
+
+	return doc
+}
+
+// Repair 修复文档中不成对或重复的标记，返回修复后的内容和发现的问题
+func Repair(content string) (string, []Issue) {
+	doc := Parse(content)
+	if !doc.HasIssues() {
+		return content, nil
+	}
+	return doc.String(), doc.Issues()
+}
+
+// Issues 返回解析时发现的标记问题
+func (d *Document) Issues() []Issue {
+	return d.issues
+}
+
+// HasIssues 检查解析时是否发现标记问题
+func (d *Document) HasIssues() bool {
+	return len(d.issues) > 0
+}
+
+// IDs 按出现顺序返回文档中的技能ID
+func (d *Document) IDs() []string {
+	var ids []string
+	for _, seg := range d.segments {
+		if seg.id != "" {
+			ids = append(ids, seg.id)
+		}
+	}
+	return ids
+}
+
+// Get 获取技能标记块内的内容
+func (d *Document) Get(skillID string) (string, bool) {
+	if idx := d.indexOf(skillID); idx >= 0 {
+		return strings.Join(d.segments[idx].lines, "\n"), true
+	}
+	return "", false
+}
+
+// Upsert 替换技能标记块内容，不存在时追加到文档末尾
+func (d *Document) Upsert(skillID, body string) {
+	lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+
+	if idx := d.indexOf(skillID); idx >= 0 {
+		d.segments[idx].lines = lines
+		return
+	}
+
+	// 文档只有空白时直接替换为新块
+	if d.isBlank() {
+		d.segments = nil
+	}
+
+	// 与前面的用户文本之间保留一个空行
+	if n := len(d.segments); n > 0 {
+		last := &d.segments[n-1]
+		if last.id != "" || strings.TrimSpace(last.lines[len(last.lines)-1]) != "" {
+			d.appendText("")
+		}
+	}
+
+	d.segments = append(d.segments, segment{id: skillID, lines: lines})
+	d.trailingNewline = true
+}
+
+// Remove 移除技能标记块，返回是否找到
+func (d *Document) Remove(skillID string) bool {
+	idx := d.indexOf(skillID)
+	if idx < 0 {
+		return false
+	}
+	d.segments = append(d.segments[:idx], d.segments[idx+1:]...)
+
+	// 移除块与前文之间的分隔空行，避免反复应用/移除后空行累积
+	if idx > 0 && d.segments[idx-1].id == "" {
+		prev := &d.segments[idx-1]
+		nextBlank := idx >= len(d.segments) ||
+			(d.segments[idx].id == "" && strings.TrimSpace(d.segments[idx].lines[0]) == "")
+		if nextBlank && strings.TrimSpace(prev.lines[len(prev.lines)-1]) == "" {
+			prev.lines = prev.lines[:len(prev.lines)-1]
+			if len(prev.lines) == 0 {
+				d.segments = append(d.segments[:idx-1], d.segments[idx:]...)
+			}
+		}
+	}
+
+	// 合并相邻的用户文本段
+	d.mergeText()
+	return true
+}
+
+// Reorder 按 order 重新排列技能标记块，返回顺序是否有变化
+//
+// 标记块之间的用户文本保持原位，只交换各位置上的标记块；order 中未出现的技能排在最后并保持原有顺序。
+func (d *Document) Reorder(order []string) bool {
+	current := d.IDs()
+	ordered := Order(current, order)
+	changed := false
+	blocks := make(map[string]segment, len(current))
+	for _, seg := range d.segments {
+		if seg.id != "" {
+			blocks[seg.id] = seg
+		}
+	}
+	next := 0
+	for i, seg := range d.segments {
+		if seg.id == "" {
+			continue
+		}
+		if seg.id != ordered[next] {
+			d.segments[i] = blocks[ordered[next]]
+			changed = true
+		}
+		next++
+	}
+	return changed
+}
+
+// Order 按 order 排列 current 中的技能ID，order 中未出现的技能排在最后并保持原有顺序
+func Order(current, order []string) []string {
+	present := make(map[string]bool, len(current))
+	for _, id := range current {
+		present[id] = true
+	}
+	result := make([]string, 0, len(current))
+	placed := make(map[string]bool, len(current))
+	for _, id := range order {
+		if present[id] && !placed[id] {
+			result = append(result, id)
+			placed[id] = true
+		}
+	}
+	for _, id := range current {
+		if !placed[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// String 渲染文档内容
+func (d *Document) String() string {
+	var lines []string
+	for _, seg := range d.segments {
+		if seg.id == "" {
+			lines = append(lines, seg.lines...)
+			continue
+		}
+		lines = append(lines, BeginLine(seg.id))
+		lines = append(lines, seg.lines...)
+		lines = append(lines, EndLine(seg.id))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	result := strings.Join(lines, "\n")
+	if d.trailingNewline {
+		result += "\n"
+	}
+	return result
+}
+
+// indexOf 查找技能标记块所在的段
+func (d *Document) indexOf(skillID string) int {
+	for i, seg := range d.segments {
+		if seg.id == skillID {
+			return i
+		}
+	}
+	return -1
+}
+
+// appendText 追加用户文本行，与前一个文本段合并
+func (d *Document) appendText(lines ...string) {
+	if len(lines) == 0 {
+		return
+	}
+	if n := len(d.segments); n > 0 && d.segments[n-1].id == "" {
+		d.segments[n-1].lines = append(d.segments[n-1].lines, lines...)
+		return
+	}
+	d.segments = append(d.segments, segment{lines: append([]string(nil), lines...)})
+}
+
+// mergeText 合并相邻的用户文本段
+func (d *Document) mergeText() {
+	var merged []segment
+	for _, seg := range d.segments {
+		if n := len(merged); n > 0 && seg.id == "" && merged[n-1].id == "" {
+			merged[n-1].lines = append(merged[n-1].lines, seg.lines...)
+			continue
+		}
+		merged = append(merged, seg)
+	}
+	d.segments = merged
+}
+
+// isBlank 检查文档是否只包含空白用户文本
+func (d *Document) isBlank() bool {
+	for _, seg := range d.segments {
+		if seg.id != "" {
+			return false
+		}
+		for _, line := range seg.lines {
+			if strings.TrimSpace(line) != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
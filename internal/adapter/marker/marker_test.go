@@ -0,0 +1,211 @@
+package marker
+
+import (
+	"testing"
+)
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		id       string
+		body     string
+		want     string
+	}{
+		{
+			name:     "空文件",
+			existing: "",
+			id:       "a",
+			body:     "rule a",
+			want:     Block("a", "rule a"),
+		},
+		{
+			name:     "追加到用户内容之后",
+			existing: "# my rules\nkeep me",
+			id:       "a",
+			body:     "rule a",
+			want:     "# my rules\nkeep me\n\n" + Block("a", "rule a"),
+		},
+		{
+			name:     "原地替换并保留周围内容",
+			existing: "top\n" + Block("a", "old") + "middle\n" + Block("b", "rule b") + "bottom\n",
+			id:       "a",
+			body:     "new",
+			want:     "top\n" + Block("a", "new") + "middle\n" + Block("b", "rule b") + "bottom\n",
+		},
+		{
+			name:     "用户调整块顺序后仍原地替换",
+			existing: Block("b", "rule b") + "notes\n" + Block("a", "old"),
+			id:       "a",
+			body:     "new",
+			want:     Block("b", "rule b") + "notes\n" + Block("a", "new"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Parse(tt.existing)
+			doc.Upsert(tt.id, tt.body)
+			if got := doc.String(); got != tt.want {
+				t.Errorf("Upsert() = %q, want %q", got, tt.want)
+			}
+
+			// 重复应用应保持幂等
+			again := Parse(doc.String())
+			again.Upsert(tt.id, tt.body)
+			if got := again.String(); got != tt.want {
+				t.Errorf("Upsert() twice = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			name:     "移除后恢复原始用户内容",
+			existing: "user rules\n\n" + Block("a", "rule a"),
+			want:     "user rules\n",
+		},
+		{
+			name:     "移除中间块",
+			existing: "top\n" + Block("a", "rule a") + "bottom\n",
+			want:     "top\nbottom\n",
+		},
+		{
+			name:     "只有标记块",
+			existing: Block("a", "rule a"),
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Parse(tt.existing)
+			if !doc.Remove("a") {
+				t.Fatal("Remove() = false, want true")
+			}
+			if got := doc.String(); got != tt.want {
+				t.Errorf("Remove() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("技能不存在", func(t *testing.T) {
+		doc := Parse("text\n")
+		if doc.Remove("missing") {
+			t.Error("Remove() = true, want false")
+		}
+	})
+}
+
+func TestReorder(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		order    []string
+		want     string
+		changed  bool
+	}{
+		{
+			name:     "按顺序交换标记块，用户文本保持原位",
+			existing: "top\n" + Block("a", "rule a") + "middle\n" + Block("b", "rule b") + "bottom\n",
+			order:    []string{"b", "a"},
+			want:     "top\n" + Block("b", "rule b") + "middle\n" + Block("a", "rule a") + "bottom\n",
+			changed:  true,
+		},
+		{
+			name:     "未列出的技能排在最后",
+			existing: Block("c", "rule c") + Block("a", "rule a") + Block("b", "rule b"),
+			order:    []string{"b", "missing"},
+			want:     Block("b", "rule b") + Block("c", "rule c") + Block("a", "rule a"),
+			changed:  true,
+		},
+		{
+			name:     "顺序已一致",
+			existing: Block("a", "rule a") + "\n" + Block("b", "rule b"),
+			order:    []string{"a", "b"},
+			want:     Block("a", "rule a") + "\n" + Block("b", "rule b"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Parse(tt.existing)
+			if changed := doc.Reorder(tt.order); changed != tt.changed {
+				t.Errorf("Reorder() = %t, want %t", changed, tt.changed)
+			}
+			if got := doc.String(); got != tt.want {
+				t.Errorf("Reorder() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		kinds []IssueKind
+		ids   []string
+	}{
+		{
+			name:  "缺少END时保留内容为用户文本",
+			input: BeginLine("a") + "\nuser text\n" + Block("b", "rule b"),
+			want:  "user text\n" + Block("b", "rule b"),
+			kinds: []IssueKind{IssueUnterminated},
+			ids:   []string{"b"},
+		},
+		{
+			name:  "孤立的END",
+			input: "text\n" + EndLine("a") + "\nmore\n",
+			want:  "text\nmore\n",
+			kinds: []IssueKind{IssueOrphanEnd},
+		},
+		{
+			name:  "重复块",
+			input: Block("a", "first") + Block("a", "second"),
+			want:  Block("a", "first"),
+			kinds: []IssueKind{IssueDuplicate},
+			ids:   []string{"a"},
+		},
+		{
+			name:  "正常文档无需修复",
+			input: "x\n" + Block("a", "rule"),
+			want:  "x\n" + Block("a", "rule"),
+			ids:   []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, issues := Repair(tt.input)
+			if got != tt.want {
+				t.Errorf("Repair() = %q, want %q", got, tt.want)
+			}
+			if len(issues) != len(tt.kinds) {
+				t.Fatalf("Repair() issues = %v, want %d", issues, len(tt.kinds))
+			}
+			for i, kind := range tt.kinds {
+				if issues[i].Kind != kind {
+					t.Errorf("issue[%d].Kind = %v, want %v", i, issues[i].Kind, kind)
+				}
+			}
+
+			ids := Parse(tt.input).IDs()
+			if len(ids) != len(tt.ids) {
+				t.Fatalf("IDs() = %v, want %v", ids, tt.ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.ids[i] {
+					t.Errorf("IDs()[%d] = %s, want %s", i, ids[i], tt.ids[i])
+				}
+			}
+		})
+	}
+}
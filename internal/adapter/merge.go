@@ -0,0 +1,68 @@
+package adapter
+
+import (
+	"fmt"
+	"os"
+
+	"skill-hub/internal/diff"
+)
+
+// 合并冲突标记后附带的说明
+const (
+	mergeLocalLabel    = "本地修改"
+	mergeUpstreamLabel = "技能更新"
+)
+
+// MergeResult 表示合并目标文件中本地修改的结果
+type MergeResult struct {
+	Merged    bool // 目标文件有本地修改且技能内容有变化，已进行三方合并
+	Conflicts int  // 合并冲突数，冲突处写入了冲突标记
+}
+
+// PlanMerge 计算应用技能的变更计划，目标文件有本地修改且技能内容也有变化时进行三方合并
+//
+// 共同祖先为按 base（上次应用的渲染内容）生成的文件，本地为目标文件的当前内容，
+// 更新为按 content（新渲染的内容）生成的文件。没有本地修改、技能内容没有变化或两者相同时
+// 返回普通的变更计划；合并后的内容保存在计划的 After 中，使用 ApplyMerged 写入。
+func PlanMerge(adpt Adapter, skillID, base, content string, variables map[string]string) (*Plan, MergeResult, error) {
+	plan, err := adpt.Plan(skillID, content, variables)
+	if err != nil {
+		return nil, MergeResult{}, err
+	}
+	ours := plan.Before
+	if ours == "" || ours == plan.After {
+		return plan, MergeResult{}, nil
+	}
+	basePlan, err := adpt.Plan(skillID, base, variables)
+	if err != nil {
+		return nil, MergeResult{}, err
+	}
+	if ours == basePlan.After || plan.After == basePlan.After {
+		return plan, MergeResult{}, nil
+	}
+
+	merged, conflicts := diff.Merge3(basePlan.After, ours, plan.After, mergeLocalLabel, mergeUpstreamLabel)
+	plan.After = merged
+	return plan, MergeResult{Merged: true, Conflicts: conflicts}, nil
+}
+
+// ApplyMerged 应用技能，进行了三方合并时再写入计划中合并后的目标文件内容
+func ApplyMerged(adpt Adapter, plan *Plan, result MergeResult, content string, variables map[string]string) error {
+	if err := adpt.Apply(plan.SkillID, content, variables); err != nil {
+		return err
+	}
+	if !result.Merged {
+		return nil
+	}
+	data, err := os.ReadFile(plan.FilePath)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", plan.FilePath, err)
+	}
+	if string(data) == plan.After {
+		return nil
+	}
+	if err := os.WriteFile(plan.FilePath, []byte(plan.After), 0644); err != nil {
+		return fmt.Errorf("写入合并后的 %s 失败: %w", plan.FilePath, err)
+	}
+	return nil
+}
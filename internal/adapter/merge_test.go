@@ -0,0 +1,89 @@
+package adapter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fileAdapter 将技能内容加上标题写入单个文件的测试适配器
+type fileAdapter struct {
+	path string
+}
+
+func (a *fileAdapter) render(content string) string {
+	return "# skill\n" + content
+}
+
+func (a *fileAdapter) Apply(skillID, content string, variables map[string]string) error {
+	return os.WriteFile(a.path, []byte(a.render(content)), 0644)
+}
+
+func (a *fileAdapter) Plan(skillID, content string, variables map[string]string) (*Plan, error) {
+	before, _ := os.ReadFile(a.path)
+	return &Plan{SkillID: skillID, FilePath: a.path, Before: string(before), After: a.render(content)}, nil
+}
+
+func (a *fileAdapter) Extract(skillID string) (string, error) { return "", nil }
+func (a *fileAdapter) Remove(skillID string) error            { return nil }
+func (a *fileAdapter) List() ([]string, error)                { return nil, nil }
+func (a *fileAdapter) Supports() bool                         { return true }
+
+func TestPlanMerge(t *testing.T) {
+	base := "rule 1\nrule 2\nrule 3\n"
+	adpt := &fileAdapter{path: filepath.Join(t.TempDir(), "SKILL.md")}
+	if err := adpt.Apply("demo", base, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("没有本地修改时直接使用新内容", func(t *testing.T) {
+		plan, result, err := PlanMerge(adpt, "demo", base, "rule 1\nrule 2\nrule 3\nrule 4\n", nil)
+		if err != nil || result.Merged {
+			t.Fatalf("PlanMerge() = %+v, %v", result, err)
+		}
+		if plan.After != "# skill\nrule 1\nrule 2\nrule 3\nrule 4\n" {
+			t.Errorf("After = %q", plan.After)
+		}
+	})
+
+	// 本地修改第一条规则
+	os.WriteFile(adpt.path, []byte("# skill\nrule 1 (local)\nrule 2\nrule 3\n"), 0644)
+
+	t.Run("技能内容没有变化时不合并", func(t *testing.T) {
+		_, result, err := PlanMerge(adpt, "demo", base, base, nil)
+		if err != nil || result.Merged {
+			t.Fatalf("PlanMerge() = %+v, %v", result, err)
+		}
+	})
+
+	t.Run("合并不同区域的修改", func(t *testing.T) {
+		content := "rule 1\nrule 2\nrule 3\nrule 4\n"
+		plan, result, err := PlanMerge(adpt, "demo", base, content, nil)
+		if err != nil || !result.Merged || result.Conflicts != 0 {
+			t.Fatalf("PlanMerge() = %+v, %v", result, err)
+		}
+		want := "# skill\nrule 1 (local)\nrule 2\nrule 3\nrule 4\n"
+		if plan.After != want {
+			t.Errorf("After = %q, want %q", plan.After, want)
+		}
+		if err := ApplyMerged(adpt, plan, result, content, nil); err != nil {
+			t.Fatalf("ApplyMerged() error = %v", err)
+		}
+		if data, _ := os.ReadFile(adpt.path); string(data) != want {
+			t.Errorf("file = %q, want %q", data, want)
+		}
+	})
+
+	t.Run("同一处修改写入冲突标记", func(t *testing.T) {
+		os.WriteFile(adpt.path, []byte("# skill\nrule 1 (local)\nrule 2\nrule 3\n"), 0644)
+		plan, result, err := PlanMerge(adpt, "demo", base, "rule 1 (upstream)\nrule 2\nrule 3\n", nil)
+		if err != nil || !result.Merged || result.Conflicts != 1 {
+			t.Fatalf("PlanMerge() = %+v, %v", result, err)
+		}
+		want := "<<<<<<< 本地修改\nrule 1 (local)\n=======\nrule 1 (upstream)\n>>>>>>> 技能更新\n"
+		if !strings.Contains(plan.After, want) {
+			t.Errorf("After = %q, want to contain %q", plan.After, want)
+		}
+	})
+}
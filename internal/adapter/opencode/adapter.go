@@ -2,11 +2,14 @@ package opencode
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"skill-hub/internal/adapter"
 	"skill-hub/internal/config"
+	"skill-hub/pkg/spec"
 )
 
 // OpenCodeAdapter 实现OpenCode适配器
@@ -28,44 +31,109 @@ func (a *OpenCodeAdapter) WithProjectMode() *OpenCodeAdapter {
 	return a
 }
 
+// WithProjectPath 设置为指定项目目录的项目级模式
+func (a *OpenCodeAdapter) WithProjectPath(path string) *OpenCodeAdapter {
+	a.mode = "project"
+	a.basePath = filepath.Join(path, ".agents")
+	return a
+}
+
 // WithGlobalMode 设置为全局级模式
 func (a *OpenCodeAdapter) WithGlobalMode() *OpenCodeAdapter {
 	a.mode = "global"
 	return a
 }
 
-// Apply 应用技能到OpenCode目录
+// Apply 应用技能到OpenCode目录，并在opencode.json中注册
 func (a *OpenCodeAdapter) Apply(skillID string, content string, variables map[string]string) error {
-	// 验证技能ID符合OpenCode命名规范
-	if err := validateSkillName(skillID); err != nil {
-		return fmt.Errorf("技能ID验证失败: %w", err)
+	plan, err := a.Plan(skillID, content, variables)
+	if err != nil {
+		return err
+	}
+
+	// 创建技能目录
+	if err := createSkillDirectory(filepath.Dir(plan.FilePath)); err != nil {
+		return fmt.Errorf("创建技能目录失败: %w", err)
+	}
+
+	// 写入SKILL.md文件
+	slog.Debug("写入技能", "adapter", "open_code", "skill", skillID, "file", plan.FilePath)
+	if err := writeSkillMDFile(plan.FilePath, plan.After); err != nil {
+		return fmt.Errorf("写入SKILL.md失败: %w", err)
+	}
+
+	// 更新opencode.json
+	for _, extra := range plan.Additional {
+		if !extra.HasChanges() {
+			continue
+		}
+		if err := writeConfigFile(extra.FilePath, extra.After); err != nil {
+			return fmt.Errorf("更新%s失败: %w", configFileName, err)
+		}
+	}
+
+	return nil
+}
+
+// Plan 预览应用技能后SKILL.md和opencode.json的变化
+func (a *OpenCodeAdapter) Plan(skillID string, content string, variables map[string]string) (*adapter.Plan, error) {
+	// 验证技能ID符合OpenCode命名规范，命名空间技能使用 <命名空间>-<名称> 作为技能名
+	if strings.Contains(skillID, spec.NamespaceSeparator) {
+		if err := spec.ValidateSkillID(skillID); err != nil {
+			return nil, fmt.Errorf("技能ID验证失败: %w", err)
+		}
+	}
+	name := spec.FlatSkillID(skillID)
+	if err := validateSkillName(name); err != nil {
+		return nil, fmt.Errorf("技能ID验证失败: %w", err)
 	}
 
 	// 获取基础路径
 	basePath, err := a.getBasePath()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	skillPath := filepath.Join(basePath, "skills", name, "SKILL.md")
+	if err := spec.CheckFlatSkillOwner(skillID, installedSkillID(skillPath)); err != nil {
+		return nil, err
 	}
 
-	// 创建技能目录
-	skillDir := filepath.Join(basePath, "skills", skillID)
-	if err := createSkillDirectory(skillDir); err != nil {
-		return fmt.Errorf("创建技能目录失败: %w", err)
+	// 读取现有文件内容
+	var before string
+	if data, err := os.ReadFile(skillPath); err == nil {
+		before = string(data)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取SKILL.md失败: %w", err)
 	}
 
 	// 转换内容为OpenCode格式
 	openCodeContent, err := convertToOpenCodeFormat(content, skillID)
 	if err != nil {
-		return fmt.Errorf("转换技能格式失败: %w", err)
+		return nil, fmt.Errorf("转换技能格式失败: %w", err)
 	}
 
-	// 写入SKILL.md文件
-	skillPath := filepath.Join(skillDir, "SKILL.md")
-	if err := writeSkillMDFile(skillPath, openCodeContent); err != nil {
-		return fmt.Errorf("写入SKILL.md失败: %w", err)
+	// 计算opencode.json的变化
+	servers, err := parseMCPServers(content)
+	if err != nil {
+		return nil, err
+	}
+	configPath, configBefore, configAfter, err := a.planConfig(skillPath, servers)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return &adapter.Plan{
+		SkillID:  skillID,
+		FilePath: skillPath,
+		Before:   before,
+		After:    openCodeContent,
+		Additional: []*adapter.Plan{{
+			SkillID:  skillID,
+			FilePath: configPath,
+			Before:   configBefore,
+			After:    configAfter,
+		}},
+	}, nil
 }
 
 // Extract 从OpenCode目录提取技能内容
@@ -77,7 +145,10 @@ func (a *OpenCodeAdapter) Extract(skillID string) (string, error) {
 	}
 
 	// 构建技能文件路径
-	skillPath := filepath.Join(basePath, "skills", skillID, "SKILL.md")
+	skillPath := filepath.Join(basePath, "skills", spec.FlatSkillID(skillID), "SKILL.md")
+	if err := spec.CheckFlatSkillOwner(skillID, installedSkillID(skillPath)); err != nil {
+		return "", err
+	}
 
 	// 读取文件内容
 	content, err := os.ReadFile(skillPath)
@@ -100,13 +171,24 @@ func (a *OpenCodeAdapter) Remove(skillID string) error {
 	}
 
 	// 构建技能目录路径
-	skillDir := filepath.Join(basePath, "skills", skillID)
+	skillDir := filepath.Join(basePath, "skills", spec.FlatSkillID(skillID))
+
+	skillPath := filepath.Join(skillDir, "SKILL.md")
+	// 同名目录属于其他技能时不删除
+	if err := spec.CheckFlatSkillOwner(skillID, installedSkillID(skillPath)); err != nil {
+		return err
+	}
+	slog.Debug("移除技能", "adapter", "open_code", "skill", skillID, "dir", skillDir)
 
 	// 检查目录是否存在
 	if _, err := os.Stat(skillDir); os.IsNotExist(err) {
-		return nil // 目录不存在，无需移除
+		// 目录不存在，仍清理opencode.json中的残留条目
+		return a.unregisterSkill(skillPath, nil)
 	}
 
+	// 删除前读取该技能注册的MCP服务器
+	mcpNames := installedMCPServers(skillPath)
+
 	// 递归删除目录
 	if err := os.RemoveAll(skillDir); err != nil {
 		return fmt.Errorf("删除技能目录失败: %w", err)
@@ -118,7 +200,8 @@ func (a *OpenCodeAdapter) Remove(skillID string) error {
 		os.Remove(parentDir)
 	}
 
-	return nil
+	// 从opencode.json注销技能
+	return a.unregisterSkill(skillPath, mcpNames)
 }
 
 // List 列出OpenCode目录中的所有技能
@@ -160,6 +243,34 @@ func (a *OpenCodeAdapter) List() ([]string, error) {
 	return skillIDs, nil
 }
 
+// TargetFiles 返回opencode.json的路径，技能目录在移除时整体删除，不需要备份
+func (a *OpenCodeAdapter) TargetFiles() ([]string, error) {
+	configPath, err := a.getConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return []string{configPath}, nil
+}
+
+// SkillDir 返回技能的安装目录 skills/<名称>，Remove 会删除整个目录
+func (a *OpenCodeAdapter) SkillDir(skillID string) (string, error) {
+	basePath, err := a.getBasePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(basePath, "skills", spec.FlatSkillID(skillID)), nil
+}
+
+// Verify 检查opencode.json是有效的JSON
+func (a *OpenCodeAdapter) Verify() error {
+	configPath, err := a.getConfigPath()
+	if err != nil {
+		return err
+	}
+	_, _, err = readConfigFile(configPath)
+	return err
+}
+
 // GetSkillsPath 获取技能目录路径（公开方法）
 func (a *OpenCodeAdapter) GetSkillsPath() (string, error) {
 	basePath, err := a.getBasePath()
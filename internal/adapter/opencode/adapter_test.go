@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -157,7 +158,7 @@ func TestOpenCodeAdapter(t *testing.T) {
 		}
 
 		// 验证提取的内容（包含OpenCode格式的frontmatter）
-		expectedContent := "---\ndescription: 'Skill: test-skill'\nmetadata:\n    source: skill-hub\nname: test-skill\n---\n" + content
+		expectedContent := "---\ndescription: 'Skill: test-skill'\nmetadata:\n    id: test-skill\n    source: skill-hub\nname: test-skill\n---\n" + content
 		if extracted != expectedContent {
 			t.Errorf("Extract() = %v, want %v", extracted, expectedContent)
 		}
@@ -255,7 +256,7 @@ func TestOpenCodeAdapter(t *testing.T) {
 				t.Errorf("Extract(%s) error = %v", skill.id, err)
 			}
 
-			expectedContent := fmt.Sprintf("---\ndescription: 'Skill: %s'\nmetadata:\n    source: skill-hub\nname: %s\n---\n%s", skill.id, skill.id, skill.content)
+			expectedContent := fmt.Sprintf("---\ndescription: 'Skill: %s'\nmetadata:\n    id: %s\n    source: skill-hub\nname: %s\n---\n%s", skill.id, skill.id, skill.id, skill.content)
 			if extracted != expectedContent {
 				t.Errorf("Extract(%s) = %v, want %v", skill.id, extracted, expectedContent)
 			}
@@ -273,7 +274,7 @@ func TestOpenCodeAdapter(t *testing.T) {
 			t.Errorf("Extract(updated) error = %v", err)
 		}
 
-		expectedUpdatedContent := fmt.Sprintf("---\ndescription: 'Skill: skill-2'\nmetadata:\n    source: skill-hub\nname: skill-2\n---\n%s", updatedContent)
+		expectedUpdatedContent := fmt.Sprintf("---\ndescription: 'Skill: skill-2'\nmetadata:\n    id: skill-2\n    source: skill-hub\nname: skill-2\n---\n%s", updatedContent)
 		if extracted != expectedUpdatedContent {
 			t.Errorf("Skill not updated properly: got %v, want %v", extracted, expectedUpdatedContent)
 		}
@@ -312,6 +313,58 @@ func TestOpenCodeAdapter(t *testing.T) {
 		}
 	})
 
+	t.Run("Namespaced skill", func(t *testing.T) {
+		adapter := NewOpenCodeAdapter().WithProjectMode()
+
+		oldDir, err := os.Getwd()
+		if err != nil {
+			t.Fatalf("Failed to get current directory: %v", err)
+		}
+		defer os.Chdir(oldDir)
+
+		if err := os.Chdir(t.TempDir()); err != nil {
+			t.Fatalf("Failed to change directory: %v", err)
+		}
+
+		// OpenCode要求name与目录名一致，命名空间技能使用 <命名空间>-<名称>
+		content := "---\nname: git-expert\ndescription: Git专家\n---\nbody"
+		if err := adapter.Apply("acme/git-expert", content, map[string]string{}); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+
+		basePath, _ := adapter.getBasePath()
+		data, err := os.ReadFile(filepath.Join(basePath, "skills", "acme-git-expert", "SKILL.md"))
+		if err != nil {
+			t.Fatalf("SKILL.md file not created: %v", err)
+		}
+		if !strings.Contains(string(data), "name: acme-git-expert\n") {
+			t.Errorf("SKILL.md = %q, want name acme-git-expert", data)
+		}
+
+		extracted, err := adapter.Extract("acme/git-expert")
+		if err != nil || extracted != string(data) {
+			t.Errorf("Extract() = %q, %v", extracted, err)
+		}
+
+		// acme-git-expert 扁平化后与 acme/git-expert 同名，不能覆盖或删除其目录
+		if err := adapter.Apply("acme-git-expert", "---\nname: acme-git-expert\ndescription: other\n---\nother", map[string]string{}); err == nil {
+			t.Error("Apply() should refuse a skill whose flat name belongs to acme/git-expert")
+		}
+		if err := adapter.Remove("acme-git-expert"); err == nil {
+			t.Error("Remove() should refuse a skill whose flat name belongs to acme/git-expert")
+		}
+		if after, _ := os.ReadFile(filepath.Join(basePath, "skills", "acme-git-expert", "SKILL.md")); string(after) != string(data) {
+			t.Errorf("SKILL.md changed to %q", after)
+		}
+
+		if err := adapter.Remove("acme/git-expert"); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(basePath, "skills", "acme-git-expert")); !os.IsNotExist(err) {
+			t.Error("skill directory should be removed")
+		}
+	})
+
 	t.Run("Supports check", func(t *testing.T) {
 		adapter := NewOpenCodeAdapter()
 
@@ -0,0 +1,298 @@
+package opencode
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileName OpenCode配置文件名
+const configFileName = "opencode.json"
+
+// configSchema 新建配置文件时使用的JSON Schema地址
+const configSchema = "https://opencode.ai/config.json"
+
+// getConfigPath 获取opencode.json路径
+//
+// 项目级配置位于项目根目录（.agents 的上级），全局配置位于 ~/.config/opencode。
+func (a *OpenCodeAdapter) getConfigPath() (string, error) {
+	basePath, err := a.getBasePath()
+	if err != nil {
+		return "", err
+	}
+	if a.mode == "project" {
+		return filepath.Join(filepath.Dir(basePath), configFileName), nil
+	}
+	return filepath.Join(basePath, configFileName), nil
+}
+
+// instructionPath 获取技能在instructions数组中的路径（相对配置文件目录）
+func instructionPath(configPath, skillPath string) string {
+	rel, err := filepath.Rel(filepath.Dir(configPath), skillPath)
+	if err != nil {
+		return filepath.ToSlash(skillPath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// readConfigFile 读取opencode.json，文件不存在时返回nil
+func readConfigFile(configPath string) (map[string]interface{}, string, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("读取%s失败: %w", configFileName, err)
+	}
+
+	var configData map[string]interface{}
+	if err := json.Unmarshal(data, &configData); err != nil {
+		return nil, "", fmt.Errorf("解析%s失败: %w", configFileName, err)
+	}
+	if configData == nil {
+		configData = map[string]interface{}{}
+	}
+
+	return configData, string(data), nil
+}
+
+// marshalConfig 序列化配置为带缩进的JSON
+func marshalConfig(configData map[string]interface{}) (string, error) {
+	data, err := json.MarshalIndent(configData, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化%s失败: %w", configFileName, err)
+	}
+	return string(data) + "\n", nil
+}
+
+// addInstruction 将路径加入instructions数组（已存在则忽略）
+func addInstruction(configData map[string]interface{}, path string) {
+	instructions, _ := configData["instructions"].([]interface{})
+	for _, item := range instructions {
+		if s, ok := item.(string); ok && s == path {
+			return
+		}
+	}
+	configData["instructions"] = append(instructions, path)
+}
+
+// removeInstruction 从instructions数组移除路径，数组为空时删除该字段
+func removeInstruction(configData map[string]interface{}, path string) {
+	instructions, ok := configData["instructions"].([]interface{})
+	if !ok {
+		return
+	}
+
+	kept := make([]interface{}, 0, len(instructions))
+	for _, item := range instructions {
+		if s, ok := item.(string); ok && s == path {
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	if len(kept) == 0 {
+		delete(configData, "instructions")
+		return
+	}
+	configData["instructions"] = kept
+}
+
+// mergeMCPServers 将技能声明的MCP服务器写入mcp字段
+//
+// owned 为技能上次应用时注册的服务器（记录在已安装SKILL.md的 metadata.mcp 中），只有这些条目属于该技能：
+// 可以被更新，新版本不再声明时被移除。mcp 中已有不属于该技能的同名服务器时返回错误，不覆盖用户或其他技能的配置。
+func mergeMCPServers(configData map[string]interface{}, servers map[string]interface{}, owned []string) error {
+	mcp, _ := configData["mcp"].(map[string]interface{})
+	ownedSet := make(map[string]bool, len(owned))
+	for _, name := range owned {
+		ownedSet[name] = true
+	}
+	for _, name := range mcpServerNames(servers) {
+		if _, exists := mcp[name]; exists && !ownedSet[name] {
+			return fmt.Errorf("%s 中已存在同名的MCP服务器 %s，且不是由该技能注册的，请重命名或移除后重试", configFileName, name)
+		}
+	}
+
+	var stale []string
+	for _, name := range owned {
+		if _, ok := servers[name]; !ok {
+			stale = append(stale, name)
+		}
+	}
+	removeMCPServers(configData, stale)
+	if len(servers) == 0 {
+		return nil
+	}
+
+	mcp, ok := configData["mcp"].(map[string]interface{})
+	if !ok {
+		mcp = map[string]interface{}{}
+	}
+	for name, server := range servers {
+		mcp[name] = server
+	}
+	configData["mcp"] = mcp
+	return nil
+}
+
+// removeMCPServers 从mcp字段移除指定服务器，字段为空时删除。调用方只传入技能注册的服务器
+func removeMCPServers(configData map[string]interface{}, names []string) {
+	mcp, ok := configData["mcp"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, name := range names {
+		delete(mcp, name)
+	}
+	if len(mcp) == 0 {
+		delete(configData, "mcp")
+	}
+}
+
+// parseMCPServers 从技能frontmatter的mcp字段解析MCP服务器定义
+func parseMCPServers(content string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, nil
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return nil, nil
+	}
+
+	var frontmatter struct {
+		MCP map[string]interface{} `yaml:"mcp"`
+	}
+	if err := yaml.Unmarshal([]byte(content[4:4+end]), &frontmatter); err != nil {
+		return nil, fmt.Errorf("解析frontmatter失败: %w", err)
+	}
+
+	return frontmatter.MCP, nil
+}
+
+// mcpServerNames 返回排序后的MCP服务器名称
+func mcpServerNames(servers map[string]interface{}) []string {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// installedMCPServers 从已安装的SKILL.md元数据中读取该技能注册的MCP服务器
+func installedMCPServers(skillPath string) []string {
+	value := installedMetadata(skillPath)["mcp"]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// installedSkillID 从已安装的SKILL.md元数据中读取完整的技能ID，没有记录时返回空字符串
+func installedSkillID(skillPath string) string {
+	return installedMetadata(skillPath)["id"]
+}
+
+// installedMetadata 读取已安装SKILL.md的frontmatter中的metadata，文件不存在或无法解析时返回nil
+func installedMetadata(skillPath string) map[string]string {
+	data, err := os.ReadFile(skillPath)
+	if err != nil {
+		return nil
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "---\n") {
+		return nil
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return nil
+	}
+
+	var frontmatter struct {
+		Metadata map[string]string `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal([]byte(content[4:4+end]), &frontmatter); err != nil {
+		return nil
+	}
+	return frontmatter.Metadata
+}
+
+// planConfig 计算应用技能后opencode.json的内容
+func (a *OpenCodeAdapter) planConfig(skillPath string, servers map[string]interface{}) (configPath, before, after string, err error) {
+	configPath, err = a.getConfigPath()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	configData, before, err := readConfigFile(configPath)
+	if err != nil {
+		return "", "", "", err
+	}
+	if configData == nil {
+		configData = map[string]interface{}{"$schema": configSchema}
+	}
+
+	addInstruction(configData, instructionPath(configPath, skillPath))
+	if err := mergeMCPServers(configData, servers, installedMCPServers(skillPath)); err != nil {
+		return "", "", "", err
+	}
+
+	after, err = marshalConfig(configData)
+	if err != nil {
+		return "", "", "", err
+	}
+	return configPath, before, after, nil
+}
+
+// unregisterSkill 从opencode.json移除技能的instructions和MCP条目
+func (a *OpenCodeAdapter) unregisterSkill(skillPath string, mcpNames []string) error {
+	configPath, err := a.getConfigPath()
+	if err != nil {
+		return err
+	}
+
+	configData, before, err := readConfigFile(configPath)
+	if err != nil {
+		return err
+	}
+	if configData == nil {
+		return nil // 配置文件不存在，无需修改
+	}
+
+	removeInstruction(configData, instructionPath(configPath, skillPath))
+	removeMCPServers(configData, mcpNames)
+
+	after, err := marshalConfig(configData)
+	if err != nil {
+		return err
+	}
+	if after == before {
+		return nil
+	}
+	return writeConfigFile(configPath, after)
+}
+
+// writeConfigFile 写入opencode.json（原子操作）
+func writeConfigFile(configPath, content string) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	tmpPath := configPath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("重命名文件失败: %w", err)
+	}
+
+	return nil
+}
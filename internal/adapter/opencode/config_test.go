@@ -0,0 +1,153 @@
+package opencode
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenCodeConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(oldDir)
+
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	configPath := filepath.Join(tmpDir, configFileName)
+
+	// 用户已有的配置应被保留
+	userConfig := `{"theme": "dark", "instructions": ["docs/rules.md"]}`
+	if err := os.WriteFile(configPath, []byte(userConfig), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	readConfig := func(t *testing.T) map[string]interface{} {
+		t.Helper()
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read config: %v", err)
+		}
+		var configData map[string]interface{}
+		if err := json.Unmarshal(data, &configData); err != nil {
+			t.Fatalf("opencode.json is not valid JSON: %v", err)
+		}
+		return configData
+	}
+
+	withMCP := `---
+name: db-helper
+description: Database helper
+mcp:
+  postgres:
+    type: local
+    command: ["pg-mcp"]
+---
+# DB Helper
+`
+
+	t.Run("Apply registers instructions and MCP", func(t *testing.T) {
+		adapter := NewOpenCodeAdapter().WithProjectMode()
+		if err := adapter.Apply("db-helper", withMCP, nil); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if err := NewOpenCodeAdapter().WithProjectMode().Apply("plain", "# Plain\n", nil); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+
+		configData := readConfig(t)
+		if configData["theme"] != "dark" {
+			t.Errorf("user setting lost: %v", configData)
+		}
+
+		instructions, _ := configData["instructions"].([]interface{})
+		want := []string{"docs/rules.md", ".agents/skills/db-helper/SKILL.md", ".agents/skills/plain/SKILL.md"}
+		if len(instructions) != len(want) {
+			t.Fatalf("instructions = %v, want %v", instructions, want)
+		}
+		for i := range want {
+			if instructions[i] != want[i] {
+				t.Errorf("instructions[%d] = %v, want %v", i, instructions[i], want[i])
+			}
+		}
+
+		mcp, _ := configData["mcp"].(map[string]interface{})
+		if _, ok := mcp["postgres"]; !ok {
+			t.Errorf("mcp = %v, want postgres entry", mcp)
+		}
+	})
+
+	t.Run("Apply twice is idempotent", func(t *testing.T) {
+		before, _ := os.ReadFile(configPath)
+		if err := NewOpenCodeAdapter().WithProjectMode().Apply("db-helper", withMCP, nil); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		after, _ := os.ReadFile(configPath)
+		if string(before) != string(after) {
+			t.Errorf("config changed on re-apply:\n%s\n---\n%s", before, after)
+		}
+	})
+
+	t.Run("Remove unregisters skill", func(t *testing.T) {
+		if err := NewOpenCodeAdapter().WithProjectMode().Remove("db-helper"); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+
+		configData := readConfig(t)
+		if _, ok := configData["mcp"]; ok {
+			t.Errorf("mcp should be removed: %v", configData["mcp"])
+		}
+
+		instructions, _ := configData["instructions"].([]interface{})
+		if len(instructions) != 2 || instructions[0] != "docs/rules.md" || instructions[1] != ".agents/skills/plain/SKILL.md" {
+			t.Errorf("instructions = %v", instructions)
+		}
+	})
+
+	t.Run("Same-name MCP server is not taken over", func(t *testing.T) {
+		os.WriteFile(configPath, []byte(`{"mcp": {"postgres": {"type": "remote", "url": "https://db.example.com"}}}`), 0644)
+		if err := NewOpenCodeAdapter().WithProjectMode().Apply("db-helper", withMCP, nil); err == nil {
+			t.Fatal("Apply() should refuse to overwrite a user MCP server")
+		}
+		if err := NewOpenCodeAdapter().WithProjectMode().Remove("db-helper"); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		mcp, _ := readConfig(t)["mcp"].(map[string]interface{})
+		server, _ := mcp["postgres"].(map[string]interface{})
+		if server["url"] != "https://db.example.com" {
+			t.Errorf("user MCP server changed: %v", mcp)
+		}
+	})
+
+	t.Run("Servers dropped by a new version are removed", func(t *testing.T) {
+		os.WriteFile(configPath, []byte(`{"mcp": {"user": {"type": "local"}}}`), 0644)
+		if err := NewOpenCodeAdapter().WithProjectMode().Apply("db-helper", withMCP, nil); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if err := NewOpenCodeAdapter().WithProjectMode().Apply("db-helper", "---\nname: db-helper\ndescription: Database helper\n---\n# DB Helper\n", nil); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		mcp, _ := readConfig(t)["mcp"].(map[string]interface{})
+		if _, ok := mcp["postgres"]; ok || mcp["user"] == nil {
+			t.Errorf("mcp = %v, want only the user server", mcp)
+		}
+		NewOpenCodeAdapter().WithProjectMode().Remove("db-helper")
+	})
+
+	t.Run("Invalid JSON is not overwritten", func(t *testing.T) {
+		os.WriteFile(configPath, []byte("{invalid"), 0644)
+		if _, err := NewOpenCodeAdapter().WithProjectMode().Plan("plain", "# Plain\n", nil); err == nil {
+			t.Error("Plan() should fail on invalid opencode.json")
+		}
+		data, _ := os.ReadFile(configPath)
+		if string(data) != "{invalid" {
+			t.Errorf("invalid config was modified: %s", data)
+		}
+	})
+}
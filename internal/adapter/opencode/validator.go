@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v3"
+	"skill-hub/pkg/spec"
 )
 
 // validateSkillName 验证技能名称是否符合OpenCode规范
@@ -39,12 +40,18 @@ func validateDescription(desc string) error {
 }
 
 // convertToOpenCodeFormat 转换Skill Hub格式为OpenCode格式
+//
+// OpenCode要求name与技能目录名一致，命名空间技能的name使用 <命名空间>-<名称>。
 func convertToOpenCodeFormat(content string, skillID string) (string, error) {
+	fullID := skillID
+	namespace, _ := spec.SplitSkillID(skillID)
+	skillID = spec.FlatSkillID(skillID)
+
 	// 解析原始内容中的frontmatter
 	lines := strings.Split(content, "\n")
 	if len(lines) < 2 || lines[0] != "---" {
 		// 如果没有frontmatter，创建基本的OpenCode格式
-		return createBasicOpenCodeFormat(content, skillID)
+		return createBasicOpenCodeFormat(content, fullID)
 	}
 
 	// 提取frontmatter
@@ -76,7 +83,7 @@ func convertToOpenCodeFormat(content string, skillID string) (string, error) {
 	openCodeData := make(map[string]interface{})
 
 	// 处理必需字段
-	if name, ok := originalData["name"].(string); ok {
+	if name, ok := originalData["name"].(string); ok && namespace == "" {
 		if err := validateSkillName(name); err != nil {
 			return "", err
 		}
@@ -115,12 +122,18 @@ func convertToOpenCodeFormat(content string, skillID string) (string, error) {
 	// 添加metadata字段
 	metadata := make(map[string]string)
 	metadata["source"] = "skill-hub"
+	// 记录完整的技能ID，acme/git-expert 与 acme-git-expert 的目录名相同，据此区分归属
+	metadata["id"] = fullID
 	if version, ok := originalData["version"].(string); ok {
 		metadata["version"] = version
 	}
 	if author, ok := originalData["author"].(string); ok {
 		metadata["author"] = author
 	}
+	// 记录注册到opencode.json的MCP服务器，便于移除时清理
+	if servers, ok := originalData["mcp"].(map[string]interface{}); ok && len(servers) > 0 {
+		metadata["mcp"] = strings.Join(mcpServerNames(servers), ",")
+	}
 	openCodeData["metadata"] = metadata
 
 	// 生成YAML frontmatter
@@ -136,18 +149,20 @@ func convertToOpenCodeFormat(content string, skillID string) (string, error) {
 	return fmt.Sprintf("---\n%s---\n%s", frontmatter, contentText), nil
 }
 
-// createBasicOpenCodeFormat 创建基本的OpenCode格式
+// createBasicOpenCodeFormat 创建基本的OpenCode格式，name 使用扁平化的技能ID
 func createBasicOpenCodeFormat(content string, skillID string) (string, error) {
+	name := spec.FlatSkillID(skillID)
 	// 验证技能ID
-	if err := validateSkillName(skillID); err != nil {
+	if err := validateSkillName(name); err != nil {
 		return "", err
 	}
 
 	// 创建基本的frontmatter
 	frontmatter := map[string]interface{}{
-		"name":        skillID,
-		"description": fmt.Sprintf("Skill: %s", skillID),
+		"name":        name,
+		"description": fmt.Sprintf("Skill: %s", name),
 		"metadata": map[string]string{
+			"id":     skillID,
 			"source": "skill-hub",
 		},
 	}
@@ -0,0 +1,341 @@
+package shell
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/adapter"
+	"skill-hub/pkg/spec"
+)
+
+// managedHeader 标识由Skill Hub管理的脚本
+const managedHeader = "# SKILL-HUB MANAGED: "
+
+// defaultShebang 脚本缺少shebang时使用的解释器
+const defaultShebang = "#!/usr/bin/env bash"
+
+// scriptBlockPattern 匹配Markdown中的shell代码块
+var scriptBlockPattern = regexp.MustCompile("(?s)```(?:bash|sh|shell|zsh)[ \\t]*\\n(.*?)\\n```")
+
+// ShellAdapter 实现脚本型技能的适配器
+//
+// 项目模式下脚本安装到 ./scripts/skills/<id>，全局模式下安装到 ~/.skill-hub/bin/<id>。
+type ShellAdapter struct {
+	mode        string // "project" 或 "global"
+	projectPath string // 项目目录，为空时使用当前工作目录
+}
+
+// NewShellAdapter 创建新的Shell适配器
+func NewShellAdapter() *ShellAdapter {
+	return &ShellAdapter{
+		mode: "project", // 默认项目级
+	}
+}
+
+// WithProjectMode 设置为项目模式
+func (a *ShellAdapter) WithProjectMode() *ShellAdapter {
+	a.mode = "project"
+	return a
+}
+
+// WithProjectPath 设置为指定项目目录的项目模式
+func (a *ShellAdapter) WithProjectPath(path string) *ShellAdapter {
+	a.mode = "project"
+	a.projectPath = path
+	return a
+}
+
+// WithGlobalMode 设置为全局模式
+func (a *ShellAdapter) WithGlobalMode() *ShellAdapter {
+	a.mode = "global"
+	return a
+}
+
+// Apply 安装技能脚本
+func (a *ShellAdapter) Apply(skillID string, content string, variables map[string]string) error {
+	plan, err := a.Plan(skillID, content, variables)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("安装技能脚本: %s\n", plan.FilePath)
+	slog.Debug("写入技能", "adapter", "shell", "skill", skillID, "file", plan.FilePath)
+
+	if err := writeScript(plan.FilePath, plan.After); err != nil {
+		return err
+	}
+
+	if hint := a.PathHint(); hint != "" {
+		fmt.Println(hint)
+	}
+	return nil
+}
+
+// Plan 预览安装技能脚本后的变化
+func (a *ShellAdapter) Plan(skillID string, content string, variables map[string]string) (*adapter.Plan, error) {
+	if err := validateScriptName(skillID); err != nil {
+		return nil, err
+	}
+
+	scriptPath, err := a.scriptPath(skillID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 读取现有脚本
+	var before string
+	if data, err := os.ReadFile(scriptPath); err == nil {
+		before = string(data)
+		if err := spec.CheckFlatSkillOwner(skillID, managedSkillID(before)); err != nil {
+			return nil, err
+		}
+		if !isManaged(before, skillID) {
+			return nil, fmt.Errorf("脚本 %s 已存在且不由Skill Hub管理", scriptPath)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取脚本失败: %w", err)
+	}
+
+	return &adapter.Plan{
+		SkillID:  skillID,
+		FilePath: scriptPath,
+		Before:   before,
+		After:    buildScript(skillID, content),
+	}, nil
+}
+
+// Extract 提取技能脚本内容（不含Skill Hub管理头）
+func (a *ShellAdapter) Extract(skillID string) (string, error) {
+	scriptPath, err := a.scriptPath(skillID)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(scriptPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", adapter.NotFoundf("脚本不存在: %s", scriptPath)
+		}
+		return "", fmt.Errorf("读取脚本失败: %w", err)
+	}
+
+	content := string(data)
+	if err := spec.CheckFlatSkillOwner(skillID, managedSkillID(content)); err != nil {
+		return "", err
+	}
+	if !isManaged(content, skillID) {
+		return "", fmt.Errorf("脚本 %s 不由Skill Hub管理", scriptPath)
+	}
+
+	return stripScript(content), nil
+}
+
+// Remove 移除技能脚本
+func (a *ShellAdapter) Remove(skillID string) error {
+	scriptPath, err := a.scriptPath(skillID)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(scriptPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // 脚本不存在，无需移除
+		}
+		return fmt.Errorf("读取脚本失败: %w", err)
+	}
+
+	// 不删除用户自己的脚本和同名的其他技能脚本
+	if err := spec.CheckFlatSkillOwner(skillID, managedSkillID(string(data))); err != nil {
+		return err
+	}
+	if !isManaged(string(data), skillID) {
+		return fmt.Errorf("脚本 %s 不由Skill Hub管理，拒绝删除", scriptPath)
+	}
+
+	if err := os.Remove(scriptPath); err != nil {
+		return fmt.Errorf("删除脚本失败: %w", err)
+	}
+
+	// 目录为空时一并删除
+	os.Remove(filepath.Dir(scriptPath))
+	return nil
+}
+
+// List 列出已安装的技能脚本
+func (a *ShellAdapter) List() ([]string, error) {
+	dir, err := a.GetScriptsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("读取脚本目录失败: %w", err)
+	}
+
+	skillIDs := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		// 脚本头记录完整的技能ID，命名空间技能的文件名与技能ID不同
+		if skillID := managedSkillID(string(data)); skillID != "" && spec.FlatSkillID(skillID) == entry.Name() {
+			skillIDs = append(skillIDs, skillID)
+		}
+	}
+	sort.Strings(skillIDs)
+
+	return skillIDs, nil
+}
+
+// Supports 检查是否支持当前环境
+func (a *ShellAdapter) Supports() bool {
+	// 依赖可执行权限，Windows下不支持
+	return os.PathSeparator == '/'
+}
+
+// GetScriptsDir 获取脚本安装目录
+func (a *ShellAdapter) GetScriptsDir() (string, error) {
+	if a.mode == "global" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("获取用户主目录失败: %w", err)
+		}
+		return filepath.Join(homeDir, ".skill-hub", "bin"), nil
+	}
+
+	if a.projectPath != "" {
+		return filepath.Join(a.projectPath, "scripts", "skills"), nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	return filepath.Join(cwd, "scripts", "skills"), nil
+}
+
+// PathHint 当脚本目录不在PATH中时返回提示信息
+func (a *ShellAdapter) PathHint() string {
+	dir, err := a.GetScriptsDir()
+	if err != nil {
+		return ""
+	}
+
+	for _, p := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(p) == filepath.Clean(dir) {
+			return ""
+		}
+	}
+
+	if a.mode == "global" {
+		return fmt.Sprintf("💡 将以下内容添加到shell配置文件以直接调用技能脚本:\n   export PATH=\"%s:$PATH\"", dir)
+	}
+	return fmt.Sprintf("💡 技能脚本位于 %s，可通过 export PATH=\"$PWD/scripts/skills:$PATH\" 直接调用", dir)
+}
+
+// scriptPath 获取技能脚本路径，命名空间技能的脚本名为 <命名空间>-<名称>
+func (a *ShellAdapter) scriptPath(skillID string) (string, error) {
+	dir, err := a.GetScriptsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, spec.FlatSkillID(skillID)), nil
+}
+
+// validateScriptName 校验技能ID可作为脚本文件名
+func validateScriptName(skillID string) error {
+	if strings.Contains(skillID, spec.NamespaceSeparator) && spec.ValidateSkillID(skillID) != nil {
+		return fmt.Errorf("技能ID '%s' 不能作为脚本文件名", skillID)
+	}
+	name := spec.FlatSkillID(skillID)
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("技能ID '%s' 不能作为脚本文件名", skillID)
+	}
+	return nil
+}
+
+// buildScript 生成脚本内容：提取Markdown中的shell代码块，补全shebang并加入管理头
+func buildScript(skillID, content string) string {
+	script := content
+	if m := scriptBlockPattern.FindStringSubmatch(content); m != nil {
+		script = m[1]
+	}
+	script = strings.TrimSpace(script)
+
+	shebang := defaultShebang
+	if strings.HasPrefix(script, "#!") {
+		if idx := strings.Index(script, "\n"); idx >= 0 {
+			shebang, script = script[:idx], strings.TrimLeft(script[idx+1:], "\n")
+		} else {
+			shebang, script = script, ""
+		}
+	}
+
+	return shebang + "\n" + managedHeader + skillID + "\n\n" + script + "\n"
+}
+
+// stripScript 去除shebang和管理头，返回脚本主体
+func stripScript(content string) string {
+	lines := strings.Split(content, "\n")
+	var body []string
+	for i, line := range lines {
+		if i == 0 && strings.HasPrefix(line, "#!") {
+			continue
+		}
+		if strings.HasPrefix(line, managedHeader) {
+			continue
+		}
+		body = append(body, line)
+	}
+	return strings.TrimSpace(strings.Join(body, "\n"))
+}
+
+// isManaged 检查脚本是否由Skill Hub为指定技能管理
+func isManaged(content, skillID string) bool {
+	return managedSkillID(content) == skillID
+}
+
+// managedSkillID 返回脚本头中记录的技能ID，不是Skill Hub管理的脚本时返回空字符串
+func managedSkillID(content string) string {
+	for i, line := range strings.SplitN(content, "\n", 3) {
+		if i > 1 {
+			break
+		}
+		if skillID, ok := strings.CutPrefix(strings.TrimSpace(line), managedHeader); ok {
+			return strings.TrimSpace(skillID)
+		}
+	}
+	return ""
+}
+
+// writeScript 写入可执行脚本（原子操作）
+func writeScript(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(content), 0755); err != nil {
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("重命名文件失败: %w", err)
+	}
+
+	return nil
+}
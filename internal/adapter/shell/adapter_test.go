@@ -0,0 +1,158 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellAdapter(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(oldDir)
+
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	adapter := NewShellAdapter().WithProjectMode()
+	scriptPath := filepath.Join(tmpDir, "scripts", "skills", "deploy")
+
+	t.Run("Apply installs executable script", func(t *testing.T) {
+		// 内容已由 engine.RenderContent 渲染
+		content := "# 部署脚本\n\n```bash\necho \"deploy prod\"\n```\n"
+		if err := adapter.Apply("deploy", content, map[string]string{"ENV": "prod"}); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+
+		info, err := os.Stat(scriptPath)
+		if err != nil {
+			t.Fatalf("script not created: %v", err)
+		}
+		if info.Mode().Perm()&0100 == 0 {
+			t.Errorf("script mode = %v, want executable", info.Mode())
+		}
+
+		data, _ := os.ReadFile(scriptPath)
+		script := string(data)
+		if !strings.HasPrefix(script, defaultShebang+"\n") {
+			t.Errorf("script missing shebang: %q", script)
+		}
+		if !strings.Contains(script, `echo "deploy prod"`) {
+			t.Errorf("script missing rendered body: %q", script)
+		}
+	})
+
+	t.Run("Rendered content is written as is", func(t *testing.T) {
+		// 变量值中的 {{.OTHER}} 不能再被替换
+		plan, err := adapter.Plan("deploy", "```bash\necho \"{{.OTHER}}\"\n```\n", map[string]string{"OTHER": "x"})
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		if !strings.Contains(plan.After, `echo "{{.OTHER}}"`) {
+			t.Errorf("Plan().After = %q, want content unchanged", plan.After)
+		}
+	})
+
+	t.Run("Extract and List", func(t *testing.T) {
+		extracted, err := adapter.Extract("deploy")
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if extracted != `echo "deploy prod"` {
+			t.Errorf("Extract() = %q", extracted)
+		}
+
+		// 非托管脚本不应出现在列表中
+		os.WriteFile(filepath.Join(tmpDir, "scripts", "skills", "mine"), []byte("#!/bin/sh\necho hi\n"), 0755)
+
+		skills, err := adapter.List()
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(skills) != 1 || skills[0] != "deploy" {
+			t.Errorf("List() = %v, want [deploy]", skills)
+		}
+	})
+
+	t.Run("Refuse unmanaged scripts", func(t *testing.T) {
+		if _, err := adapter.Plan("mine", "echo new", nil); err == nil {
+			t.Error("Plan() should fail for unmanaged script")
+		}
+		if err := adapter.Remove("mine"); err == nil {
+			t.Error("Remove() should fail for unmanaged script")
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		if err := adapter.Remove("deploy"); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		if _, err := os.Stat(scriptPath); !os.IsNotExist(err) {
+			t.Error("script should be removed")
+		}
+		// 再次移除不报错
+		if err := adapter.Remove("deploy"); err != nil {
+			t.Errorf("Remove() again error = %v", err)
+		}
+	})
+
+	t.Run("Namespaced skill", func(t *testing.T) {
+		if err := adapter.Apply("acme/deploy", "```sh\necho acme\n```", nil); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "scripts", "skills", "acme-deploy")); err != nil {
+			t.Fatalf("script not created: %v", err)
+		}
+
+		skills, err := adapter.List()
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(skills) != 1 || skills[0] != "acme/deploy" {
+			t.Errorf("List() = %v, want [acme/deploy]", skills)
+		}
+
+		if _, err := adapter.Plan("acme-deploy", "echo other", nil); err == nil || !strings.Contains(err.Error(), "acme/deploy") {
+			t.Errorf("Plan() error = %v, want conflict with acme/deploy", err)
+		}
+		if err := adapter.Remove("acme-deploy"); err == nil {
+			t.Error("Remove() should refuse a script owned by acme/deploy")
+		}
+		if err := adapter.Remove("acme/deploy"); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+	})
+}
+
+func TestBuildScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "纯脚本补全shebang",
+			content: "echo hi",
+			want:    defaultShebang + "\n" + managedHeader + "x\n\necho hi\n",
+		},
+		{
+			name:    "保留自定义shebang",
+			content: "#!/bin/sh\necho hi\n",
+			want:    "#!/bin/sh\n" + managedHeader + "x\n\necho hi\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildScript("x", tt.content); got != tt.want {
+				t.Errorf("buildScript() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
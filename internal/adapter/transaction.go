@@ -0,0 +1,210 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// BackupSuffix 备份文件后缀，与适配器写入时使用的备份一致，异常中断后可按同一规则恢复
+const BackupSuffix = ".bak"
+
+// trackedFile 记录事务中一个受影响文件的原始状态
+type trackedFile struct {
+	path    string
+	existed bool
+	data    []byte      // 原始内容
+	mode    os.FileMode // 原始权限
+}
+
+// Transaction 跨适配器的应用事务
+//
+// 在适配器写入前通过 Track 为目标文件创建备份，全部成功后 Commit 清理备份；
+// 任一适配器失败时 Rollback 将所有文件恢复到事务开始前的状态，并删除事务中新建的目录。
+// 适配器写入时会覆盖并清理同名的 .bak 备份，因此回滚使用登记时保存在内存中的原始内容，
+// 磁盘上的备份只用于进程异常中断后的恢复。
+type Transaction struct {
+	files []trackedFile
+	dirs  []string // 登记时不存在、由事务中的写入创建的目录
+	kept  []string // 通过 TrackDir 登记的已有目录，回滚时重新创建
+	seen  map[string]bool
+	done  bool
+}
+
+// NewTransaction 创建新的应用事务
+func NewTransaction() *Transaction {
+	return &Transaction{
+		seen: make(map[string]bool),
+	}
+}
+
+// Track 在修改前登记目标文件，同一文件只备份一次
+func (t *Transaction) Track(path string) error {
+	if t.done {
+		return fmt.Errorf("事务已结束")
+	}
+	if t.seen[path] {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("检查文件失败: %w", err)
+		}
+		t.trackDirs(filepath.Dir(path))
+		t.files = append(t.files, trackedFile{path: path})
+		t.seen[path] = true
+		return nil
+	}
+	if info.IsDir() {
+		return fmt.Errorf("目标路径是目录: %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+	if err := os.WriteFile(path+BackupSuffix, data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("创建备份失败: %w", err)
+	}
+
+	t.files = append(t.files, trackedFile{path: path, existed: true, data: data, mode: info.Mode().Perm()})
+	t.seen[path] = true
+	return nil
+}
+
+// TrackDir 在删除整个目录前登记其中的所有文件，回滚时重新创建目录并恢复文件，目录不存在时不做任何操作
+//
+// 只支持普通文件和目录，遇到符号链接等其他文件时返回错误，避免删除后无法完整恢复。
+func (t *Transaction) TrackDir(dir string) error {
+	if t.done {
+		return fmt.Errorf("事务已结束")
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			if !t.seen[path] {
+				t.seen[path] = true
+				t.kept = append(t.kept, path)
+			}
+		case d.Type().IsRegular():
+			if !strings.HasSuffix(path, BackupSuffix) {
+				files = append(files, path)
+			}
+		default:
+			return fmt.Errorf("无法备份 %s：不是普通文件", path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("备份目录 %s 失败: %w", dir, err)
+	}
+	for _, path := range files {
+		if err := t.Track(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trackDirs 登记 dir 及其上层中尚不存在的目录
+func (t *Transaction) trackDirs(dir string) {
+	for {
+		if _, err := os.Stat(dir); err == nil || !os.IsNotExist(err) {
+			return
+		}
+		if t.seen[dir] {
+			return
+		}
+		t.seen[dir] = true
+		t.dirs = append(t.dirs, dir)
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+}
+
+// Files 返回事务中登记的文件路径
+func (t *Transaction) Files() []string {
+	paths := make([]string, 0, len(t.files))
+	for _, f := range t.files {
+		paths = append(paths, f.path)
+	}
+	return paths
+}
+
+// Commit 提交事务，清理所有备份文件
+func (t *Transaction) Commit() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+
+	var errs []error
+	for _, f := range t.files {
+		if !f.existed {
+			continue
+		}
+		if err := os.Remove(f.path + BackupSuffix); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("清理备份 %s 失败: %w", f.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Rollback 回滚事务，将所有登记的文件恢复到原始状态
+func (t *Transaction) Rollback() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+
+	var errs []error
+	// 先重新创建 TrackDir 登记的目录，按遍历顺序从上层开始
+	for _, dir := range t.kept {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			errs = append(errs, fmt.Errorf("恢复目录 %s 失败: %w", dir, err))
+		}
+	}
+	// 逆序恢复，保证后写入的文件先被还原
+	for i := len(t.files) - 1; i >= 0; i-- {
+		f := t.files[i]
+		if f.existed {
+			if err := os.WriteFile(f.path, f.data, f.mode); err != nil {
+				errs = append(errs, fmt.Errorf("恢复 %s 失败: %w", f.path, err))
+				continue
+			}
+			os.Remove(f.path + BackupSuffix)
+			continue
+		}
+
+		// 事务前不存在的文件直接删除
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("删除 %s 失败: %w", f.path, err))
+		}
+	}
+
+	// 从最深的目录开始删除事务中新建的目录，目录中还有其他内容时保留
+	dirs := append([]string(nil), t.dirs...)
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], string(filepath.Separator)) > strings.Count(dirs[j], string(filepath.Separator))
+	})
+	for _, dir := range dirs {
+		os.Remove(dir)
+	}
+	return errors.Join(errs...)
+}
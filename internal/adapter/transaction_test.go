@@ -0,0 +1,89 @@
+package adapter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	t.Run("Rollback restores all files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		existing := filepath.Join(tmpDir, ".cursorrules")
+		created := filepath.Join(tmpDir, "skills", "demo", "SKILL.md")
+
+		if err := os.WriteFile(existing, []byte("original"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		tx := NewTransaction()
+		if err := tx.Track(existing); err != nil {
+			t.Fatalf("Track() error = %v", err)
+		}
+		if err := tx.Track(created); err != nil {
+			t.Fatalf("Track() error = %v", err)
+		}
+
+		// 模拟适配器写入：适配器自己的 .bak 备份在写入成功后被清理
+		os.WriteFile(existing+BackupSuffix, []byte("original"), 0644)
+		os.WriteFile(existing, []byte("modified"), 0644)
+		os.Remove(existing + BackupSuffix)
+		os.MkdirAll(filepath.Dir(created), 0755)
+		os.WriteFile(created, []byte("new"), 0644)
+
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("Rollback() error = %v", err)
+		}
+
+		data, err := os.ReadFile(existing)
+		if err != nil || string(data) != "original" {
+			t.Errorf("existing file = %q, %v; want %q", data, err, "original")
+		}
+		if _, err := os.Stat(created); !os.IsNotExist(err) {
+			t.Errorf("created file should be removed after rollback")
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "skills")); !os.IsNotExist(err) {
+			t.Errorf("directories created by the transaction should be removed after rollback")
+		}
+		if _, err := os.Stat(existing + BackupSuffix); !os.IsNotExist(err) {
+			t.Errorf("backup file should not remain after rollback")
+		}
+	})
+
+	t.Run("Commit removes backups", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		path := filepath.Join(tmpDir, ".clauderc")
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		tx := NewTransaction()
+		if err := tx.Track(path); err != nil {
+			t.Fatalf("Track() error = %v", err)
+		}
+		// 重复登记不应覆盖首次备份
+		os.WriteFile(path, []byte(`{"a":1}`), 0644)
+		if err := tx.Track(path); err != nil {
+			t.Fatalf("Track() error = %v", err)
+		}
+		if got := len(tx.Files()); got != 1 {
+			t.Errorf("Files() len = %d, want 1", got)
+		}
+
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit() error = %v", err)
+		}
+		if _, err := os.Stat(path + BackupSuffix); !os.IsNotExist(err) {
+			t.Errorf("backup file should be removed after commit")
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != `{"a":1}` {
+			t.Errorf("file = %q, want committed content", data)
+		}
+
+		// 事务结束后回滚无效
+		if err := tx.Rollback(); err != nil {
+			t.Errorf("Rollback() after Commit() error = %v", err)
+		}
+	})
+}
@@ -3,13 +3,18 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"skill-hub/internal/adapter"
 	"skill-hub/internal/adapter/claude"
 	"skill-hub/internal/adapter/cursor"
 	"skill-hub/internal/adapter/opencode"
+	"skill-hub/internal/adapter/shell"
+	"skill-hub/internal/config"
 	"skill-hub/internal/engine"
+	"skill-hub/internal/resource"
 	"skill-hub/internal/state"
 	"skill-hub/pkg/converter"
 	"skill-hub/pkg/spec"
@@ -26,58 +31,131 @@ var (
 	skipValidation bool
 	strictMode     bool
 	interactive    bool
+	applyNoDeps    bool
+	applyGlobal    bool
+	applyProfile   string
+	applyWorkspace bool
+	applyLocale    string
+	applyNoMerge   bool
+	applyExclude   []string
 )
 
 var applyCmd = &cobra.Command{
-	Use:   "apply",
+	Use:   "apply [skill-id...]",
 	Short: "将已启用的技能应用到当前项目",
 	Long: `将当前项目已启用的技能分发到目标工具配置文件。
 
+指定技能ID时只应用这些技能，--exclude 排除指定的技能，目标文件中其他技能的内容保持不变；
+指定的技能依赖但尚未启用的技能会自动启用并一起应用。
+
 使用 --dry-run 参数可以预览变更而不实际修改文件。
-使用 --target 参数指定目标工具 (cursor/claude_code/open_code/all)。
+使用 --target 参数指定目标工具 (cursor/claude_code/open_code/shell/all，claude 和 opencode 为别名)，
+未指定时按顺序应用到 'skill-hub set-target' 绑定的目标。
+使用 --profile 选择项目的变量配置（'skill-hub profile'），未指定时使用项目的默认配置。
+使用 --global 将全局作用域中启用的技能（'skill-hub use --global'）应用到
+~/.cursor、~/.claude 等用户级配置，全局作用域的技能与项目状态分开记录。
+使用 --workspace 将工作区启用的技能（'skill-hub use --workspace'）应用到每个成员项目，
+成员项目的变量覆盖见 'skill-hub workspace set'。
+使用 --project 应用指定目录的项目，不必先切换到项目目录，适合脚本和CI。
 
 技能标准校验选项:
   --auto-fix        自动修复不符合标准的技能
   --skip-validation 跳过技能标准校验
-  --strict          严格模式：发现不合规技能立即失败
-  --interactive     交互式模式：询问用户确认修复`,
+  --strict          严格模式：发现不合规技能或任一技能应用失败时立即失败并回滚
+  --interactive     交互式模式：询问用户确认修复
+
+技能按依赖顺序应用，技能依赖但尚未启用的技能会自动启用，使用 --no-deps 跳过。
+优先级（SKILL.md 的 priority，可用 set-priority 在项目中覆盖）高的技能先应用，
+目标文件中的技能按应用顺序排列，优先级相同时按技能ID排序。
+
+技能目录中 resources/ 下的脚本、模板和参考文档会安装到项目的
+<resources_dir>/<skill-id>/ 目录（默认 .skill-hub/resources），
+配置项 resources_mode 决定复制（copy）还是创建符号链接（link）。
+
+SKILL.md 中的 {{include "file.md"}} 会在应用前展开为对应文件的内容，路径相对于技能所在目录，
+被包含的文件可以继续包含其他文件。多个技能共享的片段可以放在技能仓库中不含 SKILL.md 的
+子目录（如 _partials/），通过 {{include "../_partials/header.md"}} 引用。
+
+技能内容可以使用条件区块为不同目标工具提供不同内容，内置变量 .Target 为目标工具
+(cursor/claude_code/open_code/shell)，.Mode 为配置模式 (project/global)：
+  {{if eq .Target "cursor"}}...{{else if .STRICT}}...{{else}}...{{end}}
+条件支持 .NAME、not .NAME、eq .NAME "a" "b" 和 ne .NAME "a"。
+
+技能可以提供语言版本 SKILL.<语言>.md（如 SKILL.zh-CN.md），按 --locale、项目语言（set-locale）、
+全局配置项 locale 的顺序确定语言；没有对应版本时依次尝试更通用的语言（zh-CN -> zh），最后使用 SKILL.md。
+
+目标文件中的技能有本地修改、技能内容也有变化时，以上次应用的内容为共同祖先进行三方合并，
+保留本地修改；两侧修改了同一处时写入冲突标记（<<<<<<< 本地修改 / ======= / >>>>>>> 技能更新），
+手动解决后可使用 'skill-hub feedback' 反馈到技能仓库。使用 --no-merge 直接覆盖本地修改。
+目标文件中已是本次渲染的内容的技能报告为已是最新，不写入也不备份目标文件。
+
+应用结束后以表格列出每个技能在每个目标中的结果（已应用/已是最新/已跳过/失败）和修改的文件，
+--json 输出同样的结果（包含渲染内容的摘要），最近一次应用的结果保存在项目状态中，可用 'skill-hub status' 查看。
+
+个别技能应用失败时继续应用其余技能，最后以非零退出码结束：
+  2  部分技能失败，其余技能已应用
+  3  没有应用任何技能（全部失败，或因 --strict、写入失败已回滚）
+使用 --strict 时任一技能失败立即回滚本次的所有修改。`,
+	ValidArgsFunction: completeProjectSkillIDList,
+	// 技能应用失败时以退出码区分部分失败和全部失败，不再附带用法说明
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runApply()
+		return runApply(args)
 	},
 }
 
 func init() {
 	applyCmd.Flags().BoolVar(&dryRun, "dry-run", false, "预览变更而不实际修改文件")
-	applyCmd.Flags().StringVar(&target, "target", "", "目标工具: cursor, claude_code, open_code, all (为空时使用状态绑定的目标)")
+	applyCmd.Flags().StringVar(&target, "target", "", "目标工具: cursor, claude_code, open_code, shell, all (为空时使用状态绑定的目标)")
 	applyCmd.Flags().StringVar(&mode, "mode", "project", "配置模式: project (项目级), global (全局)")
 	applyCmd.Flags().BoolVar(&autoFix, "auto-fix", false, "自动修复不符合标准的技能")
 	applyCmd.Flags().BoolVar(&skipValidation, "skip-validation", false, "跳过技能标准校验")
-	applyCmd.Flags().BoolVar(&strictMode, "strict", false, "严格模式：发现不合规技能立即失败")
+	applyCmd.Flags().BoolVar(&strictMode, "strict", false, "严格模式：发现不合规技能或任一技能应用失败时立即失败并回滚")
 	applyCmd.Flags().BoolVar(&interactive, "interactive", false, "交互式模式：询问用户确认修复")
+	applyCmd.Flags().BoolVar(&applyNoDeps, "no-deps", false, "不解析技能依赖")
+	applyCmd.Flags().BoolVar(&applyGlobal, "global", false, "应用全局作用域的技能到用户级配置（隐含 --mode global）")
+	applyCmd.Flags().StringVar(&applyProfile, "profile", "", "使用的变量配置 (为空时使用项目的默认配置)")
+	applyCmd.Flags().BoolVar(&applyWorkspace, "workspace", false, "将工作区的技能应用到所有成员项目")
+	applyCmd.Flags().StringVar(&applyLocale, "locale", "", "技能提示词的语言，例如 zh-CN (为空时使用项目或全局配置的语言)")
+	applyCmd.Flags().BoolVar(&applyNoMerge, "no-merge", false, "直接覆盖目标文件中的本地修改，不进行三方合并")
+	applyCmd.Flags().StringArrayVar(&applyExclude, "exclude", nil, "不应用指定的技能（可多次指定）")
+	addWordDiffFlag(applyCmd)
+	addProjectFlag(applyCmd)
+
+	applyCmd.RegisterFlagCompletionFunc("target", fixedCompletions(targetCompletions...))
+	applyCmd.RegisterFlagCompletionFunc("mode", fixedCompletions(modeCompletions...))
+	applyCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+	applyCmd.RegisterFlagCompletionFunc("exclude", completeProjectSkillIDList)
 }
 
-func runApply() error {
-	fmt.Println("正在应用技能到当前项目...")
+func runApply(only []string) error {
+	for _, skillID := range append(append([]string{}, only...), applyExclude...) {
+		if err := spec.ValidateSkillID(skillID); err != nil {
+			return err
+		}
+	}
+	if applyWorkspace {
+		return runApplyWorkspace(only)
+	}
+	if applyGlobal {
+		mode = "global"
+		fmt.Println("正在应用全局技能到用户级配置...")
+	} else {
+		fmt.Println("正在应用技能到当前项目...")
+	}
 
-	// 获取当前目录
-	cwd, err := os.Getwd()
+	// 创建状态管理器，cwd 为作用域对应的项目路径
+	stateMgr, cwd, err := scopeState(applyGlobal)
 	if err != nil {
-		return fmt.Errorf("获取当前目录失败: %w", err)
+		return err
 	}
 
-	// 创建状态管理器
-	stateMgr, err := state.NewStateManager()
+	// 确定目标工具：指定了 --target 时使用指定的目标，否则使用项目按顺序绑定的目标
+	resolvedTargets, err := resolveTargets(target, nil)
 	if err != nil {
 		return err
 	}
-
-	// 确定目标工具
-	resolvedTarget := target
-	switch resolvedTarget {
-	case spec.TargetAll:
-		// 如果指定了all，直接使用all
-	case "":
-		// 如果没有指定target，尝试从状态获取
+	if target == "" {
 		projectState, err := stateMgr.FindProjectByPath(cwd)
 		if err != nil {
 			return fmt.Errorf("查找项目状态失败: %w", err)
@@ -95,72 +173,190 @@ func runApply() error {
 			}
 		}
 
-		if projectState.PreferredTarget == "" {
+		if resolvedTargets, err = resolveTargets("", projectState); err != nil {
+			return err
+		}
+		if len(resolvedTargets) == 0 {
 			// 未绑定项目
 			fmt.Println("❌ 当前目录未关联目标")
 			fmt.Println("请先执行以下操作之一:")
 			fmt.Printf("  1. 使用 'skill-hub set-target [%s|%s|%s]' 设置首选目标\n", spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode)
-			fmt.Printf("  2. 使用 'skill-hub use [skill-id] --target [%s|%s|%s]' 启用技能并指定目标\n", spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode)
-			fmt.Printf("  3. 使用 'skill-hub apply --target [%s|%s|%s|%s]' 显式指定目标\n", spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetAll)
+			fmt.Printf("  2. 使用 'skill-hub use [skill-id]%s --target [%s|%s|%s]' 启用技能并指定目标\n", scopeFlag(applyGlobal), spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode)
+			fmt.Printf("  3. 使用 'skill-hub apply%s --target [%s|%s|%s|%s]' 显式指定目标\n", scopeFlag(applyGlobal), spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetAll)
 			return nil
 		}
+		fmt.Printf("🔍 使用状态绑定的目标: %s\n", strings.Join(resolvedTargets, ", "))
+	}
 
-		resolvedTarget = spec.NormalizeTarget(projectState.PreferredTarget)
-		fmt.Printf("🔍 使用状态绑定的目标: %s\n", resolvedTarget)
+	if applyGlobal {
+		fmt.Println("作用域: 全局")
+	} else {
+		fmt.Printf("当前项目: %s\n", cwd)
 	}
+	fmt.Printf("目标工具: %s\n", strings.Join(resolvedTargets, ", "))
 
-	fmt.Printf("当前项目: %s\n", cwd)
-	fmt.Printf("目标工具: %s\n", resolvedTarget)
+	projectState, err := stateMgr.LoadProjectState(cwd)
+	if err != nil {
+		return err
+	}
+	skills := projectState.Skills
 
-	skills, err := stateMgr.GetProjectSkills(cwd)
+	// 使用变量配置时以合并后的变量渲染技能
+	profileName, profileVars, err := state.ResolveProfile(projectState, applyProfile)
 	if err != nil {
 		return err
 	}
+	if profileName != "" {
+		fmt.Printf("变量配置: %s\n", profileName)
+		for skillID, variables := range profileVars {
+			skillVars := skills[skillID]
+			skillVars.Variables = variables
+			skills[skillID] = skillVars
+		}
+	}
 
 	if len(skills) == 0 {
-		fmt.Println("ℹ️  当前项目未启用任何技能")
-		fmt.Println("使用 'skill-hub use <skill-id>' 启用技能")
+		if applyGlobal {
+			fmt.Println("ℹ️  全局作用域未启用任何技能")
+		} else {
+			fmt.Println("ℹ️  当前项目未启用任何技能")
+		}
+		fmt.Printf("使用 'skill-hub use <skill-id>%s' 启用技能\n", scopeFlag(applyGlobal))
 		return nil
 	}
+	for _, skillID := range only {
+		if _, ok := skills[skillID]; !ok {
+			return fmt.Errorf("技能 '%s' 未启用，使用 'skill-hub use %s%s' 启用", skillID, skillID, scopeFlag(applyGlobal))
+		}
+	}
 
 	// 加载技能管理器
 	skillManager, err := engine.NewSkillManager()
 	if err != nil {
 		return err
 	}
+	useProjectLocale(skillManager, projectState)
+	if applyLocale != "" {
+		locale, err := spec.NormalizeLocale(applyLocale)
+		if err != nil {
+			return err
+		}
+		skillManager.SetLocale(locale)
+	}
+	if locale := skillManager.Locale(); locale != "" {
+		fmt.Printf("🌐 技能提示词语言: %s\n", locale)
+	}
 
-	// 检查技能与目标的兼容性（当使用状态绑定的目标时）
-	if target == "" && resolvedTarget != spec.TargetAll {
-		fmt.Println("\n🔍 检查技能与目标兼容性...")
-		incompatibleSkills := []string{}
+	// 加载项目锁文件，已固定的技能不会被静默升级
+	lock, err := state.LoadLockFile(cwd)
+	if err != nil {
+		return err
+	}
+	versions, err := engine.NewVersionStore()
+	if err != nil {
+		return err
+	}
 
-		for skillID := range skills {
+	// 确定应用顺序：优先级高的技能先应用，被依赖的技能先应用
+	skillIDs := make([]string, 0, len(skills))
+	for skillID := range skills {
+		skillIDs = append(skillIDs, skillID)
+	}
+	sort.Strings(skillIDs)
+	orderByPriority(skillManager, skillIDs, skills)
+
+	added := make(map[string]bool)
+	if !applyNoDeps {
+		// 技能仓库中已不存在的技能不参与解析，应用时照常提示跳过
+		var roots, missing []string
+		for _, skillID := range skillIDs {
+			if skillManager.SkillExists(skillID) {
+				roots = append(roots, skillID)
+			} else {
+				missing = append(missing, skillID)
+			}
+		}
+		// 已固定的技能按锁定的版本检查依赖约束
+		resolved, err := engine.ResolveDependencies(roots, func(skillID string) (*spec.Skill, error) {
 			skill, err := skillManager.LoadSkill(skillID)
 			if err != nil {
-				continue
+				return nil, err
+			}
+			if stored, _, ok := loadPinnedVersion(versions, lock, skill, ""); ok {
+				return stored, nil
 			}
+			if locked, pinned := lock.Get(skillID); pinned {
+				pinnedSkill := *skill
+				pinnedSkill.Version = locked.Version
+				return &pinnedSkill, nil
+			}
+			return skill, nil
+		})
+		if err != nil {
+			return err
+		}
+		skillIDs = append(resolved, missing...)
+
+		// dry-run 时只预览依赖技能，不写入项目状态
+		enabled, err := enableDependencies(newInputReader(), skillManager, stateMgr, cwd, skillIDs, skills, !dryRun)
+		if err != nil {
+			return err
+		}
+		for skillID, skillVars := range enabled {
+			skills[skillID] = skillVars
+			added[skillID] = true
+		}
+	}
+	debugf("应用顺序: %s", strings.Join(skillIDs, ", "))
 
-			// 检查技能是否兼容当前目标
-			isCompatible := false
-			if skill.Compatibility != "" {
-				compatLower := strings.ToLower(skill.Compatibility)
-				targetLower := strings.ToLower(resolvedTarget)
+	// 目标文件中的技能按全部技能的应用顺序排列，只应用选择的技能
+	order := skillIDs
+	skillIDs = filterApplySkills(order, only, applyExclude, added)
+	if len(skillIDs) == 0 {
+		fmt.Println("ℹ️  排除指定的技能后没有要应用的技能")
+		return nil
+	}
+	if len(skillIDs) < len(order) {
+		fmt.Printf("应用的技能: %s\n", strings.Join(skillIDs, ", "))
+	}
 
-				// 检查兼容性字符串中是否包含目标名称
-				if strings.Contains(compatLower, targetLower) {
-					isCompatible = true
-				} else if resolvedTarget == spec.TargetOpenCode && strings.Contains(compatLower, "opencode") {
-					isCompatible = true
-				} else if resolvedTarget == spec.TargetClaudeCode && (strings.Contains(compatLower, "claude code") || strings.Contains(compatLower, "claude_code")) {
-					isCompatible = true
+	// 运行要求不满足时不应用任何技能，避免平台相关的脚本在不支持的系统上静默失效
+	var orderedSkills []*spec.Skill
+	for _, skillID := range skillIDs {
+		if skill, err := skillManager.LoadSkill(skillID); err == nil {
+			orderedSkills = append(orderedSkills, skill)
+		}
+	}
+	if err := checkSkillRequirements(orderedSkills); err != nil {
+		return err
+	}
+	if locale := skillManager.Locale(); locale != "" {
+		for _, skill := range orderedSkills {
+			if resolved := skillManager.ResolveLocale(skill.ID); resolved != locale {
+				if resolved == "" {
+					fmt.Printf("ℹ️  技能 %s 没有 %s 语言版本，使用 SKILL.md\n", skill.ID, locale)
+				} else {
+					fmt.Printf("ℹ️  技能 %s 没有 %s 语言版本，使用 %s\n", skill.ID, locale, resolved)
 				}
-			} else {
-				// 如果没有指定兼容性，假设兼容所有
-				isCompatible = true
 			}
+		}
+	}
+
+	// 检查技能与目标的兼容性（当使用状态绑定的目标时）
+	if target == "" {
+		fmt.Println("\n🔍 检查技能与目标兼容性...")
+		incompatibleSkills := []string{}
 
-			if !isCompatible {
-				incompatibleSkills = append(incompatibleSkills, fmt.Sprintf("%s (不兼容 %s)", skillID, resolvedTarget))
+		for _, skillID := range skillIDs {
+			skill, err := skillManager.LoadSkill(skillID)
+			if err != nil {
+				continue
+			}
+			for _, resolvedTarget := range resolvedTargets {
+				// 检查技能是否兼容当前目标，与应用时筛选适配器的规则相同
+				if !skill.SupportsTarget(resolvedTarget) {
+					incompatibleSkills = append(incompatibleSkills, fmt.Sprintf("%s (不兼容 %s)", skillID, resolvedTarget))
+				}
 			}
 		}
 
@@ -176,56 +372,67 @@ func runApply() error {
 
 	// 根据目标选择适配器
 	var adapters []adapter.Adapter
-
-	if resolvedTarget == spec.TargetAll || resolvedTarget == spec.TargetCursor {
-		cursorAdapter := cursor.NewCursorAdapter()
-		if mode == "global" {
-			cursorAdapter = cursorAdapter.WithGlobalMode()
-		} else {
-			cursorAdapter = cursorAdapter.WithProjectMode()
-		}
-		adapters = append(adapters, cursorAdapter)
-	}
-
-	if resolvedTarget == spec.TargetAll || resolvedTarget == spec.TargetClaudeCode {
-		claudeAdapter := claude.NewClaudeAdapter()
-		if mode == "global" {
-			claudeAdapter = claudeAdapter.WithGlobalMode()
-		} else {
-			claudeAdapter = claudeAdapter.WithProjectMode()
-		}
-		adapters = append(adapters, claudeAdapter)
-	}
-
-	if resolvedTarget == spec.TargetAll || resolvedTarget == spec.TargetOpenCode {
-		opencodeAdapter := opencode.NewOpenCodeAdapter()
-		if mode == "global" {
-			opencodeAdapter = opencodeAdapter.WithGlobalMode()
-		} else {
-			opencodeAdapter = opencodeAdapter.WithProjectMode()
-		}
-		adapters = append(adapters, opencodeAdapter)
+	for _, resolvedTarget := range resolvedTargets {
+		adapters = append(adapters, selectAdapters(resolvedTarget, mode)...)
 	}
 
 	if len(adapters) == 0 {
-		return fmt.Errorf("无效的目标工具: %s，可用选项: %s, %s, %s, %s", resolvedTarget, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetAll)
+		return fmt.Errorf("无效的目标工具: %s，可用选项: %s, %s, %s, %s, %s", strings.Join(resolvedTargets, ", "), spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell, spec.TargetAll)
 	}
 
-	// 应用每个技能到每个适配器
-	totalApplied := 0
+	debugf("状态文件: %s", stateMgr.GetStatePath())
+	debugf("锁文件: %s (%d 个固定技能)", lock.GetPath(), len(lock.Skills))
+
+	// 应用每个技能到每个适配器（所有写入在同一事务中，任一失败则全部回滚）
+	totalApplied, totalUpToDate := 0, 0
+	tx := adapter.NewTransaction()
+	var records []appliedRecord
+	report := &spec.ApplyReport{
+		AppliedAt: time.Now().UTC().Format(time.RFC3339),
+		DryRun:    dryRun,
+		Mode:      mode,
+		Targets:   resolvedTargets,
+		Profile:   profileName,
+		Skills:    []spec.ApplyResult{},
+		Files:     []string{},
+	}
+	var changed []string // 修改的目标文件
 
 	for _, adapter := range adapters {
 		adapterName := getAdapterName(adapter)
 		fmt.Printf("\n=== 处理 %s 适配器 ===\n", adapterName)
 
-		adapterApplied := 0
-		for skillID, skillVars := range skills {
+		adapterApplied, adapterUpToDate := 0, 0
+		adapterTarget := getAdapterTarget(adapter)
+		rendered := make(map[string]string) // 技能ID -> 渲染后的内容，用于估算token
+		for _, skillID := range skillIDs {
+			skillVars := skills[skillID]
 			fmt.Printf("\n处理技能: %s\n", skillID)
+			// addResult 记录技能应用到当前适配器的结果
+			addResult := func(status string, reason error) {
+				result := spec.ApplyResult{SkillID: skillID, Target: adapterTarget, Status: status}
+				if reason != nil {
+					result.Reason = reason.Error()
+				}
+				report.Skills = append(report.Skills, result)
+			}
+			// fail 记录技能应用失败，--strict 时回滚已应用的技能并返回错误
+			fail := func(err error) error {
+				addResult(spec.ApplyFailed, err)
+				if !strictMode {
+					return nil
+				}
+				tx.Rollback()
+				return applyFailed(fmt.Errorf("严格模式下技能 %s 应用到 %s 失败: %w", skillID, adapterName, err))
+			}
 
 			// 获取技能文件路径
 			skillPath, err := getSkillFilePath(skillManager, skillID)
 			if err != nil {
-				fmt.Printf("⚠️  跳过技能 %s: %v\n", skillID, err)
+				fmt.Printf("❌ 加载技能 %s 失败: %v\n", skillID, err)
+				if err := fail(err); err != nil {
+					return err
+				}
 				continue
 			}
 
@@ -235,8 +442,10 @@ func runApply() error {
 				if err != nil {
 					fmt.Printf("⚠️  技能验证失败 %s: %v\n", skillID, err)
 					if strictMode {
-						return fmt.Errorf("严格模式下验证失败: %s", skillID)
+						tx.Rollback()
+						return applyFailed(fmt.Errorf("严格模式下验证失败: %s", skillID))
 					}
+					addResult(spec.ApplyFailed, err)
 					continue
 				}
 
@@ -247,11 +456,13 @@ func runApply() error {
 					}
 
 					if strictMode {
-						return fmt.Errorf("严格模式下发现不合规技能: %s", skillID)
+						tx.Rollback()
+						return applyFailed(fmt.Errorf("严格模式下发现不合规技能: %s", skillID))
 					}
 
 					if !autoFix {
 						fmt.Println("  使用 --auto-fix 自动修复或 --skip-validation 跳过验证")
+						addResult(spec.ApplySkipped, fmt.Errorf("技能不符合标准"))
 						continue
 					}
 				}
@@ -260,60 +471,252 @@ func runApply() error {
 			// 加载技能详情
 			skill, err := skillManager.LoadSkill(skillID)
 			if err != nil {
-				fmt.Printf("⚠️  跳过技能 %s: %v\n", skillID, err)
+				fmt.Printf("❌ 加载技能 %s 失败: %v\n", skillID, err)
+				if err := fail(err); err != nil {
+					return err
+				}
 				continue
 			}
 
+			// 固定的版本已安装时使用该版本的技能和提示词
+			storedPrompt := ""
+			if stored, content, ok := loadPinnedVersion(versions, lock, skill, skillManager.Locale()); ok {
+				fmt.Printf("📌 技能 %s 固定在 %s（仓库版本 %s），使用已安装的该版本\n", skillID, stored.Version, skill.Version)
+				skill, storedPrompt = stored, content
+			}
+
 			// 检查适配器支持
 			if !adapterSupportsSkill(adapter, skill) {
 				fmt.Printf("ℹ️  技能 %s 不支持 %s，跳过\n", skillID, adapterName)
+				addResult(spec.ApplySkipped, fmt.Errorf("技能不支持 %s", adapterTarget))
 				continue
 			}
 
 			// 获取提示词内容
-			prompt, err := skillManager.GetSkillPrompt(skillID)
+			prompt := storedPrompt
+			if prompt == "" {
+				prompt, err = skillManager.GetSkillPrompt(skillID)
+				if err != nil {
+					fmt.Printf("❌ 读取技能 %s 的提示词失败: %v\n", skillID, err)
+					if err := fail(err); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+
+			// 按锁文件确定应用的内容，使用固定版本的应用记录时内容已渲染，不再处理变量和条件区块
+			content, variables, version, err := resolvePinnedSkill(lock, skill, prompt, skillVars, getAdapterTarget(adapter))
+			if err == nil && version == skill.Version {
+				if content, err = engine.RenderContent(skill, content, variables, getAdapterTarget(adapter), mode); err != nil {
+					err = fmt.Errorf("渲染技能 %s 失败: %w（使用 'skill-hub use %s' 设置变量）", skillID, err, skillID)
+				}
+			}
 			if err != nil {
-				fmt.Printf("⚠️  跳过技能 %s: %v\n", skillID, err)
+				fmt.Printf("❌ %v\n", err)
+				if err := fail(err); err != nil {
+					return err
+				}
 				continue
 			}
 
+			// 计算变更计划，目标文件有本地修改时与新内容三方合并
+			plan, merge, err := planSkillMerge(adapter, skillVars, skillID, content, variables)
+			if err != nil {
+				fmt.Printf("❌ 预览技能 %s 到 %s 失败: %v\n", skillID, adapterName, err)
+				if err := fail(err); err != nil {
+					return err
+				}
+				continue
+			}
+			if merge.Merged && merge.Conflicts > 0 {
+				fmt.Printf("⚠️  技能 %s 在 %s 中有本地修改，与新内容合并时有 %d 处冲突，冲突处写入冲突标记，请手动解决\n", skillID, adapterName, merge.Conflicts)
+			} else if merge.Merged {
+				fmt.Printf("🔀 技能 %s 在 %s 中的本地修改已与新内容合并\n", skillID, adapterName)
+			}
+
+			rendered[skillID] = content
+			result := spec.ApplyResult{
+				SkillID:   skillID,
+				Target:    adapterTarget,
+				Version:   version,
+				Status:    spec.ApplyApplied,
+				File:      plan.FilePath,
+				Hash:      state.ContentHash(content),
+				Merged:    merge.Merged,
+				Conflicts: merge.Conflicts,
+			}
+			if !plan.HasChanges() {
+				result.Status = spec.ApplyUpToDate
+			}
+
 			if dryRun {
-				fmt.Printf("🔍 DRY RUN - 将应用技能 %s 到 %s\n", skillID, adapterName)
-				fmt.Printf("变量: %v\n", skillVars.Variables)
-				adapterApplied++
+				fmt.Printf("🔍 DRY RUN - 技能 %s -> %s (%s)\n", skillID, adapterName, plan.FilePath)
+				if plan.HasChanges() {
+					printDiff(plan.Diff(), "")
+					changed = append(changed, plan.ChangedFiles()...)
+					adapterApplied++
+				} else {
+					fmt.Println("  无变化")
+					adapterUpToDate++
+				}
+				report.Skills = append(report.Skills, result)
 				continue
 			}
 
+			debugf("%s -> %s: 目标文件 %v, 有变化: %t", skillID, adapterName, plan.Files(), plan.HasChanges())
+
+			// 目标文件中已是本次的内容时不写入也不备份，应用记录不一致时只更新记录
+			if !plan.HasChanges() {
+				fmt.Printf("✓ 技能 %s 在 %s 中已是最新\n", skillID, adapterName)
+				adapterUpToDate++
+				report.Skills = append(report.Skills, result)
+				records = append(records, appliedRecord{
+					skillID: skillID,
+					target:  adapterTarget,
+					rev: spec.AppliedRevision{
+						Version:   version,
+						Variables: skillVars.Variables,
+						Profile:   profileName,
+						Content:   rendered[skillID],
+						Mode:      mode,
+					},
+					upToDate: state.AppliedUpToDate(skillVars, adapterTarget, mode, version, rendered[skillID]),
+				})
+				continue
+			}
+
+			// 登记目标文件以便失败时回滚
+			for _, path := range plan.Files() {
+				if err := tx.Track(path); err != nil {
+					tx.Rollback()
+					return applyFailed(fmt.Errorf("备份 %s 失败: %w", path, err))
+				}
+			}
+
 			// 实际应用技能
-			if err := adapter.Apply(skillID, prompt, skillVars.Variables); err != nil {
+			if err := applyMergedPlan(adapter, plan, merge, content, variables); err != nil {
 				fmt.Printf("❌ 应用技能 %s 到 %s 失败: %v\n", skillID, adapterName, err)
-				// 尝试恢复操作
-				if recoveryErr := attemptRecovery(adapter, skillID); recoveryErr != nil {
-					fmt.Printf("⚠️  恢复操作失败: %v\n", recoveryErr)
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					fmt.Printf("⚠️  回滚失败: %v\n", rollbackErr)
+					// 回滚失败时尽量清理当前适配器的残留内容
+					if recoveryErr := attemptRecovery(adapter, skillID); recoveryErr != nil {
+						fmt.Printf("⚠️  恢复操作失败: %v\n", recoveryErr)
+					}
+				} else {
+					fmt.Printf("↩️  已回滚 %d 个文件的变更\n", len(tx.Files()))
 				}
-				continue
+				return applyFailed(fmt.Errorf("应用技能 %s 到 %s 失败: %w", skillID, adapterName, err))
 			}
 
 			fmt.Printf("✓ 成功应用技能 %s 到 %s\n", skillID, adapterName)
 			adapterApplied++
+			report.Skills = append(report.Skills, result)
+			changed = append(changed, plan.ChangedFiles()...)
+
+			records = append(records, appliedRecord{
+				skillID: skillID,
+				target:  adapterTarget,
+				rev: spec.AppliedRevision{
+					Version:   version,
+					Variables: skillVars.Variables,
+					Profile:   profileName,
+					Content:   rendered[skillID],
+					Mode:      mode,
+				},
+				merge: merge,
+			})
+		}
+
+		// 按应用顺序排列目标文件中的技能，使生成的文件与技能启用的先后无关
+		if !dryRun && adapterApplied+adapterUpToDate > 0 {
+			files, err := orderAdapterSkills(tx, adapter, order)
+			if err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					fmt.Printf("⚠️  回滚失败: %v\n", rollbackErr)
+				}
+				return applyFailed(fmt.Errorf("排列 %s 中的技能失败: %w", adapterName, err))
+			}
+			changed = append(changed, files...)
 		}
 
-		if adapterApplied > 0 {
+		warnTokenBudget(adapterTarget, rendered)
+
+		switch {
+		case adapterApplied > 0 && adapterUpToDate > 0:
+			fmt.Printf("\n✅ %s: 成功应用 %d 个技能，%d 个技能已是最新\n", adapterName, adapterApplied, adapterUpToDate)
+		case adapterApplied > 0:
 			fmt.Printf("\n✅ %s: 成功应用 %d 个技能\n", adapterName, adapterApplied)
-			totalApplied += adapterApplied
-		} else {
+		case adapterUpToDate > 0:
+			fmt.Printf("\n✅ %s: %d 个技能已是最新\n", adapterName, adapterUpToDate)
+		default:
 			fmt.Printf("\nℹ️  %s: 没有技能被应用\n", adapterName)
 		}
+		totalApplied += adapterApplied
+		totalUpToDate += adapterUpToDate
 	}
 
-	if totalApplied > 0 {
+	if err := tx.Commit(); err != nil {
+		fmt.Printf("⚠️  清理事务备份失败: %v\n", err)
+	}
+
+	// 记录已应用内容，供 rollback 使用
+	for _, record := range records {
+		if record.upToDate {
+			continue
+		}
+		if err := stateMgr.RecordAppliedRevision(cwd, record.skillID, record.target, record.rev); err != nil {
+			fmt.Printf("⚠️  记录技能 %s 的应用历史失败: %v\n", record.skillID, err)
+		}
+		recordAudit(state.AuditEntry{
+			Operation: state.OpApply,
+			Project:   cwd,
+			SkillID:   record.skillID,
+			Version:   record.rev.Version,
+			Adapter:   record.target,
+		})
+	}
+
+	// 资源文件与目标工具无关，每个技能只安装一次，全局模式下不安装到项目中
+	if mode != "global" {
+		installed := make(map[string]bool)
+		for _, skillID := range skillIDs {
+			if installed[skillID] || (!dryRun && !hasRecord(records, skillID)) {
+				continue
+			}
+			installed[skillID] = true
+			// 固定的版本已安装时使用该版本的资源文件
+			skillDir := skillManager.GetSkillDir(skillID)
+			if locked, pinned := lock.Get(skillID); pinned && versions.Has(skillID, locked.Version) {
+				skillDir = versions.Dir(skillID, locked.Version)
+			}
+			if err := applySkillResources(skillDir, stateMgr, cwd, skillID, skills[skillID].Resources); err != nil {
+				fmt.Printf("⚠️  安装技能 %s 的资源文件失败: %v\n", skillID, err)
+			}
+		}
+	}
+
+	report.Files = uniqueFiles(changed)
+	printApplyReport(report)
+	if !dryRun {
+		if err := stateMgr.SetLastApply(cwd, report); err != nil {
+			fmt.Printf("⚠️  保存应用结果失败: %v\n", err)
+		}
+	}
+	setResult(report)
+
+	switch {
+	case totalApplied > 0:
 		fmt.Printf("\n🎉 总计成功应用 %d 个技能\n", totalApplied)
 		fmt.Println("使用 'skill-hub status' 检查技能状态")
-	} else {
+	case totalUpToDate > 0:
+		fmt.Printf("\n✅ 所有技能都已是最新（%d 个），没有修改目标文件\n", totalUpToDate)
+	default:
 		fmt.Println("\nℹ️  没有技能被应用到任何适配器")
 	}
 
-	return nil
+	// 个别技能失败时其余技能已应用，以退出码区分部分失败和全部失败
+	return applyFailures(report.Count(spec.ApplyFailed), report.Count(spec.ApplyApplied)+report.Count(spec.ApplyUpToDate), "个技能")
 }
 
 // validateAndFixSkill 验证并修复技能文件
@@ -455,14 +858,8 @@ func attemptRecovery(adpt adapter.Adapter, skillID string) error {
 
 // getSkillFilePath 获取技能文件路径
 func getSkillFilePath(skillManager *engine.SkillManager, skillID string) (string, error) {
-	// Try to get skills directory
-	skillsDir, err := engine.GetSkillsDir()
-	if err != nil {
-		return "", fmt.Errorf("获取技能目录失败: %w", err)
-	}
-
-	// Only use standard structure: skills/skillID
-	skillDir := fmt.Sprintf("%s/%s", skillsDir, skillID)
+	// Only use standard structure: <skills root>/skillID
+	skillDir := skillManager.GetSkillDir(skillID)
 	skillPath := fmt.Sprintf("%s/SKILL.md", skillDir)
 	if _, err := os.Stat(skillPath); err == nil {
 		return skillPath, nil
@@ -471,6 +868,225 @@ func getSkillFilePath(skillManager *engine.SkillManager, skillID string) (string
 	return "", fmt.Errorf("找不到技能文件: %s", skillID)
 }
 
+// appliedRecord 待写入状态的应用记录
+type appliedRecord struct {
+	skillID  string
+	target   string
+	rev      spec.AppliedRevision
+	merge    adapter.MergeResult
+	upToDate bool // 目标文件和应用记录都已是本次的内容，没有写入
+}
+
+// applyStatusLabels 应用结果的显示文本
+var applyStatusLabels = map[string]string{
+	spec.ApplyApplied:  "已应用",
+	spec.ApplyUpToDate: "已是最新",
+	spec.ApplySkipped:  "已跳过",
+	spec.ApplyFailed:   "失败",
+}
+
+// printApplyReport 以表格输出每个技能应用到每个目标的结果和修改的文件
+func printApplyReport(report *spec.ApplyReport) {
+	if len(report.Skills) == 0 {
+		return
+	}
+	fmt.Println("\n=== 应用结果 ===")
+	fmt.Printf("%-20s %-12s %-10s %-10s %s\n", "技能", "目标", "版本", "状态", "说明")
+	for _, result := range report.Skills {
+		status := applyStatusLabels[result.Status]
+		if report.DryRun && result.Status == spec.ApplyApplied {
+			status = "将应用"
+		}
+		detail := result.File
+		if result.Reason != "" {
+			detail = result.Reason
+		}
+		if result.Conflicts > 0 {
+			detail += fmt.Sprintf("（%d 处冲突）", result.Conflicts)
+		}
+		version := result.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Printf("%-20s %-12s %-10s %-10s %s\n", result.SkillID, result.Target, version, status, detail)
+	}
+	if len(report.Files) > 0 {
+		if report.DryRun {
+			fmt.Println("\n将修改的文件:")
+		} else {
+			fmt.Println("\n修改的文件:")
+		}
+		for _, file := range report.Files {
+			fmt.Printf("  %s\n", file)
+		}
+	}
+}
+
+// uniqueFiles 返回去重并排序后的文件路径
+func uniqueFiles(files []string) []string {
+	seen := make(map[string]bool)
+	unique := []string{}
+	for _, file := range files {
+		if !seen[file] {
+			seen[file] = true
+			unique = append(unique, file)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// hasRecord 检查技能是否已应用到至少一个目标
+func hasRecord(records []appliedRecord, skillID string) bool {
+	for _, record := range records {
+		if record.skillID == skillID {
+			return true
+		}
+	}
+	return false
+}
+
+// applySkillResources 将技能 resources/ 目录中的文件安装到项目中并记录到项目状态，
+// 同时删除上一次安装、技能中已不存在的资源文件
+func applySkillResources(skillDir string, stateMgr *state.StateManager, cwd, skillID string, previous []string) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		files, err := resource.List(skillDir)
+		if err != nil || len(files) == 0 {
+			return err
+		}
+		dest, err := resource.Destination(cfg.ResourcesDir, skillID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🔍 DRY RUN - 技能 %s 的 %d 个资源文件 -> %s (%s)\n", skillID, len(files), dest, cfg.ResourcesMode)
+		return nil
+	}
+
+	files, err := resource.Install(skillDir, cwd, cfg.ResourcesDir, skillID, cfg.ResourcesMode)
+	if err != nil {
+		return err
+	}
+	if stale := resource.Stale(previous, files); len(stale) > 0 {
+		if err := resource.Remove(cwd, stale); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+		debugf("%s: 删除 %d 个过期的资源文件", skillID, len(stale))
+	}
+	if len(files) == 0 && len(previous) == 0 {
+		return nil
+	}
+	if len(files) > 0 {
+		fmt.Printf("✓ 已安装技能 %s 的 %d 个资源文件\n", skillID, len(files))
+	}
+	return stateMgr.SetSkillResources(cwd, skillID, files)
+}
+
+// warnTokenBudget 估算应用到目标工具的全部技能合计的token，超过配置的预算时给出警告
+func warnTokenBudget(target string, rendered map[string]string) {
+	budget := engine.TokenBudgetFor(target)
+	if budget <= 0 || len(rendered) == 0 {
+		return
+	}
+	estimate := engine.EstimateTokenBudget(target, rendered, budget)
+	debugf("%s: 技能合计约 %d tokens，预算 %d", target, estimate.Total, budget)
+	if !estimate.Exceeded() {
+		return
+	}
+
+	fmt.Printf("\n⚠️  %s 的技能合计约 %d tokens，超过预算 %d，过长的配置会挤占上下文\n", target, estimate.Total, budget)
+	for i, skill := range estimate.Skills {
+		if i == 3 {
+			fmt.Printf("  ... 共 %d 个技能，使用 'skill-hub status' 查看明细\n", len(estimate.Skills))
+			break
+		}
+		fmt.Printf("  - %s: 约 %d tokens\n", skill.SkillID, skill.Tokens)
+	}
+	fmt.Println("  可以精简技能内容、移除不需要的技能，或在配置文件中调整 token_budgets")
+}
+
+// planSkillMerge 计算应用技能的变更计划，目标文件有本地修改时以上次应用的内容为共同祖先三方合并
+func planSkillMerge(adpt adapter.Adapter, skillVars spec.SkillVars, skillID, content string, variables map[string]string) (*adapter.Plan, adapter.MergeResult, error) {
+	base, ok := state.AppliedBase(skillVars, getAdapterTarget(adpt))
+	if applyNoMerge || !ok {
+		plan, err := adpt.Plan(skillID, content, variables)
+		return plan, adapter.MergeResult{}, err
+	}
+	return adapter.PlanMerge(adpt, skillID, base, content, variables)
+}
+
+// applyMergedPlan 应用技能，进行了三方合并时写入合并后的内容
+func applyMergedPlan(adpt adapter.Adapter, plan *adapter.Plan, merge adapter.MergeResult, content string, variables map[string]string) error {
+	return adapter.ApplyMerged(adpt, plan, merge, content, variables)
+}
+
+// filterApplySkills 按指定的技能ID和 --exclude 筛选要应用的技能，保持 order 中的应用顺序
+//
+// only 为空时选择全部技能；added 为本次自动启用的依赖技能，没有被排除时总是应用。
+func filterApplySkills(order, only, exclude []string, added map[string]bool) []string {
+	selected := make(map[string]bool, len(only))
+	for _, skillID := range only {
+		selected[skillID] = true
+	}
+	excluded := make(map[string]bool, len(exclude))
+	for _, skillID := range exclude {
+		excluded[skillID] = true
+	}
+
+	var skillIDs []string
+	for _, skillID := range order {
+		if excluded[skillID] {
+			continue
+		}
+		if len(only) == 0 || selected[skillID] || added[skillID] {
+			skillIDs = append(skillIDs, skillID)
+		}
+	}
+	return skillIDs
+}
+
+// orderAdapterSkills 按应用顺序排列适配器目标文件中的技能，顺序需要调整时先在事务中登记目标文件，返回修改的文件
+func orderAdapterSkills(tx *adapter.Transaction, adpt adapter.Adapter, skillIDs []string) ([]string, error) {
+	plan, err := adapter.PlanOrder(adpt, skillIDs)
+	if err != nil || plan == nil || !plan.HasChanges() {
+		return nil, err
+	}
+	debugf("调整 %s 中技能的顺序", plan.FilePath)
+	if err := tx.Track(plan.FilePath); err != nil {
+		return nil, fmt.Errorf("备份 %s 失败: %w", plan.FilePath, err)
+	}
+	return plan.ChangedFiles(), adapter.Order(adpt, skillIDs)
+}
+
+// orderByPriority 按技能在项目中的优先级从高到低排列技能ID，技能仓库中不存在的技能优先级为 0
+func orderByPriority(skillManager *engine.SkillManager, skillIDs []string, skills map[string]spec.SkillVars) {
+	priorities := make(map[string]int, len(skillIDs))
+	for _, skillID := range skillIDs {
+		skill, _ := skillManager.LoadSkill(skillID)
+		priorities[skillID] = engine.EffectivePriority(skill, skills[skillID])
+	}
+	engine.OrderByPriority(skillIDs, func(skillID string) int { return priorities[skillID] })
+}
+
+// getAdapterTarget 获取适配器对应的目标类型
+func getAdapterTarget(adpt adapter.Adapter) string {
+	switch adpt.(type) {
+	case *cursor.CursorAdapter:
+		return spec.TargetCursor
+	case *claude.ClaudeAdapter:
+		return spec.TargetClaudeCode
+	case *opencode.OpenCodeAdapter:
+		return spec.TargetOpenCode
+	case *shell.ShellAdapter:
+		return spec.TargetShell
+	}
+	return spec.TargetUnknown
+}
+
 // getAdapterName 获取适配器名称
 func getAdapterName(adpt adapter.Adapter) string {
 	if _, ok := adpt.(*cursor.CursorAdapter); ok {
@@ -482,27 +1098,31 @@ func getAdapterName(adpt adapter.Adapter) string {
 	if _, ok := adpt.(*opencode.OpenCodeAdapter); ok {
 		return "OpenCode"
 	}
+	if _, ok := adpt.(*shell.ShellAdapter); ok {
+		return "Shell"
+	}
 	return "Unknown"
 }
 
 // adapterSupportsSkill 检查适配器是否支持该技能
 func adapterSupportsSkill(adpt adapter.Adapter, skill *spec.Skill) bool {
-	// 如果没有指定兼容性，假设兼容所有
-	if skill.Compatibility == "" {
-		return true
-	}
-
-	compatLower := strings.ToLower(skill.Compatibility)
+	return skill.SupportsTarget(getAdapterTarget(adpt))
+}
 
-	// 使用类型断言检查适配器类型
-	if _, ok := adpt.(*cursor.CursorAdapter); ok {
-		return strings.Contains(compatLower, "cursor")
+// checkSkillRequirements 检查技能的运行要求（skill-hub 版本和操作系统），列出所有不满足的技能
+func checkSkillRequirements(skills []*spec.Skill) error {
+	var problems []string
+	for _, skill := range skills {
+		if err := engine.CheckRequirements(skill, version); err != nil {
+			problems = append(problems, err.Error())
+		}
 	}
-	if _, ok := adpt.(*claude.ClaudeAdapter); ok {
-		return strings.Contains(compatLower, "claude code") || strings.Contains(compatLower, "claude_code")
+	if len(problems) == 0 {
+		return nil
 	}
-	if _, ok := adpt.(*opencode.OpenCodeAdapter); ok {
-		return strings.Contains(compatLower, "opencode")
+	fmt.Println("❌ 以下技能的运行要求不满足:")
+	for _, problem := range problems {
+		fmt.Printf("  - %s\n", problem)
 	}
-	return false
+	return fmt.Errorf("%d 个技能的运行要求不满足", len(problems))
 }
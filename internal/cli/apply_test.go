@@ -3,6 +3,7 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"skill-hub/internal/adapter"
@@ -150,7 +151,7 @@ func TestSelectAdapters(t *testing.T) {
 			name:   "All targets",
 			target: spec.TargetAll,
 			mode:   "project",
-			count:  3,
+			count:  4,
 		},
 		{
 			name:   "Cursor only",
@@ -170,6 +171,12 @@ func TestSelectAdapters(t *testing.T) {
 			mode:   "project",
 			count:  1,
 		},
+		{
+			name:   "Shell only",
+			target: spec.TargetShell,
+			mode:   "global",
+			count:  1,
+		},
 		{
 			name:   "Invalid target",
 			target: "invalid",
@@ -188,48 +195,27 @@ func TestSelectAdapters(t *testing.T) {
 	}
 }
 
-func TestRenderTemplateForRemove(t *testing.T) {
+func TestFilterApplySkills(t *testing.T) {
+	order := []string{"base", "lang", "lint", "docs"}
 	tests := []struct {
-		name      string
-		content   string
-		variables map[string]string
-		expected  string
+		name    string
+		only    []string
+		exclude []string
+		added   map[string]bool
+		want    string
 	}{
-		{
-			name:      "Simple variable replacement",
-			content:   "Hello {{.Name}}!",
-			variables: map[string]string{"Name": "World"},
-			expected:  "Hello World!",
-		},
-		{
-			name:      "Multiple variables",
-			content:   "Project: {{.Project}}, Port: {{.Port}}",
-			variables: map[string]string{"Project": "test", "Port": "8080"},
-			expected:  "Project: test, Port: 8080",
-		},
-		{
-			name:      "No variables",
-			content:   "Static content",
-			variables: map[string]string{},
-			expected:  "Static content",
-		},
-		{
-			name:      "Variable not in template",
-			content:   "Hello World!",
-			variables: map[string]string{"Name": "Test"},
-			expected:  "Hello World!",
-		},
+		{name: "全部技能", want: "base,lang,lint,docs"},
+		{name: "指定技能", only: []string{"lint", "lang"}, want: "lang,lint"},
+		{name: "排除技能", exclude: []string{"lang"}, want: "base,lint,docs"},
+		{name: "自动启用的依赖一起应用", only: []string{"lang"}, added: map[string]bool{"base": true}, want: "base,lang"},
+		{name: "排除优先", only: []string{"lang"}, exclude: []string{"lang"}, want: ""},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := renderTemplateForRemove(tt.content, tt.variables)
-			if err != nil {
-				t.Errorf("renderTemplateForRemove() error = %v", err)
-				return
-			}
-			if result != tt.expected {
-				t.Errorf("renderTemplateForRemove() = %v, want %v", result, tt.expected)
+			got := strings.Join(filterApplySkills(order, tt.only, tt.exclude, tt.added), ",")
+			if got != tt.want {
+				t.Errorf("filterApplySkills() = %s, want %s", got, tt.want)
 			}
 		})
 	}
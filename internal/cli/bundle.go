@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleTarget      string
+	bundleApplyNow    bool
+	bundleDescription string
+	bundleForce       bool
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "管理技能组合",
+	Long: `技能组合是一组带预设变量的技能，定义在技能仓库的 bundles.yaml 中，
+团队可以用一条命令在项目中启用标准技能栈。
+
+bundles.yaml 示例:
+  bundles:
+    go-backend:
+      description: Go 后端标准技能栈
+      skills:
+        - id: git-expert
+          variables:
+            LANGUAGE: go
+        - id: code-review`,
+}
+
+var bundleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出技能组合",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBundleList()
+	},
+}
+
+var bundleApplyCmd = &cobra.Command{
+	Use:   "apply <bundle>",
+	Short: "在当前项目启用技能组合中的全部技能",
+	Long: `在当前项目启用技能组合中的全部技能，变量使用组合中的预设值。
+
+组合未预设的变量沿用项目中已配置的值或技能默认值，
+仍无法确定的变量会提示输入。
+使用 --apply 在启用后立即将技能应用到当前项目。`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeBundleNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBundleApply(args[0])
+	},
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create <bundle> [skill-id...]",
+	Short: "创建技能组合",
+	Long: `创建技能组合并保存到技能仓库的 bundles.yaml。
+
+不指定技能ID时使用当前项目已启用的全部技能。
+技能已在当前项目启用时，其变量值作为组合的预设变量。`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeBundleCreateArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBundleCreate(args[0], args[1:])
+	},
+}
+
+func init() {
+	bundleApplyCmd.Flags().StringVar(&bundleTarget, "target", "", "首选目标工具: cursor, claude_code, open_code (为空时使用项目状态绑定的目标)")
+	bundleApplyCmd.Flags().BoolVar(&bundleApplyNow, "apply", false, "启用后立即应用技能")
+	bundleCreateCmd.Flags().StringVar(&bundleDescription, "description", "", "技能组合的描述")
+	bundleCreateCmd.Flags().BoolVar(&bundleForce, "force", false, "覆盖已存在的同名技能组合")
+
+	bundleApplyCmd.RegisterFlagCompletionFunc("target", fixedCompletions(targetCompletions...))
+
+	bundleCmd.AddCommand(bundleListCmd)
+	bundleCmd.AddCommand(bundleApplyCmd)
+	bundleCmd.AddCommand(bundleCreateCmd)
+}
+
+func runBundleList() error {
+	bundles, err := engine.LoadBundleFile()
+	if err != nil {
+		return err
+	}
+	setResult(bundles.Bundles)
+	if len(bundles.Bundles) == 0 {
+		fmt.Println("ℹ️  技能仓库中没有定义技能组合")
+		fmt.Println("使用 'skill-hub bundle create <name>' 从当前项目创建技能组合")
+		return nil
+	}
+
+	for _, name := range bundles.Names() {
+		bundle := bundles.Bundles[name]
+		fmt.Printf("📦 %s", name)
+		if bundle.Description != "" {
+			fmt.Printf(" - %s", bundle.Description)
+		}
+		fmt.Println()
+		for _, skill := range bundle.Skills {
+			fmt.Printf("   - %s", skill.ID)
+			if len(skill.Variables) > 0 {
+				fmt.Printf(" (%s)", formatBundleVariables(skill.Variables))
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+func runBundleApply(name string) error {
+	bundles, err := engine.LoadBundleFile()
+	if err != nil {
+		return err
+	}
+	bundle, ok := bundles.Get(name)
+	if !ok {
+		return fmt.Errorf("技能组合 '%s' 不存在，使用 'skill-hub bundle list' 查看可用组合", name)
+	}
+
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+
+	// 先加载全部技能，避免只启用了组合中的一部分
+	skills := make([]*spec.Skill, 0, len(bundle.Skills))
+	for _, item := range bundle.Skills {
+		skill, err := manager.LoadSkill(item.ID)
+		if err != nil {
+			return fmt.Errorf("技能组合 %s 中的技能 '%s' 不存在", name, item.ID)
+		}
+		skills = append(skills, skill)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	projectSkills, err := stateManager.GetProjectSkills(cwd)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("启用技能组合: %s (%d 个技能)\n", name, len(skills))
+
+	reader := newInputReader()
+	for i, skill := range skills {
+		variables, err := resolveBundleVariables(reader, skill, bundle.Skills[i].Variables, projectSkills[skill.ID].Variables)
+		if err != nil {
+			return err
+		}
+		if err := stateManager.AddSkillToProjectWithTarget(cwd, skill.ID, skill.Version, variables, bundleTarget); err != nil {
+			return fmt.Errorf("保存项目状态失败: %w", err)
+		}
+		fmt.Printf("✓ %s@%s\n", skill.ID, skill.Version)
+	}
+
+	fmt.Printf("\n✅ 技能组合 '%s' 已启用！\n", name)
+
+	if bundleApplyNow {
+		fmt.Println()
+		target = bundleTarget
+		return runApply(nil)
+	}
+
+	fmt.Println("使用 'skill-hub apply' 将技能应用到当前项目")
+	return nil
+}
+
+// resolveBundleVariables 确定技能变量值：组合预设值优先，其次是项目已配置的值和默认值，
+// 仍为空的必填变量提示用户输入
+func resolveBundleVariables(reader *bufio.Reader, skill *spec.Skill, preset, existing map[string]string) (map[string]string, error) {
+	declared := make(map[string]bool, len(skill.Variables))
+	values := make(map[string]string, len(skill.Variables))
+
+	for _, variable := range skill.Variables {
+		declared[variable.Name] = true
+
+		value, fromPreset := preset[variable.Name]
+		if !fromPreset {
+			value = existing[variable.Name]
+		}
+		if value == "" {
+			value = variable.Default
+		}
+
+		if value == "" && variable.IsRequired() {
+			fmt.Printf("技能 %s 需要设置变量:\n", skill.ID)
+			prompted, err := promptVariables(reader, []spec.Variable{variable}, nil)
+			if err != nil {
+				return nil, err
+			}
+			values[variable.Name] = prompted[variable.Name]
+			continue
+		}
+
+		value, err := validateVariableValue(variable, value)
+		if err != nil {
+			return nil, fmt.Errorf("技能 %s 的变量 %s 无效: %w", skill.ID, variable.Name, err)
+		}
+		if fromPreset && existing[variable.Name] != "" && existing[variable.Name] != value {
+			fmt.Printf("ℹ️  %s: 变量 %s 由 %q 改为组合预设值 %q\n", skill.ID, variable.Name, existing[variable.Name], value)
+		}
+		values[variable.Name] = value
+	}
+
+	for name := range preset {
+		if !declared[name] {
+			fmt.Printf("⚠️  技能 %s 没有声明变量 %s，已忽略\n", skill.ID, name)
+		}
+	}
+
+	return values, nil
+}
+
+func runBundleCreate(name string, skillIDs []string) error {
+	if !isValidSkillName(name) {
+		return fmt.Errorf("技能组合名称 '%s' 格式无效。应使用小写字母、数字和连字符，例如：go-backend", name)
+	}
+
+	bundles, err := engine.LoadBundleFile()
+	if err != nil {
+		return err
+	}
+	if _, exists := bundles.Get(name); exists && !bundleForce {
+		return fmt.Errorf("技能组合 '%s' 已存在，使用 --force 覆盖", name)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	projectSkills, err := stateManager.GetProjectSkills(cwd)
+	if err != nil {
+		return err
+	}
+
+	if len(skillIDs) == 0 {
+		for id := range projectSkills {
+			skillIDs = append(skillIDs, id)
+		}
+		sort.Strings(skillIDs)
+		if len(skillIDs) == 0 {
+			return fmt.Errorf("当前项目没有启用任何技能，请指定技能ID")
+		}
+	}
+
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+
+	bundle := spec.Bundle{Description: bundleDescription}
+	for _, id := range skillIDs {
+		if !manager.SkillExists(id) {
+			return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", id)
+		}
+		item := spec.BundleSkill{ID: id}
+		if vars := projectSkills[id].Variables; len(vars) > 0 {
+			item.Variables = make(map[string]string, len(vars))
+			for k, v := range vars {
+				item.Variables[k] = v
+			}
+		}
+		bundle.Skills = append(bundle.Skills, item)
+	}
+
+	if err := bundles.Set(name, bundle); err != nil {
+		return fmt.Errorf("技能组合 %s 无效: %w", name, err)
+	}
+	if err := bundles.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ 技能组合 %s 已保存 (%d 个技能): %s\n", name, len(bundle.Skills), bundles.GetPath())
+	fmt.Println("如果技能仓库关联了远程仓库，使用 'skill-hub git commit' 提交并共享")
+	return nil
+}
+
+// formatBundleVariables 按名称排序格式化预设变量
+func formatBundleVariables(vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+vars[name])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// completeBundleNames 补全技能组合名称
+func completeBundleNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	bundles, err := engine.LoadBundleFile()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, name := range bundles.Names() {
+		names = append(names, name+"\t"+completionDescription(bundles.Bundles[name].Description))
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeBundleCreateArgs 第一个参数为新组合名称，之后补全技能ID
+func completeBundleCreateArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeSkillIDList(cmd, args[1:], toComplete)
+}
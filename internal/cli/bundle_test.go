@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestResolveBundleVariables(t *testing.T) {
+	skill := &spec.Skill{
+		ID: "demo",
+		Variables: []spec.Variable{
+			{Name: "LANGUAGE", Default: "zh"},
+			{Name: "PROJECT"},
+			{Name: "STYLE", Default: "quick"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		preset   map[string]string
+		existing map[string]string
+		input    string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:   "preset overrides defaults",
+			preset: map[string]string{"LANGUAGE": "en", "PROJECT": "api"},
+			want:   map[string]string{"LANGUAGE": "en", "PROJECT": "api", "STYLE": "quick"},
+		},
+		{
+			name:     "preset overrides existing values",
+			preset:   map[string]string{"PROJECT": "api"},
+			existing: map[string]string{"PROJECT": "old", "STYLE": "detailed"},
+			want:     map[string]string{"LANGUAGE": "zh", "PROJECT": "api", "STYLE": "detailed"},
+		},
+		{
+			name:  "prompt for required variable",
+			input: "web\n",
+			want:  map[string]string{"LANGUAGE": "zh", "PROJECT": "web", "STYLE": "quick"},
+		},
+		{
+			name:    "invalid preset value",
+			preset:  map[string]string{"PROJECT": "{{ X }}"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+			got, err := resolveBundleVariables(reader, skill, tt.preset, tt.existing)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveBundleVariables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/engine"
+)
+
+var (
+	changelogFrom string
+	changelogTo   string
+	changelogDiff bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog <skill-id>",
+	Short: "显示技能两个版本之间的更新日志",
+	Long: `显示技能版本 --from（不含）到 --to（含）之间的更新日志。
+
+优先使用 --to 版本的 CHANGELOG.md 中记录的说明；没有记录时比较两个版本的内容，
+列出frontmatter字段、变量、提示词正文和文件的变化。使用 --diff 时同时列出内容变化。
+
+版本可以是技能仓库中的当前版本，或 update 和 pin 安装到 ~/.skill-hub/versions 的版本。
+--to 默认为技能仓库中的当前版本，--from 默认为已安装的版本中低于 --to 的最高版本。
+
+示例:
+  skill-hub changelog git-expert
+  skill-hub changelog git-expert --from 1.0.0 --to 1.2.0
+  skill-hub changelog git-expert --from 1.0.0 --diff`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSkillIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runChangelog(args[0])
+	},
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogFrom, "from", "", "起始版本（不含），默认为已安装的上一个版本")
+	changelogCmd.Flags().StringVar(&changelogTo, "to", "", "结束版本（含），默认为技能仓库中的当前版本")
+	changelogCmd.Flags().BoolVar(&changelogDiff, "diff", false, "同时列出由内容差异推导的变化")
+	changelogCmd.RegisterFlagCompletionFunc("from", completeInstalledVersions)
+	changelogCmd.RegisterFlagCompletionFunc("to", completeInstalledVersions)
+	rootCmd.AddCommand(changelogCmd)
+}
+
+// skillChangelog 技能两个版本之间的更新日志
+type skillChangelog struct {
+	SkillID string                  `json:"skill_id"`
+	From    string                  `json:"from,omitempty"`
+	To      string                  `json:"to"`
+	Entries []engine.ChangelogEntry `json:"entries"`
+	Changes *engine.VersionChanges  `json:"changes,omitempty"`
+}
+
+func runChangelog(skillID string) error {
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	store, err := engine.NewVersionStore()
+	if err != nil {
+		return err
+	}
+
+	current := ""
+	if manager.SkillExists(skillID) {
+		if skill, err := manager.LoadSkill(skillID); err == nil {
+			current = skill.Version
+		}
+	}
+	installed := store.Versions(skillID)
+	to := changelogTo
+	if to == "" {
+		to = current
+		if to == "" && len(installed) > 0 {
+			to = installed[len(installed)-1]
+		}
+	}
+	if to == "" {
+		return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", skillID)
+	}
+	from := changelogFrom
+	if from == "" {
+		from = previousVersion(installed, to)
+	}
+
+	versionDir := func(version string) (string, error) {
+		if version == current {
+			return manager.GetSkillDir(skillID), nil
+		}
+		if store.Has(skillID, version) {
+			return store.Dir(skillID, version), nil
+		}
+		available := append([]string{}, installed...)
+		if current != "" && !store.Has(skillID, current) {
+			available = append(available, current)
+		}
+		return "", fmt.Errorf("技能 %s 的版本 %s 未安装，可用版本: %s", skillID, version, strings.Join(available, ", "))
+	}
+	toDir, err := versionDir(to)
+	if err != nil {
+		return err
+	}
+	fromDir := ""
+	if from != "" {
+		if fromDir, err = versionDir(from); err != nil {
+			return err
+		}
+	}
+
+	log, err := buildSkillChangelog(skillID, from, fromDir, to, toDir, changelogDiff)
+	if err != nil {
+		return err
+	}
+	setResult(log)
+
+	if from != "" {
+		fmt.Printf("📋 %s: %s -> %s\n", skillID, from, to)
+	} else {
+		fmt.Printf("📋 %s: %s 及之前的版本\n", skillID, to)
+	}
+	printSkillChangelog(log, "  ")
+	return nil
+}
+
+// previousVersion 返回已安装的版本中低于 version 的最高版本，没有时返回空
+func previousVersion(installed []string, version string) string {
+	target, err := engine.ParseVersion(version)
+	if err != nil {
+		return ""
+	}
+	previous := ""
+	for _, candidate := range installed {
+		if v, err := engine.ParseVersion(candidate); err == nil && v.Compare(target) < 0 {
+			previous = candidate
+		}
+	}
+	return previous
+}
+
+// buildSkillChangelog 读取 toDir 中 CHANGELOG.md 记录的 from（不含）到 to（含）之间的说明，
+// 没有记录或 withDiff 为true时比较 fromDir 和 toDir 推导内容变化。fromDir 为空时不比较
+func buildSkillChangelog(skillID, from, fromDir, to, toDir string, withDiff bool) (*skillChangelog, error) {
+	entries, err := engine.ReadChangelog(toDir)
+	if err != nil {
+		return nil, err
+	}
+	log := &skillChangelog{SkillID: skillID, From: from, To: to, Entries: engine.ChangelogBetween(entries, from, to)}
+	if log.Entries == nil {
+		log.Entries = []engine.ChangelogEntry{}
+	}
+	if fromDir != "" && (len(log.Entries) == 0 || withDiff) {
+		if log.Changes, err = engine.DiffVersions(fromDir, toDir, skillID); err != nil {
+			return nil, err
+		}
+	}
+	return log, nil
+}
+
+// printSkillChangelog 输出更新日志，每行前加 indent
+func printSkillChangelog(log *skillChangelog, indent string) {
+	for _, entry := range log.Entries {
+		heading := entry.Version
+		if entry.Date != "" {
+			heading += " (" + entry.Date + ")"
+		}
+		fmt.Printf("%s%s\n", indent, heading)
+		for _, line := range strings.Split(entry.Body, "\n") {
+			if strings.TrimSpace(line) != "" {
+				fmt.Printf("%s  %s\n", indent, line)
+			}
+		}
+	}
+
+	switch {
+	case log.Changes != nil && !log.Changes.Empty():
+		if len(log.Entries) == 0 {
+			fmt.Printf("%s（没有 %s 记录，以下由内容差异生成）\n", indent, engine.ChangelogFile)
+		} else {
+			fmt.Printf("%s内容变化:\n", indent)
+		}
+		for _, line := range log.Changes.Summary() {
+			fmt.Printf("%s  - %s\n", indent, line)
+		}
+	case len(log.Entries) == 0:
+		fmt.Printf("%s（没有 %s 记录）\n", indent, engine.ChangelogFile)
+	}
+}
+
+// printSkillChange 输出技能从 from 更新到 to 的更新日志，版本未变时只列出内容变化，每行前加 indent
+//
+// fromDir 为更新前的技能目录，为空时不比较内容，用于新技能和更新前的版本未安装时。
+func printSkillChange(id, from, fromDir, to, toDir, indent string) {
+	if from == to {
+		if fromDir == "" {
+			return
+		}
+		if changes, err := engine.DiffVersions(fromDir, toDir, id); err == nil {
+			for _, line := range changes.Summary() {
+				fmt.Printf("%s  - %s\n", indent, line)
+			}
+		}
+		return
+	}
+	log, err := buildSkillChangelog(id, from, fromDir, to, toDir, false)
+	if err != nil {
+		fmt.Printf("%s⚠️  %v\n", indent, err)
+		return
+	}
+	printSkillChangelog(log, indent)
+}
+
+// completeInstalledVersions 补全技能已安装的版本和技能仓库中的当前版本
+func completeInstalledVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var versions []string
+	if store, err := engine.NewVersionStore(); err == nil {
+		versions = store.Versions(args[0])
+	}
+	if manager, err := engine.NewSkillManager(); err == nil {
+		if skill, err := manager.LoadSkill(args[0]); err == nil {
+			for i, version := range versions {
+				if version == skill.Version {
+					versions = append(versions[:i], versions[i+1:]...)
+					break
+				}
+			}
+			versions = append(versions, skill.Version+"\t当前版本")
+		}
+	}
+	return versions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,22 @@
+package cli
+
+import "testing"
+
+func TestPreviousVersion(t *testing.T) {
+	installed := []string{"0.9.0", "1.0.0", "1.1.0", "2.0.0"}
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.1.0", "1.0.0"},
+		{"1.5.0", "1.1.0"},
+		{"3.0.0", "2.0.0"},
+		{"0.9.0", ""},
+		{"invalid", ""},
+	}
+	for _, tt := range tests {
+		if got := previousVersion(installed, tt.version); got != tt.want {
+			t.Errorf("previousVersion(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
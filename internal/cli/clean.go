@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/adapter"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanDryRun bool
+	cleanYes    bool
+	cleanGlobal bool
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "清理目标文件中残留的技能块",
+	Long: `扫描 .cursorrules、.clauderc 等目标文件中的 SKILL-HUB 技能块，找出残留的技能：
+未在当前项目中启用的技能（例如技能改名或未通过 remove 移除），以及技能仓库中已不存在的技能。
+列出后确认清理，只删除这些技能块，文件中的其他内容保持不变。
+
+使用 --dry-run 只列出残留的技能块，--yes 不询问直接清理。
+使用 --global 清理用户级配置（~/.cursor、~/.claude）中未在全局作用域启用的技能块。
+OpenCode 和脚本型技能的目录可能包含用户自己的技能，不在清理范围内。`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runClean()
+	},
+}
+
+func init() {
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "只列出残留的技能块，不实际清理")
+	cleanCmd.Flags().BoolVarP(&cleanYes, "yes", "y", false, "不询问，直接清理")
+	cleanCmd.Flags().BoolVar(&cleanGlobal, "global", false, "清理用户级配置中的残留技能块")
+	addProjectFlag(cleanCmd)
+	rootCmd.AddCommand(cleanCmd)
+}
+
+// 技能块残留的原因
+const (
+	orphanNotEnabled = "not_enabled" // 未在项目中启用
+	orphanNotInRepo  = "not_in_repo" // 技能仓库中不存在
+)
+
+// orphanBlock 目标文件中残留的技能块
+type orphanBlock struct {
+	SkillID string `json:"skill_id"`
+	Target  string `json:"target"`
+	Reason  string `json:"reason"`
+}
+
+// findOrphanBlocks 按目标找出残留的技能块：未启用的技能，或已启用但技能仓库中不存在的技能
+//
+// listed 为每个目标文件中的技能ID，结果按目标和技能ID排序。
+func findOrphanBlocks(listed map[string][]string, enabled map[string]spec.SkillVars, exists func(string) bool) []orphanBlock {
+	var orphans []orphanBlock
+	for target, skillIDs := range listed {
+		for _, skillID := range skillIDs {
+			_, isEnabled := enabled[skillID]
+			switch {
+			case !isEnabled:
+				orphans = append(orphans, orphanBlock{SkillID: skillID, Target: target, Reason: orphanNotEnabled})
+			case !exists(skillID):
+				orphans = append(orphans, orphanBlock{SkillID: skillID, Target: target, Reason: orphanNotInRepo})
+			}
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Target != orphans[j].Target {
+			return orphans[i].Target < orphans[j].Target
+		}
+		return orphans[i].SkillID < orphans[j].SkillID
+	})
+	return orphans
+}
+
+func runClean() error {
+	stateMgr, cwd, err := scopeState(cleanGlobal)
+	if err != nil {
+		return err
+	}
+	cleanMode := "project"
+	if cleanGlobal {
+		cleanMode = "global"
+	}
+	enabled, err := stateMgr.GetProjectSkills(cwd)
+	if err != nil {
+		return err
+	}
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+
+	// 只扫描以技能块写入同一个文件的目标工具
+	adapters := make(map[string]adapter.Adapter)
+	listed := make(map[string][]string)
+	for _, target := range []string{spec.TargetCursor, spec.TargetClaudeCode} {
+		for _, adpt := range selectAdapters(target, cleanMode) {
+			if !adpt.Supports() {
+				continue
+			}
+			skillIDs, err := adpt.List()
+			if err != nil {
+				fmt.Printf("⚠️  读取 %s 的目标文件失败: %v\n", getAdapterName(adpt), err)
+				continue
+			}
+			adapters[target] = adpt
+			listed[target] = skillIDs
+		}
+	}
+
+	orphans := findOrphanBlocks(listed, enabled, skillManager.SkillExists)
+	if len(orphans) == 0 {
+		fmt.Println("✓ 目标文件中没有残留的技能块")
+		setResult(map[string][]orphanBlock{"removed": {}})
+		return nil
+	}
+
+	fmt.Println("目标文件中残留的技能块:")
+	for _, orphan := range orphans {
+		reason := "未在当前项目中启用"
+		if cleanGlobal {
+			reason = "未在全局作用域启用"
+		}
+		if orphan.Reason == orphanNotInRepo {
+			reason = "技能仓库中不存在"
+		}
+		fmt.Printf("  - %-20s %-12s %s\n", orphan.SkillID, orphan.Target, reason)
+	}
+	if cleanDryRun {
+		fmt.Println("\nℹ️  预览模式，未清理任何技能块")
+		setResult(map[string][]orphanBlock{"orphans": orphans})
+		return nil
+	}
+
+	if !cleanYes {
+		if !canPrompt() {
+			return fmt.Errorf("清理残留的技能块需要确认，使用 --yes 直接清理")
+		}
+		fmt.Printf("\n是否清理这 %d 个技能块？ [y/N]: ", len(orphans))
+		response, _ := newInputReader().ReadString('\n')
+		response = strings.ToLower(strings.TrimSpace(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("❌ 操作已取消")
+			return nil
+		}
+	}
+
+	removed := []orphanBlock{}
+	prune := false
+	for _, orphan := range orphans {
+		check, err := removeAndVerify(adapters[orphan.Target], orphan.SkillID)
+		if err == nil && check.Error != "" {
+			err = fmt.Errorf("校验失败: %s", check.Error)
+			if check.Restored {
+				err = fmt.Errorf("%w，已从备份恢复目标文件", err)
+			}
+		}
+		if err != nil {
+			fmt.Printf("❌ 从 %s 清理技能 %s 失败: %v\n", orphan.Target, orphan.SkillID, err)
+			continue
+		}
+		fmt.Printf("✓ 已从 %s 清理技能 %s\n", orphan.Target, orphan.SkillID)
+		removed = append(removed, orphan)
+		prune = prune || orphan.Reason == orphanNotInRepo
+		recordAudit(state.AuditEntry{
+			Operation: state.OpRemove,
+			Project:   cwd,
+			SkillID:   orphan.SkillID,
+			Adapter:   orphan.Target,
+		})
+	}
+	fmt.Printf("\n✅ 已清理 %d 个残留的技能块\n", len(removed))
+	if prune {
+		fmt.Println("技能仓库中已不存在的技能仍记录在项目状态中，使用 'skill-hub state prune' 清理")
+	}
+	setResult(map[string][]orphanBlock{"removed": removed})
+	if len(removed) < len(orphans) {
+		return fmt.Errorf("%d 个技能块清理失败", len(orphans)-len(removed))
+	}
+	return nil
+}
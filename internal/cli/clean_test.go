@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestFindOrphanBlocks(t *testing.T) {
+	listed := map[string][]string{
+		spec.TargetCursor:     {"kept", "renamed", "deleted"},
+		spec.TargetClaudeCode: {"kept", "old"},
+	}
+	enabled := map[string]spec.SkillVars{"kept": {}, "deleted": {}}
+	exists := func(id string) bool { return id != "deleted" }
+
+	got := findOrphanBlocks(listed, enabled, exists)
+	want := []orphanBlock{
+		{SkillID: "old", Target: spec.TargetClaudeCode, Reason: orphanNotEnabled},
+		{SkillID: "deleted", Target: spec.TargetCursor, Reason: orphanNotInRepo},
+		{SkillID: "renamed", Target: spec.TargetCursor, Reason: orphanNotEnabled},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findOrphanBlocks() = %+v, want %+v", got, want)
+	}
+
+	if got := findOrphanBlocks(map[string][]string{spec.TargetCursor: {"kept"}}, enabled, exists); len(got) != 0 {
+		t.Errorf("findOrphanBlocks() = %+v, want none", got)
+	}
+}
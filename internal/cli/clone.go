@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"skill-hub/internal/config"
+	"skill-hub/internal/engine"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// clonedSkillVersion 克隆技能的初始版本
+const clonedSkillVersion = "1.0.0"
+
+var (
+	cloneDescription string
+	cloneNoEdit      bool
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone <skill-id> <new-id>",
+	Short: "基于已有技能派生新技能",
+	Long: `复制技能仓库中的已有技能为新技能，便于在社区技能基础上派生组织内的变体。
+
+克隆操作会：
+1. 复制技能目录（SKILL.md 及资源文件）
+2. 将技能名称改为新ID，版本重置为 ` + clonedSkillVersion + `
+3. 在 metadata.forked_from 中记录来源技能及版本
+4. 使用 $VISUAL 或 $EDITOR 打开新技能的 SKILL.md 进行编辑
+
+使用 --no-edit 跳过编辑器。`,
+	Aliases:           []string{"fork"},
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeSkillIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runClone(args[0], args[1])
+	},
+}
+
+func init() {
+	cloneCmd.Flags().StringVar(&cloneDescription, "description", "", "新技能的描述 (默认沿用原技能描述)")
+	cloneCmd.Flags().BoolVar(&cloneNoEdit, "no-edit", false, "不打开编辑器")
+}
+
+func runClone(sourceID, newID string) error {
+	if spec.ValidateSkillID(newID) != nil {
+		return fmt.Errorf("技能ID '%s' 格式无效。应使用小写字母、数字和连字符，可带命名空间，例如：my-project-skill 或 acme/my-project-skill", newID)
+	}
+
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	if !manager.SkillExists(sourceID) {
+		return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", sourceID)
+	}
+	if manager.SkillExists(newID) {
+		return fmt.Errorf("技能 '%s' 已存在", newID)
+	}
+
+	source, err := manager.LoadSkill(sourceID)
+	if err != nil {
+		return fmt.Errorf("加载技能失败: %w", err)
+	}
+
+	skillsDir, err := engine.GetSkillsDir()
+	if err != nil {
+		return fmt.Errorf("获取技能目录失败: %w", err)
+	}
+	srcDir := manager.GetSkillDir(sourceID)
+	dstDir := filepath.Join(skillsDir, newID)
+
+	if err := copyDir(srcDir, dstDir); err != nil {
+		os.RemoveAll(dstDir)
+		return fmt.Errorf("复制技能失败: %w", err)
+	}
+
+	mdPath := filepath.Join(dstDir, "SKILL.md")
+	data, err := os.ReadFile(mdPath)
+	if err != nil {
+		os.RemoveAll(dstDir)
+		return fmt.Errorf("读取SKILL.md失败: %w", err)
+	}
+
+	origin := sourceID
+	if source.Version != "" {
+		origin += "@" + source.Version
+	}
+	content, err := rewriteClonedSkill(string(data), newID, cloneDescription, origin)
+	if err != nil {
+		os.RemoveAll(dstDir)
+		return err
+	}
+	if err := os.WriteFile(mdPath, []byte(content), 0644); err != nil {
+		os.RemoveAll(dstDir)
+		return fmt.Errorf("写入SKILL.md失败: %w", err)
+	}
+
+	fmt.Printf("✓ 已将技能 %s 克隆为 %s (版本 %s)\n", sourceID, newID, clonedSkillVersion)
+
+	if !cloneNoEdit {
+		if err := openInEditor(mdPath); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			fmt.Printf("请手动编辑: %s\n", mdPath)
+		}
+	}
+
+	repoDir, err := config.GetRepoPath()
+	if err != nil {
+		return err
+	}
+	if err := refreshSkillRegistry(repoDir); err != nil {
+		fmt.Printf("⚠️  刷新技能索引失败: %v\n", err)
+	}
+
+	fmt.Printf("\n✅ 技能 %s 已创建: %s\n", newID, dstDir)
+	fmt.Printf("使用 'skill-hub use %s' 在项目中启用技能\n", newID)
+	return nil
+}
+
+// rewriteClonedSkill 更新克隆技能的frontmatter：名称、版本、来源以及可选的描述
+func rewriteClonedSkill(content, newID, description, origin string) (string, error) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", fmt.Errorf("无效的SKILL.md格式: 缺少frontmatter")
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return "", fmt.Errorf("无效的SKILL.md格式: frontmatter没有正确结束")
+	}
+	frontmatter := content[4 : 4+end+1]
+	body := content[4+end+len("\n---"):]
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(frontmatter), &doc); err != nil {
+		return "", fmt.Errorf("解析frontmatter失败: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("无效的SKILL.md格式: frontmatter不是映射")
+	}
+	mapping := doc.Content[0]
+
+	// name 与技能目录名一致，命名空间只体现在技能ID中
+	_, name := spec.SplitSkillID(newID)
+	setYAMLMapValue(mapping, "name", name)
+	if findYAMLMapValue(mapping, "id") != nil {
+		setYAMLMapValue(mapping, "id", newID)
+	}
+	if description != "" {
+		setYAMLMapValue(mapping, "description", description)
+	}
+	// 根级别的旧式version字段与metadata中的版本保持一致
+	if findYAMLMapValue(mapping, "version") != nil {
+		setYAMLMapValue(mapping, "version", clonedSkillVersion)
+	}
+
+	metadata := findYAMLMapValue(mapping, "metadata")
+	if metadata == nil || metadata.Kind != yaml.MappingNode {
+		metadata = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setYAMLMapNode(mapping, "metadata", metadata)
+	}
+	setYAMLMapValue(metadata, "version", clonedSkillVersion)
+	setYAMLMapValue(metadata, "forked_from", origin)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return "", fmt.Errorf("序列化frontmatter失败: %w", err)
+	}
+	encoder.Close()
+
+	return "---\n" + buf.String() + "---" + body, nil
+}
+
+// findYAMLMapValue 查找映射节点中指定键的值节点
+func findYAMLMapValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setYAMLMapValue 设置映射节点中指定键的字符串值，键不存在时追加
+func setYAMLMapValue(mapping *yaml.Node, key, value string) {
+	valueNode := &yaml.Node{}
+	valueNode.SetString(value)
+	setYAMLMapNode(mapping, key, valueNode)
+}
+
+// setYAMLMapNode 设置映射节点中指定键的值节点，键不存在时追加
+func setYAMLMapNode(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			// 保留原值节点上的注释
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	keyNode := &yaml.Node{}
+	keyNode.SetString(key)
+	mapping.Content = append(mapping.Content, keyNode, value)
+}
+
+// openInEditor 使用 $VISUAL 或 $EDITOR 打开文件
+func openInEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		return fmt.Errorf("未设置 $VISUAL 或 $EDITOR，跳过编辑")
+	}
+
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("运行编辑器失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteClonedSkill(t *testing.T) {
+	content := `---
+name: git-expert # 技能名称
+description: Git helper
+compatibility: cursor
+metadata:
+  version: 2.3.1
+  author: community
+---
+# Git Expert
+
+Use {{.BRANCH}}.
+`
+
+	got, err := rewriteClonedSkill(content, "acme-git", "", "git-expert@2.3.1")
+	if err != nil {
+		t.Fatalf("rewriteClonedSkill() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"name: acme-git # 技能名称\n",
+		"description: Git helper\n",
+		"  version: 1.0.0\n",
+		"  author: community\n",
+		"  forked_from: git-expert@2.3.1\n",
+		"---\n# Git Expert\n\nUse {{.BRANCH}}.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("result missing %q:\n%s", want, got)
+		}
+	}
+
+	t.Run("Description and missing metadata", func(t *testing.T) {
+		got, err := rewriteClonedSkill("---\nname: a\ndescription: old\n---\nbody\n", "b", "new desc", "a")
+		if err != nil {
+			t.Fatalf("rewriteClonedSkill() error = %v", err)
+		}
+		for _, want := range []string{"name: b\n", "description: new desc\n", "metadata:\n  version: 1.0.0\n  forked_from: a\n"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("result missing %q:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("Invalid frontmatter", func(t *testing.T) {
+		if _, err := rewriteClonedSkill("# no frontmatter\n", "b", "", "a"); err == nil {
+			t.Error("rewriteClonedSkill() expected error")
+		}
+	})
+}
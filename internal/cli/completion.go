@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var completionNoDesc bool
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "生成shell自动补全脚本",
+	Long: `为指定的shell生成自动补全脚本，支持补全命令、参数、技能ID和目标名称。
+
+Bash:
+  source <(skill-hub completion bash)
+  # 永久生效 (Linux):
+  skill-hub completion bash > /etc/bash_completion.d/skill-hub
+  # 永久生效 (macOS):
+  skill-hub completion bash > $(brew --prefix)/etc/bash_completion.d/skill-hub
+
+Zsh:
+  # 如未启用补全，先执行:
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+  skill-hub completion zsh > "${fpath[1]}/_skill-hub"
+
+Fish:
+  skill-hub completion fish > ~/.config/fish/completions/skill-hub.fish
+
+PowerShell:
+  skill-hub completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCompletion(cmd.Root(), args[0])
+	},
+}
+
+func init() {
+	completionCmd.Flags().BoolVar(&completionNoDesc, "no-descriptions", false, "不在补全中显示描述")
+}
+
+func runCompletion(root *cobra.Command, shell string) error {
+	// 补全脚本不受 --quiet 影响
+	out := stdout
+	switch shell {
+	case "bash":
+		return root.GenBashCompletionV2(out, !completionNoDesc)
+	case "zsh":
+		if completionNoDesc {
+			return root.GenZshCompletionNoDesc(out)
+		}
+		return root.GenZshCompletion(out)
+	case "fish":
+		return root.GenFishCompletion(out, !completionNoDesc)
+	case "powershell":
+		if completionNoDesc {
+			return root.GenPowerShellCompletion(out)
+		}
+		return root.GenPowerShellCompletionWithDesc(out)
+	}
+	return fmt.Errorf("不支持的shell: %s，可用选项: bash, zsh, fish, powershell", shell)
+}
+
+// targetCompletions 目标工具的补全候选
+var targetCompletions = []string{
+	spec.TargetCursor + "\tCursor (.cursorrules)",
+	spec.TargetClaudeCode + "\tClaude Code",
+	spec.TargetOpenCode + "\tOpenCode",
+	spec.TargetShell + "\t脚本型技能",
+	spec.TargetAll + "\t所有目标",
+}
+
+// autoTargetCompletions 支持自动检测的命令的目标补全候选
+var autoTargetCompletions = append(append([]string{}, targetCompletions...), "auto\t自动检测目标工具")
+
+// modeCompletions 配置模式的补全候选
+var modeCompletions = []string{
+	"project\t项目级配置",
+	"global\t全局配置",
+}
+
+// channelCompletions 发布通道的补全候选
+var channelCompletions = []string{
+	spec.ChannelStable + "\t正式版本",
+	spec.ChannelBeta + "\t预览版本（同时接受正式版本）",
+}
+
+// fixedCompletions 返回补全固定候选值的函数
+func fixedCompletions(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeSkillIDs 补全技能仓库中的技能ID（仅第一个参数）
+func completeSkillIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return repoSkillCompletions(nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSkillIDList 补全任意数量的技能ID，跳过已输入的技能
+func completeSkillIDList(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return repoSkillCompletions(args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSkillIDFlag 补全取值为技能ID的标志
+func completeSkillIDFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return repoSkillCompletions(nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectSkillIDs 补全当前项目已启用的技能ID（仅第一个参数）
+func completeProjectSkillIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return projectSkillCompletions(nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectSkillIDList 补全任意数量的当前项目已启用的技能ID，跳过已输入的技能
+func completeProjectSkillIDList(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return projectSkillCompletions(args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// projectSkillCompletions 返回当前项目已启用的技能ID及版本，跳过 exclude 中的技能
+func projectSkillCompletions(exclude []string) []string {
+	if enterProject() != nil {
+		return nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return nil
+	}
+	skills, err := stateMgr.GetProjectSkills(cwd)
+	if err != nil {
+		return nil
+	}
+
+	skip := make(map[string]bool, len(exclude))
+	for _, id := range exclude {
+		skip[id] = true
+	}
+	var ids []string
+	for id, skillVars := range skills {
+		if !skip[id] {
+			ids = append(ids, id+"\t"+skillVars.Version)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// completePinnedSkillIDs 补全当前项目锁文件中已固定的技能ID
+func completePinnedSkillIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	lock, err := state.LoadLockFile(cwd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for _, id := range lock.SkillIDs() {
+		ids = append(ids, id+"\t"+lock.Skills[id].Version)
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfileNames 补全当前项目的变量配置名称
+func completeProfileNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if enterProject() != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	projectState, err := stateMgr.LoadProjectState(cwd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return state.ProfileNames(projectState), cobra.ShellCompDirectiveNoFileComp
+}
+
+// repoSkillCompletions 返回技能仓库中的技能ID及描述，exclude中的技能不返回
+func repoSkillCompletions(exclude []string) []string {
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return nil
+	}
+	skills, err := manager.LoadAllSkills()
+	if err != nil {
+		return nil
+	}
+
+	skip := make(map[string]bool, len(exclude))
+	for _, id := range exclude {
+		skip[id] = true
+	}
+
+	var completions []string
+	for _, skill := range skills {
+		if skip[skill.ID] {
+			continue
+		}
+		completions = append(completions, skill.ID+"\t"+completionDescription(skill.Description))
+	}
+	sort.Strings(completions)
+	return completions
+}
+
+// completionDescription 将描述压缩为单行短文本
+func completionDescription(desc string) string {
+	desc = strings.Join(strings.Fields(desc), " ")
+	runes := []rune(desc)
+	if len(runes) > 50 {
+		return string(runes[:47]) + "..."
+	}
+	return desc
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRunCompletionUnsupportedShell(t *testing.T) {
+	err := runCompletion(rootCmd, "tcsh")
+	if err == nil || !strings.Contains(err.Error(), "tcsh") {
+		t.Fatalf("Expected unsupported shell error, got %v", err)
+	}
+}
+
+func TestCompletionDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		desc string
+		want string
+	}{
+		{"short", "Git 专家", "Git 专家"},
+		{"multiline", "第一行\n  第二行", "第一行 第二行"},
+		{"long", strings.Repeat("a", 60), strings.Repeat("a", 47) + "..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completionDescription(tt.desc); got != tt.want {
+				t.Errorf("completionDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetFlagsHaveCompletion(t *testing.T) {
+	for _, cmd := range rootCmd.Commands() {
+		for _, name := range []string{"target", "mode"} {
+			if cmd.Flags().Lookup(name) == nil {
+				continue
+			}
+			if _, ok := cmd.GetFlagCompletionFunc(name); !ok {
+				t.Errorf("%s --%s has no completion function", cmd.Name(), name)
+			}
+		}
+	}
+}
+
+func TestFixedCompletions(t *testing.T) {
+	values, directive := fixedCompletions(modeCompletions...)(nil, nil, "")
+	if len(values) != len(modeCompletions) {
+		t.Errorf("Expected %d values, got %d", len(modeCompletions), len(values))
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected NoFileComp directive, got %v", directive)
+	}
+}
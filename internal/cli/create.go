@@ -1,13 +1,14 @@
 package cli
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"skill-hub/pkg/spec"
+
 	"github.com/spf13/cobra"
 )
 
@@ -36,12 +37,14 @@ func init() {
 	createCmd.Flags().StringVar(&createDescription, "description", "", "技能描述")
 	createCmd.Flags().StringVar(&createTarget, "target", "all", "目标工具: cursor, claude_code, open_code, all")
 	createCmd.Flags().StringVar(&createOutputDir, "output-dir", ".", "输出目录")
+
+	createCmd.RegisterFlagCompletionFunc("target", fixedCompletions(targetCompletions...))
 }
 
 func runCreate(skillName string) error {
 	// 验证技能名称格式
-	if !isValidSkillName(skillName) {
-		return fmt.Errorf("技能名称 '%s' 格式无效。应使用小写字母、数字和连字符，例如：my-project-skill", skillName)
+	if spec.ValidateSkillID(skillName) != nil {
+		return fmt.Errorf("技能名称 '%s' 格式无效。应使用小写字母、数字和连字符，可带命名空间，例如：my-project-skill 或 acme/my-project-skill", skillName)
 	}
 
 	// 获取当前工作目录
@@ -81,7 +84,7 @@ func runCreate(skillName string) error {
 		fmt.Printf("⚠️  文件已存在: %s\n", skillFilePath)
 		fmt.Print("是否覆盖？ [y/N]: ")
 
-		reader := bufio.NewReader(os.Stdin)
+		reader := newInputReader()
 		response, _ := reader.ReadString('\n')
 		response = strings.TrimSpace(response)
 
@@ -95,7 +98,7 @@ func runCreate(skillName string) error {
 	description := createDescription
 	if description == "" {
 		fmt.Printf("请输入技能描述 (按Enter跳过): ")
-		reader := bufio.NewReader(os.Stdin)
+		reader := newInputReader()
 		input, _ := reader.ReadString('\n')
 		description = strings.TrimSpace(input)
 	}
@@ -112,7 +115,9 @@ func runCreate(skillName string) error {
 	}
 
 	// 生成技能内容
-	content, err := generateSkillContent(skillName, description, target)
+	// frontmatter中的名称与技能目录名一致，不含命名空间
+	_, name := spec.SplitSkillID(skillName)
+	content, err := generateSkillContent(name, description, target)
 	if err != nil {
 		return fmt.Errorf("生成技能内容失败: %w", err)
 	}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/diff"
+)
+
+// diffWords --word-diff 参数：按词显示差异中修改的行
+var diffWords bool
+
+// addWordDiffFlag 为输出差异的命令添加 --word-diff 参数
+func addWordDiffFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&diffWords, "word-diff", false, "按词显示差异中修改的行（[-删除-]{+新增+}）")
+}
+
+// colorOutput 检查标准输出是否使用颜色：只在终端中使用，设置了 NO_COLOR 环境变量时不使用
+func colorOutput() bool {
+	return !outputJSON && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+}
+
+// printDiff 按 --word-diff 和终端颜色格式化统一差异文本后输出，每行前加 indent
+func printDiff(unified, indent string) {
+	text := diff.Format(unified, diff.FormatOptions{Color: colorOutput(), Words: diffWords})
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		fmt.Printf("%s%s\n", indent, line)
+	}
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"skill-hub/internal/config"
+	"skill-hub/internal/state"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorReplay   bool
+	doctorRollback bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "检查配置和状态文件，恢复中断的状态更新",
+	Long: `检查配置文件、技能仓库和状态文件是否可用。
+
+use、remove、set-target 等命令修改状态时先写入事务日志，全部文件写入后再删除日志。
+命令中途被中断时日志会保留，此后修改状态的命令会拒绝执行，直到恢复：
+  skill-hub doctor --replay     # 完成中断的更新
+  skill-hub doctor --rollback   # 撤销中断的更新，恢复到更新前的状态`,
+	Args: cobra.NoArgs,
+	// 发现问题是检查结果而不是用法错误
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDoctor()
+	},
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorReplay, "replay", false, "重新执行中断的状态更新")
+	doctorCmd.Flags().BoolVar(&doctorRollback, "rollback", false, "撤销中断的状态更新")
+}
+
+func runDoctor() error {
+	if doctorReplay && doctorRollback {
+		return fmt.Errorf("--replay 和 --rollback 不能同时使用")
+	}
+
+	fmt.Println("🔍 检查 skill-hub 环境...")
+
+	if _, err := config.GetConfig(); err != nil {
+		fmt.Printf("❌ 配置文件: %v\n", err)
+		return fmt.Errorf("配置不可用，请运行 'skill-hub init'")
+	}
+	fmt.Println("✓ 配置文件")
+
+	repoPath, err := config.GetRepoPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(repoPath); err != nil {
+		fmt.Printf("❌ 技能仓库: %s 不存在\n", repoPath)
+		return fmt.Errorf("技能仓库不可用，请运行 'skill-hub init'")
+	}
+	fmt.Printf("✓ 技能仓库: %s\n", repoPath)
+
+	projectMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	globalMgr, err := state.NewGlobalStateManager()
+	if err != nil {
+		return err
+	}
+
+	problems := 0
+	for _, mgr := range []*state.StateManager{projectMgr, globalMgr} {
+		problems += checkStateManager(mgr)
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("发现 %d 个问题", problems)
+	}
+	fmt.Println("\n✅ 未发现问题")
+	return nil
+}
+
+// checkStateManager 检查一个状态文件及其事务日志，返回未解决的问题数
+func checkStateManager(mgr *state.StateManager) int {
+	problems := 0
+	statePath := mgr.GetStatePath()
+
+	tx, err := mgr.PendingTransaction()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Printf("   确认没有其他实例在运行后，可以删除 %s\n", mgr.JournalPath())
+		return 1
+	}
+	if tx != nil {
+		fmt.Printf("⚠️  状态更新未完成: 项目 %s（开始于 %s，进程 %d）\n",
+			tx.Project, tx.StartedAt.Format("2006-01-02 15:04:05"), tx.PID)
+		for _, file := range tx.Files {
+			fmt.Printf("   - %s\n", file.Path)
+		}
+		switch {
+		case doctorReplay:
+			if err := mgr.ReplayTransaction(); err != nil {
+				fmt.Printf("❌ 重新执行失败: %v\n", err)
+				problems++
+			} else {
+				fmt.Println("✓ 已完成中断的状态更新")
+			}
+		case doctorRollback:
+			if err := mgr.RollbackTransaction(); err != nil {
+				fmt.Printf("❌ 撤销失败: %v\n", err)
+				problems++
+			} else {
+				fmt.Println("✓ 已撤销中断的状态更新")
+			}
+		default:
+			fmt.Println("   使用 'skill-hub doctor --replay' 完成更新，或 'skill-hub doctor --rollback' 撤销")
+			problems++
+		}
+	}
+
+	if _, err := os.Stat(mgr.LockPath()); err == nil {
+		fmt.Printf("ℹ️  锁文件存在: %s（另一个实例正在运行，或上次运行异常退出后遗留）\n", mgr.LockPath())
+	}
+
+	if _, err := os.Stat(statePath); os.IsNotExist(err) {
+		return problems
+	}
+	paths, err := mgr.ProjectPaths()
+	if err != nil {
+		fmt.Printf("❌ 状态文件: %v\n", err)
+		return problems + 1
+	}
+	fmt.Printf("✓ 状态文件: %s（%d 个项目）\n", statePath, len(paths))
+	return problems
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/tool"
+
+	"github.com/spf13/cobra"
+)
+
+var execInput string
+
+var execCmd = &cobra.Command{
+	Use:   "exec <skill-id>",
+	Short: "运行 tool 模式的技能",
+	Long: `运行 claude.mode 为 tool 的技能。
+
+按技能声明的 claude.runtime 启动 claude.entrypoint，工作目录为当前目录。
+输入为JSON，先按 claude.tool_spec.input_schema 校验，再写入工具的标准输入；
+工具的输出实时转发到终端，--json 模式下作为结果的 output 字段返回。
+工具以非零状态退出时，skill-hub 使用相同的退出码退出。
+
+--input 支持三种形式:
+  --input '{"path": "main.go"}'   JSON字符串
+  --input @input.json             从文件读取
+  --input -                       从标准输入读取`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSkillIDs,
+	// 工具执行失败不是用法错误
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runExec(args[0])
+	},
+}
+
+func init() {
+	execCmd.Flags().StringVar(&execInput, "input", "{}", "工具输入 (JSON字符串、@文件 或 - 表示标准输入)")
+}
+
+// execResult --json 模式下 exec 命令的结果
+type execResult struct {
+	SkillID  string `json:"skill_id"`
+	ExitCode int    `json:"exit_code"`
+	Output   string `json:"output"`
+}
+
+func runExec(skillID string) error {
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	skill, err := manager.LoadSkill(skillID)
+	if err != nil {
+		return err
+	}
+	if skill.Claude == nil || skill.Claude.Mode != tool.ModeTool {
+		return fmt.Errorf("技能 %s 不是 tool 模式，无法执行", skillID)
+	}
+
+	raw, err := readExecInput(execInput)
+	if err != nil {
+		return err
+	}
+	var input interface{}
+	if err := json.Unmarshal(raw, &input); err != nil {
+		return fmt.Errorf("解析输入JSON失败: %w", err)
+	}
+	if toolSpec := skill.Claude.ToolSpec; toolSpec != nil {
+		if err := tool.ValidateInput(toolSpec.InputSchema, input); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	skillDir := manager.GetSkillDir(skillID)
+	cmd, err := tool.Command(ctx, skill.Claude, skillDir)
+	if err != nil {
+		return fmt.Errorf("技能 %s 无法执行: %w", skillID, err)
+	}
+	debugf("执行: %s", strings.Join(cmd.Args, " "))
+
+	cmd.Stdin = bytes.NewReader(raw)
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"SKILL_HUB_SKILL_ID="+skillID,
+		"SKILL_HUB_SKILL_DIR="+skillDir,
+	)
+
+	// 普通模式实时转发输出；--json 模式收集输出放入结果
+	var output bytes.Buffer
+	if outputJSON {
+		cmd.Stdout = &output
+	} else {
+		cmd.Stdout = stdout
+	}
+
+	runErr := cmd.Run()
+	result := execResult{SkillID: skillID, Output: output.String()}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(runErr, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		setResult(result)
+		return toolFailed(result.ExitCode, fmt.Errorf("技能 %s 执行失败，退出码 %d", skillID, result.ExitCode))
+	case runErr != nil:
+		return fmt.Errorf("启动技能 %s 失败: %w", skillID, runErr)
+	}
+
+	setResult(result)
+	return nil
+}
+
+// readExecInput 读取 --input 指定的输入
+func readExecInput(value string) ([]byte, error) {
+	switch {
+	case value == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("读取标准输入失败: %w", err)
+		}
+		return data, nil
+	case strings.HasPrefix(value, "@"):
+		data, err := os.ReadFile(value[1:])
+		if err != nil {
+			return nil, fmt.Errorf("读取输入文件失败: %w", err)
+		}
+		return data, nil
+	default:
+		return []byte(value), nil
+	}
+}
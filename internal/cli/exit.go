@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 进程退出码
+const (
+	ExitError          = 1 // 命令失败
+	ExitPartialFailure = 2 // apply 部分技能失败，其余技能已应用
+	ExitApplyFailed    = 3 // apply 没有应用任何技能：全部失败，或因 --strict、写入失败已回滚
+)
+
+// exitError 指定进程退出码的命令错误
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode 返回命令错误对应的进程退出码，没有指定时为 ExitError
+func ExitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitError
+}
+
+// applyFailed 将错误标记为 apply 没有应用任何技能
+func applyFailed(err error) error {
+	return &exitError{code: ExitApplyFailed, err: err}
+}
+
+// toolFailed 将错误标记为 exec 运行的工具以 code 退出，进程使用相同的退出码；
+// 工具被信号终止等没有有效退出码时为 ExitError
+func toolFailed(code int, err error) error {
+	if code <= 0 {
+		code = ExitError
+	}
+	return &exitError{code: code, err: err}
+}
+
+// applyFailures 根据失败数和成功数返回 apply 的最终错误，没有失败时返回nil
+//
+// unit 为统计的对象（如“个技能”、“个成员项目”）。
+func applyFailures(failed, succeeded int, unit string) error {
+	switch {
+	case failed == 0:
+		return nil
+	case succeeded == 0:
+		return applyFailed(fmt.Errorf("%d %s应用失败", failed, unit))
+	}
+	return &exitError{code: ExitPartialFailure, err: fmt.Errorf("%d %s应用失败，其余 %d %s已应用", failed, unit, succeeded, unit)}
+}
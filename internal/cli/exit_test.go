@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+func TestApplyFailuresExitCode(t *testing.T) {
+	tests := []struct {
+		failed, succeeded int
+		code              int
+	}{
+		{0, 3, 0},
+		{1, 2, ExitPartialFailure},
+		{3, 0, ExitApplyFailed},
+	}
+	for _, tt := range tests {
+		err := applyFailures(tt.failed, tt.succeeded, "个技能")
+		if tt.code == 0 {
+			if err != nil {
+				t.Errorf("applyFailures(%d, %d) = %v, want nil", tt.failed, tt.succeeded, err)
+			}
+			continue
+		}
+		if got := ExitCode(err); got != tt.code {
+			t.Errorf("ExitCode(applyFailures(%d, %d)) = %d, want %d", tt.failed, tt.succeeded, got, tt.code)
+		}
+	}
+
+	// 包装后的错误保留退出码，其他错误为 ExitError
+	wrapped := fmt.Errorf("apply: %w", applyFailed(errors.New("boom")))
+	if got := ExitCode(wrapped); got != ExitApplyFailed {
+		t.Errorf("ExitCode(wrapped) = %d, want %d", got, ExitApplyFailed)
+	}
+	if got := ExitCode(errors.New("boom")); got != ExitError {
+		t.Errorf("ExitCode(plain) = %d, want %d", got, ExitError)
+	}
+}
+
+func TestToolFailedExitCode(t *testing.T) {
+	var exitErr *exec.ExitError
+	if err := exec.Command("sh", "-c", "exit 7").Run(); !errors.As(err, &exitErr) {
+		t.Fatalf("Run() error = %v, want exit error", err)
+	}
+	if got := ExitCode(toolFailed(exitErr.ExitCode(), errors.New("boom"))); got != 7 {
+		t.Errorf("ExitCode(toolFailed(7)) = %d, want 7", got)
+	}
+	// 被信号终止时没有有效的退出码
+	if got := ExitCode(toolFailed(-1, errors.New("boom"))); got != ExitError {
+		t.Errorf("ExitCode(toolFailed(-1)) = %d, want %d", got, ExitError)
+	}
+}
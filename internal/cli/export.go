@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/pack"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <skill-id>",
+	Short: "将技能打包为可分发的归档",
+	Long: `将技能目录（SKILL.md 及其资源文件）打包为可移植的归档，便于他人导入。
+
+归档中包含 manifest.json 清单，记录技能版本以及每个文件的大小和SHA256哈希。
+支持的格式：
+  tar - gzip压缩的tar归档 (默认)
+  zip - zip归档
+  dir - 普通目录`,
+	Aliases:           []string{"pack"},
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSkillIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runExport(args[0])
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", pack.FormatTar, "导出格式: tar, zip, dir")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "输出路径 (默认: <skill-id>-<version>.tar.gz|.zip 或目录)")
+
+	exportCmd.RegisterFlagCompletionFunc("format", fixedCompletions(pack.FormatTar, pack.FormatZip, pack.FormatDir))
+}
+
+func runExport(skillID string) error {
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+
+	if !manager.SkillExists(skillID) {
+		return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", skillID)
+	}
+
+	skill, err := manager.LoadSkill(skillID)
+	if err != nil {
+		return fmt.Errorf("加载技能失败: %w", err)
+	}
+
+	skillsDir, err := engine.GetSkillsDir()
+	if err != nil {
+		return fmt.Errorf("获取技能目录失败: %w", err)
+	}
+	skillDir := filepath.Join(skillsDir, skillID)
+
+	fmt.Printf("正在导出技能: %s (版本 %s)\n", skillID, skill.Version)
+
+	manifest, err := pack.BuildManifest(skillDir, skillID, skill.Name, skill.Version)
+	if err != nil {
+		return err
+	}
+
+	output, err := pack.Export(skillDir, manifest, exportFormat, exportOutput)
+	if err != nil {
+		return fmt.Errorf("导出失败: %w", err)
+	}
+
+	fmt.Printf("✓ 已打包 %d 个文件\n", len(manifest.Files))
+	fmt.Printf("✅ 技能已导出到: %s\n", output)
+	return nil
+}
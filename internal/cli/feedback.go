@@ -1,19 +1,24 @@
 package cli
 
 import (
-	"bufio"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"encoding/json"
+	"skill-hub/internal/adapter"
 	"skill-hub/internal/adapter/claude"
 	"skill-hub/internal/adapter/cursor"
 	"skill-hub/internal/adapter/opencode"
 	"skill-hub/internal/config"
+	"skill-hub/internal/diff"
 	"skill-hub/internal/engine"
+	"skill-hub/internal/git"
+	"skill-hub/internal/pack"
 	"skill-hub/internal/state"
 	"skill-hub/internal/template"
 	"skill-hub/pkg/spec"
@@ -23,8 +28,14 @@ import (
 )
 
 var (
-	feedbackTarget string
-	archiveFlag    bool
+	feedbackTarget    string
+	archiveFlag       bool
+	feedbackMessage   string
+	feedbackPush      bool
+	feedbackBump      string
+	feedbackOverwrite bool
+	feedbackYes       bool
+	feedbackDryRun    bool
 )
 
 var feedbackCmd = &cobra.Command{
@@ -33,10 +44,31 @@ var feedbackCmd = &cobra.Command{
 	Long: `将项目配置文件中手动修改的技能内容反向更新到本地技能仓库。
 
 使用 --target 参数指定从哪个工具配置文件提取内容 (cursor/claude_code/open_code/all/auto)。
-默认为空，会使用状态绑定的目标或自动检测。
+默认为空，会使用状态绑定的目标或自动检测。从多个目标提取内容时（绑定了多个目标或使用 all），
+以应用时的技能内容为基础合并各目标中的修改；不同目标修改了同一处时列出冲突并停止反馈。
 
-使用 --archive 参数在反馈完成后将技能归档到正式技能仓库。`,
-	Args: cobra.ExactArgs(1),
+技能使用了模板变量时，默认将修改后内容中的项目变量值还原为 {{.变量}} 占位符再保存，
+未修改的行保留技能中原来的内容，技能仍可按其他项目的变量渲染。
+
+每次反馈都会提升技能的语义化版本号：--bump 指定提升主版本号 (major)、次版本号 (minor)
+或修订号 (patch)，未指定时在终端中询问，默认提升修订号。版本号不是有效的语义化版本时停止反馈。
+每次反馈都在技能目录的 CHANGELOG.md 中记录本次修改的说明，说明通过 --message 指定，未指定时交互输入。update 命令会显示已更新技能的说明。
+
+技能仓库中的技能比项目中应用的版本新时（例如其他项目反馈了修改），以项目应用时的内容为基础，
+将项目中的修改合并到仓库中的新版本；有冲突时列出冲突并停止，使用 --overwrite 用项目中的内容覆盖。
+
+使用 --archive 参数在反馈完成后将技能归档到正式技能仓库。
+使用 --push 参数只提交该技能的修改并推送到技能仓库的远程仓库。
+
+使用 --dry-run 预览反馈的结果而不写入：按相同的方式还原变量占位符、合并技能仓库中的更新并提升版本号，
+以统一差异格式列出技能仓库中 SKILL.md 和 CHANGELOG.md 将发生的变化，不询问是否确认，
+不修改技能仓库和项目状态，也不归档和推送。
+
+在脚本和钩子中使用 --yes 不询问：直接确认反馈，变量按方式 1 还原为占位符，版本号未指定 --bump 时提升修订号，
+未指定 --message 时使用默认说明。使用全局参数 --no-input 而未指定 --yes 时，需要确认反馈时停止并返回错误。
+使用 --json 输出反馈结果（技能ID、新旧版本号、提取内容的目标、是否合并了技能仓库中的更新、是否归档和推送）。`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectSkillIDs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runFeedback(args[0])
 	},
@@ -45,9 +77,24 @@ var feedbackCmd = &cobra.Command{
 func init() {
 	feedbackCmd.Flags().StringVar(&feedbackTarget, "target", "", "目标工具: cursor, claude_code, open_code, all, auto (为空时使用状态绑定的目标)")
 	feedbackCmd.Flags().BoolVar(&archiveFlag, "archive", false, "反馈完成后归档到技能仓库")
+	feedbackCmd.Flags().StringVarP(&feedbackMessage, "message", "m", "", "记录到 CHANGELOG.md 的修改说明")
+	feedbackCmd.Flags().BoolVar(&feedbackPush, "push", false, "提交该技能的修改并推送到远程仓库")
+	feedbackCmd.Flags().StringVar(&feedbackBump, "bump", "", "版本号提升方式: major, minor, patch (为空时在终端中询问，默认 patch)")
+	feedbackCmd.Flags().BoolVar(&feedbackOverwrite, "overwrite", false, "技能仓库中的技能比项目中的新时，用项目中的内容覆盖仓库中的更新")
+	feedbackCmd.Flags().BoolVarP(&feedbackYes, "yes", "y", false, "不询问，确认反馈并使用默认选择")
+	feedbackCmd.Flags().BoolVar(&feedbackDryRun, "dry-run", false, "预览反馈后技能仓库中 SKILL.md 和 CHANGELOG.md 的变化而不写入")
+	addWordDiffFlag(feedbackCmd)
+
+	feedbackCmd.RegisterFlagCompletionFunc("target", fixedCompletions(autoTargetCompletions...))
+	feedbackCmd.RegisterFlagCompletionFunc("bump", fixedCompletions(engine.BumpMajor, engine.BumpMinor, engine.BumpPatch))
 }
 
 func runFeedback(skillID string) error {
+	if feedbackBump != "" {
+		if _, err := (engine.Version{}).Bump(feedbackBump); err != nil {
+			return err
+		}
+	}
 	fmt.Printf("收集技能 '%s' 的反馈...\n", skillID)
 
 	// 获取当前目录
@@ -56,36 +103,32 @@ func runFeedback(skillID string) error {
 		return fmt.Errorf("获取当前目录失败: %w", err)
 	}
 
-	// 检查.agents/skills/目录下是否存在该技能
-	agentsSkillsDir := filepath.Join(cwd, ".agents", "skills", skillID)
-	if _, err := os.Stat(agentsSkillsDir); os.IsNotExist(err) {
-		return fmt.Errorf("技能 '%s' 在当前项目的 .agents/skills/ 目录中不存在", skillID)
-	}
-
-	// 检查SKILL.md文件是否存在
-	skillMdPath := filepath.Join(agentsSkillsDir, "SKILL.md")
-	if _, err := os.Stat(skillMdPath); os.IsNotExist(err) {
-		return fmt.Errorf("技能文件 SKILL.md 在当前项目的 .agents/skills/%s/ 目录中不存在", skillID)
-	}
-
-	// 从本地项目加载技能信息
-	skill, err := loadSkillFromLocalProject(cwd, skillID)
+	// 原始技能内容优先使用项目 .agents/skills/ 中的技能，只应用到 Cursor、Claude Code 等目标的项目
+	// 没有该目录，使用技能仓库中的技能
+	skillMdPath := filepath.Join(cwd, ".agents", "skills", skillID, "SKILL.md")
+	var skill *spec.Skill
 	skillNotFound := false
-	if err != nil {
-		// 检查是否是"文件不存在"错误
-		if strings.Contains(err.Error(), "读取SKILL.md失败") && strings.Contains(err.Error(), "no such file or directory") {
-			fmt.Println("🔍 本地项目中未找到技能文件，将创建新技能")
-			skillNotFound = true
-			// 创建临时技能对象
-			skill = &spec.Skill{
-				ID:          skillID,
-				Name:        skillID,
-				Description: fmt.Sprintf("从项目反馈的新技能: %s", skillID),
-				Version:     "1.0.0",
-			}
-		} else {
+	originalFromProject := true
+	if _, err := os.Stat(skillMdPath); err == nil {
+		// 从本地项目加载技能信息
+		skill, err = loadSkillFromLocalProject(cwd, skillID)
+		if err != nil {
 			return fmt.Errorf("加载本地技能失败: %w", err)
 		}
+	} else {
+		manager, err := engine.NewSkillManager()
+		if err != nil {
+			return err
+		}
+		if !manager.SkillExists(skillID) {
+			return fmt.Errorf("技能 '%s' 在当前项目的 .agents/skills/ 目录和技能仓库中都不存在", skillID)
+		}
+		if skill, err = manager.LoadSkill(skillID); err != nil {
+			return fmt.Errorf("加载技能失败: %w", err)
+		}
+		skillMdPath = filepath.Join(manager.GetSkillDir(skillID), "SKILL.md")
+		originalFromProject = false
+		fmt.Println("🔍 项目中没有 .agents/skills/ 中的技能，使用技能仓库中的内容作为原始内容")
 	}
 
 	// 初始化状态管理器（用于目标解析）
@@ -97,145 +140,113 @@ func runFeedback(skillID string) error {
 
 	// 确定目标工具
 	resolvedTarget := feedbackTarget
+	var boundTargets []string
 	if resolvedTarget == "" {
 		// 如果没有指定target，尝试从状态获取
 		projectState, err := stateManager.FindProjectByPath(cwd)
 		if err != nil {
 			return fmt.Errorf("查找项目状态失败: %w", err)
 		}
+		if projectState != nil {
+			boundTargets = projectState.BoundTargets()
+		}
 
-		if projectState == nil || projectState.PreferredTarget == "" {
+		if len(boundTargets) == 0 {
 			// 未绑定项目，使用auto
 			resolvedTarget = "auto"
 			fmt.Println("🔍 项目未绑定目标，使用自动检测模式")
 		} else {
-			resolvedTarget = spec.NormalizeTarget(projectState.PreferredTarget)
-			fmt.Printf("🔍 使用状态绑定的目标: %s\n", resolvedTarget)
+			resolvedTarget = boundTargets[0]
+			fmt.Printf("🔍 使用状态绑定的目标: %s\n", strings.Join(boundTargets, ", "))
 		}
 	} else {
 		resolvedTarget = spec.NormalizeTarget(resolvedTarget)
 		fmt.Printf("🔍 使用指定的目标: %s\n", resolvedTarget)
 	}
 
-	// 根据参数或自动检测选择适配器
-	var fileContent string
-	var adapterName string
 	var extractErr error
 
-	// 确定要尝试的适配器顺序
-	tryCursor := false
-	tryClaude := false
-	tryOpenCode := false
+	// 确定要依次尝试的目标
+	allTargets := []string{spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode}
+	var candidates []string
 
-	switch resolvedTarget {
-	case "", "auto":
+	switch {
+	case len(boundTargets) > 1:
+		// 绑定了多个目标：按绑定顺序尝试技能兼容的目标
+		candidates = feedbackCompatibleTargets(skill, boundTargets)
+		if len(candidates) == 0 {
+			return fmt.Errorf("技能 '%s' 不支持项目绑定的目标: %s", skillID, strings.Join(boundTargets, ", "))
+		}
+	case resolvedTarget == "" || resolvedTarget == "auto":
 		// 自动模式：首先尝试项目的首选目标
 		projectState, err := stateManager.FindProjectByPath(cwd)
 		if err != nil {
 			return fmt.Errorf("查找项目状态失败: %w", err)
 		}
 
-		if projectState != nil && projectState.PreferredTarget != "" {
-			// 使用项目的首选目标
-			normalizedTarget := spec.NormalizeTarget(projectState.PreferredTarget)
-			compatLower := strings.ToLower(skill.Compatibility)
-
-			if normalizedTarget == spec.TargetCursor && strings.Contains(compatLower, "cursor") {
-				tryCursor = true
-				fmt.Printf("🔍 使用项目首选目标: Cursor\n")
-			} else if normalizedTarget == spec.TargetClaudeCode && (strings.Contains(compatLower, "claude code") || strings.Contains(compatLower, "claude_code") || strings.Contains(compatLower, "claude")) {
-				tryClaude = true
-				fmt.Printf("🔍 使用项目首选目标: Claude Code\n")
-			} else if normalizedTarget == spec.TargetOpenCode && (strings.Contains(compatLower, "opencode") || strings.Contains(compatLower, "open_code")) {
-				tryOpenCode = true
-				fmt.Printf("🔍 使用项目首选目标: OpenCode\n")
-			} else {
-				// 首选目标不支持，回退到技能兼容性
-				tryCursor = strings.Contains(compatLower, "cursor")
-				tryClaude = strings.Contains(compatLower, "claude code") || strings.Contains(compatLower, "claude_code") || strings.Contains(compatLower, "claude")
-				tryOpenCode = strings.Contains(compatLower, "opencode") || strings.Contains(compatLower, "open_code")
-			}
+		if projectState != nil {
+			candidates = feedbackCompatibleTargets(skill, projectState.BoundTargets())
+		}
+		if len(candidates) > 0 {
+			fmt.Printf("🔍 使用项目首选目标: %s\n", strings.Join(candidates, ", "))
 		} else {
-			// 没有首选目标，根据技能兼容性尝试
-			compatLower := strings.ToLower(skill.Compatibility)
-			tryCursor = strings.Contains(compatLower, "cursor")
-			tryClaude = strings.Contains(compatLower, "claude code") || strings.Contains(compatLower, "claude_code") || strings.Contains(compatLower, "claude")
-			tryOpenCode = strings.Contains(compatLower, "opencode") || strings.Contains(compatLower, "open_code")
+			// 没有首选目标或首选目标不支持，根据技能兼容性尝试
+			candidates = feedbackCompatibleTargets(skill, allTargets)
 		}
-	case spec.TargetAll:
+	case resolvedTarget == spec.TargetAll:
 		// 尝试所有适配器
-		compatLower := strings.ToLower(skill.Compatibility)
-		tryCursor = strings.Contains(compatLower, "cursor")
-		tryClaude = strings.Contains(compatLower, "claude code") || strings.Contains(compatLower, "claude_code") || strings.Contains(compatLower, "claude")
-		tryOpenCode = strings.Contains(compatLower, "opencode") || strings.Contains(compatLower, "open_code")
-
-		if !tryCursor && !tryClaude && !tryOpenCode {
+		candidates = feedbackCompatibleTargets(skill, allTargets)
+		if len(candidates) == 0 {
 			return fmt.Errorf("技能 '%s' 不支持任何适配器", skillID)
 		}
-	case spec.TargetCursor:
-		tryCursor = true
-		if !strings.Contains(strings.ToLower(skill.Compatibility), "cursor") {
-			return fmt.Errorf("技能 '%s' 不支持 Cursor 适配器", skillID)
-		}
-	case spec.TargetClaudeCode:
-		tryClaude = true
-		if !(strings.Contains(strings.ToLower(skill.Compatibility), "claude code") || strings.Contains(strings.ToLower(skill.Compatibility), "claude_code") || strings.Contains(strings.ToLower(skill.Compatibility), "claude")) {
-			return fmt.Errorf("技能 '%s' 不支持 Claude Code 适配器", skillID)
-		}
-	case spec.TargetOpenCode:
-		tryOpenCode = true
-		if !(strings.Contains(strings.ToLower(skill.Compatibility), "opencode") || strings.Contains(strings.ToLower(skill.Compatibility), "open_code")) {
-			return fmt.Errorf("技能 '%s' 不支持 OpenCode 适配器", skillID)
+	case resolvedTarget == spec.TargetCursor || resolvedTarget == spec.TargetClaudeCode || resolvedTarget == spec.TargetOpenCode:
+		adpt := newFeedbackAdapter(resolvedTarget)
+		if len(feedbackCompatibleTargets(skill, []string{resolvedTarget})) == 0 {
+			name := getAdapterName(adpt)
+			if name == "Claude" {
+				name = "Claude Code"
+			}
+			return fmt.Errorf("技能 '%s' 不支持 %s 适配器", skillID, name)
 		}
+		candidates = []string{resolvedTarget}
 	default:
 		return fmt.Errorf("无效的目标: %s，可用选项: %s, %s, %s, %s, auto", resolvedTarget, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetAll)
 	}
 
-	// 尝试Cursor适配器
-	if tryCursor {
-		cursorAdapter := cursor.NewCursorAdapter()
-		fileContent, extractErr = cursorAdapter.Extract(skillID)
-		if extractErr == nil {
-			adapterName = "Cursor"
-		}
-	}
-
-	// 如果Cursor适配器失败且需要尝试Claude适配器
-	if fileContent == "" && tryClaude {
-		claudeAdapter := claude.NewClaudeAdapter()
-		fileContent, extractErr = claudeAdapter.Extract(skillID)
-		if extractErr == nil {
-			adapterName = "Claude"
+	// 依次从各目标提取内容
+	var extracts []feedbackExtract
+	for _, candidate := range candidates {
+		adpt := newFeedbackAdapter(candidate)
+		content, err := adpt.Extract(skillID)
+		if err != nil {
+			extractErr = err
+			continue
 		}
-	}
-
-	// 如果前两个适配器失败且需要尝试OpenCode适配器
-	if fileContent == "" && tryOpenCode {
-		opencodeAdapter := opencode.NewOpenCodeAdapter()
-		fileContent, extractErr = opencodeAdapter.Extract(skillID)
-		if extractErr == nil {
-			adapterName = "OpenCode"
+		if content == "" {
+			continue
 		}
+		extracts = append(extracts, feedbackExtract{Target: candidate, Name: getAdapterName(adpt), Content: content})
 	}
 
 	// 如果都没有提取到内容
-	if fileContent == "" {
-		if resolvedTarget == "auto" || resolvedTarget == "" {
+	if len(extracts) == 0 {
+		if resolvedTarget == "auto" || resolvedTarget == "" || len(boundTargets) > 1 {
 			return fmt.Errorf("无法从任何配置文件中提取技能 '%s' 的内容。请确保技能已应用到目标工具。错误: %v", skillID, extractErr)
 		} else {
 			return fmt.Errorf("无法从 %s 配置文件中提取技能 '%s' 的内容。错误: %v", resolvedTarget, skillID, extractErr)
 		}
 	}
 
-	fmt.Printf("从 %s 配置文件提取到技能内容\n", adapterName)
+	for _, extract := range extracts {
+		fmt.Printf("从 %s 配置文件提取到技能内容\n", extract.Name)
+	}
 
 	// 从本地项目获取原始技能内容
 	var originalContent []byte
 	if !skillNotFound {
-		localSkillPath := filepath.Join(cwd, ".agents", "skills", skillID, "SKILL.md")
-		originalContent, err = os.ReadFile(localSkillPath)
+		originalContent, err = os.ReadFile(skillMdPath)
 		if err != nil {
-			return fmt.Errorf("读取本地技能文件失败: %w", err)
+			return fmt.Errorf("读取技能文件失败: %w", err)
 		}
 	} else {
 		// 技能不存在，创建空的原始内容
@@ -259,10 +270,13 @@ metadata:
 
 	// 尝试获取项目变量（如果技能已启用）
 	var skillVariables map[string]string
+	var projectSkill spec.SkillVars
+	skillEnabled := false
 	if stateManager != nil {
 		skills, err := stateManager.GetProjectSkills(cwd)
 		if err == nil {
 			if skillVars, exists := skills[skillID]; exists {
+				projectSkill, skillEnabled = skillVars, true
 				skillVariables = skillVars.Variables
 				fmt.Println("🔍 使用项目变量配置")
 			} else {
@@ -281,6 +295,72 @@ metadata:
 	// 渲染原始内容（使用项目变量）
 	renderedOriginal := template.Render(string(originalContent), skillVariables)
 
+	// 合并各目标中的修改
+	var fileContent, adapterTarget, sourceName string
+	if skillNotFound {
+		// 新技能没有共同的原始内容，使用第一个目标中的内容
+		fileContent, adapterTarget, sourceName = extracts[0].Content, extracts[0].Target, extracts[0].Name
+		for _, extract := range extracts[1:] {
+			if strings.TrimSpace(extract.Content) != strings.TrimSpace(fileContent) {
+				fmt.Printf("⚠️  %s 中的技能内容与 %s 不同，只反馈 %s 中的内容\n", extract.Name, extracts[0].Name, extracts[0].Name)
+			}
+		}
+	} else {
+		merge := mergeFeedbackExtracts(renderedOriginal, extracts)
+		if len(merge.Changed) > 1 {
+			fmt.Printf("🔀 合并 %s 中的修改\n", strings.Join(merge.Changed, "、"))
+		}
+		if merge.Conflicts > 0 {
+			fmt.Printf("\n❌ %s 中的修改有 %d 处冲突:\n", strings.Join(merge.Changed, "、"), merge.Conflicts)
+			printFeedbackConflicts(merge.Content)
+			return fmt.Errorf("合并各目标中的修改失败，请统一各目标中的修改，或使用 --target 指定要反馈的目标")
+		}
+		fileContent = merge.Content
+		adapterTarget, sourceName = strings.Join(merge.Targets, ","), strings.Join(merge.Changed, ", ")
+		if adapterTarget == "" {
+			adapterTarget, sourceName = extracts[0].Target, extracts[0].Name
+		}
+	}
+
+	result := &feedbackResult{SkillID: skillID, Version: skill.Version, Targets: strings.Split(adapterTarget, ",")}
+	defer setResult(result)
+
+	// 技能仓库中的技能比项目中应用的版本新时（其他项目反馈或更新了技能），将项目中的修改合并到新版本，不覆盖仓库中的更新
+	if !skillNotFound && skillEnabled {
+		upstream, err := newerRepoSkill(skillID, projectSkill.Version)
+		if err != nil {
+			return err
+		}
+		if upstream != nil {
+			fmt.Printf("⚠️  技能仓库中的 %s 已更新到 %s，项目中应用的是 %s\n", skillID, upstream.Version, projectSkill.Version)
+			if feedbackOverwrite {
+				fmt.Println("⚠️  使用了 --overwrite，项目中的内容将覆盖技能仓库中的更新")
+			} else {
+				base, ok := state.AppliedBase(projectSkill, strings.SplitN(adapterTarget, ",", 2)[0])
+				if !ok && originalFromProject {
+					// 项目 .agents/skills/ 中的技能即为项目应用的版本
+					base, ok = renderedOriginal, true
+				}
+				if !ok {
+					return fmt.Errorf("找不到项目应用的 %s 版本的内容，无法与技能仓库中的更新合并。请先运行 'skill-hub apply' 更新项目中的技能后再修改，或使用 --overwrite 覆盖", projectSkill.Version)
+				}
+				theirs := template.Render(upstream.Content, skillVariables)
+				merged, conflicts := diff.Merge3(strings.TrimSpace(base)+"\n", strings.TrimSpace(fileContent)+"\n",
+					strings.TrimSpace(theirs)+"\n", "项目中的修改", "技能仓库 "+upstream.Version)
+				if conflicts > 0 {
+					fmt.Printf("\n❌ 项目中的修改与技能仓库中的更新有 %d 处冲突:\n", conflicts)
+					printFeedbackConflicts(merged)
+					return fmt.Errorf("项目中的修改与技能仓库中的更新冲突，请先运行 'skill-hub apply' 更新项目中的技能后再修改，或使用 --overwrite 覆盖")
+				}
+				fmt.Printf("🔀 已将项目中的修改合并到技能仓库中的 %s\n", upstream.Version)
+				result.MergedVersion = upstream.Version
+				fileContent = merged
+				originalContent = []byte(upstream.Content)
+				renderedOriginal = theirs
+			}
+		}
+	}
+
 	// 比较内容
 	hasChanges := skillNotFound || strings.TrimSpace(fileContent) != strings.TrimSpace(renderedOriginal)
 
@@ -288,7 +368,9 @@ metadata:
 		fmt.Println("✅ 技能内容未修改")
 
 		// 如果没有变化但使用了--archive参数，仍然执行归档
-		if archiveFlag {
+		if archiveFlag && feedbackDryRun {
+			fmt.Println("🔍 预演模式，不执行归档")
+		} else if archiveFlag {
 			fmt.Println("📦 检测到--archive参数，执行归档操作...")
 
 			// 先检查技能是否在仓库中存在，如果不存在则先创建
@@ -310,6 +392,7 @@ metadata:
 				return fmt.Errorf("归档失败: %w", err)
 			}
 			fmt.Println("✅ 技能归档完成！")
+			result.Version, result.Archived = skill.Version, true
 
 			// 刷新技能索引
 			fmt.Println("🔄 刷新技能索引...")
@@ -319,6 +402,9 @@ metadata:
 			} else {
 				fmt.Println("✅ 技能索引已刷新")
 			}
+			if feedbackPush {
+				result.Pushed = publishSkill(skillID, skill.Version)
+			}
 			return nil
 		}
 
@@ -337,69 +423,61 @@ metadata:
 		fmt.Println("\n🔍 检测到手动修改:")
 		fmt.Println("========================================")
 
-		fileLines := strings.Split(strings.TrimSpace(fileContent), "\n")
-		originalLines := strings.Split(strings.TrimSpace(renderedOriginal), "\n")
-
-		// 简单差异显示
-		maxLines := len(fileLines)
-		if len(originalLines) > maxLines {
-			maxLines = len(originalLines)
-		}
-
-		changesFound := false
-		for i := 0; i < maxLines; i++ {
-			var fileLine, originalLine string
-			if i < len(fileLines) {
-				fileLine = fileLines[i]
-			}
-			if i < len(originalLines) {
-				originalLine = originalLines[i]
-			}
-
-			if fileLine != originalLine {
-				if !changesFound {
-					fmt.Println("行号 | 修改前                      | 修改后")
-					fmt.Println("-----|---------------------------|---------------------------")
-					changesFound = true
-				}
-
-				lineNum := i + 1
-				fmt.Printf("%4d | %-25s | %-25s\n", lineNum,
-					truncate(originalLine, 25),
-					truncate(fileLine, 25))
-			}
-		}
-
-		if !changesFound {
+		changes := diff.Unified(skillID+" (技能仓库)", skillID+" ("+sourceName+")",
+			strings.TrimSpace(renderedOriginal)+"\n", strings.TrimSpace(fileContent)+"\n", diff.DefaultContext)
+		if changes == "" {
 			fmt.Println("（仅空白字符差异）")
+		} else {
+			printDiff(changes, "")
 		}
 
 		fmt.Println("========================================")
 
-		// 确认反馈
-		fmt.Print("\n是否将这些修改更新到技能仓库？ [y/N]: ")
+		// 确认反馈，--yes 时不询问
+		switch {
+		case feedbackYes, feedbackDryRun:
+			response = "y"
+		case noInput:
+			return fmt.Errorf("需要确认是否将修改更新到技能仓库，不读取输入时请使用 --yes 确认反馈")
+		default:
+			fmt.Print("\n是否将这些修改更新到技能仓库？ [y/N]: ")
 
-		reader := bufio.NewReader(os.Stdin)
-		response, _ = reader.ReadString('\n')
-		response = strings.TrimSpace(response)
+			reader := newInputReader()
+			response, _ = reader.ReadString('\n')
+			response = strings.TrimSpace(response)
+		}
 	}
 
 	if response != "y" && response != "Y" {
 		fmt.Println("❌ 取消反馈操作")
+		result.Cancelled = true
 		return nil
 	}
 
 	// 更新技能仓库
-	fmt.Println("正在更新技能仓库...")
+	if feedbackDryRun {
+		fmt.Println("🔍 预演模式：计算反馈后的技能内容，不修改技能仓库和项目状态")
+	} else {
+		fmt.Println("正在更新技能仓库...")
+	}
 
-	// 获取技能目录
-	skillsDir, err := engine.GetSkillsDir()
+	// 获取技能所在的目录，技能来自其他技能目录时更新那里的技能
+	manager, err := engine.NewSkillManager()
 	if err != nil {
 		return err
 	}
 
-	skillDir := fmt.Sprintf("%s/%s", skillsDir, skillID)
-	promptPath := fmt.Sprintf("%s/prompt.md", skillDir)
+	skillDir := manager.GetSkillDir(skillID)
+
+	// 先确定新版本号，版本号无效时不修改技能仓库
+	repoSkill, err := manager.LoadSkill(skillID)
+	if err != nil {
+		return fmt.Errorf("加载技能失败: %w", err)
+	}
+	newVersion, err := nextFeedbackVersion(repoSkill.Version)
+	if err != nil {
+		return err
+	}
 
 	// 使用智能变量提取算法
 	fmt.Println("正在分析变量变化...")
@@ -407,19 +485,24 @@ metadata:
 	// 提取原始模板中的变量
 	templateVars := template.ExtractVariables(string(originalContent))
 
+	newContent := fileContent
 	if len(templateVars) > 0 {
 		fmt.Printf("检测到 %d 个模板变量: %v\n", len(templateVars), templateVars)
 
-		// 询问用户如何处理变量
-		fmt.Println("\n检测到模板变量。请选择处理方式:")
-		fmt.Println("1. 保存修改后的内容（包含具体值）")
-		fmt.Println("2. 尝试智能提取变量值")
-		fmt.Println("3. 手动编辑变量值")
-		fmt.Print("请选择 (1/2/3, 默认 1): ")
-
-		reader := bufio.NewReader(os.Stdin)
-		choice, _ := reader.ReadString('\n')
-		choice = strings.TrimSpace(choice)
+		// 询问用户如何处理变量，--yes 时使用默认方式
+		reader := newInputReader()
+		choice := ""
+		if !feedbackYes {
+			fmt.Println("\n检测到模板变量。请选择处理方式:")
+			fmt.Println("1. 保留模板变量（将内容中的变量值还原为占位符）")
+			fmt.Println("2. 尝试智能提取变量值")
+			fmt.Println("3. 手动编辑变量值")
+			fmt.Println("4. 保存修改后的内容（包含具体值）")
+			fmt.Print("请选择 (1/2/3/4, 默认 1): ")
+
+			choice, _ = reader.ReadString('\n')
+			choice = strings.TrimSpace(choice)
+		}
 
 		var newTemplate string
 		var updatedVariables map[string]string
@@ -447,10 +530,13 @@ metadata:
 					fmt.Println("  (没有检测到变量值变化)")
 				}
 
-				// 询问是否更新项目变量
-				fmt.Print("\n是否更新项目中的变量值？ [y/N]: ")
-				updateVars, _ := reader.ReadString('\n')
-				updateVars = strings.TrimSpace(updateVars)
+				// 询问是否更新项目变量，预演时不修改
+				updateVars := ""
+				if !feedbackDryRun {
+					fmt.Print("\n是否更新项目中的变量值？ [y/N]: ")
+					updateVars, _ = reader.ReadString('\n')
+					updateVars = strings.TrimSpace(updateVars)
+				}
 
 				if updateVars == "y" || updateVars == "Y" {
 					if stateManager != nil {
@@ -484,8 +570,10 @@ metadata:
 			// 使用更新后的变量渲染模板
 			newTemplate = template.Render(string(originalContent), updatedVariables)
 
-			// 更新项目变量
-			if stateManager != nil {
+			// 更新项目变量，预演时不修改
+			if feedbackDryRun {
+				fmt.Println("🔍 预演模式，不更新项目变量")
+			} else if stateManager != nil {
 				if err := stateManager.UpdateSkillVariables(cwd, skillID, updatedVariables); err != nil {
 					fmt.Printf("警告: 更新项目变量失败: %v\n", err)
 				} else {
@@ -495,80 +583,94 @@ metadata:
 				fmt.Println("⚠️  状态管理器不可用，无法更新项目变量")
 			}
 
-		default:
-			// 选项1或默认：保存修改后的内容
+		case "4":
 			fmt.Println("将保存修改后的内容（包含具体值）")
 			newTemplate = fileContent
 			updatedVariables = skillVariables
-		}
 
-		// 写入更新后的模板
-		if err := os.WriteFile(promptPath, []byte(newTemplate), 0644); err != nil {
-			return fmt.Errorf("更新prompt.md失败: %w", err)
+		default:
+			// 选项1或默认：将变量值还原为占位符，未修改的行保留模板中的内容
+			newTemplate = template.Unrender(string(originalContent), fileContent, skillVariables)
+			updatedVariables = skillVariables
+			if lost := missingVariables(templateVars, newTemplate); len(lost) > 0 {
+				fmt.Printf("⚠️  修改后的内容中找不到变量 %s 的值，这些变量不再出现在模板中\n", strings.Join(lost, ", "))
+			} else {
+				fmt.Println("✓ 已将变量值还原为占位符")
+			}
 		}
 
-		fmt.Println("✓ 更新 prompt.md")
-
-	} else {
-		// 没有变量，直接保存
-		if err := os.WriteFile(promptPath, []byte(fileContent), 0644); err != nil {
-			return fmt.Errorf("更新prompt.md失败: %w", err)
-		}
-		fmt.Println("✓ 更新 prompt.md (无变量)")
+		newContent = newTemplate
 	}
 
-	// 重新初始化技能管理器以更新SKILL.md版本
-	skillManager, err := engine.NewSkillManager()
-	if err != nil {
-		return fmt.Errorf("初始化技能管理器失败: %w", err)
-	}
-
-	// 更新SKILL.md版本（重新加载技能以获取最新信息）
-	updatedSkill, err := skillManager.LoadSkill(skillID)
-	if err != nil {
-		return fmt.Errorf("加载技能失败: %w", err)
-	}
-
-	// 增加版本号
-	versionParts := strings.Split(updatedSkill.Version, ".")
-	if len(versionParts) == 3 {
-		// 简单增加修订版本号
-		// 在实际实现中应该更智能地处理版本号
-		updatedSkill.Version = fmt.Sprintf("%s.%s.%d",
-			versionParts[0],
-			versionParts[1],
-			parseInt(versionParts[2])+1)
-	}
-
-	// 读取当前的SKILL.md文件
-	skillMdPath = fmt.Sprintf("%s/SKILL.md", skillDir)
+	// 读取当前的SKILL.md文件，修改后的内容没有frontmatter时保留原来的frontmatter
+	skillMdPath = filepath.Join(skillDir, "SKILL.md")
 	skillMdContent, err := os.ReadFile(skillMdPath)
 	if err != nil {
 		return fmt.Errorf("读取SKILL.md失败: %w", err)
 	}
+	newContent = withSkillFrontmatter(newContent, string(skillMdContent))
 
 	// 解析并更新frontmatter中的版本号
-	updatedContent, err := updateVersionInFrontmatter(string(skillMdContent), updatedSkill.Version)
+	updatedContent, err := updateVersionInFrontmatter(newContent, newVersion)
 	if err != nil {
 		return fmt.Errorf("更新frontmatter版本号失败: %w", err)
 	}
 
+	// 更新日志的说明
+	entry := engine.ChangelogEntry{
+		Version: newVersion,
+		Date:    time.Now().Format("2006-01-02"),
+		Body:    feedbackChangelogBody(feedbackMessage, cwd),
+	}
+
+	if feedbackDryRun {
+		preview, err := feedbackPreview(skillID, skillDir, string(skillMdContent), updatedContent, entry)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n📋 技能 %s 反馈后的变化（版本 %s -> %s）:\n", skillID, repoSkill.Version, newVersion)
+		printDiff(preview, "")
+		if archiveFlag || feedbackPush {
+			fmt.Println("🔍 预演模式，不执行归档和推送")
+		}
+		fmt.Println("\n✅ 预演完成，未写入任何文件。去掉 --dry-run 执行反馈")
+		result.DryRun, result.Diff = true, preview
+		result.Changed, result.PreviousVersion, result.Version = true, repoSkill.Version, newVersion
+		return nil
+	}
+
 	// 保存更新后的SKILL.md
 	if err := os.WriteFile(skillMdPath, []byte(updatedContent), 0644); err != nil {
 		return fmt.Errorf("更新SKILL.md失败: %w", err)
 	}
 
 	fmt.Println("✓ 更新 SKILL.md")
-	fmt.Printf("✓ 版本更新: %s\n", updatedSkill.Version)
+	fmt.Printf("✓ 版本更新: %s\n", newVersion)
+	result.Changed, result.PreviousVersion, result.Version = true, repoSkill.Version, newVersion
+
+	// 记录更新日志
+	if err := engine.AppendChangelog(skillDir, entry); err != nil {
+		fmt.Printf("⚠️  记录更新日志失败: %v\n", err)
+	} else {
+		fmt.Printf("✓ 更新 %s\n", engine.ChangelogFile)
+	}
+
+	// 项目中的内容已是新版本，之后的反馈不再视为技能仓库有更新
+	if skillEnabled {
+		if err := stateManager.SetSkillVersion(cwd, skillID, newVersion); err != nil {
+			fmt.Printf("⚠️  更新项目中的技能版本失败: %v\n", err)
+		}
+	}
 
 	// 如果启用了归档标志，执行归档操作
 	if archiveFlag {
 		fmt.Println("\n📦 开始归档技能...")
-		if err := archiveSkill(skillID, updatedSkill.Version, cwd); err != nil {
+		if err := archiveSkill(skillID, newVersion, cwd); err != nil {
 			fmt.Printf("⚠️  归档失败: %v\n", err)
 			fmt.Println("技能已更新但未归档，请手动处理")
 		} else {
 			fmt.Println("✅ 技能归档完成！")
+			result.Archived = true
 
 			// 刷新技能索引
 			fmt.Println("🔄 刷新技能索引...")
@@ -581,28 +683,181 @@ metadata:
 		}
 	}
 
+	recordAudit(state.AuditEntry{
+		Operation: state.OpFeedback,
+		Project:   cwd,
+		SkillID:   skillID,
+		Version:   newVersion,
+		Adapter:   adapterTarget,
+	})
+
+	if feedbackPush {
+		result.Pushed = publishSkill(skillID, newVersion)
+	}
+
 	fmt.Println("\n✅ 反馈完成！")
+	if !feedbackPush {
+		fmt.Println("使用 'skill-hub feedback --push' 或 'skill-hub git commit' 推送到远程仓库")
+	}
 	if !archiveFlag {
-		fmt.Println("使用 'skill-hub update' 同步到远程仓库")
 		fmt.Println("使用 'skill-hub feedback --archive' 归档技能到正式仓库")
 	}
 
 	return nil
 }
 
-// truncate 截断字符串
-func truncate(s string, length int) string {
-	if len(s) <= length {
-		return s
+// feedbackChangelogBody 返回记录到更新日志的修改说明，未通过 --message 指定时交互输入，--yes 和 --dry-run 时使用默认说明
+func feedbackChangelogBody(message, projectPath string) string {
+	if message == "" && !feedbackYes && !feedbackDryRun {
+		fmt.Print("\n请输入本次修改的说明（留空使用默认说明）: ")
+		reader := newInputReader()
+		input, _ := reader.ReadString('\n')
+		message = strings.TrimSpace(input)
+	}
+	if message == "" {
+		message = fmt.Sprintf("根据项目 %s 中的修改更新", filepath.Base(projectPath))
 	}
-	return s[:length-3] + "..."
+	return changelogBody(message)
 }
 
-// parseInt 解析整数，失败返回0
-func parseInt(s string) int {
-	var result int
-	fmt.Sscanf(s, "%d", &result)
-	return result
+// changelogBody 将修改说明整理为更新日志的列表项，每个非空行一项
+func changelogBody(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "- ") {
+			line = "- " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// feedbackResult feedback 命令的结构化结果
+type feedbackResult struct {
+	SkillID         string   `json:"skill_id"`
+	Changed         bool     `json:"changed"`                    // 是否更新了技能仓库中的技能
+	PreviousVersion string   `json:"previous_version,omitempty"` // 反馈前技能仓库中的版本
+	Version         string   `json:"version"`
+	Targets         []string `json:"targets"`                  // 提取了修改的目标
+	MergedVersion   string   `json:"merged_version,omitempty"` // 合并了修改的技能仓库中的新版本
+	Archived        bool     `json:"archived"`
+	Pushed          bool     `json:"pushed"`
+	DryRun          bool     `json:"dry_run,omitempty"`
+	Diff            string   `json:"diff,omitempty"` // 预演时技能仓库中各文件的统一差异
+	Cancelled       bool     `json:"cancelled,omitempty"`
+}
+
+// feedbackPreview 返回反馈后技能目录中 SKILL.md 和 CHANGELOG.md 的统一差异
+func feedbackPreview(skillID, skillDir, oldSkillMd, newSkillMd string, entry engine.ChangelogEntry) (string, error) {
+	oldChangelog, err := os.ReadFile(filepath.Join(skillDir, engine.ChangelogFile))
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("读取更新日志失败: %w", err)
+	}
+	skillMd := path.Join(skillID, "SKILL.md")
+	changelog := path.Join(skillID, engine.ChangelogFile)
+	return diff.Unified(skillMd, skillMd, oldSkillMd, newSkillMd, diff.DefaultContext) +
+		diff.Unified(changelog, changelog, string(oldChangelog), engine.InsertChangelogEntry(string(oldChangelog), entry), diff.DefaultContext), nil
+}
+
+// repoSkillUpdate 技能仓库中比项目应用的版本新的技能
+type repoSkillUpdate struct {
+	Version string // 技能仓库中的版本
+	Content string // 技能仓库中的 SKILL.md 内容
+}
+
+// newerRepoSkill 返回技能仓库中比项目应用的版本 recorded 新的技能，不比 recorded 新或版本号无法比较时返回nil
+func newerRepoSkill(skillID, recorded string) (*repoSkillUpdate, error) {
+	applied, err := engine.ParseVersion(recorded)
+	if err != nil {
+		return nil, nil
+	}
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return nil, err
+	}
+	if !manager.SkillExists(skillID) {
+		return nil, nil
+	}
+	repoSkill, err := manager.LoadSkill(skillID)
+	if err != nil {
+		return nil, fmt.Errorf("加载技能失败: %w", err)
+	}
+	current, err := engine.ParseVersion(repoSkill.Version)
+	if err != nil || current.Compare(applied) <= 0 {
+		return nil, nil
+	}
+	content, err := os.ReadFile(filepath.Join(manager.GetSkillDir(skillID), "SKILL.md"))
+	if err != nil {
+		return nil, fmt.Errorf("读取技能文件失败: %w", err)
+	}
+	return &repoSkillUpdate{Version: repoSkill.Version, Content: string(content)}, nil
+}
+
+// nextFeedbackVersion 返回反馈后的版本号：指定了 --bump 时按其提升，否则在终端中询问（--yes 时不询问），默认提升修订号
+func nextFeedbackVersion(current string) (string, error) {
+	version, err := engine.ParseStrictVersion(current)
+	if err != nil {
+		return "", fmt.Errorf("技能的版本号不是有效的语义化版本，请先修正 SKILL.md 中的 metadata.version: %w", err)
+	}
+
+	part := feedbackBump
+	if part == "" {
+		part = engine.BumpPatch
+		if !feedbackYes && canPrompt() {
+			part = promptFeedbackBump(version)
+		}
+	}
+	next, err := version.Bump(part)
+	if err != nil {
+		return "", err
+	}
+	return next.String(), nil
+}
+
+// promptFeedbackBump 列出各提升方式得到的版本号，询问用户选择，默认提升修订号
+func promptFeedbackBump(version engine.Version) string {
+	parts := []struct{ name, note string }{
+		{engine.BumpPatch, "修正、措辞调整"},
+		{engine.BumpMinor, "新增内容，向后兼容"},
+		{engine.BumpMajor, "不兼容的修改"},
+	}
+	fmt.Printf("\n选择版本号提升方式（当前版本 %s）:\n", version)
+	for i, part := range parts {
+		next, _ := version.Bump(part.name)
+		fmt.Printf("%d. %-5s -> %s（%s）\n", i+1, part.name, next, part.note)
+	}
+	fmt.Print("请选择 (1/2/3, 默认 1): ")
+
+	reader := newInputReader()
+	choice, _ := reader.ReadString('\n')
+	choice = strings.ToLower(strings.TrimSpace(choice))
+	for i, part := range parts {
+		if choice == strconv.Itoa(i+1) || choice == part.name {
+			return part.name
+		}
+	}
+	return engine.BumpPatch
+}
+
+// withSkillFrontmatter 返回带frontmatter的技能内容：content 没有frontmatter时使用 original 的frontmatter
+func withSkillFrontmatter(content, original string) string {
+	content = strings.TrimSpace(content) + "\n"
+	if strings.HasPrefix(content, "---\n") {
+		return content
+	}
+	lines := strings.Split(original, "\n")
+	if len(lines) < 2 || lines[0] != "---" {
+		return content
+	}
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "---" {
+			return strings.Join(lines[:i+1], "\n") + "\n" + content
+		}
+	}
+	return content
 }
 
 // updateVersionInFrontmatter 更新SKILL.md frontmatter中的版本号
@@ -796,13 +1051,29 @@ func createSkillInRepository(skillID, content string) (*spec.Skill, error) {
 		result.Description = desc
 	}
 
-	if compat, ok := data["compatibility"].(string); ok {
-		result.Compatibility = compat
+	if compat, ok := data["compatibility"]; ok {
+		result.Compatibility = spec.CompatibilityDescription(compat)
 	}
 
 	return result, nil
 }
 
+// publishSkill 提交技能目录的修改并推送到远程仓库，返回是否推送成功。失败时只输出警告，反馈的修改保留在本地
+func publishSkill(skillID, version string) bool {
+	fmt.Println("\n🚀 推送到远程仓库...")
+	repo, err := git.NewSkillRepository()
+	if err == nil {
+		err = repo.PublishSkill(skillID, fmt.Sprintf("更新技能 %s@%s", skillID, version))
+	}
+	if err != nil {
+		fmt.Printf("⚠️  推送失败: %v\n", err)
+		fmt.Println("修改已保存在本地技能仓库，可使用 'skill-hub git commit' 手动提交并推送")
+		return false
+	}
+	fmt.Printf("✅ 技能 %s@%s 已推送到远程仓库\n", skillID, version)
+	return true
+}
+
 // archiveSkill 归档技能到正式技能仓库
 func archiveSkill(skillID, version, projectPath string) error {
 	fmt.Printf("归档技能 '%s' (版本: %s)...\n", skillID, version)
@@ -848,16 +1119,18 @@ func archiveSkill(skillID, version, projectPath string) error {
 
 	// 检查目标目录是否已存在
 	if _, err := os.Stat(targetDir); err == nil {
-		// 目录已存在，询问是否覆盖
+		// 目录已存在，询问是否覆盖，--yes 时直接覆盖（原有目录会备份）
 		fmt.Printf("⚠️  技能 '%s' 已存在于正式仓库\n", skillID)
-		fmt.Print("是否覆盖？ [y/N]: ")
+		if !feedbackYes {
+			fmt.Print("是否覆盖？ [y/N]: ")
 
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(response)
+			reader := newInputReader()
+			response, _ := reader.ReadString('\n')
+			response = strings.TrimSpace(response)
 
-		if response != "y" && response != "Y" {
-			return fmt.Errorf("取消归档操作")
+			if response != "y" && response != "Y" {
+				return fmt.Errorf("取消归档操作")
+			}
 		}
 
 		// 备份原有目录
@@ -890,7 +1163,7 @@ func archiveSkill(skillID, version, projectPath string) error {
 	}
 
 	// 复制技能文件
-	sourceFiles := []string{"SKILL.md", "prompt.md"}
+	sourceFiles := []string{"SKILL.md", "prompt.md", engine.ChangelogFile}
 	for _, filename := range sourceFiles {
 		sourceFile := filepath.Join(sourceDir, filename)
 		targetFile := filepath.Join(targetDir, filename)
@@ -972,27 +1245,19 @@ func refreshSkillRegistryAfterArchive() error {
 	}
 
 	var skills []spec.SkillMetadata
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		skillID := entry.Name()
-		skillDir := filepath.Join(skillsDir, skillID)
-		skillMdPath := filepath.Join(skillDir, "SKILL.md")
-
-		// 检查是否存在SKILL.md文件
-		if _, err := os.Stat(skillMdPath); os.IsNotExist(err) {
-			continue
-		}
-
+	for _, skillID := range collectSkillIDs(skillsDir, entries) {
 		// 解析SKILL.md文件
-		skillMeta, err := parseSkillMetadataForArchive(skillMdPath, skillID)
+		skillMeta, err := parseSkillMetadataForArchive(filepath.Join(skillsDir, skillID, "SKILL.md"), skillID)
 		if err != nil {
 			fmt.Printf("⚠️  解析技能 %s 失败: %v\n", skillID, err)
 			continue
 		}
 
+		// 记录内容摘要，导入和更新时据此校验
+		if digest, err := pack.SkillDigest(filepath.Join(skillsDir, skillID)); err == nil {
+			skillMeta.SHA256 = digest
+		}
+
 		skills = append(skills, *skillMeta)
 	}
 
@@ -1001,6 +1266,9 @@ func refreshSkillRegistryAfterArchive() error {
 		Version: "1.0.0",
 		Skills:  skills,
 	}
+	if previous, err := readRegistry(registryPath); err == nil {
+		registry.CarryStats(previous)
+	}
 
 	// 转换为JSON
 	registryJSON, err := json.MarshalIndent(registry, "", "  ")
@@ -1086,31 +1354,116 @@ func parseSkillMetadataForArchive(mdPath, skillID string) (*spec.SkillMetadata,
 		}
 	}
 
-	// 设置兼容性
+	// 设置兼容性，对象格式（向后兼容）转换为说明文字
 	if compatData, ok := skillData["compatibility"]; ok {
-		switch v := compatData.(type) {
-		case string:
-			skillMeta.Compatibility = v
-		case map[string]interface{}:
-			// 向后兼容：将对象格式转换为字符串
-			var compatList []string
-			if cursorVal, ok := v["cursor"].(bool); ok && cursorVal {
-				compatList = append(compatList, "Cursor")
-			}
-			if claudeVal, ok := v["claude_code"].(bool); ok && claudeVal {
-				compatList = append(compatList, "Claude Code")
-			}
-			if openCodeVal, ok := v["open_code"].(bool); ok && openCodeVal {
-				compatList = append(compatList, "OpenCode")
-			}
-			if shellVal, ok := v["shell"].(bool); ok && shellVal {
-				compatList = append(compatList, "Shell")
-			}
-			if len(compatList) > 0 {
-				skillMeta.Compatibility = "Designed for " + strings.Join(compatList, ", ") + " (or similar AI coding assistants)"
-			}
+		skillMeta.Compatibility = spec.CompatibilityDescription(compatData)
+	}
+
+	// 设置许可证
+	if license, ok := skillData["license"].(string); ok {
+		skillMeta.License = license
+	}
+
+	// 设置发布通道
+	if metadata, ok := skillData["metadata"].(map[string]interface{}); ok {
+		if channel, ok := metadata["channel"].(string); ok {
+			skillMeta.Channel = strings.ToLower(strings.TrimSpace(channel))
 		}
 	}
 
 	return skillMeta, nil
 }
+
+// feedbackCompatibleTargets 按顺序返回技能支持的目标，没有兼容性声明的技能支持 Cursor、Claude Code 和 OpenCode
+func feedbackCompatibleTargets(skill *spec.Skill, targets []string) []string {
+	var compatible []string
+	for _, target := range targets {
+		if skill.SupportsTarget(target) {
+			compatible = append(compatible, target)
+		}
+	}
+	return compatible
+}
+
+// missingVariables 返回 names 中没有出现在模板中的变量
+func missingVariables(names []string, content string) []string {
+	present := make(map[string]bool)
+	for _, name := range template.ExtractVariables(content) {
+		present[name] = true
+	}
+	var missing []string
+	for _, name := range names {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// feedbackExtract 从一个目标工具配置文件中提取到的技能内容
+type feedbackExtract struct {
+	Target  string
+	Name    string
+	Content string
+}
+
+// feedbackMerge 合并各目标中修改的结果
+type feedbackMerge struct {
+	Content   string   // 合并后的内容，没有目标修改时为原始内容
+	Changed   []string // 有修改的目标名称
+	Targets   []string // 有修改的目标
+	Conflicts int      // 冲突数，冲突处写入冲突标记
+}
+
+// mergeFeedbackExtracts 以渲染后的原始内容 base 为共同祖先，依次三方合并各目标中的修改
+//
+// 内容与 base 相同（忽略首尾空白）的目标不参与合并，修改相同的目标只合并一次。
+func mergeFeedbackExtracts(base string, extracts []feedbackExtract) feedbackMerge {
+	base = strings.TrimSpace(base) + "\n"
+	result := feedbackMerge{Content: base}
+	for _, extract := range extracts {
+		content := strings.TrimSpace(extract.Content) + "\n"
+		if content == base {
+			continue
+		}
+		if len(result.Changed) == 0 {
+			result.Content = content
+		} else if content != result.Content {
+			merged, conflicts := diff.Merge3(base, result.Content, content, strings.Join(result.Changed, ", "), extract.Name)
+			result.Content = merged
+			result.Conflicts += conflicts
+		}
+		result.Changed = append(result.Changed, extract.Name)
+		result.Targets = append(result.Targets, extract.Target)
+	}
+	return result
+}
+
+// printFeedbackConflicts 输出合并结果中的冲突区域
+func printFeedbackConflicts(content string) {
+	inConflict := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, diff.ConflictStart) {
+			inConflict = true
+		}
+		if inConflict {
+			fmt.Printf("  %s\n", line)
+		}
+		if strings.HasPrefix(line, diff.ConflictEnd) {
+			inConflict = false
+			fmt.Println()
+		}
+	}
+}
+
+// newFeedbackAdapter 创建从当前项目的目标工具配置文件中提取技能内容的适配器
+func newFeedbackAdapter(target string) adapter.Adapter {
+	switch target {
+	case spec.TargetClaudeCode:
+		return claude.NewClaudeAdapter().WithProjectMode()
+	case spec.TargetOpenCode:
+		return opencode.NewOpenCodeAdapter().WithProjectMode()
+	default:
+		return cursor.NewCursorAdapter().WithProjectMode()
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"skill-hub/internal/engine"
+)
+
+func TestMergeFeedbackExtracts(t *testing.T) {
+	base := "# demo\n\nline one\nline two\nline three\n"
+
+	t.Run("unchanged", func(t *testing.T) {
+		merge := mergeFeedbackExtracts(base, []feedbackExtract{
+			{Target: "cursor", Name: "Cursor", Content: base},
+			{Target: "open_code", Name: "OpenCode", Content: strings.TrimSpace(base)},
+		})
+		if merge.Content != base || len(merge.Changed) != 0 || merge.Conflicts != 0 {
+			t.Fatalf("unexpected merge: %+v", merge)
+		}
+	})
+
+	t.Run("single change", func(t *testing.T) {
+		changed := "# demo\n\nline one\nline 2\nline three\n"
+		merge := mergeFeedbackExtracts(base, []feedbackExtract{
+			{Target: "open_code", Name: "OpenCode", Content: base},
+			{Target: "claude_code", Name: "Claude", Content: changed},
+		})
+		if merge.Content != changed || strings.Join(merge.Targets, ",") != "claude_code" {
+			t.Fatalf("unexpected merge: %+v", merge)
+		}
+	})
+
+	t.Run("divergent changes", func(t *testing.T) {
+		merge := mergeFeedbackExtracts(base, []feedbackExtract{
+			{Target: "cursor", Name: "Cursor", Content: "# demo\n\nline 1\nline two\nline three\n"},
+			{Target: "claude_code", Name: "Claude", Content: "# demo\n\nline one\nline two\nline 3\n"},
+		})
+		want := "# demo\n\nline 1\nline two\nline 3\n"
+		if merge.Content != want || merge.Conflicts != 0 {
+			t.Fatalf("merge = %q (%d conflicts), want %q", merge.Content, merge.Conflicts, want)
+		}
+		if strings.Join(merge.Changed, ",") != "Cursor,Claude" {
+			t.Errorf("changed = %v", merge.Changed)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		merge := mergeFeedbackExtracts(base, []feedbackExtract{
+			{Target: "cursor", Name: "Cursor", Content: "# demo\n\nline one\nline 2\nline three\n"},
+			{Target: "claude_code", Name: "Claude", Content: "# demo\n\nline one\nline II\nline three\n"},
+		})
+		if merge.Conflicts != 1 || !strings.Contains(merge.Content, "<<<<<<< Cursor") || !strings.Contains(merge.Content, ">>>>>>> Claude") {
+			t.Fatalf("unexpected merge: %+v", merge)
+		}
+	})
+}
+
+func TestWithSkillFrontmatter(t *testing.T) {
+	original := "---\nname: demo\nmetadata:\n  version: 1.0.0\n---\n# demo\n\nold body\n"
+
+	if got := withSkillFrontmatter("---\nname: demo\n---\nnew body", original); got != "---\nname: demo\n---\nnew body\n" {
+		t.Errorf("content with frontmatter changed: %q", got)
+	}
+	want := "---\nname: demo\nmetadata:\n  version: 1.0.0\n---\n# demo\n\nnew body\n"
+	if got := withSkillFrontmatter("\n# demo\n\nnew body\n", original); got != want {
+		t.Errorf("withSkillFrontmatter() = %q, want %q", got, want)
+	}
+	if got := withSkillFrontmatter("body", "no frontmatter"); got != "body\n" {
+		t.Errorf("withSkillFrontmatter() without original frontmatter = %q", got)
+	}
+}
+
+func TestFeedbackChangelogBodyWithoutInput(t *testing.T) {
+	saved := feedbackYes
+	feedbackYes = true
+	t.Cleanup(func() { feedbackYes = saved })
+
+	if got := feedbackChangelogBody("", "/work/demo"); got != "- 根据项目 demo 中的修改更新" {
+		t.Errorf("default body = %q", got)
+	}
+	if got := feedbackChangelogBody("补充示例\n- 修正拼写", "/work/demo"); got != "- 补充示例\n- 修正拼写" {
+		t.Errorf("message body = %q", got)
+	}
+}
+
+func TestFeedbackPreview(t *testing.T) {
+	dir := t.TempDir()
+	entry := engine.ChangelogEntry{Version: "1.0.1", Date: "2024-01-01", Body: "- 补充示例"}
+	preview, err := feedbackPreview("demo", dir, "---\nversion: 1.0.0\n---\nold\n", "---\nversion: 1.0.1\n---\nnew\n", entry)
+	if err != nil {
+		t.Fatalf("feedbackPreview() error = %v", err)
+	}
+	for _, want := range []string{"--- demo/SKILL.md", "-version: 1.0.0", "+new", "+++ demo/CHANGELOG.md", "+## 1.0.1 - 2024-01-01", "+- 补充示例"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("preview missing %q:\n%s", want, preview)
+		}
+	}
+	if entries, _ := engine.ReadChangelog(dir); len(entries) != 0 {
+		t.Errorf("feedbackPreview() wrote changelog: %+v", entries)
+	}
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/migrate"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fmtCheck  bool
+	fmtDryRun bool
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [skill-id|path...]",
+	Short: "格式化技能的 SKILL.md 和 skill.yaml",
+	Long: `将 SKILL.md 的 frontmatter 和旧格式的 skill.yaml 改写为规范格式：
+字段按固定顺序排列，缩进为两个空格，字符串只在必要时加引号，使技能仓库的 diff 在不同贡献者之间保持一致。
+
+参数可以是技能ID、技能目录或文件，目录会递归查找；不指定时格式化技能仓库中的所有技能。
+使用 --check 只检查不修改，存在未格式化的文件时返回错误，适合在 CI 中使用。`,
+	ValidArgsFunction: completeSkillIDList,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFmt(args)
+	},
+}
+
+func init() {
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "只检查格式，存在未格式化的文件时返回错误")
+	fmtCmd.Flags().BoolVar(&fmtDryRun, "dry-run", false, "只列出需要格式化的文件")
+}
+
+func runFmt(args []string) error {
+	files, err := resolveFmtFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Println("ℹ️  没有找到 SKILL.md 或 skill.yaml 文件")
+		return nil
+	}
+
+	var changed []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("读取%s失败: %w", file, err)
+		}
+		formatted, err := formatSkillFile(file, content)
+		if err != nil {
+			return fmt.Errorf("格式化 %s 失败: %w", file, err)
+		}
+		if bytes.Equal(content, formatted) {
+			continue
+		}
+		changed = append(changed, file)
+		fmt.Printf("  %s\n", file)
+		if fmtCheck || fmtDryRun {
+			continue
+		}
+		if err := os.WriteFile(file, formatted, 0644); err != nil {
+			return fmt.Errorf("写入%s失败: %w", file, err)
+		}
+	}
+
+	setResult(map[string]interface{}{"files": files, "changed": changed})
+	switch {
+	case len(changed) == 0:
+		fmt.Printf("✓ %d 个文件均已是规范格式\n", len(files))
+	case fmtCheck:
+		return fmt.Errorf("%d 个文件未格式化，运行 'skill-hub fmt' 修复", len(changed))
+	case fmtDryRun:
+		fmt.Println("\n🔍 DRY RUN - 未修改任何文件")
+	default:
+		fmt.Printf("\n✅ 已格式化 %d 个文件\n", len(changed))
+	}
+	return nil
+}
+
+// formatSkillFile 按文件名选择格式化方式
+func formatSkillFile(path string, content []byte) ([]byte, error) {
+	if filepath.Base(path) == migrate.LegacySkillFile {
+		return spec.FormatSkillYAML(content)
+	}
+	return spec.FormatSkillMD(content)
+}
+
+// resolveFmtFiles 将参数解析为需要格式化的文件，未指定时查找技能仓库中的所有技能
+func resolveFmtFiles(args []string) ([]string, error) {
+	if len(args) == 0 {
+		skillsDir, err := engine.GetSkillsDir()
+		if err != nil {
+			return nil, err
+		}
+		return findSkillFiles(skillsDir)
+	}
+
+	var files []string
+	for _, arg := range args {
+		path := arg
+		if _, err := os.Stat(path); err != nil {
+			skillsDir, err := engine.GetSkillsDir()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(skillsDir, arg)
+			if _, err := os.Stat(path); err != nil {
+				return nil, fmt.Errorf("'%s' 既不是文件或目录，也不是技能仓库中的技能", arg)
+			}
+		}
+		found, err := findSkillFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+// findSkillFiles 查找路径下的 SKILL.md 和 skill.yaml，path 为文件时直接返回
+func findSkillFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != path && d.Name()[0] == '.' {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == migrate.SkillFile || d.Name() == migrate.LegacySkillFile {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查找技能文件失败: %w", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+)
+
+var gcDryRun bool
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "清理不再使用的已安装技能版本",
+	Long: `update 和 pin 会把技能版本安装到 ~/.skill-hub/versions/<技能ID>/<版本>/，新旧版本并存。
+该命令删除既不是技能仓库当前版本、也没有被任何项目的锁文件固定的版本。
+
+项目从状态文件中记录的项目路径查找；项目目录已不存在时不再保留它固定的版本。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGC()
+	},
+}
+
+func init() {
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "只列出将删除的版本，不实际删除")
+	rootCmd.AddCommand(gcCmd)
+}
+
+// skillVersionRef 已安装的技能版本
+type skillVersionRef struct {
+	SkillID string `json:"skill_id"`
+	Version string `json:"version"`
+}
+
+func runGC() error {
+	versions, err := engine.NewVersionStore()
+	if err != nil {
+		return err
+	}
+	installed, err := versions.All()
+	if err != nil {
+		return err
+	}
+
+	referenced, err := referencedSkillVersions()
+	if err != nil {
+		return err
+	}
+	unused := unreferencedVersions(installed, referenced)
+	if len(unused) == 0 {
+		fmt.Println("✓ 没有需要清理的技能版本")
+		setResult(map[string][]skillVersionRef{"removed": {}})
+		return nil
+	}
+
+	fmt.Println("以下技能版本不再被使用:")
+	for _, ref := range unused {
+		fmt.Printf("  - %s@%s\n", ref.SkillID, ref.Version)
+	}
+	if gcDryRun {
+		fmt.Println("\nℹ️  预览模式，未删除任何版本")
+		setResult(map[string][]skillVersionRef{"unused": unused})
+		return nil
+	}
+
+	var removed []skillVersionRef
+	for _, ref := range unused {
+		if err := versions.Remove(ref.SkillID, ref.Version); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			continue
+		}
+		removed = append(removed, ref)
+	}
+	fmt.Printf("\n✓ 已删除 %d 个技能版本\n", len(removed))
+	setResult(map[string][]skillVersionRef{"removed": removed})
+	return nil
+}
+
+// referencedSkillVersions 返回仍在使用的技能版本：技能仓库的当前版本和各项目锁文件固定的版本
+func referencedSkillVersions() (map[string]map[string]bool, error) {
+	referenced := make(map[string]map[string]bool)
+	add := func(skillID, version string) {
+		if referenced[skillID] == nil {
+			referenced[skillID] = make(map[string]bool)
+		}
+		referenced[skillID][version] = true
+	}
+
+	// 技能仓库加载失败时不能判断当前版本，不删除任何版本
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return nil, err
+	}
+	skills, err := skillManager.LoadAllSkills()
+	if err != nil {
+		return nil, fmt.Errorf("加载技能仓库失败，未删除任何版本: %w", err)
+	}
+	for _, skill := range skills {
+		add(skill.ID, skill.Version)
+	}
+
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return nil, err
+	}
+	projects, err := stateManager.ProjectPaths()
+	if err != nil {
+		return nil, err
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		projects = append(projects, cwd)
+	}
+	for _, project := range projects {
+		if _, err := os.Stat(project); err != nil {
+			continue
+		}
+		lock, err := state.LoadLockFile(project)
+		if err != nil {
+			return nil, fmt.Errorf("读取项目 %s 的锁文件失败，未删除任何版本: %w", project, err)
+		}
+		for id, locked := range lock.Skills {
+			add(id, locked.Version)
+		}
+	}
+	return referenced, nil
+}
+
+// unreferencedVersions 返回已安装但未被引用的技能版本，按技能ID和版本排序
+func unreferencedVersions(installed map[string][]string, referenced map[string]map[string]bool) []skillVersionRef {
+	ids := make([]string, 0, len(installed))
+	for id := range installed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var unused []skillVersionRef
+	for _, id := range ids {
+		for _, version := range installed[id] {
+			if !referenced[id][version] {
+				unused = append(unused, skillVersionRef{SkillID: id, Version: version})
+			}
+		}
+	}
+	return unused
+}
@@ -1,15 +1,18 @@
 package cli
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"skill-hub/internal/config"
 	"skill-hub/internal/git"
 )
 
+var gitCloneBranch string
+
 var gitCmd = &cobra.Command{
 	Use:   "git",
 	Short: "Git仓库操作",
@@ -71,6 +74,22 @@ var gitPullCmd = &cobra.Command{
 	},
 }
 
+var gitBranchCmd = &cobra.Command{
+	Use:   "branch [name]",
+	Short: "查看或切换技能仓库的分支",
+	Long: `不指定分支时显示技能仓库当前的分支；指定分支时切换到该分支，
+本地没有该分支时从远程仓库获取，并记录到配置文件的 git_branch。
+
+技能仓库中有未提交的修改时拒绝切换，先使用 'skill-hub git commit' 提交。`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return runGitBranch()
+		}
+		return runGitSwitchBranch(cmd.Context(), args[0])
+	},
+}
+
 var gitRemoteCmd = &cobra.Command{
 	Use:   "remote [url]",
 	Short: "设置远程仓库",
@@ -89,6 +108,9 @@ func init() {
 	gitCmd.AddCommand(gitPushCmd)
 	gitCmd.AddCommand(gitPullCmd)
 	gitCmd.AddCommand(gitRemoteCmd)
+	gitCmd.AddCommand(gitBranchCmd)
+
+	gitCloneCmd.Flags().StringVarP(&gitCloneBranch, "branch", "b", "", "克隆的分支，默认为远程仓库的默认分支")
 }
 
 func runGitClone(url string) error {
@@ -97,7 +119,7 @@ func runGitClone(url string) error {
 		return err
 	}
 
-	return repo.CloneRemote(url)
+	return repo.CloneRemote(url, gitCloneBranch)
 }
 
 func runGitSync() error {
@@ -132,7 +154,7 @@ func runGitCommit() error {
 
 	// 获取提交信息
 	fmt.Print("请输入提交信息: ")
-	reader := bufio.NewReader(os.Stdin)
+	reader := newInputReader()
 	message, _ := reader.ReadString('\n')
 	message = strings.TrimSpace(message)
 
@@ -159,7 +181,7 @@ func runGitPush() error {
 		fmt.Println("⚠️  检测到未提交的更改")
 		fmt.Print("是否先提交更改？ [y/N]: ")
 
-		reader := bufio.NewReader(os.Stdin)
+		reader := newInputReader()
 		response, _ := reader.ReadString('\n')
 		response = strings.TrimSpace(response)
 
@@ -187,6 +209,54 @@ func runGitPull() error {
 	return repo.Sync()
 }
 
+func runGitBranch() error {
+	repo, err := git.NewSkillRepository()
+	if err != nil {
+		return err
+	}
+	branch := repo.Branch()
+	if branch == "" {
+		return fmt.Errorf("技能仓库当前不在任何分支上")
+	}
+	setResult(map[string]string{"branch": branch})
+	fmt.Printf("🌿 当前分支: %s\n", branch)
+	return nil
+}
+
+func runGitSwitchBranch(ctx context.Context, branch string) error {
+	repo, err := git.NewSkillRepository()
+	if err != nil {
+		return err
+	}
+	if repo.Branch() == branch {
+		fmt.Printf("ℹ️  技能仓库已在分支 %s 上\n", branch)
+		return nil
+	}
+	if err := repo.SwitchBranch(ctx, branch); err != nil {
+		if errors.Is(err, git.ErrDirtyWorktree) {
+			return fmt.Errorf("%w\n   使用 'skill-hub git commit' 提交这些修改后再切换分支", err)
+		}
+		return err
+	}
+
+	// 记录跟踪的分支，并按新分支中的技能重新生成索引
+	if configPath, err := config.GetConfigPath(); err == nil {
+		if err := updateConfigValue(configPath, "git_branch", branch); err != nil {
+			fmt.Printf("⚠️  更新配置文件失败: %v\n", err)
+		}
+	}
+	repoDir, err := config.GetRepoPath()
+	if err != nil {
+		return err
+	}
+	if err := refreshSkillRegistry(repoDir); err != nil {
+		fmt.Printf("⚠️  刷新技能索引失败: %v\n", err)
+	}
+	setResult(map[string]string{"branch": branch})
+	fmt.Printf("✅ 已切换到分支 %s\n", branch)
+	return nil
+}
+
 func runGitRemote(url string) error {
 	repo, err := git.NewSkillsRepository()
 	if err != nil {
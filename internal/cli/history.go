@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"skill-hub/internal/state"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historySkill     string
+	historyProject   string
+	historyOperation string
+	historyLimit     int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "查看技能操作历史",
+	Long: `查看 apply、remove、feedback、update、rollback、uninstall、publish 操作的审计日志。
+
+每条记录包含时间、操作、技能、版本、适配器和执行用户。
+日志以仅追加方式保存在技能仓库目录的 history.jsonl 中。
+
+示例:
+  skill-hub history --skill git-expert
+  skill-hub history --project .
+  skill-hub history --op apply --limit 50`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHistory()
+	},
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historySkill, "skill", "", "只显示指定技能的记录")
+	historyCmd.Flags().StringVar(&historyProject, "project", "", "只显示指定项目路径的记录 (. 表示当前目录)")
+	historyCmd.Flags().StringVar(&historyOperation, "op", "", "只显示指定操作: apply, remove, feedback, update, rollback, uninstall, publish")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "显示最近的N条记录 (0 表示全部)")
+
+	historyCmd.RegisterFlagCompletionFunc("skill", completeSkillIDFlag)
+	historyCmd.RegisterFlagCompletionFunc("op", fixedCompletions(
+		state.OpApply, state.OpRemove, state.OpFeedback, state.OpUpdate, state.OpRollback, state.OpUninstall, state.OpPublish))
+}
+
+func runHistory() error {
+	auditLog, err := state.NewAuditLog()
+	if err != nil {
+		return err
+	}
+
+	filter := state.AuditFilter{
+		SkillID:   historySkill,
+		Operation: historyOperation,
+		Limit:     historyLimit,
+	}
+	if historyProject != "" {
+		absPath, err := filepath.Abs(historyProject)
+		if err != nil {
+			return fmt.Errorf("获取绝对路径失败: %w", err)
+		}
+		filter.Project = absPath
+	}
+
+	entries, err := auditLog.Read(filter)
+	if err != nil {
+		return err
+	}
+	setResult(entries)
+
+	if len(entries) == 0 {
+		fmt.Println("ℹ️  没有找到操作记录")
+		return nil
+	}
+
+	fmt.Printf("%-19s  %-8s  %-20s  %-10s  %-12s  %-10s  %s\n", "时间", "操作", "技能", "版本", "适配器", "用户", "项目")
+	for _, entry := range entries {
+		fmt.Printf("%-19s  %-8s  %-20s  %-10s  %-12s  %-10s  %s\n",
+			formatAuditTime(entry.Time),
+			entry.Operation,
+			valueOrDash(entry.SkillID),
+			valueOrDash(entry.Version),
+			valueOrDash(entry.Adapter),
+			valueOrDash(entry.User),
+			valueOrDash(entry.Project))
+		if entry.Detail != "" {
+			fmt.Printf("  └─ %s\n", entry.Detail)
+		}
+	}
+
+	fmt.Printf("\n共 %d 条记录\n", len(entries))
+	return nil
+}
+
+// recordAudit 写入审计日志，失败时只打印警告而不影响当前操作
+func recordAudit(entry state.AuditEntry) {
+	auditLog, err := state.NewAuditLog()
+	if err == nil {
+		err = auditLog.Append(entry)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  写入操作历史失败: %v\n", err)
+	}
+}
+
+// formatAuditTime 将记录时间转换为本地时间显示
+func formatAuditTime(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// valueOrDash 空值显示为 -
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/config"
+	"skill-hub/pkg/spec"
+)
+
+// updateSelection 'skill-hub update [技能ID...] --exclude' 选择要更新的技能
+type updateSelection struct {
+	ids     map[string]bool
+	exclude map[string]bool
+	channel string // --channel 指定的发布通道，为空时使用配置中的通道
+	confirm bool   // 逐个技能确认
+	all     bool   // 已选择更新其余全部技能
+	reader  *bufio.Reader
+}
+
+// newUpdateSelection 创建更新选择，指定了技能ID或 --exclude 时在终端中逐个技能确认
+//
+// channel 不为空时本次更新所有技能都使用该发布通道，否则使用配置中的 channel 和 skill_channels。
+func newUpdateSelection(ids, exclude []string, channel string) (*updateSelection, error) {
+	s := &updateSelection{ids: make(map[string]bool), exclude: make(map[string]bool)}
+	if channel != "" {
+		normalized, err := spec.NormalizeChannel(channel)
+		if err != nil {
+			return nil, err
+		}
+		s.channel = normalized
+	}
+	for _, id := range ids {
+		if err := spec.ValidateSkillID(id); err != nil {
+			return nil, err
+		}
+		s.ids[id] = true
+	}
+	for _, id := range exclude {
+		if err := spec.ValidateSkillID(id); err != nil {
+			return nil, err
+		}
+		s.exclude[id] = true
+	}
+	s.confirm = s.selective() && !updateYes && canPrompt()
+	if s.confirm {
+		s.reader = newInputReader()
+	}
+	return s, nil
+}
+
+// selective 检查是否只更新部分技能
+func (s *updateSelection) selective() bool {
+	return len(s.ids) > 0 || len(s.exclude) > 0
+}
+
+// includes 检查技能是否在要更新的范围内
+func (s *updateSelection) includes(id string) bool {
+	if s.exclude[id] {
+		return false
+	}
+	return len(s.ids) == 0 || s.ids[id]
+}
+
+// validateChannelConfig 检查配置中的发布通道，避免更新到一半时才发现配置无效
+func validateChannelConfig() error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	if _, err := config.GetSkillChannel(""); err != nil {
+		return err
+	}
+	for id := range cfg.SkillChannels {
+		if _, err := config.GetSkillChannel(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscribed 返回技能本次更新使用的发布通道
+func (s *updateSelection) subscribed(id string) string {
+	return subscribedChannel(id, s.channel)
+}
+
+// subscribedChannel 返回技能订阅的发布通道，override 不为空时优先，配置无效时为 stable
+func subscribedChannel(id, override string) string {
+	if override != "" {
+		return override
+	}
+	channel, err := config.GetSkillChannel(id)
+	if err != nil {
+		return spec.ChannelStable
+	}
+	return channel
+}
+
+// acceptChannel 检查技能订阅的通道是否接受新版本，不接受时输出提示
+func (s *updateSelection) acceptChannel(id, version, channel string) bool {
+	subscribed := s.subscribed(id)
+	if spec.ChannelAccepts(subscribed, channel) {
+		return true
+	}
+	fmt.Printf("🔒 %s@%s 发布在 %s 通道，当前订阅 %s 通道，保持当前版本（使用 --channel %s 更新）\n",
+		id, version, channel, subscribed, channel)
+	return false
+}
+
+// accept 检查是否更新技能，需要确认时询问用户，change 描述技能的变化
+//
+// preview 不为nil时在询问前调用，输出技能的更新日志。
+func (s *updateSelection) accept(id, change string, preview func()) bool {
+	if !s.includes(id) {
+		return false
+	}
+	if !s.confirm || s.all {
+		return true
+	}
+	if preview != nil {
+		fmt.Println()
+		preview()
+	}
+	fmt.Printf("是否更新 %s（%s）？ [y/N/a(全部)]: ", id, change)
+	response, _ := s.reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(response)) {
+	case "a", "all":
+		s.all = true
+		return true
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// missing 返回指定了但不存在的技能ID
+func (s *updateSelection) missing(exists func(id string) bool) []string {
+	var ids []string
+	for id := range s.ids {
+		if !exists(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// loadHeldSkills 读取保持在当前版本的技能，文件不存在时返回nil
+func loadHeldSkills() ([]string, error) {
+	path, err := config.GetHeldSkillsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取保持版本的技能列表失败: %w", err)
+	}
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("解析保持版本的技能列表失败: %w", err)
+	}
+	return ids, nil
+}
+
+// saveHeldSkills 保存保持在当前版本的技能，列表为空时删除文件
+func saveHeldSkills(ids []string) error {
+	path, err := config.GetHeldSkillsPath()
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除保持版本的技能列表失败: %w", err)
+		}
+		return nil
+	}
+	sort.Strings(ids)
+	data, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化保持版本的技能列表失败: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("保存保持版本的技能列表失败: %w", err)
+	}
+	return nil
+}
+
+// holdUnselectedSkills 同步后将未选择、未确认更新或新版本不在订阅通道中的技能恢复为同步前的内容，返回保持当前版本的技能
+//
+// snapshotDir 为同步前技能目录的副本，before 为同步前的技能版本和内容摘要。
+func holdUnselectedSkills(skillsDir, snapshotDir string, before map[string]skillSnapshot, selection *updateSelection) ([]string, error) {
+	after := skillSnapshots()
+	changes := diffSkillSnapshots(before, after)
+
+	describe := make(map[string]string)
+	for _, id := range changes.Added {
+		describe[id] = "新技能 " + after[id].Version
+	}
+	for _, id := range changes.Upgraded {
+		describe[id] = before[id].Version + " -> " + after[id].Version
+	}
+	for _, id := range changes.Modified {
+		describe[id] = "内容有变化"
+	}
+	for _, id := range changes.Removed {
+		describe[id] = "已从技能仓库删除"
+	}
+	ids := make([]string, 0, len(describe))
+	for id := range describe {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var held []string
+	for _, id := range ids {
+		current, exists := after[id]
+		var preview func()
+		if exists {
+			fromDir := filepath.Join(snapshotDir, filepath.FromSlash(id))
+			if _, err := os.Stat(fromDir); err != nil {
+				fromDir = ""
+			}
+			toDir := filepath.Join(skillsDir, filepath.FromSlash(id))
+			preview = func() { printSkillChange(id, before[id].Version, fromDir, current.Version, toDir, "   ") }
+		}
+		if (!exists || selection.acceptChannel(id, current.Version, current.Channel)) && selection.accept(id, describe[id], preview) {
+			continue
+		}
+		if err := restoreSkillDir(skillsDir, snapshotDir, id); err != nil {
+			return held, err
+		}
+		held = append(held, id)
+	}
+	return held, nil
+}
+
+// restoreSkillDir 将技能目录恢复为副本中的内容，副本中没有该技能时删除技能目录
+func restoreSkillDir(skillsDir, snapshotDir, id string) error {
+	dir := filepath.Join(skillsDir, filepath.FromSlash(id))
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("恢复技能 %s 失败: %w", id, err)
+	}
+	saved := filepath.Join(snapshotDir, filepath.FromSlash(id))
+	if _, err := os.Stat(saved); os.IsNotExist(err) {
+		return nil
+	}
+	if err := copyDir(saved, dir); err != nil {
+		return fmt.Errorf("恢复技能 %s 失败: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestUpdateSelection(t *testing.T) {
+	selection, err := newUpdateSelection([]string{"git-expert", "acme/lint"}, []string{"acme/lint"}, "")
+	if err != nil {
+		t.Fatalf("newUpdateSelection() error = %v", err)
+	}
+	if !selection.selective() {
+		t.Error("selective() = false, want true")
+	}
+	tests := map[string]bool{
+		"git-expert":  true,
+		"acme/lint":   false, // --exclude 优先于指定的技能ID
+		"code-review": false,
+	}
+	for id, want := range tests {
+		if got := selection.accept(id, "1.0.0 -> 1.1.0", nil); got != want {
+			t.Errorf("accept(%q) = %v, want %v", id, got, want)
+		}
+	}
+
+	missing := selection.missing(func(id string) bool { return id == "git-expert" })
+	if len(missing) != 1 || missing[0] != "acme/lint" {
+		t.Errorf("missing() = %v, want [acme/lint]", missing)
+	}
+
+	all, err := newUpdateSelection(nil, nil, "beta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all.selective() || !all.accept("code-review", "内容有变化", nil) {
+		t.Error("未指定技能时应更新全部技能")
+	}
+
+	if _, err := newUpdateSelection([]string{"../etc"}, nil, ""); err == nil {
+		t.Error("newUpdateSelection() 应拒绝无效的技能ID")
+	}
+	if _, err := newUpdateSelection(nil, nil, "nightly"); err == nil {
+		t.Error("newUpdateSelection() 应拒绝无效的发布通道")
+	}
+
+	// --channel beta 接受 beta 和 stable 通道的版本
+	if !all.acceptChannel("code-review", "1.1.0-beta.1", spec.ChannelBeta) || !all.acceptChannel("code-review", "1.0.0", "") {
+		t.Error("acceptChannel() 应接受 beta 和 stable 通道的版本")
+	}
+}
+
+func TestRestoreSkillDir(t *testing.T) {
+	skillsDir := t.TempDir()
+	snapshotDir := t.TempDir()
+	write := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(snapshotDir, "alpha", "SKILL.md"), "v1")
+	write(filepath.Join(skillsDir, "alpha", "SKILL.md"), "v2")
+	write(filepath.Join(skillsDir, "alpha", "extra.md"), "new")
+	write(filepath.Join(skillsDir, "acme", "beta", "SKILL.md"), "new skill")
+
+	if err := restoreSkillDir(skillsDir, snapshotDir, "alpha"); err != nil {
+		t.Fatalf("restoreSkillDir(alpha) error = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(skillsDir, "alpha", "SKILL.md")); err != nil || string(data) != "v1" {
+		t.Errorf("alpha/SKILL.md = %q, %v, want v1", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(skillsDir, "alpha", "extra.md")); !os.IsNotExist(err) {
+		t.Error("同步后新增的文件应被删除")
+	}
+
+	// 同步前不存在的技能被删除
+	if err := restoreSkillDir(skillsDir, snapshotDir, "acme/beta"); err != nil {
+		t.Fatalf("restoreSkillDir(acme/beta) error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(skillsDir, "acme", "beta")); !os.IsNotExist(err) {
+		t.Error("同步前不存在的技能应被删除")
+	}
+}
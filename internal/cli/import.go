@@ -0,0 +1,952 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/config"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/forge"
+	"skill-hub/internal/git"
+	"skill-hub/internal/migrate"
+	"skill-hub/internal/oci"
+	"skill-hub/internal/pack"
+	"skill-hub/internal/registry"
+	"skill-hub/pkg/spec"
+	"skill-hub/pkg/validator"
+)
+
+// 技能ID冲突处理策略
+const (
+	conflictAsk       = "ask"
+	conflictOverwrite = "overwrite"
+	conflictRename    = "rename"
+	conflictSkip      = "skip"
+)
+
+var (
+	importOnConflict     string
+	importSkipValidation bool
+	importNamespace      string
+	importOnly           []string
+	importJobs           int
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <source>...",
+	Short: "从目录、归档或Git仓库导入技能",
+	Long: `将技能导入到本地技能仓库。
+
+source 可以是：
+  - 本地目录：单个技能目录（包含SKILL.md），或包含多个技能的目录/仓库
+  - 归档文件：由 'skill-hub export' 生成的 .tar.gz/.tgz/.tar/.zip
+  - Git仓库URL：https://、git@、ssh://、file:// 地址，或 github.com/owner/repo 简写
+  - 代码托管平台简写：<平台名称>:owner/repo，例如 gitlab:group/skills，
+    平台在配置文件的 forges 中设置，可以是自建的 GitLab 或 Gitea，克隆时使用平台的 token
+  - OCI制品：oci://ghcr.io/acme/skills/git-expert:1.0.0，由 'skill-hub push' 推送
+  - 技能ID：<技能ID>[@版本]，从配置的 registry_url 静态HTTP技能注册表下载，不需要git
+
+在目录、归档或仓库地址后使用 // 指定子目录，只导入该目录中的技能，例如
+https://github.com/org/repo//skills/git-expert；也可以使用 --only 按技能ID选择要导入的技能。
+
+可以同时指定多个导入源，它们并行下载或克隆（--jobs 设置并行数），显示每个导入源的状态、
+下载字节数和预计剩余时间；部分导入源获取失败时继续导入其他导入源。
+
+导入前会校验技能格式和归档清单中的文件哈希。
+Claude Code 和社区技能仓库（例如 .claude/skills/、document-skills/ 等嵌套布局）中的技能
+会自动转换为规范格式：修正 name、补全 description、将根级别的 version 和 author 移入 metadata 等，
+并列出每项转换；导入源本身不会被修改。
+技能ID取自归档清单或技能目录名；位于归档或仓库根部、没有清单的技能使用 SKILL.md 中的 name，
+name 缺失或不符合规范时使用归档或仓库名。
+技能ID与任一技能目录（skill_roots）中已有的技能冲突时，默认交互式询问覆盖、重命名或跳过；
+技能总是导入到技能仓库，覆盖时先复制到临时目录再替换现有技能。
+
+使用 --namespace 将技能导入到命名空间中（技能ID为 <namespace>/<name>），
+避免不同来源的同名技能互相冲突。归档清单中带命名空间的技能ID会被保留。
+
+示例:
+  skill-hub import https://github.com/org/repo//skills/git-expert
+  skill-hub import github.com/org/repo --only git-expert --only code-review
+  skill-hub import acme/lint acme/format github.com/org/repo -j 8`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runImport(args)
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importOnConflict, "on-conflict", conflictAsk, "ID冲突处理: ask, overwrite, rename, skip")
+	importCmd.Flags().BoolVar(&importSkipValidation, "skip-validation", false, "跳过技能格式校验")
+	importCmd.Flags().StringVar(&importNamespace, "namespace", "", "导入到指定命名空间，例如 acme")
+	importCmd.Flags().StringArrayVar(&importOnly, "only", nil, "只导入指定ID的技能（可多次指定）")
+	importCmd.Flags().IntVarP(&importJobs, "jobs", "j", defaultJobs, "指定多个导入源时并行下载或克隆的数量")
+
+	importCmd.RegisterFlagCompletionFunc("on-conflict", fixedCompletions(conflictAsk, conflictOverwrite, conflictRename, conflictSkip))
+}
+
+func runImport(sources []string) error {
+	switch importOnConflict {
+	case conflictAsk, conflictOverwrite, conflictRename, conflictSkip:
+	default:
+		return fmt.Errorf("无效的冲突处理策略: %s，可用选项: ask, overwrite, rename, skip", importOnConflict)
+	}
+	if importNamespace != "" && (strings.Contains(importNamespace, spec.NamespaceSeparator) || spec.ValidateSkillID(importNamespace) != nil) {
+		return fmt.Errorf("无效的命名空间: %s，只能包含小写字母、数字和连字符", importNamespace)
+	}
+	if importJobs < 1 {
+		return fmt.Errorf("--jobs 必须大于0")
+	}
+
+	repoDir, err := config.GetRepoPath()
+	if err != nil {
+		return err
+	}
+	skillsDir, err := engine.GetSkillsDir()
+	if err != nil {
+		return fmt.Errorf("获取技能目录失败: %w", err)
+	}
+	roots, err := config.GetSkillRoots()
+	if err != nil {
+		return fmt.Errorf("获取技能目录失败: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "skill-hub-import-")
+	if err != nil {
+		return fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	resolved, err := resolveImportSources(sources, tmpDir)
+	if err != nil {
+		return err
+	}
+
+	// 技能目录所属导入源的根目录，用于读取其中的 registry.json
+	var skillDirs []string
+	rootOf := make(map[string]string)
+	// 位于解压或克隆的临时目录根部的技能，目录名没有意义，记录导入源名称用于确定ID
+	sourceNames := make(map[string]string)
+	for _, source := range resolved {
+		if source.root == "" {
+			continue
+		}
+		dirs, err := source.skillDirs()
+		if err != nil {
+			if len(resolved) == 1 {
+				return err
+			}
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+		for _, dir := range dirs {
+			rootOf[dir] = source.root
+			if name := source.nameFor(dir); name != "" {
+				sourceNames[dir] = name
+			}
+		}
+		skillDirs = append(skillDirs, dirs...)
+	}
+	if len(skillDirs) == 0 {
+		return fmt.Errorf("导入源中没有找到技能（需要包含SKILL.md的目录）")
+	}
+	if len(importOnly) > 0 {
+		if skillDirs, err = filterImportSkills(skillDirs, importOnly, sourceNames); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("发现 %d 个技能\n", len(skillDirs))
+
+	// 依赖的版本约束在安装任何技能之前检查
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	if err := checkImportDependencies(skillDirs, sourceNames, manager.LoadSkill); err != nil {
+		return err
+	}
+	if err := checkImportRequirements(skillDirs, sourceNames); err != nil {
+		return err
+	}
+
+	// 导入源带有 registry.json 时，按其中记录的内容摘要校验技能
+	digestsOf := make(map[string]map[string]string)
+	for _, root := range rootOf {
+		if _, ok := digestsOf[root]; ok {
+			continue
+		}
+		if digestsOf[root], err = loadRegistryDigests(root); err != nil {
+			return err
+		}
+	}
+
+	reader := newInputReader()
+	imported := 0
+	for i, dir := range skillDirs {
+		root := rootOf[dir]
+		skillID, explicit, err := importSkillID(dir, sourceNames[dir])
+		if err != nil {
+			fmt.Printf("⚠️  跳过 %s: %v\n", dir, err)
+			continue
+		}
+		if importNamespace != "" {
+			_, name := spec.SplitSkillID(skillID)
+			skillID = spec.JoinSkillID(importNamespace, name)
+		}
+		fmt.Printf("\n处理技能: %s\n", skillID)
+
+		// 校验归档清单
+		manifest, err := pack.ReadManifest(dir)
+		if err != nil {
+			fmt.Printf("❌ 跳过 %s: %v\n", skillID, err)
+			continue
+		}
+		if manifest != nil {
+			if err := pack.Verify(dir, manifest); err != nil {
+				fmt.Printf("❌ 跳过 %s: %v\n", skillID, err)
+				continue
+			}
+			fmt.Printf("✓ 清单校验通过 (%d 个文件)\n", len(manifest.Files))
+		}
+		if digest, ok := registryDigest(root, dir, digestsOf[root]); ok {
+			if err := pack.VerifyDigest(dir, digest); err != nil {
+				fmt.Printf("❌ 跳过 %s: 与 registry.json 记录的不一致，可能已损坏或被篡改: %v\n", skillID, err)
+				continue
+			}
+			fmt.Println("✓ 内容摘要校验通过")
+		}
+
+		// 没有归档清单的技能可能来自 Claude Code 或社区技能仓库，先转换为规范格式
+		if manifest == nil {
+			converted, err := convertImportedSkill(dir, skillID, explicit, filepath.Join(tmpDir, "converted", fmt.Sprint(i)))
+			if err != nil {
+				fmt.Printf("❌ 跳过 %s: %v\n", skillID, err)
+				continue
+			}
+			dir = converted
+		}
+
+		// 校验技能格式
+		if !importSkipValidation {
+			result, err := validator.NewValidator().ValidateFile(filepath.Join(dir, "SKILL.md"))
+			if err != nil {
+				fmt.Printf("❌ 跳过 %s: %v\n", skillID, err)
+				continue
+			}
+			if !result.IsValid {
+				fmt.Printf("❌ 技能 %s 不符合标准，跳过:\n", skillID)
+				for _, e := range result.Errors {
+					fmt.Printf("  ❌ [%s] %s\n", e.Code, e.Message)
+				}
+				continue
+			}
+			if skill, err := engine.LoadSkillFile(filepath.Join(dir, "SKILL.md"), skillID); err == nil {
+				if err := engine.ValidateToolSpec(dir, skill); err != nil {
+					fmt.Printf("❌ 技能 %s 的工具定义无效，跳过: %v\n", skillID, err)
+					continue
+				}
+			}
+		}
+
+		// 处理ID冲突
+		targetID, ok, err := resolveImportConflict(reader, roots, skillID, importOnConflict)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("ℹ️  跳过技能 %s\n", skillID)
+			continue
+		}
+
+		if err := installImportedSkill(dir, skillsDir, skillID, targetID); err != nil {
+			fmt.Printf("❌ 安装技能 %s 失败: %v\n", targetID, err)
+			continue
+		}
+
+		if targetID != skillID {
+			fmt.Printf("✓ 已导入技能 %s (重命名自 %s)\n", targetID, skillID)
+		} else {
+			fmt.Printf("✓ 已导入技能 %s\n", targetID)
+		}
+		imported++
+	}
+
+	if imported == 0 {
+		fmt.Println("\nℹ️  没有导入任何技能")
+		return nil
+	}
+
+	if err := refreshSkillRegistry(repoDir); err != nil {
+		fmt.Printf("⚠️  刷新技能索引失败: %v\n", err)
+	}
+
+	fmt.Printf("\n✅ 成功导入 %d 个技能\n", imported)
+	fmt.Println("使用 'skill-hub use <skill-id>' 在项目中启用技能")
+	return nil
+}
+
+// splitSubPath 拆分导入源末尾 // 之后的子目录，例如 https://github.com/org/repo//skills/git-expert
+// 拆分为仓库地址和 skills/git-expert；URL协议中的 :// 不作为分隔符
+func splitSubPath(source string) (string, string, error) {
+	start := 0
+	if i := strings.Index(source, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	i := strings.Index(source[start:], "//")
+	if i < 0 {
+		return source, "", nil
+	}
+	base, subPath := source[:start+i], strings.Trim(source[start+i+2:], "/")
+	if base == "" || subPath == "" {
+		return "", "", fmt.Errorf("无效的导入源: %s，子目录的格式为 <导入源>//<子目录>", source)
+	}
+	cleaned := path.Clean(subPath)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", "", fmt.Errorf("无效的子目录: %s，不能指向导入源之外", subPath)
+	}
+	return base, cleaned, nil
+}
+
+// filterImportSkills 按技能ID选择要导入的技能目录，有ID未找到时返回错误并列出可用的技能
+func filterImportSkills(skillDirs, ids []string, sourceNames map[string]string) ([]string, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var selected, available []string
+	for _, dir := range skillDirs {
+		skillID, _, err := importSkillID(dir, sourceNames[dir])
+		if err != nil {
+			continue
+		}
+		available = append(available, skillID)
+		if wanted[skillID] {
+			selected = append(selected, dir)
+			delete(wanted, skillID)
+		}
+	}
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for id := range wanted {
+			missing = append(missing, id)
+		}
+		sort.Strings(missing)
+		return nil, fmt.Errorf("导入源中没有技能 %s，可用技能: %s", strings.Join(missing, ", "), strings.Join(available, ", "))
+	}
+	return selected, nil
+}
+
+// importSource 准备好的导入源
+type importSource struct {
+	source  string
+	subPath string
+	// root 导入源的本地目录，获取失败时为空
+	root string
+}
+
+// skillDirs 在导入源（或其中的子目录）中查找技能目录
+func (s *importSource) skillDirs() ([]string, error) {
+	// 子目录只限定查找技能的范围，registry.json 仍从导入源的根目录读取
+	searchRoot := s.root
+	if s.subPath != "" {
+		searchRoot = filepath.Join(s.root, filepath.FromSlash(s.subPath))
+		if info, err := os.Stat(searchRoot); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("在 %s 中没有找到子目录 %s", s.source, s.subPath)
+		}
+	}
+	dirs, err := findSkillDirs(searchRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("在 %s 中没有找到技能（需要包含SKILL.md的目录）", s.source)
+	}
+	return dirs, nil
+}
+
+// nameFor 返回确定技能ID时使用的导入源名称：只有位于解压归档或克隆仓库的临时目录根部的技能
+// 才需要，其他技能的目录名就是技能ID，返回空字符串
+func (s *importSource) nameFor(dir string) string {
+	if dir != s.root || s.root == s.source {
+		return ""
+	}
+	return importSourceName(s.source)
+}
+
+// resolveImportSources 将导入源准备为本地目录。多个导入源并行下载或克隆并显示进度，
+// 部分导入源失败时继续导入其他导入源，全部失败时返回错误
+func resolveImportSources(sources []string, tmpDir string) ([]*importSource, error) {
+	resolved := make([]*importSource, len(sources))
+	for i, source := range sources {
+		base, subPath, err := splitSubPath(source)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = &importSource{source: base, subPath: subPath}
+	}
+
+	if len(resolved) == 1 {
+		root, err := resolveImportSource(resolved[0].source, filepath.Join(tmpDir, "0"), nil)
+		if err != nil {
+			return nil, err
+		}
+		resolved[0].root = root
+		return resolved, nil
+	}
+
+	fmt.Printf("正在获取 %d 个导入源（并行数 %d）\n", len(sources), importJobs)
+	errs := newProgressReporter(sources).runParallel(importJobs, func(i int, task *progressTask) error {
+		root, err := resolveImportSource(resolved[i].source, filepath.Join(tmpDir, fmt.Sprint(i)), task)
+		resolved[i].root = root
+		return err
+	})
+	for _, err := range errs {
+		if err == nil {
+			return resolved, nil
+		}
+	}
+	return nil, fmt.Errorf("所有导入源都获取失败")
+}
+
+// importStatus 输出获取导入源的进度，并行获取时更新任务状态
+func importStatus(task *progressTask, format string, args ...interface{}) {
+	if task != nil {
+		task.SetStatus(fmt.Sprintf(format, args...))
+		return
+	}
+	fmt.Printf(format+"\n", args...)
+}
+
+// resolveImportSource 将导入源准备为本地目录（解压归档或克隆仓库），task 不为nil时通过它报告进度
+func resolveImportSource(source, tmpDir string, task *progressTask) (string, error) {
+	if info, err := os.Stat(source); err == nil {
+		if info.IsDir() {
+			return source, nil
+		}
+		if !pack.IsArchive(source) {
+			return "", fmt.Errorf("不支持的文件类型: %s（支持 .tar.gz, .tgz, .tar, .zip）", source)
+		}
+
+		extractDir := filepath.Join(tmpDir, "extract")
+		importStatus(task, "正在解压归档: %s", source)
+		if err := pack.Extract(source, extractDir); err != nil {
+			return "", fmt.Errorf("解压失败: %w", err)
+		}
+		return extractDir, nil
+	}
+
+	if strings.HasPrefix(source, oci.Scheme) {
+		if isOffline() {
+			return "", fmt.Errorf("离线模式下不能拉取OCI制品: %s", source)
+		}
+		return pullOCISource(source, tmpDir, task)
+	}
+
+	// 配置的代码托管平台优先，克隆时使用平台各自的token
+	token := ""
+	forges, err := config.GetForges()
+	if err != nil {
+		return "", err
+	}
+	url, f, ok := forge.Resolve(forges, source)
+	if ok {
+		token = f.Token
+	} else if url, ok = normalizeGitURL(source); !ok {
+		return resolveRegistrySource(source, tmpDir, task)
+	}
+
+	if isOffline() {
+		return "", fmt.Errorf("离线模式下不能克隆仓库: %s，可以导入本地目录、归档或缓存的注册表技能", url)
+	}
+	cloneDir := filepath.Join(tmpDir, "clone")
+	importStatus(task, "正在克隆仓库: %s", url)
+	// 并行克隆时不输出各仓库的克隆进度
+	progress := io.Writer(os.Stdout)
+	if task != nil {
+		progress = io.Discard
+	}
+	if _, err := git.CloneIntoWithProgress(url, cloneDir, token, progress); err != nil {
+		return "", err
+	}
+	return cloneDir, nil
+}
+
+// pullOCISource 从容器镜像仓库拉取技能制品并解压，返回解压目录
+func pullOCISource(source, tmpDir string, task *progressTask) (string, error) {
+	ref, err := oci.ParseReference(source)
+	if err != nil {
+		return "", err
+	}
+	credentials, err := oci.LoadCredentials(ref.Registry)
+	if err != nil {
+		return "", err
+	}
+
+	importStatus(task, "正在拉取OCI制品: %s", ref)
+	archive, _, err := oci.NewClient(credentials).Pull(context.Background(), ref, filepath.Join(tmpDir, "oci"))
+	if err != nil {
+		return "", err
+	}
+	extractDir := filepath.Join(tmpDir, "extract")
+	if err := pack.Extract(archive, extractDir); err != nil {
+		return "", fmt.Errorf("解压失败: %w", err)
+	}
+	return extractDir, nil
+}
+
+// resolveRegistrySource 从HTTP技能注册表下载 <技能ID>[@版本] 形式的导入源，返回技能目录
+func resolveRegistrySource(source, tmpDir string, task *progressTask) (string, error) {
+	client, err := newRegistryClient()
+	if err != nil {
+		return "", err
+	}
+	skillID, version := source, ""
+	if i := strings.LastIndex(source, "@"); i > 0 {
+		skillID, version = source[:i], source[i+1:]
+	}
+	if client == nil || spec.ValidateSkillID(skillID) != nil {
+		return "", fmt.Errorf("无法识别的导入源: %s", source)
+	}
+
+	ctx := context.Background()
+	index, err := client.Index(ctx)
+	if err != nil {
+		return "", err
+	}
+	meta, ok := index.Find(skillID)
+	if !ok {
+		return "", fmt.Errorf("技能注册表 %s 中没有技能 %s", client.URL(), skillID)
+	}
+	if version != "" && version != meta.Version {
+		// index.json 只记录最新版本，旧版本按默认路径下载，只校验归档清单
+		if _, err := engine.ParseVersion(version); err != nil {
+			return "", fmt.Errorf("无效的版本号: %s", version)
+		}
+		meta = &spec.SkillMetadata{ID: skillID, Version: version}
+	}
+
+	importStatus(task, "正在从技能注册表下载: %s@%s", skillID, meta.Version)
+	var progress registry.ProgressFunc
+	if task != nil {
+		progress = task.SetBytes
+	}
+	return client.DownloadWithProgress(ctx, *meta, filepath.Join(tmpDir, "registry"), progress)
+}
+
+// normalizeGitURL 识别Git仓库地址，支持 github.com/owner/repo 简写
+func normalizeGitURL(source string) (string, bool) {
+	for _, prefix := range []string{"https://", "http://", "git@", "ssh://", "git://", "file://"} {
+		if strings.HasPrefix(source, prefix) {
+			return source, true
+		}
+	}
+	for _, host := range []string{"github.com/", "gitlab.com/", "gitee.com/"} {
+		if strings.HasPrefix(source, host) {
+			return "https://" + source, true
+		}
+	}
+	return "", false
+}
+
+// findSkillDirs 查找目录中的技能：目录本身、skills/子目录或一级子目录
+func findSkillDirs(root string) ([]string, error) {
+	if hasSkillMD(root) {
+		return []string{root}, nil
+	}
+
+	searchDir := root
+	if info, err := os.Stat(filepath.Join(root, "skills")); err == nil && info.IsDir() {
+		searchDir = filepath.Join(root, "skills")
+	}
+
+	entries, err := os.ReadDir(searchDir)
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %w", err)
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(searchDir, entry.Name())
+		if hasSkillMD(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) > 0 {
+		return dirs, nil
+	}
+	return findNestedSkillDirs(root)
+}
+
+// maxSkillSearchDepth 查找嵌套技能目录的最大深度
+const maxSkillSearchDepth = 4
+
+// findNestedSkillDirs 在更深的目录中查找技能，兼容 Claude Code 和社区技能仓库的布局，
+// 例如 .claude/skills/<技能>、document-skills/<技能> 和 plugins/<插件>/skills/<技能>。
+// 技能目录中的子目录不再查找
+func findNestedSkillDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(dir string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || dir == root {
+			return nil
+		}
+		name := d.Name()
+		if (strings.HasPrefix(name, ".") && name != ".claude") || name == "node_modules" {
+			return filepath.SkipDir
+		}
+		if hasSkillMD(dir) {
+			dirs = append(dirs, dir)
+			return filepath.SkipDir
+		}
+		if rel, _ := filepath.Rel(root, dir); strings.Count(rel, string(filepath.Separator)) >= maxSkillSearchDepth-1 {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查找技能目录失败: %w", err)
+	}
+	return dirs, nil
+}
+
+// hasSkillMD 检查目录是否包含SKILL.md
+func hasSkillMD(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "SKILL.md"))
+	return err == nil && !info.IsDir()
+}
+
+// importSkillID 确定导入技能的ID：优先使用清单中的ID（可带命名空间），其次使用目录名。
+//
+// sourceName 不为空时 dir 是解压归档或克隆仓库得到的临时目录，目录名没有意义，
+// 改为使用 SKILL.md 中的 name，name 缺失或不符合规范时使用归档或仓库名 sourceName。
+// explicit 表示ID由技能作者指定（清单或技能目录名），只有这时才按ID改写 SKILL.md 中的 name
+func importSkillID(dir, sourceName string) (id string, explicit bool, err error) {
+	if manifest, err := pack.ReadManifest(dir); err == nil && manifest != nil && manifest.SkillID != "" {
+		if spec.ValidateSkillID(manifest.SkillID) != nil {
+			return "", false, fmt.Errorf("清单中的技能ID无效: %s", manifest.SkillID)
+		}
+		return manifest.SkillID, true, nil
+	}
+
+	if sourceName == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", false, err
+		}
+		id = filepath.Base(abs)
+		if err := spec.ValidateSkillID(id); err != nil {
+			return "", false, err
+		}
+		return id, true, nil
+	}
+
+	if name := skillFrontmatterName(filepath.Join(dir, "SKILL.md")); name != "" && !strings.Contains(name, spec.NamespaceSeparator) && spec.ValidateSkillID(name) == nil {
+		return name, false, nil
+	}
+	if err := spec.ValidateSkillID(sourceName); err != nil {
+		return "", false, fmt.Errorf("无法确定技能ID: SKILL.md 中没有规范的 name，导入源名称 '%s' 也不是有效的技能ID", sourceName)
+	}
+	return sourceName, false, nil
+}
+
+// skillFrontmatterName 读取 SKILL.md frontmatter 中的 name，读取或解析失败时返回空字符串
+func skillFrontmatterName(mdPath string) string {
+	skill, err := engine.LoadSkillFile(mdPath, "")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(skill.Name)
+}
+
+// importSourceName 返回导入源的名称：归档去掉扩展名的文件名、仓库名或OCI制品名，
+// 例如 ./my-skill.tar.gz、https://github.com/org/my-skill.git 和
+// oci://ghcr.io/acme/my-skill:1.0.0 都是 my-skill
+func importSourceName(source string) string {
+	name := strings.TrimRight(source, "/")
+	if i := strings.LastIndexAny(name, "/\\"); i >= 0 {
+		name = name[i+1:]
+	}
+	// 代码托管平台简写 gitlab:skills 和 OCI 标签 my-skill:1.0.0
+	if i := strings.Index(name, ":"); i >= 0 {
+		if strings.HasPrefix(source, oci.Scheme) {
+			name = name[:i]
+		} else {
+			name = name[i+1:]
+		}
+	}
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar", ".zip", ".git"} {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			name = name[:len(name)-len(ext)]
+			break
+		}
+	}
+	return strings.ToLower(name)
+}
+
+// convertImportedSkill 将 Claude Code / 社区格式的技能转换为规范格式，输出转换警告。
+// 需要转换时在 convertedRoot 下创建转换后的副本并返回其路径，不修改导入源；否则返回原目录。
+// explicit 为false时ID不是技能作者指定的，SKILL.md 中规范的 name 保持不变
+func convertImportedSkill(dir, skillID string, explicit bool, convertedRoot string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return "", fmt.Errorf("读取SKILL.md失败: %w", err)
+	}
+	converted, warnings, err := migrate.ConvertClaudeSkill(string(content), skillID, explicit)
+	if err != nil {
+		return "", fmt.Errorf("转换技能格式失败: %w", err)
+	}
+	if len(warnings) == 0 {
+		return dir, nil
+	}
+
+	target := filepath.Join(convertedRoot, spec.FlatSkillID(skillID))
+	if err := copyDir(dir, target); err != nil {
+		return "", fmt.Errorf("复制技能失败: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(target, "SKILL.md"), []byte(converted), 0644); err != nil {
+		return "", fmt.Errorf("写入SKILL.md失败: %w", err)
+	}
+	fmt.Println("🔄 已转换为 skill-hub 格式:")
+	for _, warning := range warnings {
+		fmt.Printf("  ⚠️  %s\n", warning)
+	}
+	return target, nil
+}
+
+// checkImportDependencies 检查待导入技能的依赖版本约束
+//
+// 依赖的技能在本次导入中时按导入的版本检查，否则按技能仓库中已安装的版本检查。
+// 依赖的技能尚未安装只给出提示；任一约束不满足时返回错误，不导入任何技能。
+func checkImportDependencies(skillDirs []string, sourceNames map[string]string, loadInstalled func(skillID string) (*spec.Skill, error)) error {
+	batch := make(map[string]*spec.Skill)
+	var ids []string
+	for _, dir := range skillDirs {
+		skillID, _, err := importSkillID(dir, sourceNames[dir])
+		if err != nil {
+			continue
+		}
+		skill, err := engine.LoadSkillFile(filepath.Join(dir, "SKILL.md"), skillID)
+		if err != nil {
+			continue
+		}
+		batch[skillID] = skill
+		ids = append(ids, skillID)
+	}
+	sort.Strings(ids)
+
+	var problems []string
+	for _, skillID := range ids {
+		for _, raw := range batch[skillID].Dependencies {
+			dep, err := engine.ParseDependency(raw)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", skillID, err))
+				continue
+			}
+			installed, ok := batch[dep.ID]
+			if !ok {
+				if installed, err = loadInstalled(dep.ID); err != nil {
+					fmt.Printf("⚠️  技能 %s 依赖的技能 %s 尚未安装\n", skillID, dep)
+					continue
+				}
+			}
+			if err := dep.CheckVersion(installed.Version); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", skillID, err))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		fmt.Println("❌ 以下技能的依赖不满足版本约束:")
+		for _, problem := range problems {
+			fmt.Printf("  - %s\n", problem)
+		}
+		return fmt.Errorf("%d 个依赖不满足版本约束，未导入任何技能", len(problems))
+	}
+	return nil
+}
+
+// checkImportRequirements 检查待导入技能的运行要求，任一技能不满足时返回错误，不导入任何技能
+func checkImportRequirements(skillDirs []string, sourceNames map[string]string) error {
+	var skills []*spec.Skill
+	for _, dir := range skillDirs {
+		skillID, _, err := importSkillID(dir, sourceNames[dir])
+		if err != nil {
+			continue
+		}
+		if skill, err := engine.LoadSkillFile(filepath.Join(dir, "SKILL.md"), skillID); err == nil {
+			skills = append(skills, skill)
+		}
+	}
+	if err := checkSkillRequirements(skills); err != nil {
+		return fmt.Errorf("%w，未导入任何技能", err)
+	}
+	return nil
+}
+
+// resolveImportConflict 处理技能ID冲突，返回最终ID以及是否继续导入
+//
+// roots 为按优先级排列的全部技能目录，任一目录中已有同名技能都视为冲突，重命名后的ID在所有目录中都不冲突。
+// 导入的技能总是安装到技能仓库（名为 repo 的技能目录），覆盖时更高优先级目录中的同名技能仍会生效。
+func resolveImportConflict(reader *bufio.Reader, roots []config.SkillRoot, skillID, strategy string) (string, bool, error) {
+	existing := skillRootsContaining(roots, skillID)
+	if len(existing) == 0 {
+		return skillID, true, nil
+	}
+
+	fmt.Printf("⚠️  技能 %s 已存在（技能目录: %s）\n", skillID, strings.Join(existing, ", "))
+
+	interactive := strategy == conflictAsk
+	if interactive {
+		fmt.Print("选择操作: [o]覆盖 / [r]重命名 / [S]跳过: ")
+		response, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(response)) {
+		case "o", "overwrite":
+			strategy = conflictOverwrite
+		case "r", "rename":
+			strategy = conflictRename
+		default:
+			strategy = conflictSkip
+		}
+	}
+
+	switch strategy {
+	case conflictOverwrite:
+		for _, root := range roots {
+			if root.Name == config.RepoRootName {
+				break
+			}
+			if len(skillRootsContaining([]config.SkillRoot{root}, skillID)) > 0 {
+				fmt.Printf("⚠️  技能目录 %s 中的 %s 优先级更高，导入到技能仓库的版本不会生效\n", root.Name, skillID)
+				break
+			}
+		}
+		return skillID, true, nil
+	case conflictSkip:
+		return "", false, nil
+	}
+
+	// 重命名：交互模式下询问新ID，否则自动生成不冲突的ID
+	suggested := nextAvailableID(roots, skillID)
+	if interactive {
+		for {
+			fmt.Printf("新的技能ID [%s]: ", suggested)
+			input, readErr := reader.ReadString('\n')
+			newID := strings.TrimSpace(input)
+			if newID == "" {
+				newID = suggested
+			}
+			if spec.ValidateSkillID(newID) != nil {
+				fmt.Println("❌ 技能ID只能包含小写字母、数字和连字符，命名空间格式为 namespace/name")
+			} else if len(skillRootsContaining(roots, newID)) > 0 {
+				fmt.Printf("❌ 技能 %s 也已存在\n", newID)
+			} else {
+				return newID, true, nil
+			}
+			if readErr != nil {
+				return "", false, fmt.Errorf("读取输入失败: %w", readErr)
+			}
+		}
+	}
+	return suggested, true, nil
+}
+
+// skillRootsContaining 返回已包含该技能ID的技能目录名称
+func skillRootsContaining(roots []config.SkillRoot, skillID string) []string {
+	var names []string
+	for _, root := range roots {
+		if _, err := os.Stat(filepath.Join(root.Path, skillID)); err == nil {
+			names = append(names, root.Name)
+		}
+	}
+	return names
+}
+
+// nextAvailableID 生成在所有技能目录中都不冲突的技能ID
+func nextAvailableID(roots []config.SkillRoot, skillID string) string {
+	candidate := skillID + "-imported"
+	for i := 2; ; i++ {
+		if len(skillRootsContaining(roots, candidate)) == 0 {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-imported-%d", skillID, i)
+	}
+}
+
+// installImportedSkill 将技能复制到技能目录，重命名时同步更新frontmatter中的name
+//
+// 技能先复制到同一目录下的临时目录并完成修改，再替换现有技能，复制失败时现有技能保持不变。
+func installImportedSkill(srcDir, skillsDir, skillID, targetID string) error {
+	dst := filepath.Join(skillsDir, targetID)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("创建技能目录失败: %w", err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".import-")
+	if err != nil {
+		return fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	staged := filepath.Join(tmp, "skill")
+	if err := copyDir(srcDir, staged); err != nil {
+		return err
+	}
+	if err := prepareImportedSkill(staged, skillID, targetID); err != nil {
+		return err
+	}
+
+	// 现有技能先移到临时目录，替换失败时移回
+	old := filepath.Join(tmp, "old")
+	if err := os.Rename(dst, old); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("替换现有技能失败: %w", err)
+	}
+	if err := os.Rename(staged, dst); err != nil {
+		if _, statErr := os.Stat(old); statErr == nil {
+			os.Rename(old, dst)
+		}
+		return fmt.Errorf("替换现有技能失败: %w", err)
+	}
+	return nil
+}
+
+// prepareImportedSkill 整理复制到 dir 的技能：删除传输清单，重命名时更新frontmatter中的name
+func prepareImportedSkill(dir, skillID, targetID string) error {
+	// 清单只用于传输校验，不保留在技能目录中
+	os.Remove(filepath.Join(dir, pack.ManifestFileName))
+
+	// name 与技能目录名一致，只比较不含命名空间的名称
+	_, name := spec.SplitSkillID(skillID)
+	_, targetName := spec.SplitSkillID(targetID)
+	if targetName == name {
+		return nil
+	}
+
+	mdPath := filepath.Join(dir, "SKILL.md")
+	data, err := os.ReadFile(mdPath)
+	if err != nil {
+		return err
+	}
+	namePattern := regexp.MustCompile(`(?m)^name:\s*["']?` + regexp.QuoteMeta(name) + `["']?\s*$`)
+	content := string(data)
+	if loc := namePattern.FindStringIndex(content); loc != nil {
+		content = content[:loc[0]] + "name: " + targetName + content[loc[1]:]
+	}
+	return os.WriteFile(mdPath, []byte(content), 0644)
+}
@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skill-hub/internal/config"
+	"skill-hub/pkg/spec"
+)
+
+// writeSkill 在目录中创建测试技能
+func writeSkill(t *testing.T, dir, name string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	content := "---\nname: " + name + "\ndescription: test\n---\n# " + name + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write SKILL.md: %v", err)
+	}
+}
+
+func TestFindSkillDirs(t *testing.T) {
+	t.Run("单个技能目录", func(t *testing.T) {
+		root := t.TempDir()
+		writeSkill(t, root, "single")
+		dirs, err := findSkillDirs(root)
+		if err != nil || len(dirs) != 1 || dirs[0] != root {
+			t.Errorf("findSkillDirs() = %v, %v", dirs, err)
+		}
+	})
+
+	t.Run("仓库的skills子目录", func(t *testing.T) {
+		root := t.TempDir()
+		writeSkill(t, filepath.Join(root, "skills", "a"), "a")
+		writeSkill(t, filepath.Join(root, "skills", "b"), "b")
+		os.MkdirAll(filepath.Join(root, "skills", "not-a-skill"), 0755)
+		dirs, err := findSkillDirs(root)
+		if err != nil || len(dirs) != 2 {
+			t.Errorf("findSkillDirs() = %v, %v", dirs, err)
+		}
+	})
+
+	t.Run("Claude Code和社区仓库的嵌套布局", func(t *testing.T) {
+		root := t.TempDir()
+		writeSkill(t, filepath.Join(root, ".claude", "skills", "a"), "a")
+		writeSkill(t, filepath.Join(root, "document-skills", "pdf"), "pdf")
+		writeSkill(t, filepath.Join(root, "document-skills", "pdf", "examples", "nested"), "nested")
+		writeSkill(t, filepath.Join(root, "plugins", "p", "skills", "b"), "b")
+		writeSkill(t, filepath.Join(root, ".git", "skills", "ignored"), "ignored")
+		dirs, err := findSkillDirs(root)
+		if err != nil || len(dirs) != 3 {
+			t.Errorf("findSkillDirs() = %v, %v", dirs, err)
+		}
+	})
+}
+
+func TestConvertImportedSkill(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "pdf")
+	os.MkdirAll(filepath.Join(src, "scripts"), 0755)
+	original := "---\nname: PDF Tools\ndescription: Extract text from PDF files.\n---\n# PDF\n"
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte(original), 0644)
+	os.WriteFile(filepath.Join(src, "scripts", "extract.py"), []byte("print(1)"), 0644)
+
+	convertedRoot := t.TempDir()
+	dir, err := convertImportedSkill(src, "pdf", true, convertedRoot)
+	if err != nil {
+		t.Fatalf("convertImportedSkill() error = %v", err)
+	}
+	if dir == src {
+		t.Fatal("convertImportedSkill() should return a converted copy")
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if !strings.Contains(string(content), "name: pdf\n") {
+		t.Errorf("converted SKILL.md = %s", content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "scripts", "extract.py")); err != nil {
+		t.Errorf("resources not copied: %v", err)
+	}
+	// 导入源不被修改
+	if data, _ := os.ReadFile(filepath.Join(src, "SKILL.md")); string(data) != original {
+		t.Errorf("source modified: %s", data)
+	}
+
+	native := filepath.Join(t.TempDir(), "native")
+	writeSkill(t, native, "native")
+	if dir, err := convertImportedSkill(native, "native", true, convertedRoot); err != nil || dir != native {
+		t.Errorf("convertImportedSkill(native) = %q, %v", dir, err)
+	}
+
+	// ID取自导入源名称时，规范的 name 不被改写
+	extracted := filepath.Join(t.TempDir(), "extract")
+	os.MkdirAll(extracted, 0755)
+	os.WriteFile(filepath.Join(extracted, "SKILL.md"), []byte("---\nname: my-skill\ndescription: test\nversion: 1.0.0\n---\n# My skill\n"), 0644)
+	dir, err = convertImportedSkill(extracted, "bundle", false, convertedRoot)
+	if err != nil {
+		t.Fatalf("convertImportedSkill(extracted) error = %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "SKILL.md")); !strings.Contains(string(content), "name: my-skill\n") {
+		t.Errorf("name should stay my-skill: %s", content)
+	}
+}
+
+func TestSplitSubPath(t *testing.T) {
+	for _, tt := range []struct{ source, base, sub string }{
+		{"https://github.com/org/repo//skills/git-expert", "https://github.com/org/repo", "skills/git-expert"},
+		{"git@github.com:org/repo.git//skills/a/", "git@github.com:org/repo.git", "skills/a"},
+		{"github.com/org/repo", "github.com/org/repo", ""},
+		{"./skills.tar.gz//git-expert", "./skills.tar.gz", "git-expert"},
+		{"oci://ghcr.io/acme/skills/demo:1.0.0", "oci://ghcr.io/acme/skills/demo:1.0.0", ""},
+	} {
+		base, sub, err := splitSubPath(tt.source)
+		if err != nil || base != tt.base || sub != tt.sub {
+			t.Errorf("splitSubPath(%q) = %q, %q, %v", tt.source, base, sub, err)
+		}
+	}
+	for _, source := range []string{"https://github.com/org/repo//", "https://github.com/org/repo//../etc", "//skills"} {
+		if _, _, err := splitSubPath(source); err == nil {
+			t.Errorf("splitSubPath(%q) expected error", source)
+		}
+	}
+}
+
+func TestFilterImportSkills(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a", "b", "c"} {
+		writeSkill(t, filepath.Join(root, "skills", name), name)
+	}
+	dirs, err := findSkillDirs(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selected, err := filterImportSkills(dirs, []string{"c", "a"}, nil)
+	if err != nil || len(selected) != 2 || filepath.Base(selected[0]) != "a" || filepath.Base(selected[1]) != "c" {
+		t.Errorf("filterImportSkills() = %v, %v", selected, err)
+	}
+	if _, err := filterImportSkills(dirs, []string{"a", "missing"}, nil); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("filterImportSkills() error = %v", err)
+	}
+}
+
+func TestImportSkillIDAtSourceRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// 归档根部的技能：解压目录名没有意义，使用 SKILL.md 中的 name
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	writeTarGz(t, archive, map[string]string{
+		"SKILL.md":       "---\nname: my-skill\ndescription: test\n---\n# My skill\n",
+		"scripts/run.sh": "echo ok\n",
+	})
+	root, err := resolveImportSource(archive, filepath.Join(tmpDir, "0"), nil)
+	if err != nil {
+		t.Fatalf("resolveImportSource() error = %v", err)
+	}
+	source := &importSource{source: archive, root: root}
+	dirs, err := source.skillDirs()
+	if err != nil || len(dirs) != 1 {
+		t.Fatalf("skillDirs() = %v, %v", dirs, err)
+	}
+	if id, explicit, err := importSkillID(dirs[0], source.nameFor(dirs[0])); err != nil || id != "my-skill" || explicit {
+		t.Errorf("importSkillID(archive root) = %q, %v, %v, want my-skill", id, explicit, err)
+	}
+
+	// 仓库根部的技能：name 不符合规范时使用仓库名
+	cloneDir := filepath.Join(tmpDir, "1", "clone")
+	if err := os.MkdirAll(cloneDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(cloneDir, "SKILL.md"), []byte("---\nname: Team Skill\ndescription: test\n---\n# Team\n"), 0644)
+	source = &importSource{source: "https://github.com/acme/team-skill.git", root: cloneDir}
+	if id, explicit, err := importSkillID(cloneDir, source.nameFor(cloneDir)); err != nil || id != "team-skill" || explicit {
+		t.Errorf("importSkillID(repo root) = %q, %v, %v, want team-skill", id, explicit, err)
+	}
+	source = &importSource{source: "https://github.com/acme/Team_Skills.git", root: cloneDir}
+	if _, _, err := importSkillID(cloneDir, source.nameFor(cloneDir)); err == nil {
+		t.Error("importSkillID() should reject an invalid repository name")
+	}
+
+	// 本地目录和子目录中的技能仍然使用目录名
+	local := filepath.Join(t.TempDir(), "local-skill")
+	writeSkill(t, local, "other-name")
+	source = &importSource{source: local, root: local}
+	if id, explicit, err := importSkillID(local, source.nameFor(local)); err != nil || id != "local-skill" || !explicit {
+		t.Errorf("importSkillID(local dir) = %q, %v, %v, want local-skill", id, explicit, err)
+	}
+}
+
+func TestImportSourceName(t *testing.T) {
+	for source, want := range map[string]string{
+		"./dist/my-skill.tar.gz":                   "my-skill",
+		"/tmp/My-Skill.ZIP":                        "my-skill",
+		"https://github.com/org/my-skill.git":      "my-skill",
+		"github.com/org/my-skill/":                 "my-skill",
+		"git@github.com:org/my-skill.git":          "my-skill",
+		"gitlab:my-skill":                          "my-skill",
+		"oci://ghcr.io/acme/skills/my-skill:1.0.0": "my-skill",
+		"acme/my-skill@1.2.0":                      "my-skill",
+	} {
+		if got := importSourceName(source); got != want {
+			t.Errorf("importSourceName(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
+
+// writeTarGz 创建包含指定文件的 tar.gz 归档
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveImportConflict(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeSkill(t, filepath.Join(skillsDir, "demo"), "demo")
+	// 其他技能目录中的技能同样视为冲突
+	teamDir := t.TempDir()
+	writeSkill(t, filepath.Join(teamDir, "team-only"), "team-only")
+	writeSkill(t, filepath.Join(teamDir, "demo-imported"), "demo-imported")
+	roots := []config.SkillRoot{{Name: "team", Path: teamDir}, {Name: config.RepoRootName, Path: skillsDir}}
+
+	tests := []struct {
+		name     string
+		skillID  string
+		strategy string
+		input    string
+		wantID   string
+		wantOK   bool
+	}{
+		{name: "无冲突", skillID: "fresh", strategy: conflictAsk, wantID: "fresh", wantOK: true},
+		{name: "覆盖", skillID: "demo", strategy: conflictOverwrite, wantID: "demo", wantOK: true},
+		{name: "跳过", skillID: "demo", strategy: conflictSkip, wantOK: false},
+		{name: "自动重命名", skillID: "demo", strategy: conflictRename, wantID: "demo-imported-2", wantOK: true},
+		{name: "其他技能目录中的冲突", skillID: "team-only", strategy: conflictSkip, wantOK: false},
+		{name: "交互重命名", skillID: "demo", strategy: conflictAsk, input: "r\nBad_ID\nmy-demo\n", wantID: "my-demo", wantOK: true},
+		{name: "交互默认跳过", skillID: "demo", strategy: conflictAsk, input: "\n", wantOK: false},
+		{name: "命名空间无冲突", skillID: "acme/demo", strategy: conflictAsk, wantID: "acme/demo", wantOK: true},
+		{name: "交互重命名到命名空间", skillID: "demo", strategy: conflictAsk, input: "r\nacme/x/y\nacme/demo\n", wantID: "acme/demo", wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+			id, ok, err := resolveImportConflict(reader, roots, tt.skillID, tt.strategy)
+			if err != nil {
+				t.Fatalf("resolveImportConflict() error = %v", err)
+			}
+			if ok != tt.wantOK || id != tt.wantID {
+				t.Errorf("resolveImportConflict() = %q, %v; want %q, %v", id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestInstallImportedSkill(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "demo")
+	writeSkill(t, src, "demo")
+	os.WriteFile(filepath.Join(src, "manifest.json"), []byte("{}"), 0644)
+
+	skillsDir := t.TempDir()
+	if err := installImportedSkill(src, skillsDir, "demo", "demo-copy"); err != nil {
+		t.Fatalf("installImportedSkill() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(skillsDir, "demo-copy", "SKILL.md"))
+	if err != nil {
+		t.Fatalf("SKILL.md not installed: %v", err)
+	}
+	if !strings.Contains(string(data), "name: demo-copy\n") {
+		t.Errorf("name not updated: %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(skillsDir, "demo-copy", "manifest.json")); !os.IsNotExist(err) {
+		t.Error("manifest.json should not be installed")
+	}
+
+	// 导入到命名空间时目录为 <命名空间>/<名称>，名称不变
+	if err := installImportedSkill(src, skillsDir, "acme/demo", "acme/demo"); err != nil {
+		t.Fatalf("installImportedSkill() error = %v", err)
+	}
+	data, err = os.ReadFile(filepath.Join(skillsDir, "acme", "demo", "SKILL.md"))
+	if err != nil {
+		t.Fatalf("SKILL.md not installed: %v", err)
+	}
+	if !strings.Contains(string(data), "name: demo\n") {
+		t.Errorf("name should stay demo: %s", data)
+	}
+
+	// 覆盖时替换整个技能目录，复制失败时保留现有技能
+	os.WriteFile(filepath.Join(skillsDir, "demo-copy", "stale.md"), []byte("old"), 0644)
+	if err := installImportedSkill(src, skillsDir, "demo", "demo-copy"); err != nil {
+		t.Fatalf("installImportedSkill() overwrite error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(skillsDir, "demo-copy", "stale.md")); !os.IsNotExist(err) {
+		t.Error("stale file should be replaced with the imported skill")
+	}
+	if err := installImportedSkill(filepath.Join(t.TempDir(), "missing"), skillsDir, "demo", "demo-copy"); err == nil {
+		t.Error("installImportedSkill() should fail for a missing source")
+	}
+	if _, err := os.Stat(filepath.Join(skillsDir, "demo-copy", "SKILL.md")); err != nil {
+		t.Errorf("existing skill removed after a failed overwrite: %v", err)
+	}
+	if entries, _ := os.ReadDir(skillsDir); len(entries) != 2 {
+		t.Errorf("temporary directories left in skills dir: %v", entries)
+	}
+}
+
+func TestCheckImportDependencies(t *testing.T) {
+	root := t.TempDir()
+	writeDependentSkill := func(name, version string, deps ...string) {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		content := "---\nname: " + name + "\ndescription: test\nmetadata:\n  version: " + version + "\n"
+		if len(deps) > 0 {
+			content += "  dependencies: " + strings.Join(deps, ",") + "\n"
+		}
+		content += "---\n# " + name + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write SKILL.md: %v", err)
+		}
+	}
+	installed := map[string]string{"git-expert": "1.4.0"}
+	loadInstalled := func(skillID string) (*spec.Skill, error) {
+		version, ok := installed[skillID]
+		if !ok {
+			return nil, fmt.Errorf("技能 '%s' 不存在", skillID)
+		}
+		return &spec.Skill{ID: skillID, Version: version}, nil
+	}
+
+	writeDependentSkill("base", "2.1.0")
+	writeDependentSkill("ok", "1.0.0", "git-expert@^1.2.0", "base@~2.1", "not-installed@^1.0.0")
+	if err := checkImportDependencies([]string{filepath.Join(root, "base"), filepath.Join(root, "ok")}, nil, loadInstalled); err != nil {
+		t.Errorf("checkImportDependencies() error = %v", err)
+	}
+
+	writeDependentSkill("too-new", "1.0.0", "git-expert@^2.0.0", "base@<2.0.0")
+	err := checkImportDependencies([]string{filepath.Join(root, "base"), filepath.Join(root, "too-new")}, nil, loadInstalled)
+	if err == nil || !strings.Contains(err.Error(), "2 个依赖不满足版本约束") {
+		t.Errorf("checkImportDependencies() error = %v", err)
+	}
+}
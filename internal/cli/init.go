@@ -11,23 +11,42 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 	"skill-hub/internal/git"
+	"skill-hub/internal/pack"
 	"skill-hub/internal/state"
 	"skill-hub/pkg/spec"
 )
 
+var (
+	initForce      bool
+	initStarter    string
+	initNoExamples bool
+	initBranch     string
+)
+
 var initCmd = &cobra.Command{
 	Use:   "init [git-url]",
 	Short: "初始化Skill Hub工作区",
 	Long: `初始化Skill Hub工作区，创建必要的配置文件和目录结构。
 
-如果提供了Git仓库URL，会克隆远程仓库到本地。
-如果没有提供URL，会创建一个空的本地仓库。`,
+如果提供了Git仓库URL，会克隆远程仓库到本地，--branch 指定克隆的分支（默认为远程仓库的默认分支），
+克隆的分支记录在配置文件的 git_branch 中，之后 update 从该分支拉取。
+如果没有提供URL，会创建一个空的本地仓库。
+
+使用 --starter 可从远程仓库导入一组入门技能到本地仓库。
+已存在的配置文件默认保留，使用 --force 重新生成。`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runInit(args)
 	},
 }
 
+func init() {
+	initCmd.Flags().BoolVar(&initForce, "force", false, "覆盖已存在的配置文件和同名入门技能")
+	initCmd.Flags().StringVar(&initStarter, "starter", "", "入门技能集的Git仓库URL")
+	initCmd.Flags().BoolVar(&initNoExamples, "no-examples", false, "不创建示例技能")
+	initCmd.Flags().StringVarP(&initBranch, "branch", "b", "", "克隆远程仓库的分支，默认为远程仓库的默认分支")
+}
+
 func runInit(args []string) error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -60,22 +79,11 @@ func runInit(args []string) error {
 		fmt.Printf("✓ 创建目录: %s\n", dir)
 	}
 
-	// 创建配置文件
+	// 创建配置文件（已存在时保留用户配置）
 	configPath := filepath.Join(skillHubDir, "config.yaml")
-	configContent := fmt.Sprintf(`# Skill Hub 配置文件
-repo_path: "~/.skill-hub/repo"
-claude_config_path: "~/.claude/config.json"
-cursor_config_path: "~/.cursor/rules"
-default_tool: "cursor"
-git_remote_url: "%s"
-git_token: ""
-git_branch: "main"
-`, gitURL)
-
-	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
-		return fmt.Errorf("创建配置文件失败: %w", err)
+	if err := writeInitConfig(configPath, gitURL, initForce); err != nil {
+		return err
 	}
-	fmt.Printf("✓ 创建配置文件: %s\n", configPath)
 
 	// 根据是否提供git_url执行不同的初始化逻辑
 	repoAlreadyValid := false
@@ -104,7 +112,7 @@ git_branch: "main"
 		}
 
 		// 克隆远程仓库
-		if err := tempRepo.Clone(gitURL); err != nil {
+		if err := tempRepo.CloneBranch(gitURL, initBranch); err != nil {
 			fmt.Printf("⚠️  克隆远程仓库失败: %v\n", err)
 			fmt.Println("\n故障排除建议:")
 			fmt.Println("1. 对于SSH URL (git@...):")
@@ -117,23 +125,30 @@ git_branch: "main"
 			fmt.Println("\n将创建本地空仓库")
 
 			// 如果克隆失败，创建本地空仓库
-			return initLocalEmptyRepository(repoDir, skillHubDir)
-		}
-
-		fmt.Println("✅ 远程技能仓库克隆完成")
+			if err := initLocalEmptyRepository(repoDir, skillHubDir); err != nil {
+				return err
+			}
+		} else {
+			fmt.Println("✅ 远程技能仓库克隆完成")
+			if branch := tempRepo.Branch(); branch != "" {
+				if err := updateConfigValue(configPath, "git_branch", branch); err != nil {
+					fmt.Printf("⚠️  记录分支失败: %v\n", err)
+				}
+			}
 
-		// 修复克隆后的目录结构（如果远程仓库包含嵌套的skills目录）
-		skillsDir := filepath.Join(repoDir, "skills")
-		if err := fixClonedRepositoryStructure(skillsDir); err != nil {
-			fmt.Printf("⚠️  调整目录结构失败: %v\n", err)
-		}
+			// 修复克隆后的目录结构（如果远程仓库包含嵌套的skills目录）
+			skillsDir := filepath.Join(repoDir, "skills")
+			if err := fixClonedRepositoryStructure(skillsDir); err != nil {
+				fmt.Printf("⚠️  调整目录结构失败: %v\n", err)
+			}
 
-		// 刷新技能索引
-		fmt.Println("\n正在刷新技能索引...")
-		if err := refreshSkillRegistry(repoDir); err != nil {
-			fmt.Printf("⚠️  刷新技能索引失败: %v\n", err)
-		} else {
-			fmt.Println("✓ 技能索引已刷新")
+			// 刷新技能索引
+			fmt.Println("\n正在刷新技能索引...")
+			if err := refreshSkillRegistry(repoDir); err != nil {
+				fmt.Printf("⚠️  刷新技能索引失败: %v\n", err)
+			} else {
+				fmt.Println("✓ 技能索引已刷新")
+			}
 		}
 
 	} else {
@@ -150,6 +165,13 @@ git_branch: "main"
 		}
 	}
 
+	// 导入入门技能集
+	if initStarter != "" {
+		if err := importStarterSkills(initStarter, repoDir, initForce); err != nil {
+			fmt.Printf("⚠️  导入入门技能失败: %v\n", err)
+		}
+	}
+
 	fmt.Println("\n✅ Skill Hub 初始化完成！")
 	fmt.Println("工作区位置:", skillHubDir)
 
@@ -225,13 +247,15 @@ func initLocalEmptyRepository(repoDir, skillHubDir string) error {
 	fmt.Println("✓ 初始化git仓库")
 
 	// 创建初始技能示例
-	if err := createExampleSkills(repoDir); err != nil {
-		return fmt.Errorf("创建示例技能失败: %w", err)
+	if !initNoExamples {
+		if err := createExampleSkills(repoDir); err != nil {
+			return fmt.Errorf("创建示例技能失败: %w", err)
+		}
 	}
 
-	// 创建初始registry.json
+	// 根据技能目录生成registry.json
 	registryPath := filepath.Join(repoDir, "registry.json")
-	if err := createInitialRegistry(registryPath); err != nil {
+	if err := refreshSkillRegistry(repoDir); err != nil {
 		return fmt.Errorf("创建技能索引失败: %w", err)
 	}
 	fmt.Printf("✓ 创建技能索引: %s\n", registryPath)
@@ -331,44 +355,6 @@ metadata:
 	return nil
 }
 
-// createInitialRegistry 创建初始技能索引
-func createInitialRegistry(registryPath string) error {
-	registryContent := `{
-  "version": "1.0.0",
-  "skills": [
-    {
-      "id": "git-expert",
-      "name": "Git 提交专家",
-      "version": "1.0.0",
-      "author": "skill-hub",
-      "description": "根据变更自动生成符合 Conventional Commits 规范的说明",
-      "tags": ["git", "workflow"],
-      "compatibility": {
-        "cursor": true,
-        "claude_code": false,
-        "open_code": false
-      }
-    },
-    {
-      "id": "claude-code-review",
-      "name": "Claude 代码审查助手",
-      "version": "1.0.0",
-      "author": "skill-hub",
-      "description": "专业的代码审查助手，帮助发现代码中的问题和改进点",
-      "tags": ["claude", "code-review", "quality"],
-      "compatibility": {
-        "cursor": false,
-        "claude_code": true,
-        "open_code": false
-      }
-    }
-  ]
-}
-`
-
-	return os.WriteFile(registryPath, []byte(registryContent), 0644)
-}
-
 // parseSkillMetadata 从SKILL.md文件解析技能元数据
 func parseSkillMetadata(mdPath, skillID string) (*spec.SkillMetadata, error) {
 	content, err := os.ReadFile(mdPath)
@@ -415,10 +401,14 @@ func parseSkillMetadata(mdPath, skillID string) (*spec.SkillMetadata, error) {
 		skillMeta.Description = desc
 	}
 
-	// 设置版本
+	// 设置版本（标准格式位于metadata.version，兼容根级别的version）
 	skillMeta.Version = "1.0.0"
 	if version, ok := skillData["version"].(string); ok {
 		skillMeta.Version = version
+	} else if metadata, ok := skillData["metadata"].(map[string]interface{}); ok {
+		if version, ok := metadata["version"]; ok && version != nil {
+			skillMeta.Version = fmt.Sprint(version)
+		}
 	}
 
 	// 设置作者
@@ -438,29 +428,20 @@ func parseSkillMetadata(mdPath, skillID string) (*spec.SkillMetadata, error) {
 		}
 	}
 
-	// 设置兼容性
+	// 设置兼容性，对象格式（向后兼容）转换为说明文字
 	if compatData, ok := skillData["compatibility"]; ok {
-		switch v := compatData.(type) {
-		case string:
-			skillMeta.Compatibility = v
-		case map[string]interface{}:
-			// 向后兼容：将对象格式转换为字符串
-			var compatList []string
-			if cursorVal, ok := v["cursor"].(bool); ok && cursorVal {
-				compatList = append(compatList, "Cursor")
-			}
-			if claudeVal, ok := v["claude_code"].(bool); ok && claudeVal {
-				compatList = append(compatList, "Claude Code")
-			}
-			if openCodeVal, ok := v["open_code"].(bool); ok && openCodeVal {
-				compatList = append(compatList, "OpenCode")
-			}
-			if shellVal, ok := v["shell"].(bool); ok && shellVal {
-				compatList = append(compatList, "Shell")
-			}
-			if len(compatList) > 0 {
-				skillMeta.Compatibility = "Designed for " + strings.Join(compatList, ", ") + " (or similar AI coding assistants)"
-			}
+		skillMeta.Compatibility = spec.CompatibilityDescription(compatData)
+	}
+
+	// 设置许可证
+	if license, ok := skillData["license"].(string); ok {
+		skillMeta.License = license
+	}
+
+	// 设置发布通道
+	if metadata, ok := skillData["metadata"].(map[string]interface{}); ok {
+		if channel, ok := metadata["channel"].(string); ok {
+			skillMeta.Channel = strings.ToLower(strings.TrimSpace(channel))
 		}
 	}
 
@@ -515,27 +496,19 @@ func refreshSkillRegistry(repoDir string) error {
 	}
 
 	var skills []spec.SkillMetadata
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		skillID := entry.Name()
-		skillDir := filepath.Join(skillsDir, skillID)
-		skillMdPath := filepath.Join(skillDir, "SKILL.md")
-
-		// 检查是否存在SKILL.md文件
-		if _, err := os.Stat(skillMdPath); os.IsNotExist(err) {
-			continue
-		}
-
+	for _, skillID := range collectSkillIDs(skillsDir, entries) {
 		// 解析SKILL.md文件
-		skillMeta, err := parseSkillMetadata(skillMdPath, skillID)
+		skillMeta, err := parseSkillMetadata(filepath.Join(skillsDir, skillID, "SKILL.md"), skillID)
 		if err != nil {
 			fmt.Printf("⚠️  解析技能 %s 失败: %v\n", skillID, err)
 			continue
 		}
 
+		// 记录内容摘要，导入和更新时据此校验
+		if digest, err := pack.SkillDigest(filepath.Join(skillsDir, skillID)); err == nil {
+			skillMeta.SHA256 = digest
+		}
+
 		skills = append(skills, *skillMeta)
 	}
 
@@ -544,6 +517,9 @@ func refreshSkillRegistry(repoDir string) error {
 		Version: "1.0.0",
 		Skills:  skills,
 	}
+	if previous, err := readRegistry(registryPath); err == nil {
+		registry.CarryStats(previous)
+	}
 
 	// 转换为JSON
 	registryJSON, err := json.MarshalIndent(registry, "", "  ")
@@ -560,6 +536,40 @@ func refreshSkillRegistry(repoDir string) error {
 	return nil
 }
 
+// collectSkillIDs 收集技能目录中的技能ID
+//
+// 不含SKILL.md的目录视为命名空间，其中的技能ID为 <命名空间>/<名称>。
+func collectSkillIDs(skillsDir string, entries []os.DirEntry) []string {
+	var skillIDs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		skillID := entry.Name()
+		skillDir := filepath.Join(skillsDir, skillID)
+
+		// 检查是否存在SKILL.md文件
+		if _, err := os.Stat(filepath.Join(skillDir, "SKILL.md")); err == nil {
+			skillIDs = append(skillIDs, skillID)
+			continue
+		}
+		if spec.ValidateSkillID(skillID) != nil {
+			continue
+		}
+		nsEntries, err := os.ReadDir(skillDir)
+		if err != nil {
+			continue
+		}
+		for _, nsEntry := range nsEntries {
+			if _, err := os.Stat(filepath.Join(skillDir, nsEntry.Name(), "SKILL.md")); err == nil {
+				skillIDs = append(skillIDs, spec.JoinSkillID(skillID, nsEntry.Name()))
+			}
+		}
+	}
+	return skillIDs
+}
+
 // fixClonedRepositoryStructure 修复克隆后的仓库目录结构
 // 处理远程仓库克隆到 ~/.skill-hub/repo/skills/ 后产生的问题：
 // 1. 嵌套的 skills/skills/ 目录
@@ -660,3 +670,197 @@ func fixClonedRepositoryStructure(skillsDir string) error {
 
 	return nil
 }
+
+// writeInitConfig 写入默认配置文件
+// 配置文件已存在时保留用户配置，仅在提供了Git URL时更新远程仓库地址
+func writeInitConfig(configPath, gitURL string, force bool) error {
+	if _, err := os.Stat(configPath); err == nil && !force {
+		if gitURL == "" {
+			fmt.Printf("ℹ️  配置文件已存在，保留现有配置: %s (使用 --force 重新生成)\n", configPath)
+			return nil
+		}
+		if err := updateConfigValue(configPath, "git_remote_url", gitURL); err != nil {
+			return fmt.Errorf("更新配置文件失败: %w", err)
+		}
+		fmt.Printf("✓ 更新配置文件远程仓库地址: %s\n", configPath)
+		return nil
+	}
+
+	configContent := fmt.Sprintf(`# Skill Hub 配置文件
+repo_path: "~/.skill-hub/repo"
+claude_config_path: "~/.claude/config.json"
+cursor_config_path: "~/.cursor/rules"
+default_tool: "cursor"
+git_remote_url: "%s"
+# 访问私有仓库的token；也可以使用 'skill-hub login <主机>' 按主机保存
+git_token: ""
+git_branch: "main"
+
+# 额外的技能目录，按优先级从高到低排列，同名技能使用优先级高的目录中的版本。
+# 名为 repo 的条目表示技能仓库，未列出时排在最后；相对路径相对于当前项目目录。
+# skill_roots:
+#   - name: project
+#     path: "./skills"
+#   - name: personal
+#     path: "~/.skill-hub/skills"
+#   - name: team
+#     path: "/mnt/shared/team-skills"
+
+# 技能 resources/ 目录中的文件在项目中的安装目录（相对项目根目录）和安装方式：
+# copy 复制文件，link 创建指向技能仓库的符号链接
+resources_dir: ".skill-hub/resources"
+resources_mode: "copy"
+
+# 应用到同一目标工具的全部技能合计的token预算，超过时 apply 会给出警告，0 表示不检查。
+# 未设置时 cursor 和 claude_code 默认为 8000
+# token_budgets:
+#   cursor: 6000
+#   claude_code: 12000
+
+# search 和 import 使用的代码托管平台，支持 github、gitlab 和 gitea（包括自建实例），
+# 未设置时使用 github.com 和 gitlab.com；导入时可以使用 <name>:<owner>/<repo> 简写
+# forges:
+#   - name: corp
+#     type: gitlab
+#     url: "https://gitlab.example.com"
+#     token: ""
+
+# 静态HTTP技能注册表（index.json + 技能归档，由 'skill-hub registry build' 生成），
+# 设置后 update、import、search 和 outdated 通过它获取技能，不需要git
+# registry_url: "https://skills.example.com"
+# registry_token: ""
+# registry_mirrors:
+#   - "https://mirror.internal/skills"
+
+# 离线模式，与 --offline 相同：search、update 和 import 只使用缓存的注册表索引和技能归档
+# offline: false
+`, gitURL)
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		return fmt.Errorf("创建配置文件失败: %w", err)
+	}
+	fmt.Printf("✓ 创建配置文件: %s\n", configPath)
+	return nil
+}
+
+// updateConfigValue 更新YAML配置文件中的单个字段，保留其他字段和注释
+func updateConfigValue(configPath, key, value string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("解析配置文件失败: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("配置文件格式无效")
+	}
+
+	setYAMLMapValue(doc.Content[0], key, value)
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return fmt.Errorf("序列化配置文件失败: %w", err)
+	}
+	return os.WriteFile(configPath, out, 0644)
+}
+
+// importStarterSkills 从远程仓库导入入门技能到本地技能目录
+func importStarterSkills(url, repoDir string, force bool) error {
+	fmt.Printf("\n正在导入入门技能: %s\n", url)
+
+	tmpDir, err := os.MkdirTemp("", "skill-hub-starter-")
+	if err != nil {
+		return fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cloneDir := filepath.Join(tmpDir, "repo")
+	if _, err := git.CloneInto(url, cloneDir); err != nil {
+		return err
+	}
+
+	// 入门仓库可以把技能放在skills/子目录或仓库根目录
+	srcDir := filepath.Join(cloneDir, "skills")
+	if info, err := os.Stat(srcDir); err != nil || !info.IsDir() {
+		srcDir = cloneDir
+	}
+
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return fmt.Errorf("读取入门技能目录失败: %w", err)
+	}
+
+	skillsDir := filepath.Join(repoDir, "skills")
+	imported := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		src := filepath.Join(srcDir, entry.Name())
+		if _, err := os.Stat(filepath.Join(src, "SKILL.md")); err != nil {
+			continue
+		}
+
+		dst := filepath.Join(skillsDir, entry.Name())
+		if _, err := os.Stat(dst); err == nil {
+			if !force {
+				fmt.Printf("⚠️  技能 %s 已存在，跳过 (使用 --force 覆盖)\n", entry.Name())
+				continue
+			}
+			if err := os.RemoveAll(dst); err != nil {
+				return fmt.Errorf("删除现有技能 %s 失败: %w", entry.Name(), err)
+			}
+		}
+
+		if err := copyDir(src, dst); err != nil {
+			return fmt.Errorf("复制技能 %s 失败: %w", entry.Name(), err)
+		}
+		fmt.Printf("✓ 导入技能: %s\n", entry.Name())
+		imported++
+	}
+
+	if imported == 0 {
+		fmt.Println("ℹ️  没有导入任何入门技能")
+		return nil
+	}
+
+	if err := refreshSkillRegistry(repoDir); err != nil {
+		return fmt.Errorf("刷新技能索引失败: %w", err)
+	}
+	fmt.Printf("✅ 成功导入 %d 个入门技能\n", imported)
+	return nil
+}
+
+// copyDir 递归复制目录（跳过.git）
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteInitConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	t.Run("创建默认配置", func(t *testing.T) {
+		if err := writeInitConfig(configPath, "", false); err != nil {
+			t.Fatalf("writeInitConfig() error = %v", err)
+		}
+		data, _ := os.ReadFile(configPath)
+		if !strings.Contains(string(data), `repo_path: "~/.skill-hub/repo"`) {
+			t.Errorf("default config missing repo_path: %s", data)
+		}
+	})
+
+	t.Run("保留已有配置", func(t *testing.T) {
+		custom := "# my config\nrepo_path: /custom/repo\ngit_token: secret\n"
+		os.WriteFile(configPath, []byte(custom), 0644)
+
+		if err := writeInitConfig(configPath, "", false); err != nil {
+			t.Fatalf("writeInitConfig() error = %v", err)
+		}
+		data, _ := os.ReadFile(configPath)
+		if string(data) != custom {
+			t.Errorf("config overwritten: %s", data)
+		}
+	})
+
+	t.Run("仅更新远程仓库地址", func(t *testing.T) {
+		if err := writeInitConfig(configPath, "https://example.com/skills.git", false); err != nil {
+			t.Fatalf("writeInitConfig() error = %v", err)
+		}
+		data, _ := os.ReadFile(configPath)
+		content := string(data)
+		for _, want := range []string{"git_token: secret", "repo_path: /custom/repo", "git_remote_url: https://example.com/skills.git", "# my config"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("config missing %q: %s", want, content)
+			}
+		}
+	})
+
+	t.Run("强制重新生成", func(t *testing.T) {
+		if err := writeInitConfig(configPath, "", true); err != nil {
+			t.Fatalf("writeInitConfig() error = %v", err)
+		}
+		data, _ := os.ReadFile(configPath)
+		if strings.Contains(string(data), "secret") {
+			t.Errorf("config not regenerated: %s", data)
+		}
+	})
+}
+
+func TestCopyDir(t *testing.T) {
+	src := t.TempDir()
+	dst := filepath.Join(t.TempDir(), "copy")
+
+	os.MkdirAll(filepath.Join(src, "scripts"), 0755)
+	os.MkdirAll(filepath.Join(src, ".git"), 0755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("skill"), 0644)
+	os.WriteFile(filepath.Join(src, "scripts", "run.sh"), []byte("echo"), 0755)
+	os.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref"), 0644)
+
+	if err := copyDir(src, dst); err != nil {
+		t.Fatalf("copyDir() error = %v", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dst, "SKILL.md")); err != nil || string(data) != "skill" {
+		t.Errorf("SKILL.md not copied: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "scripts", "run.sh"))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("script not copied with exec bit: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".git")); !os.IsNotExist(err) {
+		t.Error(".git should not be copied")
+	}
+}
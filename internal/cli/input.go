@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// noInput --no-input 全局标志
+var noInput bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noInput, "no-input", false, "不读取标准输入：需要确认或输入时使用默认选择，用于脚本和钩子")
+}
+
+// newInputReader 返回读取用户输入的reader，使用 --no-input 时不读取标准输入，每次读取都得到空输入（默认选择）
+func newInputReader() *bufio.Reader {
+	if noInput {
+		return bufio.NewReader(strings.NewReader(""))
+	}
+	return bufio.NewReader(os.Stdin)
+}
+
+// canPrompt 检查是否可以在终端中询问用户：--no-input、--json 和 --quiet 时提示不可见或不读取输入，
+// 标准输入不是终端时也不询问，均使用默认选择
+func canPrompt() bool {
+	return !noInput && !outputJSON && !outputQuiet && isTerminal(os.Stdin)
+}
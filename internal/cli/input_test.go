@@ -0,0 +1,17 @@
+package cli
+
+import "testing"
+
+func TestNoInput(t *testing.T) {
+	saved := noInput
+	noInput = true
+	t.Cleanup(func() { noInput = saved })
+
+	if canPrompt() {
+		t.Error("canPrompt() = true with --no-input")
+	}
+	line, err := newInputReader().ReadString('\n')
+	if line != "" || err == nil {
+		t.Errorf("ReadString() = %q, %v, want empty input", line, err)
+	}
+}
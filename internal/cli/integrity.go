@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/pack"
+	"skill-hub/pkg/spec"
+)
+
+// loadRegistryDigests 读取技能仓库根目录下 registry.json 记录的内容摘要，返回技能ID到摘要的映射
+//
+// 没有 registry.json 或技能没有记录摘要时不校验。
+func loadRegistryDigests(root string) (map[string]string, error) {
+	registry, err := readRegistry(filepath.Join(root, "registry.json"))
+	if err != nil || registry == nil {
+		return nil, err
+	}
+
+	digests := make(map[string]string)
+	for _, skill := range registry.Skills {
+		if skill.SHA256 == "" || spec.ValidateSkillID(skill.ID) != nil {
+			continue
+		}
+		digests[skill.ID] = skill.SHA256
+	}
+	return digests, nil
+}
+
+// readRegistry 读取注册表文件，文件不存在时返回nil
+func readRegistry(path string) (*spec.Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取registry.json失败: %w", err)
+	}
+	return spec.ParseRegistry(data)
+}
+
+// verifyRegistryDigests 校验技能仓库中的技能与 registry.json 记录的摘要是否一致，返回不一致的技能及原因
+func verifyRegistryDigests(root string) ([]string, error) {
+	digests, err := loadRegistryDigests(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for id, digest := range digests {
+		dir := registrySkillDir(root, id)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			problems = append(problems, fmt.Sprintf("%s: 技能目录缺失", id))
+			continue
+		}
+		if err := pack.VerifyDigest(dir, digest); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", id, err))
+		}
+	}
+	sort.Strings(problems)
+	return problems, nil
+}
+
+// registrySkillDir 返回技能仓库中技能的目录
+func registrySkillDir(root, skillID string) string {
+	return filepath.Join(root, "skills", filepath.FromSlash(skillID))
+}
+
+// registryDigest 返回 registry.json 中为技能目录记录的摘要，目录不在技能仓库的 skills 下时返回false
+func registryDigest(root, dir string, digests map[string]string) (string, bool) {
+	rel, err := filepath.Rel(filepath.Join(root, "skills"), dir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	digest, ok := digests[filepath.ToSlash(rel)]
+	return digest, ok
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/internal/pack"
+	"skill-hub/pkg/spec"
+)
+
+func TestVerifyRegistryDigests(t *testing.T) {
+	root := t.TempDir()
+	writeSkill := func(id, body string) string {
+		dir := registrySkillDir(root, id)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		content := "---\nname: " + filepath.Base(dir) + "\ndescription: test\n---\n" + body
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write SKILL.md: %v", err)
+		}
+		return dir
+	}
+	digestOf := func(dir string) string {
+		digest, err := pack.SkillDigest(dir)
+		if err != nil {
+			t.Fatalf("SkillDigest() error = %v", err)
+		}
+		return digest
+	}
+
+	good := writeSkill("good", "# Good\n")
+	namespaced := writeSkill("acme/tool", "# Tool\n")
+	tampered := writeSkill("tampered", "# Tampered\n")
+	registry := spec.Registry{Version: "1.0.0", Skills: []spec.SkillMetadata{
+		{ID: "good", SHA256: digestOf(good)},
+		{ID: "acme/tool", SHA256: digestOf(namespaced)},
+		{ID: "tampered", SHA256: digestOf(tampered)},
+		{ID: "missing", SHA256: digestOf(good)},
+		{ID: "unsigned"},
+	}}
+	os.WriteFile(filepath.Join(tampered, "SKILL.md"), []byte("---\nname: tampered\n---\n# Evil\n"), 0644)
+
+	for name, marshal := range map[string]func(interface{}) ([]byte, error){"json": json.Marshal, "yaml": yaml.Marshal} {
+		t.Run(name, func(t *testing.T) {
+			data, err := marshal(registry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(root, "registry.json"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			problems, err := verifyRegistryDigests(root)
+			if err != nil {
+				t.Fatalf("verifyRegistryDigests() error = %v", err)
+			}
+			if len(problems) != 2 || !strings.HasPrefix(problems[0], "missing:") || !strings.HasPrefix(problems[1], "tampered:") {
+				t.Errorf("verifyRegistryDigests() = %v", problems)
+			}
+
+			digests, _ := loadRegistryDigests(root)
+			if _, ok := registryDigest(root, namespaced, digests); !ok {
+				t.Error("registryDigest() should find namespaced skill")
+			}
+			if _, ok := registryDigest(root, root, digests); ok {
+				t.Error("registryDigest() should ignore directories outside skills/")
+			}
+		})
+	}
+
+	t.Run("No registry", func(t *testing.T) {
+		problems, err := verifyRegistryDigests(t.TempDir())
+		if err != nil || len(problems) != 0 {
+			t.Errorf("verifyRegistryDigests() = %v, %v", problems, err)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/internal/template"
+	"skill-hub/pkg/promptlint"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintMaxTokens int
+	lintStrict    bool
+)
+
+var lintPromptCmd = &cobra.Command{
+	Use:   "lint-prompt [skill-id...]",
+	Short: "分析技能提示词的质量问题",
+	Long: `对技能提示词进行静态分析，检查LLM提示词特有的问题：
+
+  - 提示词过长（按估算的token数）
+  - 相互矛盾的指令（同一内容既被要求又被禁止）
+  - 缺少角色设定
+  - 未替换的占位符（无值的变量、格式错误的 {{ }}、TODO 等）
+  - 多个技能之间重复的章节
+
+不指定技能ID时检查当前项目已启用的全部技能，并使用项目中配置的变量值。`,
+	ValidArgsFunction: completeSkillIDList,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLintPrompt(args)
+	},
+}
+
+func init() {
+	lintPromptCmd.Flags().IntVar(&lintMaxTokens, "max-tokens", promptlint.DefaultMaxTokens, "单个提示词的token上限")
+	lintPromptCmd.Flags().BoolVar(&lintStrict, "strict", false, "严格模式：存在警告时返回错误")
+}
+
+func runLintPrompt(skillIDs []string) error {
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+
+	// 项目中配置的变量值
+	projectVars := map[string]map[string]string{}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	if stateMgr, err := state.NewStateManager(); err == nil {
+		if skills, err := stateMgr.GetProjectSkills(cwd); err == nil {
+			for id, skillVars := range skills {
+				projectVars[id] = skillVars.Variables
+			}
+		}
+	}
+
+	if len(skillIDs) == 0 {
+		for id := range projectVars {
+			skillIDs = append(skillIDs, id)
+		}
+		if len(skillIDs) == 0 {
+			fmt.Println("ℹ️  当前项目未启用任何技能")
+			fmt.Println("使用 'skill-hub lint-prompt <skill-id>' 检查指定技能")
+			return nil
+		}
+		sort.Strings(skillIDs)
+	}
+
+	prompts := map[string]string{}
+	findings := map[string][]promptlint.Finding{}
+	for _, id := range skillIDs {
+		skill, err := skillManager.LoadSkill(id)
+		if err != nil {
+			return fmt.Errorf("加载技能 %s 失败: %w", id, err)
+		}
+		prompt, err := skillManager.GetSkillPrompt(id)
+		if err != nil {
+			return err
+		}
+		prompts[id] = prompt
+
+		// 变量值优先使用项目配置，其次使用声明的默认值
+		vars := map[string]string{}
+		for _, v := range skill.Variables {
+			vars[v.Name] = v.Default
+		}
+		for name, value := range projectVars[id] {
+			vars[name] = value
+		}
+		// 内置变量由 apply 按目标工具注入，检查时视为已设置
+		vars = template.WithContext(vars, spec.TargetAll, "project")
+
+		findings[id] = promptlint.Lint(prompt, promptlint.Options{
+			MaxTokens: lintMaxTokens,
+			Variables: vars,
+		})
+	}
+
+	if len(skillIDs) > 1 {
+		for id, dup := range promptlint.FindDuplicateSections(prompts) {
+			findings[id] = append(findings[id], dup...)
+		}
+	}
+
+	type lintResult struct {
+		SkillID  string               `json:"skill_id"`
+		Tokens   int                  `json:"tokens"`
+		Findings []promptlint.Finding `json:"findings"`
+	}
+	results := make([]lintResult, 0, len(skillIDs))
+
+	warnings, infos, totalTokens := 0, 0, 0
+	for _, id := range skillIDs {
+		body, _ := promptlint.StripFrontmatter(prompts[id])
+		tokens := promptlint.EstimateTokens(body)
+		totalTokens += tokens
+		results = append(results, lintResult{SkillID: id, Tokens: tokens, Findings: findings[id]})
+
+		fmt.Printf("\n=== %s (约 %d tokens) ===\n", id, tokens)
+		if len(findings[id]) == 0 {
+			fmt.Println("✅ 未发现问题")
+			continue
+		}
+		for _, f := range findings[id] {
+			if f.Severity == promptlint.SeverityWarning {
+				warnings++
+				fmt.Printf("  ⚠️  %s\n", f)
+			} else {
+				infos++
+				fmt.Printf("  ℹ️  %s\n", f)
+			}
+		}
+	}
+
+	fmt.Printf("\n检查 %d 个技能，合计约 %d tokens: %d 个警告, %d 个提示\n", len(skillIDs), totalTokens, warnings, infos)
+	setResult(results)
+
+	if lintStrict && warnings > 0 {
+		return fmt.Errorf("严格模式下发现 %d 个警告", warnings)
+	}
+	return nil
+}
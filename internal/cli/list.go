@@ -5,19 +5,45 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"skill-hub/internal/config"
 	"skill-hub/internal/engine"
+	"skill-hub/pkg/spec"
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "列出所有可用技能",
-	Long:  "列出本地技能仓库中的所有可用技能，显示状态、版本和适用工具。",
+	Long: `列出所有技能目录中的可用技能，显示版本、适用工具和来源。
+
+配置了多个技能目录 (skill_roots) 时，同名技能使用优先级最高的目录中的版本，
+被覆盖的目录在列表末尾说明。
+
+使用 --tag 只列出带有指定标签的技能，多次指定时需同时带有所有标签：
+  skill-hub list --tag golang --tag testing
+
+使用 --remote 列出远程仓库注册表（registry.json）中的技能及下载次数和社区评分，
+统计数据随 'skill-hub update' 从远程仓库同步。`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runList()
 	},
 }
 
+var (
+	listTags   []string
+	listRemote bool
+)
+
+func init() {
+	listCmd.Flags().StringArrayVar(&listTags, "tag", nil, "只列出带有该标签的技能（可多次指定）")
+	listCmd.Flags().BoolVar(&listRemote, "remote", false, "列出远程仓库注册表中的技能及下载次数和评分")
+	listCmd.RegisterFlagCompletionFunc("tag", completeTags)
+}
+
 func runList() error {
+	if listRemote {
+		return runListRemote()
+	}
+
 	manager, err := engine.NewSkillManager()
 	if err != nil {
 		return err
@@ -27,31 +53,47 @@ func runList() error {
 	if err != nil {
 		return err
 	}
+	skills = filterSkillsByTags(skills, listTags)
+
+	type listItem struct {
+		ID       string   `json:"id"`
+		Name     string   `json:"name"`
+		Version  string   `json:"version"`
+		License  string   `json:"license,omitempty"`
+		Tags     []string `json:"tags,omitempty"`
+		Targets  []string `json:"targets"`
+		Source   string   `json:"source"`
+		Shadowed []string `json:"shadowed,omitempty"` // 被覆盖的低优先级技能目录
+	}
+	items := make([]listItem, 0, len(skills))
+	defer func() { setResult(items) }()
 
 	if len(skills) == 0 {
+		if len(listTags) > 0 {
+			fmt.Printf("ℹ️  没有带有标签 %s 的技能，使用 'skill-hub tags' 查看所有标签\n", strings.Join(listTags, ", "))
+			return nil
+		}
 		fmt.Println("ℹ️  未找到任何技能")
 		fmt.Println("使用 'skill-hub init' 初始化技能仓库")
 		return nil
 	}
 
 	fmt.Println("可用技能列表:")
-	fmt.Println("ID          名称                版本      适用工具")
-	fmt.Println("--------------------------------------------------")
+	fmt.Println("ID          名称                版本      许可证        适用工具              来源")
+	fmt.Println("------------------------------------------------------------------------------------")
+
+	var shadowed []string
 
 	for _, skill := range skills {
-		tools := []string{}
-		compatLower := strings.ToLower(skill.Compatibility)
-		if strings.Contains(compatLower, "cursor") {
-			tools = append(tools, "cursor")
-		}
-		if strings.Contains(compatLower, "claude code") || strings.Contains(compatLower, "claude_code") {
-			tools = append(tools, "claude_code")
-		}
-		if strings.Contains(compatLower, "shell") {
-			tools = append(tools, "shell")
+		tools := skill.Compatible().Targets()
+		if tools == nil {
+			tools = []string{}
 		}
-		if strings.Contains(compatLower, "opencode") || strings.Contains(compatLower, "open_code") {
-			tools = append(tools, "open_code")
+
+		origin, _ := manager.Origin(skill.ID)
+		items = append(items, listItem{ID: skill.ID, Name: skill.Name, Version: skill.Version, License: skill.License, Tags: skill.Tags, Targets: tools, Source: origin.Root, Shadowed: origin.Shadowed})
+		if len(origin.Shadowed) > 0 {
+			shadowed = append(shadowed, fmt.Sprintf("%s: %s 覆盖 %s", skill.ID, origin.Root, strings.Join(origin.Shadowed, ", ")))
 		}
 
 		toolsStr := ""
@@ -62,13 +104,65 @@ func runList() error {
 			}
 		}
 
-		fmt.Printf("%-12s %-20s %-10s %s\n",
+		license := skill.License
+		if license == "" {
+			license = "-"
+		}
+
+		fmt.Printf("%-12s %-20s %-10s %-13s %-21s %s\n",
 			skill.ID,
 			skill.Name,
 			skill.Version,
-			toolsStr)
+			license,
+			toolsStr,
+			origin.Root)
+	}
+
+	if len(shadowed) > 0 {
+		fmt.Println("\n📌 同名技能覆盖:")
+		for _, line := range shadowed {
+			fmt.Printf("   %s\n", line)
+		}
 	}
 
 	fmt.Println("\n使用 'skill-hub use <skill-id>' 在当前项目启用技能")
 	return nil
 }
+
+// runListRemote 列出远程仓库注册表中的技能及使用统计
+func runListRemote() error {
+	registryPath, err := config.GetRegistryPath()
+	if err != nil {
+		return err
+	}
+	registry, err := readRegistry(registryPath)
+	if err != nil {
+		return err
+	}
+
+	items := make([]spec.SkillMetadata, 0)
+	if registry != nil {
+		for _, skill := range registry.Skills {
+			if (&spec.Skill{Tags: skill.Tags}).HasTags(listTags) {
+				items = append(items, skill)
+			}
+		}
+	}
+	setResult(items)
+
+	if len(items) == 0 {
+		fmt.Println("ℹ️  远程仓库注册表中没有匹配的技能")
+		fmt.Println("使用 'skill-hub update' 同步远程仓库")
+		return nil
+	}
+
+	fmt.Println("远程仓库技能列表:")
+	fmt.Printf("%-24s %-10s %-8s %-12s %s\n", "ID", "版本", "下载", "评分", "描述")
+	fmt.Println("------------------------------------------------------------------------------------")
+	for _, skill := range items {
+		fmt.Printf("%-24s %-10s %-8d %-12s %s\n", skill.ID, skill.Version, skill.Downloads, skill.RatingText(), completionDescription(skill.Description))
+	}
+
+	fmt.Println("\n使用 'skill-hub show <skill-id>' 查看技能详情")
+	return nil
+}
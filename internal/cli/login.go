@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/credentials"
+)
+
+var (
+	loginUsername   string
+	loginTokenStdin bool
+	loginStore      string
+	loginList       bool
+)
+
+var loginCmd = &cobra.Command{
+	Use:   "login [host]",
+	Short: "保存访问私有仓库的凭据",
+	Long: `为主机保存访问令牌，import、update、search 和 push 访问该主机上的私有仓库时自动使用：
+  - Git 仓库（GitHub、GitLab、Gitea 等）的克隆和拉取
+  - 配置文件 forges 中未设置 token 的代码托管平台
+  - 未设置 registry_token 的HTTP技能注册表
+  - OCI 镜像仓库（用户名默认为 skill-hub）
+
+令牌默认保存在系统钥匙串中（macOS security、Linux secret-tool），
+钥匙串不可用或指定 --store file 时保存在 ~/.skill-hub/credentials.yaml（仅当前用户可读写）。
+环境变量 SKILL_HUB_TOKEN_<主机> 优先于保存的凭据，主机名转为大写、非字母数字替换为下划线，
+例如 SKILL_HUB_TOKEN_GITLAB_EXAMPLE_COM，便于在CI中使用。
+
+示例:
+  skill-hub login gitlab.example.com
+  echo $GITHUB_TOKEN | skill-hub login github.com --token-stdin
+  skill-hub login --list
+  skill-hub logout gitlab.example.com`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if loginList {
+			return runLoginList()
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("请指定主机，或使用 --list 查看已登录的主机")
+		}
+		return runLogin(args[0])
+	},
+}
+
+var logoutCmd = &cobra.Command{
+	Use:               "logout <host>",
+	Short:             "删除保存的私有仓库凭据",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeLoginHosts,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLogout(args[0])
+	},
+}
+
+func init() {
+	loginCmd.Flags().StringVarP(&loginUsername, "username", "u", "", "用户名，Git 和 OCI 仓库的基本认证使用")
+	loginCmd.Flags().BoolVar(&loginTokenStdin, "token-stdin", false, "从标准输入读取令牌")
+	loginCmd.Flags().StringVar(&loginStore, "store", "", "令牌的保存位置: keychain 或 file（默认钥匙串可用时使用 keychain）")
+	loginCmd.Flags().BoolVar(&loginList, "list", false, "列出已登录的主机")
+	loginCmd.RegisterFlagCompletionFunc("store", fixedCompletions(credentials.SourceKeychain, credentials.SourceFile))
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+}
+
+func runLogin(host string) error {
+	host = credentials.NormalizeHost(host)
+	if host == "" {
+		return fmt.Errorf("主机不能为空")
+	}
+
+	useKeychain := credentials.KeychainAvailable()
+	switch loginStore {
+	case "":
+	case credentials.SourceKeychain:
+		if !useKeychain {
+			return fmt.Errorf("当前系统没有可用的钥匙串工具（macOS security 或 Linux secret-tool），请使用 --store file")
+		}
+	case credentials.SourceFile:
+		useKeychain = false
+	default:
+		return fmt.Errorf("不支持的保存位置: %s，可用: keychain、file", loginStore)
+	}
+
+	token, err := readLoginToken(host)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("令牌不能为空")
+	}
+
+	cred := credentials.Credential{Host: host, Username: loginUsername, Token: token, Source: credentials.SourceFile}
+	if useKeychain {
+		cred.Source = credentials.SourceKeychain
+	}
+	if err := credentials.Save(cred, useKeychain); err != nil {
+		return err
+	}
+	if useKeychain {
+		fmt.Printf("✓ 已保存 %s 的凭据到系统钥匙串\n", host)
+	} else {
+		fmt.Printf("✓ 已保存 %s 的凭据到 ~/.skill-hub/credentials.yaml\n", host)
+	}
+	if env := credentials.EnvName(host); os.Getenv(env) != "" {
+		fmt.Printf("⚠️  环境变量 %s 已设置，访问 %s 时优先使用环境变量中的令牌\n", env, host)
+	}
+	setResult(cred)
+	return nil
+}
+
+// readLoginToken 从标准输入读取令牌，未指定 --token-stdin 时先提示输入
+func readLoginToken(host string) (string, error) {
+	if loginTokenStdin {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("读取令牌失败: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	fmt.Printf("请输入 %s 的访问令牌: ", host)
+	line, err := newInputReader().ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("读取令牌失败: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func runLoginList() error {
+	creds, err := credentials.List()
+	if err != nil {
+		return err
+	}
+	setResult(creds)
+	if len(creds) == 0 {
+		fmt.Println("ℹ️  没有已登录的主机，使用 'skill-hub login <主机>' 保存凭据")
+		return nil
+	}
+	fmt.Printf("%-32s %-16s %s\n", "主机", "用户名", "保存位置")
+	fmt.Println(strings.Repeat("-", 60))
+	for _, cred := range creds {
+		username := cred.Username
+		if username == "" {
+			username = "-"
+		}
+		fmt.Printf("%-32s %-16s %s\n", cred.Host, username, cred.Source)
+	}
+	return nil
+}
+
+func runLogout(host string) error {
+	host = credentials.NormalizeHost(host)
+	removed, err := credentials.Delete(host)
+	if err != nil {
+		return err
+	}
+	setResult(map[string]interface{}{"host": host, "removed": removed})
+	if !removed {
+		fmt.Printf("ℹ️  没有保存 %s 的凭据\n", host)
+		return nil
+	}
+	fmt.Printf("✓ 已删除 %s 的凭据\n", host)
+	return nil
+}
+
+// completeLoginHosts 补全已登录的主机
+func completeLoginHosts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	creds, err := credentials.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, cred := range creds {
+		if strings.HasPrefix(cred.Host, toComplete) {
+			completions = append(completions, cred.Host+"\t"+cred.Source)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,456 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/config"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/migrate"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateReverse bool
+	migrateDryRun  bool
+	migrateKeep    bool
+	migrateForce   bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "在旧格式与当前格式之间转换",
+	Long: `将旧格式的规则和技能文件转换为当前格式，使用 --reverse 转换回旧格式。
+
+转换时保留 SKILL-HUB 标记块和模板变量，迁移后 apply、remove、feedback 仍能识别技能内容。
+转换成功后删除源文件，使用 --keep 保留。`,
+}
+
+var migrateCursorRulesCmd = &cobra.Command{
+	Use:   "cursor-rules",
+	Short: "转换 .cursorrules 与 .cursor/rules/*.mdc",
+	Long: `将当前项目的 .cursorrules 拆分为 ` + migrate.CursorRulesDir + ` 下的 .mdc 规则文件：
+每个技能标记块生成 <skill-id>.mdc，标记块之外的内容保存到 ` + migrate.CursorUserRulesFile + `。
+
+使用 --reverse 将这些 .mdc 文件合并回 .cursorrules（不含标记块的其他 .mdc 文件不受影响）。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if migrateReverse {
+			return runMigrateMDCToCursorRules()
+		}
+		return runMigrateCursorRulesToMDC()
+	},
+}
+
+var migrateSkillCmd = &cobra.Command{
+	Use:   "skill [skill-id|dir...]",
+	Short: "转换 skill.yaml+prompt.md 与 SKILL.md",
+	Long: `将旧格式技能（skill.yaml + prompt.md）转换为 Agent Skills 规范的 SKILL.md。
+
+参数可以是技能目录或技能仓库中的技能ID；不指定时转换技能仓库中所有旧格式技能。
+使用 --reverse 将 SKILL.md 转换回 skill.yaml + prompt.md（必须指定技能）。`,
+	ValidArgsFunction: completeSkillIDList,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMigrateSkills(args)
+	},
+}
+
+func init() {
+	migrateCmd.PersistentFlags().BoolVar(&migrateReverse, "reverse", false, "转换回旧格式")
+	migrateCmd.PersistentFlags().BoolVar(&migrateDryRun, "dry-run", false, "只显示将要进行的转换")
+	migrateCmd.PersistentFlags().BoolVar(&migrateKeep, "keep", false, "转换后保留源文件")
+	migrateCmd.PersistentFlags().BoolVar(&migrateForce, "force", false, "覆盖已存在的目标文件")
+
+	migrateCmd.AddCommand(migrateCursorRulesCmd)
+	migrateCmd.AddCommand(migrateSkillCmd)
+}
+
+func runMigrateCursorRulesToMDC() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	sourcePath := filepath.Join(cwd, ".cursorrules")
+	rulesDir := filepath.Join(cwd, migrate.CursorRulesDir)
+
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("当前项目没有 .cursorrules 文件")
+		}
+		return fmt.Errorf("读取 .cursorrules 失败: %w", err)
+	}
+
+	files, issues := migrate.CursorRulesToMDC(string(data), skillDescriptions())
+	for _, issue := range issues {
+		fmt.Printf("⚠️  修复标记 %s: %s\n", sourcePath, issue)
+	}
+	if len(files) == 0 {
+		fmt.Println("ℹ️  .cursorrules 为空，无需迁移")
+		return nil
+	}
+
+	names := sortedKeys(files)
+	if !migrateForce {
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(rulesDir, name)); err == nil {
+				return fmt.Errorf("规则文件已存在: %s，使用 --force 覆盖", filepath.Join(migrate.CursorRulesDir, name))
+			}
+		}
+	}
+
+	fmt.Printf("迁移 .cursorrules -> %s/\n", migrate.CursorRulesDir)
+	for _, name := range names {
+		fmt.Printf("  + %s\n", filepath.Join(migrate.CursorRulesDir, name))
+	}
+	if migrateDryRun {
+		fmt.Println("\n🔍 DRY RUN - 未修改任何文件")
+		return nil
+	}
+
+	if err := os.MkdirAll(rulesDir, 0755); err != nil {
+		return fmt.Errorf("创建规则目录失败: %w", err)
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(rulesDir, name), []byte(files[name]), 0644); err != nil {
+			return fmt.Errorf("写入规则文件失败: %w", err)
+		}
+	}
+	if !migrateKeep {
+		if err := os.Remove(sourcePath); err != nil {
+			return fmt.Errorf("删除 .cursorrules 失败: %w", err)
+		}
+	}
+
+	fmt.Printf("\n✅ 已迁移 %d 个规则文件\n", len(names))
+	return nil
+}
+
+func runMigrateMDCToCursorRules() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	targetPath := filepath.Join(cwd, ".cursorrules")
+	rulesDir := filepath.Join(cwd, migrate.CursorRulesDir)
+
+	paths, err := filepath.Glob(filepath.Join(rulesDir, "*.mdc"))
+	if err != nil {
+		return fmt.Errorf("查找规则文件失败: %w", err)
+	}
+	files := make(map[string]string, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取规则文件失败: %w", err)
+		}
+		files[filepath.Base(path)] = string(data)
+	}
+
+	content, merged, err := migrate.MDCToCursorRules(files)
+	if err != nil {
+		return err
+	}
+	if len(merged) == 0 {
+		fmt.Printf("ℹ️  %s 中没有可合并的规则文件\n", migrate.CursorRulesDir)
+		return nil
+	}
+	if _, err := os.Stat(targetPath); err == nil && !migrateForce {
+		return fmt.Errorf(".cursorrules 已存在，使用 --force 覆盖")
+	}
+
+	fmt.Printf("迁移 %s/ -> .cursorrules\n", migrate.CursorRulesDir)
+	for _, name := range merged {
+		fmt.Printf("  - %s\n", filepath.Join(migrate.CursorRulesDir, name))
+	}
+	if migrateDryRun {
+		fmt.Println("\n🔍 DRY RUN - 未修改任何文件")
+		return nil
+	}
+
+	if err := os.WriteFile(targetPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入 .cursorrules 失败: %w", err)
+	}
+	if !migrateKeep {
+		for _, name := range merged {
+			if err := os.Remove(filepath.Join(rulesDir, name)); err != nil {
+				return fmt.Errorf("删除规则文件失败: %w", err)
+			}
+		}
+		// 规则目录为空时一并删除，失败说明还有其他文件
+		if os.Remove(rulesDir) == nil {
+			os.Remove(filepath.Dir(rulesDir))
+		}
+	}
+
+	fmt.Printf("\n✅ 已将 %d 个规则文件合并到 .cursorrules\n", len(merged))
+	return nil
+}
+
+func runMigrateSkills(args []string) error {
+	dirs, err := resolveMigrateSkillDirs(args)
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		fmt.Println("ℹ️  技能仓库中没有旧格式的技能")
+		return nil
+	}
+
+	migrated := 0
+	for _, dir := range dirs {
+		var changed bool
+		if migrateReverse {
+			changed, err = migrateSkillToLegacy(dir)
+		} else {
+			changed, err = migrateLegacySkill(dir)
+		}
+		if err != nil {
+			return fmt.Errorf("转换 %s 失败: %w", dir, err)
+		}
+		if changed {
+			migrated++
+		}
+	}
+
+	if migrateDryRun {
+		fmt.Println("\n🔍 DRY RUN - 未修改任何文件")
+		return nil
+	}
+
+	// 仓库中的技能发生变化时刷新索引
+	if migrated > 0 {
+		if repoDir, err := config.GetRepoPath(); err == nil && anyUnder(dirs, repoDir) {
+			if err := refreshSkillRegistry(repoDir); err != nil {
+				fmt.Printf("⚠️  刷新技能索引失败: %v\n", err)
+			}
+		}
+	}
+
+	fmt.Printf("\n✅ 已转换 %d 个技能\n", migrated)
+	if migrateReverse && !migrateKeep && migrated > 0 {
+		fmt.Println("ℹ️  已删除 SKILL.md，Skill Hub 只能识别 SKILL.md 格式的技能")
+	}
+	return nil
+}
+
+// resolveMigrateSkillDirs 将参数解析为技能目录，未指定时查找技能仓库中的旧格式技能
+func resolveMigrateSkillDirs(args []string) ([]string, error) {
+	if len(args) == 0 {
+		if migrateReverse {
+			return nil, fmt.Errorf("使用 --reverse 时必须指定技能")
+		}
+		skillsDir, err := engine.GetSkillsDir()
+		if err != nil {
+			return nil, err
+		}
+		matches, err := filepath.Glob(filepath.Join(skillsDir, "*", migrate.LegacySkillFile))
+		if err != nil {
+			return nil, fmt.Errorf("查找旧格式技能失败: %w", err)
+		}
+		var dirs []string
+		for _, match := range matches {
+			dirs = append(dirs, filepath.Dir(match))
+		}
+		return dirs, nil
+	}
+
+	var dirs []string
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			abs, err := filepath.Abs(arg)
+			if err != nil {
+				return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+			}
+			dirs = append(dirs, abs)
+			continue
+		}
+		skillsDir, err := engine.GetSkillsDir()
+		if err != nil {
+			return nil, err
+		}
+		dir := filepath.Join(skillsDir, arg)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("'%s' 既不是目录也不是技能仓库中的技能", arg)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// migrateLegacySkill 将目录中的 skill.yaml + prompt.md 转换为 SKILL.md
+func migrateLegacySkill(dir string) (bool, error) {
+	skillYAML, err := os.ReadFile(filepath.Join(dir, migrate.LegacySkillFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("ℹ️  %s 不是旧格式技能，跳过\n", dir)
+			return false, nil
+		}
+		return false, err
+	}
+	prompt, err := os.ReadFile(filepath.Join(dir, migrate.LegacyPromptFile))
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	target := filepath.Join(dir, migrate.SkillFile)
+	if _, err := os.Stat(target); err == nil && !migrateForce {
+		fmt.Printf("⚠️  %s 已存在，跳过（使用 --force 覆盖）\n", target)
+		return false, nil
+	}
+
+	content, err := migrate.LegacyToSkillMD(skillYAML, prompt)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Printf("  %s: %s + %s -> %s\n", filepath.Base(dir), migrate.LegacySkillFile, migrate.LegacyPromptFile, migrate.SkillFile)
+	if migrateDryRun {
+		return true, nil
+	}
+
+	if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+		return false, err
+	}
+	if err := migrateLocaleVariants(dir, true); err != nil {
+		return false, err
+	}
+	if !migrateKeep {
+		for _, name := range []string{migrate.LegacySkillFile, migrate.LegacyPromptFile} {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// migrateSkillToLegacy 将目录中的 SKILL.md 转换为 skill.yaml + prompt.md
+func migrateSkillToLegacy(dir string) (bool, error) {
+	content, err := os.ReadFile(filepath.Join(dir, migrate.SkillFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("ℹ️  %s 没有 %s，跳过\n", dir, migrate.SkillFile)
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !migrateForce {
+		for _, name := range []string{migrate.LegacySkillFile, migrate.LegacyPromptFile} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				fmt.Printf("⚠️  %s 已存在，跳过（使用 --force 覆盖）\n", filepath.Join(dir, name))
+				return false, nil
+			}
+		}
+	}
+
+	skillYAML, prompt, err := migrate.SkillMDToLegacy(string(content))
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Printf("  %s: %s -> %s + %s\n", filepath.Base(dir), migrate.SkillFile, migrate.LegacySkillFile, migrate.LegacyPromptFile)
+	if migrateDryRun {
+		return true, nil
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, migrate.LegacySkillFile), skillYAML, 0644); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, migrate.LegacyPromptFile), prompt, 0644); err != nil {
+		return false, err
+	}
+	if err := migrateLocaleVariants(dir, false); err != nil {
+		return false, err
+	}
+	if !migrateKeep {
+		if err := os.Remove(filepath.Join(dir, migrate.SkillFile)); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// migrateLocaleVariants 转换提示词的语言版本：fromLegacy 为 true 时 prompt.<语言>.md -> SKILL.<语言>.md，否则相反
+//
+// 语言版本只包含正文，两种格式的内容相同，只需重命名；使用 --keep 时复制。
+func migrateLocaleVariants(dir string, fromLegacy bool) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		parse, targetName := spec.ParseLocaleFileName, spec.LegacyLocaleFileName
+		if fromLegacy {
+			parse, targetName = spec.ParseLegacyLocaleFileName, spec.LocaleFileName
+		}
+		locale, ok := parse(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		source := filepath.Join(dir, entry.Name())
+		target := filepath.Join(dir, targetName(locale))
+		if _, err := os.Stat(target); err == nil && !migrateForce {
+			fmt.Printf("⚠️  %s 已存在，跳过（使用 --force 覆盖）\n", target)
+			continue
+		}
+		fmt.Printf("  %s: %s -> %s\n", filepath.Base(dir), entry.Name(), filepath.Base(target))
+		if migrateKeep {
+			data, err := os.ReadFile(source)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, data, 0644); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Rename(source, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skillDescriptions 返回技能仓库中各技能的描述，仓库不可用时返回空
+func skillDescriptions() map[string]string {
+	descriptions := make(map[string]string)
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return descriptions
+	}
+	skills, err := manager.LoadAllSkills()
+	if err != nil {
+		return descriptions
+	}
+	for _, skill := range skills {
+		descriptions[skill.ID] = skill.Description
+	}
+	return descriptions
+}
+
+// anyUnder 检查是否有路径位于指定目录下
+func anyUnder(paths []string, dir string) bool {
+	for _, path := range paths {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys 返回按名称排序的文件名
+func sortedKeys(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
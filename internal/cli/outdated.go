@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+)
+
+var outdatedChannel string
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "列出技能注册表中有新版本的技能",
+	Long: `对比技能仓库中的技能与 registry_url 静态HTTP技能注册表中的最新版本，列出可以更新的技能，
+并标出当前项目启用和固定的技能。使用 'skill-hub update' 下载新版本。
+
+只列出订阅的发布通道（配置中的 channel 和 skill_channels，未设置时为 stable）中发布的版本，
+--channel beta 同时列出 beta 通道的版本。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runOutdated(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().StringVar(&outdatedChannel, "channel", "", "列出该发布通道（stable 或 beta）中的版本，覆盖配置中的通道")
+	outdatedCmd.RegisterFlagCompletionFunc("channel", fixedCompletions(channelCompletions...))
+}
+
+// outdatedSkill 有新版本的技能
+type outdatedSkill struct {
+	SkillID   string `json:"skill_id"`
+	Current   string `json:"current"`
+	Latest    string `json:"latest"`
+	InProject bool   `json:"in_project"`
+	Pinned    string `json:"pinned,omitempty"`
+}
+
+func runOutdated(cmd *cobra.Command) error {
+	client, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		return fmt.Errorf("未配置 registry_url，使用Git技能仓库时运行 'skill-hub update' 获取最新技能")
+	}
+	channel := ""
+	if outdatedChannel != "" {
+		if channel, err = spec.NormalizeChannel(outdatedChannel); err != nil {
+			return err
+		}
+	} else if err := validateChannelConfig(); err != nil {
+		return err
+	}
+	index, err := client.Index(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	// 订阅的通道不接受的版本不列出，update 不会更新到这些版本
+	available := &spec.Registry{Version: index.Version}
+	for _, meta := range index.Skills {
+		if spec.ChannelAccepts(subscribedChannel(meta.ID, channel), meta.Channel) {
+			available.Skills = append(available.Skills, meta)
+		}
+	}
+	items := outdatedSkills(available, skillVersions())
+	if cwd, err := os.Getwd(); err == nil {
+		markProjectSkills(cwd, items)
+	}
+	setResult(items)
+
+	if len(items) == 0 {
+		fmt.Println("✓ 所有技能都是最新版本")
+		return nil
+	}
+	fmt.Printf("📦 %d 个技能有新版本 (%s):\n", len(items), client.URL())
+	fmt.Printf("  %-24s %-12s %s\n", "技能", "当前版本", "最新版本")
+	for _, item := range items {
+		note := ""
+		if item.InProject {
+			note = "当前项目已启用"
+		}
+		if item.Pinned != "" {
+			note = fmt.Sprintf("当前项目固定在 %s", item.Pinned)
+		}
+		fmt.Printf("  %-24s %-12s %-12s %s\n", item.SkillID, item.Current, item.Latest, note)
+	}
+	if channel != "" {
+		fmt.Printf("\n使用 'skill-hub update --channel %s' 下载新版本\n", channel)
+	} else {
+		fmt.Println("\n使用 'skill-hub update' 下载新版本")
+	}
+	return nil
+}
+
+// outdatedSkills 返回注册表版本高于本地版本的技能，按技能ID排序；本地没有的技能不列出
+func outdatedSkills(index *spec.Registry, local map[string]string) []outdatedSkill {
+	items := []outdatedSkill{}
+	for _, meta := range index.Skills {
+		current, ok := local[meta.ID]
+		if !ok {
+			continue
+		}
+		latest, err := engine.ParseVersion(meta.Version)
+		if err != nil {
+			continue
+		}
+		if installed, err := engine.ParseVersion(current); err == nil && latest.Compare(installed) <= 0 {
+			continue
+		}
+		items = append(items, outdatedSkill{SkillID: meta.ID, Current: current, Latest: meta.Version})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].SkillID < items[j].SkillID })
+	return items
+}
+
+// markProjectSkills 标记项目中启用和固定的技能，读取失败时不标记
+func markProjectSkills(projectPath string, items []outdatedSkill) {
+	var enabled map[string]spec.SkillVars
+	if stateManager, err := state.NewStateManager(); err == nil {
+		enabled, _ = stateManager.GetProjectSkills(projectPath)
+	}
+	lock, err := state.LoadLockFile(projectPath)
+	if err != nil {
+		lock = &state.LockFile{}
+	}
+	for i := range items {
+		_, items[i].InProject = enabled[items[i].SkillID]
+		if locked, ok := lock.Skills[items[i].SkillID]; ok {
+			items[i].Pinned = locked.Version
+		}
+	}
+}
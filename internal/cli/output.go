@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"skill-hub/internal/logging"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputJSON    bool
+	outputQuiet   bool
+	outputVerbose bool
+	logLevel      string
+	logFile       string
+)
+
+// stdout 进程原始的标准输出。--json 和 --quiet 会丢弃命令的普通输出，
+// JSON 结果和补全脚本等必须输出的内容写入这里
+var stdout io.Writer = os.Stdout
+
+// commandResult 是 --json 模式下每个命令输出的结果
+type commandResult struct {
+	Command string      `json:"command"`
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// resultData 当前命令通过 setResult 设置的结构化结果
+var resultData interface{}
+
+// silencedStdout 被替换前的 os.Stdout，命令结束后恢复
+var silencedStdout *os.File
+
+// closeLog 关闭 --log-file 打开的日志文件，命令结束后调用
+var closeLog func() error
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.BoolVar(&outputJSON, "json", false, "以JSON格式输出结果（普通输出被隐藏）")
+	flags.BoolVarP(&outputQuiet, "quiet", "q", false, "只输出错误")
+	flags.BoolVarP(&outputVerbose, "verbose", "v", false, "输出调试日志（同 --log-level debug）")
+	flags.StringVar(&logLevel, "log-level", "", "日志级别: debug, info, warn, error (默认 warn)")
+	flags.StringVar(&logFile, "log-file", "", "将日志追加写入指定文件，不输出到标准错误")
+	rootCmd.RegisterFlagCompletionFunc("log-level", fixedCompletions(logging.Levels()...))
+
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := setupOutput(cmd); err != nil {
+			return err
+		}
+		startUpdateCheck(cmd)
+		return nil
+	}
+}
+
+// setupOutput 根据全局输出标志设置日志并重定向普通输出
+func setupOutput(cmd *cobra.Command) error {
+	if outputQuiet && outputVerbose {
+		return fmt.Errorf("--quiet 和 --verbose 不能同时使用")
+	}
+	level := logLevel
+	if level == "" && outputVerbose {
+		level = logging.LevelDebug
+	}
+	closeFn, err := logging.Setup(level, logFile)
+	if err != nil {
+		return err
+	}
+	closeLog = closeFn
+	if !outputJSON && !outputQuiet {
+		return nil
+	}
+
+	// 错误由调用方统一输出一次，不再附带用法说明
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("打开 %s 失败: %w", os.DevNull, err)
+	}
+	silencedStdout = os.Stdout
+	os.Stdout = devNull
+	return nil
+}
+
+// finishOutput 恢复标准输出并关闭日志文件，--json 模式下输出命令结果
+func finishOutput(cmd *cobra.Command, err error) error {
+	if closeLog != nil {
+		closeLog()
+		closeLog = nil
+	}
+	if silencedStdout != nil {
+		os.Stdout.Close()
+		os.Stdout = silencedStdout
+		silencedStdout = nil
+	}
+
+	if !outputJSON || cmd == nil {
+		return err
+	}
+
+	result := commandResult{
+		Command: commandPath(cmd),
+		Success: err == nil,
+		Data:    resultData,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	data, marshalErr := json.MarshalIndent(result, "", "  ")
+	if marshalErr != nil {
+		return fmt.Errorf("序列化JSON输出失败: %w", marshalErr)
+	}
+	fmt.Fprintln(stdout, string(data))
+	return err
+}
+
+// setResult 设置命令的结构化结果，仅在 --json 模式下输出
+func setResult(data interface{}) {
+	resultData = data
+}
+
+// debugf 记录调试日志，--verbose 或 --log-level debug 时输出
+func debugf(format string, args ...interface{}) {
+	if !slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	slog.Debug(fmt.Sprintf(format, args...))
+}
+
+// commandPath 返回不含根命令名的命令路径，例如 "git status"
+func commandPath(cmd *cobra.Command) string {
+	return strings.TrimPrefix(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()), " ")
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// withOutputFlags 临时设置全局输出标志并捕获结果输出
+func withOutputFlags(t *testing.T, jsonOut, quiet, verbose bool) *bytes.Buffer {
+	t.Helper()
+	savedJSON, savedQuiet, savedVerbose, savedStdout := outputJSON, outputQuiet, outputVerbose, stdout
+	buf := &bytes.Buffer{}
+	outputJSON, outputQuiet, outputVerbose, stdout = jsonOut, quiet, verbose, buf
+	resultData = nil
+	t.Cleanup(func() {
+		outputJSON, outputQuiet, outputVerbose, stdout = savedJSON, savedQuiet, savedVerbose, savedStdout
+		resultData = nil
+	})
+	return buf
+}
+
+func TestJSONOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		runErr  error
+		data    interface{}
+		success bool
+	}{
+		{"success with data", nil, []string{"git-expert"}, true},
+		{"failure", errors.New("技能不存在"), nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := withOutputFlags(t, true, false, false)
+			cmd := &cobra.Command{Use: "list"}
+			(&cobra.Command{Use: "skill-hub"}).AddCommand(cmd)
+
+			if err := setupOutput(cmd); err != nil {
+				t.Fatalf("setupOutput() error = %v", err)
+			}
+			fmt.Println("普通输出应被隐藏")
+			if tt.data != nil {
+				setResult(tt.data)
+			}
+			if err := finishOutput(cmd, tt.runErr); err != tt.runErr {
+				t.Errorf("finishOutput() error = %v, want %v", err, tt.runErr)
+			}
+
+			var result commandResult
+			if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+				t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+			}
+			if result.Command != "list" || result.Success != tt.success {
+				t.Errorf("Unexpected result: %+v", result)
+			}
+			if tt.runErr != nil && result.Error != tt.runErr.Error() {
+				t.Errorf("Error = %q, want %q", result.Error, tt.runErr.Error())
+			}
+			if (tt.data != nil) != (result.Data != nil) {
+				t.Errorf("Data = %v, want %v", result.Data, tt.data)
+			}
+		})
+	}
+}
+
+func TestQuietVerboseConflict(t *testing.T) {
+	withOutputFlags(t, false, true, true)
+	if err := setupOutput(&cobra.Command{Use: "list"}); err == nil {
+		t.Error("Expected error when --quiet and --verbose are both set")
+	}
+}
+
+func TestCommandPath(t *testing.T) {
+	root := &cobra.Command{Use: "skill-hub"}
+	parent := &cobra.Command{Use: "bundle"}
+	child := &cobra.Command{Use: "apply"}
+	root.AddCommand(parent)
+	parent.AddCommand(child)
+
+	if got := commandPath(child); got != "bundle apply" {
+		t.Errorf("commandPath() = %q, want %q", got, "bundle apply")
+	}
+	if got := commandPath(root); got != "" {
+		t.Errorf("commandPath(root) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var pinList bool
+
+var pinCmd = &cobra.Command{
+	Use:   "pin [skill-id[@version]]",
+	Short: "在当前项目中固定技能版本",
+	Long: `将技能版本固定记录到项目锁文件 ` + state.LockFileName + ` 中（可提交到版本库）。
+
+不指定版本时固定为技能仓库中的当前版本，并将该版本安装到 ~/.skill-hub/versions。
+apply 时已固定的技能不会被静默升级：仓库版本与固定版本不一致时，使用已安装的该版本；
+未安装时使用该版本的应用记录恢复内容，也没有应用记录时拒绝应用该技能。
+
+示例:
+  skill-hub pin git-expert          # 固定为当前版本
+  skill-hub pin git-expert@1.2.0    # 固定为指定版本
+  skill-hub pin --list              # 查看已固定的技能
+  skill-hub unpin git-expert        # 取消固定`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeSkillIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if pinList || len(args) == 0 {
+			return runPinList()
+		}
+		return runPin(args[0])
+	},
+}
+
+var unpinCmd = &cobra.Command{
+	Use:               "unpin <skill-id>",
+	Short:             "取消技能的版本固定",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completePinnedSkillIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUnpin(args[0])
+	},
+}
+
+func init() {
+	pinCmd.Flags().BoolVar(&pinList, "list", false, "列出当前项目已固定的技能")
+}
+
+func runPin(arg string) error {
+	skillID, version, _ := strings.Cut(arg, "@")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	if !skillManager.SkillExists(skillID) {
+		return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", skillID)
+	}
+	skill, err := skillManager.LoadSkill(skillID)
+	if err != nil {
+		return fmt.Errorf("加载技能失败: %w", err)
+	}
+
+	lock, err := state.LoadLockFile(cwd)
+	if err != nil {
+		return err
+	}
+
+	versions, err := engine.NewVersionStore()
+	if err != nil {
+		return err
+	}
+
+	// 只有固定为仓库当前版本时才能记录内容哈希
+	checksum := ""
+	switch {
+	case version == "" || version == skill.Version:
+		version = skill.Version
+		prompt, err := skillManager.GetSkillPrompt(skillID)
+		if err != nil {
+			return err
+		}
+		checksum = contentSHA256(prompt)
+		// 安装当前版本，仓库升级后仍可应用固定的版本
+		if _, err := versions.Install(skillID, version, skillManager.GetSkillDir(skillID)); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+	case versions.Has(skillID, version):
+		fmt.Printf("ℹ️  技能仓库中 %s 的版本为 %s，apply 时使用已安装的 %s\n", skillID, skill.Version, version)
+	default:
+		fmt.Printf("⚠️  技能仓库中 %s 的版本为 %s，与固定版本 %s 不一致，且该版本未安装\n", skillID, skill.Version, version)
+		fmt.Println("   apply 时将使用该版本的应用记录，没有记录时拒绝应用")
+	}
+
+	lock.Pin(skillID, version, checksum)
+	if err := lock.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("📌 已固定技能 %s@%s\n", skillID, version)
+	fmt.Printf("锁文件: %s\n", lock.GetPath())
+	return nil
+}
+
+func runUnpin(skillID string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+
+	lock, err := state.LoadLockFile(cwd)
+	if err != nil {
+		return err
+	}
+	if !lock.Unpin(skillID) {
+		fmt.Printf("ℹ️  技能 %s 未被固定\n", skillID)
+		return nil
+	}
+	if err := lock.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ 已取消固定技能 %s\n", skillID)
+	return nil
+}
+
+func runPinList() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+
+	lock, err := state.LoadLockFile(cwd)
+	if err != nil {
+		return err
+	}
+	if len(lock.Skills) == 0 {
+		fmt.Println("ℹ️  当前项目没有固定的技能")
+		return nil
+	}
+
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+
+	type pinnedItem struct {
+		SkillID     string `json:"skill_id"`
+		Version     string `json:"version"`
+		RepoVersion string `json:"repo_version,omitempty"`
+		PinnedAt    string `json:"pinned_at"`
+	}
+	var items []pinnedItem
+
+	fmt.Printf("%-24s %-12s %s\n", "技能", "固定版本", "仓库版本")
+	for _, id := range lock.SkillIDs() {
+		locked := lock.Skills[id]
+		repoVersion := ""
+		if skill, err := skillManager.LoadSkill(id); err == nil {
+			repoVersion = skill.Version
+		}
+		items = append(items, pinnedItem{SkillID: id, Version: locked.Version, RepoVersion: repoVersion, PinnedAt: locked.PinnedAt})
+		marker := ""
+		if repoVersion != locked.Version {
+			marker = " ⚠️"
+		}
+		fmt.Printf("%-24s %-12s %s%s\n", id, locked.Version, valueOrDash(repoVersion), marker)
+	}
+	setResult(items)
+	return nil
+}
+
+// resolvePinnedSkill 根据锁文件确定实际应用的内容
+// 返回要应用的模板内容、变量和版本；技能被固定且无法满足时返回错误
+func resolvePinnedSkill(lock *state.LockFile, skill *spec.Skill, prompt string, skillVars spec.SkillVars, target string) (string, map[string]string, string, error) {
+	locked, pinned := lock.Get(skill.ID)
+	if !pinned {
+		return prompt, skillVars.Variables, skill.Version, nil
+	}
+
+	if locked.Version == skill.Version {
+		if locked.SHA256 != "" && locked.SHA256 != contentSHA256(prompt) {
+			fmt.Printf("⚠️  技能 %s@%s 的内容自固定后已变化\n", skill.ID, locked.Version)
+		}
+		return prompt, skillVars.Variables, skill.Version, nil
+	}
+
+	// 仓库版本已变化，使用固定版本的应用记录（内容已渲染，无需再替换变量）
+	if rev, ok := state.FindRevisionByVersion(skillVars, target, locked.Version); ok {
+		fmt.Printf("📌 技能 %s 固定在 %s（仓库版本 %s），使用该版本的应用记录\n", skill.ID, locked.Version, skill.Version)
+		content, err := state.RevisionContent(*rev)
+		if err != nil {
+			return "", nil, "", fmt.Errorf("还原技能 %s 的应用记录失败: %w", skill.ID, err)
+		}
+		return content, nil, locked.Version, nil
+	}
+
+	return "", nil, "", fmt.Errorf("技能 %s 固定在 %s，但仓库版本为 %s 且没有该版本的应用记录，拒绝升级（使用 'skill-hub unpin %s' 取消固定）",
+		skill.ID, locked.Version, skill.Version, skill.ID)
+}
+
+// loadPinnedVersion 技能固定的版本与仓库版本不同且已安装到版本存储时，加载该版本的技能和提示词
+func loadPinnedVersion(versions *engine.VersionStore, lock *state.LockFile, skill *spec.Skill, locale string) (*spec.Skill, string, bool) {
+	locked, pinned := lock.Get(skill.ID)
+	if !pinned {
+		return nil, "", false
+	}
+	stored, prompt, ok, err := versions.LoadPinned(skill, locked.Version, locale)
+	if err != nil {
+		fmt.Printf("⚠️  加载已安装的 %s@%s 失败: %v\n", skill.ID, locked.Version, err)
+		return nil, "", false
+	}
+	return stored, prompt, ok
+}
+
+// contentSHA256 计算内容的SHA256
+func contentSHA256(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// reportPinnedSkills 提示项目中固定版本与仓库版本不一致的技能
+func reportPinnedSkills(projectPath string) {
+	lock, err := state.LoadLockFile(projectPath)
+	if err != nil || len(lock.Skills) == 0 {
+		return
+	}
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return
+	}
+
+	for _, id := range lock.SkillIDs() {
+		locked := lock.Skills[id]
+		skill, err := skillManager.LoadSkill(id)
+		if err != nil || skill.Version == locked.Version {
+			continue
+		}
+		fmt.Printf("📌 技能 %s 固定在 %s，仓库版本为 %s，不会自动升级\n", id, locked.Version, skill.Version)
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+)
+
+func TestResolvePinnedSkill(t *testing.T) {
+	lock, err := state.LoadLockFile(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadLockFile() error = %v", err)
+	}
+
+	skill := &spec.Skill{ID: "demo", Version: "2.0.0"}
+	prompt := "Hello {{.NAME}}"
+	skillVars := spec.SkillVars{
+		SkillID:   "demo",
+		Variables: map[string]string{"NAME": "world"},
+		History: map[string][]spec.AppliedRevision{
+			spec.TargetCursor: {{Version: "1.0.0", Content: "Hello v1"}},
+		},
+	}
+
+	t.Run("Not pinned", func(t *testing.T) {
+		content, vars, version, err := resolvePinnedSkill(lock, skill, prompt, skillVars, spec.TargetCursor)
+		if err != nil || content != prompt || vars["NAME"] != "world" || version != "2.0.0" {
+			t.Errorf("resolvePinnedSkill() = %q, %v, %q, %v", content, vars, version, err)
+		}
+	})
+
+	t.Run("Pinned to current version", func(t *testing.T) {
+		lock.Pin("demo", "2.0.0", contentSHA256(prompt))
+		content, _, version, err := resolvePinnedSkill(lock, skill, prompt, skillVars, spec.TargetCursor)
+		if err != nil || content != prompt || version != "2.0.0" {
+			t.Errorf("resolvePinnedSkill() = %q, %q, %v", content, version, err)
+		}
+	})
+
+	t.Run("Pinned to recorded older version", func(t *testing.T) {
+		lock.Pin("demo", "1.0.0", "")
+		content, vars, version, err := resolvePinnedSkill(lock, skill, prompt, skillVars, spec.TargetCursor)
+		if err != nil || content != "Hello v1" || vars != nil || version != "1.0.0" {
+			t.Errorf("resolvePinnedSkill() = %q, %v, %q, %v", content, vars, version, err)
+		}
+	})
+
+	t.Run("Pinned to unknown version", func(t *testing.T) {
+		lock.Pin("demo", "0.5.0", "")
+		if _, _, _, err := resolvePinnedSkill(lock, skill, prompt, skillVars, spec.TargetCursor); err == nil {
+			t.Error("resolvePinnedSkill() expected error for unavailable pinned version")
+		}
+	})
+}
+
+func TestLoadPinnedVersion(t *testing.T) {
+	lock, err := state.LoadLockFile(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadLockFile() error = %v", err)
+	}
+	versions := engine.NewVersionStoreAt(t.TempDir())
+	skillDir := t.TempDir()
+	os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: demo\ndescription: Demo\n---\nHello v1 {{.NAME}}\n"), 0644)
+	if _, err := versions.Install("demo", "1.0.0", skillDir); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	skill := &spec.Skill{ID: "demo", Version: "2.0.0"}
+
+	if _, _, ok := loadPinnedVersion(versions, lock, skill, ""); ok {
+		t.Error("loadPinnedVersion() should ignore skills that are not pinned")
+	}
+
+	lock.Pin("demo", "1.0.0", "")
+	stored, prompt, ok := loadPinnedVersion(versions, lock, skill, "")
+	if !ok || stored.Version != "1.0.0" || !strings.Contains(prompt, "Hello v1") {
+		t.Errorf("loadPinnedVersion() = %v, %q, %v", stored, prompt, ok)
+	}
+
+	lock.Pin("demo", "0.5.0", "")
+	if _, _, ok := loadPinnedVersion(versions, lock, skill, ""); ok {
+		t.Error("loadPinnedVersion() should fail for versions that are not installed")
+	}
+}
+
+func TestUnreferencedVersions(t *testing.T) {
+	installed := map[string][]string{
+		"demo":      {"1.0.0", "1.1.0", "2.0.0"},
+		"acme/tool": {"0.1.0"},
+	}
+	referenced := map[string]map[string]bool{
+		"demo": {"2.0.0": true, "1.0.0": true},
+	}
+	got := unreferencedVersions(installed, referenced)
+	want := []skillVersionRef{{"acme/tool", "0.1.0"}, {"demo", "1.1.0"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unreferencedVersions() = %v, want %v", got, want)
+	}
+}
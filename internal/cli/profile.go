@@ -0,0 +1,298 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileExtends string
+	profileNone    bool
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "管理项目的变量配置",
+	Long: `为同一项目保存多组技能变量（如 backend 与 frontend、dev 与 staging），
+应用时使用 'skill-hub apply --profile <name>' 选择。
+
+配置只记录与技能变量不同的值，可以继承另一个配置：变量按
+技能变量 <- 继承的配置 <- 本配置 的顺序合并。设置默认配置后，apply 未指定 --profile 时使用默认配置。
+
+示例:
+  skill-hub profile create backend
+  skill-hub profile set backend git-expert LANGUAGE=go
+  skill-hub profile create backend-strict --extends backend
+  skill-hub profile default backend
+  skill-hub apply --profile backend-strict`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProfileList()
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出项目的变量配置",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProfileList()
+	},
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "创建变量配置",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProfileCreate(args[0])
+	},
+}
+
+var profileSetCmd = &cobra.Command{
+	Use:   "set <name> <skill-id> <KEY=VALUE>...",
+	Short: "设置配置中技能的变量值（KEY= 删除该变量的覆盖值）",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProfileSet(args[0], args[1], args[2:])
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:               "remove <name>",
+	Short:             "删除变量配置",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProfileRemove(args[0])
+	},
+}
+
+var profileDefaultCmd = &cobra.Command{
+	Use:               "default [name]",
+	Short:             "设置 apply 默认使用的变量配置",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProfileNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		} else if !profileNone {
+			return fmt.Errorf("请指定配置名称，或使用 --none 取消默认配置")
+		}
+		return runProfileDefault(name)
+	},
+}
+
+func init() {
+	profileCreateCmd.Flags().StringVar(&profileExtends, "extends", "", "继承的配置名称")
+	profileDefaultCmd.Flags().BoolVar(&profileNone, "none", false, "取消默认配置")
+
+	profileCreateCmd.RegisterFlagCompletionFunc("extends", completeProfileNames)
+
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileSetCmd)
+	profileCmd.AddCommand(profileRemoveCmd)
+	profileCmd.AddCommand(profileDefaultCmd)
+}
+
+func runProfileList() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	projectState, err := stateMgr.LoadProjectState(cwd)
+	if err != nil {
+		return err
+	}
+
+	setResult(struct {
+		Default  string                  `json:"default,omitempty"`
+		Profiles map[string]spec.Profile `json:"profiles"`
+	}{projectState.DefaultProfile, projectState.Profiles})
+
+	names := state.ProfileNames(projectState)
+	if len(names) == 0 {
+		fmt.Println("ℹ️  当前项目没有变量配置")
+		fmt.Println("使用 'skill-hub profile create <name>' 创建配置")
+		return nil
+	}
+
+	for _, name := range names {
+		profile := projectState.Profiles[name]
+		line := name
+		if profile.Extends != "" {
+			line += " (继承 " + profile.Extends + ")"
+		}
+		if name == projectState.DefaultProfile {
+			line += " [默认]"
+		}
+		fmt.Println(line)
+
+		skillIDs := make([]string, 0, len(profile.Variables))
+		for skillID := range profile.Variables {
+			skillIDs = append(skillIDs, skillID)
+		}
+		sort.Strings(skillIDs)
+		for _, skillID := range skillIDs {
+			values := profile.Variables[skillID]
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			pairs := make([]string, 0, len(keys))
+			for _, key := range keys {
+				pairs = append(pairs, key+"="+values[key])
+			}
+			fmt.Printf("  %s: %s\n", skillID, strings.Join(pairs, ", "))
+		}
+	}
+	return nil
+}
+
+func runProfileCreate(name string) error {
+	if err := spec.ValidateSkillID(name); err != nil {
+		return fmt.Errorf("无效的配置名称: %s，只能包含小写字母、数字和连字符", name)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	projectState, err := stateMgr.LoadProjectState(cwd)
+	if err != nil {
+		return err
+	}
+	if _, exists := projectState.Profiles[name]; exists {
+		return fmt.Errorf("配置 %s 已存在", name)
+	}
+
+	if err := stateMgr.SaveProfile(cwd, name, &profileExtends, "", nil); err != nil {
+		return err
+	}
+	if profileExtends != "" {
+		fmt.Printf("✓ 已创建配置 %s（继承 %s）\n", name, profileExtends)
+	} else {
+		fmt.Printf("✓ 已创建配置 %s\n", name)
+	}
+	fmt.Printf("使用 'skill-hub profile set %s <skill-id> KEY=VALUE' 设置变量\n", name)
+	return nil
+}
+
+func runProfileSet(name, skillID string, assignments []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	projectState, err := stateMgr.LoadProjectState(cwd)
+	if err != nil {
+		return err
+	}
+	if _, exists := projectState.Profiles[name]; !exists {
+		return fmt.Errorf("配置 %s 不存在，使用 'skill-hub profile create %s' 创建", name, name)
+	}
+
+	values, err := parseVariableAssignments(skillID, assignments)
+	if err != nil {
+		return err
+	}
+
+	if err := stateMgr.SaveProfile(cwd, name, nil, skillID, values); err != nil {
+		return err
+	}
+	fmt.Printf("✓ 已更新配置 %s 中技能 %s 的变量\n", name, skillID)
+	return nil
+}
+
+// parseVariableAssignments 解析 KEY=VALUE 形式的变量设置，按技能声明的变量校验，空值表示删除
+func parseVariableAssignments(skillID string, assignments []string) (map[string]string, error) {
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return nil, err
+	}
+	skill, err := skillManager.LoadSkill(skillID)
+	if err != nil {
+		return nil, fmt.Errorf("加载技能失败: %w", err)
+	}
+	declared := make(map[string]spec.Variable, len(skill.Variables))
+	for _, variable := range skill.Variables {
+		declared[variable.Name] = variable
+	}
+
+	values := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("无效的变量设置: %s，格式为 KEY=VALUE", assignment)
+		}
+		variable, exists := declared[key]
+		if !exists {
+			return nil, fmt.Errorf("技能 %s 没有声明变量 %s", skillID, key)
+		}
+		if value != "" {
+			if value, err = validateVariableValue(variable, value); err != nil {
+				return nil, err
+			}
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func runProfileRemove(name string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	if err := stateMgr.RemoveProfile(cwd, name); err != nil {
+		return err
+	}
+	fmt.Printf("✓ 已删除配置 %s\n", name)
+	return nil
+}
+
+func runProfileDefault(name string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	if err := stateMgr.SetDefaultProfile(cwd, name); err != nil {
+		return err
+	}
+	if name == "" {
+		fmt.Println("✓ 已取消默认配置，apply 将使用技能自身的变量")
+	} else {
+		fmt.Printf("✓ 默认配置已设置为 %s\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultJobs 并行下载或克隆的默认任务数
+const defaultJobs = 4
+
+// progressInterval 终端中刷新进度的间隔
+const progressInterval = 200 * time.Millisecond
+
+// progressTask 并行任务中一个技能或导入源的进度
+type progressTask struct {
+	reporter *progressReporter
+	name     string
+	status   string
+	written  int64
+	total    int64
+	started  bool
+	finished bool
+	reported bool
+	err      error
+}
+
+// progressReporter 显示并行任务的进度
+//
+// 标准输出是终端时原地刷新：已完成的任务逐行输出，进行中的任务和汇总（已完成数、字节数、预计剩余时间）
+// 显示在末尾并不断更新；否则每个任务开始和结束时各输出一行，便于写入日志。
+type progressReporter struct {
+	mu       sync.Mutex
+	out      io.Writer
+	live     bool
+	tasks    []*progressTask
+	started  time.Time
+	lines    int
+	reported int
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+// newProgressReporter 为每个名称创建一个任务
+func newProgressReporter(names []string) *progressReporter {
+	r := &progressReporter{
+		out:  os.Stdout,
+		live: !outputJSON && !outputQuiet && isTerminal(os.Stdout),
+	}
+	for _, name := range names {
+		r.tasks = append(r.tasks, &progressTask{reporter: r, name: name, status: "等待中", total: -1})
+	}
+	return r
+}
+
+// isTerminal 检查文件是否为终端，/dev/null 也是字符设备，需要排除
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
+
+// runParallel 最多同时运行 jobs 个任务并显示进度，返回每个任务的错误
+func (r *progressReporter) runParallel(jobs int, run func(i int, task *progressTask) error) []error {
+	if jobs < 1 {
+		jobs = 1
+	}
+	r.start()
+	defer r.finish()
+
+	errs := make([]error, len(r.tasks))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, task := range r.tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, task *progressTask) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			task.begin()
+			errs[i] = run(i, task)
+			task.end(errs[i])
+		}(i, task)
+	}
+	wg.Wait()
+	return errs
+}
+
+// start 开始计时，终端中定时刷新进度
+func (r *progressReporter) start() {
+	r.started = time.Now()
+	if !r.live {
+		return
+	}
+	r.stop = make(chan struct{})
+	r.stopped = make(chan struct{})
+	go func() {
+		defer close(r.stopped)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.mu.Lock()
+				r.render()
+				r.mu.Unlock()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// finish 停止刷新并输出最终结果
+func (r *progressReporter) finish() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.stopped
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.render()
+	if r.live {
+		r.clear()
+	}
+}
+
+// SetStatus 设置任务的状态，例如 下载中、克隆中
+func (t *progressTask) SetStatus(status string) {
+	t.reporter.mu.Lock()
+	defer t.reporter.mu.Unlock()
+	t.status = status
+	if !t.reporter.live {
+		fmt.Fprintf(t.reporter.out, "  … %s: %s\n", t.name, status)
+	}
+}
+
+// SetBytes 设置已下载的字节数和总字节数，总字节数未知时为 -1
+func (t *progressTask) SetBytes(written, total int64) {
+	t.reporter.mu.Lock()
+	defer t.reporter.mu.Unlock()
+	t.written, t.total = written, total
+}
+
+func (t *progressTask) begin() {
+	t.reporter.mu.Lock()
+	defer t.reporter.mu.Unlock()
+	t.started = true
+	if t.status == "等待中" {
+		t.status = "进行中"
+	}
+}
+
+func (t *progressTask) end(err error) {
+	t.reporter.mu.Lock()
+	defer t.reporter.mu.Unlock()
+	t.finished, t.err = true, err
+	if !t.reporter.live {
+		t.reporter.render()
+	}
+}
+
+// render 输出新完成的任务；终端中重绘进行中的任务和汇总。调用方持有锁
+func (r *progressReporter) render() {
+	if r.live {
+		r.clear()
+	}
+
+	// 已完成的任务按完成顺序输出，只输出一次
+	for _, task := range r.tasks {
+		if task.finished && !task.reported {
+			task.reported = true
+			r.reported++
+			fmt.Fprintf(r.out, "%s\n", task.resultLine(r.reported, len(r.tasks)))
+		}
+	}
+	if !r.live {
+		return
+	}
+
+	for _, task := range r.tasks {
+		if task.started && !task.finished {
+			fmt.Fprintf(r.out, "  ⬇ %-32s %s\n", task.name, task.progressText())
+			r.lines++
+		}
+	}
+	fmt.Fprintf(r.out, "%s\n", r.summary())
+	r.lines++
+}
+
+// clear 清除上次绘制的进行中任务和汇总。调用方持有锁
+func (r *progressReporter) clear() {
+	for ; r.lines > 0; r.lines-- {
+		fmt.Fprint(r.out, "\033[1A\033[2K")
+	}
+}
+
+// summary 返回汇总行：已完成数、已下载字节数和预计剩余时间
+func (r *progressReporter) summary() string {
+	var written, remaining int64
+	done := 0
+	unknown := false
+	for _, task := range r.tasks {
+		written += task.written
+		if task.finished {
+			done++
+			continue
+		}
+		if task.total < 0 {
+			unknown = true
+		} else {
+			remaining += task.total - task.written
+		}
+	}
+	line := fmt.Sprintf("[%d/%d] %s", done, len(r.tasks), formatBytes(written))
+	elapsed := time.Since(r.started)
+	if !unknown && written > 0 && elapsed > 0 {
+		rate := float64(written) / elapsed.Seconds()
+		eta := time.Duration(float64(remaining)/rate) * time.Second
+		line += fmt.Sprintf("  %s/s  剩余约 %s", formatBytes(int64(rate)), eta.Round(time.Second))
+	}
+	return line
+}
+
+// progressText 返回进行中任务的状态和字节数
+func (t *progressTask) progressText() string {
+	switch {
+	case t.total > 0:
+		return fmt.Sprintf("%s %s / %s (%d%%)", t.status, formatBytes(t.written), formatBytes(t.total), t.written*100/t.total)
+	case t.written > 0:
+		return fmt.Sprintf("%s %s", t.status, formatBytes(t.written))
+	}
+	return t.status
+}
+
+// resultLine 返回已完成任务的结果行
+func (t *progressTask) resultLine(index, count int) string {
+	prefix := fmt.Sprintf("[%d/%d]", index, count)
+	if t.err != nil {
+		return fmt.Sprintf("❌ %s %s: %v", prefix, t.name, t.err)
+	}
+	line := fmt.Sprintf("✓ %s %s", prefix, t.name)
+	if t.written > 0 {
+		line += fmt.Sprintf(" (%s)", formatBytes(t.written))
+	}
+	return line
+}
+
+// formatBytes 以 B、KB、MB、GB 显示字节数
+func formatBytes(n int64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	value := float64(n)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + " " + units[unit]
+}
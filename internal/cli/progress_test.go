@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunParallel(t *testing.T) {
+	var out bytes.Buffer
+	reporter := newProgressReporter([]string{"a", "b", "c", "d", "e"})
+	reporter.out, reporter.live = &out, false
+
+	var running, peak int32
+	errs := reporter.runParallel(2, func(i int, task *progressTask) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		task.SetStatus("下载中")
+		task.SetBytes(2048, 2048)
+		if task.name == "c" {
+			return fmt.Errorf("not found")
+		}
+		return nil
+	})
+
+	if peak > 2 {
+		t.Errorf("runParallel() ran %d tasks at once, want at most 2", peak)
+	}
+	for i, err := range errs {
+		if (err != nil) != (i == 2) {
+			t.Errorf("errs[%d] = %v", i, err)
+		}
+	}
+	text := out.String()
+	if strings.Count(text, "✓ [") != 4 || !strings.Contains(text, "❌ [") || !strings.Contains(text, "c: not found") {
+		t.Errorf("output = %s", text)
+	}
+	if !strings.Contains(text, "(2 KB)") || !strings.Contains(text, "[5/5]") {
+		t.Errorf("output should report bytes and progress, got %s", text)
+	}
+}
+
+func TestProgressSummary(t *testing.T) {
+	reporter := newProgressReporter([]string{"a", "b"})
+	reporter.started = time.Now().Add(-2 * time.Second)
+	reporter.tasks[0].written, reporter.tasks[0].total, reporter.tasks[0].finished = 2048, 2048, true
+	reporter.tasks[1].written, reporter.tasks[1].total = 1024, 3072
+
+	// 2秒下载 3 KB，剩余 2 KB 约需 1秒
+	if got := reporter.summary(); !strings.HasPrefix(got, "[1/2] 3 KB") || !strings.Contains(got, "剩余约 1s") {
+		t.Errorf("summary() = %q", got)
+	}
+	if got := reporter.tasks[1].progressText(); got != "等待中 1 KB / 3 KB (33%)" {
+		t.Errorf("progressText() = %q", got)
+	}
+	for n, want := range map[int64]string{512: "512 B", 1536: "1.5 KB", 5 << 20: "5 MB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/git"
+	"skill-hub/internal/pack"
+	"skill-hub/internal/registry"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+)
+
+var (
+	publishBump    string
+	publishNoBump  bool
+	publishTo      string
+	publishMessage string
+	publishDryRun  bool
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish <skill-id>",
+	Short: "验证、提升版本并发布技能到远程仓库或注册表",
+	Long: `发布技能的一个新版本：
+  1. 验证技能，有错误时停止发布
+  2. 提升 SKILL.md 中的版本号（默认提升修订号），并在 CHANGELOG.md 中记录说明
+  3. 刷新技能仓库的 registry.json
+  4. 发布：
+     - 默认只提交技能目录，创建标签 <技能ID>/v<版本>，将提交和标签推送到技能仓库的远程仓库
+     - --to <目录> 将技能打包到静态HTTP技能注册表的目录（'registry build' 的输出目录），
+       并更新 index.json 中该技能的索引项，之后将目录同步到静态文件服务器
+
+使用 --no-bump 发布当前版本（例如首次发布，或推送失败后重试），--dry-run 只显示将要执行的操作。
+
+示例:
+  skill-hub publish git-expert
+  skill-hub publish git-expert --bump minor -m "新增 rebase 规则"
+  skill-hub publish git-expert --to ./public`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSkillIDs,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPublish(args[0])
+	},
+}
+
+func init() {
+	publishCmd.Flags().StringVar(&publishBump, "bump", "", "版本号提升方式: major, minor, patch (默认 patch)")
+	publishCmd.Flags().BoolVar(&publishNoBump, "no-bump", false, "不提升版本号，发布当前版本")
+	publishCmd.Flags().StringVar(&publishTo, "to", "", "发布到静态HTTP技能注册表的目录 (为空时推送到技能仓库的远程仓库)")
+	publishCmd.Flags().StringVarP(&publishMessage, "message", "m", "", "记录到更新日志的发布说明")
+	publishCmd.Flags().BoolVar(&publishDryRun, "dry-run", false, "只显示将要执行的操作，不修改任何文件")
+	publishCmd.RegisterFlagCompletionFunc("bump", fixedCompletions(engine.BumpMajor, engine.BumpMinor, engine.BumpPatch))
+	rootCmd.AddCommand(publishCmd)
+}
+
+// publishResult --json 模式下 publish 命令的结果
+type publishResult struct {
+	SkillID         string `json:"skill_id"`
+	PreviousVersion string `json:"previous_version"`
+	Version         string `json:"version"`
+	Tag             string `json:"tag,omitempty"`
+	Registry        string `json:"registry,omitempty"`
+	Archive         string `json:"archive,omitempty"`
+	SHA256          string `json:"sha256,omitempty"`
+	DryRun          bool   `json:"dry_run,omitempty"`
+}
+
+func runPublish(skillID string) error {
+	if err := spec.ValidateSkillID(skillID); err != nil {
+		return err
+	}
+	if publishNoBump && publishBump != "" {
+		return fmt.Errorf("--bump 和 --no-bump 不能同时使用")
+	}
+	registryDir, err := publishRegistryDir(publishTo)
+	if err != nil {
+		return err
+	}
+
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	if !manager.SkillExists(skillID) {
+		return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", skillID)
+	}
+	skill, err := manager.LoadSkill(skillID)
+	if err != nil {
+		return fmt.Errorf("加载技能失败: %w", err)
+	}
+	skillDir := manager.GetSkillDir(skillID)
+
+	fmt.Printf("🔍 验证技能 %s...\n", skillID)
+	valid, issues, err := validateAndFixSkill(filepath.Join(skillDir, "SKILL.md"), skillID, false, false, false, false)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		fmt.Printf("  %s\n", issue)
+	}
+	if !valid {
+		return fmt.Errorf("技能 %s 验证失败，修正后重新发布", skillID)
+	}
+	if err := engine.ValidateToolSpec(skillDir, skill); err != nil {
+		return fmt.Errorf("技能 %s 的工具定义无效，修正后重新发布: %w", skillID, err)
+	}
+	fmt.Println("✓ 验证通过")
+
+	version, err := nextPublishVersion(skill.Version, publishBump, publishNoBump)
+	if err != nil {
+		return err
+	}
+
+	// 推送到技能仓库时提前检查远程仓库和标签，避免修改版本号后才发现无法发布
+	var repo *git.SkillRepository
+	if registryDir == "" {
+		if repo, err = git.NewSkillRepository(); err != nil {
+			return err
+		}
+		if err := repo.CheckRelease(skillID, version); err != nil {
+			return err
+		}
+	}
+
+	result := &publishResult{SkillID: skillID, PreviousVersion: skill.Version, Version: version, Registry: registryDir}
+	setResult(result)
+
+	if publishDryRun {
+		fmt.Printf("\n📋 发布技能 %s:\n", skillID)
+		fmt.Printf("  版本: %s -> %s\n", skill.Version, version)
+		if registryDir != "" {
+			fmt.Printf("  注册表: %s (%s)\n", registryDir, registry.ArchivePath(skillID, version))
+		} else {
+			fmt.Printf("  标签: %s，推送到技能仓库的远程仓库\n", git.SkillTag(skillID, version))
+		}
+		fmt.Println("\n✅ 预演完成，未写入任何文件。去掉 --dry-run 执行发布")
+		result.DryRun = true
+		return nil
+	}
+
+	if version != skill.Version {
+		if err := bumpSkillVersion(skillDir, version, publishMessage); err != nil {
+			return err
+		}
+		fmt.Printf("✓ 版本更新: %s -> %s\n", skill.Version, version)
+	}
+	if err := refreshSkillRegistryAfterArchive(); err != nil {
+		fmt.Printf("⚠️  刷新技能索引失败: %v\n", err)
+	}
+
+	detail := registryDir
+	if registryDir != "" {
+		meta, err := publishToRegistry(skillDir, skillID, registryDir)
+		if err != nil {
+			return err
+		}
+		result.Archive, result.SHA256 = meta.Archive, meta.SHA256
+		fmt.Printf("✓ 打包 %s\n", meta.Archive)
+		fmt.Printf("✓ 更新 %s\n", registry.IndexFileName)
+		fmt.Printf("\n✅ 技能 %s@%s 已发布到注册表 %s\n", skillID, version, registryDir)
+		fmt.Println("将注册表目录同步到静态文件服务器后，使用方即可通过 'skill-hub update' 获取新版本")
+	} else {
+		fmt.Println("\n🚀 推送到远程仓库...")
+		tag, err := repo.ReleaseSkill(skillID, version, fmt.Sprintf("发布技能 %s@%s", skillID, version))
+		if err != nil {
+			fmt.Println("新版本已保存在本地技能仓库，修正问题后使用 'skill-hub publish --no-bump' 重试")
+			return fmt.Errorf("发布失败: %w", err)
+		}
+		result.Tag, detail = tag, tag
+		fmt.Printf("\n✅ 技能 %s@%s 已发布，标签 %s\n", skillID, version, tag)
+	}
+
+	recordAudit(state.AuditEntry{
+		Operation: state.OpPublish,
+		SkillID:   skillID,
+		Version:   version,
+		Detail:    detail,
+	})
+	return nil
+}
+
+// publishRegistryDir 解析 --to 指定的注册表目录，支持 file:// 地址
+//
+// 静态HTTP技能注册表没有上传接口，需要发布到本地目录后同步到服务器。
+func publishRegistryDir(to string) (string, error) {
+	if to == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(to, "http://") || strings.HasPrefix(to, "https://") {
+		return "", fmt.Errorf("静态HTTP技能注册表不支持直接上传，请发布到注册表的本地目录后同步到服务器: %s", to)
+	}
+	dir := strings.TrimPrefix(to, "file://")
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		return "", fmt.Errorf("注册表路径不是目录: %s", dir)
+	}
+	return dir, nil
+}
+
+// nextPublishVersion 返回发布的版本号，noBump 时为当前版本，否则按 part 提升（默认提升修订号）
+func nextPublishVersion(current, part string, noBump bool) (string, error) {
+	version, err := engine.ParseStrictVersion(current)
+	if err != nil {
+		return "", fmt.Errorf("技能的版本号不是有效的语义化版本，请先修正 SKILL.md 中的 metadata.version: %w", err)
+	}
+	if noBump {
+		return current, nil
+	}
+	if part == "" {
+		part = engine.BumpPatch
+	}
+	next, err := version.Bump(part)
+	if err != nil {
+		return "", err
+	}
+	return next.String(), nil
+}
+
+// bumpSkillVersion 更新 SKILL.md 中的版本号，并在更新日志中记录发布说明
+func bumpSkillVersion(skillDir, version, message string) error {
+	skillMdPath := filepath.Join(skillDir, "SKILL.md")
+	content, err := os.ReadFile(skillMdPath)
+	if err != nil {
+		return fmt.Errorf("读取SKILL.md失败: %w", err)
+	}
+	updated, err := updateVersionInFrontmatter(string(content), version)
+	if err != nil {
+		return fmt.Errorf("更新frontmatter版本号失败: %w", err)
+	}
+	if err := os.WriteFile(skillMdPath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("更新SKILL.md失败: %w", err)
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("发布 %s", version)
+	}
+	entry := engine.ChangelogEntry{
+		Version: version,
+		Date:    time.Now().Format("2006-01-02"),
+		Body:    changelogBody(message),
+	}
+	if err := engine.AppendChangelog(skillDir, entry); err != nil {
+		return fmt.Errorf("记录更新日志失败: %w", err)
+	}
+	return nil
+}
+
+// publishToRegistry 将技能当前版本打包到注册表目录，并添加或替换 index.json 中该技能的索引项
+//
+// 其他技能的索引项和旧版本的归档保持不变，替换时保留下载次数和评分。
+func publishToRegistry(skillDir, skillID, registryDir string) (*spec.SkillMetadata, error) {
+	meta, err := parseSkillMetadata(filepath.Join(skillDir, "SKILL.md"), skillID)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := pack.BuildManifest(skillDir, skillID, meta.Name, meta.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := exportRegistryArchive(skillDir, registryDir, meta, manifest); err != nil {
+		return nil, err
+	}
+
+	index, err := readRegistry(filepath.Join(registryDir, registry.IndexFileName))
+	if err != nil {
+		return nil, err
+	}
+	if index == nil {
+		index = &spec.Registry{Version: "1.0.0", Skills: []spec.SkillMetadata{}}
+	}
+	index.Upsert(*meta)
+	if err := writeRegistryIndex(registryDir, index); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
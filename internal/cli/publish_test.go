@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/registry"
+	"skill-hub/pkg/spec"
+)
+
+func TestNextPublishVersion(t *testing.T) {
+	tests := []struct {
+		current string
+		part    string
+		noBump  bool
+		want    string
+	}{
+		{"1.2.3", "", false, "1.2.4"},
+		{"1.2.3", engine.BumpMinor, false, "1.3.0"},
+		{"1.2.3", engine.BumpMajor, false, "2.0.0"},
+		{"1.2.3", "", true, "1.2.3"},
+	}
+	for _, tt := range tests {
+		got, err := nextPublishVersion(tt.current, tt.part, tt.noBump)
+		if err != nil || got != tt.want {
+			t.Errorf("nextPublishVersion(%q, %q, %v) = %q, %v, want %q", tt.current, tt.part, tt.noBump, got, err, tt.want)
+		}
+	}
+	if _, err := nextPublishVersion("latest", "", true); err == nil {
+		t.Error("nextPublishVersion() should reject invalid versions")
+	}
+	if _, err := nextPublishVersion("1.0.0", "huge", false); err == nil {
+		t.Error("nextPublishVersion() should reject invalid bump parts")
+	}
+}
+
+func TestPublishToRegistry(t *testing.T) {
+	skillDir := filepath.Join(t.TempDir(), "git-expert")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	skillMd := "---\nname: git-expert\ndescription: Demo\nmetadata:\n  version: 1.0.0\n---\n# Demo\n"
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(skillMd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := bumpSkillVersion(skillDir, "1.1.0", "新增规则"); err != nil {
+		t.Fatalf("bumpSkillVersion() error = %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+	if !strings.Contains(string(content), "version: 1.1.0") {
+		t.Errorf("SKILL.md = %s", content)
+	}
+	entries, err := engine.ReadChangelog(skillDir)
+	if err != nil || len(entries) != 1 || entries[0].Version != "1.1.0" || entries[0].Body != "- 新增规则" {
+		t.Errorf("changelog = %+v, %v", entries, err)
+	}
+
+	// 已有的注册表：其他技能的索引项和该技能的统计信息保留
+	registryDir := t.TempDir()
+	previous := spec.Registry{Version: "1.0.0", Skills: []spec.SkillMetadata{
+		{ID: "acme/lint", Version: "0.2.0"},
+		{ID: "git-expert", Version: "1.0.0", Downloads: 42},
+	}}
+	data, _ := json.Marshal(previous)
+	os.WriteFile(filepath.Join(registryDir, registry.IndexFileName), data, 0644)
+
+	meta, err := publishToRegistry(skillDir, "git-expert", registryDir)
+	if err != nil {
+		t.Fatalf("publishToRegistry() error = %v", err)
+	}
+	if meta.Archive != registry.ArchivePath("git-expert", "1.1.0") || meta.SHA256 == "" {
+		t.Errorf("meta = %+v", meta)
+	}
+	if _, err := os.Stat(filepath.Join(registryDir, filepath.FromSlash(meta.Archive))); err != nil {
+		t.Errorf("archive not written: %v", err)
+	}
+	index, err := readRegistry(filepath.Join(registryDir, registry.IndexFileName))
+	if err != nil || len(index.Skills) != 2 {
+		t.Fatalf("index.json = %+v, %v", index, err)
+	}
+	if published, _ := index.Find("git-expert"); published.Version != "1.1.0" || published.Downloads != 42 || published.SHA256 != meta.SHA256 {
+		t.Errorf("git-expert = %+v", published)
+	}
+
+	// 新的注册表目录
+	emptyDir := filepath.Join(t.TempDir(), "public")
+	if _, err := publishToRegistry(skillDir, "git-expert", emptyDir); err != nil {
+		t.Fatalf("publishToRegistry() to new dir error = %v", err)
+	}
+	if index, err := readRegistry(filepath.Join(emptyDir, registry.IndexFileName)); err != nil || len(index.Skills) != 1 {
+		t.Errorf("new index.json = %+v, %v", index, err)
+	}
+}
+
+func TestPublishRegistryDir(t *testing.T) {
+	if dir, err := publishRegistryDir(""); err != nil || dir != "" {
+		t.Errorf("publishRegistryDir(\"\") = %q, %v", dir, err)
+	}
+	if dir, err := publishRegistryDir("file:///srv/skills"); err != nil || dir != "/srv/skills" {
+		t.Errorf("publishRegistryDir(file://) = %q, %v", dir, err)
+	}
+	if _, err := publishRegistryDir("https://skills.example.com"); err == nil {
+		t.Error("publishRegistryDir() should reject HTTP URLs")
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/oci"
+	"skill-hub/internal/pack"
+)
+
+var pushCmd = &cobra.Command{
+	Use:   "push <skill-id> <oci-reference>",
+	Short: "将技能作为OCI制品推送到容器镜像仓库",
+	Long: `将技能打包为 'skill-hub export' 格式的归档，作为OCI制品推送到 ghcr.io 等容器镜像仓库，
+复用现有的仓库、认证和保留策略分发技能。制品格式与 ORAS 兼容，也可以用 oras pull 下载。
+
+引用未指定标签时使用技能版本作为标签。认证依次使用环境变量 SKILL_HUB_OCI_USERNAME / SKILL_HUB_OCI_PASSWORD、
+'skill-hub login' 保存的凭据和 docker login 保存的凭据。
+
+示例:
+  skill-hub push git-expert oci://ghcr.io/acme/skills/git-expert
+  skill-hub import oci://ghcr.io/acme/skills/git-expert:1.0.0`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeSkillIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPush(cmd, args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pushCmd)
+}
+
+// pushResult --json 模式下 push 命令的结果
+type pushResult struct {
+	SkillID   string `json:"skill_id"`
+	Version   string `json:"version"`
+	Reference string `json:"reference"`
+	Digest    string `json:"digest"`
+}
+
+func runPush(cmd *cobra.Command, skillID, target string) error {
+	ref, err := oci.ParseReference(target)
+	if err != nil {
+		return err
+	}
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	if !manager.SkillExists(skillID) {
+		return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", skillID)
+	}
+	skill, err := manager.LoadSkill(skillID)
+	if err != nil {
+		return fmt.Errorf("加载技能失败: %w", err)
+	}
+	if ref.Reference == "" {
+		ref.Reference = skill.Version
+	}
+
+	tmpDir, err := os.MkdirTemp("", "skill-hub-push-")
+	if err != nil {
+		return fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	skillDir := manager.GetSkillDir(skillID)
+	manifest, err := pack.BuildManifest(skillDir, skillID, skill.Name, skill.Version)
+	if err != nil {
+		return err
+	}
+	archive, err := pack.Export(skillDir, manifest, pack.FormatTar, filepath.Join(tmpDir, pack.DefaultOutput(manifest, pack.FormatTar)))
+	if err != nil {
+		return fmt.Errorf("打包技能失败: %w", err)
+	}
+
+	credentials, err := oci.LoadCredentials(ref.Registry)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("正在推送 %s@%s 到 %s\n", skillID, skill.Version, ref)
+	digest, err := oci.NewClient(credentials).Push(cmd.Context(), ref, archive, map[string]string{
+		oci.AnnotationSkillID: skillID,
+		oci.AnnotationVersion: skill.Version,
+	})
+	if err != nil {
+		return err
+	}
+
+	setResult(pushResult{SkillID: skillID, Version: skill.Version, Reference: ref.String(), Digest: digest})
+	fmt.Printf("✅ 已推送: %s\n", ref)
+	fmt.Printf("   摘要: %s\n", digest)
+	return nil
+}
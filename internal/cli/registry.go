@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/config"
+	"skill-hub/internal/credentials"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/pack"
+	"skill-hub/internal/registry"
+	"skill-hub/pkg/spec"
+)
+
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "管理静态HTTP技能注册表",
+	Long: `静态HTTP技能注册表是静态文件服务器上的一个目录，不需要git：
+  index.json                        技能索引，记录每个技能的最新版本和内容摘要
+  skills/<技能ID>-<版本>.tar.gz       'skill-hub export' 格式的技能归档
+
+在配置文件中设置 registry_url（以及可选的 registry_token）后，
+update、import、search 和 outdated 通过注册表获取技能。`,
+}
+
+var registryBuildCmd = &cobra.Command{
+	Use:   "build <output-dir>",
+	Short: "从技能仓库生成静态HTTP技能注册表",
+	Long: `将技能仓库中的所有技能打包为归档，并生成 index.json，输出目录可以直接部署到任意静态文件服务器。
+
+重复生成到同一目录时保留旧版本的归档和 index.json 中的下载次数、评分。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRegistryBuild(args[0])
+	},
+}
+
+// offlineMode --offline 全局标志
+var offlineMode bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&offlineMode, "offline", false, "离线模式：只使用缓存的注册表索引和技能归档，不访问网络")
+	registryCmd.AddCommand(registryBuildCmd)
+	rootCmd.AddCommand(registryCmd)
+}
+
+// isOffline 检查是否处于离线模式：指定了 --offline 或配置文件中设置了 offline: true
+func isOffline() bool {
+	if offlineMode {
+		return true
+	}
+	cfg, err := config.GetConfig()
+	return err == nil && cfg.Offline
+}
+
+func runRegistryBuild(outputDir string) error {
+	skillsDir, err := engine.GetSkillsDir()
+	if err != nil {
+		return fmt.Errorf("获取技能目录失败: %w", err)
+	}
+	index, err := buildStaticRegistry(skillsDir, outputDir)
+	if err != nil {
+		return err
+	}
+	setResult(index)
+	fmt.Printf("✅ 已生成注册表: %s (%d 个技能)\n", outputDir, len(index.Skills))
+	return nil
+}
+
+// buildStaticRegistry 将技能目录中的技能打包到 outputDir，并写入 index.json
+func buildStaticRegistry(skillsDir, outputDir string) (*spec.Registry, error) {
+	entries, err := os.ReadDir(skillsDir)
+	if err != nil {
+		return nil, fmt.Errorf("读取skills目录失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(outputDir, registry.ArchivesDir), 0755); err != nil {
+		return nil, fmt.Errorf("创建输出目录失败: %w", err)
+	}
+
+	index := &spec.Registry{Version: "1.0.0", Skills: []spec.SkillMetadata{}}
+	owners := make(map[string]string) // 归档名称 -> 技能ID
+	for _, skillID := range collectSkillIDs(skillsDir, entries) {
+		if err := spec.CheckFlatSkillOwner(skillID, owners[spec.FlatSkillID(skillID)]); err != nil {
+			fmt.Printf("⚠️  跳过技能 %s: %v\n", skillID, err)
+			continue
+		}
+		owners[spec.FlatSkillID(skillID)] = skillID
+		skillDir := filepath.Join(skillsDir, skillID)
+		meta, err := parseSkillMetadata(filepath.Join(skillDir, "SKILL.md"), skillID)
+		if err != nil {
+			fmt.Printf("⚠️  跳过技能 %s: %v\n", skillID, err)
+			continue
+		}
+		manifest, err := pack.BuildManifest(skillDir, skillID, meta.Name, meta.Version)
+		if err != nil {
+			fmt.Printf("⚠️  跳过技能 %s: %v\n", skillID, err)
+			continue
+		}
+
+		if err := exportRegistryArchive(skillDir, outputDir, meta, manifest); err != nil {
+			return nil, err
+		}
+		index.Skills = append(index.Skills, *meta)
+		fmt.Printf("✓ %s@%s\n", skillID, meta.Version)
+	}
+
+	if previous, err := readRegistry(filepath.Join(outputDir, registry.IndexFileName)); err == nil {
+		index.CarryStats(previous)
+	}
+	if err := writeRegistryIndex(outputDir, index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// writeRegistryIndex 将技能索引写入注册表目录的 index.json
+func writeRegistryIndex(outputDir string, index *spec.Registry) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化 %s 失败: %w", registry.IndexFileName, err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, registry.IndexFileName), data, 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", registry.IndexFileName, err)
+	}
+	return nil
+}
+
+// exportRegistryArchive 将技能当前版本打包到注册表目录，并在索引信息中记录归档路径和内容摘要
+//
+// 当前版本的归档总是重新生成，旧版本的归档保持不变。
+func exportRegistryArchive(skillDir, outputDir string, meta *spec.SkillMetadata, manifest *pack.Manifest) error {
+	archive := registry.ArchivePath(meta.ID, meta.Version)
+	output := filepath.Join(outputDir, filepath.FromSlash(archive))
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	if err := os.Remove(output); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除旧归档失败: %w", err)
+	}
+	if _, err := pack.Export(skillDir, manifest, pack.FormatTar, output); err != nil {
+		return fmt.Errorf("打包技能 %s 失败: %w", meta.ID, err)
+	}
+
+	meta.SHA256 = manifest.Digest()
+	meta.Archive = archive
+	return nil
+}
+
+// newRegistryClient 按配置创建HTTP技能注册表客户端，未配置 registry_url 时返回nil
+func newRegistryClient() (*registry.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RegistryURL == "" {
+		return nil, nil
+	}
+	token := cfg.RegistryToken
+	if token == "" {
+		token = credentials.Token(cfg.RegistryURL)
+	}
+	client, err := registry.NewClient(cfg.RegistryURL, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SetMirrors(cfg.RegistryMirrors); err != nil {
+		return nil, err
+	}
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	client.SetCache(filepath.Join(cacheDir, "registry"), offlineMode || cfg.Offline)
+	return client, nil
+}
+
+// updateFromRegistry 从HTTP技能注册表下载内容有变化的技能并刷新 registry.json，返回注册表中的技能数和保持当前版本的技能
+func updateFromRegistry(ctx context.Context, client *registry.Client, selection *updateSelection) (int, []string, error) {
+	if client.Offline() {
+		fmt.Printf("📦 离线模式，使用缓存的技能注册表: %s\n", client.URL())
+	} else {
+		fmt.Printf("🌐 技能注册表: %s\n", client.URL())
+	}
+	index, err := client.Index(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	repoDir, err := config.GetRepoPath()
+	if err != nil {
+		return 0, nil, err
+	}
+	skillsDir, err := engine.GetSkillsDir()
+	if err != nil {
+		return 0, nil, fmt.Errorf("获取技能目录失败: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "skill-hub-registry-")
+	if err != nil {
+		return 0, nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	versions := skillVersions()
+	var pending []spec.SkillMetadata
+	var held []string
+	for _, meta := range index.Skills {
+		if spec.ValidateSkillID(meta.ID) != nil {
+			fmt.Printf("⚠️  跳过无效的技能ID: %q\n", meta.ID)
+			continue
+		}
+		// 内容摘要一致且归档已缓存的技能不需要下载，缓存的归档供离线模式导入
+		unchanged := meta.SHA256 != "" && pack.VerifyDigest(filepath.Join(skillsDir, meta.ID), meta.SHA256) == nil
+		if unchanged && (client.Offline() || client.Cached(meta)) {
+			continue
+		}
+		// 未选择或新版本不在订阅通道中的技能保持当前版本，需要确认的技能下载后显示更新日志再确认
+		if !unchanged && (!selection.includes(meta.ID) || !selection.acceptChannel(meta.ID, meta.Version, meta.Channel)) {
+			held = append(held, meta.ID)
+			continue
+		}
+		pending = append(pending, meta)
+	}
+
+	// 并行下载，全部下载完成后再依次安装
+	names := make([]string, len(pending))
+	for i, meta := range pending {
+		names[i] = meta.ID + "@" + meta.Version
+	}
+	dirs := make([]string, len(pending))
+	errs := newProgressReporter(names).runParallel(updateJobs, func(i int, task *progressTask) error {
+		task.SetStatus("下载中")
+		dir, err := client.DownloadWithProgress(ctx, pending[i], filepath.Join(tmpDir, fmt.Sprint(i)), task.SetBytes)
+		dirs[i] = dir
+		return err
+	})
+
+	failed := 0
+	for i, meta := range pending {
+		err := errs[i]
+		if err == nil && !confirmRegistrySkill(selection, meta, versions, filepath.Join(skillsDir, meta.ID), dirs[i]) {
+			held = append(held, meta.ID)
+			continue
+		}
+		if err == nil {
+			err = installImportedSkill(dirs[i], skillsDir, meta.ID, meta.ID)
+		}
+		if err != nil {
+			if errs[i] == nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+			failed++
+			continue
+		}
+		debugf("已安装 %s@%s", meta.ID, meta.Version)
+	}
+
+	// 注册表的索引作为 registry.json 的基础，保留其中的下载次数和评分
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return 0, nil, fmt.Errorf("序列化registry失败: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "registry.json"), data, 0644); err != nil {
+		return 0, nil, fmt.Errorf("写入registry.json失败: %w", err)
+	}
+	if err := refreshSkillRegistry(repoDir); err != nil {
+		return 0, nil, err
+	}
+
+	if failed > 0 {
+		fmt.Printf("⚠️  %d 个技能下载失败，保留本地版本\n", failed)
+	}
+	return len(index.Skills), held, nil
+}
+
+// confirmRegistrySkill 检查是否安装下载的技能，需要确认时先输出本地版本到下载版本的更新日志
+//
+// localDir 为本地技能目录，downloadDir 为下载解压后的技能目录；内容未变化的技能不需要确认。
+func confirmRegistrySkill(selection *updateSelection, meta spec.SkillMetadata, versions map[string]string, localDir, downloadDir string) bool {
+	if meta.SHA256 != "" && pack.VerifyDigest(localDir, meta.SHA256) == nil {
+		return true
+	}
+	from, exists := versions[meta.ID]
+	change := "新技能 " + meta.Version
+	fromDir := ""
+	if exists {
+		change = from + " -> " + meta.Version
+		if from == meta.Version {
+			change = "内容有变化"
+		}
+		fromDir = localDir
+	}
+	return selection.accept(meta.ID, change, func() {
+		printSkillChange(meta.ID, from, fromDir, meta.Version, downloadDir, "   ")
+	})
+}
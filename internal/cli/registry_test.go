@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"skill-hub/internal/pack"
+	"skill-hub/internal/registry"
+	"skill-hub/pkg/spec"
+)
+
+func TestBuildStaticRegistry(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeSkill := func(id, version string) {
+		dir := filepath.Join(skillsDir, filepath.FromSlash(id))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		_, name := spec.SplitSkillID(id)
+		content := "---\nname: " + name + "\ndescription: Demo\nversion: " + version + "\n---\n# Demo\n"
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSkill("git-expert", "1.0.0")
+	writeSkill("acme/lint", "0.2.0")
+
+	outputDir := t.TempDir()
+	// 上次生成的统计信息和旧版本归档保留
+	previous := spec.Registry{Skills: []spec.SkillMetadata{{ID: "git-expert", Downloads: 42}}}
+	data, _ := json.Marshal(previous)
+	os.WriteFile(filepath.Join(outputDir, registry.IndexFileName), data, 0644)
+	os.MkdirAll(filepath.Join(outputDir, registry.ArchivesDir), 0755)
+	oldArchive := filepath.Join(outputDir, filepath.FromSlash(registry.ArchivePath("git-expert", "0.9.0")))
+	os.WriteFile(oldArchive, []byte("old"), 0644)
+
+	index, err := buildStaticRegistry(skillsDir, outputDir)
+	if err != nil {
+		t.Fatalf("buildStaticRegistry() error = %v", err)
+	}
+	if len(index.Skills) != 2 {
+		t.Fatalf("index = %+v", index.Skills)
+	}
+	written, err := readRegistry(filepath.Join(outputDir, registry.IndexFileName))
+	if err != nil || !reflect.DeepEqual(written, index) {
+		t.Fatalf("index.json = %+v, %v", written, err)
+	}
+
+	for _, meta := range index.Skills {
+		archive := filepath.Join(outputDir, filepath.FromSlash(meta.Archive))
+		if meta.Archive != registry.ArchivePath(meta.ID, meta.Version) {
+			t.Errorf("%s archive = %q", meta.ID, meta.Archive)
+		}
+		extractDir := t.TempDir()
+		if err := pack.Extract(archive, extractDir); err != nil {
+			t.Fatalf("Extract(%s) error = %v", archive, err)
+		}
+		if err := pack.VerifyDigest(filepath.Join(extractDir, spec.FlatSkillID(meta.ID)), meta.SHA256); err != nil {
+			t.Errorf("%s digest: %v", meta.ID, err)
+		}
+	}
+	if meta, _ := index.Find("git-expert"); meta.Downloads != 42 {
+		t.Errorf("Downloads = %d, want carried over", meta.Downloads)
+	}
+	if _, err := os.Stat(oldArchive); err != nil {
+		t.Errorf("old version archive removed: %v", err)
+	}
+
+	// 重复生成时覆盖当前版本的归档
+	if _, err := buildStaticRegistry(skillsDir, outputDir); err != nil {
+		t.Fatalf("buildStaticRegistry() rerun error = %v", err)
+	}
+}
+
+func TestBuildStaticRegistryFlatNameConflict(t *testing.T) {
+	skillsDir := t.TempDir()
+	for _, id := range []string{"acme/lint", "acme-lint"} {
+		dir := filepath.Join(skillsDir, filepath.FromSlash(id))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		content := "---\nname: lint\ndescription: Demo\nversion: 1.0.0\n---\n# Demo\n"
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 两个技能的归档名称相同，只导出第一个，避免后者覆盖前者的归档
+	index, err := buildStaticRegistry(skillsDir, t.TempDir())
+	if err != nil {
+		t.Fatalf("buildStaticRegistry() error = %v", err)
+	}
+	if len(index.Skills) != 1 {
+		t.Errorf("index = %+v, want one of the conflicting skills", index.Skills)
+	}
+}
+
+func TestOutdatedSkills(t *testing.T) {
+	index := &spec.Registry{Skills: []spec.SkillMetadata{
+		{ID: "zeta", Version: "2.0.0"},
+		{ID: "alpha", Version: "1.10.0"},
+		{ID: "same", Version: "1.0.0"},
+		{ID: "older", Version: "0.9.0"},
+		{ID: "missing", Version: "1.0.0"},
+	}}
+	local := map[string]string{"zeta": "1.0.0", "alpha": "1.9.0", "same": "1.0.0", "older": "1.0.0"}
+
+	got := outdatedSkills(index, local)
+	want := []outdatedSkill{
+		{SkillID: "alpha", Current: "1.9.0", Latest: "1.10.0"},
+		{SkillID: "zeta", Current: "1.0.0", Latest: "2.0.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("outdatedSkills() = %+v, want %+v", got, want)
+	}
+}
@@ -1,17 +1,22 @@
 package cli
 
 import (
-	"bufio"
-	"crypto/sha256"
+	"errors"
 	"fmt"
 	"os"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
 
 	"skill-hub/internal/adapter"
 	"skill-hub/internal/adapter/claude"
 	"skill-hub/internal/adapter/cursor"
 	"skill-hub/internal/adapter/opencode"
+	"skill-hub/internal/adapter/shell"
+	"skill-hub/internal/diff"
 	"skill-hub/internal/engine"
+	"skill-hub/internal/resource"
 	"skill-hub/internal/state"
 	"skill-hub/pkg/spec"
 
@@ -19,8 +24,11 @@ import (
 )
 
 var (
-	removeTarget string
-	forceRemove  bool
+	removeTarget    string
+	forceRemove     bool
+	removeGlobal    bool
+	removeYes       bool
+	removeSavePatch string
 )
 
 var removeCmd = &cobra.Command{
@@ -30,36 +38,51 @@ var removeCmd = &cobra.Command{
 
 移除操作会：
 1. 从状态文件中删除技能记录
-2. 从目标工具配置文件中物理清理技能内容
-3. 如果检测到本地修改，会提示警告
-
-使用 --target 参数指定目标工具 (cursor/claude_code/open_code/all)。
-使用 --force 参数跳过安全检查。`,
-	Args: cobra.ExactArgs(1),
+2. 从目标工具配置文件中物理清理技能内容，清理后校验技能已移除且文件能正确解析
+   （标记块成对、opencode.json 等 JSON 有效），校验失败时从备份恢复目标文件，保留项目状态中的记录
+3. 删除 apply 安装到项目中的技能资源文件
+4. 如果检测到本地修改，会提示警告，并可以先保存或反馈修改
+
+未指定 --target 时，从技能实际应用到的目标工具中移除（见 'skill-hub status' 的应用记录），
+没有应用记录时使用项目绑定的目标。使用 --target 参数指定目标工具 (cursor/claude_code/open_code/all)。
+技能以 'skill-hub apply --mode global' 应用时按应用记录从用户级配置中清理。
+使用 --force 参数跳过安全检查。
+技能有本地修改时询问如何处理：丢弃修改继续移除、将修改保存为补丁文件后移除，
+或先将修改反馈到技能仓库（同 'skill-hub feedback'，--global 时不可用）后移除，也可以取消。
+使用 --save-patch <文件> 不询问，先将修改保存为补丁文件再移除；使用 --yes 时不询问直接移除；
+使用 --no-input 而未指定 --yes 或 --save-patch 时停止并返回错误。
+使用 --global 从全局作用域移除技能，并从用户级配置中清理。
+使用 --project 从指定目录的项目中移除技能，不必先切换到项目目录。`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectSkillIDs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runRemove(args[0])
 	},
 }
 
 func init() {
-	removeCmd.Flags().StringVar(&removeTarget, "target", "", "目标工具: cursor, claude_code, open_code, all (为空时使用状态绑定的目标)")
+	removeCmd.Flags().StringVar(&removeTarget, "target", "", "目标工具: cursor, claude_code, open_code, shell, all (为空时使用状态绑定的目标)")
 	removeCmd.Flags().BoolVar(&forceRemove, "force", false, "跳过安全检查，强制移除")
+	removeCmd.Flags().BoolVar(&removeGlobal, "global", false, "从全局作用域移除技能")
+	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "技能有本地修改时不询问，直接移除")
+	removeCmd.Flags().StringVar(&removeSavePatch, "save-patch", "", "技能有本地修改时先将修改保存为补丁文件，不询问")
+	addProjectFlag(removeCmd)
+
+	removeCmd.RegisterFlagCompletionFunc("target", fixedCompletions(targetCompletions...))
 }
 
 func runRemove(skillID string) error {
 	fmt.Printf("正在从当前项目移除技能: %s\n", skillID)
 
-	// 获取当前目录
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("获取当前目录失败: %w", err)
-	}
-
-	// 创建状态管理器
-	stateMgr, err := state.NewStateManager()
+	// 创建状态管理器，cwd 为作用域对应的项目路径
+	stateMgr, cwd, err := scopeState(removeGlobal)
 	if err != nil {
 		return err
 	}
+	removeMode := spec.ModeProject
+	if removeGlobal {
+		removeMode = spec.ModeGlobal
+	}
 
 	// 检查技能是否在项目中启用（仅用于信息提示）
 	hasSkill, err := stateMgr.ProjectHasSkill(cwd, skillID)
@@ -76,11 +99,38 @@ func runRemove(skillID string) error {
 		return fmt.Errorf("查找项目状态失败: %w", err)
 	}
 
-	// 确定目标工具
-	resolvedTarget := removeTarget
-	if resolvedTarget == "" && projectState != nil {
-		resolvedTarget = spec.NormalizeTarget(projectState.PreferredTarget)
-		fmt.Printf("🔍 使用状态绑定的目标: %s\n", resolvedTarget)
+	// 获取项目技能变量
+	projectSkills, err := stateMgr.GetProjectSkills(cwd)
+	if err != nil {
+		return err
+	}
+	skillVars, skillEnabled := projectSkills[skillID]
+
+	// 确定目标工具：未指定时优先使用技能实际应用到的目标，其次使用项目绑定的所有目标
+	resolvedTarget := ""
+	var filterTargets []string
+	appliedTargets := sortedAppliedTargets(skillVars)
+	if removeTarget == "" && len(appliedTargets) > 0 {
+		resolvedTarget = spec.TargetAll
+		filterTargets = appliedTargets
+		fmt.Printf("🔍 使用技能已应用的目标: %s\n", strings.Join(filterTargets, ", "))
+	} else {
+		targets, err := resolveTargets(removeTarget, projectState)
+		if err != nil {
+			return err
+		}
+		switch {
+		case removeTarget != "":
+			resolvedTarget = targets[0]
+		case len(targets) > 0:
+			resolvedTarget = spec.TargetAll
+			filterTargets = targets
+			fmt.Printf("🔍 使用状态绑定的目标: %s\n", strings.Join(filterTargets, ", "))
+		}
+	}
+	displayTarget := resolvedTarget
+	if len(filterTargets) > 0 {
+		displayTarget = strings.Join(filterTargets, ", ")
 	}
 
 	// 如果没有指定目标且项目未绑定目标，需要用户指定
@@ -94,47 +144,91 @@ func runRemove(skillID string) error {
 		return nil
 	}
 
-	fmt.Printf("当前项目: %s\n", cwd)
-	fmt.Printf("目标工具: %s\n", resolvedTarget)
+	if removeGlobal {
+		fmt.Println("作用域: 全局")
+	} else {
+		fmt.Printf("当前项目: %s\n", cwd)
+	}
+	fmt.Printf("目标工具: %s\n", displayTarget)
+	debugf("状态文件: %s", stateMgr.GetStatePath())
 
 	// 加载技能管理器
 	skillManager, err := engine.NewSkillManager()
 	if err != nil {
 		return err
 	}
+	useProjectLocale(skillManager, projectState)
 
-	// 加载技能详情
+	// 加载技能详情；技能已从仓库删除时按应用记录清理
 	skill, err := skillManager.LoadSkill(skillID)
 	if err != nil {
-		return fmt.Errorf("加载技能失败: %w", err)
+		if skillManager.SkillExists(skillID) {
+			return fmt.Errorf("加载技能失败: %w", err)
+		}
+		if len(appliedTargets) > 0 {
+			fmt.Printf("⚠️  技能 %s 不在技能仓库中，按应用记录清理\n", skillID)
+		} else {
+			fmt.Printf("⚠️  技能 %s 不在技能仓库中，跳过兼容性检查\n", skillID)
+		}
+		skill = nil
 	}
 
 	// 根据目标选择适配器
-	adapters := selectAdapters(resolvedTarget, "project")
+	adapters := selectAdapters(resolvedTarget, removeMode)
 	if len(adapters) == 0 {
-		return fmt.Errorf("无效的目标工具: %s，可用选项: %s, %s, %s, %s", resolvedTarget, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetAll)
+		return fmt.Errorf("无效的目标工具: %s，可用选项: %s, %s, %s, %s, %s", resolvedTarget, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell, spec.TargetAll)
 	}
-
-	// 获取项目技能变量
-	projectSkills, err := stateMgr.GetProjectSkills(cwd)
-	if err != nil {
-		return err
+	if len(filterTargets) > 0 {
+		adapters = filterAdaptersByTarget(adapters, filterTargets)
+	}
+	// 从技能实际应用到的配置文件中清理：项目中以 apply --mode global 应用的技能在用户级配置中
+	adapters = withAppliedModes(adapters, skillVars, removeMode)
+	for _, adpt := range adapters {
+		debugf("选择适配器: %s", getAdapterName(adpt))
 	}
-	skillVars, skillEnabled := projectSkills[skillID]
 
 	// 安全检查：检测本地修改（仅当技能已启用时）
 	if !forceRemove && skillEnabled {
-		hasModifications, err := checkSkillModifications(adapters, skillID, skillManager, skillVars.Variables)
+		modifications, err := checkSkillModifications(adapters, skillID, skillManager, skillVars, removeMode)
 		if err != nil {
 			fmt.Printf("⚠️  安全检查失败: %v\n", err)
 			fmt.Println("使用 --force 参数跳过安全检查")
 			return nil
 		}
 
-		if hasModifications {
-			if !confirmRemoval(skillID) {
+		if len(modifications) > 0 {
+			action := removeDiscard
+			switch {
+			case removeSavePatch != "":
+				action = removePatch
+			case removeYes:
+			case noInput:
+				return fmt.Errorf("技能 %s 有本地修改，不读取输入时请使用 --save-patch 保存修改或 --yes 确认移除", skillID)
+			default:
+				action = promptModifiedRemoval(skillID, !removeGlobal)
+			}
+
+			switch action {
+			case removeCancel:
 				fmt.Println("❌ 操作已取消")
 				return nil
+			case removePatch:
+				patchPath := removeSavePatch
+				if patchPath == "" {
+					patchPath = promptPatchPath(skillID + ".local.patch")
+				}
+				if err := saveModificationsPatch(patchPath, skillID, modifications); err != nil {
+					return err
+				}
+			case removeFeedback:
+				if err := runFeedback(skillID); err != nil {
+					return fmt.Errorf("反馈本地修改失败，技能未移除: %w", err)
+				}
+				if result, ok := resultData.(*feedbackResult); ok && result.Cancelled {
+					fmt.Println("❌ 反馈已取消，技能未移除")
+					return nil
+				}
+				fmt.Println("✓ 本地修改已反馈到技能仓库")
 			}
 		}
 	}
@@ -142,12 +236,14 @@ func runRemove(skillID string) error {
 	// 执行物理清理
 	fmt.Println("\n=== 执行物理清理 ===")
 	removedFromAdapters := []string{}
+	verifications := []removeVerification{}
+	var unverified []string
 
 	for _, adapter := range adapters {
 		adapterName := getAdapterName(adapter)
 
 		// 检查适配器是否支持该技能
-		if !adapterSupportsSkill(adapter, skill) {
+		if skill != nil && !adapterSupportsSkill(adapter, skill) {
 			fmt.Printf("ℹ️  技能 %s 不支持 %s，跳过清理\n", skillID, adapterName)
 			continue
 		}
@@ -159,13 +255,34 @@ func runRemove(skillID string) error {
 		}
 
 		fmt.Printf("清理 %s 适配器...\n", adapterName)
-		if err := adapter.Remove(skillID); err != nil {
+		check, err := removeAndVerify(adapter, skillID)
+		if err != nil {
 			fmt.Printf("❌ 从 %s 清理技能失败: %v\n", adapterName, err)
 			continue
 		}
+		verifications = append(verifications, check)
+		if check.Error != "" {
+			fmt.Printf("❌ 校验 %s 失败: %s\n", adapterName, check.Error)
+			if check.Restored {
+				fmt.Printf("↩️  已从备份恢复 %s 的目标文件\n", adapterName)
+			}
+			unverified = append(unverified, adapterName)
+			continue
+		}
 
-		fmt.Printf("✓ 成功从 %s 清理技能\n", adapterName)
+		fmt.Printf("✓ 成功从 %s 清理技能，校验通过\n", adapterName)
 		removedFromAdapters = append(removedFromAdapters, adapterName)
+		version := skillVars.Applied[getAdapterTarget(adapter)].Version
+		if version == "" && skill != nil {
+			version = skill.Version
+		}
+		recordAudit(state.AuditEntry{
+			Operation: state.OpRemove,
+			Project:   cwd,
+			SkillID:   skillID,
+			Version:   version,
+			Adapter:   getAdapterTarget(adapter),
+		})
 	}
 
 	if len(removedFromAdapters) == 0 {
@@ -178,6 +295,21 @@ func runRemove(skillID string) error {
 		fmt.Printf("\n✅ 技能已从以下适配器清理: %s\n", strings.Join(removedFromAdapters, ", "))
 	}
 
+	// 校验失败的目标文件已恢复，技能仍在其中，保留资源文件和项目状态中的记录以便重新移除
+	if len(unverified) > 0 {
+		setResult(removeResult{SkillID: skillID, RemovedFrom: removedFromAdapters, Verification: verifications})
+		return fmt.Errorf("技能 %s 未能从 %s 完整移除，已保留项目状态中的记录", skillID, strings.Join(unverified, ", "))
+	}
+
+	// 删除安装到项目中的资源文件
+	if len(skillVars.Resources) > 0 {
+		if err := resource.Remove(cwd, skillVars.Resources); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		} else {
+			fmt.Printf("✓ 已删除 %d 个资源文件\n", len(skillVars.Resources))
+		}
+	}
+
 	// 更新状态：从项目中移除技能（仅当技能已启用时）
 	if skillEnabled {
 		fmt.Println("\n=== 更新状态 ===")
@@ -187,12 +319,118 @@ func runRemove(skillID string) error {
 		fmt.Printf("✓ 成功从项目状态移除技能 %s\n", skillID)
 	}
 
+	setResult(removeResult{SkillID: skillID, RemovedFrom: removedFromAdapters, Verification: verifications})
+
 	fmt.Println("\n🎉 技能移除完成")
 	fmt.Println("使用 'skill-hub status' 检查当前状态")
 
 	return nil
 }
 
+// removeResult remove 命令的结构化结果
+type removeResult struct {
+	SkillID      string               `json:"skill_id"`
+	RemovedFrom  []string             `json:"removed_from"`
+	Verification []removeVerification `json:"verification"`
+}
+
+// removeVerification 移除技能后对目标文件的校验结果
+type removeVerification struct {
+	Target   string `json:"target"`
+	Removed  bool   `json:"removed"`            // 技能已不在目标文件中
+	Valid    bool   `json:"valid"`              // 目标文件能正确解析
+	Restored bool   `json:"restored,omitempty"` // 校验失败，已从备份恢复目标文件
+	Error    string `json:"error,omitempty"`
+}
+
+// removeAndVerify 从适配器移除技能，再提取技能内容并校验目标文件，确认技能块已移除且文件没有损坏
+//
+// 移除前备份适配器的目标文件和技能的安装目录，校验失败时从备份恢复并在结果中说明原因；
+// 返回的错误只表示移除本身失败。提取技能内容时只有 adapter.ErrNotFound 表示技能已移除。
+func removeAndVerify(adpt adapter.Adapter, skillID string) (removeVerification, error) {
+	check := removeVerification{Target: getAdapterTarget(adpt)}
+	tx := adapter.NewTransaction()
+	dirRemover, hasDir := adpt.(adapter.DirRemover)
+	if hasDir {
+		dir, err := dirRemover.SkillDir(skillID)
+		if err != nil {
+			return check, err
+		}
+		if err := tx.TrackDir(dir); err != nil {
+			tx.Rollback()
+			return check, err
+		}
+	}
+	verifier, canVerify := adpt.(adapter.Verifier)
+	if canVerify {
+		files, err := verifier.TargetFiles()
+		if err != nil {
+			return check, err
+		}
+		for _, path := range files {
+			if err := tx.Track(path); err != nil {
+				tx.Rollback()
+				return check, fmt.Errorf("备份 %s 失败: %w", path, err)
+			}
+		}
+	}
+
+	if err := adpt.Remove(skillID); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return check, fmt.Errorf("%w（恢复目标文件失败: %v）", err, rollbackErr)
+		}
+		return check, err
+	}
+
+	content, err := adpt.Extract(skillID)
+	check.Removed = errors.Is(err, adapter.ErrNotFound) || err == nil && strings.TrimSpace(content) == ""
+	check.Valid = true
+	var failure error
+	if canVerify {
+		if err := verifier.Verify(); err != nil {
+			check.Valid = false
+			failure = err
+		}
+	}
+	if !check.Removed && failure == nil {
+		failure = fmt.Errorf("技能 %s 仍在目标文件中", skillID)
+		if err != nil {
+			failure = fmt.Errorf("无法确认技能 %s 已移除: %w", skillID, err)
+		}
+	}
+	if failure == nil {
+		if err := tx.Commit(); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+		return check, nil
+	}
+
+	check.Error = failure.Error()
+	if err := tx.Rollback(); err != nil {
+		check.Error += fmt.Sprintf("；从备份恢复失败: %v", err)
+		return check, nil
+	}
+	check.Restored = canVerify || hasDir
+	return check, nil
+}
+
+// resolveTargets 返回命令使用的目标工具：指定了 target 时规范化后使用，否则使用项目状态绑定的目标，
+// 都没有时返回nil。projectState 可以为nil，指定的目标无效时返回错误
+func resolveTargets(target string, projectState *spec.ProjectState) ([]string, error) {
+	if target == "" {
+		if projectState == nil {
+			return nil, nil
+		}
+		return projectState.BoundTargets(), nil
+	}
+	target = spec.NormalizeTarget(target)
+	switch target {
+	case spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell, spec.TargetAll:
+		return []string{target}, nil
+	}
+	return nil, fmt.Errorf("无效的目标工具: %s，可用选项: %s, %s, %s, %s, %s", target, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell, spec.TargetAll)
+}
+
 // selectAdapters 根据目标选择适配器
 func selectAdapters(target string, mode string) []adapter.Adapter {
 	var adapters []adapter.Adapter
@@ -227,31 +465,85 @@ func selectAdapters(target string, mode string) []adapter.Adapter {
 		adapters = append(adapters, opencodeAdapter)
 	}
 
+	if target == spec.TargetAll || target == spec.TargetShell {
+		shellAdapter := shell.NewShellAdapter()
+		if mode == "global" {
+			shellAdapter = shellAdapter.WithGlobalMode()
+		} else {
+			shellAdapter = shellAdapter.WithProjectMode()
+		}
+		adapters = append(adapters, shellAdapter)
+	}
+
 	return adapters
 }
 
-// checkSkillModifications 检查技能是否有本地修改
-func checkSkillModifications(adapters []adapter.Adapter, skillID string, skillManager *engine.SkillManager, variables map[string]string) (bool, error) {
-	fmt.Println("\n=== 安全检查 ===")
+// sortedAppliedTargets 返回技能有应用记录的目标，按名称排序
+func sortedAppliedTargets(skillVars spec.SkillVars) []string {
+	targets := make([]string, 0, len(skillVars.Applied))
+	for target := range skillVars.Applied {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
 
-	// 获取原始技能内容
-	originalPrompt, err := skillManager.GetSkillPrompt(skillID)
-	if err != nil {
-		return false, fmt.Errorf("获取技能原始内容失败: %w", err)
+// filterAdaptersByTarget 只保留目标在列表中的适配器
+func filterAdaptersByTarget(adapters []adapter.Adapter, targets []string) []adapter.Adapter {
+	var filtered []adapter.Adapter
+	for _, adpt := range adapters {
+		for _, target := range targets {
+			if getAdapterTarget(adpt) == target {
+				filtered = append(filtered, adpt)
+				break
+			}
+		}
 	}
+	return filtered
+}
 
-	// 渲染原始内容（使用项目变量）
-	renderedOriginal, err := renderTemplateForRemove(originalPrompt, variables)
-	if err != nil {
-		return false, fmt.Errorf("渲染技能内容失败: %w", err)
+// appliedMode 返回技能应用到目标时的配置模式，没有应用记录时返回 mode
+func appliedMode(skillVars spec.SkillVars, target, mode string) string {
+	if record, ok := skillVars.Applied[target]; ok {
+		return record.AppliedMode()
 	}
+	return mode
+}
+
+// withAppliedModes 按应用记录中的配置模式替换适配器，技能以全局模式应用时从用户级配置中清理
+func withAppliedModes(adapters []adapter.Adapter, skillVars spec.SkillVars, mode string) []adapter.Adapter {
+	result := make([]adapter.Adapter, 0, len(adapters))
+	for _, adpt := range adapters {
+		target := getAdapterTarget(adpt)
+		if recorded := appliedMode(skillVars, target, mode); recorded != mode {
+			if selected := selectAdapters(target, recorded); len(selected) > 0 {
+				debugf("%s 按应用记录使用 %s 模式", target, recorded)
+				adpt = selected[0]
+			}
+		}
+		result = append(result, adpt)
+	}
+	return result
+}
 
-	originalHash := sha256.Sum256([]byte(strings.TrimSpace(renderedOriginal)))
+// skillModification 目标工具中有本地修改的技能内容
+type skillModification struct {
+	Target   string // 目标工具
+	Original string // 应用时的内容，找不到时为空
+	Current  string // 目标工具中的当前内容
+}
 
-	hasModifications := false
+// checkSkillModifications 检查技能是否有本地修改，返回有修改的目标及其内容
+//
+// 有应用记录的目标与记录的内容哈希比较，无需重新渲染；没有应用记录时与仓库中的渲染结果比较。
+func checkSkillModifications(adapters []adapter.Adapter, skillID string, skillManager *engine.SkillManager, skillVars spec.SkillVars, mode string) ([]skillModification, error) {
+	fmt.Println("\n=== 安全检查 ===")
+
+	var modifications []skillModification
 
 	for _, adapter := range adapters {
 		adapterName := getAdapterName(adapter)
+		target := getAdapterTarget(adapter)
 
 		// 检查适配器是否支持
 		if !adapter.Supports() {
@@ -270,40 +562,106 @@ func checkSkillModifications(adapters []adapter.Adapter, skillID string, skillMa
 			continue
 		}
 
-		// 计算当前内容的哈希
-		currentHash := sha256.Sum256([]byte(strings.TrimSpace(currentContent)))
+		originalHash := ""
+		original, _ := state.AppliedBase(skillVars, target)
+		if record, ok := skillVars.Applied[target]; ok {
+			originalHash = record.Hash
+		} else if !skillManager.SkillExists(skillID) {
+			fmt.Printf("ℹ️  技能 %s 不在技能仓库中且没有应用记录，无法校验 %s 适配器中的内容\n", skillID, adapterName)
+			continue
+		} else {
+			// 渲染仓库中的原始内容（使用项目变量）
+			renderedOriginal, err := skillManager.RenderSkill(skillID, skillVars.Variables, target, mode)
+			if err != nil {
+				return nil, fmt.Errorf("获取技能原始内容失败: %w", err)
+			}
+			originalHash = state.ContentHash(renderedOriginal)
+			original = renderedOriginal
+		}
 
 		// 比较哈希
-		if currentHash != originalHash {
+		if state.ContentHash(currentContent) != originalHash {
 			fmt.Printf("⚠️  检测到 %s 适配器中的技能 %s 有本地修改\n", adapterName, skillID)
-			hasModifications = true
+			modifications = append(modifications, skillModification{Target: target, Original: original, Current: currentContent})
 		} else {
 			fmt.Printf("✓ %s 适配器中的技能 %s 与原始内容一致\n", adapterName, skillID)
 		}
 	}
 
-	return hasModifications, nil
+	return modifications, nil
+}
+
+// modificationsPatch 返回技能本地修改的统一差异格式补丁，找不到应用时的内容时补丁包含完整的当前内容
+func modificationsPatch(skillID string, modifications []skillModification) string {
+	var patch strings.Builder
+	for _, mod := range modifications {
+		name := path.Join(mod.Target, skillID)
+		fromName := name
+		original := strings.TrimSpace(mod.Original)
+		if original == "" {
+			fromName = "/dev/null"
+		} else {
+			original += "\n"
+		}
+		patch.WriteString(diff.Unified(fromName, name, original, strings.TrimSpace(mod.Current)+"\n", diff.DefaultContext))
+	}
+	return patch.String()
 }
 
-// confirmRemoval 确认是否继续移除（当有本地修改时）
-func confirmRemoval(skillID string) bool {
-	fmt.Printf("\n⚠️  警告: 技能 %s 有本地修改，移除将丢失这些改动\n", skillID)
-	fmt.Print("是否继续移除？(y/n): ")
+// saveModificationsPatch 将技能的本地修改保存为补丁文件
+func saveModificationsPatch(patchPath, skillID string, modifications []skillModification) error {
+	if err := os.WriteFile(patchPath, []byte(modificationsPatch(skillID, modifications)), 0644); err != nil {
+		return fmt.Errorf("保存补丁文件失败: %w", err)
+	}
+	fmt.Printf("✓ 本地修改已保存到补丁文件: %s\n", patchPath)
+	return nil
+}
 
-	reader := bufio.NewReader(os.Stdin)
-	input, _ := reader.ReadString('\n')
-	input = strings.TrimSpace(strings.ToLower(input))
+// 技能有本地修改时移除前的处理方式
+const (
+	removeDiscard  = "discard"  // 丢弃修改，直接移除
+	removeFeedback = "feedback" // 先反馈到技能仓库
+	removePatch    = "patch"    // 先保存为补丁文件
+	removeCancel   = "cancel"   // 取消移除
+)
 
-	return input == "y" || input == "yes"
+// promptModifiedRemoval 询问有本地修改的技能如何处理，allowFeedback 为false时不提供反馈选项，默认取消
+func promptModifiedRemoval(skillID string, allowFeedback bool) string {
+	fmt.Printf("\n⚠️  警告: 技能 %s 有本地修改，直接移除将丢失这些改动\n", skillID)
+	options := []struct{ action, note string }{
+		{removeDiscard, "继续移除，丢弃本地修改"},
+		{removePatch, "将本地修改保存为补丁文件后移除"},
+	}
+	if allowFeedback {
+		options = append(options, struct{ action, note string }{removeFeedback, "先将本地修改反馈到技能仓库 (skill-hub feedback) 后移除"})
+	}
+	for i, option := range options {
+		fmt.Printf("%d. %s\n", i+1, option.note)
+	}
+	fmt.Printf("请选择 (1-%d, 其他输入取消): ", len(options))
+
+	reader := newInputReader()
+	choice, _ := reader.ReadString('\n')
+	choice = strings.ToLower(strings.TrimSpace(choice))
+	for i, option := range options {
+		if choice == strconv.Itoa(i+1) {
+			return option.action
+		}
+	}
+	// 兼容原来的 y/n 确认
+	if choice == "y" || choice == "yes" {
+		return removeDiscard
+	}
+	return removeCancel
 }
 
-// renderTemplateForRemove 渲染模板内容（用于remove命令）
-func renderTemplateForRemove(content string, variables map[string]string) (string, error) {
-	// 简单替换变量
-	result := content
-	for key, value := range variables {
-		placeholder := "{{." + key + "}}"
-		result = strings.ReplaceAll(result, placeholder, value)
+// promptPatchPath 询问补丁文件的路径，直接回车使用 defaultPath
+func promptPatchPath(defaultPath string) string {
+	fmt.Printf("补丁文件路径 (默认 %s): ", defaultPath)
+	reader := newInputReader()
+	input, _ := reader.ReadString('\n')
+	if input = strings.TrimSpace(input); input != "" {
+		return input
 	}
-	return result, nil
+	return defaultPath
 }
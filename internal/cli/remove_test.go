@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skill-hub/internal/adapter"
+	"skill-hub/internal/adapter/cursor"
+	"skill-hub/pkg/spec"
+)
+
+func TestResolveTargets(t *testing.T) {
+	bound := &spec.ProjectState{PreferredTarget: spec.TargetClaude, PreferredTargets: []string{spec.TargetClaude, "opencode"}}
+	tests := []struct {
+		name   string
+		target string
+		state  *spec.ProjectState
+		want   string
+	}{
+		{"explicit alias", "claude", bound, spec.TargetClaudeCode},
+		{"explicit open_code", "opencode", nil, spec.TargetOpenCode},
+		{"explicit all", "all", bound, spec.TargetAll},
+		{"bound targets", "", bound, spec.TargetClaudeCode + "," + spec.TargetOpenCode},
+		{"single preferred target", "", &spec.ProjectState{PreferredTarget: spec.TargetCursor}, spec.TargetCursor},
+		{"unbound", "", &spec.ProjectState{}, ""},
+		{"no state", "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveTargets(tt.target, tt.state)
+			if err != nil {
+				t.Fatalf("resolveTargets() error = %v", err)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("resolveTargets() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := resolveTargets("vscode", nil); err == nil || !strings.Contains(err.Error(), "无效的目标工具: vscode") {
+		t.Errorf("resolveTargets(vscode) error = %v", err)
+	}
+}
+
+func TestModificationsPatch(t *testing.T) {
+	patch := modificationsPatch("git-expert", []skillModification{
+		{Target: spec.TargetCursor, Original: "rule one\nrule two\n", Current: "rule one\nrule 2\n"},
+		{Target: spec.TargetClaudeCode, Current: "local only\n"},
+	})
+	for _, want := range []string{
+		"--- cursor/git-expert\n+++ cursor/git-expert\n",
+		"-rule two\n+rule 2\n",
+		"--- /dev/null\n+++ claude_code/git-expert\n",
+		"+local only\n",
+	} {
+		if !strings.Contains(patch, want) {
+			t.Errorf("modificationsPatch() missing %q in:\n%s", want, patch)
+		}
+	}
+}
+
+// corruptingAdapter 移除技能时写坏目标文件的适配器，用于测试移除后的校验
+type corruptingAdapter struct {
+	path string
+}
+
+func (a *corruptingAdapter) Apply(string, string, map[string]string) error { return nil }
+func (a *corruptingAdapter) Plan(string, string, map[string]string) (*adapter.Plan, error) {
+	return nil, nil
+}
+func (a *corruptingAdapter) Extract(string) (string, error) {
+	return "", adapter.NotFoundf("not found")
+}
+func (a *corruptingAdapter) Remove(string) error {
+	return os.WriteFile(a.path, []byte("{broken"), 0644)
+}
+func (a *corruptingAdapter) List() ([]string, error)        { return nil, nil }
+func (a *corruptingAdapter) Supports() bool                 { return true }
+func (a *corruptingAdapter) TargetFiles() ([]string, error) { return []string{a.path}, nil }
+func (a *corruptingAdapter) Verify() error {
+	data, _ := os.ReadFile(a.path)
+	if strings.HasPrefix(string(data), "{broken") {
+		return fmt.Errorf("invalid JSON")
+	}
+	return nil
+}
+
+// dirCorruptingAdapter 移除技能时删除整个技能目录并写坏目标文件的适配器
+type dirCorruptingAdapter struct {
+	corruptingAdapter
+	dir string
+}
+
+func (a *dirCorruptingAdapter) SkillDir(string) (string, error) { return a.dir, nil }
+func (a *dirCorruptingAdapter) Remove(skillID string) error {
+	if err := os.RemoveAll(a.dir); err != nil {
+		return err
+	}
+	return a.corruptingAdapter.Remove(skillID)
+}
+
+// unreadableAdapter 移除后提取技能内容失败（不是技能不存在）的适配器
+type unreadableAdapter struct {
+	corruptingAdapter
+}
+
+func (a *unreadableAdapter) Remove(string) error { return nil }
+func (a *unreadableAdapter) Extract(string) (string, error) {
+	return "", fmt.Errorf("permission denied")
+}
+
+func TestRemoveAndVerify(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("removed and valid", func(t *testing.T) {
+		path := filepath.Join(dir, ".cursorrules")
+		content := "user rules\n# === SKILL-HUB BEGIN: a ===\nrule a\n# === SKILL-HUB END: a ===\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		check, err := removeAndVerify(cursor.NewCursorAdapter().WithProjectPath(dir), "a")
+		if err != nil || check.Error != "" || !check.Removed || !check.Valid {
+			t.Fatalf("removeAndVerify() = %+v, %v", check, err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "user rules\n" {
+			t.Errorf("file content = %q", data)
+		}
+		if _, err := os.Stat(path + adapter.BackupSuffix); !os.IsNotExist(err) {
+			t.Error("backup should be removed after a successful verification")
+		}
+	})
+
+	t.Run("corrupted file is restored", func(t *testing.T) {
+		path := filepath.Join(dir, "config.json")
+		if err := os.WriteFile(path, []byte(`{"ok": true}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		check, err := removeAndVerify(&corruptingAdapter{path: path}, "a")
+		if err != nil {
+			t.Fatalf("removeAndVerify() error = %v", err)
+		}
+		if check.Valid || !check.Restored || !strings.Contains(check.Error, "invalid JSON") {
+			t.Errorf("removeAndVerify() = %+v, want restored invalid file", check)
+		}
+		if data, _ := os.ReadFile(path); string(data) != `{"ok": true}` {
+			t.Errorf("file not restored: %q", data)
+		}
+	})
+
+	t.Run("skill directory is restored", func(t *testing.T) {
+		path := filepath.Join(dir, "opencode.json")
+		skillDir := filepath.Join(dir, "skills", "a")
+		if err := os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		files := map[string]string{
+			path:                                `{"ok": true}`,
+			filepath.Join(skillDir, "SKILL.md"): "---\nname: a\n---\nbody",
+			filepath.Join(skillDir, "scripts", "run.sh"): "echo a",
+		}
+		for file, content := range files {
+			if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		check, err := removeAndVerify(&dirCorruptingAdapter{corruptingAdapter{path: path}, skillDir}, "a")
+		if err != nil {
+			t.Fatalf("removeAndVerify() error = %v", err)
+		}
+		if !check.Restored {
+			t.Errorf("removeAndVerify() = %+v, want restored", check)
+		}
+		for file, content := range files {
+			if data, err := os.ReadFile(file); err != nil || string(data) != content {
+				t.Errorf("%s not restored: %q, %v", file, data, err)
+			}
+		}
+	})
+
+	t.Run("extract error is not treated as removed", func(t *testing.T) {
+		path := filepath.Join(dir, "unreadable.json")
+		if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		check, err := removeAndVerify(&unreadableAdapter{corruptingAdapter{path: path}}, "a")
+		if err != nil {
+			t.Fatalf("removeAndVerify() error = %v", err)
+		}
+		if check.Removed || !strings.Contains(check.Error, "permission denied") {
+			t.Errorf("removeAndVerify() = %+v, want not removed", check)
+		}
+	})
+}
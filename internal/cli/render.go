@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	renderVars    []string
+	renderTarget  string
+	renderMode    string
+	renderProfile string
+	renderLocale  string
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render <skill-id>",
+	Short: "输出技能渲染后的内容，不应用到项目",
+	Long: `按目标工具渲染技能并将结果输出到标准输出，不修改任何文件，
+可以用管道交给其他工具处理、预览 apply 将写入的内容或排查模板问题。
+
+变量值依次取技能声明的默认值、当前项目中的变量（技能已启用时，--profile 选择变量配置）和 --var。
+未指定 --target 时使用项目绑定的第一个目标。
+
+示例:
+  skill-hub render git-expert
+  skill-hub render git-expert --var LANGUAGE=en --target claude_code
+  skill-hub render git-expert --json | jq -r .data.content`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSkillIDs,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRender(args[0])
+	},
+}
+
+func init() {
+	renderCmd.Flags().StringArrayVar(&renderVars, "var", nil, "设置变量，格式为 KEY=VALUE（可多次指定）")
+	renderCmd.Flags().StringVar(&renderTarget, "target", "", "目标工具: cursor, claude_code, open_code, shell (为空时使用项目绑定的目标)")
+	renderCmd.Flags().StringVar(&renderMode, "mode", "project", "配置模式: project (项目级), global (全局)")
+	renderCmd.Flags().StringVar(&renderProfile, "profile", "", "使用的变量配置 (为空时使用项目的默认配置)")
+	renderCmd.Flags().StringVar(&renderLocale, "locale", "", "技能提示词的语言，例如 zh-CN (为空时使用项目或全局配置的语言)")
+	renderCmd.RegisterFlagCompletionFunc("target", fixedCompletions(spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell))
+	renderCmd.RegisterFlagCompletionFunc("mode", fixedCompletions(modeCompletions...))
+	renderCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+	addProjectFlag(renderCmd)
+	rootCmd.AddCommand(renderCmd)
+}
+
+// renderResult render 命令的结构化结果
+type renderResult struct {
+	SkillID string `json:"skill_id"`
+	Version string `json:"version"`
+	Target  string `json:"target"`
+	Mode    string `json:"mode"`
+	Locale  string `json:"locale,omitempty"`
+	Content string `json:"content"`
+}
+
+func runRender(skillID string) error {
+	if err := spec.ValidateSkillID(skillID); err != nil {
+		return err
+	}
+	if renderMode != "project" && renderMode != "global" {
+		return fmt.Errorf("无效的配置模式: %s，可用选项: project, global", renderMode)
+	}
+
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	if !skillManager.SkillExists(skillID) {
+		return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", skillID)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	projectState, err := stateMgr.LoadProjectState(cwd)
+	if err != nil {
+		return err
+	}
+
+	// 项目中的变量和变量配置，技能未在项目中启用时为空
+	_, profileVars, err := state.ResolveProfile(projectState, renderProfile)
+	if err != nil {
+		return err
+	}
+	variables := make(map[string]string)
+	for name, value := range profileVars[skillID] {
+		variables[name] = value
+	}
+	overrides, err := parseVariableAssignments(skillID, renderVars)
+	if err != nil {
+		return err
+	}
+	for name, value := range overrides {
+		variables[name] = value
+	}
+
+	target := renderTarget
+	if target == "" {
+		if bound := projectState.BoundTargets(); len(bound) > 0 {
+			target = bound[0]
+		} else {
+			target = spec.TargetCursor
+		}
+	}
+	target = spec.NormalizeTarget(target)
+	switch target {
+	case spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell:
+	default:
+		return fmt.Errorf("无效的目标工具: %s，可用选项: %s, %s, %s, %s", target, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell)
+	}
+
+	useProjectLocale(skillManager, projectState)
+	if renderLocale != "" {
+		locale, err := spec.NormalizeLocale(renderLocale)
+		if err != nil {
+			return err
+		}
+		skillManager.SetLocale(locale)
+	}
+
+	skill, err := skillManager.LoadSkill(skillID)
+	if err != nil {
+		return fmt.Errorf("加载技能失败: %w", err)
+	}
+	content, err := skillManager.RenderSkill(skillID, variables, target, renderMode)
+	if err != nil {
+		return err
+	}
+
+	setResult(renderResult{
+		SkillID: skillID,
+		Version: skill.Version,
+		Target:  target,
+		Mode:    renderMode,
+		Locale:  skillManager.ResolveLocale(skillID),
+		Content: content,
+	})
+	// 渲染结果是命令的输出，--quiet 时同样输出，--json 时只在结果中输出
+	if !outputJSON {
+		fmt.Fprint(stdout, content)
+		if !strings.HasSuffix(content, "\n") {
+			fmt.Fprintln(stdout)
+		}
+	}
+	return nil
+}
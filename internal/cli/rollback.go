@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"skill-hub/internal/adapter"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rollbackTo     string
+	rollbackTarget string
+	rollbackMode   string
+	rollbackDryRun bool
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <skill-id>",
+	Short: "将技能回滚到之前应用的版本",
+	Long: `将技能在目标工具中的内容恢复为之前应用过的版本。
+
+每次 apply 成功后都会按目标记录渲染后的技能内容（每个目标最多保留 ` + fmt.Sprint(state.MaxAppliedRevisions) + ` 条）。
+不指定 --to 时回滚到上一次应用的内容；指定 --to 时回滚到该版本最近一次应用的内容。
+回滚后项目状态中的版本和变量同步更新，较新的应用记录被丢弃。
+
+默认回滚所有有应用记录的目标，使用 --target 只回滚指定目标。
+每个目标按应用记录中的配置模式（project 或 global）回滚，--mode 与记录不一致时拒绝回滚。`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectSkillIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRollback(args[0])
+	},
+}
+
+func init() {
+	rollbackCmd.Flags().StringVar(&rollbackTo, "to", "", "回滚到的技能版本 (默认: 上一次应用的内容)")
+	rollbackCmd.Flags().StringVar(&rollbackTarget, "target", "", "目标工具: cursor, claude_code, open_code, shell, all (默认: 所有有记录的目标)")
+	rollbackCmd.Flags().StringVar(&rollbackMode, "mode", "", "配置模式: project (项目级), global (全局) (默认: 应用记录中的模式)")
+	rollbackCmd.Flags().BoolVar(&rollbackDryRun, "dry-run", false, "预览回滚而不实际修改文件")
+	addWordDiffFlag(rollbackCmd)
+
+	rollbackCmd.RegisterFlagCompletionFunc("target", fixedCompletions(targetCompletions...))
+	rollbackCmd.RegisterFlagCompletionFunc("mode", fixedCompletions(modeCompletions...))
+}
+
+func runRollback(skillID string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+
+	skills, err := stateMgr.GetProjectSkills(cwd)
+	if err != nil {
+		return err
+	}
+	skillVars, exists := skills[skillID]
+	if !exists {
+		return fmt.Errorf("技能 '%s' 未在当前项目中启用", skillID)
+	}
+
+	targets := rollbackTargets(skillVars, rollbackTarget)
+	if len(targets) == 0 {
+		fmt.Printf("ℹ️  技能 %s 没有应用记录，无法回滚\n", skillID)
+		fmt.Println("使用 'skill-hub apply' 应用技能后才会记录历史")
+		return nil
+	}
+
+	type rollbackStep struct {
+		target string
+		index  int
+		mode   string
+	}
+	var steps []rollbackStep
+	tx := adapter.NewTransaction()
+
+	for _, t := range targets {
+		history := skillVars.History[t]
+		index, err := state.FindRollbackRevision(history, rollbackTo)
+		if err != nil {
+			fmt.Printf("⚠️  跳过 %s: %v\n", t, err)
+			continue
+		}
+
+		mode, err := rollbackModeFor(skillVars, t, rollbackMode)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		adapters := selectAdapters(t, mode)
+		if len(adapters) == 0 {
+			fmt.Printf("⚠️  跳过未知目标: %s\n", t)
+			continue
+		}
+		adpt := adapters[0]
+		adapterName := getAdapterName(adpt)
+		rev := history[index]
+
+		// 记录中的内容已经渲染过，只需还原变量引用的值
+		content, err := state.RevisionContent(rev)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("还原 %s 的应用记录失败: %w", adapterName, err)
+		}
+		plan, err := adpt.Plan(skillID, content, nil)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("预览回滚 %s 失败: %w", adapterName, err)
+		}
+
+		if rollbackDryRun {
+			fmt.Printf("🔍 DRY RUN - 技能 %s -> %s 回滚到版本 %s (应用于 %s)\n", skillID, adapterName, rev.Version, rev.AppliedAt)
+			if plan.HasChanges() {
+				printDiff(plan.Diff(), "")
+			} else {
+				fmt.Println("  无变化")
+			}
+			continue
+		}
+
+		for _, path := range plan.Files() {
+			if err := tx.Track(path); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("备份 %s 失败: %w", path, err)
+			}
+		}
+
+		if err := adpt.Apply(skillID, content, nil); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				fmt.Printf("⚠️  恢复原文件失败: %v\n", rollbackErr)
+			}
+			return fmt.Errorf("回滚技能 %s 到 %s 失败: %w", skillID, adapterName, err)
+		}
+
+		fmt.Printf("✓ %s: 技能 %s 已回滚到版本 %s (应用于 %s)\n", adapterName, skillID, rev.Version, rev.AppliedAt)
+		steps = append(steps, rollbackStep{target: t, index: index, mode: mode})
+	}
+
+	if rollbackDryRun || len(steps) == 0 {
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Printf("⚠️  清理事务备份失败: %v\n", err)
+	}
+
+	for _, step := range steps {
+		if err := stateMgr.RollbackSkill(cwd, skillID, step.target, step.index, step.mode); err != nil {
+			return fmt.Errorf("更新状态失败: %w", err)
+		}
+		recordAudit(state.AuditEntry{
+			Operation: state.OpRollback,
+			Project:   cwd,
+			SkillID:   skillID,
+			Version:   skillVars.History[step.target][step.index].Version,
+			Adapter:   step.target,
+		})
+	}
+
+	fmt.Printf("\n✅ 技能 %s 回滚完成\n", skillID)
+	fmt.Println("注意: 'skill-hub apply' 会重新应用仓库中的当前版本")
+	return nil
+}
+
+// rollbackTargets 确定需要回滚的目标，未指定时返回所有有应用记录的目标
+func rollbackTargets(skillVars spec.SkillVars, target string) []string {
+	target = spec.NormalizeTarget(target)
+	if target != "" && target != spec.TargetAll {
+		if len(skillVars.History[target]) == 0 {
+			return nil
+		}
+		return []string{target}
+	}
+
+	var targets []string
+	for t, history := range skillVars.History {
+		if len(history) > 0 {
+			targets = append(targets, t)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// rollbackModeFor 返回目标回滚时使用的配置模式：技能当前应用在哪里就回滚哪里
+//
+// 有应用记录时使用记录中的模式，否则使用最近一次应用历史的模式。mode 为 --mode 参数，
+// 指定且与记录不一致时返回错误，避免回滚写入另一处配置而状态仍记录原来的位置。
+func rollbackModeFor(skillVars spec.SkillVars, target, mode string) (string, error) {
+	recorded := spec.ModeProject
+	if record, ok := skillVars.Applied[target]; ok {
+		recorded = record.AppliedMode()
+	} else if history := skillVars.History[target]; len(history) > 0 && history[len(history)-1].Mode != "" {
+		recorded = history[len(history)-1].Mode
+	}
+	if mode != "" && mode != recorded {
+		return "", fmt.Errorf("技能 %s 在 %s 中以 %s 模式应用，与 --mode %s 不一致", skillVars.SkillID, target, recorded, mode)
+	}
+	return recorded, nil
+}
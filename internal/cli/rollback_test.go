@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"skill-hub/internal/adapter/cursor"
+	"skill-hub/pkg/spec"
+)
+
+func TestRollbackTargets(t *testing.T) {
+	skillVars := spec.SkillVars{
+		SkillID: "demo",
+		History: map[string][]spec.AppliedRevision{
+			spec.TargetOpenCode: {{Version: "1.0.0"}},
+			spec.TargetCursor:   {{Version: "1.0.0"}, {Version: "1.1.0"}},
+			spec.TargetShell:    {},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{name: "All recorded targets", target: "", want: []string{spec.TargetCursor, spec.TargetOpenCode}},
+		{name: "Explicit all", target: spec.TargetAll, want: []string{spec.TargetCursor, spec.TargetOpenCode}},
+		{name: "Single target", target: spec.TargetCursor, want: []string{spec.TargetCursor}},
+		{name: "Legacy target name", target: "opencode", want: []string{spec.TargetOpenCode}},
+		{name: "Target without history", target: spec.TargetShell, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rollbackTargets(skillVars, tt.target); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rollbackTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollbackModeFor(t *testing.T) {
+	// 以全局模式应用后回滚，应写入用户级配置而不是项目中的 .cursorrules
+	skillVars := spec.SkillVars{
+		SkillID: "demo",
+		Applied: map[string]spec.AppliedRecord{spec.TargetCursor: {Version: "1.1.0", Mode: spec.ModeGlobal}},
+		History: map[string][]spec.AppliedRevision{
+			spec.TargetCursor:   {{Version: "1.0.0", Mode: spec.ModeGlobal}, {Version: "1.1.0", Mode: spec.ModeGlobal}},
+			spec.TargetOpenCode: {{Version: "1.0.0"}, {Version: "1.1.0"}},
+		},
+	}
+
+	mode, err := rollbackModeFor(skillVars, spec.TargetCursor, "")
+	if err != nil || mode != spec.ModeGlobal {
+		t.Fatalf("rollbackModeFor() = %q, %v; want global", mode, err)
+	}
+	if got := selectAdapters(spec.TargetCursor, mode)[0]; !reflect.DeepEqual(got, cursor.NewCursorAdapter().WithGlobalMode()) {
+		t.Errorf("rollback adapter = %+v, want the global cursor adapter", got)
+	}
+
+	if _, err := rollbackModeFor(skillVars, spec.TargetCursor, spec.ModeProject); err == nil || !strings.Contains(err.Error(), "global") {
+		t.Errorf("rollbackModeFor() with --mode project error = %v, want conflict with global", err)
+	}
+	if mode, err := rollbackModeFor(skillVars, spec.TargetOpenCode, ""); err != nil || mode != spec.ModeProject {
+		t.Errorf("rollbackModeFor() without applied record = %q, %v; want project", mode, err)
+	}
+}
@@ -39,12 +39,17 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	err = finishOutput(cmd, err)
+	finishUpdateCheck(err)
+	return err
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(tagsCmd)
 	rootCmd.AddCommand(useCmd)
 	rootCmd.AddCommand(applyCmd)
 	rootCmd.AddCommand(statusCmd)
@@ -55,4 +60,24 @@ func init() {
 	rootCmd.AddCommand(removeCmd)
 	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(validateLocalCmd)
+	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(cloneCmd)
+	rootCmd.AddCommand(lintPromptCmd)
+	rootCmd.AddCommand(uninstallCmd)
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(fmtCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(stateCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(workspaceCmd)
 }
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"skill-hub/internal/state"
+	"skill-hub/internal/workspace"
+
+	"github.com/spf13/cobra"
+)
+
+// projectDir --project 指定的项目目录
+var projectDir string
+
+// addProjectFlag 为命令添加 --project 参数，命令在指定的项目目录中执行，
+// 脚本和CI可以操作项目而不必先切换到项目目录
+func addProjectFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&projectDir, "project", "", "项目目录 (为空时使用当前目录)")
+	_ = cmd.MarkFlagDirname("project")
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if projectDir == "" {
+			return nil
+		}
+		if global := cmd.Flags().Lookup("global"); global != nil && global.Changed {
+			return fmt.Errorf("--project 不能与 --global 同时使用")
+		}
+		return enterProject()
+	}
+}
+
+// enterProject 切换到 --project 指定的项目目录，未指定时不做任何操作
+//
+// 目录按项目状态的规则规范化（绝对路径，解析符号链接），与在项目目录中执行时记录的项目相同。
+func enterProject() error {
+	if projectDir == "" {
+		return nil
+	}
+	path, err := state.NormalizeProjectPath(projectDir)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("项目目录 %s 不存在", projectDir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s 不是目录", projectDir)
+	}
+	if err := os.Chdir(path); err != nil {
+		return fmt.Errorf("切换到项目目录失败: %w", err)
+	}
+	debugf("项目目录: %s", path)
+	return nil
+}
+
+// scopeState 返回作用域对应的状态管理器和项目路径
+//
+// 全局作用域（--global）的技能记录在独立的状态文件中，以用户主目录作为项目路径；
+// 否则使用当前目录的项目状态。
+func scopeState(global bool) (*state.StateManager, string, error) {
+	if global {
+		stateMgr, err := state.NewGlobalStateManager()
+		if err != nil {
+			return nil, "", err
+		}
+		scopePath, err := state.GlobalScopePath()
+		if err != nil {
+			return nil, "", err
+		}
+		return stateMgr, scopePath, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, "", fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return nil, "", err
+	}
+	return stateMgr, cwd, nil
+}
+
+// scopeFlag 返回命令示例中表示作用域的参数
+func scopeFlag(global bool) string {
+	if global {
+		return " --global"
+	}
+	return ""
+}
+
+// detectWorkspace 从当前目录向上查找工作区
+func detectWorkspace() (*workspace.Workspace, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	ws, err := workspace.Detect(cwd)
+	if err != nil {
+		return nil, err
+	}
+	if ws == nil {
+		return nil, fmt.Errorf("当前目录不在工作区中（未找到 %s、go.work 或带 workspaces 字段的 package.json）", workspace.MarkerFile)
+	}
+	return ws, nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnterProject(t *testing.T) {
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(oldDir)
+		projectDir = ""
+	})
+
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	if err := os.Mkdir(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(project, link); err != nil {
+		t.Skipf("无法创建符号链接: %v", err)
+	}
+
+	// 通过符号链接指定项目时切换到规范路径
+	projectDir = link
+	if err := enterProject(); err != nil {
+		t.Fatalf("enterProject() error = %v", err)
+	}
+	cwd, _ := os.Getwd()
+	want, _ := filepath.EvalSymlinks(project)
+	if cwd != want {
+		t.Errorf("cwd = %s, want %s", cwd, want)
+	}
+
+	projectDir = filepath.Join(root, "missing")
+	if err := enterProject(); err == nil {
+		t.Error("enterProject() with missing directory should fail")
+	}
+	file := filepath.Join(root, "file")
+	os.WriteFile(file, nil, 0644)
+	projectDir = file
+	if err := enterProject(); err == nil {
+		t.Error("enterProject() with a file should fail")
+	}
+}
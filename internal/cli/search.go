@@ -1,35 +1,269 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"skill-hub/internal/config"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/forge"
+	"skill-hub/internal/registry"
+	"skill-hub/pkg/spec"
+)
+
+var (
+	searchTags  []string
+	searchForge string
 )
 
 var searchCmd = &cobra.Command{
 	Use:   "search [keyword]",
-	Short: "从GitHub搜索技能",
-	Long:  "调用GitHub API搜索带有指定标签的技能仓库。",
-	Args:  cobra.ExactArgs(1),
+	Short: "搜索技能",
+	Long: `在技能仓库中按关键字（匹配ID、名称、描述和标签）和标签查找技能，
+在配置的 registry_url 静态HTTP技能注册表中查找技能，并在代码托管平台上按关键字搜索技能仓库。
+
+代码托管平台在配置文件的 forges 中设置，支持 GitHub、GitLab 和 Gitea（包括自建实例），
+未设置时搜索 github.com 和 gitlab.com：
+  forges:
+    - name: corp
+      type: gitlab
+      url: https://gitlab.example.com
+      token: glpat-xxx
+
+示例:
+  skill-hub search git
+  skill-hub search git --forge corp             # 只在指定平台搜索
+  skill-hub search --tag golang --tag testing   # 只查找技能仓库中同时带有两个标签的技能`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runSearch(args[0])
+		keyword := ""
+		if len(args) > 0 {
+			keyword = args[0]
+		}
+		if keyword == "" && len(searchTags) == 0 {
+			return fmt.Errorf("请指定关键字或 --tag")
+		}
+		return runSearch(cmd.Context(), keyword)
 	},
 }
 
-func runSearch(keyword string) error {
-	fmt.Printf("在GitHub搜索技能: %s\n", keyword)
-	fmt.Println("调用GitHub API...")
+func init() {
+	searchCmd.Flags().StringArrayVar(&searchTags, "tag", nil, "只查找带有该标签的技能（可多次指定）")
+	searchCmd.Flags().StringVar(&searchForge, "forge", "", "只在指定的代码托管平台搜索（forges 中配置的名称）")
+	searchCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	searchCmd.RegisterFlagCompletionFunc("forge", completeForges)
+}
 
-	fmt.Println("\n🔍 搜索结果:")
-	fmt.Println("仓库                             星标   描述")
-	fmt.Println("------------------------------------------------------------")
-	fmt.Println("awesome-ai-skills                124   精选AI技能集合")
-	fmt.Println("cursor-rules-collection          89    Cursor规则大全")
-	fmt.Println("claude-code-prompts              67    Claude Code提示词")
-	fmt.Println("git-workflow-automation          45    Git工作流自动化")
+// searchResult --json 模式下 search 命令的结果
+type searchResult struct {
+	Skills   []searchItem         `json:"skills"`
+	Registry []spec.SkillMetadata `json:"registry,omitempty"`
+	Repos    []forge.Repo         `json:"repos,omitempty"`
+}
 
-	fmt.Println("\n使用 'skill-hub import <repo-url>' 导入技能")
-	fmt.Println("示例: skill-hub import https://github.com/user/awesome-ai-skills")
+// searchItem 技能仓库中匹配的技能
+type searchItem struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	Downloads   int      `json:"downloads,omitempty"`
+	Rating      float64  `json:"rating,omitempty"`
+	RatingCount int      `json:"rating_count,omitempty"`
+}
 
+func runSearch(ctx context.Context, keyword string) error {
+	skills, err := searchLocalSkills(keyword, searchTags)
+	if err != nil {
+		return err
+	}
+	result := searchResult{Skills: skills}
+	defer func() { setResult(result) }()
+
+	client, err := newRegistryClient()
+	if err != nil {
+		return err
+	}
+	if client != nil {
+		if client.Offline() {
+			fmt.Printf("\n📦 在缓存的技能注册表 %s 搜索\n", client.URL())
+		} else {
+			fmt.Printf("\n🌐 在技能注册表 %s 搜索\n", client.URL())
+		}
+		if result.Registry, err = searchRegistry(ctx, client, keyword, searchTags); err != nil {
+			fmt.Printf("⚠️  搜索失败: %v\n", err)
+		}
+	}
+	if keyword == "" {
+		return nil
+	}
+	if isOffline() {
+		fmt.Println("\nℹ️  离线模式，跳过代码托管平台搜索")
+		return nil
+	}
+
+	forges, err := searchForges(searchForge)
+	if err != nil {
+		return err
+	}
+	for _, f := range forges {
+		fmt.Printf("\n🌐 在 %s (%s) 搜索: %s\n", f.Name, f.URL, keyword)
+		repos, err := forge.Search(ctx, f, keyword, forge.DefaultLimit)
+		if err != nil {
+			fmt.Printf("⚠️  搜索失败: %v\n", err)
+			continue
+		}
+		if len(repos) == 0 {
+			fmt.Println("ℹ️  没有匹配的仓库")
+			continue
+		}
+		fmt.Printf("%-40s %-6s %s\n", "仓库", "星标", "描述")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, repo := range repos {
+			fmt.Printf("%-40s %-6d %s\n", repo.FullName, repo.Stars, completionDescription(repo.Description))
+		}
+		result.Repos = append(result.Repos, repos...)
+	}
+
+	if len(result.Repos) > 0 {
+		fmt.Println("\n使用 'skill-hub import <平台名称>:<仓库>' 导入技能")
+		fmt.Printf("示例: skill-hub import %s:%s\n", result.Repos[0].Forge, result.Repos[0].FullName)
+	}
 	return nil
 }
+
+// searchRegistry 在HTTP技能注册表的 index.json 中查找匹配关键字和标签的技能
+func searchRegistry(ctx context.Context, client *registry.Client, keyword string, tags []string) ([]spec.SkillMetadata, error) {
+	index, err := client.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []spec.SkillMetadata
+	for _, meta := range index.Skills {
+		skill := &spec.Skill{ID: meta.ID, Name: meta.Name, Description: meta.Description, Tags: meta.Tags}
+		if skill.HasTags(tags) && matchesKeyword(skill, keyword) {
+			matched = append(matched, meta)
+		}
+	}
+	if len(matched) == 0 {
+		fmt.Println("ℹ️  没有匹配的技能")
+		return nil, nil
+	}
+	for _, meta := range matched {
+		line := fmt.Sprintf("  %-24s %-10s %s", meta.ID, meta.Version, completionDescription(meta.Description))
+		if meta.Downloads > 0 || meta.RatingText() != "-" {
+			line += fmt.Sprintf("  ⬇ %d  ★ %s", meta.Downloads, meta.RatingText())
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("使用 'skill-hub import %s' 导入技能\n", matched[0].ID)
+	return matched, nil
+}
+
+// searchForges 返回要搜索的代码托管平台，name 不为空时只返回该平台
+func searchForges(name string) ([]config.Forge, error) {
+	forges, err := config.GetForges()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return forges, nil
+	}
+	for _, f := range forges {
+		if f.Name == name {
+			return []config.Forge{f}, nil
+		}
+	}
+	names := make([]string, 0, len(forges))
+	for _, f := range forges {
+		names = append(names, f.Name)
+	}
+	return nil, fmt.Errorf("未配置代码托管平台 %s，可用平台: %s", name, strings.Join(names, ", "))
+}
+
+// completeForges 补全配置的代码托管平台名称
+func completeForges(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	forges, err := config.GetForges()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, f := range forges {
+		if strings.HasPrefix(f.Name, toComplete) {
+			completions = append(completions, f.Name+"\t"+f.Type+" "+f.URL)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// searchLocalSkills 在技能仓库中查找匹配关键字和标签的技能
+func searchLocalSkills(keyword string, tags []string) ([]searchItem, error) {
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return nil, err
+	}
+	skills, err := manager.LoadAllSkills()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*spec.Skill
+	for _, skill := range filterSkillsByTags(skills, tags) {
+		if matchesKeyword(skill, keyword) {
+			matched = append(matched, skill)
+		}
+	}
+
+	// 下载次数和评分来自远程仓库的注册表，读取失败时不显示
+	registry := &spec.Registry{}
+	if registryPath, err := config.GetRegistryPath(); err == nil {
+		if loaded, err := readRegistry(registryPath); err == nil && loaded != nil {
+			registry = loaded
+		}
+	}
+
+	items := make([]searchItem, 0, len(matched))
+	for _, skill := range matched {
+		item := searchItem{ID: skill.ID, Name: skill.Name, Version: skill.Version, Description: skill.Description, Tags: skill.Tags}
+		if meta, ok := registry.Find(skill.ID); ok {
+			item.Downloads, item.Rating, item.RatingCount = meta.Downloads, meta.Rating, meta.RatingCount
+		}
+		items = append(items, item)
+	}
+
+	if len(matched) == 0 {
+		fmt.Println("ℹ️  技能仓库中没有匹配的技能")
+		return items, nil
+	}
+	fmt.Printf("📦 技能仓库中匹配的技能 (%d):\n", len(matched))
+	for _, skill := range matched {
+		line := fmt.Sprintf("  %-24s %s", skill.ID, completionDescription(skill.Description))
+		if len(skill.Tags) > 0 {
+			line += fmt.Sprintf("  [%s]", strings.Join(skill.Tags, ", "))
+		}
+		if meta, ok := registry.Find(skill.ID); ok && (meta.Downloads > 0 || meta.RatingText() != "-") {
+			line += fmt.Sprintf("  ⬇ %d  ★ %s", meta.Downloads, meta.RatingText())
+		}
+		fmt.Println(line)
+	}
+	return items, nil
+}
+
+// matchesKeyword 检查技能的ID、名称、描述或标签是否包含关键字（忽略大小写），关键字为空时匹配所有技能
+func matchesKeyword(skill *spec.Skill, keyword string) bool {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return true
+	}
+	fields := append([]string{skill.ID, skill.Name, skill.Description}, skill.Tags...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), keyword) {
+			return true
+		}
+	}
+	return false
+}
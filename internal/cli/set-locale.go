@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+)
+
+var setLocaleCmd = &cobra.Command{
+	Use:   "set-locale <locale>",
+	Short: "设置当前项目的技能提示词语言",
+	Long: `设置当前项目使用的技能提示词语言，例如 zh-CN 或 en。
+
+技能可以在 SKILL.md 旁提供语言版本 SKILL.<语言>.md（如 SKILL.zh-CN.md），apply 时使用与项目语言匹配的版本：
+没有完全匹配时依次尝试更通用的语言（zh-CN -> zh），都没有时使用 SKILL.md。
+项目未设置语言时使用全局配置项 locale。
+
+示例:
+  skill-hub set-locale zh-CN   # 使用简体中文版本
+  skill-hub set-locale ""      # 清除项目设置，使用全局配置`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetLocale(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setLocaleCmd)
+}
+
+func runSetLocale(locale string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	normalized, err := spec.NormalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	if err := stateManager.SetLocale(cwd, normalized); err != nil {
+		return fmt.Errorf("设置语言失败: %w", err)
+	}
+
+	if normalized == "" {
+		fmt.Printf("✅ 已清除项目 '%s' 的语言设置，将使用全局配置\n", filepath.Base(cwd))
+	} else {
+		fmt.Printf("✅ 已将项目 '%s' 的技能提示词语言设置为: %s\n", filepath.Base(cwd), normalized)
+		fmt.Println("下次执行 'skill-hub apply' 时将使用技能的对应语言版本（没有时使用 SKILL.md）")
+	}
+
+	setResult(map[string]string{"locale": normalized})
+	return nil
+}
+
+// useProjectLocale 项目设置了语言时，技能管理器使用项目的语言代替全局配置
+func useProjectLocale(manager *engine.SkillManager, projectState *spec.ProjectState) {
+	if projectState != nil && projectState.Locale != "" {
+		manager.SetLocale(projectState.Locale)
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+)
+
+// priorityDefault 清除项目覆盖的优先级时使用的参数
+const priorityDefault = "default"
+
+var setPriorityCmd = &cobra.Command{
+	Use:   "set-priority <skill-id> <priority|default>",
+	Short: "设置技能在当前项目中的应用顺序优先级",
+	Long: `设置技能在当前项目中的应用顺序优先级，覆盖技能 SKILL.md 中声明的 priority。
+
+apply 按优先级从高到低应用技能，并按同样的顺序排列目标文件（如 .cursorrules）中的技能，
+重要的指令可以放在最前面；优先级相同的技能按技能ID排序，被依赖的技能排在依赖它的技能之前。
+技能未声明优先级时为 0，优先级可以为负数。
+
+示例:
+  skill-hub set-priority git-expert 10        # 排在其他技能之前
+  skill-hub set-priority git-expert -- -5     # 排在其他技能之后
+  skill-hub set-priority git-expert default   # 使用技能声明的优先级`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return []string{priorityDefault + "\t使用技能声明的优先级"}, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeProjectSkillIDs(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetPriority(args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setPriorityCmd)
+}
+
+func runSetPriority(skillID, value string) error {
+	var priority *int
+	if value != priorityDefault {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("无效的优先级 %q，应为整数或 %s", value, priorityDefault)
+		}
+		priority = &n
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	if err := stateManager.SetSkillPriority(cwd, skillID, priority); err != nil {
+		return fmt.Errorf("设置优先级失败: %w", err)
+	}
+
+	effective := 0
+	if priority != nil {
+		effective = *priority
+		fmt.Printf("✅ 已将技能 %s 在当前项目中的优先级设置为: %d\n", skillID, effective)
+	} else {
+		if manager, err := engine.NewSkillManager(); err == nil {
+			if skill, err := manager.LoadSkill(skillID); err == nil {
+				effective = skill.Priority
+			}
+		}
+		fmt.Printf("✅ 已清除技能 %s 在当前项目中的优先级设置，使用技能声明的优先级: %d\n", skillID, effective)
+	}
+	fmt.Println("下次执行 'skill-hub apply' 时按新的顺序排列目标文件中的技能")
+
+	setResult(map[string]interface{}{"skill_id": skillID, "priority": effective, "override": priority != nil})
+	return nil
+}
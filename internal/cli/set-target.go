@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"skill-hub/internal/state"
@@ -11,22 +12,37 @@ import (
 )
 
 var setTargetCmd = &cobra.Command{
-	Use:   "set-target [cursor|claude_code|open_code]",
+	Use:   "set-target [cursor|claude_code|open_code]...",
 	Short: "设置当前项目的首选目标",
 	Long: `设置当前项目的首选目标（Cursor、Claude Code 或 OpenCode）。
 
 此命令会更新项目状态，使后续的 apply、feedback 等命令自动使用指定的目标适配器。
+可以按顺序绑定多个目标（用空格或逗号分隔），apply、remove、feedback 默认依次处理所有绑定的目标。
 
 示例:
   skill-hub set-target cursor      # 设置为 Cursor
   skill-hub set-target claude_code # 设置为 Claude Code
   skill-hub set-target open_code   # 设置为 OpenCode
+  skill-hub set-target cursor claude_code  # 同时绑定 Cursor 和 Claude Code
   skill-hub set-target ""          # 清除目标设置
   
 注意: 也接受简写形式 claude 和 opencode`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var remaining []string
+		for _, candidate := range []string{spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode} {
+			used := false
+			for _, arg := range args {
+				used = used || spec.NormalizeTarget(arg) == candidate
+			}
+			if !used {
+				remaining = append(remaining, candidate)
+			}
+		}
+		return remaining, cobra.ShellCompDirectiveNoFileComp
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runSetTarget(args[0])
+		return runSetTarget(args)
 	},
 }
 
@@ -34,17 +50,27 @@ func init() {
 	rootCmd.AddCommand(setTargetCmd)
 }
 
-func runSetTarget(target string) error {
+func runSetTarget(args []string) error {
 	// 获取当前目录
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("获取当前目录失败: %w", err)
 	}
 
-	// 验证目标值（先规范化）
-	normalizedTarget := spec.NormalizeTarget(target)
-	if normalizedTarget != spec.TargetCursor && normalizedTarget != spec.TargetClaudeCode && normalizedTarget != spec.TargetOpenCode && normalizedTarget != "" {
-		return fmt.Errorf("无效的目标值: %s，可用选项: %s, %s, %s (也接受简写 claude 和 opencode)", target, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode)
+	// 验证目标值（先规范化），参数中可以用逗号分隔多个目标
+	var targets []string
+	for _, arg := range args {
+		for _, target := range strings.Split(arg, ",") {
+			target = strings.TrimSpace(target)
+			if target == "" {
+				continue
+			}
+			normalizedTarget := spec.NormalizeTarget(target)
+			if normalizedTarget != spec.TargetCursor && normalizedTarget != spec.TargetClaudeCode && normalizedTarget != spec.TargetOpenCode && normalizedTarget != spec.TargetShell {
+				return fmt.Errorf("无效的目标值: %s，可用选项: %s, %s, %s, %s (也接受简写 claude 和 opencode)", target, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell)
+			}
+			targets = append(targets, normalizedTarget)
+		}
 	}
 
 	// 创建状态管理器
@@ -54,17 +80,27 @@ func runSetTarget(target string) error {
 	}
 
 	// 设置首选目标（使用规范化后的值）
-	if err := stateManager.SetPreferredTarget(cwd, normalizedTarget); err != nil {
+	if err := stateManager.SetPreferredTargets(cwd, targets); err != nil {
 		return fmt.Errorf("设置首选目标失败: %w", err)
 	}
+	projectState, err := stateManager.LoadProjectState(cwd)
+	if err != nil {
+		return err
+	}
+	bound := projectState.BoundTargets()
 
 	// 显示结果
-	if normalizedTarget == "" {
+	if len(bound) == 0 {
 		fmt.Printf("✅ 已清除项目 '%s' 的首选目标\n", filepath.Base(cwd))
 	} else {
-		fmt.Printf("✅ 已将项目 '%s' 的首选目标设置为: %s\n", filepath.Base(cwd), normalizedTarget)
-		fmt.Println("下次执行 'skill-hub apply' 时将自动使用此目标")
+		fmt.Printf("✅ 已将项目 '%s' 的首选目标设置为: %s\n", filepath.Base(cwd), strings.Join(bound, ", "))
+		if len(bound) > 1 {
+			fmt.Println("下次执行 'skill-hub apply' 时将依次应用到这些目标")
+		} else {
+			fmt.Println("下次执行 'skill-hub apply' 时将自动使用此目标")
+		}
 	}
 
+	setResult(map[string][]string{"targets": bound})
 	return nil
 }
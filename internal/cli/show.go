@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/template"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var showFunctions bool
+
+var showCmd = &cobra.Command{
+	Use:   "show <skill-id>",
+	Short: "显示技能详情",
+	Long: `显示技能的版本、作者、许可证、适用工具、依赖、预先授权的工具和变量定义。
+
+使用 --functions 列出技能内容中可以使用的模板函数（如 {{upper .NAME}}），所有目标工具渲染时都可用。`,
+	Args:              cobra.RangeArgs(0, 1),
+	ValidArgsFunction: completeSkillIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if showFunctions {
+			if len(args) > 0 {
+				return fmt.Errorf("--functions 不需要指定技能ID")
+			}
+			return runShowFunctions()
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("请指定技能ID，或使用 --functions 列出模板函数")
+		}
+		return runShow(args[0])
+	},
+}
+
+func init() {
+	showCmd.Flags().BoolVar(&showFunctions, "functions", false, "列出技能内容中可以使用的模板函数")
+}
+
+// templateFunction 模板函数的说明，用于 --json 输出
+type templateFunction struct {
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+}
+
+// runShowFunctions 列出技能内容中可以使用的模板函数
+func runShowFunctions() error {
+	functions := []templateFunction{}
+	fmt.Println("技能内容中可以使用的模板函数:")
+	for _, fn := range template.Funcs() {
+		functions = append(functions, templateFunction{Name: fn.Name, Usage: fn.Usage, Description: fn.Description})
+		fmt.Printf("  %-28s %s\n", fn.Usage, fn.Description)
+	}
+	fmt.Println("\n参数可以是 .变量、带引号的字符串或整数，未设置的变量为空。")
+	fmt.Println("条件区块 {{if ...}} 中可以使用 eq、ne 和 not，内置变量 .Target 和 .Mode 在渲染时注入。")
+	setResult(functions)
+	return nil
+}
+
+func runShow(skillID string) error {
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	if !manager.SkillExists(skillID) {
+		return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", skillID)
+	}
+	skill, err := manager.LoadSkill(skillID)
+	if err != nil {
+		return fmt.Errorf("加载技能失败: %w", err)
+	}
+	origin, _ := manager.Origin(skillID)
+
+	fmt.Printf("技能: %s (%s)\n", skill.Name, skill.ID)
+	fmt.Printf("版本: %s\n", skill.Version)
+	fmt.Printf("作者: %s\n", skill.Author)
+	if skill.License != "" {
+		fmt.Printf("许可证: %s\n", skill.License)
+	} else {
+		fmt.Println("许可证: 未声明")
+	}
+	fmt.Printf("描述: %s\n", skill.Description)
+	if len(skill.Tags) > 0 {
+		fmt.Printf("标签: %s\n", strings.Join(skill.Tags, ", "))
+	}
+	if targets := skill.Compatible().Targets(); len(targets) > 0 {
+		fmt.Printf("适用工具: %s\n", strings.Join(targets, ", "))
+	}
+	if len(skill.Dependencies) > 0 {
+		fmt.Printf("依赖: %s\n", strings.Join(skill.Dependencies, ", "))
+	}
+	if len(skill.AllowedTools) > 0 {
+		fmt.Printf("预先授权的工具: %s\n", strings.Join(skill.AllowedTools, " "))
+	}
+	if req := skill.Requires; req != nil {
+		if req.SkillHub != "" {
+			fmt.Printf("需要 skill-hub: %s\n", req.SkillHub)
+		}
+		if len(req.OS) > 0 {
+			fmt.Printf("支持的系统: %s\n", strings.Join(req.OS, ", "))
+		}
+	}
+	locales := manager.SkillLocales(skillID)
+	if len(locales) > 0 {
+		fmt.Printf("语言版本: %s\n", strings.Join(locales, ", "))
+	}
+	var installed []string
+	if versions, err := engine.NewVersionStore(); err == nil {
+		installed = versions.Versions(skillID)
+	}
+	if len(installed) > 0 {
+		fmt.Printf("已安装版本: %s\n", strings.Join(installed, ", "))
+	}
+	if origin.Root != "" {
+		fmt.Printf("来源: %s\n", origin.Root)
+	}
+
+	if len(skill.Variables) > 0 {
+		fmt.Println("\n变量:")
+		for _, variable := range skill.Variables {
+			line := "  " + variable.Name
+			if hint := variable.Hint(); hint != "" {
+				line += " (" + hint + ")"
+			}
+			if variable.Default != "" {
+				line += " = " + variable.Default
+			}
+			if variable.Description != "" {
+				line += "  # " + variable.Description
+			}
+			fmt.Println(line)
+		}
+	}
+
+	setResult(struct {
+		*spec.Skill
+		Targets   []string `json:"targets"`
+		Locales   []string `json:"locales,omitempty"`
+		Installed []string `json:"installed_versions,omitempty"`
+		Source    string   `json:"source,omitempty"`
+	}{skill, skill.Compatible().Targets(), locales, installed, origin.Root})
+	return nil
+}
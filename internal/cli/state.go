@@ -0,0 +1,481 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "管理项目状态的保存位置",
+	Long: `项目状态默认保存在技能仓库的全局状态文件中，按项目路径区分。
+
+使用 'skill-hub state local' 将项目状态保存到项目内的 ` + state.LocalStateFile + `，
+提交到版本库后团队成员共享同一组技能、版本、变量和首选目标。
+该文件存在时优先于全局状态；应用历史包含本机渲染的内容，仍保存在全局状态中。
+
+示例:
+  skill-hub state local    # 改为使用项目内状态文件
+  skill-hub state global   # 改回只使用全局状态
+  skill-hub state relocate /old/path   # 项目移动后，将原路径的状态迁移到当前目录
+  skill-hub state export -o skills.yaml  # 导出启用的技能和变量
+  skill-hub state import skills.yaml     # 在其他机器上导入
+  skill-hub state prune --all            # 清理技能仓库中已不存在的技能`,
+}
+
+var stateLocalCmd = &cobra.Command{
+	Use:   "local",
+	Short: "将项目状态保存到项目内的状态文件",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStateLocal()
+	},
+}
+
+var stateGlobalCmd = &cobra.Command{
+	Use:   "global",
+	Short: "删除项目内的状态文件，改回只使用全局状态",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStateGlobal()
+	},
+}
+
+var stateRelocateCmd = &cobra.Command{
+	Use:   "relocate [old-path] [new-path]",
+	Short: "项目目录移动后，将原路径的状态迁移到新路径",
+	Long: `全局状态按项目路径记录。项目目录被移动或重命名后，使用该命令将原路径的状态迁移到新路径。
+
+不带参数时列出路径已不存在的项目记录；省略新路径时迁移到当前目录。
+Git 仓库中的项目带有由远程地址计算的标识，移动后首次使用时会自动找回原状态，通常无需手动迁移。`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStateRelocate(args)
+	},
+}
+
+var (
+	stateExportFormat   string
+	stateExportOutput   string
+	stateImportConflict string
+	statePruneAll       bool
+	statePruneDryRun    bool
+)
+
+var stateExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出项目启用的技能和变量",
+	Long: `将当前项目启用的技能、版本、变量和首选目标导出为可移植的 JSON 或 YAML 快照，
+团队成员使用 'skill-hub state import' 导入后在本机应用。
+
+未指定 --output 时输出到标准输出；指定了输出文件时，格式默认由扩展名决定（.yaml/.yml 为 YAML）。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStateExport()
+	},
+}
+
+var stateImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "导入 'skill-hub state export' 导出的技能和变量",
+	Long: `从 JSON 或 YAML 快照导入技能、版本、变量和首选目标到当前项目。
+
+技能已在项目中启用但配置不同、或首选目标不同时视为冲突，
+默认交互式询问是否覆盖，使用 --on-conflict overwrite|skip 跳过询问。
+技能仓库中不存在的技能会被跳过。导入后使用 'skill-hub apply' 应用技能。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStateImport(args[0])
+	},
+}
+
+var statePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "清理项目状态中技能仓库已不存在的技能",
+	Long: `技能从技能仓库删除或重命名后，项目状态中仍保留对它的引用。
+该命令检查当前项目（使用 --all 时检查所有项目）启用的技能，从状态中删除技能仓库中不存在的技能。
+
+状态中的记录删除后，目标工具配置文件中已应用的内容不会自动清理，
+可以先在项目中运行 'skill-hub remove <skill-id>'，它会按应用记录清理目标文件并更新状态。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStatePrune()
+	},
+}
+
+func init() {
+	statePruneCmd.Flags().BoolVar(&statePruneAll, "all", false, "检查状态文件中记录的所有项目")
+	statePruneCmd.Flags().BoolVar(&statePruneDryRun, "dry-run", false, "只列出需要清理的技能，不修改状态")
+	stateExportCmd.Flags().StringVar(&stateExportFormat, "format", "", "导出格式: json, yaml (默认由输出文件扩展名决定，否则为 json)")
+	stateExportCmd.Flags().StringVarP(&stateExportOutput, "output", "o", "", "输出文件 (默认输出到标准输出)")
+	stateImportCmd.Flags().StringVar(&stateImportConflict, "on-conflict", conflictAsk, "冲突处理: ask, overwrite, skip")
+
+	stateExportCmd.RegisterFlagCompletionFunc("format", fixedCompletions(state.SnapshotFormatJSON, state.SnapshotFormatYAML))
+	stateImportCmd.RegisterFlagCompletionFunc("on-conflict", fixedCompletions(conflictAsk, conflictOverwrite, conflictSkip))
+
+	stateCmd.AddCommand(stateLocalCmd)
+	stateCmd.AddCommand(stateGlobalCmd)
+	stateCmd.AddCommand(stateRelocateCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateCmd.AddCommand(stateImportCmd)
+	stateCmd.AddCommand(statePruneCmd)
+}
+
+func runStateLocal() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+
+	existed := state.HasLocalState(cwd)
+	path, err := stateManager.EnableLocalState(cwd)
+	if err != nil {
+		return fmt.Errorf("保存项目状态失败: %w", err)
+	}
+
+	if existed {
+		fmt.Printf("ℹ️  项目已使用项目内状态文件: %s\n", path)
+	} else {
+		fmt.Printf("✓ 已将项目状态保存到: %s\n", path)
+		fmt.Println("提交该文件到版本库即可与团队共享技能配置")
+	}
+	setResult(map[string]string{"state_file": path})
+	return nil
+}
+
+func runStateGlobal() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+
+	if !state.HasLocalState(cwd) {
+		fmt.Println("ℹ️  项目未使用项目内状态文件")
+		return nil
+	}
+
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	if err := stateManager.DisableLocalState(cwd); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ 已删除 %s，项目状态已合并到全局状态\n", state.LocalStateFile)
+	return nil
+}
+
+func runStateRelocate(args []string) error {
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		paths, err := stateManager.ProjectPaths()
+		if err != nil {
+			return err
+		}
+		var missing []string
+		for _, path := range paths {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				missing = append(missing, path)
+			}
+		}
+		if len(missing) == 0 {
+			fmt.Println("ℹ️  所有项目记录的路径都存在，无需迁移")
+		} else {
+			fmt.Println("以下项目记录的路径已不存在:")
+			for _, path := range missing {
+				fmt.Printf("  %s\n", path)
+			}
+			fmt.Println("\n使用 'skill-hub state relocate <原路径> [新路径]' 迁移到新位置")
+		}
+		setResult(map[string][]string{"missing_projects": missing})
+		return nil
+	}
+
+	newPath := ""
+	if len(args) == 2 {
+		newPath = args[1]
+	} else {
+		newPath, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("获取当前目录失败: %w", err)
+		}
+	}
+	if info, err := os.Stat(newPath); err != nil || !info.IsDir() {
+		return fmt.Errorf("新路径 %s 不是有效的目录", newPath)
+	}
+
+	newKey, err := stateManager.RelocateProject(args[0], newPath)
+	if err != nil {
+		return fmt.Errorf("迁移项目状态失败: %w", err)
+	}
+
+	fmt.Printf("✓ 已将项目状态从 %s 迁移到 %s\n", args[0], newKey)
+	setResult(map[string]string{"old_path": args[0], "new_path": newKey})
+	return nil
+}
+
+func runStateExport() error {
+	format := stateExportFormat
+	if format == "" {
+		format = state.SnapshotFormatForPath(stateExportOutput)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := stateManager.ExportSnapshot(cwd)
+	if err != nil {
+		return err
+	}
+	data, err := state.MarshalSnapshot(snapshot, format)
+	if err != nil {
+		return err
+	}
+
+	if stateExportOutput == "" {
+		os.Stdout.Write(data)
+		return nil
+	}
+	if err := os.WriteFile(stateExportOutput, data, 0644); err != nil {
+		return fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	fmt.Printf("✓ 已导出 %d 个技能到: %s\n", len(snapshot.Skills), stateExportOutput)
+	setResult(map[string]interface{}{"output": stateExportOutput, "skills": len(snapshot.Skills)})
+	return nil
+}
+
+func runStateImport(path string) error {
+	switch stateImportConflict {
+	case conflictAsk, conflictOverwrite, conflictSkip:
+	default:
+		return fmt.Errorf("无效的冲突处理策略: %s，可用选项: ask, overwrite, skip", stateImportConflict)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取导入文件失败: %w", err)
+	}
+	snapshot, err := state.ParseSnapshot(data)
+	if err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	projectState, err := stateManager.LoadProjectState(cwd)
+	if err != nil {
+		return err
+	}
+
+	reader := newInputReader()
+	imported, unchanged, skipped := []string{}, []string{}, []string{}
+
+	skillIDs := make([]string, 0, len(snapshot.Skills))
+	for skillID := range snapshot.Skills {
+		skillIDs = append(skillIDs, skillID)
+	}
+	sort.Strings(skillIDs)
+
+	for _, skillID := range skillIDs {
+		incoming := snapshot.Skills[skillID]
+		if !skillManager.SkillExists(skillID) {
+			fmt.Printf("⚠️  技能 %s 不在技能仓库中，跳过（可使用 'skill-hub import' 导入技能）\n", skillID)
+			skipped = append(skipped, skillID)
+			continue
+		}
+
+		if current, enabled := projectState.Skills[skillID]; enabled {
+			if incoming.Equal(current) {
+				unchanged = append(unchanged, skillID)
+				continue
+			}
+			fmt.Printf("\n⚠️  技能 %s 已启用且配置不同\n", skillID)
+			printSnapshotDiff(current, incoming)
+			if !resolveStateConflict(reader, "覆盖当前配置？") {
+				fmt.Printf("跳过技能 %s\n", skillID)
+				skipped = append(skipped, skillID)
+				continue
+			}
+		}
+
+		if err := stateManager.AddSkillToProject(cwd, skillID, incoming.Version, incoming.Variables); err != nil {
+			return fmt.Errorf("保存项目状态失败: %w", err)
+		}
+		fmt.Printf("✓ 导入技能 %s@%s\n", skillID, incoming.Version)
+		imported = append(imported, skillID)
+	}
+
+	// 首选目标：项目尚未启用技能时直接使用导入的目标，否则视为冲突
+	target := strings.Join(projectState.BoundTargets(), ", ")
+	incomingTargets := snapshot.Targets()
+	if incoming := strings.Join(incomingTargets, ", "); incoming != "" && incoming != target {
+		overwrite := len(projectState.Skills) == 0
+		if !overwrite {
+			fmt.Printf("\n⚠️  首选目标不同: 当前 %s，导入 %s\n", target, incoming)
+			overwrite = resolveStateConflict(reader, "使用导入的首选目标？")
+		}
+		if overwrite {
+			if err := stateManager.SetPreferredTargets(cwd, incomingTargets); err != nil {
+				return err
+			}
+			fmt.Printf("✓ 首选目标已设置为: %s\n", incoming)
+		}
+	}
+
+	// 语言：与首选目标相同，只在项目尚未启用技能或确认后覆盖
+	if snapshot.Locale != "" && snapshot.Locale != projectState.Locale {
+		overwrite := len(projectState.Skills) == 0 || projectState.Locale == ""
+		if !overwrite {
+			fmt.Printf("\n⚠️  语言不同: 当前 %s，导入 %s\n", projectState.Locale, snapshot.Locale)
+			overwrite = resolveStateConflict(reader, "使用导入的语言？")
+		}
+		if overwrite {
+			if err := stateManager.SetLocale(cwd, snapshot.Locale); err != nil {
+				return err
+			}
+			fmt.Printf("✓ 语言已设置为: %s\n", snapshot.Locale)
+		}
+	}
+
+	fmt.Printf("\n✅ 导入完成: %d 个技能已导入，%d 个无变化，%d 个跳过\n", len(imported), len(unchanged), len(skipped))
+	if len(imported) > 0 {
+		fmt.Println("使用 'skill-hub apply' 将技能应用到当前项目")
+	}
+	setResult(map[string][]string{"imported": imported, "unchanged": unchanged, "skipped": skipped})
+	return nil
+}
+
+// printSnapshotDiff 显示已启用技能与导入配置的差异
+func printSnapshotDiff(current spec.SkillVars, imported state.SnapshotSkill) {
+	if current.Version != imported.Version {
+		fmt.Printf("  版本: %s -> %s\n", current.Version, imported.Version)
+	}
+	names := make(map[string]bool)
+	for name := range current.Variables {
+		names[name] = true
+	}
+	for name := range imported.Variables {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		old, hadOld := current.Variables[name]
+		value, hasNew := imported.Variables[name]
+		if hadOld == hasNew && old == value {
+			continue
+		}
+		fmt.Printf("  %s: %q -> %q\n", name, old, value)
+	}
+}
+
+// resolveStateConflict 按 --on-conflict 策略决定是否覆盖，ask 时询问用户
+func resolveStateConflict(reader *bufio.Reader, question string) bool {
+	switch stateImportConflict {
+	case conflictOverwrite:
+		return true
+	case conflictSkip:
+		return false
+	}
+	fmt.Printf("%s [y/N]: ", question)
+	response, _ := reader.ReadString('\n')
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
+func runStatePrune() error {
+	stateManager, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+
+	projectPath := ""
+	if !statePruneAll {
+		if projectPath, err = os.Getwd(); err != nil {
+			return fmt.Errorf("获取当前目录失败: %w", err)
+		}
+	}
+
+	dangling, err := stateManager.FindDanglingSkills(projectPath, skillManager.SkillExists)
+	if err != nil {
+		return err
+	}
+	if len(dangling) == 0 {
+		fmt.Println("✓ 项目状态中没有技能仓库已不存在的技能")
+		setResult(map[string][]state.DanglingSkill{"pruned": {}})
+		return nil
+	}
+
+	fmt.Println("以下技能在技能仓库中已不存在:")
+	applied := false
+	for _, item := range dangling {
+		line := fmt.Sprintf("  - %s", item.SkillID)
+		if statePruneAll {
+			line += fmt.Sprintf(" (%s)", item.Project)
+		}
+		if len(item.Targets) > 0 {
+			line += fmt.Sprintf("  已应用到: %s", strings.Join(item.Targets, ", "))
+			applied = true
+		}
+		fmt.Println(line)
+	}
+
+	if statePruneDryRun {
+		fmt.Println("\nℹ️  预览模式，未修改状态")
+		setResult(map[string][]state.DanglingSkill{"dangling": dangling})
+		return nil
+	}
+
+	if err := stateManager.PruneSkills(dangling); err != nil {
+		return fmt.Errorf("清理项目状态失败: %w", err)
+	}
+	fmt.Printf("\n✓ 已从项目状态中删除 %d 个技能引用\n", len(dangling))
+	if applied {
+		fmt.Println("⚠️  已应用的技能内容仍保留在目标工具的配置文件中，")
+		fmt.Println("   可在项目中运行 'skill-hub remove <skill-id> --target <target>' 清理")
+	}
+	setResult(map[string][]state.DanglingSkill{"pruned": dangling})
+	return nil
+}
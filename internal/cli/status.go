@@ -1,9 +1,9 @@
 package cli
 
 import (
-	"crypto/sha256"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,12 +20,16 @@ import (
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "检查项目内技能状态",
-	Long:  "对比项目内配置文件与技能仓库的差异，检测是否有手动修改。",
+	Long:  "对比项目内配置文件与技能仓库的差异，检测是否有手动修改，并按目标工具估算技能合计的token占用（预算由配置项 token_budgets 设置）。使用 --project 检查指定目录的项目。",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runStatus()
 	},
 }
 
+func init() {
+	addProjectFlag(statusCmd)
+}
+
 func runStatus() error {
 	fmt.Println("检查项目技能状态...")
 
@@ -49,15 +53,25 @@ func runStatus() error {
 
 	// 显示项目信息
 	fmt.Printf("项目路径: %s\n", cwd)
-	if projectState != nil && projectState.PreferredTarget != "" {
-		normalizedTarget := spec.NormalizeTarget(projectState.PreferredTarget)
-		targetName := "Cursor"
-		if normalizedTarget == spec.TargetClaudeCode {
-			targetName = "Claude Code"
-		} else if normalizedTarget == spec.TargetOpenCode {
-			targetName = "OpenCode"
+	if state.HasLocalState(cwd) {
+		fmt.Printf("项目状态: %s（项目内，可提交到版本库）\n", state.LocalStateFile)
+	}
+	var boundTargets []string
+	if projectState != nil {
+		boundTargets = projectState.BoundTargets()
+	}
+	if len(boundTargets) > 0 {
+		targetNames := make([]string, 0, len(boundTargets))
+		for _, boundTarget := range boundTargets {
+			targetName := "Cursor"
+			if boundTarget == spec.TargetClaudeCode {
+				targetName = "Claude Code"
+			} else if boundTarget == spec.TargetOpenCode {
+				targetName = "OpenCode"
+			}
+			targetNames = append(targetNames, targetName)
 		}
-		fmt.Printf("Context Detected: %s | Project: %s\n", targetName, cwd)
+		fmt.Printf("Context Detected: %s | Project: %s\n", strings.Join(targetNames, ", "), cwd)
 	} else {
 		fmt.Println("Context Detected: Unknown | Project: (未绑定)")
 	}
@@ -82,7 +96,7 @@ func runStatus() error {
 	}
 
 	// 如果没有preferred_target，检查所有适配器
-	if projectState == nil || projectState.PreferredTarget == "" {
+	if len(boundTargets) == 0 {
 		// 检查所有适配器
 		adapters = []struct {
 			name     string
@@ -94,31 +108,27 @@ func runStatus() error {
 			{"Claude", claude.NewClaudeAdapter().WithGlobalMode(), "", "global"},
 			{"OpenCode", opencode.NewOpenCodeAdapter().WithGlobalMode(), "", "global"},
 		}
-	} else {
-		// 根据preferred_target检查对应的适配器
-		normalizedTarget := spec.NormalizeTarget(projectState.PreferredTarget)
-		switch normalizedTarget {
+	}
+	// 根据绑定的目标依次检查对应的适配器
+	for _, boundTarget := range boundTargets {
+		switch boundTarget {
 		case spec.TargetCursor:
-			adapters = []struct {
+			adapters = append(adapters, struct {
 				name     string
 				adapter  adapter.Adapter
 				filePath string
 				mode     string
-			}{
-				{"Cursor", cursor.NewCursorAdapter().WithGlobalMode(), "", "global"},
-			}
+			}{"Cursor", cursor.NewCursorAdapter().WithGlobalMode(), "", "global"})
 		case spec.TargetClaudeCode:
-			adapters = []struct {
+			adapters = append(adapters, struct {
 				name     string
 				adapter  adapter.Adapter
 				filePath string
 				mode     string
-			}{
-				{"Claude", claude.NewClaudeAdapter().WithGlobalMode(), "", "global"},
-			}
+			}{"Claude", claude.NewClaudeAdapter().WithGlobalMode(), "", "global"})
 		case spec.TargetOpenCode:
 			// 对于OpenCode，同时检查项目级和全局级
-			adapters = []struct {
+			adapters = append(adapters, []struct {
 				name     string
 				adapter  adapter.Adapter
 				filePath string
@@ -126,19 +136,7 @@ func runStatus() error {
 			}{
 				{"OpenCode (项目)", opencode.NewOpenCodeAdapter().WithProjectMode(), "", "project"},
 				{"OpenCode (全局)", opencode.NewOpenCodeAdapter().WithGlobalMode(), "", "global"},
-			}
-		default:
-			// 未知目标，检查所有适配器
-			adapters = []struct {
-				name     string
-				adapter  adapter.Adapter
-				filePath string
-				mode     string
-			}{
-				{"Cursor", cursor.NewCursorAdapter().WithGlobalMode(), "", "global"},
-				{"Claude", claude.NewClaudeAdapter().WithGlobalMode(), "", "global"},
-				{"OpenCode", opencode.NewOpenCodeAdapter().WithGlobalMode(), "", "global"},
-			}
+			}...)
 		}
 	}
 
@@ -170,6 +168,20 @@ func runStatus() error {
 	if err != nil {
 		return err
 	}
+	useProjectLocale(skillManager, projectState)
+
+	// 技能仓库中已不存在的技能无法校验，提示清理
+	var danglingSkills []string
+	for skillID := range skills {
+		if !skillManager.SkillExists(skillID) {
+			danglingSkills = append(danglingSkills, skillID)
+		}
+	}
+	if len(danglingSkills) > 0 {
+		sort.Strings(danglingSkills)
+		fmt.Printf("⚠️  以下技能在技能仓库中已不存在: %s\n", strings.Join(danglingSkills, ", "))
+		fmt.Println("   使用 'skill-hub remove <skill-id>' 或 'skill-hub state prune' 清理")
+	}
 
 	allModifiedSkills := make(map[string][]string) // adapter -> skillIDs
 	allSyncedSkills := make(map[string][]string)   // adapter -> skillIDs
@@ -230,23 +242,20 @@ func runStatus() error {
 				continue
 			}
 
-			// 从仓库获取原始内容
-			originalPrompt, err := skillManager.GetSkillPrompt(skillID)
-			if err != nil {
-				continue
-			}
-
-			// 渲染原始内容（使用项目变量）
-			renderedOriginal, err := renderTemplate(originalPrompt, skillVars.Variables)
-			if err != nil {
-				continue
+			// 与最近一次应用的内容比较；没有应用记录时渲染仓库中的原始内容（使用项目变量）
+			originalHash := ""
+			if record, ok := skillVars.Applied[getAdapterTarget(adpt)]; ok {
+				originalHash = record.Hash
+			} else {
+				renderedOriginal, err := skillManager.RenderSkill(skillID, skillVars.Variables, getAdapterTarget(adpt), adapterInfo.mode)
+				if err != nil {
+					fmt.Printf("⚠️  %v\n", err)
+					continue
+				}
+				originalHash = state.ContentHash(renderedOriginal)
 			}
 
-			// 计算哈希值进行比较
-			fileHash := sha256.Sum256([]byte(strings.TrimSpace(fileContent)))
-			originalHash := sha256.Sum256([]byte(strings.TrimSpace(renderedOriginal)))
-
-			if fileHash == originalHash {
+			if state.ContentHash(fileContent) == originalHash {
 				syncedSkills = append(syncedSkills, skillID)
 			} else {
 				modifiedSkills = append(modifiedSkills, skillID)
@@ -259,10 +268,54 @@ func runStatus() error {
 		}
 	}
 
+	type adapterStatus struct {
+		Adapter  string   `json:"adapter"`
+		Synced   []string `json:"synced"`
+		Modified []string `json:"modified"`
+	}
+	result := struct {
+		Project    string                `json:"project"`
+		Target     string                `json:"target,omitempty"`
+		LocalState bool                  `json:"local_state"`
+		Skills     []string              `json:"skills"`
+		Status     []adapterStatus       `json:"status"`
+		Budgets    []*engine.TokenBudget `json:"budgets"`
+		// 技能实际应用到的目标：技能ID -> 目标 -> 应用记录
+		Applied map[string]map[string]spec.AppliedRecord `json:"applied"`
+		// 最近一次 apply 的结果
+		LastApply *spec.ApplyReport `json:"last_apply,omitempty"`
+	}{Project: cwd, LocalState: state.HasLocalState(cwd), Status: []adapterStatus{}, Applied: map[string]map[string]spec.AppliedRecord{}}
+	if projectState != nil {
+		result.Target = spec.NormalizeTarget(projectState.PreferredTarget)
+		result.LastApply = projectState.LastApply
+	}
+	result.Budgets = estimateProjectBudgets(skillManager, skills, result.Target)
+	for skillID, skillVars := range skills {
+		result.Skills = append(result.Skills, skillID)
+		if len(skillVars.Applied) > 0 {
+			result.Applied[skillID] = skillVars.Applied
+		}
+	}
+	sort.Strings(result.Skills)
+	for _, adapterInfo := range adapters {
+		if synced, ok := allSyncedSkills[adapterInfo.name]; ok {
+			result.Status = append(result.Status, adapterStatus{
+				Adapter:  adapterInfo.name,
+				Synced:   synced,
+				Modified: allModifiedSkills[adapterInfo.name],
+			})
+		}
+	}
+	setResult(result)
+
+	adapterTargets := make(map[string]string, len(adapters))
+	for _, adapterInfo := range adapters {
+		adapterTargets[adapterInfo.name] = getAdapterTarget(adapterInfo.adapter)
+	}
+
 	// 显示结果
 	fmt.Println("\n=== 技能状态汇总 ===")
 
-	currentTime := time.Now().Format("15:04")
 	hasAnySkills := false
 
 	for adapterName, syncedSkills := range allSyncedSkills {
@@ -274,15 +327,15 @@ func runStatus() error {
 
 		hasAnySkills = true
 		fmt.Printf("\n%s:\n", adapterName)
-		fmt.Println("ID          状态      最后检查")
-		fmt.Println("----------------------------------")
+		fmt.Println("ID          状态      最后应用")
+		fmt.Println("----------------------------------------")
 
 		for _, skillID := range syncedSkills {
-			fmt.Printf("%-12s ✅ 同步   %s\n", skillID, currentTime)
+			fmt.Printf("%-12s ✅ 同步   %s\n", skillID, lastAppliedTime(skills[skillID], adapterTargets[adapterName]))
 		}
 
 		for _, skillID := range modifiedSkills {
-			fmt.Printf("%-12s ⚠️ 已修改  %s\n", skillID, currentTime)
+			fmt.Printf("%-12s ⚠️ 已修改  %s\n", skillID, lastAppliedTime(skills[skillID], adapterTargets[adapterName]))
 		}
 
 		if len(modifiedSkills) > 0 {
@@ -309,40 +362,138 @@ func runStatus() error {
 		}
 	}
 
+	printAppliedRecords(skills)
+	printLastApply(result.LastApply)
+	printTokenBudgets(result.Budgets)
+
 	fmt.Println("\n如需更新技能，使用 'skill-hub update'")
 
 	return nil
 }
 
-// checkAdapterSupport 检查适配器是否支持该技能
-func checkAdapterSupport(adpt adapter.Adapter, skill *spec.Skill) bool {
-	// 如果没有指定兼容性，假设兼容所有
-	if skill.Compatibility == "" {
-		return true
+// printAppliedRecords 显示每个技能实际应用到的目标工具、版本和时间
+func printAppliedRecords(skills map[string]spec.SkillVars) {
+	skillIDs := make([]string, 0, len(skills))
+	for skillID := range skills {
+		skillIDs = append(skillIDs, skillID)
 	}
+	sort.Strings(skillIDs)
 
-	compatLower := strings.ToLower(skill.Compatibility)
+	fmt.Println("\n=== 应用记录 ===")
+	for _, skillID := range skillIDs {
+		targets := sortedAppliedTargets(skills[skillID])
+		if len(targets) == 0 {
+			fmt.Printf("%-20s 尚未应用\n", skillID)
+			continue
+		}
+		for _, target := range targets {
+			record := skills[skillID].Applied[target]
+			scope := ""
+			if record.AppliedMode() == spec.ModeGlobal {
+				scope = "  (全局配置)"
+			}
+			fmt.Printf("%-20s %-12s v%-8s %s%s\n", skillID, target, record.Version, lastAppliedTime(skills[skillID], target), scope)
+		}
+	}
+}
 
-	// 使用类型断言检查适配器类型
-	if _, ok := adpt.(*cursor.CursorAdapter); ok {
-		return strings.Contains(compatLower, "cursor")
+// printLastApply 显示最近一次 apply 的结果汇总和跳过、失败的技能
+func printLastApply(report *spec.ApplyReport) {
+	if report == nil {
+		return
+	}
+	appliedAt := report.AppliedAt
+	if t, err := time.Parse(time.RFC3339, report.AppliedAt); err == nil {
+		appliedAt = t.Local().Format("2006-01-02 15:04")
 	}
-	if _, ok := adpt.(*claude.ClaudeAdapter); ok {
-		return strings.Contains(compatLower, "claude code") || strings.Contains(compatLower, "claude_code")
+	fmt.Println("\n=== 最近一次应用 ===")
+	fmt.Printf("%s  目标: %s  已应用 %d，已是最新 %d，跳过 %d，失败 %d\n", appliedAt, strings.Join(report.Targets, ", "),
+		report.Count(spec.ApplyApplied), report.Count(spec.ApplyUpToDate), report.Count(spec.ApplySkipped), report.Count(spec.ApplyFailed))
+	for _, result := range report.Skills {
+		if result.Status == spec.ApplySkipped || result.Status == spec.ApplyFailed {
+			fmt.Printf("  %-20s %-12s %-6s %s\n", result.SkillID, result.Target, applyStatusLabels[result.Status], result.Reason)
+		}
 	}
-	if _, ok := adpt.(*opencode.OpenCodeAdapter); ok {
-		return strings.Contains(compatLower, "opencode")
+	for _, file := range report.Files {
+		fmt.Printf("  修改: %s\n", file)
 	}
-	return false
 }
 
-// renderTemplate 渲染模板内容
-func renderTemplate(content string, variables map[string]string) (string, error) {
-	// 简单替换变量
-	result := content
-	for key, value := range variables {
-		placeholder := "{{." + key + "}}"
-		result = strings.ReplaceAll(result, placeholder, value)
+// lastAppliedTime 返回技能最近一次应用到目标的本地时间，没有应用记录时返回 "-"
+func lastAppliedTime(skillVars spec.SkillVars, target string) string {
+	record, ok := skillVars.Applied[target]
+	if !ok {
+		return "-"
+	}
+	appliedAt, err := time.Parse(time.RFC3339, record.AppliedAt)
+	if err != nil {
+		return record.AppliedAt
+	}
+	return appliedAt.Local().Format("2006-01-02 15:04")
+}
+
+// estimateProjectBudgets 估算项目启用的技能在各目标工具上合计的token
+//
+// 只包含项目的首选目标和技能已应用过的目标中设置了预算的目标工具。
+func estimateProjectBudgets(skillManager *engine.SkillManager, skills map[string]spec.SkillVars, preferred string) []*engine.TokenBudget {
+	targets := map[string]bool{preferred: true}
+	for _, skillVars := range skills {
+		for target := range skillVars.History {
+			targets[target] = true
+		}
+	}
+
+	budgets := []*engine.TokenBudget{}
+	for _, adpt := range selectAdapters(spec.TargetAll, "project") {
+		adapterTarget := getAdapterTarget(adpt)
+		budget := engine.TokenBudgetFor(adapterTarget)
+		if !targets[adapterTarget] || budget <= 0 {
+			continue
+		}
+
+		rendered := make(map[string]string)
+		for skillID, skillVars := range skills {
+			skill, err := skillManager.LoadSkill(skillID)
+			if err != nil || !adapterSupportsSkill(adpt, skill) {
+				continue
+			}
+			content, err := skillManager.RenderSkill(skillID, skillVars.Variables, adapterTarget, "project")
+			if err != nil {
+				continue
+			}
+			rendered[skillID] = content
+		}
+		if len(rendered) > 0 {
+			budgets = append(budgets, engine.EstimateTokenBudget(adapterTarget, rendered, budget))
+		}
+	}
+	return budgets
+}
+
+// printTokenBudgets 显示各目标工具的token预算和每个技能的占用
+func printTokenBudgets(budgets []*engine.TokenBudget) {
+	if len(budgets) == 0 {
+		return
+	}
+
+	fmt.Println("\n=== Token 预算 ===")
+	for _, budget := range budgets {
+		mark := "✅"
+		if budget.Exceeded() {
+			mark = "⚠️ 超出预算"
+		}
+		fmt.Printf("\n%s: 约 %d / %d tokens %s\n", budget.Target, budget.Total, budget.Budget, mark)
+		for _, skill := range budget.Skills {
+			fmt.Printf("  %-24s %6d  %5.1f%%\n", skill.SkillID, skill.Tokens, float64(skill.Tokens)*100/float64(budget.Budget))
+		}
+	}
+}
+
+// checkAdapterSupport 检查适配器是否支持该技能
+func checkAdapterSupport(adpt adapter.Adapter, skill *spec.Skill) bool {
+	// 如果没有指定兼容性，假设兼容所有
+	if skill.Compatibility == "" {
+		return true
 	}
-	return result, nil
+	return skill.Compatible().Supports(getAdapterTarget(adpt))
 }
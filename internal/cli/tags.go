@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/engine"
+	"skill-hub/pkg/spec"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "列出技能仓库中使用的标签",
+	Long: `汇总所有技能的标签及使用次数，按使用次数从多到少排列。
+
+标签比较时忽略大小写，空格和下划线视为连字符，例如 "Code Review" 与 code-review 是同一个标签。
+使用 'skill-hub list --tag <标签>' 列出带有该标签的技能。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTags()
+	},
+}
+
+func runTags() error {
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	skills, err := manager.LoadAllSkills()
+	if err != nil {
+		return err
+	}
+
+	counts := spec.CountTags(skills)
+	setResult(counts)
+	if len(counts) == 0 {
+		fmt.Println("ℹ️  技能仓库中的技能没有标签")
+		return nil
+	}
+
+	fmt.Printf("%-24s %-6s %s\n", "标签", "技能数", "技能")
+	for _, count := range counts {
+		fmt.Printf("%-24s %-6d %s\n", count.Tag, count.Count, strings.Join(count.Skills, ", "))
+	}
+	return nil
+}
+
+// filterSkillsByTags 返回带有所有指定标签的技能，未指定标签时返回全部技能
+func filterSkillsByTags(skills []*spec.Skill, tags []string) []*spec.Skill {
+	if len(tags) == 0 {
+		return skills
+	}
+	var filtered []*spec.Skill
+	for _, skill := range skills {
+		if skill.HasTags(tags) {
+			filtered = append(filtered, skill)
+		}
+	}
+	return filtered
+}
+
+// completeTags 补全技能仓库中使用的标签
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	skills, err := manager.LoadAllSkills()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, count := range spec.CountTags(skills) {
+		completions = append(completions, fmt.Sprintf("%s\t%d 个技能", count.Tag, count.Count))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"skill-hub/internal/engine"
+
+	"github.com/spf13/cobra"
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [skill-id|dir...]",
+	Short: "运行技能的渲染测试",
+	Long: `按技能目录中 tests/*.yaml 描述的变量渲染技能，检查输出是否符合预期，在发布前发现损坏的模板。
+
+参数可以是技能ID或技能目录（可以尚未导入技能仓库）；不指定时测试技能仓库中所有带测试的技能。
+
+测试文件可以是单个用例，也可以在 cases 中列出多个用例：
+  cases:
+    - name: 中文输出
+      target: cursor          # 可选，默认为技能兼容的第一个目标
+      mode: project           # 可选
+      locale: zh-CN           # 可选，使用 SKILL.<语言>.md
+      variables:
+        LANGUAGE: zh
+      contains: ["使用 zh 回答"]
+      not_contains: ["{{"]
+      matches: ["(?m)^# Git"]
+    - name: 缺少变量
+      error: LANGUAGE         # 期望渲染失败，错误信息包含该文本
+
+tool 模式的技能可以用 input 给出示例输入，按 claude.tool_spec.input_schema 校验而不渲染提示词，
+input_schema 本身不是有效的 JSON Schema (draft 2020-12) 时用例失败：
+  cases:
+    - input: {path: main.go}
+    - input: {level: 1}
+      error: 缺少必需字段      # 期望输入不符合 input_schema`,
+	ValidArgsFunction: completeSkillIDList,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTest(args)
+	},
+}
+
+// skillTestTarget 待测试的技能
+type skillTestTarget struct {
+	id  string
+	dir string
+}
+
+func runTest(args []string) error {
+	targets, err := resolveTestTargets(args)
+	if err != nil {
+		return err
+	}
+
+	var results []engine.SkillTestResult
+	passed, failed, tested := 0, 0, 0
+	for _, t := range targets {
+		skillResults, err := engine.RunSkillTests(t.dir, t.id)
+		if err != nil {
+			return fmt.Errorf("测试技能 %s 失败: %w", t.id, err)
+		}
+		if len(skillResults) == 0 {
+			if len(args) > 0 {
+				fmt.Printf("ℹ️  技能 %s 没有测试（%s/*.yaml）\n", t.id, engine.SkillTestsDir)
+			}
+			continue
+		}
+
+		tested++
+		fmt.Printf("\n🔍 %s\n", t.id)
+		for _, result := range skillResults {
+			if result.Passed {
+				passed++
+				fmt.Printf("  ✓ %s\n", result.Case.Name)
+				continue
+			}
+			failed++
+			fmt.Printf("  ❌ %s (%s)\n", result.Case.Name, result.Case.File)
+			for _, failure := range result.Failures {
+				fmt.Printf("     - %s\n", failure)
+			}
+			debugf("%s 的渲染结果:\n%s", result.Case.Name, result.Output)
+		}
+		results = append(results, skillResults...)
+	}
+
+	setResult(results)
+	if tested == 0 {
+		fmt.Println("ℹ️  没有找到技能测试")
+		return nil
+	}
+	fmt.Printf("\n共 %d 个技能 %d 个用例: %d 通过, %d 失败\n", tested, passed+failed, passed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d 个测试用例失败", failed)
+	}
+	return nil
+}
+
+// resolveTestTargets 将参数解析为待测试的技能，未指定时返回技能仓库中的所有技能
+func resolveTestTargets(args []string) ([]skillTestTarget, error) {
+	manager, err := engine.NewSkillManager()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 0 {
+		skills, err := manager.LoadAllSkills()
+		if err != nil {
+			return nil, err
+		}
+		var targets []skillTestTarget
+		for _, skill := range skills {
+			targets = append(targets, skillTestTarget{id: skill.ID, dir: manager.GetSkillDir(skill.ID)})
+		}
+		return targets, nil
+	}
+
+	var targets []skillTestTarget
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			if !hasSkillMD(arg) {
+				return nil, fmt.Errorf("目录 %s 中没有SKILL.md", arg)
+			}
+			id, _, err := importSkillID(arg, "")
+			if err != nil {
+				return nil, err
+			}
+			abs, err := filepath.Abs(arg)
+			if err != nil {
+				return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+			}
+			targets = append(targets, skillTestTarget{id: id, dir: abs})
+			continue
+		}
+		if !manager.SkillExists(arg) {
+			return nil, fmt.Errorf("'%s' 既不是技能目录也不是技能仓库中的技能", arg)
+		}
+		targets = append(targets, skillTestTarget{id: arg, dir: manager.GetSkillDir(arg)})
+	}
+	return targets, nil
+}
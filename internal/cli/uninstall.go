@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"skill-hub/internal/config"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var uninstallForce bool
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall <skill-id>",
+	Short: "从本地技能仓库删除技能",
+	Long: `从本地技能仓库中删除技能目录并刷新技能索引。
+
+删除前会检查状态文件中哪些项目仍启用了该技能，
+并询问是否先从这些项目中移除（包括清理目标工具中的技能内容）。
+使用 --force 跳过检查直接删除。
+
+注意：如果技能仓库关联了远程仓库，删除只影响本地，需自行提交推送。`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSkillIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUninstall(args[0])
+	},
+}
+
+func init() {
+	uninstallCmd.Flags().BoolVar(&uninstallForce, "force", false, "跳过项目引用检查，直接删除")
+}
+
+func runUninstall(skillID string) error {
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	if !skillManager.SkillExists(skillID) {
+		return fmt.Errorf("技能 '%s' 不存在，使用 'skill-hub list' 查看可用技能", skillID)
+	}
+
+	skillsDir, err := engine.GetSkillsDir()
+	if err != nil {
+		return fmt.Errorf("获取技能目录失败: %w", err)
+	}
+	skillDir := filepath.Join(skillsDir, skillID)
+
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	projects, err := stateMgr.FindProjectsUsingSkill(skillID)
+	if err != nil {
+		return fmt.Errorf("检查项目引用失败: %w", err)
+	}
+
+	if len(projects) > 0 {
+		if uninstallForce {
+			fmt.Printf("⚠️  以下项目仍启用了技能 %s，状态记录将保留:\n", skillID)
+			for _, p := range projects {
+				fmt.Printf("   - %s\n", p)
+			}
+		} else {
+			fmt.Printf("⚠️  以下项目仍启用了技能 %s:\n", skillID)
+			for _, p := range projects {
+				fmt.Printf("   - %s\n", p)
+			}
+			fmt.Print("\n是否先从这些项目中移除该技能？ [y/N]: ")
+
+			reader := newInputReader()
+			response, _ := reader.ReadString('\n')
+			response = strings.ToLower(strings.TrimSpace(response))
+			if response != "y" && response != "yes" {
+				fmt.Println("❌ 操作已取消")
+				fmt.Println("使用 --force 跳过检查直接删除")
+				return nil
+			}
+
+			for _, p := range projects {
+				if err := removeSkillFromProject(stateMgr, p, skillID); err != nil {
+					return fmt.Errorf("从项目 %s 移除技能失败: %w", p, err)
+				}
+			}
+		}
+	}
+
+	if err := os.RemoveAll(skillDir); err != nil {
+		return fmt.Errorf("删除技能目录失败: %w", err)
+	}
+	// 命名空间中已没有其他技能时一并删除命名空间目录
+	if namespace, _ := spec.SplitSkillID(skillID); namespace != "" {
+		os.Remove(filepath.Dir(skillDir))
+	}
+	fmt.Printf("✓ 已删除技能目录: %s\n", skillDir)
+
+	repoDir, err := config.GetRepoPath()
+	if err != nil {
+		return err
+	}
+	if err := refreshSkillRegistry(repoDir); err != nil {
+		fmt.Printf("⚠️  刷新技能索引失败: %v\n", err)
+	}
+
+	recordAudit(state.AuditEntry{
+		Operation: state.OpUninstall,
+		SkillID:   skillID,
+	})
+
+	fmt.Printf("\n✅ 技能 %s 已从本地仓库删除\n", skillID)
+	return nil
+}
+
+// removeSkillFromProject 在指定项目中执行 remove，项目目录已不存在时只清理状态记录
+func removeSkillFromProject(stateMgr *state.StateManager, projectPath, skillID string) error {
+	fmt.Printf("\n--- 项目: %s ---\n", projectPath)
+
+	if info, err := os.Stat(projectPath); err != nil || !info.IsDir() {
+		fmt.Println("ℹ️  项目目录不存在，仅清理状态记录")
+		return stateMgr.RemoveSkillFromProject(projectPath, skillID)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	if err := os.Chdir(projectPath); err != nil {
+		return fmt.Errorf("切换到项目目录失败: %w", err)
+	}
+	defer os.Chdir(cwd)
+
+	// 用户已确认移除，跳过本地修改检查
+	savedForce := forceRemove
+	forceRemove = true
+	defer func() { forceRemove = savedForce }()
+
+	return runRemove(skillID)
+}
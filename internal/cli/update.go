@@ -1,63 +1,774 @@
 package cli
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"skill-hub/internal/config"
+	"skill-hub/internal/engine"
 	"skill-hub/internal/git"
+	"skill-hub/internal/pack"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/skillhub"
+)
+
+var (
+	updateJobs     int
+	updateApply    bool
+	updateExclude  []string
+	updateYes      bool
+	updateChannel  string
+	updateRollback bool
 )
 
 var updateCmd = &cobra.Command{
-	Use:   "update",
+	Use:   "update [skill-id...]",
 	Short: "更新技能仓库",
-	Long:  "从远程仓库拉取最新技能，并提示更新受影响的项目。",
+	Long: `从远程仓库拉取最新技能（配置了 registry_url 时从HTTP技能注册表下载，不需要git），比较同步前后各技能的版本和内容摘要，
+列出新增、升级、内容有变化和已删除的技能，显示版本变化的技能在 CHANGELOG.md 中的更新说明。
+
+随后分析启用了这些技能的项目：预览重新应用后各目标工具配置文件的差异，
+在终端中逐个项目确认是否重新应用（输入 a 应用到其余全部项目），使用 --apply 时不确认直接重新应用到
+状态中记录的所有项目及其绑定的目标工具。用 'skill-hub pin' 固定了版本的技能保持固定版本，
+最后输出各项目的汇总表（有变化的技能、目标工具、固定版本和处理结果）。
+
+同步前后的技能版本都会安装到 ~/.skill-hub/versions，项目固定的旧版本在升级后仍可应用，
+使用 'skill-hub gc' 清理不再使用的版本。
+
+从注册表更新时，index.json 和技能归档缓存在 ~/.skill-hub/cache/registry，
+使用 --offline 时只使用缓存，不访问网络；registry_mirrors 中的镜像在主地址不可用时按顺序尝试。
+有变化的技能并行下载（--jobs 设置并行数），显示每个技能的状态、下载字节数和预计剩余时间。
+
+每次有技能变化的更新都会在 ~/.skill-hub/rollback 中保存更新前的技能仓库（Git提交或技能目录的副本），
+新版本有问题时使用 --rollback 恢复到上次更新前的状态，并像更新一样重新应用到启用了这些技能的项目。
+
+指定技能ID时只更新这些技能，--exclude 排除指定的技能，其余技能保持当前版本。
+只更新部分技能时在终端中逐个技能确认（输入 a 更新其余全部技能），使用 --yes 时不确认。
+使用全局参数 --no-input 或 --json 时不询问：更新所有选择的技能，受影响的项目只列出（使用 --apply 时重新应用），
+--json 的结果包含有变化的技能、保持当前版本的技能和各项目的处理结果。
+从Git仓库更新时，保持当前版本的技能记录在 ~/.skill-hub/held.json，下次更新时重新询问。
+
+技能在 metadata.channel 中声明发布通道（stable 或 beta，未声明时为 stable）。
+配置文件中的 channel 设置技能仓库订阅的通道，skill_channels 按技能设置，未设置时为 stable；
+update 只更新到订阅通道发布的版本，stable 通道不接受 beta 版本，--channel beta 本次更新接受 beta 版本：
+  channel: stable
+  skill_channels:
+    code-review: beta
+
+示例:
+  skill-hub update                          # 更新全部技能
+  skill-hub update git-commit code-review   # 只更新指定的技能
+  skill-hub update --exclude legacy-skill   # 更新除 legacy-skill 以外的技能
+  skill-hub update --channel beta           # 接受 beta 通道发布的版本
+  skill-hub update --rollback               # 撤销上次更新`,
+	ValidArgsFunction: completeSkillIDList,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runUpdate()
+		if updateJobs < 1 {
+			return fmt.Errorf("--jobs 必须大于0")
+		}
+		if updateRollback {
+			if len(args) > 0 || len(updateExclude) > 0 || updateChannel != "" {
+				return fmt.Errorf("--rollback 不能与技能ID、--exclude 或 --channel 同时使用")
+			}
+			return runUpdateRollback()
+		}
+		return runUpdate(cmd.Context(), args)
 	},
 }
 
-func runUpdate() error {
+func init() {
+	updateCmd.Flags().IntVarP(&updateJobs, "jobs", "j", defaultJobs, "从技能注册表并行下载的技能数")
+	updateCmd.Flags().BoolVar(&updateApply, "apply", false, "不逐个确认，直接重新应用到所有受影响的项目")
+	updateCmd.Flags().StringArrayVar(&updateExclude, "exclude", nil, "不更新指定的技能，保持当前版本（可多次指定）")
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "只更新部分技能时不逐个确认")
+	updateCmd.Flags().BoolVar(&updateRollback, "rollback", false, "将技能仓库恢复到上次更新前的状态，并重新应用到受影响的项目")
+	updateCmd.Flags().StringVar(&updateChannel, "channel", "", "本次更新使用的发布通道（stable 或 beta），覆盖配置中的 channel 和 skill_channels")
+	addWordDiffFlag(updateCmd)
+	updateCmd.RegisterFlagCompletionFunc("exclude", completeSkillIDFlag)
+	updateCmd.RegisterFlagCompletionFunc("channel", fixedCompletions(channelCompletions...))
+}
+
+func runUpdate(ctx context.Context, ids []string) error {
+	selection, err := newUpdateSelection(ids, updateExclude, updateChannel)
+	if err != nil {
+		return err
+	}
+	if updateChannel == "" {
+		if err := validateChannelConfig(); err != nil {
+			return err
+		}
+	}
 	fmt.Println("正在更新技能仓库...")
 
-	// 使用Git同步
-	repo, err := git.NewSkillRepository()
+	// 记录同步前的技能版本和内容摘要，用于列出有变化的技能和显示更新日志
+	before := skillSnapshots()
+
+	// 同步前安装当前版本，新版本与旧版本并存，项目固定的旧版本在升级后仍可应用
+	installed := installSkillVersions()
+
+	// 配置了HTTP技能注册表时从注册表下载，否则使用Git同步
+	client, err := newRegistryClient()
 	if err != nil {
 		return err
 	}
 
+	// 保存更新前的状态，更新有技能变化时作为 'update --rollback' 的回滚点
+	point, err := prepareUpdateRollback(before, client == nil && !isOffline())
+	if err != nil {
+		return err
+	}
+	defer point.discard()
+
+	var count int
+	var held []string
+	if client != nil {
+		count, held, err = updateFromRegistry(ctx, client, selection)
+	} else {
+		count, held, err = updateFromGit(selection, before)
+	}
+	if err != nil {
+		return err
+	}
+
+	after := skillSnapshots()
+	for _, id := range selection.missing(func(id string) bool {
+		_, ok := after[id]
+		return ok
+	}) {
+		fmt.Printf("⚠️  技能仓库中没有技能 %s\n", id)
+	}
+
+	installed += installSkillVersions()
+
+	fmt.Printf("\n✅ 技能仓库更新完成，共 %d 个技能\n", count)
+	if installed > 0 {
+		fmt.Printf("📦 新安装 %d 个技能版本（使用 'skill-hub gc' 清理不再使用的旧版本）\n", installed)
+	}
+	recordAudit(state.AuditEntry{
+		Operation: state.OpUpdate,
+		Detail:    fmt.Sprintf("同步技能仓库，共 %d 个技能", count),
+	})
+
+	updated := printSkillChangelogs(before)
+	if len(updated) > 0 {
+		if err := point.commit(updated); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+	}
+	if len(held) > 0 {
+		fmt.Printf("\n⏸️  %d 个有变化的技能保持当前版本: %s\n", len(held), strings.Join(held, ", "))
+	}
+
+	// 已固定的技能不随仓库更新
+	if cwd, err := os.Getwd(); err == nil {
+		reportPinnedSkills(cwd)
+	}
+
+	clearUpdateCheck()
+
+	result := updateResult{Skills: count, Updated: updated, Held: held}
+	if result.Updated == nil {
+		result.Updated = []string{}
+	}
+	result.Projects = reportUpdateImpact(updated)
+	setResult(result)
+	return nil
+}
+
+// updateFromGit 从远程Git仓库同步技能仓库并校验内容摘要，返回技能数和保持当前版本的技能
+//
+// 同步前保存技能目录的副本，同步后将未选择的技能和新版本不在订阅通道中的技能恢复为原来的内容。
+// 上次保持当前版本的技能先恢复为当前提交中的内容，使拉取不会因工作树中的修改而失败。
+func updateFromGit(selection *updateSelection, before map[string]skillSnapshot) (int, []string, error) {
+	repo, err := git.NewSkillRepository()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// 离线模式下不拉取远程仓库，只统计本地技能
+	if isOffline() {
+		fmt.Println("ℹ️  离线模式，跳过同步远程仓库")
+		skills, err := repo.ListSkills()
+		if err != nil {
+			return 0, nil, fmt.Errorf("获取技能列表失败: %w", err)
+		}
+		return len(skills), nil, nil
+	}
+
+	repoDir, err := config.GetRepoPath()
+	if err != nil {
+		return 0, nil, err
+	}
+	skillsDir, err := engine.GetSkillsDir()
+	if err != nil {
+		return 0, nil, fmt.Errorf("获取技能目录失败: %w", err)
+	}
+	held, err := loadHeldSkills()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// 保存技能目录的副本，用于恢复不更新的技能，同步失败时恢复保持当前版本的技能
+	tmpDir, err := os.MkdirTemp("", "skill-hub-update-")
+	if err != nil {
+		return 0, nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	snapshotDir := filepath.Join(tmpDir, "skills")
+	if err := copyDir(skillsDir, snapshotDir); err != nil {
+		return 0, nil, fmt.Errorf("备份技能目录失败: %w", err)
+	}
+	restoreHeld := func() {
+		for _, id := range held {
+			if err := restoreSkillDir(skillsDir, snapshotDir, id); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+			}
+		}
+	}
+	if len(held) > 0 {
+		if err := repo.ResetSkills(held); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	// 记录同步前的提交，内容校验失败时回退
+	head, _ := repo.Head()
+	// registry.json 纳入版本控制时是远程仓库发布的内容摘要，否则是本地生成的索引，同步后重新生成
+	published, err := repo.Tracked("registry.json")
+	if err != nil {
+		restoreHeld()
+		return 0, nil, err
+	}
+
 	if err := repo.Sync(); err != nil {
-		return fmt.Errorf("同步技能仓库失败: %w", err)
+		restoreHeld()
+		return 0, nil, fmt.Errorf("同步技能仓库失败: %w", err)
+	}
+	if published {
+		if err := verifySyncedSkills(repo, head); err != nil {
+			restoreHeld()
+			return 0, nil, err
+		}
+	}
+
+	// 未选择、未确认更新或新版本不在订阅通道中的技能恢复为同步前的内容
+	newHeld, err := holdUnselectedSkills(skillsDir, snapshotDir, before, selection)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := saveHeldSkills(newHeld); err != nil {
+		return 0, nil, err
 	}
 
 	// 获取更新后的技能列表
-	skills, err := repo.ListSkillsFromRemote()
+	skills, err := repo.ListSkills()
+	if err != nil {
+		return 0, nil, fmt.Errorf("获取技能列表失败: %w", err)
+	}
+	if !published {
+		if err := refreshSkillRegistry(repoDir); err != nil {
+			return 0, nil, fmt.Errorf("刷新技能注册表失败: %w", err)
+		}
+	}
+	return len(skills), newHeld, nil
+}
+
+// verifySyncedSkills 按 registry.json 记录的内容摘要校验同步后的技能，不一致时回退到同步前的提交
+func verifySyncedSkills(repo *git.SkillRepository, head string) error {
+	repoDir, err := config.GetRepoPath()
+	if err != nil {
+		return err
+	}
+	problems, err := verifyRegistryDigests(repoDir)
 	if err != nil {
-		return fmt.Errorf("获取技能列表失败: %w", err)
+		return err
+	}
+	if len(problems) == 0 {
+		return nil
 	}
 
-	fmt.Printf("\n✅ 技能仓库更新完成，共 %d 个技能\n", len(skills))
+	fmt.Printf("\n❌ %d 个技能与 registry.json 记录的内容摘要不一致，可能已损坏或被篡改:\n", len(problems))
+	for _, problem := range problems {
+		fmt.Printf("  - %s\n", problem)
+	}
+	if head != "" {
+		if err := repo.Rollback(head); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		} else {
+			fmt.Printf("ℹ️  已回退到同步前的提交 %s\n", head[:8])
+		}
+	}
+	return fmt.Errorf("技能仓库内容校验失败，拒绝本次更新")
+}
 
-	// 询问是否更新受影响的项目
-	fmt.Print("\n是否更新受影响的项目？ [y/N]: ")
+// installSkillVersions 将技能仓库中各技能的当前版本安装到版本存储，返回新安装的版本数
+func installSkillVersions() int {
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return 0
+	}
+	versions, err := engine.NewVersionStore()
+	if err != nil {
+		return 0
+	}
+	skills, err := skillManager.LoadAllSkills()
+	if err != nil {
+		return 0
+	}
 
-	reader := bufio.NewReader(os.Stdin)
-	response, _ := reader.ReadString('\n')
-	response = strings.TrimSpace(response)
+	installed := 0
+	for _, skill := range skills {
+		ok, err := versions.Install(skill.ID, skill.Version, skillManager.GetSkillDir(skill.ID))
+		if err != nil {
+			debugf("安装技能版本失败: %v", err)
+			continue
+		}
+		if ok {
+			installed++
+		}
+	}
+	return installed
+}
 
-	if response != "y" && response != "Y" {
-		fmt.Println("❌ 取消项目更新")
-		fmt.Println("ℹ️  技能仓库已更新，使用 'skill-hub apply' 手动更新项目")
+// skillVersions 返回技能ID到版本的映射，加载失败时为空
+func skillVersions() map[string]string {
+	versions := make(map[string]string)
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return versions
+	}
+	skills, err := skillManager.LoadAllSkills()
+	if err != nil {
+		return versions
+	}
+	for _, skill := range skills {
+		versions[skill.ID] = skill.Version
+	}
+	return versions
+}
+
+// skillSnapshot 技能在同步前或同步后的版本和内容摘要
+type skillSnapshot struct {
+	Version string
+	Digest  string
+	Channel string
+}
+
+// skillSnapshots 返回技能ID到版本和内容摘要的映射，加载失败时为空
+func skillSnapshots() map[string]skillSnapshot {
+	snapshots := make(map[string]skillSnapshot)
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return snapshots
+	}
+	skills, err := skillManager.LoadAllSkills()
+	if err != nil {
+		return snapshots
+	}
+	for _, skill := range skills {
+		digest, err := pack.SkillDigest(skillManager.GetSkillDir(skill.ID))
+		if err != nil {
+			debugf("计算技能 %s 的内容摘要失败: %v", skill.ID, err)
+		}
+		snapshots[skill.ID] = skillSnapshot{Version: skill.Version, Digest: digest, Channel: skill.Channel}
+	}
+	return snapshots
+}
+
+// skillChanges 同步前后技能的变化，各列表按技能ID排序
+type skillChanges struct {
+	Added    []string // 新增的技能
+	Upgraded []string // 版本变化的技能
+	Modified []string // 内容变化但版本未变的技能
+	Removed  []string // 已删除的技能
+}
+
+// diffSkillSnapshots 比较同步前后的技能，摘要为空（计算失败）时只比较版本
+func diffSkillSnapshots(before, after map[string]skillSnapshot) skillChanges {
+	var changes skillChanges
+	for id, current := range after {
+		previous, ok := before[id]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, id)
+		case previous.Version != current.Version:
+			changes.Upgraded = append(changes.Upgraded, id)
+		case previous.Digest != "" && current.Digest != "" && previous.Digest != current.Digest:
+			changes.Modified = append(changes.Modified, id)
+		}
+	}
+	for id := range before {
+		if _, ok := after[id]; !ok {
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Upgraded)
+	sort.Strings(changes.Modified)
+	sort.Strings(changes.Removed)
+	return changes
+}
+
+// printSkillChangelogs 列出同步后有变化的技能，显示新增和版本变化的技能的更新日志，返回有变化的技能ID
+func printSkillChangelogs(before map[string]skillSnapshot) []string {
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return nil
+	}
+	after := skillSnapshots()
+	changes := diffSkillSnapshots(before, after)
+
+	updated := append(append(append([]string{}, changes.Added...), changes.Upgraded...), changes.Modified...)
+	sort.Strings(updated)
+	if len(updated) == 0 && len(changes.Removed) == 0 {
+		fmt.Println("ℹ️  没有技能变化")
+		return nil
+	}
+
+	fmt.Printf("\n📋 %d 个技能有更新:\n", len(updated)+len(changes.Removed))
+	for _, id := range changes.Removed {
+		fmt.Printf("\n🗑️  %s@%s（已从技能仓库删除）\n", id, before[id].Version)
+	}
+	versions, _ := engine.NewVersionStore()
+	for _, id := range updated {
+		previous, existed := before[id]
+		from, to := previous.Version, after[id].Version
+		if !existed {
+			fmt.Printf("\n🆕 %s@%s（新技能）\n", id, to)
+		} else if from == to {
+			fmt.Printf("\n✏️  %s@%s: 内容有变化，版本未变\n", id, to)
+		} else {
+			fmt.Printf("\n⬆️  %s: %s -> %s\n", id, from, to)
+		}
+
+		// 同步前的版本已安装到版本存储，没有更新日志时比较两个版本的内容
+		fromDir := ""
+		if existed && versions != nil && versions.Has(id, from) {
+			fromDir = versions.Dir(id, from)
+		}
+		printSkillChange(id, from, fromDir, to, skillManager.GetSkillDir(id), "   ")
+	}
+	return append(updated, changes.Removed...)
+}
+
+// affectedProject 启用了有变化技能的项目及重新应用的预览
+type affectedProject struct {
+	Path    string
+	Skills  []string
+	Pinned  []string // 固定在其他版本、重新应用时保持固定版本的技能
+	Changes []skillhub.AppliedSkill
+	Skipped []skillhub.SkippedSkill
+	Status  string // 处理结果，见 impact* 常量
+	project *skillhub.Project
+	options []skillhub.ApplyOptions // 重新应用时的选项，按有变化技能的应用记录分组
+}
+
+// 受影响项目的处理结果
+const (
+	impactPreviewFailed = "preview_failed"
+	impactUnchanged     = "unchanged"
+	impactPending       = "pending"
+	impactSkipped       = "skipped"
+	impactFailed        = "failed"
+	impactApplied       = "applied"
+)
+
+// impactLabels 汇总表中显示的处理结果
+var impactLabels = map[string]string{
+	impactPreviewFailed: "❌ 预览失败",
+	impactUnchanged:     "✓ 没有变化",
+	impactPending:       "⏳ 待应用",
+	impactSkipped:       "⏭️  已跳过",
+	impactFailed:        "❌ 失败",
+	impactApplied:       "✅ 已重新应用",
+}
+
+// updateResult update 命令的结构化结果
+type updateResult struct {
+	Skills   int                   `json:"skills"`             // 技能仓库中的技能数
+	Updated  []string              `json:"updated"`            // 有变化的技能
+	Held     []string              `json:"held,omitempty"`     // 保持当前版本的技能
+	Projects []updateProjectResult `json:"projects,omitempty"` // 启用了有变化技能的项目
+}
+
+// updateProjectResult 受影响项目的处理结果
+type updateProjectResult struct {
+	Path    string   `json:"path"`
+	Skills  []string `json:"skills"`
+	Targets []string `json:"targets,omitempty"`
+	Pinned  []string `json:"pinned,omitempty"`
+	Status  string   `json:"status"` // preview_failed, unchanged, pending, skipped, failed, applied
+}
+
+// targets 返回重新应用后配置文件有变化的目标工具，按名称排序
+func (p *affectedProject) targets() []string {
+	seen := make(map[string]bool)
+	var targets []string
+	for _, change := range p.Changes {
+		if !seen[change.Target] {
+			seen[change.Target] = true
+			targets = append(targets, change.Target)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// reportUpdateImpact 预览有变化的技能在各项目中重新应用后的差异，并按项目确认后重新应用，返回各项目的处理结果
+//
+// 指定 --apply 时不确认，直接重新应用所有有差异的项目；非交互环境中只列出受影响的项目。
+func reportUpdateImpact(updated []string) (results []updateProjectResult) {
+	if len(updated) == 0 {
+		return nil
+	}
+	affected := findAffectedProjects(updated)
+	if len(affected) == 0 {
+		return nil
+	}
+	hub, err := skillhub.New()
+	if err != nil {
+		fmt.Printf("⚠️  分析受影响的项目失败: %v\n", err)
 		return nil
 	}
+	defer func() {
+		for _, item := range affected {
+			results = append(results, updateProjectResult{
+				Path: item.Path, Skills: item.Skills, Targets: item.targets(), Pinned: item.Pinned, Status: item.Status,
+			})
+		}
+	}()
+
+	fmt.Printf("\n🔍 %d 个项目启用了有变化的技能:\n", len(affected))
+	versions := skillVersions()
+	var pending []*affectedProject
+	for _, item := range affected {
+		fmt.Printf("\n📁 %s: %s\n", item.Path, strings.Join(item.Skills, ", "))
+		if err := previewProjectUpdate(hub, item, versions); err != nil {
+			fmt.Printf("  ⚠️  %v\n", err)
+			item.Status = impactPreviewFailed
+			continue
+		}
+		for _, id := range item.Pinned {
+			fmt.Printf("  📌 %s 已固定版本，重新应用时保持固定版本\n", id)
+		}
+		for _, skipped := range item.Skipped {
+			fmt.Printf("  ⚠️  %s → %s: %s\n", skipped.SkillID, skipped.Target, skipped.Reason)
+		}
+		if len(item.Changes) == 0 {
+			fmt.Println("  ✓ 重新应用后配置文件没有变化")
+			item.Status = impactUnchanged
+			continue
+		}
+		for _, change := range item.Changes {
+			note := ""
+			if change.Conflicts > 0 {
+				note = fmt.Sprintf("，与本地修改合并有 %d 处冲突", change.Conflicts)
+			} else if change.Merged {
+				note = "，已与本地修改合并"
+			}
+			fmt.Printf("  %s → %s (%s%s)\n", change.SkillID, change.Target, change.FilePath, note)
+			printDiff(change.Diff, "    ")
+		}
+		item.Status = impactPending
+		pending = append(pending, item)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	interactive := canPrompt()
+	if !updateApply && !interactive {
+		fmt.Printf("\nℹ️  %d 个项目需要重新应用，在项目中执行 'skill-hub apply'，或使用 'skill-hub update --apply' 全部重新应用\n", len(pending))
+		return
+	}
+	defer printUpdateImpactSummary(affected)
+
+	reader := newInputReader()
+	applyAll := updateApply
+	applied := 0
+	for _, item := range pending {
+		if !applyAll {
+			fmt.Printf("\n是否重新应用到 %s？ [y/N/a(全部)]: ", item.Path)
+			response, _ := reader.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(response)) {
+			case "a", "all":
+				applyAll = true
+			case "y", "yes":
+			default:
+				fmt.Println("  已跳过")
+				item.Status = impactSkipped
+				continue
+			}
+		}
+		count, err := applyProjectUpdate(item)
+		if err != nil {
+			fmt.Printf("❌ 重新应用到 %s 失败: %v\n", item.Path, err)
+			item.Status = impactFailed
+			continue
+		}
+		fmt.Printf("✓ 已重新应用到 %s（%d 个技能）\n", item.Path, count)
+		item.Status = impactApplied
+		applied++
+	}
+	fmt.Printf("\n✅ 已重新应用 %d/%d 个项目\n", applied, len(pending))
+	return
+}
+
+// printUpdateImpactSummary 输出各受影响项目的汇总表：有变化的技能、目标工具、固定的技能和处理结果
+func printUpdateImpactSummary(affected []*affectedProject) {
+	fmt.Printf("\n%-40s %-24s %-20s %-16s %s\n", "项目", "有变化的技能", "目标工具", "固定版本", "结果")
+	for _, item := range affected {
+		var changed []string
+		seen := make(map[string]bool)
+		for _, change := range item.Changes {
+			if !seen[change.SkillID] {
+				seen[change.SkillID] = true
+				changed = append(changed, change.SkillID)
+			}
+		}
+		fmt.Printf("%-40s %-24s %-20s %-16s %s\n", item.Path,
+			valueOrDash(strings.Join(changed, ",")),
+			valueOrDash(strings.Join(item.targets(), ",")),
+			valueOrDash(strings.Join(item.Pinned, ",")),
+			impactLabels[item.Status])
+	}
+}
+
+// findAffectedProjects 查找启用了有变化技能的项目，按路径排序
+func findAffectedProjects(updated []string) []*affectedProject {
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return nil
+	}
+
+	affected := make(map[string]*affectedProject)
+	for _, id := range updated {
+		projects, err := stateMgr.FindProjectsUsingSkill(id)
+		if err != nil {
+			fmt.Printf("⚠️  查找使用技能 %s 的项目失败: %v\n", id, err)
+			continue
+		}
+		for _, path := range projects {
+			if affected[path] == nil {
+				affected[path] = &affectedProject{Path: path}
+			}
+			affected[path].Skills = append(affected[path].Skills, id)
+		}
+	}
+
+	items := make([]*affectedProject, 0, len(affected))
+	for _, item := range affected {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Path < items[j].Path })
+	return items
+}
+
+// previewProjectUpdate 预览项目重新应用后有变化技能的差异，只保留有变化的技能，versions 为技能仓库中的版本
+func previewProjectUpdate(hub *skillhub.Manager, item *affectedProject, versions map[string]string) error {
+	if _, err := os.Stat(item.Path); err != nil {
+		return fmt.Errorf("项目目录不可访问: %w", err)
+	}
+	project, err := hub.Project(item.Path)
+	if err != nil {
+		return err
+	}
+	skills, err := project.Skills()
+	if err != nil {
+		return err
+	}
+	item.project = project
+	item.options = updateApplyOptions(skills, item.Skills)
 
-	fmt.Println("正在扫描项目中的技能标记块...")
-	fmt.Println("更新配置文件...")
-	fmt.Println("✓ 项目更新完成")
+	result := &skillhub.ApplyResult{}
+	for _, opts := range item.options {
+		opts.DryRun = true
+		preview, err := project.Apply(opts)
+		if err != nil {
+			return fmt.Errorf("预览失败: %w", err)
+		}
+		result.Applied = append(result.Applied, preview.Applied...)
+		result.Skipped = append(result.Skipped, preview.Skipped...)
+	}
 
-	fmt.Println("\n✅ 技能仓库和项目已同步更新！")
+	// 固定在其他版本的技能重新应用时保持固定版本
+	if lock, err := state.LoadLockFile(item.Path); err == nil {
+		for _, id := range item.Skills {
+			if locked, pinned := lock.Get(id); pinned && locked.Version != versions[id] {
+				item.Pinned = append(item.Pinned, id)
+			}
+		}
+	}
 
+	changed := make(map[string]bool, len(item.Skills))
+	for _, id := range item.Skills {
+		changed[id] = true
+	}
+	for _, applied := range result.Applied {
+		if changed[applied.SkillID] && applied.Changed {
+			item.Changes = append(item.Changes, applied)
+		}
+	}
+	for _, skipped := range result.Skipped {
+		if changed[skipped.SkillID] {
+			item.Skipped = append(item.Skipped, skipped)
+		}
+	}
 	return nil
 }
+
+// updateApplyOptions 返回重新应用有变化技能的选项：只应用这些技能，不自动启用依赖，
+// 并按应用记录中的目标工具和配置模式分组，以全局模式应用的技能仍写入用户级配置；没有应用记录的技能使用项目的首选目标
+func updateApplyOptions(skills map[string]skillhub.EnabledSkill, skillIDs []string) []skillhub.ApplyOptions {
+	type group struct{ target, mode string }
+	members := make(map[group][]string)
+	var groups []group
+	add := func(g group, id string) {
+		if _, ok := members[g]; !ok {
+			groups = append(groups, g)
+		}
+		members[g] = append(members[g], id)
+	}
+	for _, id := range skillIDs {
+		skillVars := skills[id]
+		if len(skillVars.Applied) == 0 {
+			add(group{mode: skillhub.ModeProject}, id)
+			continue
+		}
+		for target, record := range skillVars.Applied {
+			add(group{target: target, mode: record.AppliedMode()}, id)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].mode != groups[j].mode {
+			return groups[i].mode < groups[j].mode
+		}
+		return groups[i].target < groups[j].target
+	})
+
+	options := make([]skillhub.ApplyOptions, 0, len(groups))
+	for _, g := range groups {
+		only := members[g]
+		sort.Strings(only)
+		options = append(options, skillhub.ApplyOptions{Target: g.target, Mode: g.mode, NoDeps: true, Only: only})
+	}
+	return options
+}
+
+// applyProjectUpdate 按预览时的选项重新应用项目中有变化的技能，返回应用的技能数
+func applyProjectUpdate(item *affectedProject) (int, error) {
+	count := 0
+	for _, opts := range item.options {
+		result, err := item.project.Apply(opts)
+		if err != nil {
+			return count, err
+		}
+		count += len(result.Applied)
+	}
+	return count, nil
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"skill-hub/internal/config"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/git"
+	"skill-hub/internal/state"
+)
+
+// updateRollbackFile 回滚点的记录文件，与技能目录和 registry.json 的副本保存在同一目录
+const updateRollbackFile = "update.json"
+
+// rollbackPoint 更新前技能仓库的状态
+//
+// 技能目录和 registry.json 的副本保存在 ~/.skill-hub/rollback 中，只保留最近一次有变化的更新。
+type rollbackPoint struct {
+	UpdatedAt time.Time         `json:"updated_at"`
+	Head      string            `json:"head,omitempty"`    // 从Git仓库更新时，更新前的提交
+	Held      []string          `json:"held,omitempty"`    // 更新前保持当前版本的技能
+	Skills    map[string]string `json:"skills"`            // 更新前的技能版本
+	Updated   []string          `json:"updated,omitempty"` // 本次更新有变化的技能
+	dir       string
+}
+
+// prepareUpdateRollback 在临时目录中保存更新前的技能目录、registry.json 和保持当前版本的技能，
+// fromGit 为true时同时记录当前提交
+//
+// 更新有变化时由 commit 替换上一个回滚点，否则由 discard 删除，失败的更新不会覆盖已有的回滚点。
+func prepareUpdateRollback(before map[string]skillSnapshot, fromGit bool) (*rollbackPoint, error) {
+	rollbackDir, err := config.GetUpdateRollbackPath()
+	if err != nil {
+		return nil, err
+	}
+	repoDir, err := config.GetRepoPath()
+	if err != nil {
+		return nil, err
+	}
+	skillsDir, err := engine.GetSkillsDir()
+	if err != nil {
+		return nil, fmt.Errorf("获取技能目录失败: %w", err)
+	}
+
+	point := &rollbackPoint{UpdatedAt: time.Now(), Skills: make(map[string]string), dir: rollbackDir + ".new"}
+	for id, snapshot := range before {
+		point.Skills[id] = snapshot.Version
+	}
+	if point.Held, err = loadHeldSkills(); err != nil {
+		return nil, err
+	}
+	if fromGit {
+		repo, err := git.NewSkillRepository()
+		if err != nil {
+			return nil, err
+		}
+		if point.Head, err = repo.Head(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.RemoveAll(point.dir); err != nil {
+		return nil, fmt.Errorf("清理回滚点失败: %w", err)
+	}
+	if err := copyDir(skillsDir, filepath.Join(point.dir, "skills")); err != nil {
+		point.discard()
+		return nil, fmt.Errorf("保存回滚点失败: %w", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(repoDir, "registry.json")); err == nil {
+		if err := os.WriteFile(filepath.Join(point.dir, "registry.json"), data, 0644); err != nil {
+			point.discard()
+			return nil, fmt.Errorf("保存回滚点失败: %w", err)
+		}
+	}
+	return point, nil
+}
+
+// commit 记录有变化的技能并替换上一个回滚点
+func (p *rollbackPoint) commit(updated []string) error {
+	p.Updated = updated
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化回滚点失败: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(p.dir, updateRollbackFile), data, 0644); err != nil {
+		return fmt.Errorf("保存回滚点失败: %w", err)
+	}
+	final := strings.TrimSuffix(p.dir, ".new")
+	if err := os.RemoveAll(final); err != nil {
+		return fmt.Errorf("替换回滚点失败: %w", err)
+	}
+	if err := os.Rename(p.dir, final); err != nil {
+		return fmt.Errorf("替换回滚点失败: %w", err)
+	}
+	p.dir = ""
+	return nil
+}
+
+// discard 删除未提交的回滚点，已提交时不做任何事
+func (p *rollbackPoint) discard() {
+	if p.dir != "" {
+		os.RemoveAll(p.dir)
+	}
+}
+
+// loadUpdateRollback 读取上次更新保存的回滚点，没有回滚点时返回nil
+func loadUpdateRollback() (*rollbackPoint, error) {
+	dir, err := config.GetUpdateRollbackPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, updateRollbackFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取回滚点失败: %w", err)
+	}
+	var point rollbackPoint
+	if err := json.Unmarshal(data, &point); err != nil {
+		return nil, fmt.Errorf("解析回滚点失败: %w", err)
+	}
+	point.dir = dir
+	return &point, nil
+}
+
+// runUpdateRollback 将技能仓库恢复到上次更新前的状态，并重新应用到启用了这些技能的项目
+func runUpdateRollback() error {
+	point, err := loadUpdateRollback()
+	if err != nil {
+		return err
+	}
+	if point == nil {
+		return fmt.Errorf("没有可回滚的更新，update 有技能变化后才会保存回滚点")
+	}
+	fmt.Printf("⏪ 回滚 %s 的更新（%d 个技能有变化）\n", point.UpdatedAt.Format("2006-01-02 15:04:05"), len(point.Updated))
+
+	repoDir, err := config.GetRepoPath()
+	if err != nil {
+		return err
+	}
+	skillsDir, err := engine.GetSkillsDir()
+	if err != nil {
+		return fmt.Errorf("获取技能目录失败: %w", err)
+	}
+	current := skillSnapshots()
+
+	// 从Git仓库更新时先回退提交，保持当前版本的技能是有意保留的修改，回退前恢复为当前提交中的内容
+	if point.Head != "" {
+		repo, err := git.NewSkillRepository()
+		if err != nil {
+			return err
+		}
+		held, err := loadHeldSkills()
+		if err != nil {
+			return err
+		}
+		if len(held) > 0 {
+			if err := repo.ResetSkills(held); err != nil {
+				return err
+			}
+		}
+		if err := repo.Rollback(point.Head); err != nil {
+			return fmt.Errorf("%w\n   使用 'skill-hub git commit' 提交技能仓库中的修改后再回滚", err)
+		}
+	}
+
+	// 技能目录恢复为更新前的副本，包括保持当前版本的技能和注册表下载的技能
+	ids := make(map[string]bool)
+	for id := range current {
+		ids[id] = true
+	}
+	for id := range point.Skills {
+		ids[id] = true
+	}
+	snapshotDir := filepath.Join(point.dir, "skills")
+	for id := range ids {
+		if err := restoreSkillDir(skillsDir, snapshotDir, id); err != nil {
+			return err
+		}
+	}
+	if err := saveHeldSkills(point.Held); err != nil {
+		return err
+	}
+	if data, err := os.ReadFile(filepath.Join(point.dir, "registry.json")); err == nil {
+		if err := os.WriteFile(filepath.Join(repoDir, "registry.json"), data, 0644); err != nil {
+			return fmt.Errorf("恢复registry.json失败: %w", err)
+		}
+	} else if err := refreshSkillRegistry(repoDir); err != nil {
+		return fmt.Errorf("刷新技能注册表失败: %w", err)
+	}
+
+	// 回滚点只使用一次，再次更新后重新保存
+	if err := os.RemoveAll(point.dir); err != nil {
+		fmt.Printf("⚠️  删除回滚点失败: %v\n", err)
+	}
+
+	after := skillSnapshots()
+	changes := diffSkillSnapshots(current, after)
+	changed := append(append(append(append([]string{}, changes.Added...), changes.Upgraded...), changes.Modified...), changes.Removed...)
+	sort.Strings(changed)
+	result := map[string]interface{}{"rolled_back": changed, "updated_at": point.UpdatedAt}
+	setResult(result)
+	if len(changed) == 0 {
+		fmt.Println("ℹ️  没有技能变化")
+		return nil
+	}
+	fmt.Printf("\n📋 %d 个技能已恢复:\n", len(changed))
+	for _, id := range changed {
+		from, to := current[id].Version, after[id].Version
+		switch {
+		case from == "":
+			fmt.Printf("  ↩️  %s@%s（恢复）\n", id, to)
+		case to == "":
+			fmt.Printf("  🗑️  %s@%s（更新时新增，已删除）\n", id, from)
+		case from == to:
+			fmt.Printf("  ✏️  %s@%s（内容恢复）\n", id, to)
+		default:
+			fmt.Printf("  ⏪ %s: %s -> %s\n", id, from, to)
+		}
+	}
+	recordAudit(state.AuditEntry{
+		Operation: state.OpRollback,
+		Detail:    fmt.Sprintf("回滚技能仓库更新，恢复 %d 个技能", len(changed)),
+	})
+	fmt.Println("\n💡 再次运行 'skill-hub update' 会重新拉取这些版本，可使用 --exclude 跳过有问题的技能或用 'skill-hub pin' 固定版本")
+
+	if projects := reportUpdateImpact(changed); len(projects) > 0 {
+		result["projects"] = projects
+	}
+	return nil
+}
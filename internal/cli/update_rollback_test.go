@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRollbackPointCommit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if point, err := loadUpdateRollback(); err != nil || point != nil {
+		t.Fatalf("loadUpdateRollback() = %v, %v, want nil", point, err)
+	}
+
+	dir := filepath.Join(os.Getenv("HOME"), ".skill-hub", "rollback")
+	write := func(point *rollbackPoint, version string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(point.dir, "skills", "alpha"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(point.dir, "skills", "alpha", "SKILL.md"), []byte(version), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := &rollbackPoint{Head: "abc", Skills: map[string]string{"alpha": "1.0.0"}, dir: dir + ".new"}
+	write(first, "1.0.0")
+	if err := first.commit([]string{"alpha"}); err != nil {
+		t.Fatalf("commit() error = %v", err)
+	}
+	// 已提交的回滚点不会被 discard 删除
+	first.discard()
+
+	// 没有变化的更新丢弃新的回滚点，保留上一个
+	second := &rollbackPoint{Skills: map[string]string{"alpha": "1.1.0"}, dir: dir + ".new"}
+	write(second, "1.1.0")
+	second.discard()
+
+	point, err := loadUpdateRollback()
+	if err != nil || point == nil {
+		t.Fatalf("loadUpdateRollback() = %v, %v", point, err)
+	}
+	if point.Head != "abc" || !reflect.DeepEqual(point.Skills, map[string]string{"alpha": "1.0.0"}) || !reflect.DeepEqual(point.Updated, []string{"alpha"}) {
+		t.Errorf("loadUpdateRollback() = %+v", point)
+	}
+	if data, err := os.ReadFile(filepath.Join(point.dir, "skills", "alpha", "SKILL.md")); err != nil || string(data) != "1.0.0" {
+		t.Errorf("回滚点中的技能 = %q, %v, want 1.0.0", data, err)
+	}
+	if _, err := os.Stat(dir + ".new"); !os.IsNotExist(err) {
+		t.Errorf("未提交的回滚点应被删除")
+	}
+}
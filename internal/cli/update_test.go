@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"skill-hub/pkg/skillhub"
+	"skill-hub/pkg/spec"
+)
+
+func TestDiffSkillSnapshots(t *testing.T) {
+	before := map[string]skillSnapshot{
+		"git-expert":     {Version: "1.0.0", Digest: "sha256:a"},
+		"code-review":    {Version: "1.0.0", Digest: "sha256:b"},
+		"acme/lint":      {Version: "2.0.0", Digest: "sha256:c"},
+		"legacy-tool":    {Version: "0.1.0", Digest: "sha256:d"},
+		"no-digest":      {Version: "1.0.0"},
+		"unchanged-tool": {Version: "1.0.0", Digest: "sha256:e"},
+	}
+	after := map[string]skillSnapshot{
+		"git-expert":     {Version: "1.1.0", Digest: "sha256:a2"},
+		"code-review":    {Version: "1.0.0", Digest: "sha256:b2"},
+		"acme/lint":      {Version: "2.0.0", Digest: "sha256:c"},
+		"acme/format":    {Version: "0.1.0", Digest: "sha256:f"},
+		"no-digest":      {Version: "1.0.0", Digest: "sha256:g"},
+		"unchanged-tool": {Version: "1.0.0", Digest: "sha256:e"},
+	}
+
+	want := skillChanges{
+		Added:    []string{"acme/format"},
+		Upgraded: []string{"git-expert"},
+		Modified: []string{"code-review"},
+		Removed:  []string{"legacy-tool"},
+	}
+	if got := diffSkillSnapshots(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffSkillSnapshots() = %+v, want %+v", got, want)
+	}
+	if got := diffSkillSnapshots(after, after); !reflect.DeepEqual(got, skillChanges{}) {
+		t.Errorf("diffSkillSnapshots() without changes = %+v", got)
+	}
+}
+
+func TestAffectedProjectTargets(t *testing.T) {
+	item := &affectedProject{Changes: []skillhub.AppliedSkill{
+		{SkillID: "alpha", Target: "cursor"},
+		{SkillID: "alpha", Target: "claude_code"},
+		{SkillID: "beta", Target: "cursor"},
+	}}
+	if got := item.targets(); !reflect.DeepEqual(got, []string{"claude_code", "cursor"}) {
+		t.Errorf("targets() = %v", got)
+	}
+	if got := (&affectedProject{}).targets(); len(got) != 0 {
+		t.Errorf("targets() of unchanged project = %v, want empty", got)
+	}
+}
+
+func TestUpdateApplyOptions(t *testing.T) {
+	skills := map[string]skillhub.EnabledSkill{
+		"git-expert": {Applied: map[string]spec.AppliedRecord{
+			spec.TargetCursor:     {Version: "1.0.0", Mode: spec.ModeGlobal},
+			spec.TargetClaudeCode: {Version: "1.0.0"},
+		}},
+		"acme/lint": {Applied: map[string]spec.AppliedRecord{spec.TargetClaudeCode: {Version: "2.0.0", Mode: spec.ModeProject}}},
+		"new-skill": {},
+		// 没有更新的技能不应重新应用
+		"unrelated": {Applied: map[string]spec.AppliedRecord{spec.TargetCursor: {Version: "1.0.0"}}},
+	}
+
+	got := updateApplyOptions(skills, []string{"git-expert", "acme/lint", "new-skill"})
+	want := []skillhub.ApplyOptions{
+		{Target: spec.TargetCursor, Mode: spec.ModeGlobal, NoDeps: true, Only: []string{"git-expert"}},
+		{Target: "", Mode: spec.ModeProject, NoDeps: true, Only: []string{"new-skill"}},
+		{Target: spec.TargetClaudeCode, Mode: spec.ModeProject, NoDeps: true, Only: []string{"acme/lint", "git-expert"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("updateApplyOptions() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"skill-hub/internal/config"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/git"
+	"skill-hub/pkg/spec"
+)
+
+// updateCheckTimeout 检查技能更新的最长时间
+const updateCheckTimeout = 10 * time.Second
+
+// updateCheckWait 命令结束后等待检查完成的最长时间，超时后放弃本次检查，不影响命令退出
+const updateCheckWait = 2 * time.Second
+
+// updateCheckSkipped 不检查技能更新的命令：自身会同步技能仓库或列出更新的命令，以及补全和帮助
+var updateCheckSkipped = map[string]bool{
+	"init":             true,
+	"update":           true,
+	"git":              true,
+	"outdated":         true,
+	"completion":       true,
+	"help":             true,
+	"__complete":       true,
+	"__completeNoDesc": true,
+}
+
+// updateCheckState 上次检查技能更新的时间和结果，保存在 ~/.skill-hub/update-check.json
+type updateCheckState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Skills    []string  `json:"skills,omitempty"`
+}
+
+// pendingUpdateCheck 本次命令启动的检查，没有启动时为nil
+var pendingUpdateCheck chan []string
+
+// startUpdateCheck 配置了 update_check 且距上次检查超过 update_check_interval 时，在后台检查技能更新
+//
+// 只在终端中交互使用时检查；--json、--quiet、离线模式和不需要提示的命令不检查。
+func startUpdateCheck(cmd *cobra.Command) {
+	if outputJSON || outputQuiet || isOffline() || !isTerminal(os.Stderr) {
+		return
+	}
+	name := strings.SplitN(commandPath(cmd), " ", 2)[0]
+	if name == "" || updateCheckSkipped[name] {
+		return
+	}
+	cfg, err := config.GetConfig()
+	if err != nil || !cfg.UpdateCheck {
+		return
+	}
+	interval, err := config.GetUpdateCheckInterval()
+	if err != nil {
+		debugf("%v", err)
+		return
+	}
+	if last, err := loadUpdateCheck(); err == nil && time.Since(last.CheckedAt) < interval {
+		return
+	}
+	// 先记录检查时间，检查失败或超时也不在间隔内重试
+	if err := saveUpdateCheck(&updateCheckState{CheckedAt: time.Now()}); err != nil {
+		debugf("%v", err)
+		return
+	}
+
+	// 本地版本在命令执行前读取，后台只访问网络
+	local := skillVersions()
+	pendingUpdateCheck = make(chan []string, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+		defer cancel()
+		skills, err := checkSkillUpdates(ctx, local)
+		if err != nil {
+			debugf("检查技能更新失败: %v", err)
+			return
+		}
+		pendingUpdateCheck <- skills
+	}()
+}
+
+// finishUpdateCheck 等待后台检查完成，有更新时在标准错误输出一行提示
+func finishUpdateCheck(err error) {
+	if pendingUpdateCheck == nil {
+		return
+	}
+	var skills []string
+	select {
+	case skills = <-pendingUpdateCheck:
+	case <-time.After(updateCheckWait):
+		debugf("检查技能更新超时")
+		return
+	}
+	if saveErr := saveUpdateCheck(&updateCheckState{CheckedAt: time.Now(), Skills: skills}); saveErr != nil {
+		debugf("%v", saveErr)
+	}
+	if err != nil || len(skills) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n💡 %d 个技能有更新（%s），运行 'skill-hub update' 更新\n",
+		len(skills), updateCheckSummary(skills))
+}
+
+// updateCheckSummary 返回提示中列出的技能，最多3个
+func updateCheckSummary(skills []string) string {
+	const max = 3
+	if len(skills) <= max {
+		return strings.Join(skills, ", ")
+	}
+	return strings.Join(skills[:max], ", ") + " 等"
+}
+
+// checkSkillUpdates 比较本地技能版本与远程仓库或技能注册表，返回有更新的技能，只包含订阅通道中发布的版本
+func checkSkillUpdates(ctx context.Context, local map[string]string) ([]string, error) {
+	client, err := newRegistryClient()
+	if err != nil {
+		return nil, err
+	}
+	if client != nil {
+		index, err := client.Index(ctx)
+		if err != nil {
+			return nil, err
+		}
+		available := &spec.Registry{Version: index.Version}
+		for _, meta := range index.Skills {
+			if spec.ChannelAccepts(subscribedChannel(meta.ID, ""), meta.Channel) {
+				available.Skills = append(available.Skills, meta)
+			}
+		}
+		var skills []string
+		for _, item := range outdatedSkills(available, local) {
+			skills = append(skills, item.SkillID)
+		}
+		return skills, nil
+	}
+
+	repo, err := git.NewSkillRepository()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := repo.RemoteSkillChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var skills []string
+	for id, content := range changes {
+		if _, ok := local[id]; !ok {
+			continue
+		}
+		skill, err := engine.ParseSkill([]byte(content), id)
+		if err != nil || !spec.ChannelAccepts(subscribedChannel(id, ""), skill.Channel) {
+			continue
+		}
+		skills = append(skills, id)
+	}
+	sort.Strings(skills)
+	return skills, nil
+}
+
+// clearUpdateCheck 更新技能仓库后重置检查时间，已同步的更新不再提示，未更新的技能在下次检查时重新提示
+func clearUpdateCheck() {
+	if cfg, err := config.GetConfig(); err != nil || !cfg.UpdateCheck {
+		return
+	}
+	if err := saveUpdateCheck(&updateCheckState{CheckedAt: time.Now()}); err != nil {
+		debugf("%v", err)
+	}
+}
+
+// loadUpdateCheck 读取上次检查技能更新的结果
+func loadUpdateCheck() (*updateCheckState, error) {
+	path, err := config.GetUpdateCheckPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var state updateCheckState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	return &state, nil
+}
+
+// saveUpdateCheck 保存检查技能更新的时间和结果
+func saveUpdateCheck(state *updateCheckState) error {
+	path, err := config.GetUpdateCheckPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化检查结果失败: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("保存检查结果失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package cli
+
+import "testing"
+
+func TestUpdateCheckSummary(t *testing.T) {
+	tests := []struct {
+		skills []string
+		want   string
+	}{
+		{[]string{"alpha"}, "alpha"},
+		{[]string{"alpha", "acme/lint", "beta"}, "alpha, acme/lint, beta"},
+		{[]string{"alpha", "acme/lint", "beta", "gamma"}, "alpha, acme/lint, beta 等"},
+	}
+	for _, tt := range tests {
+		if got := updateCheckSummary(tt.skills); got != tt.want {
+			t.Errorf("updateCheckSummary(%v) = %q, want %q", tt.skills, got, tt.want)
+		}
+	}
+}
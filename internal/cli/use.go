@@ -3,16 +3,21 @@ package cli
 import (
 	"bufio"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"skill-hub/internal/engine"
+	"skill-hub/internal/secrets"
 	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
 )
 
 var (
-	useTarget string
+	useTarget    string
+	useApply     bool
+	useNoDeps    bool
+	useGlobal    bool
+	useWorkspace bool
 )
 
 var useCmd = &cobra.Command{
@@ -21,8 +26,26 @@ var useCmd = &cobra.Command{
 	Long: `在当前项目启用指定技能，并提示输入变量值。
 
 使用 --target 参数指定首选目标工具 (cursor/claude_code/open_code)。
-如果项目尚未绑定目标，此参数将设置项目的首选目标。`,
-	Args: cobra.ExactArgs(1),
+如果项目尚未绑定目标，此参数将设置项目的首选目标。
+使用 --apply 在启用后立即将该技能及其依赖应用到当前项目，不影响已启用的其他技能。
+
+变量可以在 SKILL.md 中声明类型 (string/int/bool/enum)、required、pattern 和 options，
+输入值不符合声明时会提示重新输入，布尔值统一保存为 true/false。
+
+变量值可以引用环境变量 ${env:NAME} 或密钥 ${secret:NAME}（由配置项 secrets_command 查询），
+状态文件中只保存引用，应用技能时才解析为实际值。
+
+技能声明了依赖时，尚未启用的依赖技能会一并启用，使用 --no-deps 跳过。
+
+使用 --global 在全局作用域启用技能，之后通过 'skill-hub apply --global' 安装到
+~/.cursor、~/.claude 等用户级配置，对所有项目生效。全局作用域的技能与项目状态分开记录。
+
+使用 --workspace 在当前目录所在的工作区（monorepo）启用技能，技能记录在工作区根目录的状态中，
+之后通过 'skill-hub apply --workspace' 应用到所有成员项目。
+
+使用 --project 在指定目录的项目中启用技能，不必先切换到项目目录，适合脚本和CI。`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSkillIDs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runUse(args[0])
 	},
@@ -30,9 +53,20 @@ var useCmd = &cobra.Command{
 
 func init() {
 	useCmd.Flags().StringVar(&useTarget, "target", "", "首选目标工具: cursor, claude_code, open_code (为空时使用项目状态绑定的目标)")
+	useCmd.Flags().BoolVar(&useApply, "apply", false, "启用后立即应用技能")
+	useCmd.Flags().BoolVar(&useNoDeps, "no-deps", false, "不自动启用依赖的技能")
+	useCmd.Flags().BoolVar(&useGlobal, "global", false, "在全局作用域启用技能（安装到用户级配置）")
+	useCmd.Flags().BoolVar(&useWorkspace, "workspace", false, "在当前目录所在的工作区启用技能")
+	addProjectFlag(useCmd)
+
+	useCmd.RegisterFlagCompletionFunc("target", fixedCompletions(targetCompletions...))
 }
 
 func runUse(skillID string) error {
+	if useGlobal && useWorkspace {
+		return fmt.Errorf("--global 和 --workspace 不能同时使用")
+	}
+
 	// 检查技能是否存在
 	manager, err := engine.NewSkillManager()
 	if err != nil {
@@ -49,6 +83,10 @@ func runUse(skillID string) error {
 		return fmt.Errorf("加载技能失败: %w", err)
 	}
 
+	if err := engine.CheckRequirements(skill, version); err != nil {
+		return err
+	}
+
 	fmt.Printf("启用技能: %s (%s)\n", skill.Name, skillID)
 	fmt.Printf("描述: %s\n", skill.Description)
 
@@ -56,15 +94,32 @@ func runUse(skillID string) error {
 		fmt.Printf("标签: %s\n", strings.Join(skill.Tags, ", "))
 	}
 
-	// 检查项目是否已启用该技能
-	stateManager, err := state.NewStateManager()
-	if err != nil {
-		return err
+	// 先解析依赖，依赖缺失或循环时不修改项目状态
+	var dependencies []string
+	if !useNoDeps {
+		order, err := manager.ResolveDependencies([]string{skillID})
+		if err != nil {
+			return err
+		}
+		dependencies = order[:len(order)-1]
+		if len(dependencies) > 0 {
+			fmt.Printf("依赖: %s\n", strings.Join(dependencies, ", "))
+		}
 	}
 
-	cwd, err := os.Getwd()
+	// 检查项目（或全局作用域）是否已启用该技能
+	stateManager, cwd, err := scopeState(useGlobal)
 	if err != nil {
-		return fmt.Errorf("获取当前目录失败: %w", err)
+		return err
+	}
+	// 工作区启用的技能记录在工作区根目录的状态中
+	if useWorkspace {
+		ws, err := detectWorkspace()
+		if err != nil {
+			return err
+		}
+		cwd = ws.Root
+		fmt.Printf("工作区: %s (%s，%d 个成员项目)\n", ws.Root, ws.Kind, len(ws.Members))
 	}
 
 	hasSkill, err := stateManager.ProjectHasSkill(cwd, skillID)
@@ -72,11 +127,20 @@ func runUse(skillID string) error {
 		return err
 	}
 
+	reader := newInputReader()
+
+	// 已启用时以当前变量值作为默认值
+	existing := make(map[string]string)
 	if hasSkill {
-		fmt.Println("⚠️  该技能已在当前项目启用")
+		if useGlobal {
+			fmt.Println("⚠️  该技能已在全局作用域启用")
+		} else if useWorkspace {
+			fmt.Println("⚠️  该技能已在工作区启用")
+		} else {
+			fmt.Println("⚠️  该技能已在当前项目启用")
+		}
 		fmt.Print("是否重新配置变量？ [y/N]: ")
 
-		reader := bufio.NewReader(os.Stdin)
 		response, _ := reader.ReadString('\n')
 		response = strings.TrimSpace(response)
 
@@ -84,6 +148,14 @@ func runUse(skillID string) error {
 			fmt.Println("❌ 取消操作")
 			return nil
 		}
+
+		projectSkills, err := stateManager.GetProjectSkills(cwd)
+		if err != nil {
+			return err
+		}
+		for name, value := range projectSkills[skillID].Variables {
+			existing[name] = value
+		}
 	}
 
 	// 收集变量值
@@ -92,22 +164,9 @@ func runUse(skillID string) error {
 	if len(skill.Variables) > 0 {
 		fmt.Println("\n请设置技能变量 (按Enter使用默认值):")
 
-		reader := bufio.NewReader(os.Stdin)
-		for _, variable := range skill.Variables {
-			defaultValue := variable.Default
-			if defaultValue == "" {
-				defaultValue = ""
-			}
-
-			fmt.Printf("%s [%s]: ", variable.Name, defaultValue)
-			input, _ := reader.ReadString('\n')
-			input = strings.TrimSpace(input)
-
-			if input == "" {
-				variables[variable.Name] = defaultValue
-			} else {
-				variables[variable.Name] = input
-			}
+		variables, err = promptVariables(reader, skill.Variables, existing)
+		if err != nil {
+			return err
 		}
 	} else {
 		fmt.Println("\n该技能没有可配置的变量")
@@ -118,13 +177,145 @@ func runUse(skillID string) error {
 		return fmt.Errorf("保存项目状态失败: %w", err)
 	}
 
-	fmt.Printf("\n✅ 技能 '%s' 已成功启用！\n", skillID)
+	// 全局作用域没有 set-target 命令，直接以 --target 作为首选目标
+	if useGlobal && useTarget != "" {
+		if err := stateManager.SetPreferredTarget(cwd, useTarget); err != nil {
+			return err
+		}
+	}
+
+	// 技能本身先保存，首选目标由 --target 决定
+	if len(dependencies) > 0 {
+		projectSkills, err := stateManager.GetProjectSkills(cwd)
+		if err != nil {
+			return err
+		}
+		if _, err := enableDependencies(reader, manager, stateManager, cwd, dependencies, projectSkills, true); err != nil {
+			return err
+		}
+	}
+
+	if useGlobal {
+		fmt.Printf("\n✅ 技能 '%s' 已在全局作用域启用！\n", skillID)
+	} else if useWorkspace {
+		fmt.Printf("\n✅ 技能 '%s' 已在工作区启用！\n", skillID)
+	} else {
+		fmt.Printf("\n✅ 技能 '%s' 已成功启用！\n", skillID)
+	}
 
 	// 显示目标信息
-	if useTarget != "" {
+	if useTarget != "" && useGlobal {
+		fmt.Printf("全局首选目标已设置为: %s\n", useTarget)
+	} else if useTarget != "" {
 		fmt.Printf("项目首选目标已设置为: %s\n", useTarget)
 	}
-	fmt.Println("使用 'skill-hub apply' 将技能应用到当前项目")
+
+	if useApply {
+		fmt.Println()
+		target = useTarget
+		applyNoDeps = useNoDeps
+		applyGlobal = useGlobal
+		applyWorkspace = useWorkspace
+		// 只应用该技能及其依赖，项目中的其他技能保持不变
+		return runApply(append([]string{skillID}, dependencies...))
+	}
+
+	if useGlobal {
+		fmt.Println("使用 'skill-hub apply --global' 将技能安装到用户级配置")
+	} else if useWorkspace {
+		fmt.Println("使用 'skill-hub apply --workspace' 将技能应用到所有成员项目")
+	} else {
+		fmt.Println("使用 'skill-hub apply' 将技能应用到当前项目")
+	}
 
 	return nil
 }
+
+// enableDependencies 在项目中启用尚未启用的依赖技能，返回新启用技能的配置
+//
+// 依赖技能的变量使用默认值，没有默认值时提示输入。save 为 false 时不写入项目状态。
+func enableDependencies(reader *bufio.Reader, manager *engine.SkillManager, stateManager *state.StateManager, cwd string, dependencies []string, projectSkills map[string]spec.SkillVars, save bool) (map[string]spec.SkillVars, error) {
+	enabled := make(map[string]spec.SkillVars)
+	for _, id := range dependencies {
+		if _, ok := projectSkills[id]; ok {
+			continue
+		}
+
+		skill, err := manager.LoadSkill(id)
+		if err != nil {
+			return nil, err
+		}
+		variables, err := resolveBundleVariables(reader, skill, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if save {
+			if err := stateManager.AddSkillToProject(cwd, id, skill.Version, variables); err != nil {
+				return nil, fmt.Errorf("保存项目状态失败: %w", err)
+			}
+		}
+
+		fmt.Printf("📦 自动启用依赖技能: %s@%s\n", id, skill.Version)
+		enabled[id] = spec.SkillVars{SkillID: id, Version: skill.Version, Variables: variables}
+	}
+	return enabled, nil
+}
+
+// promptVariables 逐个提示输入变量值，显示默认值和说明并校验输入
+func promptVariables(reader *bufio.Reader, vars []spec.Variable, existing map[string]string) (map[string]string, error) {
+	values := make(map[string]string)
+
+	for _, variable := range vars {
+		defaultValue := variable.Default
+		if value, ok := existing[variable.Name]; ok {
+			defaultValue = value
+		}
+
+		if variable.Description != "" {
+			fmt.Printf("  # %s\n", variable.Description)
+		}
+
+		label := variable.Name
+		if hint := variable.Hint(); hint != "" {
+			label += " (" + hint + ")"
+		}
+
+		for {
+			fmt.Printf("%s [%s]: ", label, defaultValue)
+			input, readErr := reader.ReadString('\n')
+			input = strings.TrimSpace(input)
+
+			value := input
+			if value == "" {
+				value = defaultValue
+			}
+
+			normalized, err := validateVariableValue(variable, value)
+			if err != nil {
+				if readErr != nil {
+					return nil, fmt.Errorf("变量 %s 无效: %w", variable.Name, err)
+				}
+				fmt.Printf("❌ %v，请重新输入\n", err)
+				continue
+			}
+
+			values[variable.Name] = normalized
+			break
+		}
+	}
+
+	return values, nil
+}
+
+// validateVariableValue 按变量声明校验变量值，返回规范化后的值
+//
+// 包含 ${env:NAME}/${secret:NAME} 引用的值原样保存，渲染时解析后再按声明校验。
+func validateVariableValue(variable spec.Variable, value string) (string, error) {
+	if strings.Contains(value, "{{") || strings.Contains(value, "}}") {
+		return "", fmt.Errorf("变量值不能包含模板语法 {{ }}")
+	}
+	if secrets.HasReference(value) {
+		return value, nil
+	}
+	return variable.Normalize(value)
+}
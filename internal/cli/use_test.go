@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestPromptVariables(t *testing.T) {
+	vars := []spec.Variable{
+		{Name: "LANGUAGE", Default: "zh", Description: "输出语言"},
+		{Name: "PROJECT"},
+		{Name: "STYLE", Default: "quick"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		existing map[string]string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:  "使用默认值",
+			input: "\nmy-app\n\n",
+			want:  map[string]string{"LANGUAGE": "zh", "PROJECT": "my-app", "STYLE": "quick"},
+		},
+		{
+			name:  "必填变量为空时重新输入",
+			input: "en\n\nmy-app\nstrict\n",
+			want:  map[string]string{"LANGUAGE": "en", "PROJECT": "my-app", "STYLE": "strict"},
+		},
+		{
+			name:  "拒绝模板语法",
+			input: "{{.X}}\nen\napp\n\n",
+			want:  map[string]string{"LANGUAGE": "en", "PROJECT": "app", "STYLE": "quick"},
+		},
+		{
+			name:     "已有值作为默认值",
+			input:    "\n\n\n",
+			existing: map[string]string{"PROJECT": "old-app", "STYLE": "detailed"},
+			want:     map[string]string{"LANGUAGE": "zh", "PROJECT": "old-app", "STYLE": "detailed"},
+		},
+		{
+			name:    "输入结束时必填变量仍为空",
+			input:   "\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+			got, err := promptVariables(reader, vars, tt.existing)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("promptVariables() expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("promptVariables() error = %v", err)
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %q, want %q", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestPromptTypedVariables(t *testing.T) {
+	vars := []spec.Variable{
+		{Name: "MAX_LINES", Type: spec.VarTypeInt, Default: "80"},
+		{Name: "STRICT", Type: spec.VarTypeBool, Default: "false"},
+		{Name: "LANGUAGE", Type: spec.VarTypeEnum, Options: []string{"go", "rust"}},
+	}
+
+	// 无效值提示后重新输入，布尔值规范化为 true/false
+	reader := bufio.NewReader(strings.NewReader("many\n120\nyes\njava\nrust\n"))
+	got, err := promptVariables(reader, vars, nil)
+	if err != nil {
+		t.Fatalf("promptVariables() error = %v", err)
+	}
+	want := map[string]string{"MAX_LINES": "120", "STRICT": "true", "LANGUAGE": "rust"}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+}
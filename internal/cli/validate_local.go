@@ -8,7 +8,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
+	"skill-hub/internal/engine"
 	"skill-hub/internal/state"
+	"skill-hub/internal/tool"
 	"skill-hub/pkg/spec"
 	"skill-hub/pkg/validator"
 )
@@ -25,7 +27,8 @@ var validateLocalCmd = &cobra.Command{
 
 检查技能格式、变量配置和适配器兼容性。
 生成验证报告，帮助识别和修复问题。`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectSkillIDs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runValidateLocal(args[0])
 	},
@@ -34,6 +37,8 @@ var validateLocalCmd = &cobra.Command{
 func init() {
 	validateLocalCmd.Flags().StringVar(&validateTarget, "target", "", "目标工具: cursor, claude_code, open_code, all, auto (为空时使用状态绑定的目标)")
 	validateLocalCmd.Flags().BoolVar(&validateStrict, "strict", false, "严格模式：警告也视为错误")
+
+	validateLocalCmd.RegisterFlagCompletionFunc("target", fixedCompletions(autoTargetCompletions...))
 }
 
 func runValidateLocal(skillID string) error {
@@ -149,6 +154,17 @@ func runValidateLocal(skillID string) error {
 		fmt.Println("   ✓ 技能文件完整")
 	}
 
+	// 验证5: tool 模式技能的 input_schema 和示例输入
+	if skill.Claude != nil && skill.Claude.Mode == tool.ModeTool && skill.Claude.ToolSpec != nil {
+		fmt.Println("5. 验证工具定义...")
+		if err := engine.ValidateToolSpec(agentsSkillsDir, skill); err != nil {
+			validationResult.Errors = append(validationResult.Errors, fmt.Sprintf("工具定义验证失败: %v", err))
+			validationResult.IsValid = false
+		} else {
+			fmt.Println("   ✓ input_schema 和示例输入正确")
+		}
+	}
+
 	// 显示验证结果
 	fmt.Println("\n" + strings.Repeat("=", 50))
 	fmt.Println("验证结果:")
@@ -261,8 +277,8 @@ func validateVariables(skill *spec.Skill, variables map[string]string, result *s
 
 // validateAdapterCompatibility 验证适配器兼容性
 func validateAdapterCompatibility(skill *spec.Skill, target string, result *spec.ValidationResult) error {
-	// 获取技能兼容性描述
-	compatLower := strings.ToLower(skill.Compatibility)
+	// 技能兼容性描述中声明的目标工具
+	compat := skill.Compatible()
 
 	// 规范化目标值
 	target = spec.NormalizeTarget(target)
@@ -273,14 +289,10 @@ func validateAdapterCompatibility(skill *spec.Skill, target string, result *spec
 	switch target {
 	case "", "auto":
 		// 自动检测：根据技能兼容性检查所有支持的适配器
-		if strings.Contains(compatLower, "cursor") {
-			adaptersToCheck = append(adaptersToCheck, spec.TargetCursor)
-		}
-		if strings.Contains(compatLower, "claude") {
-			adaptersToCheck = append(adaptersToCheck, spec.TargetClaudeCode)
-		}
-		if strings.Contains(compatLower, "opencode") {
-			adaptersToCheck = append(adaptersToCheck, spec.TargetOpenCode)
+		for _, candidate := range []string{spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode} {
+			if compat.Supports(candidate) {
+				adaptersToCheck = append(adaptersToCheck, candidate)
+			}
 		}
 
 		// 如果没有明确指定，检查所有
@@ -297,23 +309,7 @@ func validateAdapterCompatibility(skill *spec.Skill, target string, result *spec
 		adaptersToCheck = append(adaptersToCheck, target)
 
 		// 检查技能是否支持该适配器
-		supported := false
-		for _, adapter := range adaptersToCheck {
-			// 将适配器名称转换为技能兼容性描述中可能的形式
-			adapterName := adapter
-			if adapter == spec.TargetClaudeCode {
-				adapterName = "claude"
-			} else if adapter == spec.TargetOpenCode {
-				adapterName = "opencode"
-			}
-
-			if strings.Contains(compatLower, adapterName) {
-				supported = true
-				break
-			}
-		}
-
-		if !supported {
+		if !compat.Supports(target) {
 			result.Errors = append(result.Errors,
 				fmt.Sprintf("技能不支持 %s 适配器", target))
 			return fmt.Errorf("适配器不兼容")
@@ -322,15 +318,7 @@ func validateAdapterCompatibility(skill *spec.Skill, target string, result *spec
 
 	// 验证每个适配器
 	for _, adapter := range adaptersToCheck {
-		// 将适配器名称转换为技能兼容性描述中可能的形式
-		adapterName := adapter
-		if adapter == spec.TargetClaudeCode {
-			adapterName = "claude"
-		} else if adapter == spec.TargetOpenCode {
-			adapterName = "opencode"
-		}
-
-		if !strings.Contains(compatLower, adapterName) {
+		if !compat.Supports(adapter) {
 			result.Warnings = append(result.Warnings, fmt.Sprintf("技能可能不完全兼容 %s", adapter))
 		}
 	}
@@ -428,9 +416,9 @@ func loadSkillFromLocalProject(projectPath, skillID string) (*spec.Skill, error)
 		skill.Description = description
 	}
 
-	// 设置兼容性
-	if compatibility, ok := skillData["compatibility"].(string); ok {
-		skill.Compatibility = compatibility
+	// 设置兼容性，对象格式（向后兼容）转换为说明文字
+	if compatibility, ok := skillData["compatibility"]; ok {
+		skill.Compatibility = spec.CompatibilityDescription(compatibility)
 	}
 
 	// 设置版本
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/adapter"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+// 技能完整性状态
+const (
+	integrityOK       = "ok"       // 与最近一次应用的内容一致
+	integrityModified = "modified" // 目标文件中的内容被修改
+	integrityMissing  = "missing"  // 已应用但目标文件中找不到
+	integrityOutdated = "outdated" // 内容未被修改，但技能仓库已更新
+)
+
+var (
+	verifyTarget string
+	verifyMode   string
+	verifyStrict bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [skill-id...]",
+	Short: "校验已应用技能的完整性",
+	Long: `重新计算各适配器中已应用技能内容的哈希，与最近一次 apply 的记录比对，
+逐个技能报告被修改、丢失或落后于技能仓库的情况。
+
+发现被修改或丢失的技能时返回非零退出码，可用于 pre-commit 钩子：
+  skill-hub verify --json
+
+落后于技能仓库（outdated）默认只提示，使用 --strict 时同样视为失败。`,
+	ValidArgsFunction: completeProjectSkillIDs,
+	// 校验失败是检查结果而不是用法错误
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVerify(args)
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyTarget, "target", spec.TargetAll, "目标工具: cursor, claude_code, open_code, shell, all")
+	verifyCmd.Flags().StringVar(&verifyMode, "mode", "project", "配置模式: project (项目级), global (全局)")
+	verifyCmd.Flags().BoolVar(&verifyStrict, "strict", false, "严格模式：技能落后于仓库时也返回错误")
+
+	verifyCmd.RegisterFlagCompletionFunc("target", fixedCompletions(targetCompletions...))
+	verifyCmd.RegisterFlagCompletionFunc("mode", fixedCompletions(modeCompletions...))
+}
+
+// integrityResult 单个技能在单个适配器中的校验结果
+type integrityResult struct {
+	SkillID string `json:"skill_id"`
+	Target  string `json:"target"`
+	Status  string `json:"status"`
+	Version string `json:"version,omitempty"` // 最近一次应用的版本
+	SHA256  string `json:"sha256,omitempty"`  // 目标文件中技能内容的哈希
+}
+
+func runVerify(skillIDs []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	projectSkills, err := stateMgr.GetProjectSkills(cwd)
+	if err != nil {
+		return err
+	}
+
+	if len(skillIDs) == 0 {
+		for id := range projectSkills {
+			skillIDs = append(skillIDs, id)
+		}
+		sort.Strings(skillIDs)
+	}
+	if len(skillIDs) == 0 {
+		fmt.Println("ℹ️  当前项目未启用任何技能")
+		setResult([]integrityResult{})
+		return nil
+	}
+
+	adapters := selectAdapters(spec.NormalizeTarget(verifyTarget), verifyMode)
+	if len(adapters) == 0 {
+		return fmt.Errorf("无效的目标工具: %s，可用选项: %s, %s, %s, %s, %s", verifyTarget, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell, spec.TargetAll)
+	}
+
+	skillManager, err := engine.NewSkillManager()
+	if err != nil {
+		return err
+	}
+	if projectState, err := stateMgr.LoadProjectState(cwd); err == nil {
+		useProjectLocale(skillManager, projectState)
+	}
+	lock, err := state.LoadLockFile(cwd)
+	if err != nil {
+		return err
+	}
+
+	results := []integrityResult{}
+	for _, skillID := range skillIDs {
+		skillVars, enabled := projectSkills[skillID]
+		if !enabled {
+			return fmt.Errorf("技能 %s 未在当前项目中启用", skillID)
+		}
+
+		skill, _ := skillManager.LoadSkill(skillID)
+
+		for _, adpt := range adapters {
+			if !adpt.Supports() || (skill != nil && !adapterSupportsSkill(adpt, skill)) {
+				continue
+			}
+			target := getAdapterTarget(adpt)
+
+			// 技能仓库中的当前内容，用于判断是否落后以及没有应用记录时的比对
+			var repoContent string
+			if skill != nil {
+				repoContent, _ = skillManager.RenderSkill(skillID, skillVars.Variables, target, verifyMode)
+			}
+
+			var rev *spec.AppliedRevision
+			if history := skillVars.History[target]; len(history) > 0 {
+				rev = &history[len(history)-1]
+			}
+
+			result, checked, err := checkAppliedIntegrity(adpt, skillID, rev, repoContent)
+			if err != nil {
+				return fmt.Errorf("校验技能 %s (%s) 失败: %w", skillID, target, err)
+			}
+			if !checked {
+				continue
+			}
+			result.Target = target
+
+			// 固定版本的技能落后于仓库是预期行为
+			if result.Status == integrityOutdated {
+				if locked, pinned := lock.Get(skillID); pinned && locked.Version == result.Version {
+					result.Status = integrityOK
+				}
+			}
+			results = append(results, result)
+		}
+	}
+
+	setResult(results)
+	return reportIntegrity(results)
+}
+
+// checkAppliedIntegrity 比对适配器中技能的当前内容与最近一次应用的内容
+//
+// 比对通过适配器的 Plan 完成：以记录的内容重新计算变更计划，目标文件无变化即内容完整，
+// 这样各适配器写入时的格式转换不会影响结果。没有应用记录时以技能仓库的当前内容为准。
+// 技能既没有应用记录也不在目标文件中时返回 checked=false。
+func checkAppliedIntegrity(adpt adapter.Adapter, skillID string, rev *spec.AppliedRevision, repoContent string) (integrityResult, bool, error) {
+	result := integrityResult{SkillID: skillID}
+
+	current, err := adpt.Extract(skillID)
+	present := err == nil && strings.TrimSpace(current) != ""
+	if !present {
+		if rev == nil {
+			return result, false, nil
+		}
+		result.Status = integrityMissing
+		result.Version = rev.Version
+		return result, true, nil
+	}
+
+	sum := sha256.Sum256([]byte(strings.TrimSpace(current)))
+	result.SHA256 = hex.EncodeToString(sum[:])
+
+	expected := repoContent
+	if rev != nil {
+		if expected, err = state.RevisionContent(*rev); err != nil {
+			return result, false, err
+		}
+		result.Version = rev.Version
+	}
+
+	plan, err := adpt.Plan(skillID, expected, nil)
+	if err != nil {
+		return result, false, err
+	}
+
+	switch {
+	case plan.Before != plan.After:
+		result.Status = integrityModified
+	case rev != nil && repoContent != "" && strings.TrimSpace(expected) != strings.TrimSpace(repoContent):
+		result.Status = integrityOutdated
+	default:
+		result.Status = integrityOK
+	}
+	return result, true, nil
+}
+
+// reportIntegrity 输出校验结果，存在问题时返回错误
+func reportIntegrity(results []integrityResult) error {
+	if len(results) == 0 {
+		fmt.Println("ℹ️  没有找到已应用的技能")
+		return nil
+	}
+
+	counts := make(map[string]int)
+	fmt.Printf("%-24s %-12s %-10s %-10s %s\n", "技能", "目标", "版本", "状态", "SHA256")
+	for _, r := range results {
+		counts[r.Status]++
+
+		status := "✅ 一致"
+		switch r.Status {
+		case integrityModified:
+			status = "❌ 已修改"
+		case integrityMissing:
+			status = "❌ 已丢失"
+		case integrityOutdated:
+			status = "⚠️ 已落后"
+		}
+		hash := r.SHA256
+		if len(hash) > 12 {
+			hash = hash[:12]
+		}
+		fmt.Printf("%-24s %-12s %-10s %-10s %s\n", r.SkillID, r.Target, valueOrDash(r.Version), status, valueOrDash(hash))
+	}
+
+	fmt.Printf("\n共 %d 项: %d 一致, %d 已修改, %d 已丢失, %d 已落后\n",
+		len(results), counts[integrityOK], counts[integrityModified], counts[integrityMissing], counts[integrityOutdated])
+
+	failed := counts[integrityModified] + counts[integrityMissing]
+	if verifyStrict {
+		failed += counts[integrityOutdated]
+	}
+	if failed > 0 {
+		if counts[integrityModified] > 0 {
+			fmt.Println("使用 'skill-hub feedback <skill-id>' 保留修改，或 'skill-hub apply' 恢复")
+		}
+		return fmt.Errorf("完整性校验失败: %d 项不一致", failed)
+	}
+	if counts[integrityOutdated] > 0 {
+		fmt.Println("使用 'skill-hub apply' 更新落后的技能")
+	}
+	return nil
+}
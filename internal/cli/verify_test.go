@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"testing"
+
+	"skill-hub/internal/adapter"
+	"skill-hub/pkg/spec"
+)
+
+// fakeAdapter 以内存中的内容模拟单个技能的目标文件
+type fakeAdapter struct {
+	content string
+}
+
+func (f *fakeAdapter) Apply(skillID, content string, variables map[string]string) error {
+	f.content = content
+	return nil
+}
+
+func (f *fakeAdapter) Plan(skillID, content string, variables map[string]string) (*adapter.Plan, error) {
+	return &adapter.Plan{SkillID: skillID, FilePath: "fake", Before: f.content, After: content}, nil
+}
+
+func (f *fakeAdapter) Extract(skillID string) (string, error) { return f.content, nil }
+func (f *fakeAdapter) Remove(skillID string) error            { f.content = ""; return nil }
+func (f *fakeAdapter) List() ([]string, error)                { return nil, nil }
+func (f *fakeAdapter) Supports() bool                         { return true }
+
+func TestCheckAppliedIntegrity(t *testing.T) {
+	rev := &spec.AppliedRevision{Version: "1.0.0", Content: "applied"}
+
+	tests := []struct {
+		name        string
+		onDisk      string
+		rev         *spec.AppliedRevision
+		repoContent string
+		wantChecked bool
+		wantStatus  string
+	}{
+		{"intact", "applied", rev, "applied", true, integrityOK},
+		{"modified", "edited", rev, "applied", true, integrityModified},
+		{"missing", "", rev, "applied", true, integrityMissing},
+		{"outdated", "applied", rev, "updated", true, integrityOutdated},
+		{"modified wins over outdated", "edited", rev, "updated", true, integrityModified},
+		{"no record matches repo", "updated", nil, "updated", true, integrityOK},
+		{"no record differs from repo", "edited", nil, "updated", true, integrityModified},
+		{"not applied", "", nil, "updated", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, checked, err := checkAppliedIntegrity(&fakeAdapter{content: tt.onDisk}, "demo", tt.rev, tt.repoContent)
+			if err != nil {
+				t.Fatalf("checkAppliedIntegrity() error = %v", err)
+			}
+			if checked != tt.wantChecked {
+				t.Fatalf("checked = %v, want %v", checked, tt.wantChecked)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", result.Status, tt.wantStatus)
+			}
+			if tt.onDisk != "" && len(result.SHA256) != 64 {
+				t.Errorf("SHA256 = %q, want hex digest", result.SHA256)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/state"
+	"skill-hub/internal/workspace"
+	"skill-hub/pkg/skillhub"
+	"skill-hub/pkg/spec"
+
+	"github.com/spf13/cobra"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "查看工作区（monorepo）的成员项目和技能",
+	Long: `从当前目录向上查找工作区根目录，依次识别:
+  ` + workspace.MarkerFile + `   members 列出成员目录（支持 * 通配符）
+  go.work                     use 指令列出的模块目录
+  package.json                workspaces 字段列出的包目录
+
+使用 'skill-hub use <skill-id> --workspace' 在工作区启用技能，技能记录在工作区根目录的状态中；
+'skill-hub apply --workspace' 将工作区的技能应用到每个成员项目，成员项目的应用记录各自保存。
+成员项目需要不同的变量值时，使用 'skill-hub workspace set' 覆盖。
+
+示例:
+  skill-hub workspace
+  skill-hub workspace set services/api git-expert LANGUAGE=go
+  skill-hub apply --workspace --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWorkspaceShow()
+	},
+}
+
+var workspaceSetCmd = &cobra.Command{
+	Use:   "set <member> <skill-id> <KEY=VALUE>...",
+	Short: "设置成员项目覆盖的技能变量（KEY= 删除该变量的覆盖值）",
+	Long: `为工作区的一个成员项目覆盖技能变量，member 为成员目录相对于工作区根目录的路径。
+变量按 工作区技能变量 <- 成员覆盖值 的顺序合并。`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWorkspaceSet(args[0], args[1], args[2:])
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceSetCmd)
+}
+
+func runWorkspaceShow() error {
+	ws, err := detectWorkspace()
+	if err != nil {
+		return err
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	rootState, err := stateMgr.LoadProjectState(ws.Root)
+	if err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("获取当前目录失败: %w", err)
+	}
+	current := ws.MemberOf(cwd)
+
+	fmt.Printf("工作区: %s (%s)\n", ws.Root, ws.Kind)
+	fmt.Printf("\n成员项目 (%d):\n", len(ws.Members))
+	for _, member := range ws.Members {
+		marker := ""
+		if member == current {
+			marker = "  ← 当前目录"
+		}
+		fmt.Printf("  %s%s\n", ws.RelPath(member), marker)
+	}
+
+	skillIDs := make([]string, 0, len(rootState.Skills))
+	for skillID := range rootState.Skills {
+		skillIDs = append(skillIDs, skillID)
+	}
+	sort.Strings(skillIDs)
+	if len(skillIDs) == 0 {
+		fmt.Println("\nℹ️  工作区未启用任何技能，使用 'skill-hub use <skill-id> --workspace' 启用")
+	} else {
+		fmt.Printf("\n工作区技能 (%d):\n", len(skillIDs))
+		for _, skillID := range skillIDs {
+			fmt.Printf("  %s (v%s)\n", skillID, rootState.Skills[skillID].Version)
+		}
+	}
+
+	members := make([]string, 0, len(rootState.MemberVariables))
+	for member := range rootState.MemberVariables {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	if len(members) > 0 {
+		fmt.Println("\n成员变量覆盖:")
+		for _, member := range members {
+			overrides := rootState.MemberVariables[member]
+			overrideIDs := make([]string, 0, len(overrides))
+			for skillID := range overrides {
+				overrideIDs = append(overrideIDs, skillID)
+			}
+			sort.Strings(overrideIDs)
+			for _, skillID := range overrideIDs {
+				values := overrides[skillID]
+				var pairs []string
+				for _, key := range sortedKeys(values) {
+					pairs = append(pairs, key+"="+values[key])
+				}
+				fmt.Printf("  %s  %s: %s\n", member, skillID, strings.Join(pairs, " "))
+			}
+		}
+	}
+
+	setResult(struct {
+		*workspace.Workspace
+		Skills          []string                                `json:"skills"`
+		MemberVariables map[string]map[string]map[string]string `json:"member_variables,omitempty"`
+	}{ws, skillIDs, rootState.MemberVariables})
+	return nil
+}
+
+func runWorkspaceSet(member, skillID string, assignments []string) error {
+	ws, err := detectWorkspace()
+	if err != nil {
+		return err
+	}
+	memberPath, ok := ws.Member(member)
+	if !ok {
+		return fmt.Errorf("%s 不是工作区的成员项目，使用 'skill-hub workspace' 查看成员", member)
+	}
+	if memberPath == ws.Root {
+		return fmt.Errorf("工作区根目录使用工作区技能的变量，请使用 'skill-hub use %s --workspace' 重新配置", skillID)
+	}
+
+	values, err := parseVariableAssignments(skillID, assignments)
+	if err != nil {
+		return err
+	}
+
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	rel := ws.RelPath(memberPath)
+	if err := stateMgr.SetMemberVariables(ws.Root, rel, skillID, values); err != nil {
+		return err
+	}
+	fmt.Printf("✓ 已更新成员项目 %s 中技能 %s 的变量\n", rel, skillID)
+	fmt.Println("使用 'skill-hub apply --workspace' 应用到成员项目")
+	return nil
+}
+
+// workspaceMemberResult 工作区中一个成员项目的应用结果
+type workspaceMemberResult struct {
+	Member string                `json:"member"`
+	Result *skillhub.ApplyResult `json:"result,omitempty"`
+	Error  string                `json:"error,omitempty"`
+}
+
+// runApplyWorkspace 将工作区启用的技能应用到每个成员项目
+func runApplyWorkspace(only []string) error {
+	if applyGlobal {
+		return fmt.Errorf("--global 和 --workspace 不能同时使用")
+	}
+	if applyProfile != "" {
+		return fmt.Errorf("--profile 不能与 --workspace 同时使用，成员项目的变量使用 'skill-hub workspace set' 覆盖")
+	}
+
+	fmt.Println("正在应用工作区技能到成员项目...")
+	ws, err := detectWorkspace()
+	if err != nil {
+		return err
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return err
+	}
+	rootState, err := stateMgr.LoadProjectState(ws.Root)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("工作区: %s (%s)\n", ws.Root, ws.Kind)
+
+	if len(rootState.Skills) == 0 {
+		fmt.Println("ℹ️  工作区未启用任何技能")
+		fmt.Println("使用 'skill-hub use <skill-id> --workspace' 启用技能")
+		return nil
+	}
+	for _, skillID := range only {
+		if _, ok := rootState.Skills[skillID]; !ok {
+			return fmt.Errorf("技能 '%s' 未在工作区启用，使用 'skill-hub use %s --workspace' 启用", skillID, skillID)
+		}
+	}
+	if len(ws.Members) == 0 {
+		fmt.Println("ℹ️  工作区没有成员项目")
+		return nil
+	}
+
+	resolvedTargets, err := resolveTargets(target, rootState)
+	if err != nil {
+		return err
+	}
+	if len(resolvedTargets) == 0 {
+		fmt.Println("❌ 工作区未关联目标")
+		fmt.Printf("请使用 'skill-hub apply --workspace --target [%s|%s|%s|%s]' 指定目标\n", spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetAll)
+		return nil
+	}
+	fmt.Printf("目标工具: %s\n", strings.Join(resolvedTargets, ", "))
+
+	hub, err := skillhub.New()
+	if err != nil {
+		return err
+	}
+
+	var results []workspaceMemberResult
+	failed := 0
+	for _, member := range ws.Members {
+		rel := ws.RelPath(member)
+		fmt.Printf("\n📦 %s\n", rel)
+
+		project, err := hub.Project(member)
+		result := &skillhub.ApplyResult{Applied: []skillhub.AppliedSkill{}}
+		// 工作区绑定了多个目标时依次应用到每个目标
+		for i := 0; err == nil && i < len(resolvedTargets); i++ {
+			opts := skillhub.ApplyOptions{Target: resolvedTargets[i], Mode: mode, DryRun: dryRun, NoDeps: applyNoDeps, Only: only, Exclude: applyExclude}
+			// 根目录本身也是成员时，工作区技能已在其状态中
+			if member != ws.Root {
+				opts.Skills = state.MemberSkills(rootState, rel)
+			}
+			var targetResult *skillhub.ApplyResult
+			if targetResult, err = project.Apply(opts); err == nil {
+				result.Applied = append(result.Applied, targetResult.Applied...)
+				result.Skipped = append(result.Skipped, targetResult.Skipped...)
+			}
+		}
+		if err != nil {
+			fmt.Printf("  ❌ %v\n", err)
+			results = append(results, workspaceMemberResult{Member: rel, Error: err.Error()})
+			failed++
+			if strictMode {
+				// 之前的成员项目已应用，不回滚
+				setResult(results)
+				err = fmt.Errorf("严格模式下成员项目 %s 应用失败，停止应用其余成员项目: %w", rel, err)
+				if len(results) > 1 {
+					return &exitError{code: ExitPartialFailure, err: err}
+				}
+				return applyFailed(err)
+			}
+			continue
+		}
+		results = append(results, workspaceMemberResult{Member: rel, Result: result})
+
+		for _, applied := range result.Applied {
+			status := "无变化"
+			switch {
+			case applied.Changed && dryRun:
+				status = "将更新"
+			case applied.Changed:
+				status = "已更新"
+			}
+			fmt.Printf("  ✓ %s → %s (%s)\n", applied.SkillID, applied.Target, status)
+		}
+		for _, skipped := range result.Skipped {
+			fmt.Printf("  ⚠️  跳过 %s (%s): %s\n", skipped.SkillID, skipped.Target, skipped.Reason)
+		}
+	}
+
+	setResult(results)
+	if failed > 0 {
+		return applyFailures(failed, len(ws.Members)-failed, "个成员项目")
+	}
+	if dryRun {
+		fmt.Println("\nℹ️  预览模式，未修改文件")
+	} else {
+		fmt.Printf("\n✅ 工作区技能已应用到 %d 个成员项目\n", len(ws.Members))
+	}
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"skill-hub/pkg/spec"
 )
 
 type Config struct {
@@ -17,6 +19,71 @@ type Config struct {
 	GitRemoteURL     string `mapstructure:"git_remote_url"`
 	GitToken         string `mapstructure:"git_token"`
 	GitBranch        string `mapstructure:"git_branch"`
+	// SkillRoots 额外的技能目录，按优先级从高到低排列
+	SkillRoots []SkillRoot `mapstructure:"skill_roots"`
+	// ResourcesDir 技能资源文件在项目中的安装目录（相对项目根目录）
+	ResourcesDir string `mapstructure:"resources_dir"`
+	// ResourcesMode 技能资源文件的安装方式：copy 或 link
+	ResourcesMode string `mapstructure:"resources_mode"`
+	// TokenBudgets 按目标工具设置全部技能合计的token预算，0表示不检查
+	TokenBudgets map[string]int `mapstructure:"token_budgets"`
+	// SecretsCommand 解析变量中 ${secret:NAME} 引用的命令，密钥名称作为最后一个参数传入
+	SecretsCommand string `mapstructure:"secrets_command"`
+	// TemplateEnv 技能内容中的 {{env "NAME"}} 允许读取的环境变量，未列出的环境变量不能读取
+	TemplateEnv []string `mapstructure:"template_env"`
+	// Locale 技能提示词的默认语言，例如 zh-CN，技能提供 SKILL.<语言>.md 时使用；项目可以单独设置
+	Locale string `mapstructure:"locale"`
+	// Forges search 和 import 使用的代码托管平台，未设置时使用 github.com 和 gitlab.com
+	Forges []Forge `mapstructure:"forges"`
+	// RegistryURL 静态HTTP技能注册表的地址，设置后 update、import、search 和 outdated 通过它获取技能，不需要git
+	RegistryURL string `mapstructure:"registry_url"`
+	// RegistryToken 访问HTTP技能注册表的token，以 Bearer 方式发送
+	RegistryToken string `mapstructure:"registry_token"`
+	// RegistryMirrors HTTP技能注册表的镜像地址，目录结构与 registry_url 相同，主地址不可用时按顺序尝试
+	RegistryMirrors []string `mapstructure:"registry_mirrors"`
+	// Channel 技能仓库订阅的发布通道：stable（默认）或 beta，update 只更新到该通道发布的版本
+	Channel string `mapstructure:"channel"`
+	// SkillChannels 按技能设置订阅的发布通道，覆盖 channel
+	SkillChannels map[string]string `mapstructure:"skill_channels"`
+	// UpdateCheck 执行命令时检查技能是否有更新并提示，默认关闭
+	UpdateCheck bool `mapstructure:"update_check"`
+	// UpdateCheckInterval 两次检查的最小间隔，例如 24h、12h，默认 24h
+	UpdateCheckInterval string `mapstructure:"update_check_interval"`
+	// Offline 离线模式，与 --offline 相同：search、update 和 import 只使用缓存的注册表索引和技能归档
+	Offline bool `mapstructure:"offline"`
+}
+
+// DefaultUpdateCheckInterval 两次检查技能更新的默认间隔
+const DefaultUpdateCheckInterval = 24 * time.Hour
+
+// RepoRootName 技能仓库对应的技能目录名称
+const RepoRootName = "repo"
+
+// SkillRoot 技能目录。多个目录中存在同名技能时，优先级高的目录覆盖优先级低的目录
+type SkillRoot struct {
+	Name string `mapstructure:"name" json:"name"`
+	Path string `mapstructure:"path" json:"path"`
+}
+
+// 代码托管平台类型
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+	ForgeGitea  = "gitea"
+)
+
+// Forge 代码托管平台，可以是自建的 GitLab 或 Gitea 实例
+type Forge struct {
+	Name  string `mapstructure:"name" json:"name"`
+	Type  string `mapstructure:"type" json:"type"`
+	URL   string `mapstructure:"url" json:"url"`
+	Token string `mapstructure:"token" json:"-"`
+}
+
+// defaultForges 未配置 forges 时使用的公共平台
+var defaultForges = []Forge{
+	{Name: ForgeGitHub, Type: ForgeGitHub, URL: "https://github.com"},
+	{Name: ForgeGitLab, Type: ForgeGitLab, URL: "https://gitlab.com"},
 }
 
 var (
@@ -60,6 +127,8 @@ func LoadConfig() error {
 	viper.SetDefault("git_remote_url", "")
 	viper.SetDefault("git_token", "")
 	viper.SetDefault("git_branch", "main")
+	viper.SetDefault("resources_dir", ".skill-hub/resources")
+	viper.SetDefault("resources_mode", "copy")
 
 	if err := viper.ReadInConfig(); err != nil {
 		return fmt.Errorf("读取配置文件失败: %w", err)
@@ -104,6 +173,15 @@ func GetSkillsDir() (string, error) {
 	return filepath.Join(repoPath, "skills"), nil
 }
 
+// GetBundlesPath 获取技能组合定义文件路径
+func GetBundlesPath() (string, error) {
+	repoPath, err := GetRepoPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(repoPath, "bundles.yaml"), nil
+}
+
 // GetRegistryPath 获取索引文件路径
 func GetRegistryPath() (string, error) {
 	repoPath, err := GetRepoPath()
@@ -112,3 +190,190 @@ func GetRegistryPath() (string, error) {
 	}
 	return filepath.Join(repoPath, "registry.json"), nil
 }
+
+// GetCredentialsPath 获取 'skill-hub login' 保存的凭据文件路径
+func GetCredentialsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return filepath.Join(homeDir, ".skill-hub", "credentials.yaml"), nil
+}
+
+// GetCacheDir 获取缓存目录路径，缓存可以随时删除
+func GetCacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return filepath.Join(homeDir, ".skill-hub", "cache"), nil
+}
+
+// GetConfigPath 获取配置文件路径
+func GetConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return filepath.Join(homeDir, ".skill-hub", "config.yaml"), nil
+}
+
+// GetHeldSkillsPath 获取 'skill-hub update <技能ID>' 时保持在当前版本的技能列表文件路径
+func GetHeldSkillsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return filepath.Join(homeDir, ".skill-hub", "held.json"), nil
+}
+
+// GetUpdateRollbackPath 获取上次更新前技能仓库状态的保存目录，用于 'skill-hub update --rollback'
+func GetUpdateRollbackPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return filepath.Join(homeDir, ".skill-hub", "rollback"), nil
+}
+
+// GetUpdateCheckPath 获取上次检查技能更新的结果文件路径
+func GetUpdateCheckPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return filepath.Join(homeDir, ".skill-hub", "update-check.json"), nil
+}
+
+// GetUpdateCheckInterval 获取两次检查技能更新的最小间隔，未设置时为一天
+func GetUpdateCheckInterval() (time.Duration, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(cfg.UpdateCheckInterval) == "" {
+		return DefaultUpdateCheckInterval, nil
+	}
+	interval, err := time.ParseDuration(strings.TrimSpace(cfg.UpdateCheckInterval))
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("update_check_interval 无效: %q，格式如 24h、12h", cfg.UpdateCheckInterval)
+	}
+	return interval, nil
+}
+
+// GetVersionsDir 获取已安装技能版本的存储目录，布局为 <技能ID>/<版本>/，与技能仓库分开存放
+func GetVersionsDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return filepath.Join(homeDir, ".skill-hub", "versions"), nil
+}
+
+// GetSkillRoots 获取按优先级从高到低排列的技能目录
+//
+// 技能仓库的 skills 目录始终包含在内：配置中名为 repo 的条目决定它的位置，
+// 没有该条目时排在最后。相对路径相对于当前目录解析，用于项目内的技能目录。
+func GetSkillRoots() ([]SkillRoot, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	repoSkillsDir, err := GetSkillsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []SkillRoot
+	seen := make(map[string]bool)
+	hasRepo := false
+	for _, root := range cfg.SkillRoots {
+		path := expandPath(strings.TrimSpace(root.Path))
+		if root.Name == RepoRootName {
+			path = repoSkillsDir
+			hasRepo = true
+		}
+		if path == "" {
+			return nil, fmt.Errorf("技能目录 %s 缺少 path", root.Name)
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		// 同一目录只保留优先级最高的一次
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		name := root.Name
+		if name == "" {
+			name = filepath.Base(path)
+		}
+		roots = append(roots, SkillRoot{Name: name, Path: path})
+	}
+	if !hasRepo && !seen[repoSkillsDir] {
+		roots = append(roots, SkillRoot{Name: RepoRootName, Path: repoSkillsDir})
+	}
+	return roots, nil
+}
+
+// GetSkillChannel 获取技能订阅的发布通道：skill_channels 中的设置优先，其次为 channel，未设置时为 stable
+func GetSkillChannel(skillID string) (string, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		return "", err
+	}
+	if value, ok := cfg.SkillChannels[skillID]; ok {
+		channel, err := spec.NormalizeChannel(value)
+		if err != nil {
+			return "", fmt.Errorf("skill_channels 中技能 %s 的发布通道无效: %w", skillID, err)
+		}
+		return channel, nil
+	}
+	channel, err := spec.NormalizeChannel(cfg.Channel)
+	if err != nil {
+		return "", fmt.Errorf("配置中的 channel 无效: %w", err)
+	}
+	return channel, nil
+}
+
+// GetForges 获取配置的代码托管平台，未配置时返回 github.com 和 gitlab.com
+//
+// 名称为空时使用平台的主机名；未设置 token 的 GitHub 平台使用 git_token。
+func GetForges() ([]Forge, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	configured := cfg.Forges
+	if len(configured) == 0 {
+		configured = defaultForges
+	}
+
+	forges := make([]Forge, 0, len(configured))
+	seen := make(map[string]bool)
+	for _, forge := range configured {
+		forge.Type = strings.ToLower(strings.TrimSpace(forge.Type))
+		switch forge.Type {
+		case ForgeGitHub, ForgeGitLab, ForgeGitea:
+		default:
+			return nil, fmt.Errorf("代码托管平台 %s 的类型无效: %q，可用类型: github, gitlab, gitea", forge.Name, forge.Type)
+		}
+		forge.URL = strings.TrimRight(strings.TrimSpace(forge.URL), "/")
+		if !strings.HasPrefix(forge.URL, "https://") && !strings.HasPrefix(forge.URL, "http://") {
+			return nil, fmt.Errorf("代码托管平台 %s 的地址无效: %q，需要以 https:// 或 http:// 开头", forge.Name, forge.URL)
+		}
+		if forge.Name == "" {
+			forge.Name = strings.SplitN(strings.SplitN(forge.URL, "://", 2)[1], "/", 2)[0]
+		}
+		if seen[forge.Name] {
+			return nil, fmt.Errorf("代码托管平台名称重复: %s", forge.Name)
+		}
+		seen[forge.Name] = true
+		if forge.Token == "" && forge.Type == ForgeGitHub {
+			forge.Token = cfg.GitToken
+		}
+		forges = append(forges, forge)
+	}
+	return forges, nil
+}
@@ -0,0 +1,233 @@
+// Package credentials 管理访问私有仓库的凭据
+//
+// 按主机查找凭据，来源依次为：
+//  1. 环境变量 SKILL_HUB_TOKEN_<主机>，主机名转为大写，非字母数字替换为下划线，
+//     例如 SKILL_HUB_TOKEN_GITLAB_EXAMPLE_COM
+//  2. 'skill-hub login' 保存在系统钥匙串（macOS security、Linux secret-tool）中的令牌
+//  3. 'skill-hub login --store file' 保存在 ~/.skill-hub/credentials.yaml 中的令牌
+package credentials
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/internal/config"
+)
+
+// 凭据的来源
+const (
+	SourceEnv      = "env"
+	SourceKeychain = "keychain"
+	SourceFile     = "file"
+)
+
+// EnvPrefix 按主机设置令牌的环境变量前缀
+const EnvPrefix = "SKILL_HUB_TOKEN_"
+
+// Credential 主机的凭据
+type Credential struct {
+	Host     string `json:"host"`
+	Username string `json:"username,omitempty"`
+	Token    string `json:"-"`
+	Source   string `json:"source"`
+}
+
+// entry 凭据文件中的主机条目；令牌保存在钥匙串中时 Token 为空
+type entry struct {
+	Username string `yaml:"username,omitempty"`
+	Token    string `yaml:"token,omitempty"`
+	Keychain bool   `yaml:"keychain,omitempty"`
+}
+
+// file 凭据文件
+type file struct {
+	Hosts map[string]entry `yaml:"hosts"`
+}
+
+// EnvName 返回主机对应的环境变量名
+func EnvName(host string) string {
+	var b strings.Builder
+	b.WriteString(EnvPrefix)
+	for _, r := range strings.ToUpper(host) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// NormalizeHost 从主机名或URL中取出主机（包含端口），统一为小写
+func NormalizeHost(hostOrURL string) string {
+	s := strings.TrimSpace(hostOrURL)
+	if strings.Contains(s, "://") {
+		if u, err := url.Parse(s); err == nil {
+			return strings.ToLower(u.Host)
+		}
+	}
+	// git@host:owner/repo 形式
+	if at := strings.Index(s, "@"); at >= 0 && !strings.Contains(s[:at], "/") {
+		s = s[at+1:]
+		if colon := strings.Index(s, ":"); colon >= 0 {
+			s = s[:colon]
+		}
+	}
+	if slash := strings.Index(s, "/"); slash >= 0 {
+		s = s[:slash]
+	}
+	return strings.ToLower(s)
+}
+
+// Lookup 查找主机的凭据，hostOrURL 可以是主机名或仓库地址；没有凭据时返回 false
+func Lookup(hostOrURL string) (Credential, bool, error) {
+	host := NormalizeHost(hostOrURL)
+	if host == "" {
+		return Credential{}, false, nil
+	}
+	if token := os.Getenv(EnvName(host)); token != "" {
+		return Credential{Host: host, Token: token, Source: SourceEnv}, true, nil
+	}
+
+	f, err := load()
+	if err != nil {
+		return Credential{}, false, err
+	}
+	e, ok := f.Hosts[host]
+	if !ok {
+		return Credential{}, false, nil
+	}
+	if !e.Keychain {
+		return Credential{Host: host, Username: e.Username, Token: e.Token, Source: SourceFile}, e.Token != "", nil
+	}
+	token, err := keychainGet(host)
+	if err != nil {
+		return Credential{}, false, fmt.Errorf("从系统钥匙串读取 %s 的令牌失败: %w", host, err)
+	}
+	return Credential{Host: host, Username: e.Username, Token: token, Source: SourceKeychain}, token != "", nil
+}
+
+// Token 返回主机的令牌，没有凭据或读取失败时返回空字符串，用于可选的认证
+func Token(hostOrURL string) string {
+	cred, ok, err := Lookup(hostOrURL)
+	if err != nil || !ok {
+		return ""
+	}
+	return cred.Token
+}
+
+// Save 保存主机的凭据。useKeychain 为 true 时令牌保存在系统钥匙串中，凭据文件只记录用户名
+func Save(cred Credential, useKeychain bool) error {
+	host := NormalizeHost(cred.Host)
+	if host == "" {
+		return fmt.Errorf("主机不能为空")
+	}
+	if cred.Token == "" {
+		return fmt.Errorf("令牌不能为空")
+	}
+
+	f, err := load()
+	if err != nil {
+		return err
+	}
+	e := entry{Username: cred.Username}
+	if useKeychain {
+		if err := keychainSet(host, cred.Token); err != nil {
+			return fmt.Errorf("保存令牌到系统钥匙串失败: %w", err)
+		}
+		e.Keychain = true
+	} else {
+		// 之前保存在钥匙串中的令牌不再使用
+		if old, ok := f.Hosts[host]; ok && old.Keychain {
+			keychainDelete(host)
+		}
+		e.Token = cred.Token
+	}
+	f.Hosts[host] = e
+	return save(f)
+}
+
+// Delete 删除主机的凭据，不存在时返回 false
+func Delete(hostOrURL string) (bool, error) {
+	host := NormalizeHost(hostOrURL)
+	f, err := load()
+	if err != nil {
+		return false, err
+	}
+	e, ok := f.Hosts[host]
+	if !ok {
+		return false, nil
+	}
+	if e.Keychain {
+		if err := keychainDelete(host); err != nil {
+			return false, fmt.Errorf("从系统钥匙串删除令牌失败: %w", err)
+		}
+	}
+	delete(f.Hosts, host)
+	return true, save(f)
+}
+
+// List 列出已登录的主机（不包含令牌），按主机排序
+func List() ([]Credential, error) {
+	f, err := load()
+	if err != nil {
+		return nil, err
+	}
+	creds := make([]Credential, 0, len(f.Hosts))
+	for host, e := range f.Hosts {
+		source := SourceFile
+		if e.Keychain {
+			source = SourceKeychain
+		}
+		creds = append(creds, Credential{Host: host, Username: e.Username, Source: source})
+	}
+	sort.Slice(creds, func(i, j int) bool { return creds[i].Host < creds[j].Host })
+	return creds, nil
+}
+
+// load 读取凭据文件，不存在时返回空文件
+func load() (*file, error) {
+	path, err := config.GetCredentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	f := &file{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取凭据文件失败: %w", err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, f); err != nil {
+			return nil, fmt.Errorf("解析凭据文件失败: %w", err)
+		}
+	}
+	if f.Hosts == nil {
+		f.Hosts = make(map[string]entry)
+	}
+	return f, nil
+}
+
+// save 写入凭据文件，只有当前用户可读写
+func save(f *file) error {
+	path, err := config.GetCredentialsPath()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("序列化凭据失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("写入凭据文件失败: %w", err)
+	}
+	// 文件已存在时 WriteFile 不修改权限
+	return os.Chmod(path, 0600)
+}
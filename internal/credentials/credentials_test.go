@@ -0,0 +1,147 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	for input, want := range map[string]string{
+		"GitLab.example.com":                   "gitlab.example.com",
+		"https://github.com/acme/skills.git":   "github.com",
+		"http://localhost:5000/v2/":            "localhost:5000",
+		"git@gitlab.example.com:acme/skills":   "gitlab.example.com",
+		"ghcr.io/acme/skills/git-expert:1.0.0": "ghcr.io",
+		"":                                     "",
+	} {
+		if got := NormalizeHost(input); got != want {
+			t.Errorf("NormalizeHost(%q) = %q, want %q", input, got, want)
+		}
+	}
+	if got := EnvName("gitlab.example.com:8443"); got != "SKILL_HUB_TOKEN_GITLAB_EXAMPLE_COM_8443" {
+		t.Errorf("EnvName() = %q", got)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if _, ok, err := Lookup("github.com"); err != nil || ok {
+		t.Fatalf("Lookup() without credentials = %v, %v", ok, err)
+	}
+	if err := Save(Credential{Host: "https://GitLab.example.com/acme", Username: "alice", Token: "glpat-1"}, false); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	cred, ok, err := Lookup("https://gitlab.example.com/acme/skills.git")
+	if err != nil || !ok {
+		t.Fatalf("Lookup() = %v, %v", ok, err)
+	}
+	if cred.Host != "gitlab.example.com" || cred.Username != "alice" || cred.Token != "glpat-1" || cred.Source != SourceFile {
+		t.Errorf("Lookup() = %+v", cred)
+	}
+	info, err := os.Stat(filepath.Join(home, ".skill-hub", "credentials.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("credentials file permissions = %o, want 600", perm)
+	}
+
+	// 环境变量优先于保存的凭据
+	t.Setenv("SKILL_HUB_TOKEN_GITLAB_EXAMPLE_COM", "from-env")
+	if cred, _, _ := Lookup("gitlab.example.com"); cred.Token != "from-env" || cred.Source != SourceEnv {
+		t.Errorf("Lookup() with env = %+v", cred)
+	}
+	if got := Token("ghcr.io"); got != "" {
+		t.Errorf("Token() for unknown host = %q", got)
+	}
+
+	list, err := List()
+	if err != nil || len(list) != 1 || list[0].Token != "" {
+		t.Errorf("List() = %+v, %v", list, err)
+	}
+	if removed, err := Delete("gitlab.example.com"); err != nil || !removed {
+		t.Errorf("Delete() = %v, %v", removed, err)
+	}
+	if removed, err := Delete("gitlab.example.com"); err != nil || removed {
+		t.Errorf("Delete() of missing host = %v, %v", removed, err)
+	}
+}
+
+func TestKeychainCredentials(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// 用脚本模拟 secret-tool，条目保存在临时目录中
+	bin := t.TempDir()
+	store := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+store) cat > "` + store + `/$7" ;;
+lookup) cat "` + store + `/$5" 2>/dev/null || exit 1 ;;
+clear) rm -f "` + store + `/$5" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "secret-tool"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	original := keychainCommand
+	keychainCommand = func() string { return "secret-tool" }
+	defer func() { keychainCommand = original }()
+
+	if err := Save(Credential{Host: "ghcr.io", Token: "ghp-secret"}, true); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	cred, ok, err := Lookup("ghcr.io/acme/skills")
+	if err != nil || !ok || cred.Token != "ghp-secret" || cred.Source != SourceKeychain {
+		t.Fatalf("Lookup() = %+v, %v, %v", cred, ok, err)
+	}
+	// 令牌不写入凭据文件
+	data, err := os.ReadFile(filepath.Join(os.Getenv("HOME"), ".skill-hub", "credentials.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "ghp-secret") {
+		t.Errorf("credentials file = %q", data)
+	}
+
+	if removed, err := Delete("ghcr.io"); err != nil || !removed {
+		t.Fatalf("Delete() = %v, %v", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(store, "ghcr.io")); !os.IsNotExist(err) {
+		t.Errorf("keychain entry not removed, stat error = %v", err)
+	}
+}
+
+func TestKeychainSetSecurity(t *testing.T) {
+	// 用脚本模拟 macOS security，记录参数和标准输入
+	bin := t.TempDir()
+	out := t.TempDir()
+	script := `#!/bin/sh
+echo "$@" > "` + out + `/args"
+cat > "` + out + `/stdin"
+`
+	if err := os.WriteFile(filepath.Join(bin, "security"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	original := keychainCommand
+	keychainCommand = func() string { return "security" }
+	defer func() { keychainCommand = original }()
+
+	if err := keychainSet("ghcr.io", "ghp-secret"); err != nil {
+		t.Fatalf("keychainSet() error = %v", err)
+	}
+	args, _ := os.ReadFile(filepath.Join(out, "args"))
+	if strings.Contains(string(args), "ghp-secret") || !strings.HasSuffix(strings.TrimSpace(string(args)), "-w") {
+		t.Errorf("security args = %q, token must not be passed as an argument", args)
+	}
+	stdin, _ := os.ReadFile(filepath.Join(out, "stdin"))
+	if string(stdin) != "ghp-secret\nghp-secret\n" {
+		t.Errorf("security stdin = %q", stdin)
+	}
+}
@@ -0,0 +1,96 @@
+package credentials
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainService 系统钥匙串中条目的服务名称
+const keychainService = "skill-hub"
+
+// keychainCommand 返回访问系统钥匙串的命令：macOS 使用 security，Linux 使用 libsecret 的 secret-tool
+var keychainCommand = func() string {
+	var name string
+	switch runtime.GOOS {
+	case "darwin":
+		name = "security"
+	case "linux", "freebsd", "openbsd":
+		name = "secret-tool"
+	default:
+		return ""
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// KeychainAvailable 检查当前系统是否可以使用系统钥匙串保存令牌
+func KeychainAvailable() bool {
+	return keychainCommand() != ""
+}
+
+// keychainSet 保存令牌到系统钥匙串，已存在时覆盖
+func keychainSet(host, token string) error {
+	switch keychainCommand() {
+	case "security":
+		// -w 放在最后且不带值时 security 从标准输入读取令牌（输入两次确认），令牌不出现在进程参数中
+		return runKeychain(token+"\n"+token+"\n", "security", "add-generic-password", "-U", "-s", keychainService, "-a", host, "-w")
+	case "secret-tool":
+		// 令牌通过标准输入传递，不出现在进程参数中
+		return runKeychain(token, "secret-tool", "store", "--label", keychainService+" "+host, "service", keychainService, "host", host)
+	}
+	return fmt.Errorf("当前系统没有可用的钥匙串工具（macOS security 或 Linux secret-tool）")
+}
+
+// keychainGet 从系统钥匙串读取令牌，不存在时返回空字符串
+func keychainGet(host string) (string, error) {
+	var cmd *exec.Cmd
+	switch keychainCommand() {
+	case "security":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", host, "-w")
+	case "secret-tool":
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "host", host)
+	default:
+		return "", fmt.Errorf("当前系统没有可用的钥匙串工具")
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		// 条目不存在时两个工具都以非零状态退出
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// keychainDelete 从系统钥匙串删除令牌
+func keychainDelete(host string) error {
+	switch keychainCommand() {
+	case "security":
+		return runKeychain("", "security", "delete-generic-password", "-s", keychainService, "-a", host)
+	case "secret-tool":
+		return runKeychain("", "secret-tool", "clear", "service", keychainService, "host", host)
+	}
+	return fmt.Errorf("当前系统没有可用的钥匙串工具")
+}
+
+// runKeychain 执行钥匙串命令，失败时返回命令的错误输出
+func runKeychain(stdin, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s", name, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
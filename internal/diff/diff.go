@@ -0,0 +1,264 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OpKind 表示差异操作类型
+type OpKind int
+
+const (
+	// OpEqual 两侧相同的行
+	OpEqual OpKind = iota
+	// OpDelete 仅存在于旧内容的行
+	OpDelete
+	// OpInsert 仅存在于新内容的行
+	OpInsert
+)
+
+// Line 表示差异结果中的一行
+type Line struct {
+	Kind OpKind
+	Text string
+}
+
+// DefaultContext 统一差异格式默认的上下文行数
+const DefaultContext = 3
+
+// Lines 计算两段文本的逐行差异
+func Lines(a, b string) []Line {
+	return compare(splitLines(a), splitLines(b))
+}
+
+// compare 使用 Myers 差异算法计算两组记号（行或词）的最短编辑脚本，
+// 结果为最长公共子序列之外的删除和新增，同一处修改中删除在前、新增在后
+func compare(a, b []string) []Line {
+	// 去掉相同的前缀和后缀，只对中间部分计算编辑脚本
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var result []Line
+	for _, text := range a[:prefix] {
+		result = append(result, Line{Kind: OpEqual, Text: text})
+	}
+	result = append(result, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		result = append(result, Line{Kind: OpEqual, Text: text})
+	}
+	return result
+}
+
+// myers 按 Myers 的 O(ND) 算法逐步扩展编辑距离 d，记录每一步各对角线到达的最远位置，
+// 到达终点后回溯得到编辑脚本
+func myers(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	// v[offset+k] 为对角线 k（x-y）上到达的最远 x
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] 为第 d 步开始前 v 中对角线 -d-1 到 d+1 的值
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack 从终点沿 trace 回溯，逆序生成编辑脚本
+func backtrack(a, b []string, trace [][]int) []Line {
+	var reversed []Line
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d]
+		at := func(k int) int { return prev[k+d+1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Line{Kind: OpEqual, Text: a[x]})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, Line{Kind: OpInsert, Text: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, Line{Kind: OpDelete, Text: a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, Line{Kind: OpEqual, Text: a[x]})
+	}
+
+	result := make([]Line, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		result = append(result, reversed[i])
+	}
+	return result
+}
+
+// Unified 生成统一差异格式（unified diff）文本，内容相同时返回空字符串
+func Unified(fromName, toName, a, b string, context int) string {
+	lines := Lines(a, b)
+
+	changed := false
+	for _, line := range lines {
+		if line.Kind != OpEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- %s\n", fromName))
+	sb.WriteString(fmt.Sprintf("+++ %s\n", toName))
+
+	for _, h := range buildHunks(lines, context) {
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", formatRange(h.aStart, h.aCount), formatRange(h.bStart, h.bCount)))
+		for _, line := range h.lines {
+			switch line.Kind {
+			case OpEqual:
+				sb.WriteString(" ")
+			case OpDelete:
+				sb.WriteString("-")
+			case OpInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(line.Text)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// hunk 表示统一差异格式中的一个差异块
+type hunk struct {
+	aStart, aCount int
+	bStart, bCount int
+	lines          []Line
+}
+
+// buildHunks 将逐行差异按上下文行数分组为差异块
+func buildHunks(lines []Line, context int) []hunk {
+	if context < 0 {
+		context = 0
+	}
+
+	// 预先计算每一行在两侧的起始行号
+	aPos := make([]int, len(lines)+1)
+	bPos := make([]int, len(lines)+1)
+	aPos[0], bPos[0] = 1, 1
+	for idx, line := range lines {
+		aPos[idx+1], bPos[idx+1] = aPos[idx], bPos[idx]
+		if line.Kind != OpInsert {
+			aPos[idx+1]++
+		}
+		if line.Kind != OpDelete {
+			bPos[idx+1]++
+		}
+	}
+
+	var hunks []hunk
+	idx := 0
+	for idx < len(lines) {
+		if lines[idx].Kind == OpEqual {
+			idx++
+			continue
+		}
+
+		// 找到当前差异组的结束位置（相邻变更间隔不超过2倍上下文则合并）
+		last := idx
+		for k := idx + 1; k < len(lines); k++ {
+			if lines[k].Kind == OpEqual {
+				continue
+			}
+			if k-last > 2*context {
+				break
+			}
+			last = k
+		}
+
+		start := idx - context
+		if start < 0 {
+			start = 0
+		}
+		end := last + context + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		h := hunk{aStart: aPos[start], bStart: bPos[start]}
+		for _, line := range lines[start:end] {
+			h.lines = append(h.lines, line)
+			if line.Kind != OpInsert {
+				h.aCount++
+			}
+			if line.Kind != OpDelete {
+				h.bCount++
+			}
+		}
+		hunks = append(hunks, h)
+		idx = end
+	}
+
+	return hunks
+}
+
+// formatRange 格式化差异块的行号范围
+func formatRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines 将文本拆分为行（忽略末尾换行）
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
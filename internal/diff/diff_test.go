@@ -0,0 +1,179 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		contains []string
+		empty    bool
+	}{
+		{
+			name:  "相同内容",
+			a:     "line1\nline2\n",
+			b:     "line1\nline2\n",
+			empty: true,
+		},
+		{
+			name:     "新建文件",
+			a:        "",
+			b:        "line1\nline2\n",
+			contains: []string{"@@ -0,0 +1,2 @@", "+line1", "+line2"},
+		},
+		{
+			name:     "修改中间行",
+			a:        "a\nb\nc\n",
+			b:        "a\nx\nc\n",
+			contains: []string{"@@ -1,3 +1,3 @@", " a", "-b", "+x", " c"},
+		},
+		{
+			name:     "相距较远的变更拆分为多个差异块",
+			a:        "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:        "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			contains: []string{"@@ -1,4 +1,4 @@", "@@ -7,4 +7,4 @@", "-1", "+one", "-10", "+ten"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("old", "new", tt.a, tt.b, DefaultContext)
+			if tt.empty {
+				if got != "" {
+					t.Errorf("Unified() = %q, want empty", got)
+				}
+				return
+			}
+			if !strings.HasPrefix(got, "--- old\n+++ new\n") {
+				t.Errorf("Unified() missing header: %q", got)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want+"\n") {
+					t.Errorf("Unified() = %q, want to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      string
+		ours      string
+		theirs    string
+		want      string
+		conflicts int
+	}{
+		{
+			name:   "只有一侧修改",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nB\nc\n",
+			want:   "a\nB\nc\n",
+		},
+		{
+			name:   "两侧修改不同的区域",
+			base:   "title\n\nrule 1\nrule 2\nrule 3\n\nfooter\n",
+			ours:   "title\n\nrule 1\nrule 2 (local)\nrule 3\n\nfooter\n",
+			theirs: "title v2\n\nrule 1\nrule 2\nrule 3\nrule 4\n\nfooter\n",
+			want:   "title v2\n\nrule 1\nrule 2 (local)\nrule 3\nrule 4\n\nfooter\n",
+		},
+		{
+			name:   "两侧相同的修改",
+			base:   "a\nb\n",
+			ours:   "a\nx\n",
+			theirs: "a\nx\n",
+			want:   "a\nx\n",
+		},
+		{
+			name:   "一侧删除另一侧未改",
+			base:   "a\nb\nc\n",
+			ours:   "a\nc\n",
+			theirs: "a\nb\nc\nd\n",
+			want:   "a\nc\nd\n",
+		},
+		{
+			name:      "两侧修改同一行",
+			base:      "a\nb\nc\n",
+			ours:      "a\nlocal\nc\n",
+			theirs:    "a\nupstream\nc\n",
+			want:      "a\n<<<<<<< ours\nlocal\n=======\nupstream\n>>>>>>> theirs\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "两侧在末尾追加不同内容",
+			base:      "a\n",
+			ours:      "a\nlocal\n",
+			theirs:    "a\nupstream\n",
+			want:      "a\n<<<<<<< ours\nlocal\n=======\nupstream\n>>>>>>> theirs\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge3(tt.base, tt.ours, tt.theirs, "ours", "theirs")
+			if got != tt.want || conflicts != tt.conflicts {
+				t.Errorf("Merge3() = %q, %d; want %q, %d", got, conflicts, tt.want, tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestLinesMinimal(t *testing.T) {
+	got := Lines("a\nb\nc\nd\n", "a\nx\nc\nd\ny\n")
+	want := []Line{
+		{OpEqual, "a"}, {OpDelete, "b"}, {OpInsert, "x"}, {OpEqual, "c"}, {OpEqual, "d"}, {OpInsert, "y"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Lines() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Lines() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"run go test ./...", "run go test -race ./...", "run go test {+-race +}./..."},
+		{"use tabs", "use spaces", "use [-tabs-]{+spaces+}"},
+		{"使用中文说明", "使用英文说明", "使用[-中-]{+英+}文说明"},
+	}
+	for _, tt := range tests {
+		if got := WordDiff(tt.a, tt.b, false); got != tt.want {
+			t.Errorf("WordDiff(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	unified := Unified("old", "new", "-- a\nkeep\nuse tabs\n", "keep\nuse spaces\nextra\n", DefaultContext)
+
+	if got := Format(unified, FormatOptions{}); got != unified {
+		t.Errorf("Format() without options = %q, want unchanged", got)
+	}
+
+	words := Format(unified, FormatOptions{Words: true})
+	want := "--- old\n+++ new\n@@ -1,3 +1,3 @@\n[--- a-]\nkeep\nuse [-tabs-]{+spaces+}\n{+extra+}\n"
+	if words != want {
+		t.Errorf("Format() words = %q, want %q", words, want)
+	}
+
+	colored := Format(unified, FormatOptions{Color: true})
+	for _, line := range []string{colorBold + "--- old" + colorReset, colorCyan + "@@ -1,3 +1,3 @@" + colorReset,
+		colorRed + "--- a" + colorReset, colorGreen + "+extra" + colorReset, " keep"} {
+		if !strings.Contains(colored, line+"\n") {
+			t.Errorf("Format() color = %q, want to contain %q", colored, line)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package diff
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ANSI 颜色，与 git 的默认配色一致
+const (
+	colorBold  = "\033[1m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+	colorReset = "\033[m"
+)
+
+// FormatOptions 控制差异文本的显示方式
+type FormatOptions struct {
+	Color bool // 使用 ANSI 颜色
+	Words bool // 按词显示修改的行，与 git diff --word-diff 相同
+}
+
+// Format 按选项重新格式化 Unified 生成的统一差异文本
+//
+// 按词显示时，差异块中相邻的删除行和新增行逐行配对，合并为一行并标出修改的词：
+// 不使用颜色时删除的词写作 [-词-]，新增的词写作 {+词+}；使用颜色时只用颜色区分。
+// 没有配对的删除行和新增行整行标出，未修改的行不带前缀。
+func Format(unified string, opts FormatOptions) string {
+	if unified == "" || (!opts.Color && !opts.Words) {
+		return unified
+	}
+
+	var sb strings.Builder
+	var deleted, inserted []string
+	flush := func() {
+		if opts.Words {
+			for i := 0; i < len(deleted) || i < len(inserted); i++ {
+				switch {
+				case i >= len(inserted):
+					sb.WriteString(wordSpan(OpDelete, deleted[i], opts.Color))
+				case i >= len(deleted):
+					sb.WriteString(wordSpan(OpInsert, inserted[i], opts.Color))
+				default:
+					sb.WriteString(WordDiff(deleted[i], inserted[i], opts.Color))
+				}
+				sb.WriteString("\n")
+			}
+		} else {
+			for _, line := range deleted {
+				sb.WriteString(colorRed + "-" + line + colorReset + "\n")
+			}
+			for _, line := range inserted {
+				sb.WriteString(colorGreen + "+" + line + colorReset + "\n")
+			}
+		}
+		deleted, inserted = nil, nil
+	}
+
+	// oldLeft 和 newLeft 为当前差异块中尚未读取的修改前和修改后的行数，块内的行都是内容行
+	oldLeft, newLeft := 0, 0
+	for _, line := range strings.Split(strings.TrimSuffix(unified, "\n"), "\n") {
+		if oldLeft <= 0 && newLeft <= 0 {
+			flush()
+			if strings.HasPrefix(line, "@@") {
+				oldLeft, newLeft = hunkCounts(line)
+				if opts.Color {
+					line = colorCyan + line + colorReset
+				}
+			} else if opts.Color {
+				line = colorBold + line + colorReset
+			}
+			sb.WriteString(line + "\n")
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "-"):
+			// 删除行在新增行之前，遇到新的删除行说明上一处修改已结束
+			if len(inserted) > 0 {
+				flush()
+			}
+			deleted = append(deleted, line[1:])
+			oldLeft--
+		case strings.HasPrefix(line, "+"):
+			inserted = append(inserted, line[1:])
+			newLeft--
+		default:
+			flush()
+			if opts.Words {
+				line = strings.TrimPrefix(line, " ")
+			}
+			sb.WriteString(line + "\n")
+			oldLeft--
+			newLeft--
+		}
+	}
+	flush()
+	return sb.String()
+}
+
+// hunkCounts 解析差异块头 @@ -a,b +c,d @@ 中修改前和修改后的行数，省略时为1
+func hunkCounts(header string) (int, int) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 0, 0
+	}
+	return rangeCount(fields[1]), rangeCount(fields[2])
+}
+
+// rangeCount 返回行号范围 -a,b 或 +c,d 中的行数
+func rangeCount(field string) int {
+	_, count, found := strings.Cut(field[1:], ",")
+	if !found {
+		return 1
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// WordDiff 按词比较修改前后的一行，返回标出删除和新增的词的一行
+func WordDiff(a, b string, color bool) string {
+	var sb strings.Builder
+	var kind OpKind
+	var run []string
+	emit := func() {
+		if len(run) > 0 {
+			sb.WriteString(wordSpan(kind, strings.Join(run, ""), color))
+		}
+		run = nil
+	}
+	for _, token := range compare(splitWords(a), splitWords(b)) {
+		if token.Kind != kind {
+			emit()
+			kind = token.Kind
+		}
+		run = append(run, token.Text)
+	}
+	emit()
+	return sb.String()
+}
+
+// wordSpan 按操作类型标出一段文本
+func wordSpan(kind OpKind, text string, color bool) string {
+	switch {
+	case kind == OpDelete && color:
+		return colorRed + text + colorReset
+	case kind == OpDelete:
+		return "[-" + text + "-]"
+	case kind == OpInsert && color:
+		return colorGreen + text + colorReset
+	case kind == OpInsert:
+		return "{+" + text + "+}"
+	}
+	return text
+}
+
+// splitWords 将一行拆分为词：连续的字母、数字和下划线，连续的空白，其余字符（包括中文）各为一个词
+func splitWords(line string) []string {
+	var words []string
+	for len(line) > 0 {
+		r, size := utf8.DecodeRuneInString(line)
+		end := size
+		switch {
+		case isWordRune(r):
+			for end < len(line) {
+				next, n := utf8.DecodeRuneInString(line[end:])
+				if !isWordRune(next) {
+					break
+				}
+				end += n
+			}
+		case unicode.IsSpace(r):
+			for end < len(line) {
+				next, n := utf8.DecodeRuneInString(line[end:])
+				if !unicode.IsSpace(next) {
+					break
+				}
+				end += n
+			}
+		}
+		words = append(words, line[:end])
+		line = line[end:]
+	}
+	return words
+}
+
+// isWordRune 检查字符是否属于拼音文字的词，中日韩文字逐字比较
+func isWordRune(r rune) bool {
+	if r == '_' {
+		return true
+	}
+	if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,99 @@
+package diff
+
+import "strings"
+
+// 冲突标记，与 git 的格式一致
+const (
+	ConflictStart     = "<<<<<<<"
+	ConflictSeparator = "======="
+	ConflictEnd       = ">>>>>>>"
+)
+
+// Merge3 对文本进行三方合并：base 为共同祖先，ours 和 theirs 为两侧的修改
+//
+// 只有一侧修改的区域采用该侧的内容，两侧修改相同时采用一次；两侧修改不同时写入冲突标记，
+// ours 在前、theirs 在后，标记后附带 oursLabel 和 theirsLabel。返回合并结果和冲突数。
+func Merge3(base, ours, theirs, oursLabel, theirsLabel string) (string, int) {
+	baseLines := splitLines(base)
+	oursLines := splitLines(ours)
+	theirsLines := splitLines(theirs)
+	oursMatch := matchLines(baseLines, Lines(base, ours))
+	theirsMatch := matchLines(baseLines, Lines(base, theirs))
+
+	var result []string
+	conflicts := 0
+	i, j, k := 0, 0, 0
+	for {
+		// 找到下一个在三方中都存在的基准行，其前面是需要合并的区域
+		next := i
+		for next < len(baseLines) && (oursMatch[next] < 0 || theirsMatch[next] < 0) {
+			next++
+		}
+		nextOurs, nextTheirs := len(oursLines), len(theirsLines)
+		if next < len(baseLines) {
+			nextOurs, nextTheirs = oursMatch[next], theirsMatch[next]
+		}
+
+		baseChunk := baseLines[i:next]
+		oursChunk := oursLines[j:nextOurs]
+		theirsChunk := theirsLines[k:nextTheirs]
+		switch {
+		case equalLines(oursChunk, baseChunk):
+			result = append(result, theirsChunk...)
+		case equalLines(theirsChunk, baseChunk), equalLines(oursChunk, theirsChunk):
+			result = append(result, oursChunk...)
+		default:
+			conflicts++
+			result = append(result, ConflictStart+" "+oursLabel)
+			result = append(result, oursChunk...)
+			result = append(result, ConflictSeparator)
+			result = append(result, theirsChunk...)
+			result = append(result, ConflictEnd+" "+theirsLabel)
+		}
+
+		if next == len(baseLines) {
+			break
+		}
+		result = append(result, baseLines[next])
+		i, j, k = next+1, nextOurs+1, nextTheirs+1
+	}
+
+	merged := strings.Join(result, "\n")
+	if len(result) > 0 && (strings.HasSuffix(theirs, "\n") || strings.HasSuffix(ours, "\n")) {
+		merged += "\n"
+	}
+	return merged, conflicts
+}
+
+// matchLines 根据逐行差异返回每个基准行在另一侧对应的行号，被删除的行为 -1
+func matchLines(baseLines []string, lines []Line) []int {
+	match := make([]int, len(baseLines))
+	i, j := 0, 0
+	for _, line := range lines {
+		switch line.Kind {
+		case OpEqual:
+			match[i] = j
+			i++
+			j++
+		case OpDelete:
+			match[i] = -1
+			i++
+		case OpInsert:
+			j++
+		}
+	}
+	return match
+}
+
+// equalLines 检查两组行是否相同
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"sort"
+
+	"skill-hub/internal/config"
+	"skill-hub/pkg/promptlint"
+	"skill-hub/pkg/spec"
+)
+
+// DefaultTokenBudgets 各目标工具的默认token预算
+//
+// Cursor 和 Claude Code 将全部技能合并到同一个配置文件中，一起占用上下文；
+// 其他目标按需加载单个技能，默认不检查。
+var DefaultTokenBudgets = map[string]int{
+	spec.TargetCursor:     8000,
+	spec.TargetClaudeCode: 8000,
+}
+
+// SkillTokens 单个技能渲染后的token估算
+type SkillTokens struct {
+	SkillID string `json:"skill_id"`
+	Tokens  int    `json:"tokens"`
+}
+
+// TokenBudget 应用到同一目标工具的全部技能的token估算
+type TokenBudget struct {
+	Target string        `json:"target"`
+	Budget int           `json:"budget"` // 0表示不检查
+	Total  int           `json:"total"`
+	Skills []SkillTokens `json:"skills"` // 按token数从多到少排列
+}
+
+// Exceeded 检查技能合计是否超过预算
+func (b *TokenBudget) Exceeded() bool {
+	return b.Budget > 0 && b.Total > b.Budget
+}
+
+// TokenBudgetFor 返回目标工具的token预算：配置项 token_budgets 优先，其次为默认预算，0表示不检查
+func TokenBudgetFor(target string) int {
+	if cfg, err := config.GetConfig(); err == nil {
+		if budget, ok := cfg.TokenBudgets[target]; ok {
+			return budget
+		}
+	}
+	return DefaultTokenBudgets[target]
+}
+
+// EstimateTokenBudget 估算渲染后的技能内容合计占用的token，contents 为技能ID到渲染后内容的映射
+func EstimateTokenBudget(target string, contents map[string]string, budget int) *TokenBudget {
+	result := &TokenBudget{Target: target, Budget: budget, Skills: []SkillTokens{}}
+	for skillID, content := range contents {
+		tokens := promptlint.EstimateTokens(content)
+		result.Skills = append(result.Skills, SkillTokens{SkillID: skillID, Tokens: tokens})
+		result.Total += tokens
+	}
+	sort.Slice(result.Skills, func(i, j int) bool {
+		if result.Skills[i].Tokens != result.Skills[j].Tokens {
+			return result.Skills[i].Tokens > result.Skills[j].Tokens
+		}
+		return result.Skills[i].SkillID < result.Skills[j].SkillID
+	})
+	return result
+}
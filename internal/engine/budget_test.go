@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestEstimateTokenBudget(t *testing.T) {
+	contents := map[string]string{
+		"small": "abcd",                      // 1 token
+		"large": strings.Repeat("word ", 20), // 25 tokens
+		"cjk":   "你好世界",                      // 4 tokens
+	}
+
+	budget := EstimateTokenBudget(spec.TargetCursor, contents, 20)
+	if budget.Total != 30 {
+		t.Errorf("Total = %d, want 30", budget.Total)
+	}
+	if !budget.Exceeded() {
+		t.Error("Exceeded() = false, want true")
+	}
+	var order []string
+	for _, skill := range budget.Skills {
+		order = append(order, skill.SkillID)
+	}
+	if strings.Join(order, ",") != "large,cjk,small" {
+		t.Errorf("Skills order = %v", order)
+	}
+
+	if EstimateTokenBudget(spec.TargetCursor, contents, 0).Exceeded() {
+		t.Error("budget 0 should never be exceeded")
+	}
+}
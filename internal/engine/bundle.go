@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/internal/config"
+	"skill-hub/pkg/spec"
+)
+
+// BundleFile 技能仓库中的 bundles.yaml，定义可一次启用的技能组合
+type BundleFile struct {
+	Bundles map[string]spec.Bundle `yaml:"bundles"`
+
+	path string
+}
+
+// LoadBundleFile 加载技能仓库中的技能组合定义，文件不存在时返回空定义
+func LoadBundleFile() (*BundleFile, error) {
+	path, err := config.GetBundlesPath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadBundleFileFrom(path)
+}
+
+// LoadBundleFileFrom 从指定路径加载技能组合定义
+func LoadBundleFileFrom(path string) (*BundleFile, error) {
+	file := &BundleFile{
+		Bundles: make(map[string]spec.Bundle),
+		path:    path,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return file, nil
+		}
+		return nil, fmt.Errorf("读取技能组合文件失败: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("解析技能组合文件失败: %w", err)
+	}
+	if file.Bundles == nil {
+		file.Bundles = make(map[string]spec.Bundle)
+	}
+
+	for name, bundle := range file.Bundles {
+		if err := validateBundle(bundle); err != nil {
+			return nil, fmt.Errorf("技能组合 %s 无效: %w", name, err)
+		}
+	}
+	return file, nil
+}
+
+// GetPath 获取技能组合文件路径
+func (f *BundleFile) GetPath() string {
+	return f.path
+}
+
+// Get 获取指定名称的技能组合
+func (f *BundleFile) Get(name string) (spec.Bundle, bool) {
+	bundle, exists := f.Bundles[name]
+	return bundle, exists
+}
+
+// Set 添加或替换技能组合
+func (f *BundleFile) Set(name string, bundle spec.Bundle) error {
+	if err := validateBundle(bundle); err != nil {
+		return err
+	}
+	f.Bundles[name] = bundle
+	return nil
+}
+
+// Names 返回按名称排序的技能组合
+func (f *BundleFile) Names() []string {
+	names := make([]string, 0, len(f.Bundles))
+	for name := range f.Bundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save 保存技能组合文件
+func (f *BundleFile) Save() error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(f); err != nil {
+		return fmt.Errorf("序列化技能组合失败: %w", err)
+	}
+	encoder.Close()
+
+	if err := os.WriteFile(f.path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("写入技能组合文件失败: %w", err)
+	}
+	return nil
+}
+
+// validateBundle 检查技能组合中技能ID非空且不重复
+func validateBundle(bundle spec.Bundle) error {
+	if len(bundle.Skills) == 0 {
+		return fmt.Errorf("没有包含任何技能")
+	}
+	seen := make(map[string]bool, len(bundle.Skills))
+	for _, skill := range bundle.Skills {
+		if skill.ID == "" {
+			return fmt.Errorf("技能ID不能为空")
+		}
+		if seen[skill.ID] {
+			return fmt.Errorf("技能 %s 重复", skill.ID)
+		}
+		seen[skill.ID] = true
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestBundleFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "bundles.yaml")
+
+	t.Run("Missing file", func(t *testing.T) {
+		file, err := LoadBundleFileFrom(path)
+		if err != nil {
+			t.Fatalf("LoadBundleFileFrom() error = %v", err)
+		}
+		if len(file.Bundles) != 0 {
+			t.Errorf("Expected no bundles, got %d", len(file.Bundles))
+		}
+	})
+
+	t.Run("Save and load", func(t *testing.T) {
+		file, _ := LoadBundleFileFrom(path)
+		bundle := spec.Bundle{
+			Description: "Go 后端",
+			Skills: []spec.BundleSkill{
+				{ID: "git-expert", Variables: map[string]string{"LANGUAGE": "go"}},
+				{ID: "code-review"},
+			},
+		}
+		if err := file.Set("go-backend", bundle); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if err := file.Save(); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		loaded, err := LoadBundleFileFrom(path)
+		if err != nil {
+			t.Fatalf("LoadBundleFileFrom() error = %v", err)
+		}
+		got, ok := loaded.Get("go-backend")
+		if !ok {
+			t.Fatal("Bundle go-backend not found")
+		}
+		if got.Description != "Go 后端" || len(got.Skills) != 2 {
+			t.Errorf("Unexpected bundle: %+v", got)
+		}
+		if got.Skills[0].Variables["LANGUAGE"] != "go" {
+			t.Errorf("Preset variable lost: %+v", got.Skills[0])
+		}
+	})
+
+	t.Run("Invalid bundles", func(t *testing.T) {
+		file, _ := LoadBundleFileFrom(path)
+		if err := file.Set("empty", spec.Bundle{}); err == nil {
+			t.Error("Expected error for bundle without skills")
+		}
+		dup := spec.Bundle{Skills: []spec.BundleSkill{{ID: "a"}, {ID: "a"}}}
+		if err := file.Set("dup", dup); err == nil {
+			t.Error("Expected error for duplicate skill")
+		}
+
+		badPath := filepath.Join(tmpDir, "bad.yaml")
+		content := "bundles:\n  broken:\n    skills:\n      - variables:\n          A: b\n"
+		if err := os.WriteFile(badPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadBundleFileFrom(badPath); err == nil {
+			t.Error("Expected error for skill without id")
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"skill-hub/pkg/spec"
+)
+
+// skillIndexFile 磁盘索引在缓存目录中的文件名
+const skillIndexFile = "skills-index.json"
+
+// skillIndexVersion 磁盘索引的格式版本，SKILL.md 的解析逻辑变化时递增，使旧索引整体失效
+const skillIndexVersion = 8
+
+// skillIndexEntry 单个技能的解析结果及其 SKILL.md 的文件信息
+type skillIndexEntry struct {
+	ModTime int64       `json:"mod_time"` // 修改时间（纳秒）
+	Size    int64       `json:"size"`
+	SHA256  string      `json:"sha256"`
+	Skill   *spec.Skill `json:"skill"`
+}
+
+// matches 检查文件信息是否与缓存时一致
+func (e *skillIndexEntry) matches(info os.FileInfo) bool {
+	return e.ModTime == info.ModTime().UnixNano() && e.Size == info.Size()
+}
+
+// skillIndex 技能仓库的磁盘索引，避免每个命令都重新解析全部技能
+type skillIndex struct {
+	Version int                         `json:"version"`
+	Skills  map[string]*skillIndexEntry `json:"skills"` // 按 SKILL.md 的绝对路径索引
+	dirty   bool
+}
+
+var (
+	// skillCache 进程内缓存，按 SKILL.md 路径索引，同一命令中多个 SkillManager 共享
+	skillCache   = make(map[string]*skillIndexEntry)
+	skillCacheMu sync.Mutex
+)
+
+// loadCachedSkill 加载技能，依次使用进程内缓存、磁盘索引和文件内容
+//
+// 修改时间和大小一致时直接使用缓存；不一致时比较文件哈希，
+// 内容未变（例如只是 touch 或重新检出）则沿用解析结果，否则重新解析。
+func (m *SkillManager) loadCachedSkill(mdPath, skillID string, info os.FileInfo) (*spec.Skill, error) {
+	skillCacheMu.Lock()
+	defer skillCacheMu.Unlock()
+
+	if entry, ok := skillCache[mdPath]; ok && entry.matches(info) {
+		return copySkill(entry.Skill), nil
+	}
+
+	index := m.loadIndex()
+	entry := index.Skills[mdPath]
+	if entry != nil && entry.matches(info) {
+		skillCache[mdPath] = entry
+		return copySkill(entry.Skill), nil
+	}
+
+	content, err := os.ReadFile(mdPath)
+	if err != nil {
+		return nil, fmt.Errorf("读取SKILL.md失败: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	if entry == nil || entry.SHA256 != hash {
+		skill, err := parseSkillMarkdown(content, skillID)
+		if err != nil {
+			return nil, err
+		}
+		entry = &skillIndexEntry{SHA256: hash, Skill: skill}
+	}
+	entry.ModTime = info.ModTime().UnixNano()
+	entry.Size = info.Size()
+
+	index.Skills[mdPath] = entry
+	index.dirty = true
+	skillCache[mdPath] = entry
+	return copySkill(entry.Skill), nil
+}
+
+// loadIndex 读取磁盘索引，索引不存在、损坏或格式版本不同时返回空索引
+func (m *SkillManager) loadIndex() *skillIndex {
+	if m.index != nil {
+		return m.index
+	}
+
+	m.index = &skillIndex{
+		Version: skillIndexVersion,
+		Skills:  make(map[string]*skillIndexEntry),
+	}
+	if m.indexPath == "" {
+		return m.index
+	}
+
+	data, err := os.ReadFile(m.indexPath)
+	if err != nil {
+		return m.index
+	}
+	var index skillIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return m.index
+	}
+	if index.Version == skillIndexVersion && index.Skills != nil {
+		m.index = &index
+	}
+	return m.index
+}
+
+// pruneIndex 从磁盘索引中删除当前技能目录下已不存在的技能
+//
+// loaded 为本次加载的全部 SKILL.md 路径。其他项目的技能目录不在当前技能目录中，其条目保留。
+func (m *SkillManager) pruneIndex(loaded map[string]bool) {
+	index := m.loadIndex()
+	for path := range index.Skills {
+		if loaded[path] {
+			continue
+		}
+		for _, root := range m.skillRoots() {
+			if rel, err := filepath.Rel(root.Path, path); err == nil && !strings.HasPrefix(rel, "..") {
+				delete(index.Skills, path)
+				index.dirty = true
+				break
+			}
+		}
+	}
+}
+
+// saveIndex 保存有变化的磁盘索引。索引只用于加速，保存失败不影响命令
+func (m *SkillManager) saveIndex() {
+	if m.index == nil || !m.index.dirty || m.indexPath == "" {
+		return
+	}
+
+	// 索引只是缓存，写入失败不影响命令，下次重新解析技能
+	data, err := json.Marshal(m.index)
+	if err != nil {
+		slog.Debug("序列化技能索引失败", "error", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(m.indexPath), 0755); err != nil {
+		slog.Debug("保存技能索引失败", "path", m.indexPath, "error", err)
+		return
+	}
+	// 先写临时文件再重命名，避免并发的命令读到写了一半的索引
+	tmp := m.indexPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		slog.Debug("保存技能索引失败", "path", m.indexPath, "error", err)
+		return
+	}
+	if err := os.Rename(tmp, m.indexPath); err != nil {
+		os.Remove(tmp)
+		slog.Debug("保存技能索引失败", "path", m.indexPath, "error", err)
+		return
+	}
+	m.index.dirty = false
+}
+
+// copySkill 返回技能的副本，调用方修改返回值不会影响缓存
+func copySkill(skill *spec.Skill) *spec.Skill {
+	copied := *skill
+	copied.Tags = append([]string(nil), skill.Tags...)
+	copied.Variables = append([]spec.Variable(nil), skill.Variables...)
+	copied.Dependencies = append([]string(nil), skill.Dependencies...)
+	return &copied
+}
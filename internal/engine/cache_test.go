@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSkillCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillsDir := filepath.Join(tmpDir, "skills")
+	indexPath := filepath.Join(tmpDir, "cache", skillIndexFile)
+
+	writeSkillMD := func(id, description string, modTime time.Time) string {
+		t.Helper()
+		dir := filepath.Join(skillsDir, id)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create skill directory: %v", err)
+		}
+		path := filepath.Join(dir, "SKILL.md")
+		content := "---\nname: " + id + "\ndescription: " + description + "\n---\nbody\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write SKILL.md: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set mtime: %v", err)
+		}
+		return path
+	}
+	readIndex := func() skillIndex {
+		t.Helper()
+		data, err := os.ReadFile(indexPath)
+		if err != nil {
+			t.Fatalf("Failed to read index: %v", err)
+		}
+		var index skillIndex
+		if err := json.Unmarshal(data, &index); err != nil {
+			t.Fatalf("Failed to parse index: %v", err)
+		}
+		return index
+	}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	alphaPath := writeSkillMD("alpha", "first", base)
+	betaPath := writeSkillMD("beta", "second", base)
+
+	manager := &SkillManager{skillsDir: skillsDir, indexPath: indexPath}
+	skills, err := manager.LoadAllSkills()
+	if err != nil || len(skills) != 2 {
+		t.Fatalf("LoadAllSkills() = %v, %v", skills, err)
+	}
+	if index := readIndex(); len(index.Skills) != 2 || index.Skills[alphaPath] == nil {
+		t.Fatalf("index = %+v", index)
+	}
+
+	t.Run("returned skills are copies", func(t *testing.T) {
+		skill, _ := manager.LoadSkill("alpha")
+		skill.Description = "mutated"
+		again, _ := manager.LoadSkill("alpha")
+		if again.Description != "first" {
+			t.Errorf("Description = %q, want %q", again.Description, "first")
+		}
+	})
+
+	t.Run("disk index is used by new managers", func(t *testing.T) {
+		// 直接修改索引中的内容，命中索引时读到的就是修改后的值
+		index := readIndex()
+		index.Skills[betaPath].Skill.Description = "from index"
+		data, _ := json.Marshal(index)
+		os.WriteFile(indexPath, data, 0644)
+		resetSkillCache()
+
+		skill, err := (&SkillManager{skillsDir: skillsDir, indexPath: indexPath}).LoadSkill("beta")
+		if err != nil || skill.Description != "from index" {
+			t.Errorf("LoadSkill() = %+v, %v", skill, err)
+		}
+	})
+
+	t.Run("mtime change with same content keeps entry", func(t *testing.T) {
+		resetSkillCache()
+		touched := base.Add(time.Minute)
+		os.Chtimes(betaPath, touched, touched)
+
+		m := &SkillManager{skillsDir: skillsDir, indexPath: indexPath}
+		skill, err := m.LoadSkill("beta")
+		if err != nil || skill.Description != "from index" {
+			t.Errorf("LoadSkill() = %+v, %v", skill, err)
+		}
+		if entry := readIndex().Skills[betaPath]; entry.ModTime != touched.UnixNano() {
+			t.Errorf("index mtime = %d, want %d", entry.ModTime, touched.UnixNano())
+		}
+	})
+
+	t.Run("content change invalidates entry", func(t *testing.T) {
+		writeSkillMD("alpha", "changed", base.Add(2*time.Minute))
+		skill, err := manager.LoadSkill("alpha")
+		if err != nil || skill.Description != "changed" {
+			t.Errorf("LoadSkill() = %+v, %v", skill, err)
+		}
+	})
+
+	t.Run("removed skills are pruned", func(t *testing.T) {
+		os.RemoveAll(filepath.Dir(alphaPath))
+		skills, err := (&SkillManager{skillsDir: skillsDir, indexPath: indexPath}).LoadAllSkills()
+		if err != nil || len(skills) != 1 {
+			t.Fatalf("LoadAllSkills() = %v, %v", skills, err)
+		}
+		if _, exists := readIndex().Skills[alphaPath]; exists {
+			t.Error("removed skill should be pruned from index")
+		}
+	})
+
+	t.Run("entries of other skills directories are kept", func(t *testing.T) {
+		otherDir := filepath.Join(tmpDir, "other")
+		if _, err := (&SkillManager{skillsDir: otherDir, indexPath: indexPath}).LoadAllSkills(); err != nil {
+			t.Fatalf("LoadAllSkills() error = %v", err)
+		}
+		if _, exists := readIndex().Skills[betaPath]; !exists {
+			t.Error("skills of other directories should stay in index")
+		}
+	})
+}
+
+// resetSkillCache 清空进程内缓存，模拟新的命令进程
+func resetSkillCache() {
+	skillCacheMu.Lock()
+	defer skillCacheMu.Unlock()
+	skillCache = make(map[string]*skillIndexEntry)
+}
@@ -0,0 +1,324 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"skill-hub/internal/diff"
+	"skill-hub/internal/pack"
+	"skill-hub/pkg/spec"
+)
+
+// ChangelogFile 技能目录中的更新日志文件名
+const ChangelogFile = "CHANGELOG.md"
+
+// changelogHeader 新建更新日志时写入的标题
+const changelogHeader = "# Changelog\n"
+
+// changelogSectionPattern 匹配版本标题 "## 1.2.0 - 2024-01-02"，日期可以省略，版本号可以带方括号或 v 前缀
+var changelogSectionPattern = regexp.MustCompile(`^##\s+\[?v?([0-9][^\]\s]*)\]?(?:\s+-\s+(\S+))?\s*$`)
+
+// ChangelogEntry 更新日志中一个版本的说明
+type ChangelogEntry struct {
+	Version string `json:"version"`
+	Date    string `json:"date,omitempty"`
+	Body    string `json:"body"` // 版本标题下的内容，已去掉首尾空行
+}
+
+// ParseChangelog 解析更新日志，按文件中的顺序（通常新版本在前）返回各版本的说明
+//
+// 每个版本以二级标题 "## 版本号 - 日期" 开始，版本标题之前的内容和其他格式的二级标题被忽略。
+func ParseChangelog(content string) []ChangelogEntry {
+	var entries []ChangelogEntry
+	var body []string
+	inSection := false
+	flush := func() {
+		if inSection {
+			entries[len(entries)-1].Body = strings.Trim(strings.Join(body, "\n"), "\n")
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if m := changelogSectionPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			flush()
+			entries = append(entries, ChangelogEntry{Version: m[1], Date: m[2]})
+			inSection = true
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			flush()
+			inSection = false
+			continue
+		}
+		if inSection {
+			body = append(body, line)
+		}
+	}
+	flush()
+	return entries
+}
+
+// ReadChangelog 读取技能目录中的更新日志，文件不存在时返回空
+func ReadChangelog(skillDir string) ([]ChangelogEntry, error) {
+	data, err := os.ReadFile(filepath.Join(skillDir, ChangelogFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取更新日志失败: %w", err)
+	}
+	return ParseChangelog(string(data)), nil
+}
+
+// AppendChangelog 在技能的更新日志中添加一个版本的说明，新版本排在最前，文件不存在时创建
+func AppendChangelog(skillDir string, entry ChangelogEntry) error {
+	path := filepath.Join(skillDir, ChangelogFile)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("读取更新日志失败: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(InsertChangelogEntry(string(data), entry)), 0644); err != nil {
+		return fmt.Errorf("写入更新日志失败: %w", err)
+	}
+	return nil
+}
+
+// InsertChangelogEntry 返回在更新日志内容中添加一个版本的说明后的内容，新版本排在最前，内容为空时添加标题
+func InsertChangelogEntry(content string, entry ChangelogEntry) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if strings.TrimSpace(content) == "" {
+		content = changelogHeader
+	}
+
+	heading := "## " + entry.Version
+	if entry.Date != "" {
+		heading += " - " + entry.Date
+	}
+	section := heading + "\n\n" + strings.Trim(entry.Body, "\n") + "\n\n"
+
+	// 插入到第一个版本标题之前，没有版本标题时追加到末尾
+	lines := strings.SplitAfter(content, "\n")
+	offset := len(content)
+	pos := 0
+	for _, line := range lines {
+		if changelogSectionPattern.MatchString(strings.TrimSpace(line)) {
+			offset = pos
+			break
+		}
+		pos += len(line)
+	}
+	head := content[:offset]
+	if !strings.HasSuffix(head, "\n\n") {
+		head = strings.TrimRight(head, "\n") + "\n\n"
+	}
+	content = head + section + content[offset:]
+	return strings.TrimRight(content, "\n") + "\n"
+}
+
+// ChangelogBetween 返回版本 from（不含）到 to（含）之间的说明，from 为空时返回 to 及之前的全部说明
+//
+// 版本号无法解析时按文件顺序处理：从 to 开始，遇到 from 为止。
+func ChangelogBetween(entries []ChangelogEntry, from, to string) []ChangelogEntry {
+	fromVersion, fromErr := ParseVersion(from)
+	toVersion, toErr := ParseVersion(to)
+
+	var result []ChangelogEntry
+	started := to == ""
+	for _, entry := range entries {
+		version, err := ParseVersion(entry.Version)
+		if err == nil && fromErr == nil && toErr == nil {
+			if version.Compare(toVersion) <= 0 && version.Compare(fromVersion) > 0 {
+				result = append(result, entry)
+			}
+			continue
+		}
+
+		// 无法按版本号比较时按文件顺序截取
+		if from != "" && entry.Version == strings.TrimPrefix(from, "v") {
+			break
+		}
+		if !started && entry.Version == strings.TrimPrefix(to, "v") {
+			started = true
+		}
+		if started {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// FieldChange 技能frontmatter中一个字段的变化，From 或 To 为空表示新增或删除
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// VersionChanges 由两个版本的内容推导出的变化，用于没有记录更新日志的版本
+type VersionChanges struct {
+	Fields        []FieldChange `json:"fields,omitempty"`
+	Added         []string      `json:"added,omitempty"`    // 新增的文件
+	Removed       []string      `json:"removed,omitempty"`  // 删除的文件
+	Modified      []string      `json:"modified,omitempty"` // 内容有变化的文件
+	PromptAdded   int           `json:"prompt_added"`       // 提示词正文新增的行数
+	PromptRemoved int           `json:"prompt_removed"`     // 提示词正文删除的行数
+}
+
+// Empty 检查是否没有任何变化
+func (c *VersionChanges) Empty() bool {
+	return len(c.Fields) == 0 && len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0 &&
+		c.PromptAdded == 0 && c.PromptRemoved == 0
+}
+
+// Summary 返回每项变化的说明，每行一项
+func (c *VersionChanges) Summary() []string {
+	var lines []string
+	for _, field := range c.Fields {
+		switch {
+		case field.From == "":
+			lines = append(lines, fmt.Sprintf("新增 %s: %s", field.Field, field.To))
+		case field.To == "":
+			lines = append(lines, fmt.Sprintf("删除 %s: %s", field.Field, field.From))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: %s -> %s", field.Field, field.From, field.To))
+		}
+	}
+	if c.PromptAdded > 0 || c.PromptRemoved > 0 {
+		lines = append(lines, fmt.Sprintf("提示词正文: +%d -%d 行", c.PromptAdded, c.PromptRemoved))
+	}
+	for _, file := range c.Added {
+		lines = append(lines, "新增文件: "+file)
+	}
+	for _, file := range c.Removed {
+		lines = append(lines, "删除文件: "+file)
+	}
+	for _, file := range c.Modified {
+		lines = append(lines, "修改文件: "+file)
+	}
+	return lines
+}
+
+// DiffVersions 比较技能两个版本的目录，推导frontmatter字段、提示词正文和文件的变化
+//
+// 版本号和 CHANGELOG.md 本身的变化不列出，SKILL.md 的变化体现在字段和正文中。
+func DiffVersions(fromDir, toDir, skillID string) (*VersionChanges, error) {
+	fromSkill, err := LoadSkillFile(filepath.Join(fromDir, "SKILL.md"), skillID)
+	if err != nil {
+		return nil, err
+	}
+	toSkill, err := LoadSkillFile(filepath.Join(toDir, "SKILL.md"), skillID)
+	if err != nil {
+		return nil, err
+	}
+	changes := &VersionChanges{Fields: diffSkillFields(fromSkill, toSkill)}
+
+	fromContent, err := os.ReadFile(filepath.Join(fromDir, "SKILL.md"))
+	if err != nil {
+		return nil, fmt.Errorf("读取SKILL.md失败: %w", err)
+	}
+	toContent, err := os.ReadFile(filepath.Join(toDir, "SKILL.md"))
+	if err != nil {
+		return nil, fmt.Errorf("读取SKILL.md失败: %w", err)
+	}
+	_, fromBody := splitPromptFrontmatter(string(fromContent))
+	_, toBody := splitPromptFrontmatter(string(toContent))
+	for _, line := range diff.Lines(fromBody, toBody) {
+		switch line.Kind {
+		case diff.OpInsert:
+			changes.PromptAdded++
+		case diff.OpDelete:
+			changes.PromptRemoved++
+		}
+	}
+
+	fromFiles, err := pack.BuildManifest(fromDir, skillID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := pack.BuildManifest(toDir, skillID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[string]string)
+	for _, file := range fromFiles.Files {
+		previous[file.Path] = file.SHA256
+	}
+	for _, file := range toFiles.Files {
+		sum, existed := previous[file.Path]
+		delete(previous, file.Path)
+		switch {
+		case file.Path == "SKILL.md" || file.Path == ChangelogFile:
+		case !existed:
+			changes.Added = append(changes.Added, file.Path)
+		case sum != file.SHA256:
+			changes.Modified = append(changes.Modified, file.Path)
+		}
+	}
+	for path := range previous {
+		if path != ChangelogFile {
+			changes.Removed = append(changes.Removed, path)
+		}
+	}
+	sort.Strings(changes.Removed)
+	return changes, nil
+}
+
+// diffSkillFields 比较技能frontmatter中的字段，变量按名称逐个比较默认值
+func diffSkillFields(from, to *spec.Skill) []FieldChange {
+	var fields []FieldChange
+	compare := func(field, a, b string) {
+		if a != b {
+			fields = append(fields, FieldChange{Field: field, From: a, To: b})
+		}
+	}
+	compare("name", from.Name, to.Name)
+	compare("description", from.Description, to.Description)
+	compare("license", from.License, to.License)
+	compare("compatibility", from.Compatibility, to.Compatibility)
+	compare("channel", from.Channel, to.Channel)
+	compare("priority", strconv.Itoa(from.Priority), strconv.Itoa(to.Priority))
+	compare("tags", strings.Join(from.Tags, ","), strings.Join(to.Tags, ","))
+	compare("dependencies", strings.Join(from.Dependencies, ","), strings.Join(to.Dependencies, ","))
+	compare("allowed-tools", spec.JoinAllowedTools(from.AllowedTools), spec.JoinAllowedTools(to.AllowedTools))
+
+	previous := make(map[string]spec.Variable)
+	for _, variable := range from.Variables {
+		previous[variable.Name] = variable
+	}
+	for _, variable := range to.Variables {
+		old, existed := previous[variable.Name]
+		delete(previous, variable.Name)
+		switch {
+		case !existed:
+			fields = append(fields, FieldChange{Field: "变量 " + variable.Name, To: variableText(variable)})
+		case old.Default != variable.Default:
+			fields = append(fields, FieldChange{Field: "变量 " + variable.Name + " 的默认值", From: old.Default, To: variable.Default})
+		}
+	}
+	var removed []string
+	for name := range previous {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		fields = append(fields, FieldChange{Field: "变量 " + name, From: variableText(previous[name])})
+	}
+	return fields
+}
+
+// variableText 返回变量的说明，没有说明时返回默认值或名称
+func variableText(variable spec.Variable) string {
+	switch {
+	case variable.Description != "":
+		return variable.Description
+	case variable.Default != "":
+		return "默认值 " + variable.Default
+	}
+	return variable.Name
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseChangelog(t *testing.T) {
+	content := "# Changelog\n\nIntro text.\n\n## [1.2.0] - 2024-03-01\n\n- Added A\n- Fixed B\n\n## v1.1.0\n\n- Changed C\n\n## Unreleased notes\n\nignored\n"
+	entries := ParseChangelog(content)
+	if len(entries) != 2 {
+		t.Fatalf("ParseChangelog() = %+v", entries)
+	}
+	if entries[0].Version != "1.2.0" || entries[0].Date != "2024-03-01" || entries[0].Body != "- Added A\n- Fixed B" {
+		t.Errorf("entries[0] = %+v", entries[0])
+	}
+	if entries[1].Version != "1.1.0" || entries[1].Date != "" || entries[1].Body != "- Changed C" {
+		t.Errorf("entries[1] = %+v", entries[1])
+	}
+}
+
+func TestAppendChangelog(t *testing.T) {
+	dir := t.TempDir()
+	if err := AppendChangelog(dir, ChangelogEntry{Version: "1.0.1", Date: "2024-01-01", Body: "- First"}); err != nil {
+		t.Fatalf("AppendChangelog() error = %v", err)
+	}
+	if err := AppendChangelog(dir, ChangelogEntry{Version: "1.0.2", Date: "2024-02-01", Body: "- Second"}); err != nil {
+		t.Fatalf("AppendChangelog() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ChangelogFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Changelog\n\n## 1.0.2 - 2024-02-01\n\n- Second\n\n## 1.0.1 - 2024-01-01\n\n- First\n"
+	if string(data) != want {
+		t.Errorf("CHANGELOG.md = %q, want %q", data, want)
+	}
+
+	entries, err := ReadChangelog(dir)
+	if err != nil || len(entries) != 2 || entries[0].Version != "1.0.2" {
+		t.Errorf("ReadChangelog() = %+v, %v", entries, err)
+	}
+	if entries, err := ReadChangelog(t.TempDir()); err != nil || entries != nil {
+		t.Errorf("ReadChangelog() without file = %+v, %v", entries, err)
+	}
+}
+
+func TestChangelogBetween(t *testing.T) {
+	entries := []ChangelogEntry{{Version: "1.3.0"}, {Version: "1.2.0"}, {Version: "1.1.0"}, {Version: "1.0.0"}}
+	versions := func(entries []ChangelogEntry) string {
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Version)
+		}
+		return strings.Join(result, ",")
+	}
+
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"1.0.0", "1.2.0", "1.2.0,1.1.0"},
+		{"v1.1.0", "v1.3.0", "1.3.0,1.2.0"},
+		{"", "1.1.0", "1.1.0,1.0.0"},
+		{"1.3.0", "1.3.0", ""},
+	}
+	for _, tt := range tests {
+		if got := versions(ChangelogBetween(entries, tt.from, tt.to)); got != tt.want {
+			t.Errorf("ChangelogBetween(%q, %q) = %s, want %s", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	// 版本号无法解析时按文件顺序截取
+	named := []ChangelogEntry{{Version: "2024.3"}, {Version: "2024.2b"}, {Version: "2024.1b"}}
+	if got := versions(ChangelogBetween(named, "2024.1b", "2024.2b")); got != "2024.2b" {
+		t.Errorf("ChangelogBetween() unparsable = %s", got)
+	}
+}
+
+func TestDiffVersions(t *testing.T) {
+	write := func(dir, name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	from, to := t.TempDir(), t.TempDir()
+	write(from, "SKILL.md", "---\nname: demo\ndescription: Old\nmetadata:\n  version: 1.0.0\n  tags: [a]\n---\n# Demo\n\nline one\nline two\n")
+	write(from, "scripts/old.sh", "echo old\n")
+	write(from, "scripts/run.sh", "echo 1\n")
+	write(from, ChangelogFile, "# Changelog\n")
+	write(to, "SKILL.md", "---\nname: demo\ndescription: New\nmetadata:\n  version: 1.1.0\n  tags: [a, b]\n---\n# Demo\n\nline one\nline three\nline four\n")
+	write(to, "scripts/new.sh", "echo new\n")
+	write(to, "scripts/run.sh", "echo 2\n")
+
+	changes, err := DiffVersions(from, to, "demo")
+	if err != nil {
+		t.Fatalf("DiffVersions() error = %v", err)
+	}
+	want := []string{
+		"description: Old -> New",
+		"tags: a -> a,b",
+		"提示词正文: +2 -1 行",
+		"新增文件: scripts/new.sh",
+		"删除文件: scripts/old.sh",
+		"修改文件: scripts/run.sh",
+	}
+	if got := changes.Summary(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	same, err := DiffVersions(from, from, "demo")
+	if err != nil {
+		t.Fatalf("DiffVersions() error = %v", err)
+	}
+	if !same.Empty() {
+		t.Errorf("DiffVersions() of same dir = %+v, want empty", same)
+	}
+}
@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"skill-hub/pkg/spec"
+)
+
+// Dependency 技能依赖，格式为 <skill-id> 或 <skill-id>@<版本约束>，例如 git-expert@^1.2.0
+type Dependency struct {
+	ID         string
+	Constraint Constraint
+}
+
+// ParseDependency 解析技能依赖声明
+func ParseDependency(s string) (Dependency, error) {
+	id, constraint, _ := strings.Cut(strings.TrimSpace(s), "@")
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return Dependency{}, fmt.Errorf("无效的依赖声明: %s", s)
+	}
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return Dependency{}, err
+	}
+	return Dependency{ID: id, Constraint: c}, nil
+}
+
+// String 返回依赖声明的字符串形式
+func (d Dependency) String() string {
+	if d.Constraint.String() == "" {
+		return d.ID
+	}
+	return d.ID + "@" + d.Constraint.String()
+}
+
+// CheckVersion 检查技能版本是否满足依赖的版本约束
+func (d Dependency) CheckVersion(version string) error {
+	if d.Constraint.String() == "" {
+		return nil
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return fmt.Errorf("技能 %s 的版本号 %q 无法解析，不能检查约束 %s", d.ID, version, d.Constraint)
+	}
+	if !d.Constraint.Check(v) {
+		return fmt.Errorf("需要 %s，但当前版本为 %s", d, version)
+	}
+	return nil
+}
+
+// ResolveDependencies 解析技能的依赖关系，返回按依赖顺序排列的技能ID
+//
+// 被依赖的技能排在依赖它的技能之前，结果包含 skillIDs 本身，每个技能只出现一次。
+// 依赖的技能不存在、版本不满足约束或存在循环依赖时返回错误。
+func (m *SkillManager) ResolveDependencies(skillIDs []string) ([]string, error) {
+	return ResolveDependencies(skillIDs, m.LoadSkill)
+}
+
+// ResolveDependencies 使用 load 加载技能并解析依赖关系，参见 SkillManager.ResolveDependencies
+//
+// 版本约束按 load 返回的技能版本检查，调用方可以在 load 中替换为项目固定的版本。
+func ResolveDependencies(skillIDs []string, load func(skillID string) (*spec.Skill, error)) ([]string, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	marks := make(map[string]int)
+	skills := make(map[string]*spec.Skill)
+	var order []string
+	var path []string
+
+	var visit func(skillID, requiredBy string, dep *Dependency) error
+	visit = func(skillID, requiredBy string, dep *Dependency) error {
+		if marks[skillID] == visiting {
+			// path 中从第一次出现该技能的位置开始就是环
+			for i, id := range path {
+				if id == skillID {
+					cycle := append(append([]string{}, path[i:]...), skillID)
+					return fmt.Errorf("检测到循环依赖: %s", strings.Join(cycle, " -> "))
+				}
+			}
+		}
+
+		skill, loaded := skills[skillID]
+		if !loaded {
+			var err error
+			if skill, err = load(skillID); err != nil {
+				if requiredBy != "" {
+					return fmt.Errorf("技能 %s 依赖的技能 '%s' 不存在", requiredBy, skillID)
+				}
+				return err
+			}
+			skills[skillID] = skill
+		}
+
+		// 已访问过的技能也要检查，不同技能可能对它有不同的约束
+		if dep != nil {
+			if err := dep.CheckVersion(skill.Version); err != nil {
+				return fmt.Errorf("技能 %s 的依赖不满足: %w", requiredBy, err)
+			}
+		}
+		if marks[skillID] == visited {
+			return nil
+		}
+
+		marks[skillID] = visiting
+		path = append(path, skillID)
+		for _, raw := range skill.Dependencies {
+			dep, err := ParseDependency(raw)
+			if err != nil {
+				return fmt.Errorf("技能 %s 的依赖声明无效: %w", skillID, err)
+			}
+			if err := visit(dep.ID, skillID, &dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[skillID] = visited
+
+		order = append(order, skillID)
+		return nil
+	}
+
+	for _, skillID := range skillIDs {
+		if err := visit(skillID, "", nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// EffectivePriority 返回技能在项目中的应用顺序优先级，项目覆盖的优先级优先，skill 为nil时为 0
+func EffectivePriority(skill *spec.Skill, skillVars spec.SkillVars) int {
+	if skillVars.Priority != nil {
+		return *skillVars.Priority
+	}
+	if skill == nil {
+		return 0
+	}
+	return skill.Priority
+}
+
+// OrderByPriority 按优先级从高到低排列技能ID，优先级相同的技能保持原有顺序
+//
+// 应在解析依赖之前调用：依赖解析保持根技能的顺序，被依赖的技能仍排在依赖它的技能之前。
+func OrderByPriority(skillIDs []string, priority func(skillID string) int) {
+	sort.SliceStable(skillIDs, func(i, j int) bool {
+		return priority(skillIDs[i]) > priority(skillIDs[j])
+	})
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestResolveDependencies(t *testing.T) {
+	graph := map[string][]string{
+		"app":      {"backend", "frontend"},
+		"backend":  {"base"},
+		"frontend": {"base"},
+		"base":     nil,
+		"broken":   {"missing"},
+		"cycle-a":  {"cycle-b"},
+		"cycle-b":  {"cycle-c"},
+		"cycle-c":  {"cycle-a"},
+		"self":     {"self"},
+		"pinned":   {"base@^1.2.0", "backend@1.x"},
+		"too-new":  {"base@^2.0.0"},
+		"conflict": {"pinned", "too-new"},
+		"bad-spec": {"base@^abc"},
+	}
+	load := func(skillID string) (*spec.Skill, error) {
+		deps, ok := graph[skillID]
+		if !ok {
+			return nil, fmt.Errorf("技能 '%s' 不存在", skillID)
+		}
+		return &spec.Skill{ID: skillID, Version: "1.4.0", Dependencies: deps}, nil
+	}
+
+	tests := []struct {
+		name    string
+		roots   []string
+		want    string
+		wantErr string
+	}{
+		{"no dependencies", []string{"base"}, "base", ""},
+		{"diamond", []string{"app"}, "base,backend,frontend,app", ""},
+		{"shared dependencies appear once", []string{"backend", "frontend"}, "base,backend,frontend", ""},
+		{"root already a dependency", []string{"base", "app"}, "base,backend,frontend,app", ""},
+		{"missing dependency", []string{"broken"}, "", "技能 broken 依赖的技能 'missing' 不存在"},
+		{"missing root", []string{"unknown"}, "", "技能 'unknown' 不存在"},
+		{"cycle", []string{"cycle-a"}, "", "检测到循环依赖: cycle-a -> cycle-b -> cycle-c -> cycle-a"},
+		{"self dependency", []string{"self"}, "", "检测到循环依赖: self -> self"},
+		{"constraints satisfied", []string{"pinned"}, "base,backend,pinned", ""},
+		{"constraint not satisfied", []string{"too-new"}, "", "技能 too-new 的依赖不满足: 需要 base@^2.0.0，但当前版本为 1.4.0"},
+		{"constraint checked on visited skill", []string{"conflict"}, "", "技能 too-new 的依赖不满足: 需要 base@^2.0.0，但当前版本为 1.4.0"},
+		{"invalid constraint", []string{"bad-spec"}, "", "技能 bad-spec 的依赖声明无效: 无效的版本约束 ^abc: 无效的版本号: abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveDependencies(tt.roots, load)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ResolveDependencies() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveDependencies() error = %v", err)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("ResolveDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDependency(t *testing.T) {
+	dep, err := ParseDependency("git-expert@^1.2.0")
+	if err != nil {
+		t.Fatalf("ParseDependency() error = %v", err)
+	}
+	if dep.ID != "git-expert" || dep.String() != "git-expert@^1.2.0" {
+		t.Errorf("ParseDependency() = %+v", dep)
+	}
+	if err := dep.CheckVersion("1.3.0"); err != nil {
+		t.Errorf("CheckVersion(1.3.0) error = %v", err)
+	}
+	if err := dep.CheckVersion("2.0.0"); err == nil {
+		t.Error("CheckVersion(2.0.0) should return error")
+	}
+	if err := dep.CheckVersion("latest"); err == nil {
+		t.Error("CheckVersion(latest) should return error")
+	}
+
+	bare, err := ParseDependency("git-expert")
+	if err != nil || bare.ID != "git-expert" || bare.String() != "git-expert" || bare.CheckVersion("latest") != nil {
+		t.Errorf("ParseDependency(bare) = %+v, %v", bare, err)
+	}
+
+	if _, err := ParseDependency("@^1.0.0"); err == nil {
+		t.Error("ParseDependency() should reject missing ID")
+	}
+}
+
+func TestOrderByPriority(t *testing.T) {
+	high := 5
+	skills := map[string]*spec.Skill{
+		"a": {ID: "a"},
+		"b": {ID: "b", Priority: 10},
+		"c": {ID: "c", Priority: -1},
+		"d": {ID: "d"},
+	}
+	overrides := map[string]spec.SkillVars{"d": {Priority: &high}}
+
+	ids := []string{"a", "b", "c", "d", "missing"}
+	OrderByPriority(ids, func(skillID string) int {
+		return EffectivePriority(skills[skillID], overrides[skillID])
+	})
+	if got := strings.Join(ids, ","); got != "b,d,a,missing,c" {
+		t.Errorf("OrderByPriority() = %s, want b,d,a,missing,c", got)
+	}
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skill-hub/pkg/spec"
+)
+
+// SetLocale 设置提示词的语言，GetSkillPrompt 和 RenderSkill 优先使用对应的语言版本
+//
+// 没有完全匹配的语言版本时依次尝试更通用的语言（zh-CN -> zh），都没有时使用 SKILL.md。
+func (m *SkillManager) SetLocale(locale string) {
+	m.locale = locale
+}
+
+// Locale 返回当前设置的提示词语言，为空表示使用 SKILL.md
+func (m *SkillManager) Locale() string {
+	return m.locale
+}
+
+// ResolveLocale 返回技能实际使用的语言版本，没有匹配的语言版本时返回空字符串
+func (m *SkillManager) ResolveLocale(skillID string) string {
+	_, locale := localeFile(m.GetSkillDir(skillID), m.locale)
+	return locale
+}
+
+// SkillLocales 返回技能提供的语言版本，按名称排序
+func (m *SkillManager) SkillLocales(skillID string) []string {
+	entries, err := os.ReadDir(m.GetSkillDir(skillID))
+	if err != nil {
+		return nil
+	}
+	var locales []string
+	for _, entry := range entries {
+		if locale, ok := spec.ParseLocaleFileName(entry.Name()); ok && !entry.IsDir() {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// localeFile 查找技能目录中与语言匹配的语言版本文件，返回文件路径和匹配的语言
+func localeFile(skillDir, locale string) (string, string) {
+	if locale == "" {
+		return "", ""
+	}
+	entries, err := os.ReadDir(skillDir)
+	if err != nil {
+		return "", ""
+	}
+	// 文件名中的语言不区分大小写
+	available := make(map[string]string)
+	for _, entry := range entries {
+		if name, ok := spec.ParseLocaleFileName(entry.Name()); ok && !entry.IsDir() {
+			available[strings.ToLower(name)] = entry.Name()
+		}
+	}
+	for _, candidate := range spec.LocaleFallbacks(locale) {
+		if name, ok := available[strings.ToLower(candidate)]; ok {
+			return filepath.Join(skillDir, name), candidate
+		}
+	}
+	return "", ""
+}
+
+// localizePrompt 用语言版本的正文替换 SKILL.md 的正文，语言版本自带的frontmatter被忽略
+func localizePrompt(prompt, localized string) string {
+	frontmatter, _ := splitPromptFrontmatter(prompt)
+	_, body := splitPromptFrontmatter(localized)
+	return frontmatter + strings.TrimLeft(body, "\n")
+}
+
+// splitPromptFrontmatter 拆分为包含分隔线的frontmatter和正文，没有frontmatter时全部作为正文
+func splitPromptFrontmatter(content string) (string, string) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content
+	}
+	end := strings.Index(content[4:], "\n---\n")
+	if end == -1 {
+		if strings.HasSuffix(content, "\n---") {
+			return content + "\n", ""
+		}
+		return "", content
+	}
+	split := 4 + end + len("\n---\n")
+	return content[:split], content[split:]
+}
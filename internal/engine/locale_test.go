@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalizedSkillPrompt(t *testing.T) {
+	skillsDir := t.TempDir()
+	skillDir := filepath.Join(skillsDir, "review")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"SKILL.md":       "---\nname: review\ndescription: Code review\n---\n# Review\n\nReview in {{.STYLE}} style.\n",
+		"SKILL.zh.md":    "---\nname: ignored\n---\n# 代码评审\n\n按 {{.STYLE}} 风格评审。\n",
+		"SKILL.pt-BR.md": "# Revisão\n",
+		"SKILL.notes.md": "不是语言版本（notes 不是有效的语言）\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(skillDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := &SkillManager{skillsDir: skillsDir}
+	if got := strings.Join(manager.SkillLocales("review"), ","); got != "pt-BR,zh" {
+		t.Errorf("SkillLocales() = %q", got)
+	}
+
+	tests := []struct {
+		locale   string
+		resolved string
+		body     string
+	}{
+		{"", "", "# Review"},
+		{"zh-CN", "zh", "# 代码评审"},
+		{"pt-br", "pt-br", "# Revisão"},
+		{"fr", "", "# Review"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			manager.SetLocale(tt.locale)
+			if got := manager.ResolveLocale("review"); got != tt.resolved {
+				t.Errorf("ResolveLocale() = %q, want %q", got, tt.resolved)
+			}
+			prompt, err := manager.GetSkillPrompt("review")
+			if err != nil {
+				t.Fatalf("GetSkillPrompt() error = %v", err)
+			}
+			// frontmatter 始终来自 SKILL.md
+			if !strings.HasPrefix(prompt, "---\nname: review\ndescription: Code review\n---\n"+tt.body) {
+				t.Errorf("GetSkillPrompt() = %q", prompt)
+			}
+		})
+	}
+}
@@ -2,18 +2,25 @@ package engine
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 	"skill-hub/internal/config"
+	"skill-hub/internal/template"
 	"skill-hub/pkg/spec"
 )
 
 // SkillManager 管理技能加载和操作
 type SkillManager struct {
-	skillsDir string
+	skillsDir string             // 技能仓库的技能目录，新技能写入这里
+	roots     []config.SkillRoot // 按优先级排列的全部技能目录，为空时只使用 skillsDir
+	indexPath string             // 磁盘索引文件路径，为空时只使用进程内缓存
+	index     *skillIndex        // 磁盘索引，首次加载技能时读取
+	locale    string             // 提示词语言，默认使用全局配置中的 locale
 }
 
 // NewSkillManager 创建新的技能管理器
@@ -22,21 +29,45 @@ func NewSkillManager() (*SkillManager, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &SkillManager{skillsDir: skillsDir}, nil
+	roots, err := config.GetSkillRoots()
+	if err != nil {
+		return nil, err
+	}
+	manager := &SkillManager{skillsDir: skillsDir, roots: roots}
+	if cfg, err := config.GetConfig(); err == nil {
+		manager.locale = cfg.Locale
+	}
+	if cacheDir, err := config.GetCacheDir(); err == nil {
+		manager.indexPath = filepath.Join(cacheDir, skillIndexFile)
+	}
+	return manager, nil
 }
 
 // LoadSkill 加载指定ID的技能
 func (m *SkillManager) LoadSkill(skillID string) (*spec.Skill, error) {
-	// 只使用标准结构：skills/skillID
-	skillDir := filepath.Join(m.skillsDir, skillID)
-	skill, err := m.loadSkillFromDirectory(skillDir, skillID)
-	if err == nil {
-		return skill, nil
+	// 只使用标准结构：<技能目录>/skillID，按优先级使用第一个包含该技能的目录
+	if origin, ok := m.Origin(skillID); ok {
+		skill, err := m.loadSkillFromDirectory(origin.Dir, skillID)
+		if err == nil {
+			m.saveIndex()
+			return skill, nil
+		}
+		slog.Debug("加载技能失败", "skill", skillID, "dir", origin.Dir, "error", err)
 	}
 
 	return nil, fmt.Errorf("技能 '%s' 不存在", skillID)
 }
 
+// LoadSkillFile 从SKILL.md文件加载技能仓库之外的技能，例如待导入的技能
+func LoadSkillFile(mdPath, skillID string) (*spec.Skill, error) {
+	return (&SkillManager{}).loadSkillFromMarkdown(mdPath, skillID)
+}
+
+// ParseSkill 解析SKILL.md的内容，例如远程仓库中尚未同步的技能
+func ParseSkill(content []byte, skillID string) (*spec.Skill, error) {
+	return parseSkillMarkdown(content, skillID)
+}
+
 // loadSkillFromDirectory 从目录加载技能
 func (m *SkillManager) loadSkillFromDirectory(skillDir, skillID string) (*spec.Skill, error) {
 	// 检查技能目录是否存在
@@ -46,8 +77,8 @@ func (m *SkillManager) loadSkillFromDirectory(skillDir, skillID string) (*spec.S
 
 	// 只支持SKILL.md格式
 	skillMdPath := filepath.Join(skillDir, "SKILL.md")
-	if _, err := os.Stat(skillMdPath); err == nil {
-		return m.loadSkillFromMarkdown(skillMdPath, skillID)
+	if info, err := os.Stat(skillMdPath); err == nil {
+		return m.loadCachedSkill(skillMdPath, skillID, info)
 	}
 
 	return nil, fmt.Errorf("未找到SKILL.md文件")
@@ -59,16 +90,22 @@ func (m *SkillManager) loadSkillFromMarkdown(mdPath, skillID string) (*spec.Skil
 	if err != nil {
 		return nil, fmt.Errorf("读取SKILL.md失败: %w", err)
 	}
+	return parseSkillMarkdown(content, skillID)
+}
 
-	// 解析frontmatter
-	lines := strings.Split(string(content), "\n")
+// parseSkillMarkdown 解析SKILL.md的内容
+func parseSkillMarkdown(content []byte, skillID string) (*spec.Skill, error) {
+	// 解析frontmatter，兼容Windows换行
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
 	if len(lines) < 2 || lines[0] != "---" {
 		return nil, fmt.Errorf("无效的SKILL.md格式: 缺少frontmatter")
 	}
 
 	var frontmatterLines []string
+	bodyStart := len(lines)
 	for i := 1; i < len(lines); i++ {
 		if lines[i] == "---" {
+			bodyStart = i + 1
 			break
 		}
 		frontmatterLines = append(frontmatterLines, lines[i])
@@ -99,73 +136,229 @@ func (m *SkillManager) loadSkillFromMarkdown(mdPath, skillID string) (*spec.Skil
 		skill.Description = desc
 	}
 
-	// 设置版本
+	// 设置版本（标准格式位于metadata.version，兼容根级别的version）
 	skill.Version = "1.0.0"
 	if version, ok := skillData["version"].(string); ok {
 		skill.Version = version
+	} else if metadata, ok := skillData["metadata"].(map[string]interface{}); ok {
+		if version, ok := metadata["version"]; ok && version != nil {
+			skill.Version = fmt.Sprint(version)
+		}
 	}
 
-	// 设置作者
-	if source, ok := skillData["source"].(string); ok {
+	// 设置作者（标准格式位于metadata.author，兼容根级别的author和source）
+	skill.Author = "unknown"
+	if author, ok := frontmatterField(skillData, "author").(string); ok && author != "" {
+		skill.Author = author
+	} else if source, ok := skillData["source"].(string); ok {
 		skill.Author = source
-	} else {
-		skill.Author = "unknown"
 	}
 
-	// 设置标签
-	if tagsStr, ok := skillData["tags"].(string); ok {
-		skill.Tags = strings.Split(tagsStr, ",")
-		for i, tag := range skill.Tags {
-			skill.Tags[i] = strings.TrimSpace(tag)
+	// 设置标签，支持逗号分隔的字符串和列表
+	skill.Tags = parseTags(frontmatterField(skillData, "tags"))
+
+	// 设置兼容性，对象格式（向后兼容）转换为说明文字
+	if compatData, ok := skillData["compatibility"]; ok {
+		skill.Compatibility = spec.CompatibilityDescription(compatData)
+	}
+
+	// 设置许可证和预先授权的工具（Agent Skills 规范字段）
+	if license, ok := skillData["license"].(string); ok {
+		skill.License = license
+	}
+	skill.AllowedTools = spec.ParseAllowedTools(skillData["allowed-tools"])
+
+	// 设置发布通道（metadata.channel），未声明时为 stable
+	if channel, ok := frontmatterField(skillData, "channel").(string); ok {
+		skill.Channel = strings.ToLower(strings.TrimSpace(channel))
+	}
+
+	// 设置应用顺序优先级（metadata.priority），未声明时为 0
+	priority, err := parsePriority(frontmatterField(skillData, "priority"))
+	if err != nil {
+		return nil, err
+	}
+	skill.Priority = priority
+
+	// 设置变量：frontmatter声明的变量优先，正文中未声明的占位符补充为无默认值的变量
+	variables, err := parseVariables(frontmatter, strings.Join(lines[bodyStart:], "\n"))
+	if err != nil {
+		return nil, err
+	}
+	skill.Variables = variables
+
+	// 设置依赖（标准格式位于metadata.dependencies，兼容根级别的dependencies）
+	skill.Dependencies = parseDependencies(skillData)
+
+	// 设置运行要求（skill-hub 版本和操作系统）
+	requires, err := spec.ParseRequirements(skillData["requires"])
+	if err != nil {
+		return nil, err
+	}
+	skill.Requires = requires
+
+	// 设置Claude专项配置
+	var claude struct {
+		Claude *spec.ClaudeConfig `yaml:"claude"`
+	}
+	if err := yaml.Unmarshal([]byte(frontmatter), &claude); err != nil {
+		return nil, fmt.Errorf("解析claude配置失败: %w", err)
+	}
+	skill.Claude = claude.Claude
+
+	return skill, nil
+}
+
+// frontmatterField 读取frontmatter字段，根级别优先，其次为metadata中的同名字段
+func frontmatterField(skillData map[string]interface{}, key string) interface{} {
+	if value, ok := skillData[key]; ok {
+		return value
+	}
+	if metadata, ok := skillData["metadata"].(map[string]interface{}); ok {
+		return metadata[key]
+	}
+	return nil
+}
+
+// parsePriority 解析优先级，支持整数和整数字符串，未声明时为 0
+func parsePriority(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return 0, nil
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return n, nil
 		}
 	}
+	return 0, fmt.Errorf("无效的优先级 %v，应为整数", value)
+}
 
-	// 设置兼容性
-	// 从YAML读取兼容性设置（字符串格式）
-	if compatData, ok := skillData["compatibility"]; ok {
-		switch v := compatData.(type) {
-		case string:
-			skill.Compatibility = v
-		case map[string]interface{}:
-			// 向后兼容：将对象格式转换为字符串
-			var compatList []string
-			if cursorVal, ok := v["cursor"].(bool); ok && cursorVal {
-				compatList = append(compatList, "Cursor")
-			}
-			if claudeVal, ok := v["claude_code"].(bool); ok && claudeVal {
-				compatList = append(compatList, "Claude Code")
-			}
-			if openCodeVal, ok := v["open_code"].(bool); ok && openCodeVal {
-				compatList = append(compatList, "OpenCode")
-			}
-			if shellVal, ok := v["shell"].(bool); ok && shellVal {
-				compatList = append(compatList, "Shell")
-			}
-			if len(compatList) > 0 {
-				skill.Compatibility = "Designed for " + strings.Join(compatList, ", ") + " (or similar AI coding assistants)"
-			}
+// parseTags 解析标签，忽略空标签
+func parseTags(value interface{}) []string {
+	var raw []string
+	switch v := value.(type) {
+	case string:
+		raw = strings.Split(v, ",")
+	case []interface{}:
+		for _, item := range v {
+			raw = append(raw, fmt.Sprint(item))
 		}
 	}
 
-	return skill, nil
+	var tags []string
+	for _, tag := range raw {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
 }
 
-// LoadAllSkills 加载所有技能
+// parseVariables 解析技能声明的变量
+func parseVariables(frontmatter, body string) ([]spec.Variable, error) {
+	var declared struct {
+		Variables []spec.Variable `yaml:"variables"`
+	}
+	if err := yaml.Unmarshal([]byte(frontmatter), &declared); err != nil {
+		return nil, fmt.Errorf("解析变量定义失败: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var variables []spec.Variable
+	for _, v := range declared.Variables {
+		if v.Name == "" || seen[v.Name] {
+			continue
+		}
+		if v.Name == template.VarTarget || v.Name == template.VarMode {
+			return nil, fmt.Errorf("变量 %s 是应用时注入的内置变量，不能在技能中声明", v.Name)
+		}
+		if err := v.CheckDefinition(); err != nil {
+			return nil, err
+		}
+		seen[v.Name] = true
+		variables = append(variables, v)
+	}
+
+	for _, name := range append(template.ExtractVariables(body), template.FuncVariables(body)...) {
+		// 内置变量由 apply 注入，不需要用户输入
+		if seen[name] || name == template.VarTarget || name == template.VarMode {
+			continue
+		}
+		seen[name] = true
+		variables = append(variables, spec.Variable{Name: name})
+	}
+
+	return variables, nil
+}
+
+// parseDependencies 解析技能依赖，支持YAML列表和逗号分隔的字符串
+func parseDependencies(skillData map[string]interface{}) []string {
+	value, ok := skillData["dependencies"]
+	if !ok {
+		if metadata, isMap := skillData["metadata"].(map[string]interface{}); isMap {
+			value = metadata["dependencies"]
+		}
+	}
+
+	var items []string
+	switch v := value.(type) {
+	case string:
+		items = strings.Split(v, ",")
+	case []interface{}:
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+	}
+
+	var dependencies []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			dependencies = append(dependencies, item)
+		}
+	}
+	return dependencies
+}
+
+// LoadAllSkills 加载所有技能目录中的技能，同名技能只保留优先级最高的目录中的版本
 func (m *SkillManager) LoadAllSkills() ([]*spec.Skill, error) {
-	// 只使用标准结构：直接从skills目录加载
-	skills, err := m.loadSkillsFromDirectory(m.skillsDir)
-	if err != nil {
-		if !os.IsNotExist(err) {
+	skills := []*spec.Skill{}
+	seen := make(map[string]bool)
+	loaded := make(map[string]bool)
+
+	for _, root := range m.skillRoots() {
+		// 只使用标准结构：直接从技能目录加载
+		rootSkills, err := m.loadSkillsFromDirectory(root.Path)
+		if err != nil {
+			// 目录不存在时跳过
+			if _, statErr := os.Stat(root.Path); os.IsNotExist(statErr) {
+				continue
+			}
 			return nil, err
 		}
-		// 目录不存在，返回空列表
-		return []*spec.Skill{}, nil
+
+		for _, skill := range rootSkills {
+			loaded[filepath.Join(root.Path, skill.ID, "SKILL.md")] = true
+			if seen[skill.ID] {
+				continue
+			}
+			seen[skill.ID] = true
+			skills = append(skills, skill)
+		}
 	}
 
+	m.pruneIndex(loaded)
+	m.saveIndex()
 	return skills, nil
 }
 
 // loadSkillsFromDirectory 从目录加载所有技能
+//
+// 不含SKILL.md的子目录视为命名空间，其中的技能ID为 <命名空间>/<名称>。
 func (m *SkillManager) loadSkillsFromDirectory(dir string) ([]*spec.Skill, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -174,13 +367,18 @@ func (m *SkillManager) loadSkillsFromDirectory(dir string) ([]*spec.Skill, error
 
 	var skills []*spec.Skill
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
 		skillID := entry.Name()
 		skillDir := filepath.Join(dir, skillID)
 
+		if !m.checkSkillExistsInDirectory(skillDir) {
+			skills = append(skills, m.loadNamespaceSkills(skillDir, skillID)...)
+			continue
+		}
+
 		// 尝试加载技能
 		skill, err := m.loadSkillFromDirectory(skillDir, skillID)
 		if err != nil {
@@ -194,37 +392,98 @@ func (m *SkillManager) loadSkillsFromDirectory(dir string) ([]*spec.Skill, error
 	return skills, nil
 }
 
+// loadNamespaceSkills 加载命名空间目录中的技能，命名空间不再嵌套
+func (m *SkillManager) loadNamespaceSkills(dir, namespace string) []*spec.Skill {
+	if spec.ValidateSkillID(namespace) != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var skills []*spec.Skill
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		skillID := spec.JoinSkillID(namespace, entry.Name())
+		skill, err := m.loadSkillFromDirectory(filepath.Join(dir, entry.Name()), skillID)
+		if err != nil {
+			continue
+		}
+		skills = append(skills, skill)
+	}
+	return skills
+}
+
 // GetSkillPrompt 获取技能的提示词内容
 func (m *SkillManager) GetSkillPrompt(skillID string) (string, error) {
 	// 首先尝试直接路径
-	skillDir := filepath.Join(m.skillsDir, skillID)
+	skillDir := m.GetSkillDir(skillID)
 	skillMdPath := filepath.Join(skillDir, "SKILL.md")
 
 	// 检查SKILL.md文件是否存在
 	if _, err := os.Stat(skillMdPath); os.IsNotExist(err) {
 		// 尝试在 skills/skills/ 子目录中查找
-		skillsSubDir := filepath.Join(m.skillsDir, "skills", skillID)
-		skillMdPath = filepath.Join(skillsSubDir, "SKILL.md")
+		skillDir = filepath.Join(m.skillsDir, "skills", skillID)
+		skillMdPath = filepath.Join(skillDir, "SKILL.md")
 
 		if _, err := os.Stat(skillMdPath); os.IsNotExist(err) {
 			return "", fmt.Errorf("技能 '%s' 缺少SKILL.md文件", skillID)
 		}
 	}
 
+	return readSkillPrompt(skillDir, skillID, m.locale)
+}
+
+// readSkillPrompt 读取技能目录中的 SKILL.md（或与语言匹配的语言版本）并展开包含文件
+func readSkillPrompt(skillDir, skillID, locale string) (string, error) {
 	// 读取SKILL.md文件内容作为提示词
-	promptData, err := os.ReadFile(skillMdPath)
+	promptData, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
 	if err != nil {
 		return "", fmt.Errorf("读取SKILL.md失败: %w", err)
 	}
 
-	return string(promptData), nil
+	// 使用与语言匹配的语言版本正文
+	if localizedPath, _ := localeFile(skillDir, locale); localizedPath != "" {
+		localized, err := os.ReadFile(localizedPath)
+		if err != nil {
+			return "", fmt.Errorf("读取%s失败: %w", filepath.Base(localizedPath), err)
+		}
+		promptData = []byte(localizePrompt(string(promptData), string(localized)))
+	}
+
+	// 展开 {{include "file.md"}}，多个技能共享的片段可以放在技能目录中的非技能子目录，例如 _partials/
+	prompt, err := template.ExpandIncludes(string(promptData), skillDir, skillRootDir(skillDir, skillID))
+	if err != nil {
+		return "", fmt.Errorf("技能 '%s' 展开包含文件失败: %w", skillID, err)
+	}
+
+	return prompt, nil
+}
+
+// skillRootDir 返回技能所在的技能目录，命名空间技能向上两级
+func skillRootDir(skillDir, skillID string) string {
+	root := skillDir
+	for range strings.Split(skillID, spec.NamespaceSeparator) {
+		root = filepath.Dir(root)
+	}
+	return root
+}
+
+// GetSkillDir 获取技能所在的目录，技能不存在时返回它在技能仓库中的目录
+func (m *SkillManager) GetSkillDir(skillID string) string {
+	if origin, ok := m.Origin(skillID); ok {
+		return origin.Dir
+	}
+	return filepath.Join(m.skillsDir, skillID)
 }
 
-// SkillExists 检查技能是否存在
+// SkillExists 检查技能是否存在于任一技能目录
 func (m *SkillManager) SkillExists(skillID string) bool {
-	// 只使用标准结构：skills/skillID
-	skillDir := filepath.Join(m.skillsDir, skillID)
-	return m.checkSkillExistsInDirectory(skillDir)
+	_, ok := m.Origin(skillID)
+	return ok
 }
 
 // checkSkillExistsInDirectory 检查目录中是否存在技能
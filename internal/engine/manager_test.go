@@ -3,6 +3,7 @@ package engine
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -43,7 +44,7 @@ name: test-skill
 description: A test skill for unit testing
 compatibility: Designed for OpenCode, Claude Code, and Cursor (or similar AI coding assistants)
 metadata:
-  version: 1.0.0
+  version: 1.2.0
   author: Test Author
   tags: test,unit-test
 ---
@@ -82,6 +83,55 @@ This is a test skill for unit testing.`
 		if skill.Compatibility != "Designed for OpenCode, Claude Code, and Cursor (or similar AI coding assistants)" {
 			t.Errorf("Skill.Compatibility = %v, want %v", skill.Compatibility, "Designed for OpenCode, Claude Code, and Cursor (or similar AI coding assistants)")
 		}
+
+		if skill.Version != "1.2.0" {
+			t.Errorf("Skill.Version = %v, want %v", skill.Version, "1.2.0")
+		}
+	})
+
+	t.Run("Load tool mode skill", func(t *testing.T) {
+		manager := &SkillManager{skillsDir: skillsDir}
+
+		skillID := "tool-skill"
+		skillDir := filepath.Join(skillsDir, skillID)
+		if err := os.MkdirAll(skillDir, 0755); err != nil {
+			t.Fatalf("Failed to create skill directory: %v", err)
+		}
+		mdContent := `---
+name: tool-skill
+description: A tool mode skill
+claude:
+  mode: tool
+  runtime: python
+  entrypoint: scripts/main.py
+  tool_spec:
+    name: count_lines
+    description: Count lines
+    input_schema:
+      type: object
+      required: [path]
+---
+`
+		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(mdContent), 0644); err != nil {
+			t.Fatalf("Failed to write SKILL.md: %v", err)
+		}
+
+		skill, err := manager.LoadSkill(skillID)
+		if err != nil {
+			t.Fatalf("LoadSkill() error = %v", err)
+		}
+		if skill.Claude == nil {
+			t.Fatal("Skill.Claude = nil")
+		}
+		if skill.Claude.Mode != "tool" || skill.Claude.Runtime != "python" || skill.Claude.Entrypoint != "scripts/main.py" {
+			t.Errorf("Skill.Claude = %+v", skill.Claude)
+		}
+		if skill.Claude.ToolSpec == nil || skill.Claude.ToolSpec.InputSchema["type"] != "object" {
+			t.Errorf("Skill.Claude.ToolSpec = %+v", skill.Claude.ToolSpec)
+		}
+		if got := manager.GetSkillDir(skillID); got != skillDir {
+			t.Errorf("GetSkillDir() = %v, want %v", got, skillDir)
+		}
 	})
 
 	t.Run("Load non-existent skill", func(t *testing.T) {
@@ -239,3 +289,246 @@ description: Test skill ` + skillID + `
 		}
 	})
 }
+
+func TestParseVariables(t *testing.T) {
+	frontmatter := `name: demo
+variables:
+  - name: LANGUAGE
+    default: zh
+    description: 输出语言
+`
+	body := "使用 {{.LANGUAGE}} 输出，风格 {{.STYLE}}，再次 {{.LANGUAGE}}，目标 {{.Target}}"
+
+	variables, err := parseVariables(frontmatter, body)
+	if err != nil {
+		t.Fatalf("parseVariables() error = %v", err)
+	}
+
+	if len(variables) != 2 {
+		t.Fatalf("parseVariables() = %v, want 2 variables", variables)
+	}
+	if variables[0].Name != "LANGUAGE" || variables[0].Default != "zh" || variables[0].Description != "输出语言" {
+		t.Errorf("declared variable = %+v", variables[0])
+	}
+	if variables[1].Name != "STYLE" || variables[1].Default != "" {
+		t.Errorf("placeholder variable = %+v", variables[1])
+	}
+}
+
+func TestParseTypedVariables(t *testing.T) {
+	frontmatter := `name: demo
+variables:
+  - name: MAX_LINES
+    type: int
+    default: "80"
+  - name: STRICT
+    type: bool
+    required: false
+  - name: LANGUAGE
+    type: enum
+    options: [go, rust]
+`
+	variables, err := parseVariables(frontmatter, "")
+	if err != nil {
+		t.Fatalf("parseVariables() error = %v", err)
+	}
+	if len(variables) != 3 {
+		t.Fatalf("parseVariables() = %v, want 3 variables", variables)
+	}
+	if variables[0].Type != "int" || variables[1].IsRequired() || len(variables[2].Options) != 2 {
+		t.Errorf("typed variables = %+v", variables)
+	}
+
+	invalid := `name: demo
+variables:
+  - name: LANGUAGE
+    type: enum
+`
+	if _, err := parseVariables(invalid, ""); err == nil {
+		t.Error("parseVariables() should reject enum without options")
+	}
+}
+
+func TestParseDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		skillData map[string]interface{}
+		want      []string
+	}{
+		{"metadata", map[string]interface{}{"metadata": map[string]interface{}{"dependencies": "git-expert, code-review"}}, []string{"git-expert", "code-review"}},
+		{"root list", map[string]interface{}{"dependencies": []interface{}{"git-expert", "code-review"}}, []string{"git-expert", "code-review"}},
+		{"root overrides metadata", map[string]interface{}{"dependencies": "a", "metadata": map[string]interface{}{"dependencies": "b"}}, []string{"a"}},
+		{"none", map[string]interface{}{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDependencies(tt.skillData)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("parseDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    int
+		wantErr bool
+	}{
+		{"none", nil, 0, false},
+		{"int", 10, 10, false},
+		{"negative", -5, -5, false},
+		{"string", " 3 ", 3, false},
+		{"invalid string", "high", 0, true},
+		{"float", 1.5, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePriority(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parsePriority(%v) = %d, %v, want %d, error %t", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	skill, err := ParseSkill([]byte("---\nname: a\ndescription: d\nmetadata:\n  priority: 7\n---\nbody\n"), "a")
+	if err != nil || skill.Priority != 7 {
+		t.Errorf("ParseSkill() priority = %v, %v, want 7", skill, err)
+	}
+}
+
+func TestNamespacedSkills(t *testing.T) {
+	skillsDir := t.TempDir()
+	for _, id := range []string{"git-expert", "acme/git-expert", "acme/review", "other/git-expert"} {
+		dir := filepath.Join(skillsDir, filepath.FromSlash(id))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create skill directory: %v", err)
+		}
+		_, name := filepath.Split(dir)
+		content := "---\nname: " + name + "\ndescription: " + id + "\n---\nprompt\n"
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write SKILL.md: %v", err)
+		}
+	}
+	// 不是合法命名空间的目录不会被扫描
+	if err := os.MkdirAll(filepath.Join(skillsDir, "Not_NS", "skill"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(skillsDir, "Not_NS", "skill", "SKILL.md"), []byte("---\nname: skill\n---\n"), 0644)
+
+	manager := &SkillManager{skillsDir: skillsDir}
+
+	skills, err := manager.LoadAllSkills()
+	if err != nil {
+		t.Fatalf("LoadAllSkills() error = %v", err)
+	}
+	descriptions := make(map[string]string)
+	for _, skill := range skills {
+		descriptions[skill.ID] = skill.Description
+	}
+	if len(descriptions) != 4 {
+		t.Fatalf("LoadAllSkills() = %v, want 4 skills", descriptions)
+	}
+	for _, id := range []string{"git-expert", "acme/git-expert", "acme/review", "other/git-expert"} {
+		if descriptions[id] != id {
+			t.Errorf("skill %s description = %q", id, descriptions[id])
+		}
+	}
+
+	skill, err := manager.LoadSkill("acme/git-expert")
+	if err != nil {
+		t.Fatalf("LoadSkill() error = %v", err)
+	}
+	if skill.ID != "acme/git-expert" || skill.Name != "git-expert" {
+		t.Errorf("LoadSkill() = %s (%s)", skill.ID, skill.Name)
+	}
+	if got := manager.GetSkillDir("acme/git-expert"); got != filepath.Join(skillsDir, "acme", "git-expert") {
+		t.Errorf("GetSkillDir() = %s", got)
+	}
+
+	// 命名空间目录本身和越界的ID都不是技能
+	for _, id := range []string{"acme", "acme/../git-expert", "Not_NS/skill"} {
+		if manager.SkillExists(id) {
+			t.Errorf("SkillExists(%q) = true", id)
+		}
+	}
+}
+
+func TestParseSkillMarkdownStandardFormat(t *testing.T) {
+	// Agent Skills 规范格式：扩展字段位于metadata中，Windows换行
+	content := strings.Join([]string{
+		"---",
+		"name: pdf-tools",
+		"description: Extract text and tables from PDF files",
+		"license: Apache-2.0",
+		"allowed-tools: Bash Read",
+		"metadata:",
+		"  author: example-org",
+		"  version: \"2.1\"",
+		"  tags: [pdf, documents]",
+		"---",
+		"# PDF Tools",
+		"",
+		"Use {{.TOOL}} to extract text.",
+	}, "\r\n")
+
+	skill, err := parseSkillMarkdown([]byte(content), "pdf-tools")
+	if err != nil {
+		t.Fatalf("parseSkillMarkdown() error = %v", err)
+	}
+	if skill.Name != "pdf-tools" || skill.Description != "Extract text and tables from PDF files" {
+		t.Errorf("name/description = %q/%q", skill.Name, skill.Description)
+	}
+	if skill.Author != "example-org" || skill.Version != "2.1" {
+		t.Errorf("author/version = %q/%q", skill.Author, skill.Version)
+	}
+	if strings.Join(skill.Tags, ",") != "pdf,documents" {
+		t.Errorf("tags = %v", skill.Tags)
+	}
+	if skill.License != "Apache-2.0" || strings.Join(skill.AllowedTools, ",") != "Bash,Read" {
+		t.Errorf("license/allowed-tools = %q/%v", skill.License, skill.AllowedTools)
+	}
+	if len(skill.Variables) != 1 || skill.Variables[0].Name != "TOOL" {
+		t.Errorf("variables = %+v", skill.Variables)
+	}
+
+	t.Run("legacy root fields", func(t *testing.T) {
+		skill, err := parseSkillMarkdown([]byte("---\nname: demo\nsource: team\ntags: a, b,\n---\nbody\n"), "demo")
+		if err != nil {
+			t.Fatalf("parseSkillMarkdown() error = %v", err)
+		}
+		if skill.Author != "team" || strings.Join(skill.Tags, ",") != "a,b" {
+			t.Errorf("author/tags = %q/%v", skill.Author, skill.Tags)
+		}
+	})
+}
+
+func TestGetSkillPromptIncludes(t *testing.T) {
+	skillsDir := t.TempDir()
+	files := map[string]string{
+		"acme/review/SKILL.md":       "---\nname: review\n---\n{{include \"parts/rules.md\"}}\n{{include \"../../_partials/footer.md\"}}\n",
+		"acme/review/parts/rules.md": "- 检查 {{.LANGUAGE}} 代码\n",
+		"_partials/footer.md":        "共享页脚\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(skillsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := &SkillManager{skillsDir: skillsDir}
+	prompt, err := manager.GetSkillPrompt("acme/review")
+	if err != nil {
+		t.Fatalf("GetSkillPrompt() error = %v", err)
+	}
+	want := "---\nname: review\n---\n- 检查 {{.LANGUAGE}} 代码\n共享页脚\n"
+	if prompt != want {
+		t.Errorf("GetSkillPrompt() = %q, want %q", prompt, want)
+	}
+}
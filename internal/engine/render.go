@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"fmt"
+	"log/slog"
+
+	"skill-hub/internal/config"
+	"skill-hub/internal/secrets"
+	"skill-hub/internal/template"
+	"skill-hub/pkg/spec"
+)
+
+// ResolveVariables 按技能的变量声明校验变量值，未设置或为空的变量使用默认值，返回规范化后的值
+//
+// 技能更新后变量声明可能变化，因此渲染前需要重新校验。未声明的变量原样保留。
+func ResolveVariables(skill *spec.Skill, values map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(values)+len(skill.Variables))
+	for name, value := range values {
+		resolved[name] = value
+	}
+	for _, variable := range skill.Variables {
+		value := values[variable.Name]
+		if value == "" {
+			value = variable.Default
+		}
+		value, err := variable.Normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		resolved[variable.Name] = value
+	}
+	return resolved, nil
+}
+
+// RenderContent 按目标工具渲染技能内容，apply 写入的内容和 status、verify 比对的内容都由它生成
+//
+// 依次解析变量值中的 ${env:NAME}/${secret:NAME} 引用、校验变量、注入内置变量 Target/Mode、
+// 计算条件区块和模板函数（如 {{upper .NAME}}）并替换变量占位符。
+// 内容引用了未设置的变量时返回错误，不会在结果中保留占位符。
+func RenderContent(skill *spec.Skill, content string, variables map[string]string, target, mode string) (string, error) {
+	variables, err := secrets.Resolve(variables)
+	if err != nil {
+		return "", err
+	}
+	values, err := ResolveVariables(skill, variables)
+	if err != nil {
+		return "", err
+	}
+	data := template.WithContext(values, target, mode)
+	content, err = template.RenderConditionals(content, data)
+	if err != nil {
+		return "", fmt.Errorf("计算条件区块失败: %w", err)
+	}
+	content, err = template.RenderFuncs(content, data, funcContext())
+	if err != nil {
+		return "", fmt.Errorf("计算模板函数失败: %w", err)
+	}
+	return template.RenderStrict(content, data)
+}
+
+// funcContext 返回渲染技能内容时模板函数的运行环境，env 函数只能读取配置项 template_env 中的环境变量
+func funcContext() template.FuncContext {
+	var ctx template.FuncContext
+	if cfg, err := config.GetConfig(); err == nil {
+		ctx.EnvAllowlist = cfg.TemplateEnv
+	}
+	return ctx
+}
+
+// RenderSkill 按目标工具渲染技能仓库中的技能内容
+func (m *SkillManager) RenderSkill(skillID string, variables map[string]string, target, mode string) (string, error) {
+	skill, err := m.LoadSkill(skillID)
+	if err != nil {
+		return "", err
+	}
+	prompt, err := m.GetSkillPrompt(skillID)
+	if err != nil {
+		return "", err
+	}
+	slog.Debug("渲染技能", "skill", skillID, "version", skill.Version, "target", target, "mode", mode)
+	content, err := RenderContent(skill, prompt, variables, target, mode)
+	if err != nil {
+		return "", fmt.Errorf("渲染技能 %s 失败: %w", skillID, err)
+	}
+	return content, nil
+}
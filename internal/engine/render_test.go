@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestResolveVariables(t *testing.T) {
+	skill := &spec.Skill{
+		ID: "demo",
+		Variables: []spec.Variable{
+			{Name: "STRICT", Type: spec.VarTypeBool, Default: "no"},
+			{Name: "LANGUAGE", Type: spec.VarTypeEnum, Options: []string{"go", "rust"}},
+		},
+	}
+
+	got, err := ResolveVariables(skill, map[string]string{"LANGUAGE": "go", "EXTRA": "kept"})
+	if err != nil {
+		t.Fatalf("ResolveVariables() error = %v", err)
+	}
+	if got["STRICT"] != "false" || got["LANGUAGE"] != "go" || got["EXTRA"] != "kept" {
+		t.Errorf("ResolveVariables() = %v", got)
+	}
+
+	if _, err := ResolveVariables(skill, map[string]string{"LANGUAGE": "java"}); err == nil {
+		t.Error("ResolveVariables() should reject value outside options")
+	}
+}
+
+func TestRenderContent(t *testing.T) {
+	skill := &spec.Skill{ID: "demo"}
+
+	tests := []struct {
+		name      string
+		content   string
+		variables map[string]string
+		expected  string
+		wantErr   string
+	}{
+		{
+			name:      "Simple variable replacement",
+			content:   "Hello {{.Name}}!",
+			variables: map[string]string{"Name": "World"},
+			expected:  "Hello World!",
+		},
+		{
+			name:      "Multiple variables",
+			content:   "Project: {{.Project}}, Port: {{.Port}}",
+			variables: map[string]string{"Project": "test", "Port": "8080"},
+			expected:  "Project: test, Port: 8080",
+		},
+		{
+			name:      "No variables",
+			content:   "Static content",
+			variables: map[string]string{},
+			expected:  "Static content",
+		},
+		{
+			name:      "Variable not in template",
+			content:   "Hello World!",
+			variables: map[string]string{"Name": "Test"},
+			expected:  "Hello World!",
+		},
+		{
+			name:      "Built-in variables",
+			content:   "{{if eq .Target \"cursor\"}}{{.Mode}}{{end}}",
+			variables: nil,
+			expected:  "project",
+		},
+		{
+			name:      "Empty value is set",
+			content:   "[{{.Name}}]",
+			variables: map[string]string{"Name": ""},
+			expected:  "[]",
+		},
+		{
+			name:      "Missing variables are reported by name",
+			content:   "{{.Project}} {{.Port}} {{.Name}}",
+			variables: map[string]string{"Name": "x"},
+			wantErr:   "Project, Port",
+		},
+		{
+			name:      "Missing variable in inactive branch is ignored",
+			content:   "{{if eq .Target \"claude_code\"}}{{.Project}}{{end}}ok",
+			variables: nil,
+			expected:  "ok",
+		},
+		{
+			name:      "Unknown condition function",
+			content:   "{{if printf .Name}}x{{end}}",
+			variables: map[string]string{"Name": "x"},
+			wantErr:   "printf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderContent(skill, tt.content, tt.variables, spec.TargetCursor, "project")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("RenderContent() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderContent() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("RenderContent() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderSkill(t *testing.T) {
+	skillsDir := t.TempDir()
+	dir := filepath.Join(skillsDir, "demo")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: demo\n---\n{{if eq .Target \"cursor\"}}\nCursor {{.LANGUAGE}}\n{{else}}\nOther {{.Mode}}\n{{end}}\n"
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := &SkillManager{skillsDir: skillsDir}
+	vars := map[string]string{"LANGUAGE": "go"}
+
+	got, err := manager.RenderSkill("demo", vars, spec.TargetCursor, "project")
+	if err != nil {
+		t.Fatalf("RenderSkill() error = %v", err)
+	}
+	if got != "---\nname: demo\n---\nCursor go\n" {
+		t.Errorf("RenderSkill(cursor) = %q", got)
+	}
+
+	got, err = manager.RenderSkill("demo", vars, spec.TargetClaudeCode, "global")
+	if err != nil {
+		t.Fatalf("RenderSkill() error = %v", err)
+	}
+	if got != "---\nname: demo\n---\nOther global\n" {
+		t.Errorf("RenderSkill(claude_code) = %q", got)
+	}
+}
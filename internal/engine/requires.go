@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"skill-hub/pkg/spec"
+)
+
+// CheckRequirements 检查技能的运行要求是否满足当前的 skill-hub 版本和操作系统
+//
+// hubVersion 无法解析（例如开发构建的 dev）时不检查版本约束。
+func CheckRequirements(skill *spec.Skill, hubVersion string) error {
+	return checkRequirements(skill, hubVersion, runtime.GOOS)
+}
+
+func checkRequirements(skill *spec.Skill, hubVersion, goos string) error {
+	req := skill.Requires
+	if req == nil {
+		return nil
+	}
+
+	if !req.SupportsOS(goos) {
+		return fmt.Errorf("技能 %s 仅支持 %s 系统，当前系统为 %s", skill.ID, strings.Join(req.OS, ", "), goos)
+	}
+
+	if req.SkillHub != "" {
+		constraint, err := ParseConstraint(req.SkillHub)
+		if err != nil {
+			return fmt.Errorf("技能 %s 的 requires.skill_hub 无效: %w", skill.ID, err)
+		}
+		current, err := ParseVersion(hubVersion)
+		if err != nil {
+			return nil
+		}
+		// 预发布和 git describe 生成的版本（0.5.0-3-gabc123）按对应的正式版本比较
+		current.Prerelease = ""
+		if !constraint.Check(current) {
+			return fmt.Errorf("技能 %s 需要 skill-hub %s，当前版本为 %s，请先升级 skill-hub", skill.ID, req.SkillHub, hubVersion)
+		}
+	}
+	return nil
+}
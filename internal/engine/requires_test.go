@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestCheckRequirements(t *testing.T) {
+	content := "---\nname: deploy\ndescription: 部署脚本\nrequires:\n  skill_hub: \">=0.5\"\n  os: [linux, macOS]\n---\n执行 deploy.sh\n"
+	skill, err := parseSkillMarkdown([]byte(content), "deploy")
+	if err != nil {
+		t.Fatalf("parseSkillMarkdown() error = %v", err)
+	}
+	if skill.Requires == nil || skill.Requires.SkillHub != ">=0.5" || strings.Join(skill.Requires.OS, ",") != "linux,macOS" {
+		t.Fatalf("Requires = %+v", skill.Requires)
+	}
+
+	tests := []struct {
+		name       string
+		hubVersion string
+		goos       string
+		wantErr    string
+	}{
+		{"satisfied", "0.5.0", "linux", ""},
+		{"os alias", "v0.6.1", "darwin", ""},
+		{"describe build", "0.5.0-3-gabc123", "linux", ""},
+		{"dev build", "dev", "linux", ""},
+		{"too old", "0.4.2", "linux", "需要 skill-hub >=0.5"},
+		{"unsupported os", "0.5.0", "windows", "仅支持 linux, macOS 系统"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRequirements(skill, tt.hubVersion, tt.goos)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := checkRequirements(&spec.Skill{ID: "plain"}, "0.1.0", "plan9"); err != nil {
+		t.Errorf("skill without requires: %v", err)
+	}
+	if _, err := spec.ParseRequirements("linux"); err == nil {
+		t.Error("expected error for non-object requires")
+	}
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"path/filepath"
+	"strings"
+
+	"skill-hub/internal/config"
+	"skill-hub/pkg/spec"
+)
+
+// SkillOrigin 技能的来源
+type SkillOrigin struct {
+	Root     string   `json:"root"`               // 技能所在技能目录的名称
+	Dir      string   `json:"dir"`                // 技能目录
+	Shadowed []string `json:"shadowed,omitempty"` // 同样包含该技能、被覆盖的低优先级技能目录
+}
+
+// Roots 返回按优先级从高到低排列的技能目录
+func (m *SkillManager) Roots() []config.SkillRoot {
+	return append([]config.SkillRoot(nil), m.skillRoots()...)
+}
+
+// skillRoots 返回技能目录，未配置时只有技能仓库
+func (m *SkillManager) skillRoots() []config.SkillRoot {
+	if len(m.roots) == 0 {
+		return []config.SkillRoot{{Name: config.RepoRootName, Path: m.skillsDir}}
+	}
+	return m.roots
+}
+
+// Origin 查找技能的来源，使用优先级最高的包含该技能的技能目录
+func (m *SkillManager) Origin(skillID string) (SkillOrigin, bool) {
+	var origin SkillOrigin
+	// 命名空间ID会拼接为两级目录，格式不正确时可能指向技能目录之外
+	if strings.Contains(skillID, spec.NamespaceSeparator) && spec.ValidateSkillID(skillID) != nil {
+		return origin, false
+	}
+	found := false
+	for _, root := range m.skillRoots() {
+		dir := filepath.Join(root.Path, skillID)
+		if !m.checkSkillExistsInDirectory(dir) {
+			continue
+		}
+		if found {
+			origin.Shadowed = append(origin.Shadowed, root.Name)
+			continue
+		}
+		origin = SkillOrigin{Root: root.Name, Dir: dir}
+		found = true
+	}
+	return origin, found
+}
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skill-hub/internal/config"
+)
+
+func TestSkillRoots(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	personalDir := filepath.Join(tmpDir, "personal")
+	repoDir := filepath.Join(tmpDir, "repo")
+
+	writeSkillMD := func(root, id, description string) {
+		t.Helper()
+		dir := filepath.Join(root, id)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create skill directory: %v", err)
+		}
+		content := "---\nname: " + id + "\ndescription: " + description + "\n---\nprompt of " + description + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write SKILL.md: %v", err)
+		}
+	}
+
+	writeSkillMD(repoDir, "shared", "repo")
+	writeSkillMD(repoDir, "repo-only", "repo")
+	writeSkillMD(personalDir, "shared", "personal")
+	writeSkillMD(personalDir, "personal-only", "personal")
+	writeSkillMD(projectDir, "shared", "project")
+
+	manager := &SkillManager{
+		skillsDir: repoDir,
+		roots: []config.SkillRoot{
+			{Name: "project", Path: projectDir},
+			{Name: "personal", Path: personalDir},
+			{Name: "missing", Path: filepath.Join(tmpDir, "missing")},
+			{Name: config.RepoRootName, Path: repoDir},
+		},
+	}
+
+	t.Run("merge with shadowing", func(t *testing.T) {
+		skills, err := manager.LoadAllSkills()
+		if err != nil {
+			t.Fatalf("LoadAllSkills() error = %v", err)
+		}
+		descriptions := make(map[string]string)
+		for _, skill := range skills {
+			descriptions[skill.ID] = skill.Description
+		}
+		want := map[string]string{"shared": "project", "personal-only": "personal", "repo-only": "repo"}
+		if len(descriptions) != len(want) {
+			t.Fatalf("LoadAllSkills() = %v, want %v", descriptions, want)
+		}
+		for id, description := range want {
+			if descriptions[id] != description {
+				t.Errorf("skill %s from %q, want %q", id, descriptions[id], description)
+			}
+		}
+	})
+
+	t.Run("origin", func(t *testing.T) {
+		origin, ok := manager.Origin("shared")
+		if !ok || origin.Root != "project" || origin.Dir != filepath.Join(projectDir, "shared") {
+			t.Errorf("Origin(shared) = %+v, %v", origin, ok)
+		}
+		if strings.Join(origin.Shadowed, ",") != "personal,repo" {
+			t.Errorf("Origin(shared).Shadowed = %v", origin.Shadowed)
+		}
+
+		origin, ok = manager.Origin("repo-only")
+		if !ok || origin.Root != config.RepoRootName || len(origin.Shadowed) != 0 {
+			t.Errorf("Origin(repo-only) = %+v, %v", origin, ok)
+		}
+
+		if _, ok := manager.Origin("unknown"); ok {
+			t.Error("Origin(unknown) should not be found")
+		}
+	})
+
+	t.Run("lookups follow priority", func(t *testing.T) {
+		skill, err := manager.LoadSkill("shared")
+		if err != nil || skill.Description != "project" {
+			t.Errorf("LoadSkill(shared) = %+v, %v", skill, err)
+		}
+		prompt, err := manager.GetSkillPrompt("personal-only")
+		if err != nil || !strings.Contains(prompt, "prompt of personal") {
+			t.Errorf("GetSkillPrompt(personal-only) = %q, %v", prompt, err)
+		}
+		if !manager.SkillExists("personal-only") || manager.SkillExists("unknown") {
+			t.Error("SkillExists() should search all roots")
+		}
+		if got := manager.GetSkillDir("unknown"); got != filepath.Join(repoDir, "unknown") {
+			t.Errorf("GetSkillDir(unknown) = %v, want repo directory", got)
+		}
+	})
+
+	t.Run("default root is the repository", func(t *testing.T) {
+		roots := (&SkillManager{skillsDir: repoDir}).Roots()
+		if len(roots) != 1 || roots[0].Name != config.RepoRootName || roots[0].Path != repoDir {
+			t.Errorf("Roots() = %+v", roots)
+		}
+	})
+}
@@ -0,0 +1,312 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version 语义化版本号
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// ParseVersion 解析 1.2.3、v1.2.3 和 1.2.3-beta.1 格式的版本号，省略的次版本号和修订号视为0
+func ParseVersion(s string) (Version, error) {
+	v, _, err := parsePartialVersion(s)
+	return v, err
+}
+
+// parsePartialVersion 解析版本号并返回显式给出的段数，1.2 返回 2。x 和 * 段视为省略
+func parsePartialVersion(s string) (Version, int, error) {
+	var v Version
+	raw := strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	// 构建元数据不参与比较
+	if i := strings.Index(raw, "+"); i >= 0 {
+		raw = raw[:i]
+	}
+	if i := strings.Index(raw, "-"); i >= 0 {
+		v.Prerelease = raw[i+1:]
+		raw = raw[:i]
+	}
+
+	parts := strings.Split(raw, ".")
+	if raw == "" || len(parts) > 3 {
+		return v, 0, fmt.Errorf("无效的版本号: %s", s)
+	}
+
+	fields := []*int{&v.Major, &v.Minor, &v.Patch}
+	given := 0
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, 0, fmt.Errorf("无效的版本号: %s", s)
+		}
+		*fields[i] = n
+		given++
+	}
+	if given < 3 && v.Prerelease != "" {
+		return v, 0, fmt.Errorf("无效的版本号: %s", s)
+	}
+	return v, given, nil
+}
+
+// ParseStrictVersion 解析完整的语义化版本号 MAJOR.MINOR.PATCH[-预发布标识]，不接受省略的段和空的预发布标识
+func ParseStrictVersion(s string) (Version, error) {
+	v, given, err := parsePartialVersion(s)
+	if err != nil {
+		return v, err
+	}
+	if given < 3 {
+		return v, fmt.Errorf("无效的版本号: %s，需要 MAJOR.MINOR.PATCH 格式", s)
+	}
+	// 1.2.3- 的预发布标识为空，需要根据原始字符串判断
+	if strings.Contains(strings.SplitN(s, "+", 2)[0], "-") && !validPrerelease(v.Prerelease) {
+		return v, fmt.Errorf("无效的版本号: %s，预发布标识只能包含字母、数字和连字符", s)
+	}
+	return v, nil
+}
+
+// validPrerelease 检查预发布标识：以点分隔，每段由字母、数字和连字符组成且不为空
+func validPrerelease(prerelease string) bool {
+	for _, part := range strings.Split(prerelease, ".") {
+		if part == "" {
+			return false
+		}
+		for _, r := range part {
+			if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// 版本号的提升方式
+const (
+	BumpMajor = "major"
+	BumpMinor = "minor"
+	BumpPatch = "patch"
+)
+
+// Bump 返回按 part 提升后的版本号：提升主版本号时次版本号和修订号归零，提升次版本号时修订号归零
+//
+// 预发布版本提升到其对应的正式版本，例如 1.2.0-beta.1 提升次版本号得到 1.2.0，与 npm version 相同。
+func (v Version) Bump(part string) (Version, error) {
+	pre := v.Prerelease != ""
+	next := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	switch part {
+	case BumpMajor:
+		if !pre || v.Minor != 0 || v.Patch != 0 {
+			next = Version{Major: v.Major + 1}
+		}
+	case BumpMinor:
+		if !pre || v.Patch != 0 {
+			next = Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+	case BumpPatch:
+		if !pre {
+			next.Patch++
+		}
+	default:
+		return v, fmt.Errorf("无效的版本号提升方式: %s，可用选项: %s, %s, %s", part, BumpMajor, BumpMinor, BumpPatch)
+	}
+	return next, nil
+}
+
+// String 返回版本号的字符串形式
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare 比较两个版本号，v 小于、等于、大于 other 时分别返回 -1、0、1
+func (v Version) Compare(other Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// comparePrerelease 按语义化版本规范比较预发布标识：正式版本大于预发布版本，
+// 数字标识按数值比较并小于非数字标识
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// comparator 单个版本比较条件，例如 >=1.2.0
+type comparator struct {
+	op      string
+	version Version
+}
+
+func (c comparator) check(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return cmp == 0
+}
+
+// Constraint 版本约束，例如 ^1.2.0、~1.2、>=1.0.0 <2.0.0、1.x || 2.x
+//
+// 同一组内以空格或逗号分隔的条件需同时满足，|| 分隔的各组满足其一即可。
+type Constraint struct {
+	raw    string
+	groups [][]comparator
+}
+
+// ParseConstraint 解析版本约束，空字符串和 * 表示任意版本
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: strings.TrimSpace(s)}
+	if c.raw == "" || c.raw == "*" {
+		return c, nil
+	}
+
+	for _, group := range strings.Split(c.raw, "||") {
+		var comparators []comparator
+		for _, term := range strings.FieldsFunc(group, func(r rune) bool { return r == ' ' || r == ',' }) {
+			parsed, err := parseComparator(term)
+			if err != nil {
+				return c, fmt.Errorf("无效的版本约束 %s: %w", s, err)
+			}
+			comparators = append(comparators, parsed...)
+		}
+		if len(comparators) == 0 {
+			return c, fmt.Errorf("无效的版本约束: %s", s)
+		}
+		c.groups = append(c.groups, comparators)
+	}
+	return c, nil
+}
+
+// parseComparator 将单个条件展开为比较条件，^、~ 和部分版本号展开为上下界
+func parseComparator(term string) ([]comparator, error) {
+	if term == "*" || term == "x" || term == "X" {
+		return []comparator{{op: ">=", version: Version{}}}, nil
+	}
+
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, prefix) {
+			op = prefix
+			break
+		}
+	}
+	v, given, err := parsePartialVersion(strings.TrimPrefix(term, op))
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "^":
+		// 不改变最左侧非零段的版本
+		upper := Version{Major: v.Major + 1}
+		switch {
+		case v.Major == 0 && (v.Minor > 0 || given == 2):
+			upper = Version{Minor: v.Minor + 1}
+		case v.Major == 0 && given == 3 && v.Minor == 0:
+			upper = Version{Patch: v.Patch + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+	case "~":
+		upper := Version{Major: v.Major, Minor: v.Minor + 1}
+		if given == 1 {
+			upper = Version{Major: v.Major + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+	case "", "=":
+		switch given {
+		case 0:
+			return []comparator{{">=", Version{}}}, nil
+		case 1:
+			return []comparator{{">=", v}, {"<", Version{Major: v.Major + 1}}}, nil
+		case 2:
+			return []comparator{{">=", v}, {"<", Version{Major: v.Major, Minor: v.Minor + 1}}}, nil
+		}
+		return []comparator{{"=", v}}, nil
+	}
+	return []comparator{{op, v}}, nil
+}
+
+// Check 检查版本是否满足约束
+func (c Constraint) Check(v Version) bool {
+	if len(c.groups) == 0 {
+		return true
+	}
+	for _, group := range c.groups {
+		satisfied := true
+		for _, cmp := range group {
+			if !cmp.check(v) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+// String 返回约束的原始形式
+func (c Constraint) String() string {
+	return c.raw
+}
@@ -0,0 +1,144 @@
+package engine
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1.2.3", "1.2.3", false},
+		{"v1.2.3", "1.2.3", false},
+		{"1.2", "1.2.0", false},
+		{"1.2.3-beta.1", "1.2.3-beta.1", false},
+		{"1.2.3+build.5", "1.2.3", false},
+		{"", "", true},
+		{"1.2.3.4", "", true},
+		{"1.a.3", "", true},
+		{"1.2-beta", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := ParseVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && v.String() != tt.want {
+				t.Errorf("ParseVersion(%q) = %s, want %s", tt.input, v, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{
+		"0.9.0",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0",
+		"1.0.1",
+		"1.10.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := ParseVersion(ordered[i])
+		b, _ := ParseVersion(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", a, b)
+		}
+		if a.Compare(a) != 0 {
+			t.Errorf("expected %s == %s", a, a)
+		}
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{"", []string{"0.0.1", "9.9.9"}, nil},
+		{"*", []string{"1.0.0"}, nil},
+		{"^1.2.0", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^0.2", []string{"0.2.0", "0.2.5"}, []string{"0.3.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"=1.2.3", []string{"1.2.3"}, []string{"1.2.2"}},
+		{"1.x", []string{"1.0.0", "1.5.2"}, []string{"2.0.0", "0.9.0"}},
+		{"1.2", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{">=1.0.0 <2.0.0", []string{"1.0.0", "1.99.0"}, []string{"0.9.9", "2.0.0"}},
+		{">1.0.0, <=1.5.0", []string{"1.0.1", "1.5.0"}, []string{"1.0.0", "1.5.1"}},
+		{"^1.0.0 || ^3.0.0", []string{"1.2.0", "3.1.0"}, []string{"2.0.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q) error = %v", tt.constraint, err)
+			}
+			for _, s := range tt.match {
+				v, _ := ParseVersion(s)
+				if !c.Check(v) {
+					t.Errorf("%q should match %s", tt.constraint, s)
+				}
+			}
+			for _, s := range tt.noMatch {
+				v, _ := ParseVersion(s)
+				if c.Check(v) {
+					t.Errorf("%q should not match %s", tt.constraint, s)
+				}
+			}
+		})
+	}
+
+	for _, invalid := range []string{"^abc", ">=1.0.0 ||", "~1.2.3.4"} {
+		if _, err := ParseConstraint(invalid); err == nil {
+			t.Errorf("ParseConstraint(%q) should return error", invalid)
+		}
+	}
+}
+
+func TestParseStrictVersion(t *testing.T) {
+	for _, input := range []string{"1.2.3", "v1.2.3", "1.2.3-beta.1", "1.2.3-rc-1+build.5"} {
+		if _, err := ParseStrictVersion(input); err != nil {
+			t.Errorf("ParseStrictVersion(%q) error = %v", input, err)
+		}
+	}
+	for _, input := range []string{"1", "1.2", "1.2.x", "1.2.3-", "1.2.3-beta..1", "1.2.3-beta_1", "latest"} {
+		if _, err := ParseStrictVersion(input); err == nil {
+			t.Errorf("ParseStrictVersion(%q) expected error", input)
+		}
+	}
+}
+
+func TestVersionBump(t *testing.T) {
+	tests := []struct {
+		version, part, want string
+	}{
+		{"1.2.3", BumpPatch, "1.2.4"},
+		{"1.2.3", BumpMinor, "1.3.0"},
+		{"1.2.3", BumpMajor, "2.0.0"},
+		{"1.2.3-beta.1", BumpPatch, "1.2.3"},
+		{"1.2.0-beta.1", BumpMinor, "1.2.0"},
+		{"1.2.3-beta.1", BumpMinor, "1.3.0"},
+		{"2.0.0-rc.1", BumpMajor, "2.0.0"},
+		{"2.1.0-rc.1", BumpMajor, "3.0.0"},
+	}
+	for _, tt := range tests {
+		v, _ := ParseStrictVersion(tt.version)
+		got, err := v.Bump(tt.part)
+		if err != nil || got.String() != tt.want {
+			t.Errorf("%s.Bump(%s) = %s, %v; want %s", tt.version, tt.part, got, err, tt.want)
+		}
+	}
+	if _, err := (Version{}).Bump("build"); err == nil {
+		t.Error("Bump(build) expected error")
+	}
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/pkg/spec"
+)
+
+// SkillTestsDir 技能目录中存放渲染测试的子目录
+const SkillTestsDir = "tests"
+
+// SkillTestCase 技能的渲染测试用例，描述一组变量和渲染结果应满足的条件
+//
+//	name: 中文输出
+//	target: cursor
+//	variables:
+//	  LANGUAGE: zh
+//	contains: ["使用 zh 回答"]
+//	not_contains: ["{{"]
+//	matches: ["(?m)^# Git"]
+//
+// error 不为空时期望渲染失败，且错误信息包含该文本。
+//
+// tool 模式的技能可以用 input 给出示例输入，这类用例按 claude.tool_spec.input_schema
+// 校验输入而不渲染提示词；同时指定 error 时期望输入不符合 schema。
+type SkillTestCase struct {
+	Name        string            `yaml:"name,omitempty" json:"name"`
+	Target      string            `yaml:"target,omitempty" json:"target,omitempty"` // 为空时使用技能兼容的第一个目标
+	Mode        string            `yaml:"mode,omitempty" json:"mode,omitempty"`     // 为空时为 project
+	Locale      string            `yaml:"locale,omitempty" json:"locale,omitempty"`
+	Variables   map[string]string `yaml:"variables,omitempty" json:"variables,omitempty"`
+	Contains    []string          `yaml:"contains,omitempty" json:"contains,omitempty"`
+	NotContains []string          `yaml:"not_contains,omitempty" json:"not_contains,omitempty"`
+	Matches     []string          `yaml:"matches,omitempty" json:"matches,omitempty"` // 正则表达式
+	Error       string            `yaml:"error,omitempty" json:"error,omitempty"`
+	Input       interface{}       `yaml:"input,omitempty" json:"input,omitempty"` // tool 模式技能的示例输入
+	File        string            `yaml:"-" json:"file"`                          // 用例所在的文件，相对技能目录
+}
+
+// skillTestFile 测试文件：包含 cases 列表，或者整个文件就是一个用例
+type skillTestFile struct {
+	SkillTestCase `yaml:",inline"`
+	Cases         []SkillTestCase `yaml:"cases,omitempty"`
+}
+
+// SkillTestResult 单个测试用例的结果
+type SkillTestResult struct {
+	SkillID  string        `json:"skill_id"`
+	Case     SkillTestCase `json:"case"`
+	Passed   bool          `json:"passed"`
+	Failures []string      `json:"failures,omitempty"`
+	Output   string        `json:"-"` // 渲染结果，失败时用于排查
+}
+
+// LoadSkillTests 读取技能目录中 tests/*.yaml 的测试用例，按文件名排序，没有测试时返回nil
+func LoadSkillTests(skillDir string) ([]SkillTestCase, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(skillDir, SkillTestsDir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("查找测试文件失败: %w", err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	var cases []SkillTestCase
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("读取测试文件失败: %w", err)
+		}
+		rel := filepath.ToSlash(filepath.Join(SkillTestsDir, filepath.Base(file)))
+
+		var parsed skillTestFile
+		if err := yaml.Unmarshal(data, &parsed); err != nil {
+			return nil, fmt.Errorf("解析测试文件 %s 失败: %w", rel, err)
+		}
+		fileCases := parsed.Cases
+		if len(fileCases) == 0 {
+			fileCases = []SkillTestCase{parsed.SkillTestCase}
+		}
+		for i, tc := range fileCases {
+			tc.File = rel
+			if tc.Name == "" {
+				tc.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+				if len(fileCases) > 1 {
+					tc.Name = fmt.Sprintf("%s#%d", tc.Name, i+1)
+				}
+			}
+			cases = append(cases, tc)
+		}
+	}
+	return cases, nil
+}
+
+// RunSkillTests 渲染技能目录中的技能并检查测试用例，技能可以尚未发布到技能仓库
+func RunSkillTests(skillDir, skillID string) ([]SkillTestResult, error) {
+	skill, err := LoadSkillFile(filepath.Join(skillDir, "SKILL.md"), skillID)
+	if err != nil {
+		return nil, err
+	}
+	cases, err := LoadSkillTests(skillDir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SkillTestResult, 0, len(cases))
+	for _, tc := range cases {
+		results = append(results, runSkillTest(skillDir, skill, tc))
+	}
+	return results, nil
+}
+
+// runSkillTest 执行单个测试用例
+func runSkillTest(skillDir string, skill *spec.Skill, tc SkillTestCase) SkillTestResult {
+	if tc.Input != nil {
+		return runInputTest(skill, tc)
+	}
+
+	result := SkillTestResult{SkillID: skill.ID, Case: tc}
+	fail := func(format string, args ...interface{}) {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+
+	target := spec.NormalizeTarget(tc.Target)
+	if target == "" {
+		target = spec.TargetCursor
+		if targets := skill.Compatible().Targets(); len(targets) > 0 {
+			target = targets[0]
+		}
+	}
+	mode := tc.Mode
+	if mode == "" {
+		mode = "project"
+	}
+	locale, err := spec.NormalizeLocale(tc.Locale)
+	if err != nil {
+		fail("%v", err)
+		return result
+	}
+
+	output, err := readSkillPrompt(skillDir, skill.ID, locale)
+	if err == nil {
+		output, err = RenderContent(skill, output, tc.Variables, target, mode)
+	}
+	result.Output = output
+
+	if tc.Error != "" {
+		switch {
+		case err == nil:
+			fail("期望渲染失败（%s），但渲染成功", tc.Error)
+		case !strings.Contains(err.Error(), tc.Error):
+			fail("错误信息不包含 %q: %v", tc.Error, err)
+		}
+		result.Passed = len(result.Failures) == 0
+		return result
+	}
+	if err != nil {
+		fail("渲染失败: %v", err)
+		return result
+	}
+
+	for _, want := range tc.Contains {
+		if !strings.Contains(output, want) {
+			fail("输出不包含 %q", want)
+		}
+	}
+	for _, unwanted := range tc.NotContains {
+		if strings.Contains(output, unwanted) {
+			fail("输出不应包含 %q", unwanted)
+		}
+	}
+	for _, pattern := range tc.Matches {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			fail("无效的正则表达式 %q: %v", pattern, err)
+			continue
+		}
+		if !re.MatchString(output) {
+			fail("输出不匹配 /%s/", pattern)
+		}
+	}
+	result.Passed = len(result.Failures) == 0
+	return result
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunSkillTests(t *testing.T) {
+	skillDir := filepath.Join(t.TempDir(), "git-expert")
+	if err := os.MkdirAll(filepath.Join(skillDir, SkillTestsDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"SKILL.md": "---\nname: git-expert\ndescription: Git\ncompatibility: Designed for Claude Code\nvariables:\n  - name: LANGUAGE\n    type: enum\n    options: [zh, en]\n---\n" +
+			"# Git\n\n使用 {{.LANGUAGE}} 回答{{if eq .Target \"cursor\"}} (cursor){{end}}\n",
+		"tests/basic.yaml": "variables:\n  LANGUAGE: zh\ncontains: [\"使用 zh 回答\"]\nnot_contains: [\"(cursor)\"]\nmatches: [\"(?m)^# Git$\"]\n",
+		"tests/more.yml": "cases:\n" +
+			"  - name: cursor\n    target: cursor\n    variables: {LANGUAGE: en}\n    contains: [\"(cursor)\"]\n" +
+			"  - name: invalid option\n    variables: {LANGUAGE: fr}\n    error: fr\n" +
+			"  - variables: {LANGUAGE: en}\n    contains: [\"使用 zh 回答\"]\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(skillDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := RunSkillTests(skillDir, "git-expert")
+	if err != nil {
+		t.Fatalf("RunSkillTests() error = %v", err)
+	}
+	want := []struct {
+		name   string
+		passed bool
+	}{
+		{"basic", true},
+		{"cursor", true},
+		{"invalid option", true},
+		{"more#3", false},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		if results[i].Case.Name != w.name || results[i].Passed != w.passed {
+			t.Errorf("result %d = %s passed=%v %v, want %s passed=%v", i, results[i].Case.Name, results[i].Passed, results[i].Failures, w.name, w.passed)
+		}
+	}
+	if failures := strings.Join(results[3].Failures, "\n"); !strings.Contains(failures, "使用 zh 回答") {
+		t.Errorf("failures = %q", failures)
+	}
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"skill-hub/internal/tool"
+	"skill-hub/pkg/spec"
+)
+
+// ValidateToolSpec 检查 tool 模式技能的 claude.tool_spec：input_schema 必须是有效的
+// JSON Schema (draft 2020-12)，schema 的 examples 和测试用例中的示例输入必须符合它。
+// 技能不是 tool 模式或没有声明 tool_spec 时不检查。
+func ValidateToolSpec(skillDir string, skill *spec.Skill) error {
+	if skill.Claude == nil || skill.Claude.Mode != tool.ModeTool || skill.Claude.ToolSpec == nil {
+		return nil
+	}
+	schema := skill.Claude.ToolSpec.InputSchema
+	if err := tool.ValidateSchema(schema); err != nil {
+		return err
+	}
+
+	var problems []string
+	examples, _ := schema["examples"].([]interface{})
+	for i, example := range examples {
+		if err := validateToolInput(schema, example); err != nil {
+			problems = append(problems, fmt.Sprintf("input_schema.examples[%d]: %s", i, indentLines(err.Error())))
+		}
+	}
+
+	cases, err := LoadSkillTests(skillDir)
+	if err != nil {
+		return err
+	}
+	for _, tc := range cases {
+		if tc.Input == nil {
+			continue
+		}
+		result := runInputTest(skill, tc)
+		for _, failure := range result.Failures {
+			problems = append(problems, fmt.Sprintf("%s (%s): %s", tc.Name, tc.File, indentLines(failure)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("示例输入不符合 input_schema:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// runInputTest 执行带 input 的测试用例：按技能的 input_schema 校验示例输入
+func runInputTest(skill *spec.Skill, tc SkillTestCase) SkillTestResult {
+	result := SkillTestResult{SkillID: skill.ID, Case: tc}
+	fail := func(format string, args ...interface{}) {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+
+	if skill.Claude == nil || skill.Claude.Mode != tool.ModeTool || skill.Claude.ToolSpec == nil {
+		fail("技能不是 tool 模式或没有声明 claude.tool_spec，不能使用 input")
+		return result
+	}
+	schema := skill.Claude.ToolSpec.InputSchema
+	if err := tool.ValidateSchema(schema); err != nil {
+		fail("%v", err)
+		return result
+	}
+
+	err := validateToolInput(schema, tc.Input)
+	switch {
+	case tc.Error != "" && err == nil:
+		fail("期望输入不符合 input_schema（%s），但校验通过", tc.Error)
+	case tc.Error != "" && !strings.Contains(err.Error(), tc.Error):
+		fail("错误信息不包含 %q: %v", tc.Error, err)
+	case tc.Error == "" && err != nil:
+		fail("%v", err)
+	}
+	result.Passed = len(result.Failures) == 0
+	return result
+}
+
+// validateToolInput 校验示例输入。示例来自YAML，先转换为与 exec 读取的JSON输入相同的形式
+func validateToolInput(schema map[string]interface{}, example interface{}) error {
+	data, err := json.Marshal(example)
+	if err != nil {
+		return fmt.Errorf("示例输入无法转换为JSON: %w", err)
+	}
+	var input interface{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return fmt.Errorf("解析示例输入失败: %w", err)
+	}
+	return tool.ValidateInput(schema, input)
+}
+
+// indentLines 缩进多行错误信息的后续行，使其嵌套在所属问题之下
+func indentLines(s string) string {
+	return strings.ReplaceAll(s, "\n", "\n  ")
+}
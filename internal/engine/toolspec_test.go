@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateToolSpec(t *testing.T) {
+	writeSkill := func(t *testing.T, schema string, tests string) string {
+		skillDir := filepath.Join(t.TempDir(), "lint")
+		if err := os.MkdirAll(filepath.Join(skillDir, SkillTestsDir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		skillMD := "---\nname: lint\ndescription: Lint\nclaude:\n  mode: tool\n  runtime: bash\n  entrypoint: run.sh\n  tool_spec:\n    name: lint\n    input_schema:\n" + schema + "---\n# Lint\n"
+		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(skillMD), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if tests != "" {
+			if err := os.WriteFile(filepath.Join(skillDir, SkillTestsDir, "input.yaml"), []byte(tests), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return skillDir
+	}
+	validSchema := "      type: object\n      required: [path]\n      properties:\n        path: {type: string}\n        level: {type: integer, maximum: 3}\n      examples:\n        - {path: main.go, level: 2}\n"
+
+	tests := []struct {
+		name    string
+		schema  string
+		tests   string
+		wantErr []string
+	}{
+		{"valid", validSchema, "cases:\n  - input: {path: a.go}\n  - name: missing path\n    input: {level: 1}\n    error: 缺少必需字段\n", nil},
+		{"invalid schema", "      type: object\n      properties:\n        path: {type: text}\n", "", []string{"input_schema.properties.path.type: 未知的类型 text"}},
+		{"invalid example", "      type: object\n      properties:\n        level: {type: integer}\n      examples:\n        - {level: high}\n", "", []string{"input_schema.examples[0]", "input.level: 类型应为 integer"}},
+		{"invalid fixture", validSchema, "name: too high\ninput: {path: a.go, level: 5}\n", []string{"too high (tests/input.yaml)", "input.level: 不能大于 3"}},
+		{"expected error not raised", validSchema, "input: {path: a.go}\nerror: path\n", []string{"期望输入不符合 input_schema"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skillDir := writeSkill(t, tt.schema, tt.tests)
+			skill, err := LoadSkillFile(filepath.Join(skillDir, "SKILL.md"), "lint")
+			if err != nil {
+				t.Fatalf("LoadSkillFile() error = %v", err)
+			}
+			err = ValidateToolSpec(skillDir, skill)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateToolSpec() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateToolSpec() should return error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %q, want containing %q", err.Error(), want)
+				}
+			}
+		})
+	}
+
+	t.Run("input cases in skill tests", func(t *testing.T) {
+		skillDir := writeSkill(t, validSchema, "cases:\n  - input: {path: a.go}\n  - input: {level: 1}\n")
+		results, err := RunSkillTests(skillDir, "lint")
+		if err != nil {
+			t.Fatalf("RunSkillTests() error = %v", err)
+		}
+		if len(results) != 2 || !results[0].Passed || results[1].Passed {
+			t.Errorf("results = %+v", results)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/config"
+	"skill-hub/internal/pack"
+	"skill-hub/pkg/spec"
+)
+
+// VersionStore 已安装的技能版本，每个版本一个目录：<技能ID>/<版本>/
+//
+// 技能仓库只保存每个技能的当前版本；update 时把同步前后的版本都安装到这里，
+// 项目固定的旧版本在仓库升级后仍然可以应用。版本目录不放在技能仓库的 skills/<技能ID>/ 下：
+// 那里是Git工作区，registry.json 的内容摘要覆盖技能目录中的全部文件，多出的版本目录会使仓库有未提交的修改、
+// update 校验摘要失败。因此当前版本在仓库和版本存储中各有一份，gc 只删除旧版本。
+type VersionStore struct {
+	dir string
+}
+
+// NewVersionStore 创建使用默认存储目录的技能版本存储
+func NewVersionStore() (*VersionStore, error) {
+	dir, err := config.GetVersionsDir()
+	if err != nil {
+		return nil, err
+	}
+	return &VersionStore{dir: dir}, nil
+}
+
+// NewVersionStoreAt 创建使用指定目录的技能版本存储
+func NewVersionStoreAt(dir string) *VersionStore {
+	return &VersionStore{dir: dir}
+}
+
+// Path 返回存储目录
+func (s *VersionStore) Path() string {
+	return s.dir
+}
+
+// Dir 返回技能指定版本的目录
+func (s *VersionStore) Dir(skillID, version string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(skillID), version)
+}
+
+// Has 检查技能的指定版本是否已安装
+func (s *VersionStore) Has(skillID, version string) bool {
+	if !isVersionDirName(version) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(s.Dir(skillID, version), "SKILL.md"))
+	return err == nil
+}
+
+// Install 将技能目录安装为指定版本，版本已安装时不覆盖并返回false
+func (s *VersionStore) Install(skillID, version, skillDir string) (bool, error) {
+	if err := spec.ValidateSkillID(skillID); err != nil {
+		return false, err
+	}
+	if _, err := ParseVersion(version); err != nil {
+		return false, fmt.Errorf("技能 %s 的版本 %q 无效，无法安装: %w", skillID, version, err)
+	}
+	if !isVersionDirName(version) {
+		return false, fmt.Errorf("技能 %s 的版本 %q 无效，无法安装", skillID, version)
+	}
+	if s.Has(skillID, version) {
+		return false, nil
+	}
+
+	manifest, err := pack.BuildManifest(skillDir, skillID, "", version)
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Dir(skillID, version)), 0755); err != nil {
+		return false, fmt.Errorf("创建版本目录失败: %w", err)
+	}
+
+	// 先写入临时目录再重命名，中断时不会留下不完整的版本
+	dest := s.Dir(skillID, version)
+	tmp := filepath.Join(filepath.Dir(dest), "."+version+".tmp")
+	os.RemoveAll(tmp)
+	if _, err := pack.Export(skillDir, manifest, pack.FormatDir, tmp); err != nil {
+		return false, fmt.Errorf("安装技能 %s@%s 失败: %w", skillID, version, err)
+	}
+	os.RemoveAll(dest)
+	if err := os.Rename(tmp, dest); err != nil {
+		os.RemoveAll(tmp)
+		return false, fmt.Errorf("安装技能 %s@%s 失败: %w", skillID, version, err)
+	}
+	return true, nil
+}
+
+// Load 加载已安装版本的技能及其提示词，locale 的含义与 SkillManager 的提示词语言相同
+func (s *VersionStore) Load(skillID, version, locale string) (*spec.Skill, string, error) {
+	if !s.Has(skillID, version) {
+		return nil, "", fmt.Errorf("技能 %s@%s 未安装", skillID, version)
+	}
+	dir := s.Dir(skillID, version)
+	skill, err := LoadSkillFile(filepath.Join(dir, "SKILL.md"), skillID)
+	if err != nil {
+		return nil, "", err
+	}
+	skill.Version = version
+	prompt, err := readSkillPrompt(dir, skillID, locale)
+	if err != nil {
+		return nil, "", err
+	}
+	return skill, prompt, nil
+}
+
+// LoadPinned 加载技能固定的版本：version 与仓库中的版本 skill.Version 不同且已安装时返回该版本的技能和提示词，
+// 否则返回false。apply、update --apply 和工作区应用都通过它使用固定的版本
+func (s *VersionStore) LoadPinned(skill *spec.Skill, version, locale string) (*spec.Skill, string, bool, error) {
+	if version == "" || version == skill.Version || !s.Has(skill.ID, version) {
+		return nil, "", false, nil
+	}
+	stored, prompt, err := s.Load(skill.ID, version, locale)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return stored, prompt, true, nil
+}
+
+// Versions 返回技能已安装的版本，按版本从低到高排序
+func (s *VersionStore) Versions(skillID string) []string {
+	entries, err := os.ReadDir(filepath.Join(s.dir, filepath.FromSlash(skillID)))
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() && s.Has(skillID, entry.Name()) {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sortVersions(versions)
+	return versions
+}
+
+// All 返回所有已安装的技能版本，键为技能ID
+//
+// 直接包含版本目录（含SKILL.md）的目录是技能，否则视为命名空间。
+func (s *VersionStore) All() (map[string][]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]string{}, nil
+		}
+		return nil, fmt.Errorf("读取版本目录失败: %w", err)
+	}
+
+	all := make(map[string][]string)
+	for _, entry := range entries {
+		if !entry.IsDir() || spec.ValidateSkillID(entry.Name()) != nil {
+			continue
+		}
+		if versions := s.Versions(entry.Name()); len(versions) > 0 {
+			all[entry.Name()] = versions
+			continue
+		}
+		nsEntries, err := os.ReadDir(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, nsEntry := range nsEntries {
+			id := spec.JoinSkillID(entry.Name(), nsEntry.Name())
+			if versions := s.Versions(id); len(versions) > 0 {
+				all[id] = versions
+			}
+		}
+	}
+	return all, nil
+}
+
+// Remove 删除技能的指定版本，技能没有其他版本时一并删除技能目录
+func (s *VersionStore) Remove(skillID, version string) error {
+	if err := os.RemoveAll(s.Dir(skillID, version)); err != nil {
+		return fmt.Errorf("删除技能 %s@%s 失败: %w", skillID, version, err)
+	}
+	// 删除空的技能目录和命名空间目录，非空时 os.Remove 失败即停止
+	for dir := filepath.Dir(s.Dir(skillID, version)); dir != s.dir && len(dir) > len(s.dir); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// isVersionDirName 检查版本能否作为目录名，排除路径分隔符和隐藏目录（安装中的临时目录）
+func isVersionDirName(version string) bool {
+	return version != "" && !strings.HasPrefix(version, ".") && !strings.ContainsAny(version, `/\`)
+}
+
+// sortVersions 按语义化版本从低到高排序，无法解析的版本按字符串排在最前
+func sortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, errA := ParseVersion(versions[i])
+		b, errB := ParseVersion(versions[j])
+		switch {
+		case errA != nil && errB != nil:
+			return versions[i] < versions[j]
+		case errA != nil || errB != nil:
+			return errA != nil
+		}
+		return a.Compare(b) < 0
+	})
+}
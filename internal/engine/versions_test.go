@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVersionStore(t *testing.T) {
+	store := NewVersionStoreAt(t.TempDir())
+	writeSkill := func(body string) string {
+		dir := filepath.Join(t.TempDir(), "skill")
+		if err := os.MkdirAll(filepath.Join(dir, "resources"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		content := "---\nname: demo\ndescription: Demo\n---\n" + body
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(filepath.Join(dir, "resources", "run.sh"), []byte("echo "+body), 0755)
+		return dir
+	}
+
+	for _, tt := range []struct{ id, version, body string }{
+		{"demo", "1.10.0", "v1.10"},
+		{"demo", "1.2.0", "v1.2"},
+		{"demo", "2.0.0-beta.1", "beta"},
+		{"acme/tool", "0.1.0", "tool"},
+	} {
+		ok, err := store.Install(tt.id, tt.version, writeSkill(tt.body))
+		if err != nil || !ok {
+			t.Fatalf("Install(%s@%s) = %v, %v", tt.id, tt.version, ok, err)
+		}
+	}
+
+	// 已安装的版本不会被覆盖
+	if ok, err := store.Install("demo", "1.2.0", writeSkill("changed")); err != nil || ok {
+		t.Errorf("Install() of existing version = %v, %v", ok, err)
+	}
+	if _, err := store.Install("demo", "latest", writeSkill("x")); err == nil {
+		t.Error("Install() expected error for invalid version")
+	}
+
+	if got := store.Versions("demo"); !reflect.DeepEqual(got, []string{"1.2.0", "1.10.0", "2.0.0-beta.1"}) {
+		t.Errorf("Versions() = %v", got)
+	}
+	all, err := store.All()
+	if err != nil {
+		t.Fatalf("All() error = %v", err)
+	}
+	if len(all) != 2 || len(all["acme/tool"]) != 1 {
+		t.Errorf("All() = %v", all)
+	}
+
+	skill, prompt, err := store.Load("demo", "1.2.0", "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if skill.Version != "1.2.0" || !strings.Contains(prompt, "v1.2") {
+		t.Errorf("Load() = %q, %q", skill.Version, prompt)
+	}
+	if _, err := os.Stat(filepath.Join(store.Dir("demo", "1.2.0"), "resources", "run.sh")); err != nil {
+		t.Errorf("resources not installed: %v", err)
+	}
+	if store.Has("demo", "../demo/1.2.0") {
+		t.Error("Has() should reject versions containing path separators")
+	}
+
+	if err := store.Remove("acme/tool", "0.1.0"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(store.Path(), "acme")); !os.IsNotExist(err) {
+		t.Errorf("empty namespace directory should be removed, stat error = %v", err)
+	}
+	if err := store.Remove("demo", "1.2.0"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if got := store.Versions("demo"); len(got) != 2 {
+		t.Errorf("Versions() after Remove = %v", got)
+	}
+}
@@ -0,0 +1,208 @@
+// Package forge 在 GitHub、GitLab 和 Gitea 等代码托管平台上搜索技能仓库
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"skill-hub/internal/config"
+	"skill-hub/internal/credentials"
+)
+
+// DefaultLimit 每个平台返回的搜索结果数量
+const DefaultLimit = 10
+
+// httpClient 调用平台API使用的客户端
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// Repo 搜索到的仓库
+type Repo struct {
+	Forge       string `json:"forge"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	Stars       int    `json:"stars"`
+	CloneURL    string `json:"clone_url"`
+	WebURL      string `json:"web_url"`
+}
+
+// Search 在平台上按关键字搜索仓库，按星标从多到少排列。
+// 平台没有配置token时使用 'skill-hub login' 为该主机保存的凭据
+func Search(ctx context.Context, f config.Forge, keyword string, limit int) ([]Repo, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if f.Token == "" {
+		f.Token = credentials.Token(f.URL)
+	}
+	switch f.Type {
+	case config.ForgeGitHub:
+		return searchGitHub(ctx, f, keyword, limit)
+	case config.ForgeGitLab:
+		return searchGitLab(ctx, f, keyword, limit)
+	case config.ForgeGitea:
+		return searchGitea(ctx, f, keyword, limit)
+	}
+	return nil, fmt.Errorf("不支持的代码托管平台类型: %s", f.Type)
+}
+
+// searchGitHub 调用 GitHub 的 /search/repositories，github.com 以外的地址按 GitHub Enterprise 处理
+func searchGitHub(ctx context.Context, f config.Forge, keyword string, limit int) ([]Repo, error) {
+	api := f.URL + "/api/v3"
+	if host(f.URL) == "github.com" {
+		api = "https://api.github.com"
+	}
+	query := url.Values{"q": {keyword}, "sort": {"stars"}, "order": {"desc"}, "per_page": {fmt.Sprint(limit)}}
+
+	var response struct {
+		Items []struct {
+			FullName    string `json:"full_name"`
+			Description string `json:"description"`
+			Stars       int    `json:"stargazers_count"`
+			CloneURL    string `json:"clone_url"`
+			HTMLURL     string `json:"html_url"`
+		} `json:"items"`
+	}
+	header := http.Header{"Accept": {"application/vnd.github+json"}}
+	if f.Token != "" {
+		header.Set("Authorization", "Bearer "+f.Token)
+	}
+	if err := getJSON(ctx, api+"/search/repositories?"+query.Encode(), header, &response); err != nil {
+		return nil, err
+	}
+
+	repos := make([]Repo, 0, len(response.Items))
+	for _, item := range response.Items {
+		repos = append(repos, Repo{Forge: f.Name, FullName: item.FullName, Description: item.Description, Stars: item.Stars, CloneURL: item.CloneURL, WebURL: item.HTMLURL})
+	}
+	return repos, nil
+}
+
+// searchGitLab 调用 GitLab 的 /api/v4/projects
+func searchGitLab(ctx context.Context, f config.Forge, keyword string, limit int) ([]Repo, error) {
+	query := url.Values{"search": {keyword}, "order_by": {"star_count"}, "sort": {"desc"}, "per_page": {fmt.Sprint(limit)}}
+
+	var response []struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		Description       string `json:"description"`
+		StarCount         int    `json:"star_count"`
+		HTTPURLToRepo     string `json:"http_url_to_repo"`
+		WebURL            string `json:"web_url"`
+	}
+	header := http.Header{}
+	if f.Token != "" {
+		header.Set("PRIVATE-TOKEN", f.Token)
+	}
+	if err := getJSON(ctx, f.URL+"/api/v4/projects?"+query.Encode(), header, &response); err != nil {
+		return nil, err
+	}
+
+	repos := make([]Repo, 0, len(response))
+	for _, item := range response {
+		repos = append(repos, Repo{Forge: f.Name, FullName: item.PathWithNamespace, Description: item.Description, Stars: item.StarCount, CloneURL: item.HTTPURLToRepo, WebURL: item.WebURL})
+	}
+	return repos, nil
+}
+
+// searchGitea 调用 Gitea 的 /api/v1/repos/search，Forgejo 的API与其兼容
+func searchGitea(ctx context.Context, f config.Forge, keyword string, limit int) ([]Repo, error) {
+	query := url.Values{"q": {keyword}, "sort": {"stars"}, "order": {"desc"}, "limit": {fmt.Sprint(limit)}}
+
+	var response struct {
+		OK   bool `json:"ok"`
+		Data []struct {
+			FullName    string `json:"full_name"`
+			Description string `json:"description"`
+			Stars       int    `json:"stars_count"`
+			CloneURL    string `json:"clone_url"`
+			HTMLURL     string `json:"html_url"`
+		} `json:"data"`
+	}
+	header := http.Header{}
+	if f.Token != "" {
+		header.Set("Authorization", "token "+f.Token)
+	}
+	if err := getJSON(ctx, f.URL+"/api/v1/repos/search?"+query.Encode(), header, &response); err != nil {
+		return nil, err
+	}
+
+	repos := make([]Repo, 0, len(response.Data))
+	for _, item := range response.Data {
+		repos = append(repos, Repo{Forge: f.Name, FullName: item.FullName, Description: item.Description, Stars: item.Stars, CloneURL: item.CloneURL, WebURL: item.HTMLURL})
+	}
+	return repos, nil
+}
+
+// getJSON 发送GET请求并解析JSON响应
+func getJSON(ctx context.Context, rawURL string, header http.Header, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 %s 失败: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s 返回 %s: %s", req.URL.Host, resp.Status, strings.TrimSpace(string(truncateBody(body))))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("解析 %s 的响应失败: %w", req.URL.Host, err)
+	}
+	return nil
+}
+
+// truncateBody 截断错误响应，避免输出整个HTML页面
+func truncateBody(body []byte) []byte {
+	if len(body) > 200 {
+		return body[:200]
+	}
+	return body
+}
+
+// Resolve 将导入源解析为仓库地址和所属平台
+//
+// 支持 <平台名称>:<owner>/<repo> 简写、<平台主机>/<owner>/<repo> 简写和平台上的完整地址。
+// 不属于任何已配置平台时返回 false。
+func Resolve(forges []config.Forge, source string) (string, *config.Forge, bool) {
+	for i := range forges {
+		f := &forges[i]
+		if path, ok := strings.CutPrefix(source, f.Name+":"); ok && path != "" && !strings.HasPrefix(path, "//") {
+			return f.URL + "/" + strings.TrimSuffix(strings.Trim(path, "/"), ".git") + ".git", f, true
+		}
+	}
+	for i := range forges {
+		f := &forges[i]
+		if path, ok := strings.CutPrefix(source, host(f.URL)+"/"); ok && path != "" {
+			return f.URL + "/" + path, f, true
+		}
+		if strings.HasPrefix(source, f.URL+"/") {
+			return source, f, true
+		}
+	}
+	return "", nil, false
+}
+
+// host 返回地址中的主机名（包含端口）
+func host(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
@@ -0,0 +1,99 @@
+package forge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"skill-hub/internal/config"
+)
+
+func TestSearch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("search") != "lint" || r.Header.Get("PRIVATE-TOKEN") != "glpat" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`[{"path_with_namespace": "team/lint-skills", "description": "Lint", "star_count": 7, "http_url_to_repo": "https://gl/team/lint-skills.git", "web_url": "https://gl/team/lint-skills"}]`))
+	})
+	mux.HandleFunc("/api/v1/repos/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "lint" || r.Header.Get("Authorization") != "token gt" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"ok": true, "data": [{"full_name": "ops/lint", "description": "Gitea lint", "stars_count": 3, "clone_url": "https://gt/ops/lint.git", "html_url": "https://gt/ops/lint"}]}`))
+	})
+	mux.HandleFunc("/api/v3/search/repositories", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"items": [{"full_name": "acme/lint", "description": "GHE", "stargazers_count": 12, "clone_url": "https://ghe/acme/lint.git", "html_url": "https://ghe/acme/lint"}]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		forge config.Forge
+		want  Repo
+	}{
+		{config.Forge{Name: "corp", Type: config.ForgeGitLab, URL: server.URL, Token: "glpat"},
+			Repo{Forge: "corp", FullName: "team/lint-skills", Description: "Lint", Stars: 7, CloneURL: "https://gl/team/lint-skills.git", WebURL: "https://gl/team/lint-skills"}},
+		{config.Forge{Name: "gitea", Type: config.ForgeGitea, URL: server.URL, Token: "gt"},
+			Repo{Forge: "gitea", FullName: "ops/lint", Description: "Gitea lint", Stars: 3, CloneURL: "https://gt/ops/lint.git", WebURL: "https://gt/ops/lint"}},
+		{config.Forge{Name: "ghe", Type: config.ForgeGitHub, URL: server.URL, Token: "gh"},
+			Repo{Forge: "ghe", FullName: "acme/lint", Description: "GHE", Stars: 12, CloneURL: "https://ghe/acme/lint.git", WebURL: "https://ghe/acme/lint"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.forge.Type, func(t *testing.T) {
+			repos, err := Search(context.Background(), tt.forge, "lint", 5)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(repos) != 1 || repos[0] != tt.want {
+				t.Errorf("Search() = %+v, want %+v", repos, tt.want)
+			}
+		})
+	}
+
+	t.Run("error status", func(t *testing.T) {
+		f := config.Forge{Name: "corp", Type: config.ForgeGitLab, URL: server.URL, Token: "wrong"}
+		if _, err := Search(context.Background(), f, "lint", 5); err == nil {
+			t.Error("Search() should return error for non-200 response")
+		}
+	})
+}
+
+func TestResolve(t *testing.T) {
+	forges := []config.Forge{
+		{Name: "github", Type: config.ForgeGitHub, URL: "https://github.com"},
+		{Name: "corp", Type: config.ForgeGitLab, URL: "https://gitlab.example.com", Token: "glpat"},
+	}
+	tests := []struct {
+		source string
+		url    string
+		forge  string
+	}{
+		{"corp:team/skills", "https://gitlab.example.com/team/skills.git", "corp"},
+		{"corp:team/skills.git", "https://gitlab.example.com/team/skills.git", "corp"},
+		{"gitlab.example.com/team/skills", "https://gitlab.example.com/team/skills", "corp"},
+		{"https://gitlab.example.com/team/skills.git", "https://gitlab.example.com/team/skills.git", "corp"},
+		{"github:owner/repo", "https://github.com/owner/repo.git", "github"},
+		{"https://gitea.example.com/a/b", "", ""},
+		{"git@gitlab.example.com:team/skills.git", "", ""},
+	}
+	for _, tt := range tests {
+		url, f, ok := Resolve(forges, tt.source)
+		if tt.forge == "" {
+			if ok {
+				t.Errorf("Resolve(%q) = %q, %v, want no match", tt.source, url, f.Name)
+			}
+			continue
+		}
+		if !ok || url != tt.url || f.Name != tt.forge {
+			t.Errorf("Resolve(%q) = %q, %v, %v, want %q, %s", tt.source, url, f, ok, tt.url, tt.forge)
+		}
+	}
+}
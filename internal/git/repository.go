@@ -1,26 +1,47 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
 	gitconfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"skill-hub/internal/config"
+	"skill-hub/internal/credentials"
 )
 
+// ErrDirtyWorktree 工作树中有未提交的修改，拉取或切换分支会覆盖这些修改
+var ErrDirtyWorktree = errors.New("技能仓库有未提交的修改")
+
+// ErrNothingToCommit 没有要提交的更改
+var ErrNothingToCommit = errors.New("没有要提交的更改")
+
+// ErrUnrelatedStaged 暂存区中有指定路径之外的修改，只提交指定路径时会一并提交这些修改
+var ErrUnrelatedStaged = errors.New("技能仓库暂存区中有其他修改")
+
 // Repository 表示一个Git仓库
 type Repository struct {
 	path       string
 	repo       *git.Repository
 	remoteURL  string
 	remoteName string
+	// token HTTPS认证使用的token，为空时使用配置中的 git_token
+	token string
+	// progress 克隆进度的输出位置，为nil时输出到标准输出
+	progress io.Writer
+	// branch 克隆的分支，为空时使用远程仓库的默认分支
+	branch string
 }
 
 // NewRepository 创建或打开一个Git仓库
@@ -56,12 +77,13 @@ func NewSkillsRepository() (*Repository, error) {
 		return nil, err
 	}
 
-	skillsDir, err := config.GetSkillsDir()
+	// 打开技能仓库的根目录，skills 子目录不是独立的Git仓库
+	repoDir, err := config.GetRepoPath()
 	if err != nil {
 		return nil, err
 	}
 
-	repo, err := NewRepository(skillsDir)
+	repo, err := NewRepository(repoDir)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +114,12 @@ func (r *Repository) SetRemote(url string) error {
 	return err
 }
 
+// CloneBranch 克隆远程仓库的指定分支，branch 为空时使用远程仓库的默认分支
+func (r *Repository) CloneBranch(url, branch string) error {
+	r.branch = branch
+	return r.Clone(url)
+}
+
 // Clone 克隆远程仓库
 func (r *Repository) Clone(url string) error {
 	// 如果目录非空，先清理
@@ -108,9 +136,16 @@ func (r *Repository) Clone(url string) error {
 	}
 
 	// 准备克隆选项
+	progress := r.progress
+	if progress == nil {
+		progress = os.Stdout
+	}
 	cloneOpts := &git.CloneOptions{
 		URL:      url,
-		Progress: os.Stdout,
+		Progress: progress,
+	}
+	if r.branch != "" {
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(r.branch)
 	}
 
 	// 根据URL类型设置认证
@@ -123,7 +158,7 @@ func (r *Repository) Clone(url string) error {
 		cloneOpts.Auth = auth
 	} else if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
 		// HTTP/HTTPS URL
-		auth, err := r.getAuth()
+		auth, err := r.getAuth(url)
 		if err != nil {
 			return err
 		}
@@ -139,7 +174,7 @@ func (r *Repository) Clone(url string) error {
 			if httpsURL != "" {
 				fmt.Printf("SSH克隆失败，尝试HTTPS URL: %s\n", httpsURL)
 				cloneOpts.URL = httpsURL
-				cloneOpts.Auth, _ = r.getAuth() // 使用HTTP认证
+				cloneOpts.Auth, _ = r.getAuth(httpsURL) // 使用HTTP认证
 				repo, err = git.PlainClone(r.path, false, cloneOpts)
 				if err == nil {
 					fmt.Println("✅ 使用HTTPS URL克隆成功")
@@ -167,37 +202,65 @@ func (r *Repository) Clone(url string) error {
 	return nil
 }
 
-// Pull 拉取最新更改
+// CloneInto 将远程仓库克隆到指定目录
+func CloneInto(url, path string) (*Repository, error) {
+	return CloneIntoWithToken(url, path, "")
+}
+
+// CloneIntoWithToken 使用指定的token克隆远程仓库，用于 GitLab、Gitea 等平台各自的访问令牌
+func CloneIntoWithToken(url, path, token string) (*Repository, error) {
+	return CloneIntoWithProgress(url, path, token, os.Stdout)
+}
+
+// CloneIntoWithProgress 克隆远程仓库，克隆进度输出到 progress，并行克隆多个仓库时传入 io.Discard
+func CloneIntoWithProgress(url, path, token string, progress io.Writer) (*Repository, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	r := &Repository{
+		path:       path,
+		remoteName: "origin",
+		token:      token,
+		progress:   progress,
+	}
+	if err := r.Clone(url); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Pull 拉取最新更改，工作树中有未提交的修改时返回 ErrDirtyWorktree，不拉取
 func (r *Repository) Pull() error {
 	if r.remoteURL == "" {
 		return fmt.Errorf("未设置远程仓库URL")
 	}
+	if err := r.checkClean(); err != nil {
+		return err
+	}
 
 	worktree, err := r.repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("获取工作树失败: %w", err)
 	}
 
-	// 获取认证信息
-	var auth transport.AuthMethod
-	if strings.HasPrefix(r.remoteURL, "git@") || strings.Contains(r.remoteURL, "ssh://") {
-		auth, err = r.getSSHAuth()
-		if err != nil {
-			return fmt.Errorf("SSH认证失败: %w", err)
-		}
-	} else {
-		httpAuth, err := r.getAuth()
-		if err != nil {
-			return err
-		}
-		auth = httpAuth
+	auth, err := r.remoteAuth()
+	if err != nil {
+		return err
 	}
+	branch := r.currentBranch()
+
+	restore, err := r.preserveUntracked()
+	if err != nil {
+		return err
+	}
+	defer restore()
 
 	err = worktree.Pull(&git.PullOptions{
 		RemoteName:    r.remoteName,
 		Auth:          auth,
 		Progress:      os.Stdout,
-		ReferenceName: plumbing.NewBranchReferenceName("main"),
+		ReferenceName: branch,
 		SingleBranch:  true,
 	})
 
@@ -208,22 +271,363 @@ func (r *Repository) Pull() error {
 	return err
 }
 
-// Push 推送本地更改
+// remoteAuth 返回访问远程仓库的认证信息，SSH地址使用SSH密钥，否则使用token
+func (r *Repository) remoteAuth() (transport.AuthMethod, error) {
+	if strings.HasPrefix(r.remoteURL, "git@") || strings.Contains(r.remoteURL, "ssh://") {
+		auth, err := r.getSSHAuth()
+		if err != nil {
+			return nil, fmt.Errorf("SSH认证失败: %w", err)
+		}
+		return auth, nil
+	}
+	auth, err := r.getAuth(r.remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	if auth == nil {
+		return nil, nil
+	}
+	return auth, nil
+}
+
+// currentBranch 返回当前分支，远程仓库的默认分支不一定是 main
+func (r *Repository) currentBranch() plumbing.ReferenceName {
+	if head, err := r.repo.Head(); err == nil && head.Name().IsBranch() {
+		return head.Name()
+	}
+	return plumbing.NewBranchReferenceName("main")
+}
+
+// Branch 返回当前分支名，HEAD 不指向分支时返回空
+func (r *Repository) Branch() string {
+	if head, err := r.repo.Head(); err == nil && head.Name().IsBranch() {
+		return head.Name().Short()
+	}
+	return ""
+}
+
+// DirtyFiles 返回有未提交修改的已跟踪文件，按路径排序；未跟踪的文件不包含在内
+func (r *Repository) DirtyFiles() ([]string, error) {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("获取工作树失败: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("获取状态失败: %w", err)
+	}
+	var files []string
+	for file, fs := range status {
+		if fs.Worktree == git.Untracked && fs.Staging == git.Untracked {
+			continue
+		}
+		if fs.Worktree != git.Unmodified || fs.Staging != git.Unmodified {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// checkClean 检查工作树中没有未提交的修改，有修改时返回包装 ErrDirtyWorktree 的错误并列出文件
+//
+// go-git 在移动HEAD之后才检查工作树，修改会导致拉取或切换分支中途失败、HEAD与工作树不一致。
+func (r *Repository) checkClean() error {
+	files, err := r.DirtyFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	const max = 5
+	list := files
+	if len(list) > max {
+		list = append(list[:max:max], fmt.Sprintf("等 %d 个文件", len(files)))
+	}
+	return fmt.Errorf("%w: %s", ErrDirtyWorktree, strings.Join(list, ", "))
+}
+
+// SwitchBranch 切换到指定分支，本地没有该分支时从远程仓库获取并创建跟踪分支
+//
+// 工作树中有未提交的修改时返回 ErrDirtyWorktree，不切换。
+func (r *Repository) SwitchBranch(ctx context.Context, branch string) error {
+	if err := r.checkClean(); err != nil {
+		return err
+	}
+	local := plumbing.NewBranchReferenceName(branch)
+	if _, err := r.repo.Reference(local, true); err != nil {
+		if r.remoteURL == "" {
+			return fmt.Errorf("分支 %s 不存在", branch)
+		}
+		auth, err := r.remoteAuth()
+		if err != nil {
+			return err
+		}
+		remoteRef := plumbing.NewRemoteReferenceName(r.remoteName, branch)
+		err = r.repo.FetchContext(ctx, &git.FetchOptions{
+			RemoteName: r.remoteName,
+			Auth:       auth,
+			RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("+%s:%s", local, remoteRef))},
+		})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			return fmt.Errorf("获取远程分支 %s 失败: %w", branch, err)
+		}
+		ref, err := r.repo.Reference(remoteRef, true)
+		if err != nil {
+			return fmt.Errorf("远程仓库没有分支 %s", branch)
+		}
+		if err := r.repo.Storer.SetReference(plumbing.NewHashReference(local, ref.Hash())); err != nil {
+			return fmt.Errorf("创建分支失败: %w", err)
+		}
+		if err := r.repo.CreateBranch(&gitconfig.Branch{Name: branch, Remote: r.remoteName, Merge: local}); err != nil && err != git.ErrBranchExists {
+			return fmt.Errorf("设置跟踪分支失败: %w", err)
+		}
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("获取工作树失败: %w", err)
+	}
+	restore, err := r.preserveUntracked()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: local}); err != nil {
+		return fmt.Errorf("切换到分支 %s 失败: %w", branch, err)
+	}
+	return nil
+}
+
+// CommitPaths 只暂存并提交指定的文件或目录（相对仓库根目录），其余修改保留在工作树中
+//
+// 提交包含整个暂存区，暂存区中已有指定路径之外的修改时返回 ErrUnrelatedStaged，不提交。
+func (r *Repository) CommitPaths(message string, paths []string) error {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("获取工作树失败: %w", err)
+	}
+	before, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("检查状态失败: %w", err)
+	}
+	var unrelated []string
+	for file, fs := range before {
+		if fs.Staging != git.Unmodified && fs.Staging != git.Untracked && !withinPaths(file, paths) {
+			unrelated = append(unrelated, file)
+		}
+	}
+	if len(unrelated) > 0 {
+		sort.Strings(unrelated)
+		return fmt.Errorf("%w: %s", ErrUnrelatedStaged, strings.Join(unrelated, ", "))
+	}
+
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(r.path, filepath.FromSlash(path))); os.IsNotExist(err) {
+			continue
+		}
+		if _, err := worktree.Add(filepath.ToSlash(path)); err != nil {
+			return fmt.Errorf("添加文件 %s 失败: %w", path, err)
+		}
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("检查状态失败: %w", err)
+	}
+	staged := false
+	for _, fs := range status {
+		if fs.Staging != git.Unmodified && fs.Staging != git.Untracked {
+			staged = true
+			break
+		}
+	}
+	if !staged {
+		return ErrNothingToCommit
+	}
+	if _, err := worktree.Commit(message, &git.CommitOptions{}); err != nil {
+		return fmt.Errorf("提交失败: %w", err)
+	}
+	return nil
+}
+
+// withinPaths 检查文件（相对仓库根目录）是否为 paths 中的某个文件或位于其中的目录下
+func withinPaths(file string, paths []string) bool {
+	for _, path := range paths {
+		path = strings.TrimSuffix(filepath.ToSlash(path), "/")
+		if file == path || strings.HasPrefix(file, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteChanges 远程分支最新提交与HEAD之间的差异
+type RemoteChanges struct {
+	// Files 有变化的文件，相对仓库根目录
+	Files  []string
+	head   *object.Tree
+	remote *object.Tree
+}
+
+// FetchChanges 从远程仓库获取当前分支的最新提交并与HEAD比较，不修改工作树和本地分支
+func (r *Repository) FetchChanges(ctx context.Context) (*RemoteChanges, error) {
+	if r.remoteURL == "" {
+		return nil, fmt.Errorf("未设置远程仓库URL")
+	}
+	auth, err := r.remoteAuth()
+	if err != nil {
+		return nil, err
+	}
+	branch := r.currentBranch()
+	remoteRef := plumbing.NewRemoteReferenceName(r.remoteName, branch.Short())
+	err = r.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: r.remoteName,
+		Auth:       auth,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("+%s:%s", branch, remoteRef))},
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return nil, fmt.Errorf("获取远程仓库失败: %w", err)
+	}
+
+	head, err := r.commitTree(plumbing.HEAD)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := r.commitTree(remoteRef)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTreeWithOptions(ctx, head, remote, nil)
+	if err != nil {
+		return nil, fmt.Errorf("比较远程提交失败: %w", err)
+	}
+	result := &RemoteChanges{head: head, remote: remote}
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		result.Files = append(result.Files, name)
+	}
+	return result, nil
+}
+
+// commitTree 返回引用指向的提交的目录树
+func (r *Repository) commitTree(name plumbing.ReferenceName) (*object.Tree, error) {
+	ref, err := r.repo.Reference(name, true)
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", name.Short(), err)
+	}
+	commit, err := r.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("读取提交失败: %w", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("读取提交失败: %w", err)
+	}
+	return tree, nil
+}
+
+// RemoteFile 返回文件在远程最新提交中的内容，文件不存在时返回 false
+func (c *RemoteChanges) RemoteFile(path string) (string, bool) {
+	return treeFile(c.remote, path)
+}
+
+// HeadFile 返回文件在HEAD中的内容，文件不存在时返回 false
+func (c *RemoteChanges) HeadFile(path string) (string, bool) {
+	return treeFile(c.head, path)
+}
+
+func treeFile(tree *object.Tree, path string) (string, bool) {
+	file, err := tree.File(path)
+	if err != nil {
+		return "", false
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return "", false
+	}
+	return content, true
+}
+
+// Push 推送当前分支的本地提交
 func (r *Repository) Push() error {
 	if r.remoteURL == "" {
 		return fmt.Errorf("未设置远程仓库URL")
 	}
 
-	auth, err := r.getAuth()
+	auth, err := r.remoteAuth()
 	if err != nil {
 		return err
 	}
+	branch := r.currentBranch()
 
-	return r.repo.Push(&git.PushOptions{
+	err = r.repo.Push(&git.PushOptions{
 		RemoteName: r.remoteName,
 		Auth:       auth,
 		Progress:   os.Stdout,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("%s:%s", branch, branch))},
 	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+	return err
+}
+
+// HasTag 检查本地仓库中是否存在标签
+func (r *Repository) HasTag(name string) bool {
+	_, err := r.repo.Tag(name)
+	return err == nil
+}
+
+// CreateTag 在HEAD上创建附注标签，标签已存在时返回错误
+func (r *Repository) CreateTag(name, message string) error {
+	head, err := r.repo.Head()
+	if err != nil {
+		return fmt.Errorf("获取HEAD失败: %w", err)
+	}
+	if _, err := r.repo.CreateTag(name, head.Hash(), &git.CreateTagOptions{Message: message}); err != nil {
+		if errors.Is(err, git.ErrTagExists) {
+			return fmt.Errorf("标签 %s 已存在", name)
+		}
+		return fmt.Errorf("创建标签 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteTag 删除本地标签
+func (r *Repository) DeleteTag(name string) error {
+	if err := r.repo.DeleteTag(name); err != nil {
+		return fmt.Errorf("删除标签 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// PushTag 推送标签到远程仓库
+func (r *Repository) PushTag(name string) error {
+	if r.remoteURL == "" {
+		return fmt.Errorf("未设置远程仓库URL")
+	}
+
+	auth, err := r.remoteAuth()
+	if err != nil {
+		return err
+	}
+	ref := plumbing.NewTagReferenceName(name)
+
+	err = r.repo.Push(&git.PushOptions{
+		RemoteName: r.remoteName,
+		Auth:       auth,
+		Progress:   os.Stdout,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("%s:%s", ref, ref))},
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+	return err
 }
 
 // Commit 提交更改
@@ -246,7 +650,7 @@ func (r *Repository) Commit(message string) error {
 	}
 
 	if status.IsClean() {
-		return fmt.Errorf("没有要提交的更改")
+		return ErrNothingToCommit
 	}
 
 	// 提交更改
@@ -286,6 +690,167 @@ func (r *Repository) GetLatestCommit() (string, error) {
 	return fmt.Sprintf("%s: %s", commit.Hash.String()[:8], commit.Message), nil
 }
 
+// HeadHash 获取HEAD指向的提交哈希
+func (r *Repository) HeadHash() (string, error) {
+	ref, err := r.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("获取HEAD失败: %w", err)
+	}
+	return ref.Hash().String(), nil
+}
+
+// ResetTo 将当前分支回退到指定提交，保留工作树中未提交的修改
+func (r *Repository) ResetTo(hash string) error {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("获取工作树失败: %w", err)
+	}
+	restore, err := r.preserveUntracked()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	if err := worktree.Reset(&git.ResetOptions{
+		Commit: plumbing.NewHash(hash),
+		Mode:   git.MergeReset,
+	}); err != nil {
+		return fmt.Errorf("回退到提交 %s 失败: %w", hash[:8], err)
+	}
+	return nil
+}
+
+// ResetPaths 将目录（相对仓库根目录）中的文件恢复为HEAD中的内容，删除HEAD中不存在的文件
+func (r *Repository) ResetPaths(dirs []string) error {
+	head, err := r.repo.Head()
+	if err != nil {
+		return fmt.Errorf("获取HEAD失败: %w", err)
+	}
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return fmt.Errorf("读取提交失败: %w", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return fmt.Errorf("读取提交失败: %w", err)
+	}
+
+	var names []string
+	for _, dir := range dirs {
+		prefix := strings.TrimSuffix(filepath.ToSlash(dir), "/") + "/"
+		tracked := make(map[string]bool)
+		err := tree.Files().ForEach(func(f *object.File) error {
+			if strings.HasPrefix(f.Name, prefix) {
+				tracked[f.Name] = true
+				names = append(names, f.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("读取提交失败: %w", err)
+		}
+		// HEAD中不存在的文件直接删除
+		root := filepath.Join(r.path, filepath.FromSlash(dir))
+		err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(r.path, path)
+			if err != nil || tracked[filepath.ToSlash(rel)] {
+				return nil
+			}
+			return os.Remove(path)
+		})
+		if err != nil {
+			return fmt.Errorf("恢复 %s 失败: %w", dir, err)
+		}
+		if len(tracked) == 0 {
+			os.RemoveAll(root)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("获取工作树失败: %w", err)
+	}
+	if err := worktree.Reset(&git.ResetOptions{Commit: head.Hash(), Mode: git.HardReset, Files: names}); err != nil {
+		return fmt.Errorf("恢复 %s 失败: %w", strings.Join(dirs, ", "), err)
+	}
+	return nil
+}
+
+// Tracked 检查文件是否在索引中，file 为相对仓库根目录的路径
+func (r *Repository) Tracked(file string) (bool, error) {
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return false, fmt.Errorf("读取索引失败: %w", err)
+	}
+	_, err = idx.Entry(filepath.ToSlash(file))
+	return err == nil, nil
+}
+
+// preserveUntracked 保存工作树中不在索引里的文件（包括被忽略的文件），返回的函数恢复拉取或回退后被删除的文件
+//
+// go-git 的 Pull 和 Reset 会删除未跟踪的文件，而技能仓库根目录中保存着
+// state.json、registry.json 等不纳入版本控制的状态文件。
+func (r *Repository) preserveUntracked() (func(), error) {
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("读取索引失败: %w", err)
+	}
+	tracked := make(map[string]bool, len(idx.Entries))
+	for _, entry := range idx.Entries {
+		tracked[entry.Name] = true
+	}
+
+	type savedFile struct {
+		data []byte
+		mode os.FileMode
+	}
+	saved := make(map[string]savedFile)
+	err = filepath.WalkDir(r.path, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(r.path, path)
+		if err != nil || tracked[filepath.ToSlash(rel)] || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		saved[path] = savedFile{data: data, mode: info.Mode().Perm()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存未跟踪的文件失败: %w", err)
+	}
+
+	return func() {
+		for path, file := range saved {
+			if _, err := os.Lstat(path); !os.IsNotExist(err) {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+				os.WriteFile(path, file.data, file.mode)
+			}
+		}
+	}, nil
+}
+
 // IsInitialized 检查仓库是否已初始化
 func (r *Repository) IsInitialized() bool {
 	return r.remoteURL != ""
@@ -296,8 +861,24 @@ func (r *Repository) GetPath() string {
 	return r.path
 }
 
-// getAuth 获取认证信息
-func (r *Repository) getAuth() (*http.BasicAuth, error) {
+// getAuth 获取访问仓库地址的认证信息：指定的token、'skill-hub login' 保存的主机凭据，最后是配置中的 git_token
+func (r *Repository) getAuth(url string) (*http.BasicAuth, error) {
+	if r.token != "" {
+		return &http.BasicAuth{Username: "token", Password: r.token}, nil
+	}
+
+	cred, ok, err := credentials.Lookup(url)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		username := cred.Username
+		if username == "" {
+			username = "token"
+		}
+		return &http.BasicAuth{Username: username, Password: cred.Token}, nil
+	}
+
 	cfg, err := config.GetConfig()
 	if err != nil {
 		return nil, err
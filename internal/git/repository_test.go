@@ -1,6 +1,12 @@
 package git
 
 import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -60,3 +66,347 @@ func TestGetSSHAuth(t *testing.T) {
 		t.Logf("getSSHAuth returned expected error: %v", err)
 	}
 }
+
+func TestPullKeepsUntrackedFiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	writeFile(t, filepath.Join(home, ".gitconfig"), "[user]\n\tname = test\n\temail = test@example.com\n")
+	writeFile(t, filepath.Join(home, ".skill-hub", "config.yaml"), "repo_path: \"~/.skill-hub/repo\"\n")
+
+	upstreamDir := t.TempDir()
+	upstream, err := NewRepository(upstreamDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(upstreamDir, "skills", "alpha", "SKILL.md"), "v1")
+	writeFile(t, filepath.Join(upstreamDir, ".gitignore"), "state.json\n")
+	if err := upstream.Commit("init"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	cloneDir := filepath.Join(t.TempDir(), "repo")
+	clone, err := CloneIntoWithProgress(upstreamDir, cloneDir, "", io.Discard)
+	if err != nil {
+		t.Fatalf("CloneIntoWithProgress() error = %v", err)
+	}
+	head, err := clone.HeadHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 技能仓库根目录中不纳入版本控制的状态文件，包括被忽略的文件
+	writeFile(t, filepath.Join(cloneDir, "registry.json"), "{}")
+	writeFile(t, filepath.Join(cloneDir, "state.json"), "{}")
+	if tracked, err := clone.Tracked("registry.json"); err != nil || tracked {
+		t.Errorf("Tracked(registry.json) = %v, %v", tracked, err)
+	}
+	if tracked, err := clone.Tracked("skills/alpha/SKILL.md"); err != nil || !tracked {
+		t.Errorf("Tracked(skills/alpha/SKILL.md) = %v, %v", tracked, err)
+	}
+
+	writeFile(t, filepath.Join(upstreamDir, "skills", "alpha", "SKILL.md"), "v2")
+	if err := upstream.Commit("update"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := clone.Pull(); err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+	assertFile(t, filepath.Join(cloneDir, "skills", "alpha", "SKILL.md"), "v2")
+	assertFile(t, filepath.Join(cloneDir, "registry.json"), "{}")
+	assertFile(t, filepath.Join(cloneDir, "state.json"), "{}")
+
+	if err := clone.ResetTo(head); err != nil {
+		t.Fatalf("ResetTo() error = %v", err)
+	}
+	assertFile(t, filepath.Join(cloneDir, "skills", "alpha", "SKILL.md"), "v1")
+	assertFile(t, filepath.Join(cloneDir, "state.json"), "{}")
+}
+
+func TestFetchChanges(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	writeFile(t, filepath.Join(home, ".gitconfig"), "[user]\n\tname = test\n\temail = test@example.com\n")
+	writeFile(t, filepath.Join(home, ".skill-hub", "config.yaml"), "repo_path: \"~/.skill-hub/repo\"\n")
+
+	upstreamDir := t.TempDir()
+	upstream, err := NewRepository(upstreamDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(upstreamDir, "skills", "alpha", "SKILL.md"), "v1")
+	writeFile(t, filepath.Join(upstreamDir, "skills", "beta", "SKILL.md"), "v1")
+	if err := upstream.Commit("init"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	cloneDir := filepath.Join(t.TempDir(), "repo")
+	clone, err := CloneIntoWithProgress(upstreamDir, cloneDir, "", io.Discard)
+	if err != nil {
+		t.Fatalf("CloneIntoWithProgress() error = %v", err)
+	}
+
+	writeFile(t, filepath.Join(upstreamDir, "skills", "alpha", "SKILL.md"), "v2")
+	if err := upstream.Commit("update"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	changes, err := clone.FetchChanges(context.Background())
+	if err != nil {
+		t.Fatalf("FetchChanges() error = %v", err)
+	}
+	if len(changes.Files) != 1 || changes.Files[0] != "skills/alpha/SKILL.md" {
+		t.Errorf("Files = %v, want [skills/alpha/SKILL.md]", changes.Files)
+	}
+	if content, ok := changes.RemoteFile("skills/alpha/SKILL.md"); !ok || content != "v2" {
+		t.Errorf("RemoteFile() = %q, %v, want v2", content, ok)
+	}
+	if content, ok := changes.HeadFile("skills/alpha/SKILL.md"); !ok || content != "v1" {
+		t.Errorf("HeadFile() = %q, %v, want v1", content, ok)
+	}
+	// 只获取远程提交，不修改工作树
+	assertFile(t, filepath.Join(cloneDir, "skills", "alpha", "SKILL.md"), "v1")
+}
+
+func TestResetPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	writeFile(t, filepath.Join(home, ".gitconfig"), "[user]\n\tname = test\n\temail = test@example.com\n")
+
+	dir := t.TempDir()
+	repo, err := NewRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "skills", "alpha", "SKILL.md"), "v1")
+	writeFile(t, filepath.Join(dir, "skills", "beta", "SKILL.md"), "v1")
+	if err := repo.Commit("init"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	writeFile(t, filepath.Join(dir, "skills", "alpha", "SKILL.md"), "local")
+	writeFile(t, filepath.Join(dir, "skills", "alpha", "extra.md"), "extra")
+	writeFile(t, filepath.Join(dir, "skills", "beta", "SKILL.md"), "local")
+	writeFile(t, filepath.Join(dir, "skills", "gamma", "SKILL.md"), "untracked")
+	if err := repo.ResetPaths([]string{"skills/alpha", "skills/gamma"}); err != nil {
+		t.Fatalf("ResetPaths() error = %v", err)
+	}
+	assertFile(t, filepath.Join(dir, "skills", "alpha", "SKILL.md"), "v1")
+	assertFile(t, filepath.Join(dir, "skills", "beta", "SKILL.md"), "local")
+	for _, path := range []string{filepath.Join("skills", "alpha", "extra.md"), filepath.Join("skills", "gamma")} {
+		if _, err := os.Stat(filepath.Join(dir, path)); !os.IsNotExist(err) {
+			t.Errorf("%s 应被删除", path)
+		}
+	}
+}
+
+func TestPullRefusesDirtyWorktree(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	writeFile(t, filepath.Join(home, ".gitconfig"), "[user]\n\tname = test\n\temail = test@example.com\n")
+
+	upstreamDir := t.TempDir()
+	upstream, err := NewRepository(upstreamDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(upstreamDir, "skills", "alpha", "SKILL.md"), "v1")
+	writeFile(t, filepath.Join(upstreamDir, "skills", "beta", "SKILL.md"), "v1")
+	if err := upstream.Commit("init"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	cloneDir := filepath.Join(t.TempDir(), "repo")
+	clone, err := CloneIntoWithProgress(upstreamDir, cloneDir, "", io.Discard)
+	if err != nil {
+		t.Fatalf("CloneIntoWithProgress() error = %v", err)
+	}
+	head, _ := clone.HeadHash()
+
+	writeFile(t, filepath.Join(upstreamDir, "skills", "alpha", "SKILL.md"), "v2")
+	if err := upstream.Commit("update"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	writeFile(t, filepath.Join(cloneDir, "skills", "beta", "SKILL.md"), "local")
+	writeFile(t, filepath.Join(cloneDir, "state.json"), "{}")
+
+	files, err := clone.DirtyFiles()
+	if err != nil || len(files) != 1 || files[0] != "skills/beta/SKILL.md" {
+		t.Fatalf("DirtyFiles() = %v, %v, want [skills/beta/SKILL.md]", files, err)
+	}
+	if err := clone.Pull(); !errors.Is(err, ErrDirtyWorktree) {
+		t.Fatalf("Pull() error = %v, want ErrDirtyWorktree", err)
+	}
+	// 拒绝拉取时不移动HEAD，也不修改工作树
+	if after, _ := clone.HeadHash(); after != head {
+		t.Errorf("HEAD = %s, want %s", after, head)
+	}
+	assertFile(t, filepath.Join(cloneDir, "skills", "alpha", "SKILL.md"), "v1")
+	assertFile(t, filepath.Join(cloneDir, "skills", "beta", "SKILL.md"), "local")
+}
+
+func TestSwitchBranch(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	writeFile(t, filepath.Join(home, ".gitconfig"), "[user]\n\tname = test\n\temail = test@example.com\n")
+
+	upstreamDir := t.TempDir()
+	upstream, err := NewRepository(upstreamDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(upstreamDir, "skills", "alpha", "SKILL.md"), "stable")
+	if err := upstream.Commit("init"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	defaultBranch := upstream.Branch()
+	if err := upstream.CheckoutBranch("next"); err != nil {
+		t.Fatalf("CheckoutBranch() error = %v", err)
+	}
+	writeFile(t, filepath.Join(upstreamDir, "skills", "alpha", "SKILL.md"), "next")
+	if err := upstream.Commit("next"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	cloneDir := filepath.Join(t.TempDir(), "repo")
+	clone := &Repository{path: cloneDir, remoteName: "origin", progress: io.Discard}
+	if err := clone.CloneBranch(upstreamDir, defaultBranch); err != nil {
+		t.Fatalf("CloneBranch() error = %v", err)
+	}
+	if clone.Branch() != defaultBranch {
+		t.Fatalf("Branch() = %q, want %q", clone.Branch(), defaultBranch)
+	}
+	assertFile(t, filepath.Join(cloneDir, "skills", "alpha", "SKILL.md"), "stable")
+
+	if err := clone.SwitchBranch(context.Background(), "next"); err != nil {
+		t.Fatalf("SwitchBranch() error = %v", err)
+	}
+	if clone.Branch() != "next" {
+		t.Errorf("Branch() = %q, want next", clone.Branch())
+	}
+	assertFile(t, filepath.Join(cloneDir, "skills", "alpha", "SKILL.md"), "next")
+
+	writeFile(t, filepath.Join(cloneDir, "skills", "alpha", "SKILL.md"), "local")
+	if err := clone.SwitchBranch(context.Background(), defaultBranch); !errors.Is(err, ErrDirtyWorktree) {
+		t.Fatalf("SwitchBranch() error = %v, want ErrDirtyWorktree", err)
+	}
+	if clone.Branch() != "next" {
+		t.Errorf("Branch() = %q after refused switch, want next", clone.Branch())
+	}
+	if err := clone.SwitchBranch(context.Background(), "missing"); err == nil {
+		t.Error("SwitchBranch() to missing branch should fail")
+	}
+}
+
+func TestCommitPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	writeFile(t, filepath.Join(home, ".gitconfig"), "[user]\n\tname = test\n\temail = test@example.com\n")
+
+	dir := t.TempDir()
+	repo, err := NewRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "skills", "alpha", "SKILL.md"), "v1")
+	writeFile(t, filepath.Join(dir, "skills", "beta", "SKILL.md"), "v1")
+	if err := repo.Commit("init"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	writeFile(t, filepath.Join(dir, "skills", "alpha", "SKILL.md"), "v2")
+	writeFile(t, filepath.Join(dir, "skills", "alpha", "CHANGELOG.md"), "# Changelog")
+	writeFile(t, filepath.Join(dir, "skills", "beta", "SKILL.md"), "v2")
+	if err := repo.CommitPaths("publish alpha", []string{"skills/alpha", "registry.json"}); err != nil {
+		t.Fatalf("CommitPaths() error = %v", err)
+	}
+	files, err := repo.DirtyFiles()
+	if err != nil || len(files) != 1 || files[0] != "skills/beta/SKILL.md" {
+		t.Errorf("DirtyFiles() = %v, %v, want [skills/beta/SKILL.md]", files, err)
+	}
+	if err := repo.CommitPaths("again", []string{"skills/alpha"}); err == nil {
+		t.Error("CommitPaths() without changes should fail")
+	}
+
+	// 暂存区中有其他路径的修改时拒绝提交，不把它们带进提交
+	worktree, err := repo.repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Add("skills/beta/SKILL.md"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "skills", "alpha", "SKILL.md"), "v3")
+	if err := repo.CommitPaths("publish alpha", []string{"skills/alpha"}); !errors.Is(err, ErrUnrelatedStaged) || !strings.Contains(err.Error(), "skills/beta/SKILL.md") {
+		t.Errorf("CommitPaths() error = %v, want ErrUnrelatedStaged", err)
+	}
+	if files, _ := repo.DirtyFiles(); len(files) != 2 {
+		t.Errorf("DirtyFiles() = %v, want alpha and beta still uncommitted", files)
+	}
+}
+
+func TestPushTag(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	writeFile(t, filepath.Join(home, ".gitconfig"), "[user]\n\tname = test\n\temail = test@example.com\n")
+
+	writeFile(t, filepath.Join(home, ".skill-hub", "config.yaml"), "repo_path: \"~/.skill-hub/repo\"\n")
+
+	upstreamDir := t.TempDir()
+	upstream, err := NewRepository(upstreamDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(upstreamDir, "skills", "alpha", "SKILL.md"), "v1")
+	if err := upstream.Commit("init"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	clone, err := CloneIntoWithProgress(upstreamDir, filepath.Join(t.TempDir(), "repo"), "", io.Discard)
+	if err != nil {
+		t.Fatalf("CloneIntoWithProgress() error = %v", err)
+	}
+	tag := SkillTag("alpha", "1.0.0")
+	if tag != "alpha/v1.0.0" {
+		t.Errorf("SkillTag() = %q", tag)
+	}
+	if err := clone.CreateTag(tag, "release alpha"); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if err := clone.CreateTag(tag, "again"); err == nil {
+		t.Error("CreateTag() with existing tag should fail")
+	}
+	if upstream.HasTag(tag) {
+		t.Fatal("tag should not exist upstream before PushTag()")
+	}
+	if err := clone.PushTag(tag); err != nil {
+		t.Fatalf("PushTag() error = %v", err)
+	}
+	if !upstream.HasTag(tag) {
+		t.Error("PushTag() did not push the tag")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("读取 %s 失败: %v", path, err)
+		return
+	}
+	if string(data) != want {
+		t.Errorf("%s = %q, want %q", path, data, want)
+	}
+}
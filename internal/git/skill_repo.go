@@ -1,14 +1,18 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 	"skill-hub/internal/config"
+	"skill-hub/internal/pack"
 	"skill-hub/pkg/spec"
 )
 
@@ -34,22 +38,12 @@ func (sr *SkillRepository) Sync() error {
 		return fmt.Errorf("技能仓库未初始化，请先设置远程仓库URL")
 	}
 
-	// 获取当前状态
-	status, err := sr.repo.GetStatus()
-	if err != nil {
-		return fmt.Errorf("获取仓库状态失败: %w", err)
-	}
-
-	// 检查是否有未提交的更改
-	if strings.Contains(status, " M ") || strings.Contains(status, "?? ") {
-		fmt.Println("⚠️  检测到未提交的更改，建议先提交或暂存")
-		fmt.Println("   使用 'skill-hub git commit' 提交更改")
-		fmt.Println("   或使用 'skill-hub git stash' 暂存更改")
-	}
-
-	// 拉取最新更改
+	// 拉取前检查工作树，有未提交的修改时不拉取
 	fmt.Println("从远程仓库拉取最新更改...")
 	if err := sr.repo.Pull(); err != nil {
+		if errors.Is(err, ErrDirtyWorktree) {
+			return fmt.Errorf("%w\n   使用 'skill-hub git commit' 提交这些修改后再同步", err)
+		}
 		return fmt.Errorf("拉取失败: %w", err)
 	}
 
@@ -93,27 +87,111 @@ func (sr *SkillRepository) PushChanges(message string) error {
 	return nil
 }
 
-// CloneRemote 克隆远程技能仓库
-func (sr *SkillRepository) CloneRemote(url string) error {
+// PublishSkill 只提交技能目录（registry.json 纳入版本控制时一并提交）并推送到远程仓库
+//
+// 技能仓库中其他未提交的修改保留在工作树中，不随本次提交推送。
+func (sr *SkillRepository) PublishSkill(skillID, message string) error {
+	if !sr.repo.IsInitialized() {
+		return fmt.Errorf("技能仓库未设置远程仓库URL，无法推送")
+	}
+	paths := []string{path.Join("skills", skillID)}
+	if tracked, err := sr.repo.Tracked("registry.json"); err == nil && tracked {
+		paths = append(paths, "registry.json")
+	}
+	if err := sr.repo.CommitPaths(message, paths); err != nil {
+		return err
+	}
+	if err := sr.repo.Push(); err != nil {
+		return fmt.Errorf("推送失败: %w", err)
+	}
+	return nil
+}
+
+// SkillTag 返回技能版本的发布标签，格式与 Go 模块的子目录标签相同，例如 git-expert/v1.2.0
+func SkillTag(skillID, version string) string {
+	return skillID + "/v" + version
+}
+
+// CheckRelease 检查技能版本能否发布：技能仓库设置了远程仓库，且该版本的标签不存在
+func (sr *SkillRepository) CheckRelease(skillID, version string) error {
+	if !sr.repo.IsInitialized() {
+		return fmt.Errorf("技能仓库未设置远程仓库URL，无法推送")
+	}
+	if tag := SkillTag(skillID, version); sr.repo.HasTag(tag) {
+		return fmt.Errorf("标签 %s 已存在，该版本已发布", tag)
+	}
+	return nil
+}
+
+// ReleaseSkill 提交技能目录，为该版本创建标签，并将提交和标签推送到远程仓库，返回标签名
+//
+// 提交前检查标签是否已存在，避免重复发布同一版本。
+func (sr *SkillRepository) ReleaseSkill(skillID, version, message string) (string, error) {
+	if err := sr.CheckRelease(skillID, version); err != nil {
+		return "", err
+	}
+	tag := SkillTag(skillID, version)
+	paths := []string{path.Join("skills", skillID)}
+	if tracked, err := sr.repo.Tracked("registry.json"); err == nil && tracked {
+		paths = append(paths, "registry.json")
+	}
+
+	// 技能目录没有修改时（例如上次推送失败后重试）直接为HEAD创建标签
+	if err := sr.repo.CommitPaths(message, paths); err != nil && !errors.Is(err, ErrNothingToCommit) {
+		return "", err
+	}
+	if err := sr.repo.CreateTag(tag, message); err != nil {
+		return "", err
+	}
+
+	// 推送失败时删除本地标签，重试时重新创建
+	if err := sr.repo.Push(); err != nil {
+		sr.repo.DeleteTag(tag)
+		return "", fmt.Errorf("推送失败: %w", err)
+	}
+	if err := sr.repo.PushTag(tag); err != nil {
+		sr.repo.DeleteTag(tag)
+		return "", fmt.Errorf("推送标签 %s 失败: %w", tag, err)
+	}
+	return tag, nil
+}
+
+// Branch 返回技能仓库当前的分支
+func (sr *SkillRepository) Branch() string {
+	return sr.repo.Branch()
+}
+
+// SwitchBranch 切换技能仓库跟踪的分支，工作树中有未提交的修改时返回 ErrDirtyWorktree
+func (sr *SkillRepository) SwitchBranch(ctx context.Context, branch string) error {
+	return sr.repo.SwitchBranch(ctx, branch)
+}
+
+// DirtyFiles 返回技能仓库中有未提交修改的已跟踪文件
+func (sr *SkillRepository) DirtyFiles() ([]string, error) {
+	return sr.repo.DirtyFiles()
+}
+
+// CloneRemote 克隆远程技能仓库，branch 为空时使用远程仓库的默认分支
+func (sr *SkillRepository) CloneRemote(url, branch string) error {
 	fmt.Printf("正在克隆远程技能仓库: %s\n", url)
 
-	// 获取技能目录路径
-	skillsDir, err := config.GetSkillsDir()
+	// 获取技能仓库路径
+	repoDir, err := config.GetRepoPath()
 	if err != nil {
 		return err
 	}
 
 	// 如果目录已存在，备份
-	if _, err := os.Stat(skillsDir); err == nil {
-		backupDir := skillsDir + ".bak." + time.Now().Format("20060102-150405")
-		fmt.Printf("备份现有技能目录到: %s\n", backupDir)
-		if err := os.Rename(skillsDir, backupDir); err != nil {
+	if _, err := os.Stat(repoDir); err == nil {
+		backupDir := repoDir + ".bak." + time.Now().Format("20060102-150405")
+		fmt.Printf("备份现有技能仓库到: %s\n", backupDir)
+		if err := os.Rename(repoDir, backupDir); err != nil {
 			return fmt.Errorf("备份失败: %w", err)
 		}
 	}
 
 	// 克隆仓库
-	if err := sr.repo.Clone(url); err != nil {
+	if err := sr.repo.CloneBranch(url, branch); err != nil {
 		return fmt.Errorf("克隆失败: %w", err)
 	}
 
@@ -136,7 +214,7 @@ func (sr *SkillRepository) CloneRemote(url string) error {
 		"default_tool":       cfg.DefaultTool,
 		"git_remote_url":     url,
 		"git_token":          cfg.GitToken,
-		"git_branch":         cfg.GitBranch,
+		"git_branch":         sr.repo.Branch(),
 	}
 
 	yamlData, err := yaml.Marshal(configData)
@@ -152,6 +230,58 @@ func (sr *SkillRepository) CloneRemote(url string) error {
 	return nil
 }
 
+// Head 获取技能仓库当前的提交哈希，用于同步失败时回退
+func (sr *SkillRepository) Head() (string, error) {
+	return sr.repo.HeadHash()
+}
+
+// Rollback 将技能仓库回退到同步前的提交
+func (sr *SkillRepository) Rollback(hash string) error {
+	return sr.repo.ResetTo(hash)
+}
+
+// ResetSkills 将技能目录恢复为当前提交中的内容，丢弃工作树中的修改
+func (sr *SkillRepository) ResetSkills(skillIDs []string) error {
+	dirs := make([]string, 0, len(skillIDs))
+	for _, id := range skillIDs {
+		dirs = append(dirs, "skills/"+id)
+	}
+	return sr.repo.ResetPaths(dirs)
+}
+
+// RemoteSkillChanges 从远程仓库获取最新提交，不修改工作树，返回内容有变化的技能ID到远程 SKILL.md 内容的映射
+//
+// 远程仓库中已删除的技能不返回。技能目录为包含 SKILL.md 的最近一级目录，支持命名空间下的技能。
+func (sr *SkillRepository) RemoteSkillChanges(ctx context.Context) (map[string]string, error) {
+	changes, err := sr.repo.FetchChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	skills := make(map[string]string)
+	for _, file := range changes.Files {
+		if !strings.HasPrefix(file, "skills/") {
+			continue
+		}
+		for dir := path.Dir(file); dir != "skills" && dir != "."; dir = path.Dir(dir) {
+			content, ok := changes.RemoteFile(dir + "/SKILL.md")
+			if !ok {
+				if _, existed := changes.HeadFile(dir + "/SKILL.md"); existed {
+					break
+				}
+				continue
+			}
+			skills[strings.TrimPrefix(dir, "skills/")] = content
+			break
+		}
+	}
+	return skills, nil
+}
+
+// Tracked 检查文件是否纳入了技能仓库的版本控制，file 为相对仓库根目录的路径
+func (sr *SkillRepository) Tracked(file string) (bool, error) {
+	return sr.repo.Tracked(file)
+}
+
 // GetStatus 获取技能仓库状态
 func (sr *SkillRepository) GetStatus() (string, error) {
 	if !sr.repo.IsInitialized() {
@@ -183,7 +313,11 @@ func (sr *SkillRepository) ListSkillsFromRemote() ([]*spec.Skill, error) {
 	if err := sr.Sync(); err != nil {
 		return nil, err
 	}
+	return sr.ListSkills()
+}
 
+// ListSkills 列出本地技能仓库中的技能，不同步远程仓库
+func (sr *SkillRepository) ListSkills() ([]*spec.Skill, error) {
 	// 加载所有技能
 	skillsDir, err := config.GetSkillsDir()
 	if err != nil {
@@ -223,6 +357,9 @@ func (sr *SkillRepository) loadSkillsFromDirectory(dir string, recursive bool) (
 			if recursive {
 				subSkills, _ := sr.loadSkillsFromDirectory(skillDir, true)
 				skills = append(skills, subSkills...)
+			} else if spec.ValidateSkillID(skillID) == nil {
+				// 不含SKILL.md的目录视为命名空间
+				skills = append(skills, sr.loadNamespaceSkills(skillDir, skillID)...)
 			}
 			continue
 		}
@@ -233,6 +370,27 @@ func (sr *SkillRepository) loadSkillsFromDirectory(dir string, recursive bool) (
 	return skills, nil
 }
 
+// loadNamespaceSkills 加载命名空间目录中的技能，技能ID为 <命名空间>/<名称>
+func (sr *SkillRepository) loadNamespaceSkills(dir, namespace string) []*spec.Skill {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var skills []*spec.Skill
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		skill, err := sr.loadSkill(filepath.Join(dir, entry.Name()), spec.JoinSkillID(namespace, entry.Name()))
+		if err != nil {
+			continue
+		}
+		skills = append(skills, skill)
+	}
+	return skills
+}
+
 // loadSkill 加载单个技能
 func (sr *SkillRepository) loadSkill(skillDir, skillID string) (*spec.Skill, error) {
 	// 只支持SKILL.md格式
@@ -314,6 +472,11 @@ func (sr *SkillRepository) loadSkillFromMarkdown(mdPath, skillID string) (*spec.
 	// 设置兼容性（默认为所有工具）
 	skill.Compatibility = "Designed for Cursor and Claude Code (or similar AI coding assistants)"
 
+	// 设置许可证
+	if license, ok := skillData["license"].(string); ok {
+		skill.License = license
+	}
+
 	return skill, nil
 }
 
@@ -423,6 +586,11 @@ func (sr *SkillRepository) UpdateRegistry() error {
 		return err
 	}
 
+	skillsDir, err := config.GetSkillsDir()
+	if err != nil {
+		return err
+	}
+
 	// 创建注册表
 	registry := spec.Registry{
 		Version: "1.0",
@@ -438,15 +606,25 @@ func (sr *SkillRepository) UpdateRegistry() error {
 			Description:   skill.Description,
 			Tags:          skill.Tags,
 			Compatibility: skill.Compatibility,
+			License:       skill.License,
+			Channel:       skill.Channel,
+		}
+		if digest, err := pack.SkillDigest(filepath.Join(skillsDir, skill.ID)); err == nil {
+			metadata.SHA256 = digest
 		}
 		registry.Skills = append(registry.Skills, metadata)
 	}
 
-	// 保存注册表
+	// 保存注册表，保留远程仓库维护的使用统计
 	registryPath, err := config.GetRegistryPath()
 	if err != nil {
 		return err
 	}
+	if data, err := os.ReadFile(registryPath); err == nil {
+		if previous, err := spec.ParseRegistry(data); err == nil {
+			registry.CarryStats(previous)
+		}
+	}
 
 	registryData, err := yaml.Marshal(registry)
 	if err != nil {
@@ -0,0 +1,82 @@
+// Package logging 配置全局的分级日志（log/slog）
+//
+// 各模块直接使用 slog.Debug、slog.Warn 等记录日志，由命令行根据 --log-level 和 --log-file
+// 调用 Setup 设置级别和输出位置。日志与命令的普通输出分开：默认写入标准错误，只输出警告和错误。
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 日志级别
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// DefaultLevel 未指定日志级别时使用的级别
+const DefaultLevel = LevelWarn
+
+// Levels 返回可用的日志级别
+func Levels() []string {
+	return []string{LevelDebug, LevelInfo, LevelWarn, LevelError}
+}
+
+// ParseLevel 解析日志级别，不区分大小写，warning 等同于 warn
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case LevelDebug:
+		return slog.LevelDebug, nil
+	case LevelInfo:
+		return slog.LevelInfo, nil
+	case "", LevelWarn, "warning":
+		return slog.LevelWarn, nil
+	case LevelError:
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("无效的日志级别: %s，可用选项: %s", level, strings.Join(Levels(), ", "))
+}
+
+// Setup 设置全局日志的级别和输出位置，返回关闭日志文件的函数
+//
+// file 为空时日志写入标准错误，不带时间；否则追加写入该文件（目录不存在时创建），带时间。
+func Setup(level, file string) (func() error, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	var w io.Writer = os.Stderr
+	closeFn := func() error { return nil }
+	opts := &slog.HandlerOptions{Level: lvl}
+	if file == "" {
+		opts.ReplaceAttr = dropTime
+	} else {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return nil, fmt.Errorf("创建日志目录失败: %w", err)
+		}
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("打开日志文件失败: %w", err)
+		}
+		w, closeFn = f, f.Close
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, opts)))
+	return closeFn, nil
+}
+
+// dropTime 去掉终端输出中的时间属性
+func dropTime(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 && attr.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return attr
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		level   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"", slog.LevelWarn, false},
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warning", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"trace", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.level)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, wantErr %v", tt.level, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestSetupLogFile(t *testing.T) {
+	saved := slog.Default()
+	defer slog.SetDefault(saved)
+
+	path := filepath.Join(t.TempDir(), "logs", "skill-hub.log")
+	closeFn, err := Setup(LevelInfo, path)
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	slog.Debug("hidden")
+	slog.Info("applied", "skill", "git-expert")
+	if err := closeFn(); err != nil {
+		t.Fatalf("close error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	log := string(data)
+	if strings.Contains(log, "hidden") {
+		t.Errorf("debug message written at info level:\n%s", log)
+	}
+	if !strings.Contains(log, "level=INFO msg=applied skill=git-expert") || !strings.Contains(log, "time=") {
+		t.Errorf("unexpected log content:\n%s", log)
+	}
+
+	if _, err := Setup("verbose", ""); err == nil {
+		t.Error("Setup() expected error for invalid level")
+	}
+}
@@ -0,0 +1,237 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/pkg/spec"
+)
+
+// maxDescriptionLength Agent Skills 规范中 description 的最大长度
+const maxDescriptionLength = 1024
+
+// namePattern 规范的技能名称：小写字母、数字和连字符
+var namePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// metadataFields 移入 metadata 的根级别字段，Claude Code 和社区技能常把它们写在根级别
+var metadataFields = []string{"version", "author"}
+
+// ConvertClaudeSkill 将 Claude Code / Anthropic 技能仓库中的 SKILL.md 转换为 skill-hub 使用的规范格式
+//
+// 社区技能常见的差异会被修正并返回对应的警告：缺少frontmatter、name 不符合规范或与技能ID不同
+// （原名称保存在 metadata.display_name 中）、缺少 description（取正文第一段）、
+// allowed-tools 写成列表、根级别的 version 和 author、metadata 中的非字符串值。
+// 其他字段（包括 Claude Code 专有的字段）原样保留。不需要转换时返回原内容和空警告。
+//
+// renameName 为false时技能ID不是技能作者指定的（例如取自导入源名称），符合规范的 name
+// 即使与技能ID不同也保持不变，只补全缺失的和改写不符合规范的 name。
+func ConvertClaudeSkill(content, skillID string, renameName bool) (string, []string, error) {
+	var warnings []string
+	text := content
+	if strings.Contains(text, "\r\n") {
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+		warnings = append(warnings, "换行符已从 CRLF 转换为 LF")
+	}
+
+	frontmatter, body := "", text
+	if strings.HasPrefix(text, "---\n") {
+		var err error
+		if frontmatter, body, err = splitFrontmatter(text); err != nil {
+			return "", nil, err
+		}
+	} else {
+		warnings = append(warnings, "缺少frontmatter，已根据技能ID和正文生成")
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(frontmatter), &doc); err != nil {
+		return "", nil, fmt.Errorf("解析frontmatter失败: %w", err)
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	if len(doc.Content) > 0 {
+		if doc.Content[0].Kind != yaml.MappingNode {
+			return "", nil, fmt.Errorf("无效的frontmatter: 必须是键值映射")
+		}
+		root = doc.Content[0]
+	}
+
+	_, name := spec.SplitSkillID(skillID)
+	var displayName string
+	if value := mappingValue(root, "name"); value == nil {
+		setMappingValue(root, "name", name)
+		warnings = append(warnings, fmt.Sprintf("缺少 name，已设置为 %s", name))
+	} else if value.Value != name && (renameName || !namePattern.MatchString(value.Value)) {
+		if namePattern.MatchString(value.Value) {
+			warnings = append(warnings, fmt.Sprintf("name '%s' 与技能ID不同，已改为 %s", value.Value, name))
+		} else {
+			displayName = value.Value
+			warnings = append(warnings, fmt.Sprintf("name '%s' 不符合规范，已改为 %s，原名称保存在 metadata.display_name", value.Value, name))
+		}
+		setMappingValue(root, "name", name)
+	}
+
+	if value := mappingValue(root, "description"); value == nil || strings.TrimSpace(value.Value) == "" {
+		description := firstParagraph(body)
+		if description == "" {
+			return "", nil, fmt.Errorf("缺少 description，且正文中没有可用作描述的段落")
+		}
+		setMappingValue(root, "description", truncate(description, maxDescriptionLength))
+		warnings = append(warnings, "缺少 description，已使用正文第一段")
+	} else if len(value.Value) > maxDescriptionLength {
+		value.Value = truncate(value.Value, maxDescriptionLength)
+		warnings = append(warnings, fmt.Sprintf("description 超过 %d 个字符，已截断", maxDescriptionLength))
+	}
+
+	if value := mappingValue(root, "allowed-tools"); value != nil && value.Kind == yaml.SequenceNode {
+		var tools []interface{}
+		if err := value.Decode(&tools); err != nil {
+			return "", nil, fmt.Errorf("解析 allowed-tools 失败: %w", err)
+		}
+		setMappingValue(root, "allowed-tools", spec.JoinAllowedTools(spec.ParseAllowedTools(tools)))
+		warnings = append(warnings, "allowed-tools 列表已转换为空格分隔的字符串")
+	}
+
+	metadata := mappingValue(root, "metadata")
+	if metadata != nil && metadata.Kind != yaml.MappingNode {
+		return "", nil, fmt.Errorf("无效的 metadata: 必须是键值映射")
+	}
+	ensureMetadata := func() *yaml.Node {
+		if metadata == nil {
+			metadata = &yaml.Node{Kind: yaml.MappingNode}
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "metadata"}, metadata)
+		}
+		return metadata
+	}
+	if displayName != "" && mappingValue(metadata, "display_name") == nil {
+		setMappingValue(ensureMetadata(), "display_name", displayName)
+	}
+	for _, field := range metadataFields {
+		value := mappingValue(root, field)
+		if value == nil {
+			continue
+		}
+		removeMappingKey(root, field)
+		if mappingValue(metadata, field) != nil {
+			warnings = append(warnings, fmt.Sprintf("根级别的 %s 与 metadata.%s 重复，已删除", field, field))
+			continue
+		}
+		setMappingValue(ensureMetadata(), field, scalarText(value))
+		warnings = append(warnings, fmt.Sprintf("根级别的 %s 已移入 metadata", field))
+	}
+	if metadata != nil {
+		for i := 0; i+1 < len(metadata.Content); i += 2 {
+			key, value := metadata.Content[i].Value, metadata.Content[i+1]
+			if value.Kind == yaml.ScalarNode && value.Tag == "!!str" {
+				continue
+			}
+			metadata.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: scalarText(value)}
+			warnings = append(warnings, fmt.Sprintf("metadata.%s 已转换为字符串", key))
+		}
+	}
+
+	if len(warnings) == 0 {
+		return content, nil, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return "", nil, fmt.Errorf("序列化frontmatter失败: %w", err)
+	}
+	encoder.Close()
+	formatted, err := spec.FormatSkillMD([]byte("---\n" + buf.String() + "---\n" + body))
+	if err != nil {
+		return "", nil, err
+	}
+	return string(formatted), warnings, nil
+}
+
+// mappingValue 返回映射中键对应的值节点，不存在时返回nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue 设置映射中键的字符串值，不存在时追加
+func setMappingValue(node *yaml.Node, key, value string) {
+	scalar := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = scalar
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, scalar)
+}
+
+// removeMappingKey 删除映射中的键
+func removeMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// scalarText 返回值节点的文本，列表以逗号连接，与 metadata.tags 的格式一致
+func scalarText(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			items = append(items, scalarText(item))
+		}
+		return strings.Join(items, ",")
+	}
+	var value interface{}
+	node.Decode(&value)
+	return fmt.Sprint(value)
+}
+
+// firstParagraph 返回正文中第一个非标题段落，合并为一行
+func firstParagraph(body string) string {
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			if len(lines) > 0 {
+				return strings.Join(lines, " ")
+			}
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, "```"):
+			if len(lines) > 0 {
+				return strings.Join(lines, " ")
+			}
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// truncate 按字节长度截断字符串，不截断多字节字符
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,105 @@
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/internal/adapter/marker"
+	"skill-hub/pkg/spec"
+)
+
+// CursorRulesDir Cursor 新版规则目录（相对项目根目录）
+const CursorRulesDir = ".cursor/rules"
+
+// CursorUserRulesFile .cursorrules 中标记块之外的用户内容迁移后的文件名
+const CursorUserRulesFile = "cursorrules.mdc"
+
+// CursorRulesToMDC 将 .cursorrules 拆分为 .cursor/rules 下的 .mdc 文件
+//
+// 每个技能标记块生成一个 <skill-id>.mdc（命名空间技能为 <命名空间>-<名称>.mdc），文件内保留标记块以便后续 apply/remove/feedback 识别；
+// 标记块之外的用户内容保存在 cursorrules.mdc 中。descriptions 提供各技能的规则描述。
+// 返回文件名到内容的映射以及解析时修复的标记问题。
+func CursorRulesToMDC(content string, descriptions map[string]string) (map[string]string, []marker.Issue) {
+	doc := marker.Parse(content)
+	files := make(map[string]string)
+
+	for _, id := range doc.IDs() {
+		body, _ := doc.Get(id)
+		description := descriptions[id]
+		if description == "" {
+			description = "Skill Hub 技能 " + id
+		}
+		files[spec.FlatSkillID(id)+".mdc"] = mdcFrontmatter(description) + marker.Block(id, body)
+	}
+
+	// 移除技能块后剩余的就是用户内容
+	for _, id := range doc.IDs() {
+		doc.Remove(id)
+	}
+	if user := strings.TrimSpace(doc.String()); user != "" {
+		files[CursorUserRulesFile] = mdcFrontmatter("从 .cursorrules 迁移的项目规则") + user + "\n"
+	}
+
+	return files, doc.Issues()
+}
+
+// MDCToCursorRules 将 .mdc 文件合并回 .cursorrules，是 CursorRulesToMDC 的逆操作
+//
+// 只处理包含技能标记块的文件和 cursorrules.mdc，返回合并后的内容和被合并的文件名。
+func MDCToCursorRules(files map[string]string) (string, []string, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := marker.Parse("")
+	var merged []string
+
+	// 用户内容放在最前面，与原 .cursorrules 的常见布局一致
+	if content, ok := files[CursorUserRulesFile]; ok {
+		result = marker.Parse(strings.TrimSpace(stripMDCFrontmatter(content)) + "\n")
+		merged = append(merged, CursorUserRulesFile)
+	}
+
+	for _, name := range names {
+		if name == CursorUserRulesFile {
+			continue
+		}
+		doc := marker.Parse(stripMDCFrontmatter(files[name]))
+		ids := doc.IDs()
+		if len(ids) == 0 {
+			continue
+		}
+		for _, id := range ids {
+			if _, exists := result.Get(id); exists {
+				return "", nil, fmt.Errorf("技能 %s 出现在多个规则文件中", id)
+			}
+			body, _ := doc.Get(id)
+			result.Upsert(id, body)
+		}
+		merged = append(merged, name)
+	}
+
+	return result.String(), merged, nil
+}
+
+// mdcFrontmatter 生成始终生效的 .mdc 规则frontmatter
+func mdcFrontmatter(description string) string {
+	// 描述中可能含有冒号等YAML特殊字符，按YAML标量输出
+	quoted, err := yaml.Marshal(strings.Join(strings.Fields(description), " "))
+	if err != nil {
+		quoted = []byte("\"\"\n")
+	}
+	return "---\ndescription: " + string(quoted) + "globs:\nalwaysApply: true\n---\n"
+}
+
+// stripMDCFrontmatter 去掉 .mdc 文件的frontmatter
+func stripMDCFrontmatter(content string) string {
+	if _, body, err := splitFrontmatter(content); err == nil {
+		return body
+	}
+	return content
+}
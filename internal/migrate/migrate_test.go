@@ -0,0 +1,203 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/internal/adapter/marker"
+	"skill-hub/pkg/spec"
+)
+
+func TestLegacySkillRoundTrip(t *testing.T) {
+	legacy := `id: git-expert
+name: Git Expert
+version: 1.2.0
+author: team
+description: "Git 专家: 提交规范"
+tags: [git, vcs]
+compatibility: Designed for Cursor
+allowed-tools: [Bash(git:*), Read]
+variables:
+  - name: LANGUAGE
+    default: zh
+    description: 输出语言
+dependencies: [base]
+`
+	prompt := "# Git\n\n使用 {{.LANGUAGE}} 回答\n"
+
+	content, err := LegacyToSkillMD([]byte(legacy), []byte(prompt))
+	if err != nil {
+		t.Fatalf("LegacyToSkillMD() error = %v", err)
+	}
+	if !strings.HasSuffix(content, "---\n"+prompt) {
+		t.Errorf("Body not preserved:\n%s", content)
+	}
+
+	frontmatter, _, err := splitFrontmatter(content)
+	if err != nil {
+		t.Fatalf("splitFrontmatter() error = %v", err)
+	}
+	var fm skillFrontmatter
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		t.Fatalf("Invalid frontmatter: %v", err)
+	}
+	if fm.Name != "git-expert" || fm.Metadata["version"] != "1.2.0" || fm.Metadata["tags"] != "git,vcs" {
+		t.Errorf("Unexpected frontmatter: %+v", fm)
+	}
+	if len(fm.Variables) != 1 || fm.Variables[0].Default != "zh" {
+		t.Errorf("Variables not preserved: %+v", fm.Variables)
+	}
+
+	skillYAML, gotPrompt, err := SkillMDToLegacy(content)
+	if err != nil {
+		t.Fatalf("SkillMDToLegacy() error = %v", err)
+	}
+	if string(gotPrompt) != prompt {
+		t.Errorf("Prompt = %q, want %q", gotPrompt, prompt)
+	}
+
+	var skill spec.Skill
+	if err := yaml.Unmarshal(skillYAML, &skill); err != nil {
+		t.Fatalf("Invalid skill.yaml: %v", err)
+	}
+	if skill.ID != "git-expert" || skill.Name != "Git Expert" || skill.Version != "1.2.0" || skill.Author != "team" {
+		t.Errorf("Unexpected skill: %+v", skill)
+	}
+	if strings.Join(skill.Tags, ",") != "git,vcs" || strings.Join(skill.Dependencies, ",") != "base" {
+		t.Errorf("Lists not preserved: tags=%v deps=%v", skill.Tags, skill.Dependencies)
+	}
+	if strings.Join(skill.AllowedTools, " ") != "Bash(git:*) Read" {
+		t.Errorf("AllowedTools = %v", skill.AllowedTools)
+	}
+	if skill.Description != "Git 专家: 提交规范" {
+		t.Errorf("Description = %q", skill.Description)
+	}
+}
+
+func TestLegacyToSkillMDErrors(t *testing.T) {
+	if _, err := LegacyToSkillMD([]byte("description: x\n"), nil); err == nil {
+		t.Error("Expected error for skill.yaml without id")
+	}
+	if _, _, err := SkillMDToLegacy("# no frontmatter\n"); err == nil {
+		t.Error("Expected error for SKILL.md without frontmatter")
+	}
+}
+
+func TestCursorRulesRoundTrip(t *testing.T) {
+	content := "# 项目规则\n始终使用中文\n\n" +
+		marker.Block("git-expert", "Git 规范") + "\n" +
+		marker.Block("code-review", "审查: 关注安全")
+
+	files, issues := CursorRulesToMDC(content, map[string]string{"git-expert": "Git: 专家"})
+	if len(issues) != 0 {
+		t.Errorf("Unexpected issues: %v", issues)
+	}
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 files, got %d: %v", len(files), files)
+	}
+
+	gitRule := files["git-expert.mdc"]
+	if !strings.Contains(gitRule, "alwaysApply: true") || !strings.Contains(gitRule, marker.BeginLine("git-expert")) {
+		t.Errorf("Unexpected rule file:\n%s", gitRule)
+	}
+	var fm struct {
+		Description string `yaml:"description"`
+	}
+	frontmatter, _, _ := splitFrontmatter(gitRule)
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil || fm.Description != "Git: 专家" {
+		t.Errorf("Description not preserved: %q (%v)", fm.Description, err)
+	}
+	if !strings.Contains(files[CursorUserRulesFile], "始终使用中文") {
+		t.Errorf("User rules not preserved:\n%s", files[CursorUserRulesFile])
+	}
+
+	// 与迁移无关的规则文件不参与合并
+	files["other.mdc"] = "---\ndescription: other\n---\n其他规则\n"
+
+	merged, names, err := MDCToCursorRules(files)
+	if err != nil {
+		t.Fatalf("MDCToCursorRules() error = %v", err)
+	}
+	if len(names) != 3 {
+		t.Errorf("Expected 3 merged files, got %v", names)
+	}
+	doc := marker.Parse(merged)
+	for _, id := range []string{"git-expert", "code-review"} {
+		if _, ok := doc.Get(id); !ok {
+			t.Errorf("Block %s missing after round trip:\n%s", id, merged)
+		}
+	}
+	if !strings.HasPrefix(merged, "# 项目规则\n始终使用中文\n") || strings.Contains(merged, "其他规则") {
+		t.Errorf("Unexpected merged content:\n%s", merged)
+	}
+}
+
+func TestMDCToCursorRulesDuplicate(t *testing.T) {
+	files := map[string]string{
+		"a.mdc": marker.Block("git-expert", "a"),
+		"b.mdc": marker.Block("git-expert", "b"),
+	}
+	if _, _, err := MDCToCursorRules(files); err == nil {
+		t.Error("Expected error for skill in multiple rule files")
+	}
+}
+
+func TestConvertClaudeSkill(t *testing.T) {
+	content := "---\r\nname: PDF Processing\r\nversion: 1.2\r\nallowed-tools:\r\n  - Read\r\n  - Bash(python:*)\r\nmodel: claude-sonnet\r\nmetadata:\r\n  tags: [pdf, documents]\r\n---\r\n# PDF\r\n\r\nExtract text and tables\r\nfrom PDF files.\r\n\r\nMore details.\r\n"
+	converted, warnings, err := ConvertClaudeSkill(content, "pdf", true)
+	if err != nil {
+		t.Fatalf("ConvertClaudeSkill() error = %v", err)
+	}
+	if len(warnings) != 6 {
+		t.Errorf("warnings = %v", warnings)
+	}
+
+	frontmatter, body, err := splitFrontmatter(converted)
+	if err != nil {
+		t.Fatalf("splitFrontmatter() error = %v", err)
+	}
+	var fm map[string]interface{}
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		t.Fatal(err)
+	}
+	metadata, _ := fm["metadata"].(map[string]interface{})
+	for key, want := range map[string]interface{}{
+		"name":          "pdf",
+		"description":   "Extract text and tables from PDF files.",
+		"allowed-tools": "Read Bash(python:*)",
+		"model":         "claude-sonnet",
+	} {
+		if fm[key] != want {
+			t.Errorf("%s = %v, want %v", key, fm[key], want)
+		}
+	}
+	if _, ok := fm["version"]; ok {
+		t.Error("root-level version should be moved into metadata")
+	}
+	if metadata["version"] != "1.2" || metadata["display_name"] != "PDF Processing" || metadata["tags"] != "pdf,documents" {
+		t.Errorf("metadata = %v", metadata)
+	}
+	if !strings.HasPrefix(body, "# PDF\n\nExtract text") || strings.Contains(body, "\r") {
+		t.Errorf("body = %q", body)
+	}
+
+	// 已符合规范的技能不做修改
+	native := "---\nname: pdf\ndescription: Extract text from PDF files.\n---\n# PDF\n"
+	if got, warnings, err := ConvertClaudeSkill(native, "pdf", true); err != nil || got != native || len(warnings) != 0 {
+		t.Errorf("ConvertClaudeSkill(native) = %q, %v, %v", got, warnings, err)
+	}
+
+	// 技能ID不是作者指定的：规范的 name 保留，不规范的 name 仍然改写
+	kept := "---\nname: my-skill\ndescription: Demo\n---\n# Demo\n"
+	if got, warnings, err := ConvertClaudeSkill(kept, "bundle", false); err != nil || got != kept || len(warnings) != 0 {
+		t.Errorf("ConvertClaudeSkill(kept) = %q, %v, %v", got, warnings, err)
+	}
+	if got, _, err := ConvertClaudeSkill("---\nname: My Skill\ndescription: Demo\n---\n", "bundle", false); err != nil || !strings.Contains(got, "name: bundle\n") {
+		t.Errorf("ConvertClaudeSkill(invalid name) = %q, %v", got, err)
+	}
+
+	if _, _, err := ConvertClaudeSkill("# Empty\n", "empty", true); err == nil {
+		t.Error("ConvertClaudeSkill() expected error without description")
+	}
+}
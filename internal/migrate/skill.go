@@ -0,0 +1,161 @@
+// Package migrate 在旧格式与当前格式之间转换技能和规则文件
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/pkg/spec"
+)
+
+// 旧格式技能目录中的文件
+const (
+	LegacySkillFile  = "skill.yaml"
+	LegacyPromptFile = "prompt.md"
+	SkillFile        = "SKILL.md"
+)
+
+// skillFrontmatter SKILL.md 的frontmatter，字段顺序即输出顺序
+type skillFrontmatter struct {
+	Name          string             `yaml:"name"`
+	Description   string             `yaml:"description"`
+	License       string             `yaml:"license,omitempty"`
+	Compatibility string             `yaml:"compatibility,omitempty"`
+	AllowedTools  string             `yaml:"allowed-tools,omitempty"`
+	Metadata      map[string]string  `yaml:"metadata,omitempty"`
+	Variables     []spec.Variable    `yaml:"variables,omitempty"`
+	Requires      *spec.Requirements `yaml:"requires,omitempty"`
+	Claude        *spec.ClaudeConfig `yaml:"claude,omitempty"`
+}
+
+// LegacyToSkillMD 将 skill.yaml 和 prompt.md 合并为 Agent Skills 规范的 SKILL.md
+//
+// 根级别的 version、author、tags 和 dependencies 移入 metadata，
+// 与技能ID不同的显示名称保存在 metadata.display_name 中。
+func LegacyToSkillMD(skillYAML, prompt []byte) (string, error) {
+	var skill spec.Skill
+	if err := yaml.Unmarshal(skillYAML, &skill); err != nil {
+		return "", fmt.Errorf("解析%s失败: %w", LegacySkillFile, err)
+	}
+
+	id := skill.ID
+	if id == "" {
+		id = skill.Name
+	}
+	if id == "" {
+		return "", fmt.Errorf("%s 缺少 id 或 name 字段", LegacySkillFile)
+	}
+
+	fm := skillFrontmatter{
+		Name:          id,
+		Description:   skill.Description,
+		License:       skill.License,
+		Compatibility: skill.Compatibility,
+		AllowedTools:  spec.JoinAllowedTools(skill.AllowedTools),
+		Variables:     skill.Variables,
+		Requires:      skill.Requires,
+		Claude:        skill.Claude,
+		Metadata:      map[string]string{},
+	}
+	if skill.Name != "" && skill.Name != id {
+		fm.Metadata["display_name"] = skill.Name
+	}
+	if skill.Version != "" {
+		fm.Metadata["version"] = skill.Version
+	}
+	if skill.Author != "" {
+		fm.Metadata["author"] = skill.Author
+	}
+	if len(skill.Tags) > 0 {
+		fm.Metadata["tags"] = strings.Join(skill.Tags, ",")
+	}
+	if len(skill.Dependencies) > 0 {
+		fm.Metadata["dependencies"] = strings.Join(skill.Dependencies, ",")
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(fm); err != nil {
+		return "", fmt.Errorf("序列化frontmatter失败: %w", err)
+	}
+	encoder.Close()
+
+	body := strings.TrimLeft(string(prompt), "\n")
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	return "---\n" + buf.String() + "---\n" + body, nil
+}
+
+// SkillMDToLegacy 将 SKILL.md 拆分为旧格式的 skill.yaml 和 prompt.md，是 LegacyToSkillMD 的逆操作
+func SkillMDToLegacy(content string) ([]byte, []byte, error) {
+	frontmatter, body, err := splitFrontmatter(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fm skillFrontmatter
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		return nil, nil, fmt.Errorf("解析frontmatter失败: %w", err)
+	}
+	if fm.Name == "" {
+		return nil, nil, fmt.Errorf("%s 缺少 name 字段", SkillFile)
+	}
+
+	skill := spec.Skill{
+		ID:            fm.Name,
+		Name:          fm.Name,
+		Version:       fm.Metadata["version"],
+		Author:        fm.Metadata["author"],
+		Description:   fm.Description,
+		Tags:          splitList(fm.Metadata["tags"]),
+		Compatibility: fm.Compatibility,
+		License:       fm.License,
+		AllowedTools:  spec.ParseAllowedTools(fm.AllowedTools),
+		Variables:     fm.Variables,
+		Dependencies:  splitList(fm.Metadata["dependencies"]),
+		Requires:      fm.Requires,
+		Claude:        fm.Claude,
+	}
+	if name := fm.Metadata["display_name"]; name != "" {
+		skill.Name = name
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(skill); err != nil {
+		return nil, nil, fmt.Errorf("序列化%s失败: %w", LegacySkillFile, err)
+	}
+	encoder.Close()
+
+	return buf.Bytes(), []byte(strings.TrimLeft(body, "\n")), nil
+}
+
+// splitFrontmatter 拆分 SKILL.md 的frontmatter和正文
+func splitFrontmatter(content string) (string, string, error) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", "", fmt.Errorf("无效的%s格式: 缺少frontmatter", SkillFile)
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return "", "", fmt.Errorf("无效的%s格式: frontmatter没有正确结束", SkillFile)
+	}
+	frontmatter := content[4 : 4+end+1]
+	body := content[4+end+len("\n---"):]
+	return frontmatter, strings.TrimPrefix(body, "\n"), nil
+}
+
+// splitList 拆分逗号分隔的列表
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
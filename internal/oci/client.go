@@ -0,0 +1,400 @@
+// Package oci 将技能作为 OCI 制品推送到容器镜像仓库（ghcr.io 等）或从中拉取
+//
+// 制品的格式与 ORAS 一致：OCI 1.1 镜像清单，artifactType 为 ArtifactType，
+// config 为空描述符，唯一的层是 'skill-hub export' 生成的 tar.gz 归档，
+// 层的 org.opencontainers.image.title 注解为归档文件名，因此也可以用 oras pull 下载。
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 技能制品使用的媒体类型
+const (
+	ArtifactType      = "application/vnd.skill-hub.skill.v1"
+	LayerMediaType    = "application/vnd.skill-hub.skill.layer.v1.tar+gzip"
+	ManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	emptyMediaType    = "application/vnd.oci.empty.v1+json"
+)
+
+// 标准注解和技能注解
+const (
+	AnnotationTitle   = "org.opencontainers.image.title"
+	AnnotationCreated = "org.opencontainers.image.created"
+	AnnotationVersion = "org.opencontainers.image.version"
+	AnnotationSkillID = "dev.skill-hub.skill.id"
+)
+
+// maxManifestSize 清单的大小上限
+const maxManifestSize = 4 << 20
+
+// emptyConfig OCI 规范定义的空 config
+var emptyConfig = []byte("{}")
+
+// Descriptor OCI 内容描述符
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest OCI 镜像清单
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Client 访问 OCI 分发协议（/v2/）的仓库服务
+type Client struct {
+	http        *http.Client
+	credentials Credentials
+	token       string // 仓库服务颁发的 Bearer 令牌
+}
+
+// NewClient 创建客户端，凭据为空时匿名访问
+func NewClient(credentials Credentials) *Client {
+	return &Client{http: &http.Client{Timeout: 5 * time.Minute}, credentials: credentials}
+}
+
+// Push 推送技能归档，annotations 写入清单，返回清单的摘要
+func (c *Client) Push(ctx context.Context, ref Reference, archivePath string, annotations map[string]string) (string, error) {
+	if ref.Reference == "" || strings.HasPrefix(ref.Reference, "sha256:") {
+		return "", fmt.Errorf("推送需要指定标签: %s", ref)
+	}
+	layer, err := os.ReadFile(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("读取归档失败: %w", err)
+	}
+
+	config := Descriptor{MediaType: emptyMediaType, Digest: digestOf(emptyConfig), Size: int64(len(emptyConfig))}
+	layerDesc := Descriptor{
+		MediaType:   LayerMediaType,
+		Digest:      digestOf(layer),
+		Size:        int64(len(layer)),
+		Annotations: map[string]string{AnnotationTitle: filepath.Base(archivePath)},
+	}
+	if err := c.pushBlob(ctx, ref, config.Digest, emptyConfig); err != nil {
+		return "", err
+	}
+	if err := c.pushBlob(ctx, ref, layerDesc.Digest, layer); err != nil {
+		return "", err
+	}
+
+	manifestAnnotations := map[string]string{AnnotationCreated: time.Now().UTC().Format(time.RFC3339)}
+	for key, value := range annotations {
+		manifestAnnotations[key] = value
+	}
+	manifest, err := json.Marshal(Manifest{
+		SchemaVersion: 2,
+		MediaType:     ManifestMediaType,
+		ArtifactType:  ArtifactType,
+		Config:        config,
+		Layers:        []Descriptor{layerDesc},
+		Annotations:   manifestAnnotations,
+	})
+	if err != nil {
+		return "", fmt.Errorf("序列化清单失败: %w", err)
+	}
+
+	header := http.Header{"Content-Type": {ManifestMediaType}}
+	resp, err := c.do(ctx, ref, http.MethodPut, "/manifests/"+ref.Reference, header, manifest)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", statusError("推送清单", resp)
+	}
+	return digestOf(manifest), nil
+}
+
+// Pull 拉取技能制品的归档层到 destDir，返回归档路径和清单
+func (c *Client) Pull(ctx context.Context, ref Reference, destDir string) (string, *Manifest, error) {
+	if ref.Reference == "" {
+		ref.Reference = "latest"
+	}
+	header := http.Header{"Accept": {ManifestMediaType}}
+	resp, err := c.do(ctx, ref, http.MethodGet, "/manifests/"+ref.Reference, header, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, statusError("获取清单", resp)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return "", nil, fmt.Errorf("读取清单失败: %w", err)
+	}
+	if strings.HasPrefix(ref.Reference, "sha256:") && digestOf(data) != ref.Reference {
+		return "", nil, fmt.Errorf("清单摘要不匹配: 期望 %s，实际 %s", ref.Reference, digestOf(data))
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", nil, fmt.Errorf("解析清单失败: %w", err)
+	}
+	layer, ok := skillLayer(&manifest)
+	if !ok {
+		return "", nil, fmt.Errorf("%s 不是技能制品（artifactType: %s）", ref, manifest.ArtifactType)
+	}
+
+	blob, err := c.pullBlob(ctx, ref, layer)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+	name := filepath.Base(layer.Annotations[AnnotationTitle])
+	if !strings.HasSuffix(name, ".tar.gz") && !strings.HasSuffix(name, ".tgz") {
+		name = "skill.tar.gz"
+	}
+	archivePath := filepath.Join(destDir, name)
+	if err := os.WriteFile(archivePath, blob, 0644); err != nil {
+		return "", nil, fmt.Errorf("写入归档失败: %w", err)
+	}
+	return archivePath, &manifest, nil
+}
+
+// skillLayer 返回清单中的技能归档层
+func skillLayer(m *Manifest) (Descriptor, bool) {
+	for _, layer := range m.Layers {
+		if layer.MediaType == LayerMediaType {
+			return layer, true
+		}
+	}
+	return Descriptor{}, false
+}
+
+// pushBlob 上传blob，仓库中已存在时跳过
+func (c *Client) pushBlob(ctx context.Context, ref Reference, digest string, data []byte) error {
+	resp, err := c.do(ctx, ref, http.MethodHead, "/blobs/"+digest, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = c.do(ctx, ref, http.MethodPost, "/blobs/uploads/", nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		defer resp.Body.Close()
+		return statusError("创建上传会话", resp)
+	}
+	resp.Body.Close()
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.Header.Get("Location") == "" {
+		return fmt.Errorf("仓库服务没有返回有效的上传地址")
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	resp, err = c.send(ctx, ref, http.MethodPut, location.String(), header, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return statusError("上传 "+digest, resp)
+	}
+	return nil
+}
+
+// pullBlob 下载blob并校验大小和摘要
+func (c *Client) pullBlob(ctx context.Context, ref Reference, desc Descriptor) ([]byte, error) {
+	resp, err := c.do(ctx, ref, http.MethodGet, "/blobs/"+desc.Digest, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("下载 "+desc.Digest, resp)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, desc.Size+1))
+	if err != nil {
+		return nil, fmt.Errorf("下载 %s 失败: %w", desc.Digest, err)
+	}
+	if int64(len(data)) != desc.Size || digestOf(data) != desc.Digest {
+		return nil, fmt.Errorf("%s 校验失败: 内容与清单记录的摘要不一致", desc.Digest)
+	}
+	return data, nil
+}
+
+// do 向仓库 /v2/<repository> 下的路径发送请求
+func (c *Client) do(ctx context.Context, ref Reference, method, path string, header http.Header, body []byte) (*http.Response, error) {
+	scheme := "https"
+	if ref.plainHTTP() {
+		scheme = "http"
+	}
+	return c.send(ctx, ref, method, fmt.Sprintf("%s://%s/v2/%s%s", scheme, ref.Registry, ref.Repository, path), header, body)
+}
+
+// send 发送请求，收到401时按 WWW-Authenticate 认证后重试一次
+func (c *Client) send(ctx context.Context, ref Reference, method, rawURL string, header http.Header, body []byte) (*http.Response, error) {
+	resp, err := c.request(ctx, method, rawURL, header, body)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	if err := c.authenticate(ctx, ref, challenge); err != nil {
+		return nil, err
+	}
+	return c.request(ctx, method, rawURL, header, body)
+}
+
+// request 发送单个请求，附带当前的认证信息
+func (c *Client) request(ctx context.Context, method, rawURL string, header http.Header, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.credentials.Password != "":
+		req.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求仓库服务失败: %w", err)
+	}
+	return resp, nil
+}
+
+// authenticate 处理 Bearer 认证质询：向 realm 申请令牌。Basic 质询直接使用凭据
+func (c *Client) authenticate(ctx context.Context, ref Reference, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if c.credentials.Password == "" {
+			return fmt.Errorf("%s 需要认证，请运行 'docker login %s' 或设置 %s", ref.Registry, ref.Registry, EnvPassword)
+		}
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("%s 需要认证，但不支持质询: %q", ref.Registry, challenge)
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("%s 返回了无效的认证地址", ref.Registry)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull,push"
+	}
+	query := realm.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	if c.credentials.Password != "" {
+		req.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("获取 %s 的访问令牌失败: %w", ref.Registry, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("获取 %s 的访问令牌失败: %s，请运行 'docker login %s' 或设置 %s", ref.Registry, resp.Status, ref.Registry, EnvPassword)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("解析访问令牌失败: %w", err)
+	}
+	c.token = token.Token
+	if c.token == "" {
+		c.token = token.AccessToken
+	}
+	if c.token == "" {
+		return fmt.Errorf("%s 没有返回访问令牌", ref.Registry)
+	}
+	return nil
+}
+
+// parseChallenge 解析 WWW-Authenticate，例如 Bearer realm="https://ghcr.io/token",service="ghcr.io"
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, ", "), "=")
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			params[key] = value
+		}
+	}
+	return strings.ToLower(scheme), params
+}
+
+// statusError 根据响应生成错误，包含仓库服务返回的错误信息
+func statusError(action string, resp *http.Response) error {
+	var body struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, &body) == nil && len(body.Errors) > 0 {
+		return fmt.Errorf("%s失败: %s %s: %s", action, resp.Status, body.Errors[0].Code, body.Errors[0].Message)
+	}
+	return fmt.Errorf("%s失败: %s", action, resp.Status)
+}
+
+// digestOf 计算内容的 sha256 摘要
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,196 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRegistry 内存中的 OCI 仓库服务，使用 Bearer 令牌认证
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	server    *httptest.Server
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	r := &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serve))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *fakeRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req.URL.Path == "/token" {
+		if user, pass, ok := req.BasicAuth(); !ok || user != "bot" || pass != "secret" {
+			http.Error(w, "denied", http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": "tok-" + req.URL.Query().Get("scope")})
+		return
+	}
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer tok-repository:acme/skills/lint:") {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="`+r.server.URL+`/token",service="fake",scope="repository:acme/skills/lint:pull,push"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/acme/skills/lint")
+	switch {
+	case req.Method == http.MethodPost && path == "/blobs/uploads/":
+		w.Header().Set("Location", "/v2/acme/skills/lint/blobs/uploads/session?state=1")
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == http.MethodPut && path == "/blobs/uploads/session":
+		data, _ := io.ReadAll(req.Body)
+		if req.URL.Query().Get("state") != "1" || digestOf(data) != req.URL.Query().Get("digest") {
+			http.Error(w, "bad digest", http.StatusBadRequest)
+			return
+		}
+		r.blobs[digestOf(data)] = data
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(path, "/blobs/"):
+		data, ok := r.blobs[strings.TrimPrefix(path, "/blobs/")]
+		if !ok {
+			http.Error(w, `{"errors":[{"code":"BLOB_UNKNOWN","message":"blob unknown"}]}`, http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case req.Method == http.MethodPut && strings.HasPrefix(path, "/manifests/"):
+		data, _ := io.ReadAll(req.Body)
+		r.manifests[strings.TrimPrefix(path, "/manifests/")] = data
+		r.manifests[digestOf(data)] = data
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodGet && strings.HasPrefix(path, "/manifests/"):
+		data, ok := r.manifests[strings.TrimPrefix(path, "/manifests/")]
+		if !ok {
+			http.Error(w, `{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`, http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func TestPushPull(t *testing.T) {
+	registry := newFakeRegistry(t)
+	ref, err := ParseReference("oci://" + strings.TrimPrefix(registry.server.URL, "http://") + "/acme/skills/lint:1.0.0")
+	if err != nil {
+		t.Fatalf("ParseReference() error = %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "lint-1.0.0.tar.gz")
+	if err := os.WriteFile(archive, []byte("archive content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := NewClient(Credentials{}).Push(ctx, ref, archive, nil); err == nil {
+		t.Error("Push() without credentials should fail")
+	}
+
+	client := NewClient(Credentials{Username: "bot", Password: "secret"})
+	digest, err := client.Push(ctx, ref, archive, map[string]string{AnnotationSkillID: "lint"})
+	if err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	// 再次推送时跳过已存在的blob
+	if _, err := client.Push(ctx, ref, archive, map[string]string{AnnotationSkillID: "lint"}); err != nil {
+		t.Fatalf("Push() again error = %v", err)
+	}
+
+	var manifest Manifest
+	json.Unmarshal(registry.manifests["1.0.0"], &manifest)
+	if manifest.ArtifactType != ArtifactType || len(manifest.Layers) != 1 || manifest.Layers[0].Annotations[AnnotationTitle] != "lint-1.0.0.tar.gz" {
+		t.Errorf("manifest = %+v", manifest)
+	}
+
+	for _, reference := range []string{"1.0.0", digest} {
+		pullRef := ref
+		pullRef.Reference = reference
+		path, pulled, err := NewClient(Credentials{Username: "bot", Password: "secret"}).Pull(ctx, pullRef, t.TempDir())
+		if err != nil {
+			t.Fatalf("Pull(%s) error = %v", reference, err)
+		}
+		data, _ := os.ReadFile(path)
+		if filepath.Base(path) != "lint-1.0.0.tar.gz" || string(data) != "archive content" {
+			t.Errorf("Pull(%s) = %s %q", reference, path, data)
+		}
+		if pulled.Annotations[AnnotationSkillID] != "lint" {
+			t.Errorf("Pull(%s) annotations = %v", reference, pulled.Annotations)
+		}
+	}
+
+	// 层的内容被篡改
+	registry.blobs[manifest.Layers[0].Digest] = []byte("tampered content")
+	if _, _, err := client.Pull(ctx, ref, t.TempDir()); err == nil || !strings.Contains(err.Error(), "校验失败") {
+		t.Errorf("Pull() of tampered blob error = %v", err)
+	}
+
+	missing := ref
+	missing.Reference = "9.9.9"
+	if _, _, err := client.Pull(ctx, missing, t.TempDir()); err == nil || !strings.Contains(err.Error(), "MANIFEST_UNKNOWN") {
+		t.Errorf("Pull() of missing tag error = %v", err)
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Reference
+		wantErr bool
+	}{
+		{"oci://ghcr.io/acme/skills/lint:1.0.0", Reference{"ghcr.io", "acme/skills/lint", "1.0.0"}, false},
+		{"localhost:5000/lint", Reference{"localhost:5000", "lint", ""}, false},
+		{"ghcr.io/acme/lint@sha256:abc", Reference{"ghcr.io", "acme/lint", "sha256:abc"}, false},
+		{"docker.io/acme/lint:v1", Reference{"registry-1.docker.io", "acme/lint", "v1"}, false},
+		{"ghcr.io/Acme/lint", Reference{}, true},
+		{"lint:1.0.0", Reference{}, true},
+		{"ghcr.io/acme/lint@md5:abc", Reference{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, %v, want %+v", tt.input, got, err, tt.want)
+		}
+	}
+	if !(Reference{Registry: "127.0.0.1:5000"}).plainHTTP() || (Reference{Registry: "ghcr.io"}).plainHTTP() {
+		t.Error("plainHTTP() should only be true for loopback registries")
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:acme/lint:pull"`)
+	if scheme != "bearer" || params["realm"] != "https://ghcr.io/token" || params["service"] != "ghcr.io" || params["scope"] != "repository:acme/lint:pull" {
+		t.Errorf("parseChallenge() = %s, %v", scheme, params)
+	}
+}
+
+func TestLoadCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv(EnvPassword, "")
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"auths": {"ghcr.io": {"auth": "Ym90OnNlY3JldA=="}}}`), 0644)
+
+	if got, err := LoadCredentials("ghcr.io"); err != nil || got != (Credentials{"bot", "secret"}) {
+		t.Errorf("LoadCredentials() = %+v, %v", got, err)
+	}
+	if got, _ := LoadCredentials("quay.io"); got != (Credentials{}) {
+		t.Errorf("LoadCredentials() for unknown registry = %+v", got)
+	}
+	t.Setenv(EnvPassword, "token")
+	if got, _ := LoadCredentials("quay.io"); got.Password != "token" {
+		t.Errorf("LoadCredentials() with %s = %+v", EnvPassword, got)
+	}
+}
@@ -0,0 +1,102 @@
+package oci
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"skill-hub/internal/credentials"
+)
+
+// dockerConfig ~/.docker/config.json 中与认证有关的部分
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+}
+
+// Credentials 仓库服务的用户名和密码（或访问令牌）
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// 覆盖Docker配置的环境变量，便于在CI中使用，例如 SKILL_HUB_OCI_PASSWORD=$GITHUB_TOKEN
+const (
+	EnvUsername = "SKILL_HUB_OCI_USERNAME"
+	EnvPassword = "SKILL_HUB_OCI_PASSWORD"
+)
+
+// LoadCredentials 获取仓库服务的凭据：环境变量优先，其次是 'skill-hub login' 保存的凭据，最后是 docker login 保存的凭据
+func LoadCredentials(registry string) (Credentials, error) {
+	if password := os.Getenv(EnvPassword); password != "" {
+		username := os.Getenv(EnvUsername)
+		if username == "" {
+			username = "skill-hub"
+		}
+		return Credentials{Username: username, Password: password}, nil
+	}
+	cred, ok, err := credentials.Lookup(registry)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if ok {
+		username := cred.Username
+		if username == "" {
+			username = "skill-hub"
+		}
+		return Credentials{Username: username, Password: cred.Token}, nil
+	}
+	return DockerCredentials(registry)
+}
+
+// DockerCredentials 从 docker login 保存的配置中读取仓库服务的凭据，
+// 配置文件位于 $DOCKER_CONFIG/config.json 或 ~/.docker/config.json。
+// 只支持直接保存在 auths 中的凭据，不支持 credsStore 等凭据助手；没有凭据时返回空值。
+func DockerCredentials(registry string) (Credentials, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Credentials{}, nil
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Credentials{}, nil
+		}
+		return Credentials{}, fmt.Errorf("读取Docker配置失败: %w", err)
+	}
+
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Credentials{}, fmt.Errorf("解析Docker配置失败: %w", err)
+	}
+	keys := []string{registry, "https://" + registry, "http://" + registry}
+	if registry == "registry-1.docker.io" {
+		keys = append(keys, "https://index.docker.io/v1/")
+	}
+	for _, key := range keys {
+		entry, ok := cfg.Auths[key]
+		if !ok {
+			continue
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return Credentials{}, fmt.Errorf("解析 %s 的凭据失败: %w", registry, err)
+			}
+			username, password, _ := strings.Cut(string(decoded), ":")
+			return Credentials{Username: username, Password: password}, nil
+		}
+		return Credentials{Username: entry.Username, Password: entry.Password}, nil
+	}
+	return Credentials{}, nil
+}
@@ -0,0 +1,72 @@
+package oci
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Scheme 导入源和推送目标中 OCI 引用的前缀
+const Scheme = "oci://"
+
+// Reference OCI 制品引用，例如 ghcr.io/acme/skills/git-expert:1.0.0
+type Reference struct {
+	Registry   string // 仓库服务地址，可以带端口
+	Repository string
+	Reference  string // 标签或 sha256:<hex> 摘要，可以为空
+}
+
+// ParseReference 解析 [oci://]<registry>/<repository>[:<tag>|@<digest>]
+func ParseReference(s string) (Reference, error) {
+	raw := strings.TrimPrefix(s, Scheme)
+	slash := strings.Index(raw, "/")
+	if slash <= 0 || slash == len(raw)-1 {
+		return Reference{}, fmt.Errorf("无效的OCI引用: %s，格式为 <registry>/<repository>[:<tag>]", s)
+	}
+	ref := Reference{Registry: raw[:slash]}
+	repository := raw[slash+1:]
+
+	if at := strings.Index(repository, "@"); at >= 0 {
+		ref.Reference = repository[at+1:]
+		repository = repository[:at]
+		if !strings.HasPrefix(ref.Reference, "sha256:") {
+			return Reference{}, fmt.Errorf("无效的OCI引用: %s，摘要需要以 sha256: 开头", s)
+		}
+	} else if colon := strings.LastIndex(repository, ":"); colon > strings.LastIndex(repository, "/") {
+		ref.Reference = repository[colon+1:]
+		repository = repository[:colon]
+	}
+	if repository == "" || repository != strings.ToLower(repository) || strings.Contains(repository, "//") {
+		return Reference{}, fmt.Errorf("无效的OCI引用: %s，仓库名称只能包含小写字母、数字和分隔符", s)
+	}
+	if ref.Registry == "docker.io" {
+		ref.Registry = "registry-1.docker.io"
+	}
+	ref.Repository = repository
+	return ref, nil
+}
+
+// String 返回不含 oci:// 前缀的引用
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	switch {
+	case strings.HasPrefix(r.Reference, "sha256:"):
+		s += "@" + r.Reference
+	case r.Reference != "":
+		s += ":" + r.Reference
+	}
+	return s
+}
+
+// plainHTTP 本机的仓库服务使用HTTP，其余使用HTTPS
+func (r Reference) plainHTTP() bool {
+	host := r.Registry
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
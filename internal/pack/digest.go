@@ -0,0 +1,48 @@
+package pack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// DigestPrefix 技能内容摘要的算法前缀
+const DigestPrefix = "sha256:"
+
+// Digest 计算清单中所有文件的内容摘要，格式为 sha256:<hex>
+//
+// 摘要只覆盖文件路径和内容，与清单的创建时间、文件权限无关；
+// 同一技能目录导出为归档再解压后摘要不变。
+func (m *Manifest) Digest() string {
+	h := sha256.New()
+	for _, entry := range m.Files {
+		fmt.Fprintf(h, "%s  %s\n", entry.SHA256, entry.Path)
+	}
+	return DigestPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// SkillDigest 计算技能目录的内容摘要（跳过隐藏文件、临时文件和清单文件）
+func SkillDigest(skillDir string) (string, error) {
+	manifest, err := BuildManifest(skillDir, "", "", "")
+	if err != nil {
+		return "", err
+	}
+	return manifest.Digest(), nil
+}
+
+// VerifyDigest 校验技能目录的内容摘要与期望值是否一致，期望值可以省略 sha256: 前缀
+func VerifyDigest(skillDir, want string) error {
+	got, err := SkillDigest(skillDir)
+	if err != nil {
+		return err
+	}
+	want = strings.ToLower(strings.TrimSpace(want))
+	if !strings.HasPrefix(want, DigestPrefix) {
+		want = DigestPrefix + want
+	}
+	if got != want {
+		return fmt.Errorf("内容摘要不匹配: 期望 %s，实际 %s", want, got)
+	}
+	return nil
+}
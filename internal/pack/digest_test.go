@@ -0,0 +1,46 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSkillDigest(t *testing.T) {
+	skillDir := createTestSkill(t)
+
+	digest, err := SkillDigest(skillDir)
+	if err != nil {
+		t.Fatalf("SkillDigest() error = %v", err)
+	}
+	if !strings.HasPrefix(digest, DigestPrefix) || len(digest) != len(DigestPrefix)+64 {
+		t.Fatalf("SkillDigest() = %q", digest)
+	}
+
+	// 隐藏文件、备份文件和清单不影响摘要
+	os.WriteFile(filepath.Join(skillDir, ManifestFileName), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(skillDir, "notes.tmp"), []byte("x"), 0644)
+	if err := VerifyDigest(skillDir, digest); err != nil {
+		t.Errorf("VerifyDigest() error = %v", err)
+	}
+	if err := VerifyDigest(skillDir, strings.ToUpper(strings.TrimPrefix(digest, DigestPrefix))); err != nil {
+		t.Errorf("VerifyDigest() without prefix error = %v", err)
+	}
+
+	t.Run("Tampered content", func(t *testing.T) {
+		os.WriteFile(filepath.Join(skillDir, "scripts", "run.sh"), []byte("rm -rf /\n"), 0755)
+		if err := VerifyDigest(skillDir, digest); err == nil {
+			t.Error("VerifyDigest() expected error for modified file")
+		}
+	})
+
+	t.Run("Renamed file", func(t *testing.T) {
+		other := createTestSkill(t)
+		want, _ := SkillDigest(other)
+		os.Rename(filepath.Join(other, "scripts", "run.sh"), filepath.Join(other, "scripts", "start.sh"))
+		if err := VerifyDigest(other, want); err == nil {
+			t.Error("VerifyDigest() expected error for renamed file")
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package pack
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsArchive 根据文件名判断是否为支持的归档格式
+func IsArchive(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar", ".zip"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract 将归档解压到目标目录
+func Extract(archivePath, dest string) error {
+	lower := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return extractZip(archivePath, dest)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return extractTar(archivePath, dest, true)
+	case strings.HasSuffix(lower, ".tar"):
+		return extractTar(archivePath, dest, false)
+	}
+	return fmt.Errorf("不支持的归档格式: %s", archivePath)
+}
+
+// ReadManifest 读取目录中的清单，不存在时返回nil
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取清单失败: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("解析清单失败: %w", err)
+	}
+	return &m, nil
+}
+
+// Verify 校验目录中的文件与清单记录的哈希是否一致
+func Verify(dir string, m *Manifest) error {
+	for _, entry := range m.Files {
+		p, err := safeJoin(dir, entry.Path)
+		if err != nil {
+			return err
+		}
+		sum, err := fileSHA256(p)
+		if err != nil {
+			return fmt.Errorf("文件 %s 缺失或不可读: %w", entry.Path, err)
+		}
+		if sum != entry.SHA256 {
+			return fmt.Errorf("文件 %s 校验失败: 哈希不匹配", entry.Path)
+		}
+	}
+	return nil
+}
+
+// extractTar 解压tar或tar.gz归档
+func extractTar(archivePath, dest string, gzipped bool) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开归档失败: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("解压gzip失败: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取归档失败: %w", err)
+		}
+
+		target, err := safeJoin(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFromReader(target, tr, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return fmt.Errorf("解压 %s 失败: %w", hdr.Name, err)
+			}
+		default:
+			// 忽略符号链接等特殊文件
+		}
+	}
+}
+
+// extractZip 解压zip归档
+func extractZip(archivePath, dest string) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开归档失败: %w", err)
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		target, err := safeJoin(dest, zf.Name)
+		if err != nil {
+			return err
+		}
+
+		if zf.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := zf.Open()
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %w", zf.Name, err)
+		}
+		err = writeFromReader(target, rc, zf.Mode().Perm())
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("解压 %s 失败: %w", zf.Name, err)
+		}
+	}
+	return nil
+}
+
+// writeFromReader 将内容写入文件
+func writeFromReader(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if mode == 0 {
+		mode = 0644
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// safeJoin 拼接路径并防止路径穿越
+func safeJoin(base, name string) (string, error) {
+	target := filepath.Join(base, filepath.FromSlash(name))
+	rel, err := filepath.Rel(base, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(name) {
+		return "", fmt.Errorf("归档包含非法路径: %s", name)
+	}
+	return target, nil
+}
@@ -0,0 +1,66 @@
+package pack
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractAndVerify(t *testing.T) {
+	skillDir := createTestSkill(t)
+	m, err := BuildManifest(skillDir, "demo", "Demo", "1.0.0")
+	if err != nil {
+		t.Fatalf("BuildManifest() error = %v", err)
+	}
+
+	outDir := t.TempDir()
+	for _, format := range []string{FormatTar, FormatZip} {
+		t.Run(format, func(t *testing.T) {
+			archive, err := Export(skillDir, m, format, filepath.Join(outDir, DefaultOutput(m, format)))
+			if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if !IsArchive(archive) {
+				t.Errorf("IsArchive(%s) = false", archive)
+			}
+
+			dest := t.TempDir()
+			if err := Extract(archive, dest); err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+
+			extracted := filepath.Join(dest, "demo")
+			got, err := ReadManifest(extracted)
+			if err != nil || got == nil {
+				t.Fatalf("ReadManifest() = %v, %v", got, err)
+			}
+			if err := Verify(extracted, got); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+
+			// 篡改文件后校验失败
+			os.WriteFile(filepath.Join(extracted, "SKILL.md"), []byte("tampered"), 0644)
+			if err := Verify(extracted, got); err == nil {
+				t.Error("Verify() expected error after tampering")
+			}
+		})
+	}
+}
+
+func TestExtractRejectsPathTraversal(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "evil.tar.gz")
+	f, _ := os.Create(archive)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "../evil.txt", Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+	tw.Write([]byte("evil"))
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	if err := Extract(archive, t.TempDir()); err == nil {
+		t.Error("Extract() expected error for path traversal")
+	}
+}
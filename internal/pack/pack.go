@@ -0,0 +1,343 @@
+package pack
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"skill-hub/pkg/spec"
+)
+
+// ManifestFileName 技能包清单文件名
+const ManifestFileName = "manifest.json"
+
+// ManifestVersion 清单格式版本
+const ManifestVersion = "1"
+
+// 支持的导出格式
+const (
+	FormatTar = "tar"
+	FormatZip = "zip"
+	FormatDir = "dir"
+)
+
+// FileEntry 技能包中的单个文件
+type FileEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	Mode   uint32 `json:"mode"`
+}
+
+// Manifest 技能包清单
+type Manifest struct {
+	FormatVersion string      `json:"format_version"`
+	SkillID       string      `json:"skill_id"`
+	Name          string      `json:"name"`
+	Version       string      `json:"version"`
+	CreatedAt     string      `json:"created_at"` // SKILL.md 的修改时间，也用作归档中文件的时间
+	Files         []FileEntry `json:"files"`
+}
+
+// fallbackModTime 清单没有有效的 CreatedAt 时归档中文件使用的时间，zip 不能表示1980年之前的时间
+var fallbackModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// BuildManifest 扫描技能目录并生成清单（跳过隐藏文件和临时文件）
+//
+// CreatedAt 取 SKILL.md 的修改时间而不是当前时间，同一份技能内容重复打包得到相同的归档。
+func BuildManifest(skillDir, skillID, name, version string) (*Manifest, error) {
+	manifest := &Manifest{
+		FormatVersion: ManifestVersion,
+		SkillID:       skillID,
+		Name:          name,
+		Version:       version,
+	}
+
+	err := filepath.Walk(skillDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(skillDir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		// 只跳过根目录的清单，子目录中的 manifest.json 是技能自己的文件
+		if skipFile(info.Name()) || rel == ManifestFileName {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		sum, err := fileSHA256(p)
+		if err != nil {
+			return err
+		}
+		if rel == "SKILL.md" {
+			manifest.CreatedAt = info.ModTime().UTC().Format(time.RFC3339)
+		}
+		manifest.Files = append(manifest.Files, FileEntry{
+			Path:   filepath.ToSlash(rel),
+			Size:   info.Size(),
+			SHA256: sum,
+			Mode:   uint32(info.Mode().Perm()),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("扫描技能目录失败: %w", err)
+	}
+
+	if !manifest.hasFile("SKILL.md") {
+		return nil, fmt.Errorf("技能目录缺少SKILL.md: %s", skillDir)
+	}
+
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+	return manifest, nil
+}
+
+// DefaultOutput 返回默认的导出路径
+func DefaultOutput(m *Manifest, format string) string {
+	base := spec.FlatSkillID(m.SkillID)
+	if m.Version != "" {
+		base += "-" + m.Version
+	}
+	switch format {
+	case FormatTar:
+		return base + ".tar.gz"
+	case FormatZip:
+		return base + ".zip"
+	}
+	return base
+}
+
+// rootDir 返回归档内的根目录名。命名空间技能使用 <命名空间>-<名称>，
+// 使归档解压后仍是单层技能目录，完整的技能ID由清单保存
+func rootDir(m *Manifest) string {
+	return spec.FlatSkillID(m.SkillID)
+}
+
+// modTime 返回归档中文件的修改时间：清单的 CreatedAt，无法解析时使用固定时间
+func (m *Manifest) modTime() time.Time {
+	if t, err := time.Parse(time.RFC3339, m.CreatedAt); err == nil && t.After(fallbackModTime) {
+		return t
+	}
+	return fallbackModTime
+}
+
+// sortedFiles 返回按路径排序的文件列表，归档中的文件顺序与清单的生成方式无关
+func (m *Manifest) sortedFiles() []FileEntry {
+	files := append([]FileEntry(nil), m.Files...)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+// Export 按指定格式导出技能包，返回输出路径
+func Export(skillDir string, m *Manifest, format, output string) (string, error) {
+	if output == "" {
+		output = DefaultOutput(m, format)
+	}
+
+	if _, err := os.Stat(output); err == nil {
+		return "", fmt.Errorf("输出路径已存在: %s", output)
+	}
+
+	// 清单中的文件同样按路径排序，与归档中的顺序一致
+	sorted := *m
+	sorted.Files = m.sortedFiles()
+	manifestData, err := json.MarshalIndent(&sorted, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化清单失败: %w", err)
+	}
+
+	switch format {
+	case FormatTar:
+		err = writeTar(skillDir, m, manifestData, output)
+	case FormatZip:
+		err = writeZip(skillDir, m, manifestData, output)
+	case FormatDir:
+		err = writeDir(skillDir, m, manifestData, output)
+	default:
+		return "", fmt.Errorf("不支持的格式: %s，可用选项: %s, %s, %s", format, FormatTar, FormatZip, FormatDir)
+	}
+	if err != nil {
+		// 清理不完整的输出
+		os.RemoveAll(output)
+		return "", err
+	}
+
+	return output, nil
+}
+
+// writeTar 导出为tar.gz，包内根目录见 rootDir
+//
+// 文件按路径排序并使用清单中的时间，同一份清单和内容生成的归档逐字节相同。
+func writeTar(skillDir string, m *Manifest, manifestData []byte, output string) error {
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	writeEntry := func(name string, mode int64, data []byte) error {
+		hdr := &tar.Header{
+			Name:    path.Join(rootDir(m), name),
+			Mode:    mode,
+			Size:    int64(len(data)),
+			ModTime: m.modTime(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := writeEntry(ManifestFileName, 0644, manifestData); err != nil {
+		return fmt.Errorf("写入清单失败: %w", err)
+	}
+	for _, entry := range m.sortedFiles() {
+		data, err := os.ReadFile(filepath.Join(skillDir, filepath.FromSlash(entry.Path)))
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %w", entry.Path, err)
+		}
+		if err := writeEntry(entry.Path, int64(entry.Mode), data); err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", entry.Path, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// writeZip 导出为zip，包内根目录见 rootDir
+func writeZip(skillDir string, m *Manifest, manifestData []byte, output string) error {
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	writeEntry := func(name string, mode uint32, data []byte) error {
+		hdr := &zip.FileHeader{
+			Name:     path.Join(rootDir(m), name),
+			Method:   zip.Deflate,
+			Modified: m.modTime(),
+		}
+		hdr.SetMode(os.FileMode(mode))
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	if err := writeEntry(ManifestFileName, 0644, manifestData); err != nil {
+		return fmt.Errorf("写入清单失败: %w", err)
+	}
+	for _, entry := range m.sortedFiles() {
+		data, err := os.ReadFile(filepath.Join(skillDir, filepath.FromSlash(entry.Path)))
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %w", entry.Path, err)
+		}
+		if err := writeEntry(entry.Path, entry.Mode, data); err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", entry.Path, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// writeDir 导出为目录
+func writeDir(skillDir string, m *Manifest, manifestData []byte, output string) error {
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(output, ManifestFileName), manifestData, 0644); err != nil {
+		return fmt.Errorf("写入清单失败: %w", err)
+	}
+	for _, entry := range m.Files {
+		src := filepath.Join(skillDir, filepath.FromSlash(entry.Path))
+		dst := filepath.Join(output, filepath.FromSlash(entry.Path))
+		if err := copyFile(src, dst, os.FileMode(entry.Mode)); err != nil {
+			return fmt.Errorf("复制 %s 失败: %w", entry.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// hasFile 检查清单是否包含指定文件
+func (m *Manifest) hasFile(p string) bool {
+	for _, entry := range m.Files {
+		if entry.Path == p {
+			return true
+		}
+	}
+	return false
+}
+
+// skipFile 判断是否跳过打包的文件
+func skipFile(name string) bool {
+	return strings.HasPrefix(name, ".") ||
+		strings.HasSuffix(name, ".bak") ||
+		strings.HasSuffix(name, ".tmp")
+}
+
+// fileSHA256 计算文件的SHA256
+func fileSHA256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyFile 复制文件并设置权限
+func copyFile(src, dst string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, mode)
+}
@@ -0,0 +1,208 @@
+package pack
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createTestSkill 创建测试技能目录
+func createTestSkill(t *testing.T) string {
+	t.Helper()
+	skillDir := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755)
+	os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: demo\n---\n# Demo\n"), 0644)
+	os.WriteFile(filepath.Join(skillDir, "scripts", "run.sh"), []byte("echo hi\n"), 0755)
+	os.WriteFile(filepath.Join(skillDir, "SKILL.md.bak"), []byte("old"), 0644)
+	os.WriteFile(filepath.Join(skillDir, ".DS_Store"), []byte("x"), 0644)
+	return skillDir
+}
+
+func TestBuildManifest(t *testing.T) {
+	skillDir := createTestSkill(t)
+
+	m, err := BuildManifest(skillDir, "demo", "Demo", "1.2.0")
+	if err != nil {
+		t.Fatalf("BuildManifest() error = %v", err)
+	}
+
+	if len(m.Files) != 2 {
+		t.Fatalf("Files = %v, want SKILL.md and scripts/run.sh", m.Files)
+	}
+	if m.Files[0].Path != "SKILL.md" || m.Files[1].Path != "scripts/run.sh" {
+		t.Errorf("Files = %v", m.Files)
+	}
+	if len(m.Files[0].SHA256) != 64 {
+		t.Errorf("SHA256 = %q", m.Files[0].SHA256)
+	}
+	if m.Files[1].Mode&0100 == 0 {
+		t.Errorf("script mode = %o, want executable", m.Files[1].Mode)
+	}
+
+	t.Run("Nested manifest.json", func(t *testing.T) {
+		os.WriteFile(filepath.Join(skillDir, ManifestFileName), []byte("{}"), 0644)
+		os.WriteFile(filepath.Join(skillDir, "scripts", ManifestFileName), []byte("{}"), 0644)
+		m, err := BuildManifest(skillDir, "demo", "Demo", "1.2.0")
+		if err != nil {
+			t.Fatalf("BuildManifest() error = %v", err)
+		}
+		var paths []string
+		for _, f := range m.Files {
+			paths = append(paths, f.Path)
+		}
+		want := "SKILL.md,scripts/manifest.json,scripts/run.sh"
+		if strings.Join(paths, ",") != want {
+			t.Errorf("Files = %v, want %s", paths, want)
+		}
+	})
+
+	t.Run("Missing SKILL.md", func(t *testing.T) {
+		if _, err := BuildManifest(t.TempDir(), "x", "x", "1.0.0"); err == nil {
+			t.Error("BuildManifest() expected error without SKILL.md")
+		}
+	})
+}
+
+func TestExport(t *testing.T) {
+	skillDir := createTestSkill(t)
+	m, err := BuildManifest(skillDir, "demo", "Demo", "1.2.0")
+	if err != nil {
+		t.Fatalf("BuildManifest() error = %v", err)
+	}
+
+	outDir := t.TempDir()
+
+	t.Run("tar", func(t *testing.T) {
+		output, err := Export(skillDir, m, FormatTar, filepath.Join(outDir, "demo.tar.gz"))
+		if err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+
+		f, _ := os.Open(output)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("gzip reader error = %v", err)
+		}
+		tr := tar.NewReader(gz)
+
+		names := map[string]bool{}
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("tar read error = %v", err)
+			}
+			names[hdr.Name] = true
+			if hdr.Name == "demo/manifest.json" {
+				var got Manifest
+				data, _ := io.ReadAll(tr)
+				if err := json.Unmarshal(data, &got); err != nil || got.Version != "1.2.0" {
+					t.Errorf("manifest = %+v, err = %v", got, err)
+				}
+			}
+		}
+		for _, want := range []string{"demo/manifest.json", "demo/SKILL.md", "demo/scripts/run.sh"} {
+			if !names[want] {
+				t.Errorf("archive missing %s: %v", want, names)
+			}
+		}
+	})
+
+	t.Run("tar is reproducible", func(t *testing.T) {
+		// 重新扫描、文件顺序不同时生成的归档逐字节相同
+		again, err := BuildManifest(skillDir, "demo", "Demo", "1.2.0")
+		if err != nil {
+			t.Fatalf("BuildManifest() error = %v", err)
+		}
+		again.Files[0], again.Files[1] = again.Files[1], again.Files[0]
+		first, err := Export(skillDir, m, FormatTar, filepath.Join(outDir, "first.tar.gz"))
+		if err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		time.Sleep(1100 * time.Millisecond)
+		second, err := Export(skillDir, again, FormatTar, filepath.Join(outDir, "second.tar.gz"))
+		if err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		a, _ := os.ReadFile(first)
+		b, _ := os.ReadFile(second)
+		if !bytes.Equal(a, b) {
+			t.Error("archives of the same skill differ")
+		}
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		output, err := Export(skillDir, m, FormatZip, filepath.Join(outDir, "demo.zip"))
+		if err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		zr, err := zip.OpenReader(output)
+		if err != nil {
+			t.Fatalf("zip open error = %v", err)
+		}
+		defer zr.Close()
+		if len(zr.File) != 3 {
+			t.Errorf("zip entries = %d, want 3", len(zr.File))
+		}
+	})
+
+	t.Run("dir", func(t *testing.T) {
+		output, err := Export(skillDir, m, FormatDir, filepath.Join(outDir, "demo-dir"))
+		if err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		for _, name := range []string{ManifestFileName, "SKILL.md", filepath.Join("scripts", "run.sh")} {
+			if _, err := os.Stat(filepath.Join(output, name)); err != nil {
+				t.Errorf("missing %s: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("Existing output", func(t *testing.T) {
+		if _, err := Export(skillDir, m, FormatZip, filepath.Join(outDir, "demo.zip")); err == nil {
+			t.Error("Export() expected error for existing output")
+		}
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		if _, err := Export(skillDir, m, "rar", filepath.Join(outDir, "demo.rar")); err == nil {
+			t.Error("Export() expected error for unknown format")
+		}
+	})
+
+	t.Run("Namespaced skill", func(t *testing.T) {
+		nm, err := BuildManifest(skillDir, "acme/demo", "demo", "1.2.0")
+		if err != nil {
+			t.Fatalf("BuildManifest() error = %v", err)
+		}
+		if got := DefaultOutput(nm, FormatZip); got != "acme-demo-1.2.0.zip" {
+			t.Errorf("DefaultOutput() = %q", got)
+		}
+
+		output, err := Export(skillDir, nm, FormatZip, filepath.Join(outDir, "acme-demo.zip"))
+		if err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		zr, err := zip.OpenReader(output)
+		if err != nil {
+			t.Fatalf("zip open error = %v", err)
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			if !strings.HasPrefix(f.Name, "acme-demo/") {
+				t.Errorf("entry %s should be under acme-demo/", f.Name)
+			}
+		}
+	})
+}
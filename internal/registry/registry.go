@@ -0,0 +1,412 @@
+// Package registry 实现静态HTTP技能注册表协议
+//
+// 注册表是任意静态文件服务器上的一个目录：
+//
+//	<registry_url>/index.json                          技能索引，格式与 registry.json 相同
+//	<registry_url>/skills/<技能ID>-<版本>.tar.gz         'skill-hub export' 格式的技能归档
+//
+// index.json 中每个技能记录最新版本、内容摘要 sha256 和可选的归档地址 archive；
+// 命名空间技能的归档文件名使用 <命名空间>-<名称>。旧版本的归档保留在原路径，可以按版本导入。
+//
+// 镜像是目录结构相同的其他地址，主地址不可用时按顺序尝试。设置缓存目录后，下载的 index.json 和
+// 技能归档保存在缓存中，离线模式只使用缓存，用于无法访问网络的环境。
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"skill-hub/internal/pack"
+	"skill-hub/pkg/spec"
+)
+
+// IndexFileName 注册表的索引文件名
+const IndexFileName = "index.json"
+
+// ArchivesDir 注册表中存放技能归档的目录
+const ArchivesDir = "skills"
+
+// maxIndexSize 索引文件的大小上限
+const maxIndexSize = 32 << 20
+
+// ArchivePath 返回技能版本归档在注册表中的默认路径
+func ArchivePath(skillID, version string) string {
+	return ArchivesDir + "/" + spec.FlatSkillID(skillID) + "-" + version + ".tar.gz"
+}
+
+// Client 访问静态HTTP技能注册表
+type Client struct {
+	baseURL  string
+	mirrors  []string
+	cacheDir string
+	offline  bool
+	token    string
+	http     *http.Client
+}
+
+// NewClient 创建注册表客户端，registryURL 可以是注册表目录或其中的 index.json 地址
+func NewClient(registryURL, token string) (*Client, error) {
+	base, err := normalizeURL(registryURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{baseURL: base, token: token, http: &http.Client{Timeout: 60 * time.Second}}, nil
+}
+
+// normalizeURL 去掉注册表地址末尾的 / 和 index.json，只接受 http 和 https 地址
+func normalizeURL(registryURL string) (string, error) {
+	base := strings.TrimSuffix(strings.TrimSpace(registryURL), "/")
+	base = strings.TrimSuffix(base, "/"+IndexFileName)
+	u, err := url.Parse(base)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("无效的注册表地址: %q，需要以 https:// 或 http:// 开头", registryURL)
+	}
+	return base, nil
+}
+
+// SetMirrors 设置镜像地址，主地址请求失败时按顺序尝试。镜像的目录结构必须与主地址相同，使用相同的token
+func (c *Client) SetMirrors(mirrors []string) error {
+	c.mirrors = nil
+	for _, mirror := range mirrors {
+		base, err := normalizeURL(mirror)
+		if err != nil {
+			return fmt.Errorf("无效的镜像地址: %w", err)
+		}
+		c.mirrors = append(c.mirrors, base)
+	}
+	return nil
+}
+
+// SetCache 设置缓存根目录，缓存按主地址分目录，镜像与主地址共享缓存。
+// offline 为 true 时不访问网络，只使用缓存的 index.json 和技能归档
+func (c *Client) SetCache(root string, offline bool) {
+	c.cacheDir = filepath.Join(root, cacheName(c.baseURL))
+	c.offline = offline
+}
+
+// Offline 是否为离线模式
+func (c *Client) Offline() bool {
+	return c.offline
+}
+
+// URL 返回注册表地址
+func (c *Client) URL() string {
+	return c.baseURL
+}
+
+// Index 下载并解析 index.json，离线模式下读取缓存
+func (c *Client) Index(ctx context.Context) (*spec.Registry, error) {
+	cached := c.cachePath(IndexFileName)
+	if c.offline {
+		if cached == "" {
+			return nil, fmt.Errorf("离线模式需要缓存目录")
+		}
+		data, err := os.ReadFile(cached)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("离线模式下没有缓存的 %s，请先在联网时运行 'skill-hub update'", IndexFileName)
+			}
+			return nil, fmt.Errorf("读取缓存的 %s 失败: %w", IndexFileName, err)
+		}
+		return spec.ParseRegistry(data)
+	}
+
+	body, _, err := c.fetch(ctx, IndexFileName)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxIndexSize))
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", IndexFileName, err)
+	}
+	index, err := spec.ParseRegistry(data)
+	if err != nil {
+		return nil, err
+	}
+	if cached != "" {
+		if err := writeCache(cached, data); err != nil {
+			return nil, err
+		}
+	}
+	return index, nil
+}
+
+// ProgressFunc 报告下载进度：已下载的字节数和总字节数，总字节数未知时为 -1
+type ProgressFunc func(written, total int64)
+
+// Download 下载技能归档并解压到 dest，校验清单和 index.json 记录的内容摘要，返回技能目录。
+// 离线模式下使用缓存的归档
+func (c *Client) Download(ctx context.Context, meta spec.SkillMetadata, dest string) (string, error) {
+	return c.DownloadWithProgress(ctx, meta, dest, nil)
+}
+
+// DownloadWithProgress 与 Download 相同，下载过程中调用 progress 报告进度，progress 可以为nil
+func (c *Client) DownloadWithProgress(ctx context.Context, meta spec.SkillMetadata, dest string, progress ProgressFunc) (string, error) {
+	ref, err := archiveRef(meta)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return "", fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	cached := c.archiveCachePath(meta, ref)
+	archive := filepath.Join(dest, archiveFileName(ref))
+	if c.offline {
+		if cached == "" {
+			return "", fmt.Errorf("离线模式需要缓存目录")
+		}
+		if _, err := os.Stat(cached); err != nil {
+			return "", fmt.Errorf("离线模式下没有缓存技能 %s@%s 的归档", meta.ID, meta.Version)
+		}
+		archive = cached
+	} else if err := c.downloadArchive(ctx, meta, ref, archive, progress); err != nil {
+		return "", err
+	}
+
+	extractDir := filepath.Join(dest, "extract")
+	if err := pack.Extract(archive, extractDir); err != nil {
+		return "", fmt.Errorf("解压技能 %s 失败: %w", meta.ID, err)
+	}
+	skillDir, err := archiveSkillDir(extractDir)
+	if err != nil {
+		return "", fmt.Errorf("技能 %s 的%w", meta.ID, err)
+	}
+
+	manifest, err := pack.ReadManifest(skillDir)
+	if err != nil {
+		return "", err
+	}
+	if manifest != nil {
+		if manifest.SkillID != meta.ID {
+			return "", fmt.Errorf("归档清单中的技能ID %s 与注册表中的 %s 不一致", manifest.SkillID, meta.ID)
+		}
+		if err := pack.Verify(skillDir, manifest); err != nil {
+			return "", fmt.Errorf("技能 %s 的归档清单校验失败: %w", meta.ID, err)
+		}
+	}
+	if meta.SHA256 != "" {
+		if err := pack.VerifyDigest(skillDir, meta.SHA256); err != nil {
+			return "", fmt.Errorf("技能 %s 与 %s 记录的不一致，可能已损坏或被篡改: %w", meta.ID, IndexFileName, err)
+		}
+	}
+
+	// 校验通过的归档才写入缓存
+	if cached != "" && !c.offline {
+		data, err := os.ReadFile(archive)
+		if err == nil {
+			err = writeCache(cached, data)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return skillDir, nil
+}
+
+// progressReader 读取时报告累计读取的字节数
+type progressReader struct {
+	reader   io.Reader
+	written  int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.written += int64(n)
+		r.progress(r.written, r.total)
+	}
+	return n, err
+}
+
+// Cached 检查技能版本的归档是否已在缓存中
+func (c *Client) Cached(meta spec.SkillMetadata) bool {
+	ref, err := archiveRef(meta)
+	if err != nil {
+		return false
+	}
+	cached := c.archiveCachePath(meta, ref)
+	if cached == "" {
+		return false
+	}
+	_, err = os.Stat(cached)
+	return err == nil
+}
+
+// archiveCachePath 返回技能归档在缓存中的路径，没有设置缓存目录时返回空字符串
+func (c *Client) archiveCachePath(meta spec.SkillMetadata, ref string) string {
+	return c.cachePath(ArchivesDir, spec.FlatSkillID(meta.ID), meta.Version, archiveFileName(ref))
+}
+
+// downloadArchive 下载技能归档到 archive
+func (c *Client) downloadArchive(ctx context.Context, meta spec.SkillMetadata, ref, archive string, progress ProgressFunc) error {
+	body, size, err := c.fetch(ctx, ref)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	f, err := os.Create(archive)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	var reader io.Reader = body
+	if progress != nil {
+		progress(0, size)
+		reader = &progressReader{reader: body, total: size, progress: progress}
+	}
+	_, err = io.Copy(f, reader)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("下载技能 %s 失败: %w", meta.ID, err)
+	}
+	return nil
+}
+
+// archiveSkillDir 返回解压后的技能目录：归档根目录本身或其中唯一的子目录
+func archiveSkillDir(extractDir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(extractDir, "SKILL.md")); err == nil {
+		return extractDir, nil
+	}
+	entries, err := os.ReadDir(extractDir)
+	if err != nil {
+		return "", fmt.Errorf("归档读取失败: %w", err)
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		dir := filepath.Join(extractDir, entries[0].Name())
+		if _, err := os.Stat(filepath.Join(dir, "SKILL.md")); err == nil {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("归档中没有SKILL.md")
+}
+
+// archiveRef 返回技能归档相对注册表目录的地址，index.json 中记录完整URL时返回该URL
+func archiveRef(meta spec.SkillMetadata) (string, error) {
+	archive := meta.Archive
+	if archive == "" {
+		if meta.Version == "" {
+			return "", fmt.Errorf("注册表中的技能 %s 缺少版本", meta.ID)
+		}
+		archive = ArchivePath(meta.ID, meta.Version)
+	}
+	if _, err := url.Parse(archive); err != nil {
+		return "", fmt.Errorf("技能 %s 的归档地址无效: %w", meta.ID, err)
+	}
+	return archive, nil
+}
+
+// fetch 依次从主地址和镜像请求相对注册表目录的地址 ref，返回第一个成功的响应体和内容长度（未知时为 -1）。
+// ref 为完整URL时只请求该地址
+func (c *Client) fetch(ctx context.Context, ref string) (io.ReadCloser, int64, error) {
+	target, err := url.Parse(ref)
+	if err != nil {
+		return nil, 0, fmt.Errorf("无效的地址 %s: %w", ref, err)
+	}
+	bases := append([]string{c.baseURL}, c.mirrors...)
+	if target.IsAbs() {
+		bases = bases[:1]
+	}
+
+	var errs []string
+	for _, base := range bases {
+		baseURL, err := url.Parse(base + "/")
+		if err != nil {
+			return nil, 0, err
+		}
+		rawURL := baseURL.ResolveReference(target).String()
+		body, size, err := c.get(ctx, rawURL)
+		if err == nil {
+			return body, size, nil
+		}
+		if ctx.Err() != nil {
+			return nil, 0, err
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 1 {
+		return nil, 0, fmt.Errorf("%s", errs[0])
+	}
+	return nil, 0, fmt.Errorf("注册表及其镜像都不可用:\n  %s", strings.Join(errs, "\n  "))
+}
+
+// cachePath 返回缓存中的文件路径，没有设置缓存目录时返回空字符串
+func (c *Client) cachePath(elem ...string) string {
+	if c.cacheDir == "" {
+		return ""
+	}
+	return filepath.Join(append([]string{c.cacheDir}, elem...)...)
+}
+
+// writeCache 写入缓存文件，先写临时文件再重命名，避免中断时留下不完整的缓存
+func writeCache(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("写入缓存失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("写入缓存失败: %w", err)
+	}
+	return nil
+}
+
+// cacheName 返回注册表地址对应的缓存目录名，例如 https://skills.example.com/team 为 skills.example.com_team
+func cacheName(baseURL string) string {
+	name := baseURL
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// get 发送GET请求，返回状态为200的响应体和内容长度（未知时为 -1）
+func (c *Client) get(ctx context.Context, rawURL string) (io.ReadCloser, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("创建请求失败: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("请求注册表失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("请求 %s 失败: %s", rawURL, resp.Status)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+// archiveFileName 返回地址路径的最后一段，用作下载文件名以保留归档扩展名
+func archiveFileName(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Path != "" {
+		if name := filepath.Base(u.Path); name != "/" && name != "." && pack.IsArchive(name) {
+			return name
+		}
+	}
+	return "skill.tar.gz"
+}
@@ -0,0 +1,192 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skill-hub/internal/pack"
+	"skill-hub/pkg/spec"
+)
+
+func TestClient(t *testing.T) {
+	// 注册表目录：一个命名空间技能的归档和 index.json
+	root := t.TempDir()
+	skillDir := filepath.Join(t.TempDir(), "lint")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: lint\ndescription: Lint\nversion: 1.2.0\n---\n# Lint\n"), 0644)
+	manifest, err := pack.BuildManifest(skillDir, "acme/lint", "lint", "1.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(root, ArchivesDir), 0755)
+	if _, err := pack.Export(skillDir, manifest, pack.FormatTar, filepath.Join(root, filepath.FromSlash(ArchivePath("acme/lint", "1.2.0")))); err != nil {
+		t.Fatal(err)
+	}
+	index := spec.Registry{Version: "1.0.0", Skills: []spec.SkillMetadata{
+		{ID: "acme/lint", Name: "lint", Version: "1.2.0", SHA256: manifest.Digest()},
+		{ID: "tampered", Version: "1.0.0", SHA256: manifest.Digest(), Archive: ArchivePath("acme/lint", "1.2.0")},
+	}}
+	data, _ := json.Marshal(index)
+	os.WriteFile(filepath.Join(root, IndexFileName), data, 0644)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		http.FileServer(http.Dir(root)).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL+"/"+IndexFileName, "secret")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.URL() != server.URL {
+		t.Errorf("URL() = %q, want %q", client.URL(), server.URL)
+	}
+
+	ctx := context.Background()
+	got, err := client.Index(ctx)
+	if err != nil {
+		t.Fatalf("Index() error = %v", err)
+	}
+	if len(got.Skills) != 2 || got.Skills[0].ID != "acme/lint" {
+		t.Fatalf("Index() = %+v", got.Skills)
+	}
+
+	dir, err := client.Download(ctx, got.Skills[0], t.TempDir())
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if filepath.Base(dir) != "acme-lint" {
+		t.Errorf("Download() dir = %s", dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "SKILL.md")); err != nil {
+		t.Errorf("SKILL.md not extracted: %v", err)
+	}
+
+	// 归档清单中的技能ID与注册表不一致
+	if _, err := client.Download(ctx, got.Skills[1], t.TempDir()); err == nil || !strings.Contains(err.Error(), "不一致") {
+		t.Errorf("Download() error = %v, want mismatch error", err)
+	}
+	// 内容摘要与 index.json 不一致
+	tampered := got.Skills[0]
+	tampered.SHA256 = pack.DigestPrefix + strings.Repeat("0", 64)
+	if _, err := client.Download(ctx, tampered, t.TempDir()); err == nil || !strings.Contains(err.Error(), "内容摘要不匹配") {
+		t.Errorf("Download() error = %v, want digest error", err)
+	}
+	// 不存在的版本
+	if _, err := client.Download(ctx, spec.SkillMetadata{ID: "acme/lint", Version: "0.9.0"}, t.TempDir()); err == nil {
+		t.Error("Download() should fail for missing archive")
+	}
+
+	unauthorized, _ := NewClient(server.URL, "")
+	if _, err := unauthorized.Index(ctx); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Index() without token error = %v", err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	for _, tt := range []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"https://skills.example.com/", "https://skills.example.com", false},
+		{"https://skills.example.com/team/index.json", "https://skills.example.com/team", false},
+		{"skills.example.com", "", true},
+		{"ftp://skills.example.com", "", true},
+	} {
+		client, err := NewClient(tt.url, "")
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewClient(%q) should return error", tt.url)
+			}
+			continue
+		}
+		if err != nil || client.URL() != tt.want {
+			t.Errorf("NewClient(%q) = %v, %v, want %q", tt.url, client, err, tt.want)
+		}
+	}
+}
+
+func TestMirrorsAndOfflineCache(t *testing.T) {
+	root := t.TempDir()
+	skillDir := filepath.Join(t.TempDir(), "lint")
+	os.MkdirAll(skillDir, 0755)
+	os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: lint\ndescription: Lint\n---\n# Lint\n"), 0644)
+	manifest, err := pack.BuildManifest(skillDir, "lint", "lint", "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(root, ArchivesDir), 0755)
+	if _, err := pack.Export(skillDir, manifest, pack.FormatTar, filepath.Join(root, filepath.FromSlash(ArchivePath("lint", "1.0.0")))); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(spec.Registry{Skills: []spec.SkillMetadata{{ID: "lint", Version: "1.0.0", SHA256: manifest.Digest()}}})
+	os.WriteFile(filepath.Join(root, IndexFileName), data, 0644)
+
+	// 主地址不可用，从镜像下载
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	mirror := httptest.NewServer(http.FileServer(http.Dir(root)))
+
+	cacheRoot := t.TempDir()
+	client, _ := NewClient(primary.URL, "")
+	if err := client.SetMirrors([]string{mirror.URL + "/index.json"}); err != nil {
+		t.Fatalf("SetMirrors() error = %v", err)
+	}
+	if err := client.SetMirrors([]string{"mirror.example.com"}); err == nil {
+		t.Error("SetMirrors() expected error for invalid URL")
+	}
+	client.SetMirrors([]string{mirror.URL})
+	client.SetCache(cacheRoot, false)
+
+	ctx := context.Background()
+	index, err := client.Index(ctx)
+	if err != nil {
+		t.Fatalf("Index() error = %v", err)
+	}
+	if _, err := client.Download(ctx, index.Skills[0], t.TempDir()); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if !client.Cached(index.Skills[0]) {
+		t.Error("Cached() = false after Download()")
+	}
+	mirror.Close()
+
+	// 离线模式只使用缓存，不访问网络
+	offline, _ := NewClient(primary.URL, "")
+	offline.SetCache(cacheRoot, true)
+	index, err = offline.Index(ctx)
+	if err != nil || len(index.Skills) != 1 {
+		t.Fatalf("offline Index() = %v, %v", index, err)
+	}
+	dir, err := offline.Download(ctx, index.Skills[0], t.TempDir())
+	if err != nil {
+		t.Fatalf("offline Download() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "SKILL.md")); err != nil {
+		t.Errorf("SKILL.md not extracted: %v", err)
+	}
+	if _, err := offline.Download(ctx, spec.SkillMetadata{ID: "lint", Version: "0.9.0"}, t.TempDir()); err == nil || !strings.Contains(err.Error(), "离线模式") {
+		t.Errorf("offline Download() of uncached version error = %v", err)
+	}
+
+	other, _ := NewClient("https://other.example.com", "")
+	other.SetCache(cacheRoot, true)
+	if _, err := other.Index(ctx); err == nil || !strings.Contains(err.Error(), "没有缓存") {
+		t.Errorf("offline Index() without cache error = %v", err)
+	}
+}
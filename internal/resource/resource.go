@@ -0,0 +1,194 @@
+package resource
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DirName 技能目录中存放资源文件（脚本、模板、参考文档）的子目录
+const DirName = "resources"
+
+// DefaultDestination 资源文件在项目中的默认安装目录（相对项目根目录）
+const DefaultDestination = ".skill-hub/resources"
+
+// 资源文件的安装方式
+const (
+	ModeCopy = "copy" // 复制到项目中
+	ModeLink = "link" // 创建指向技能目录的符号链接
+)
+
+// List 返回技能的资源文件，路径相对于资源目录并使用 / 分隔。没有资源目录时返回空列表
+func List(skillDir string) ([]string, error) {
+	root := filepath.Join(skillDir, DirName)
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil, nil
+	}
+
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取资源目录失败: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Destination 返回技能资源在项目中的安装目录（相对项目根目录），dest 为空时使用默认目录
+func Destination(dest, skillID string) (string, error) {
+	if dest == "" {
+		dest = DefaultDestination
+	}
+	dest = filepath.Clean(dest)
+	if filepath.IsAbs(dest) || dest == ".." || strings.HasPrefix(dest, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("资源安装目录必须是项目内的相对路径: %s", dest)
+	}
+	return filepath.Join(dest, filepath.FromSlash(skillID)), nil
+}
+
+// Install 将技能的资源文件安装到项目的 <dest>/<技能ID>/ 目录下
+//
+// 返回安装的文件路径，相对于项目根目录并使用 / 分隔，用于记录到项目状态中。
+func Install(skillDir, projectPath, dest, skillID, mode string) ([]string, error) {
+	if mode == "" {
+		mode = ModeCopy
+	}
+	if mode != ModeCopy && mode != ModeLink {
+		return nil, fmt.Errorf("无效的资源安装方式: %s，可用选项: %s, %s", mode, ModeCopy, ModeLink)
+	}
+
+	files, err := List(skillDir)
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+	destDir, err := Destination(dest, skillID)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make([]string, 0, len(files))
+	for _, file := range files {
+		src := filepath.Join(skillDir, DirName, filepath.FromSlash(file))
+		rel := filepath.Join(destDir, filepath.FromSlash(file))
+		dst := filepath.Join(projectPath, rel)
+
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return installed, fmt.Errorf("创建目录失败: %w", err)
+		}
+		// 已存在的文件或链接直接替换
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			return installed, fmt.Errorf("替换 %s 失败: %w", rel, err)
+		}
+
+		if mode == ModeLink {
+			abs, err := filepath.Abs(src)
+			if err != nil {
+				return installed, err
+			}
+			if err := os.Symlink(abs, dst); err != nil {
+				return installed, fmt.Errorf("创建链接 %s 失败: %w", rel, err)
+			}
+		} else if err := copyFile(src, dst); err != nil {
+			return installed, fmt.Errorf("复制 %s 失败: %w", rel, err)
+		}
+		installed = append(installed, filepath.ToSlash(rel))
+	}
+	return installed, nil
+}
+
+// Remove 删除项目中已安装的资源文件，并清理因此变空的目录
+//
+// files 为 Install 返回的相对路径，已不存在的文件忽略，指向项目之外的路径拒绝删除。
+func Remove(projectPath string, files []string) error {
+	var errs []string
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		rel := filepath.Clean(filepath.FromSlash(file))
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			errs = append(errs, fmt.Sprintf("%s: 不在项目目录中", file))
+			continue
+		}
+		if err := os.Remove(filepath.Join(projectPath, rel)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err.Error())
+			continue
+		}
+		for dir := filepath.Dir(rel); dir != "."; dir = filepath.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+
+	// 由深到浅删除空目录，非空目录删除失败时忽略
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for _, dir := range sorted {
+		os.Remove(filepath.Join(projectPath, dir))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("删除资源文件失败: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Stale 返回上一次安装过、本次不再安装的资源文件
+func Stale(previous, current []string) []string {
+	keep := make(map[string]bool, len(current))
+	for _, file := range current {
+		keep[file] = true
+	}
+	var stale []string
+	for _, file := range previous {
+		if !keep[file] {
+			stale = append(stale, file)
+		}
+	}
+	return stale
+}
+
+// copyFile 复制文件并保留权限，脚本复制后仍可执行
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
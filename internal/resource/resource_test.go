@@ -0,0 +1,128 @@
+package resource
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// createSkill 创建带资源文件的技能目录
+func createSkill(t *testing.T) string {
+	t.Helper()
+	skillDir := filepath.Join(t.TempDir(), "demo")
+	files := map[string]string{
+		"SKILL.md":                    "---\nname: demo\n---\n",
+		"resources/scripts/run.sh":    "#!/bin/sh\necho hi\n",
+		"resources/templates/pr.md":   "## PR\n",
+		"resources/.DS_Store":         "junk",
+		"resources/.cache/ignored.md": "junk",
+	}
+	for name, content := range files {
+		path := filepath.Join(skillDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return skillDir
+}
+
+func TestList(t *testing.T) {
+	files, err := List(createSkill(t))
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if strings.Join(files, ",") != "scripts/run.sh,templates/pr.md" {
+		t.Errorf("List() = %v", files)
+	}
+
+	files, err = List(t.TempDir())
+	if err != nil || len(files) != 0 {
+		t.Errorf("List() without resources = %v, %v", files, err)
+	}
+}
+
+func TestInstallAndRemove(t *testing.T) {
+	skillDir := createSkill(t)
+
+	t.Run("copy", func(t *testing.T) {
+		project := t.TempDir()
+		files, err := Install(skillDir, project, "", "acme/demo", ModeCopy)
+		if err != nil {
+			t.Fatalf("Install() error = %v", err)
+		}
+		want := []string{".skill-hub/resources/acme/demo/scripts/run.sh", ".skill-hub/resources/acme/demo/templates/pr.md"}
+		if strings.Join(files, ",") != strings.Join(want, ",") {
+			t.Fatalf("Install() = %v, want %v", files, want)
+		}
+		info, err := os.Lstat(filepath.Join(project, filepath.FromSlash(want[0])))
+		if err != nil {
+			t.Fatalf("resource not installed: %v", err)
+		}
+		if !info.Mode().IsRegular() || info.Mode().Perm()&0100 == 0 {
+			t.Errorf("mode = %v, want executable regular file", info.Mode())
+		}
+
+		// 用户在资源目录中的其他文件保留
+		other := filepath.Join(project, ".skill-hub", "notes.md")
+		os.WriteFile(other, []byte("mine"), 0644)
+
+		if err := Remove(project, files); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(project, ".skill-hub", "resources")); !os.IsNotExist(err) {
+			t.Error("empty resource directories should be removed")
+		}
+		if _, err := os.Stat(other); err != nil {
+			t.Errorf("unrelated file removed: %v", err)
+		}
+	})
+
+	t.Run("link", func(t *testing.T) {
+		project := t.TempDir()
+		files, err := Install(skillDir, project, "tools", "demo", ModeLink)
+		if err != nil {
+			t.Fatalf("Install() error = %v", err)
+		}
+		target, err := os.Readlink(filepath.Join(project, "tools", "demo", "templates", "pr.md"))
+		if err != nil {
+			t.Fatalf("link not created: %v", err)
+		}
+		if target != filepath.Join(skillDir, "resources", "templates", "pr.md") {
+			t.Errorf("link target = %s", target)
+		}
+
+		// 重复安装替换已有链接
+		if _, err := Install(skillDir, project, "tools", "demo", ModeLink); err != nil {
+			t.Fatalf("Install() again error = %v", err)
+		}
+		if err := Remove(project, files); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(project, "tools")); !os.IsNotExist(err) {
+			t.Error("empty resource directories should be removed")
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		if _, err := Install(skillDir, t.TempDir(), "", "demo", "hardlink"); err == nil {
+			t.Error("Install() should reject unknown mode")
+		}
+		if _, err := Install(skillDir, t.TempDir(), "../outside", "demo", ModeCopy); err == nil {
+			t.Error("Install() should reject destination outside the project")
+		}
+		if err := Remove(t.TempDir(), []string{"../outside.txt"}); err == nil {
+			t.Error("Remove() should reject paths outside the project")
+		}
+	})
+}
+
+func TestStale(t *testing.T) {
+	stale := Stale([]string{"a", "b", "c"}, []string{"b"})
+	if strings.Join(stale, ",") != "a,c" {
+		t.Errorf("Stale() = %v", stale)
+	}
+}
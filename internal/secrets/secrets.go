@@ -0,0 +1,163 @@
+// Package secrets 解析变量值中的环境变量和密钥引用
+//
+// 项目状态中的变量值可以写成 ${env:NAME} 或 ${secret:NAME}，渲染技能时才替换为实际值，
+// 敏感值不会保存到状态文件中。
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"skill-hub/internal/config"
+)
+
+// 引用的来源
+const (
+	SourceEnv    = "env"
+	SourceSecret = "secret"
+)
+
+// ReferencePattern 匹配变量值中的引用：${env:NAME} 或 ${secret:NAME}
+var ReferencePattern = regexp.MustCompile(`\$\{(env|secret):([A-Za-z0-9_./-]+)\}`)
+
+// LookupSecret 查询密钥的值，默认执行配置项 secrets_command 指定的命令
+//
+// 嵌入 skill-hub 的程序可以替换为自己的密钥提供方。
+var LookupSecret = commandSecret
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[string]string)
+)
+
+// HasReference 检查值中是否包含引用
+func HasReference(value string) bool {
+	return ReferencePattern.MatchString(value)
+}
+
+// Resolve 将变量值中的引用替换为实际值，返回新的变量表
+//
+// 环境变量未设置或密钥查询失败时返回错误，不会渲染出空值。
+func Resolve(values map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(values))
+	for name, value := range values {
+		expanded, err := Expand(value)
+		if err != nil {
+			return nil, fmt.Errorf("解析变量 %s 失败: %w", name, err)
+		}
+		resolved[name] = expanded
+	}
+	return resolved, nil
+}
+
+// Expand 将文本中的引用替换为实际值
+func Expand(text string) (string, error) {
+	var firstErr error
+	expanded := ReferencePattern.ReplaceAllStringFunc(text, func(ref string) string {
+		value, err := lookup(ref)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return expanded, nil
+}
+
+// Redact 将内容中由变量引用解析出的值替换回引用本身
+//
+// 用于保存渲染结果（如应用历史）前去除敏感值，使用 Expand 还原。
+// 解析失败的引用会被忽略。
+func Redact(content string, values map[string]string) string {
+	refs := make(map[string]bool)
+	for _, value := range values {
+		for _, ref := range ReferencePattern.FindAllString(value, -1) {
+			refs[ref] = true
+		}
+	}
+
+	// 先替换较长的值，避免一个值是另一个值的一部分时替换不完整
+	type pair struct{ ref, value string }
+	var pairs []pair
+	for ref := range refs {
+		value, err := lookup(ref)
+		if err != nil || value == "" {
+			continue
+		}
+		pairs = append(pairs, pair{ref, value})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if len(pairs[i].value) != len(pairs[j].value) {
+			return len(pairs[i].value) > len(pairs[j].value)
+		}
+		return pairs[i].ref < pairs[j].ref
+	})
+	for _, p := range pairs {
+		content = strings.ReplaceAll(content, p.value, p.ref)
+	}
+	return content
+}
+
+// lookup 查询单个引用的值，密钥在进程内缓存，避免重复执行查询命令
+func lookup(ref string) (string, error) {
+	match := ReferencePattern.FindStringSubmatch(ref)
+	source, name := match[1], match[2]
+
+	switch source {
+	case SourceEnv:
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("环境变量 %s 未设置", name)
+		}
+		return value, nil
+	default:
+		cacheMu.Lock()
+		value, ok := cache[name]
+		cacheMu.Unlock()
+		if ok {
+			return value, nil
+		}
+		value, err := LookupSecret(name)
+		if err != nil {
+			return "", fmt.Errorf("查询密钥 %s 失败: %w", name, err)
+		}
+		cacheMu.Lock()
+		cache[name] = value
+		cacheMu.Unlock()
+		return value, nil
+	}
+}
+
+// commandSecret 执行 secrets_command 查询密钥，密钥名称作为最后一个参数，输出去除首尾空白后作为值
+//
+// 例如 secrets_command: "pass show" 时执行 pass show <name>。
+func commandSecret(name string) (string, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(cfg.SecretsCommand)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("未配置 secrets_command，无法解析 ${secret:%s}", name)
+	}
+
+	cmd := exec.Command(fields[0], append(fields[1:], name)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
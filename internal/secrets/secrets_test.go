@@ -0,0 +1,68 @@
+package secrets
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("SKILL_HUB_TEST_HOST", "api.example.com")
+	oldLookup := LookupSecret
+	LookupSecret = func(name string) (string, error) {
+		if name == "api-token" {
+			return "s3cr3t-token", nil
+		}
+		return "", fmt.Errorf("unknown secret")
+	}
+	defer func() { LookupSecret = oldLookup }()
+
+	values := map[string]string{
+		"HOST":  "https://${env:SKILL_HUB_TEST_HOST}/v1",
+		"TOKEN": "${secret:api-token}",
+		"LANG":  "go",
+	}
+	resolved, err := Resolve(values)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolved["HOST"] != "https://api.example.com/v1" || resolved["TOKEN"] != "s3cr3t-token" || resolved["LANG"] != "go" {
+		t.Errorf("Resolve() = %v", resolved)
+	}
+	if values["TOKEN"] != "${secret:api-token}" {
+		t.Error("Resolve() should not modify the input")
+	}
+
+	if _, err := Resolve(map[string]string{"X": "${env:SKILL_HUB_TEST_UNSET}"}); err == nil {
+		t.Error("Resolve() with unset environment variable should fail")
+	}
+	if _, err := Resolve(map[string]string{"X": "${secret:missing}"}); err == nil {
+		t.Error("Resolve() with unknown secret should fail")
+	}
+
+	t.Run("redact and expand", func(t *testing.T) {
+		content := "Call https://api.example.com/v1 with token s3cr3t-token"
+		redacted := Redact(content, values)
+		if redacted != "Call https://${env:SKILL_HUB_TEST_HOST}/v1 with token ${secret:api-token}" {
+			t.Errorf("Redact() = %q", redacted)
+		}
+		expanded, err := Expand(redacted)
+		if err != nil || expanded != content {
+			t.Errorf("Expand() = %q, %v", expanded, err)
+		}
+	})
+}
+
+func TestHasReference(t *testing.T) {
+	tests := map[string]bool{
+		"${env:HOME}":     true,
+		"x-${secret:a/b}": true,
+		"$HOME":           false,
+		"${other:NAME}":   false,
+		"plain value":     false,
+	}
+	for value, want := range tests {
+		if got := HasReference(value); got != want {
+			t.Errorf("HasReference(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"skill-hub/internal/config"
+)
+
+// 审计日志记录的操作类型
+const (
+	OpApply     = "apply"
+	OpRemove    = "remove"
+	OpFeedback  = "feedback"
+	OpUpdate    = "update"
+	OpRollback  = "rollback"
+	OpUninstall = "uninstall"
+	OpPublish   = "publish"
+)
+
+// AuditEntry 审计日志中的一条操作记录
+type AuditEntry struct {
+	Time      string `json:"time"`
+	Operation string `json:"operation"`
+	Project   string `json:"project,omitempty"`
+	SkillID   string `json:"skill_id,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Adapter   string `json:"adapter,omitempty"`
+	User      string `json:"user,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// AuditFilter 查询审计日志的过滤条件，空字段表示不过滤
+type AuditFilter struct {
+	SkillID   string
+	Project   string
+	Operation string
+	Limit     int // 只返回最近的N条，0表示不限制
+}
+
+// AuditLog 仅追加的审计日志（每行一个JSON记录）
+type AuditLog struct {
+	path string
+}
+
+// NewAuditLog 创建审计日志，日志文件与状态文件位于同一目录
+func NewAuditLog() (*AuditLog, error) {
+	repoPath, err := config.GetRepoPath()
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{path: filepath.Join(repoPath, "history.jsonl")}, nil
+}
+
+// GetPath 获取审计日志文件路径
+func (l *AuditLog) GetPath() string {
+	return l.path
+}
+
+// Append 追加一条记录，未设置的时间和用户自动填充
+func (l *AuditLog) Append(entry AuditEntry) error {
+	if entry.Time == "" {
+		entry.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	if entry.User == "" {
+		entry.User = currentUser()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化审计记录失败: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// Read 按时间顺序读取符合条件的记录（跳过无法解析的行）
+func (l *AuditLog) Read(filter AuditFilter) ([]AuditEntry, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取审计日志失败: %w", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取审计日志失败: %w", err)
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries, nil
+}
+
+// matches 检查记录是否符合过滤条件
+func (f AuditFilter) matches(entry AuditEntry) bool {
+	if f.SkillID != "" && entry.SkillID != f.SkillID {
+		return false
+	}
+	if f.Project != "" && entry.Project != f.Project {
+		return false
+	}
+	if f.Operation != "" && entry.Operation != f.Operation {
+		return false
+	}
+	return true
+}
+
+// currentUser 获取当前系统用户名
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,107 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// NormalizeProjectPath 返回项目的规范路径：绝对路径，并解析符号链接
+//
+// 同一项目通过符号链接或不同挂载点访问时得到相同的路径。路径不存在时只转换为绝对路径。
+func NormalizeProjectPath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("获取绝对路径失败: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		return resolved, nil
+	}
+	return absPath, nil
+}
+
+// gitIdentity 项目所在Git仓库的远程地址和工作区根目录
+type gitIdentity struct {
+	remote string // 规范化后的远程地址
+	root   string // 工作区根目录（规范路径）
+}
+
+// lookupGitIdentity 查找路径所在的Git仓库，没有仓库或没有远程地址时返回 nil
+func lookupGitIdentity(path string) *gitIdentity {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil
+	}
+	remote, err := repo.Remote("origin")
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return nil
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil
+	}
+	root, err := NormalizeProjectPath(worktree.Filesystem.Root())
+	if err != nil {
+		return nil
+	}
+	return &gitIdentity{remote: normalizeRemoteURL(remote.Config().URLs[0]), root: root}
+}
+
+// projectID 返回项目在该Git仓库中的稳定标识，项目不在仓库工作区内时返回空
+func (g *gitIdentity) projectID(projectPath string) string {
+	rel, err := filepath.Rel(g.root, projectPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(g.remote + "\n" + filepath.ToSlash(rel)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ProjectID 返回项目的稳定标识：由Git远程地址和项目相对仓库根目录的路径计算
+//
+// 项目移动到其他目录或重新克隆后标识不变，用于找回移动前的项目状态。
+// 项目不在Git仓库中或仓库没有 origin 远程地址时返回空。
+func ProjectID(projectPath string) string {
+	path, err := NormalizeProjectPath(projectPath)
+	if err != nil {
+		return ""
+	}
+	identity := lookupGitIdentity(path)
+	if identity == nil {
+		return ""
+	}
+	return identity.projectID(path)
+}
+
+// normalizeRemoteURL 规范化Git远程地址，使 SSH 和 HTTPS 形式的同一仓库得到相同结果
+//
+// git@github.com:org/repo.git、https://user@github.com/org/repo 都规范化为 github.com/org/repo。
+func normalizeRemoteURL(url string) string {
+	url = strings.TrimSpace(url)
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	} else if i := strings.Index(url, ":"); i >= 0 && !strings.Contains(url[:i], "/") {
+		// scp 形式：git@host:org/repo
+		url = url[:i] + "/" + url[i+1:]
+	}
+	if i := strings.Index(url, "@"); i >= 0 && i < strings.Index(url+"/", "/") {
+		url = url[i+1:]
+	}
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+
+	host, rest, _ := strings.Cut(url, "/")
+	// 去掉默认端口
+	host = strings.TrimSuffix(strings.TrimSuffix(host, ":22"), ":443")
+	return strings.ToLower(host) + "/" + rest
+}
+
+// pathExists 检查路径是否存在
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
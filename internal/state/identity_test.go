@@ -0,0 +1,145 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+)
+
+func TestNormalizeRemoteURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"git@github.com:org/repo.git", "github.com/org/repo"},
+		{"https://github.com/org/repo", "github.com/org/repo"},
+		{"https://user@GitHub.com/org/repo.git/", "github.com/org/repo"},
+		{"ssh://git@github.com:22/org/repo.git", "github.com/org/repo"},
+		{"https://gitlab.example.com:8443/group/sub/repo.git", "gitlab.example.com:8443/group/sub/repo"},
+	}
+	for _, tt := range tests {
+		if got := normalizeRemoteURL(tt.url); got != tt.want {
+			t.Errorf("normalizeRemoteURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSymlinkedProjectPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := &StateManager{statePath: filepath.Join(tmpDir, "repo", "state.json")}
+	project := filepath.Join(tmpDir, "project")
+	link := filepath.Join(tmpDir, "link")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(project, link); err != nil {
+		t.Skipf("无法创建符号链接: %v", err)
+	}
+
+	if err := manager.AddSkillToProject(link, "git-expert", "1.0.0", nil); err != nil {
+		t.Fatal(err)
+	}
+	state, err := manager.LoadProjectState(project)
+	if err != nil || len(state.Skills) != 1 {
+		t.Fatalf("LoadProjectState() via real path = %+v, %v", state, err)
+	}
+	if found, err := manager.FindProjectByPath(link); err != nil || found == nil || found.ProjectPath != state.ProjectPath {
+		t.Errorf("FindProjectByPath() via symlink = %+v, %v", found, err)
+	}
+}
+
+func TestRelocateProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := &StateManager{statePath: filepath.Join(tmpDir, "repo", "state.json")}
+	oldPath := filepath.Join(tmpDir, "old")
+	newPath := filepath.Join(tmpDir, "new")
+	if err := os.MkdirAll(oldPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddSkillToProject(oldPath, "git-expert", "1.0.0", map[string]string{"LANGUAGE": "go"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := manager.RelocateProject(filepath.Join(tmpDir, "missing"), newPath); err == nil {
+		t.Error("RelocateProject() with unknown path should fail")
+	}
+	if _, err := manager.RelocateProject(oldPath, newPath); err != nil {
+		t.Fatalf("RelocateProject() error = %v", err)
+	}
+	state, err := manager.LoadProjectState(newPath)
+	if err != nil || state.Skills["git-expert"].Variables["LANGUAGE"] != "go" {
+		t.Errorf("LoadProjectState() after relocate = %+v, %v", state, err)
+	}
+	paths, err := manager.ProjectPaths()
+	if err != nil || len(paths) != 1 {
+		t.Errorf("ProjectPaths() = %v, %v", paths, err)
+	}
+
+	// 新路径已有技能时拒绝覆盖
+	other := filepath.Join(tmpDir, "other")
+	if err := manager.AddSkillToProject(other, "code-review", "1.0.0", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.RelocateProject(other, newPath); err == nil {
+		t.Error("RelocateProject() onto project with skills should fail")
+	}
+}
+
+func TestProjectIDRelocation(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := &StateManager{statePath: filepath.Join(tmpDir, "repo", "state.json")}
+	clone := func(dir string) string {
+		repo, err := git.PlainInit(dir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"git@github.com:org/app.git"}}); err != nil {
+			t.Fatal(err)
+		}
+		project := filepath.Join(dir, "service")
+		if err := os.MkdirAll(project, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return project
+	}
+
+	oldProject := clone(filepath.Join(tmpDir, "a"))
+	if err := manager.AddSkillToProject(oldProject, "git-expert", "1.0.0", nil); err != nil {
+		t.Fatal(err)
+	}
+	id := ProjectID(oldProject)
+	if id == "" {
+		t.Fatal("ProjectID() should not be empty in a git repository with origin")
+	}
+
+	// 同一仓库的另一份克隆：原目录仍存在时不共用状态
+	newProject := clone(filepath.Join(tmpDir, "b"))
+	if ProjectID(newProject) != id {
+		t.Fatalf("ProjectID() = %s, want %s", ProjectID(newProject), id)
+	}
+	if state, _ := manager.LoadProjectState(newProject); len(state.Skills) != 0 {
+		t.Errorf("state should not be shared while the original project exists: %+v", state)
+	}
+
+	// 原目录删除后找回原状态，保存后旧记录被替换
+	if err := os.RemoveAll(filepath.Join(tmpDir, "a")); err != nil {
+		t.Fatal(err)
+	}
+	found, err := manager.FindProjectByPath(newProject)
+	if err != nil || found == nil || len(found.Skills) != 1 {
+		t.Fatalf("FindProjectByPath() after move = %+v, %v", found, err)
+	}
+	if err := manager.SetPreferredTarget(newProject, "cursor"); err != nil {
+		t.Fatal(err)
+	}
+	paths, err := manager.ProjectPaths()
+	if err != nil || len(paths) != 1 || paths[0] != found.ProjectPath {
+		t.Errorf("ProjectPaths() = %v, %v", paths, err)
+	}
+}
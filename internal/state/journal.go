@@ -0,0 +1,179 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrIncompleteTransaction 上一次状态更新中途中断，需要先恢复
+var ErrIncompleteTransaction = errors.New("存在未完成的状态更新")
+
+// JournalFile 事务中的一个文件：修改前后的内容
+type JournalFile struct {
+	Path    string `json:"path"`
+	Existed bool   `json:"existed"`
+	Before  []byte `json:"before,omitempty"`
+	After   []byte `json:"after,omitempty"`
+	Remove  bool   `json:"remove,omitempty"`
+}
+
+// Transaction 预写日志中记录的一次状态更新
+type Transaction struct {
+	Project   string        `json:"project"`
+	StartedAt time.Time     `json:"started_at"`
+	PID       int           `json:"pid"`
+	Files     []JournalFile `json:"files"`
+}
+
+// fileWrite 一次状态更新需要写入或删除的文件
+type fileWrite struct {
+	path   string
+	data   []byte
+	remove bool
+}
+
+// journalPath 预写日志路径，与状态文件位于同一目录
+func (m *StateManager) journalPath() string {
+	return m.statePath + ".journal"
+}
+
+// commit 以事务方式写入一组文件，调用方需持有状态文件锁
+//
+// 先将所有文件修改前后的内容写入预写日志，再逐个写入文件，全部完成后删除日志。
+// 写入中途进程退出时日志保留，可通过 ReplayTransaction 或 RollbackTransaction 恢复。
+func (m *StateManager) commit(project string, writes []fileWrite) error {
+	if _, err := os.Stat(m.journalPath()); err == nil {
+		return fmt.Errorf("%w（%s），请先运行 'skill-hub doctor' 恢复", ErrIncompleteTransaction, m.journalPath())
+	}
+
+	tx := Transaction{
+		Project:   project,
+		StartedAt: time.Now(),
+		PID:       os.Getpid(),
+	}
+	for _, w := range writes {
+		slog.Debug("写入状态文件", "project", project, "file", w.path, "remove", w.remove)
+		file := JournalFile{Path: w.path, After: w.data, Remove: w.remove}
+		before, err := os.ReadFile(w.path)
+		switch {
+		case err == nil:
+			file.Existed = true
+			file.Before = before
+		case !os.IsNotExist(err):
+			return fmt.Errorf("读取文件失败: %w", err)
+		}
+		tx.Files = append(tx.Files, file)
+	}
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("序列化事务日志失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.journalPath()), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := writeFileAtomic(m.journalPath(), data, 0644); err != nil {
+		return fmt.Errorf("写入事务日志失败: %w", err)
+	}
+
+	for _, file := range tx.Files {
+		if err := applyJournalFile(file.Path, file.After, !file.Remove); err != nil {
+			// 保留日志，由 doctor 回滚已写入的部分
+			return fmt.Errorf("写入状态失败，运行 'skill-hub doctor --rollback' 可恢复: %w", err)
+		}
+	}
+	return m.clearJournal()
+}
+
+// applyJournalFile 将文件写为指定内容，exists 为 false 时删除文件
+func applyJournalFile(path string, data []byte, exists bool) error {
+	if !exists {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除文件失败: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("写入文件 %s 失败: %w", path, err)
+	}
+	return nil
+}
+
+// clearJournal 删除预写日志
+func (m *StateManager) clearJournal() error {
+	if err := os.Remove(m.journalPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除事务日志失败: %w", err)
+	}
+	return nil
+}
+
+// readJournal 读取预写日志，没有未完成的事务时返回 nil
+func (m *StateManager) readJournal() (*Transaction, error) {
+	data, err := os.ReadFile(m.journalPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取事务日志失败: %w", err)
+	}
+	var tx Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("解析事务日志 %s 失败: %w", m.journalPath(), err)
+	}
+	return &tx, nil
+}
+
+// PendingTransaction 返回中途中断的状态更新，没有时返回 nil
+func (m *StateManager) PendingTransaction() (*Transaction, error) {
+	return m.readJournal()
+}
+
+// JournalPath 返回预写日志路径
+func (m *StateManager) JournalPath() string {
+	return m.journalPath()
+}
+
+// ReplayTransaction 重新写入中断事务中的所有文件，完成这次状态更新
+func (m *StateManager) ReplayTransaction() error {
+	return m.recoverTransaction(true)
+}
+
+// RollbackTransaction 将中断事务中的所有文件恢复为修改前的内容
+func (m *StateManager) RollbackTransaction() error {
+	return m.recoverTransaction(false)
+}
+
+func (m *StateManager) recoverTransaction(replay bool) error {
+	unlock, err := acquireLock(m.statePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tx, err := m.readJournal()
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		return nil
+	}
+	for _, file := range tx.Files {
+		if replay {
+			err = applyJournalFile(file.Path, file.After, !file.Remove)
+		} else {
+			err = applyJournalFile(file.Path, file.Before, file.Existed)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return m.clearJournal()
+}
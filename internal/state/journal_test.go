@@ -0,0 +1,136 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestTransactionJournal(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := &StateManager{statePath: filepath.Join(tmpDir, "repo", "state.json")}
+	project := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.AddSkillToProjectWithTarget(project, "git-expert", "1.0.0", nil, spec.TargetCursor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.EnableLocalState(project); err != nil {
+		t.Fatal(err)
+	}
+	files := []string{LocalStatePath(project), manager.statePath}
+	readAll := func() []string {
+		var contents []string
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			contents = append(contents, string(data))
+		}
+		return contents
+	}
+
+	before := readAll()
+	if err := manager.AddSkillToProjectWithTarget(project, "code-review", "1.0.0", nil, spec.TargetCursor); err != nil {
+		t.Fatal(err)
+	}
+	after := readAll()
+	if _, err := os.Stat(manager.journalPath()); !os.IsNotExist(err) {
+		t.Fatalf("journal should be removed after commit, stat error = %v", err)
+	}
+
+	// 模拟写入项目内状态文件后、写入全局状态文件前进程退出
+	simulateCrash := func() {
+		tx := Transaction{Project: project, StartedAt: time.Now(), PID: 1}
+		for i, path := range files {
+			tx.Files = append(tx.Files, JournalFile{Path: path, Existed: true, Before: []byte(before[i]), After: []byte(after[i])})
+		}
+		data, err := json.Marshal(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(manager.journalPath(), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(files[0], []byte(after[0]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(files[1], []byte(before[1]), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("pending transaction blocks updates", func(t *testing.T) {
+		simulateCrash()
+		tx, err := manager.PendingTransaction()
+		if err != nil || tx == nil || tx.Project != project || len(tx.Files) != 2 {
+			t.Fatalf("PendingTransaction() = %+v, %v", tx, err)
+		}
+		err = manager.SetPreferredTarget(project, spec.TargetClaudeCode)
+		if !errors.Is(err, ErrIncompleteTransaction) {
+			t.Errorf("SetPreferredTarget() error = %v, want ErrIncompleteTransaction", err)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		simulateCrash()
+		if err := manager.RollbackTransaction(); err != nil {
+			t.Fatalf("RollbackTransaction() error = %v", err)
+		}
+		got := readAll()
+		for i := range files {
+			if got[i] != before[i] {
+				t.Errorf("%s after rollback =\n%s\nwant\n%s", files[i], got[i], before[i])
+			}
+		}
+		if tx, _ := manager.PendingTransaction(); tx != nil {
+			t.Errorf("journal should be removed after rollback")
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		simulateCrash()
+		if err := manager.ReplayTransaction(); err != nil {
+			t.Fatalf("ReplayTransaction() error = %v", err)
+		}
+		got := readAll()
+		for i := range files {
+			if got[i] != after[i] {
+				t.Errorf("%s after replay =\n%s\nwant\n%s", files[i], got[i], after[i])
+			}
+		}
+		skills, err := manager.GetProjectSkills(project)
+		if err != nil || len(skills) != 2 {
+			t.Errorf("GetProjectSkills() = %v, %v", skills, err)
+		}
+		if err := manager.SetPreferredTarget(project, spec.TargetClaudeCode); err != nil {
+			t.Errorf("SetPreferredTarget() after replay error = %v", err)
+		}
+	})
+
+	t.Run("rollback removes created files", func(t *testing.T) {
+		created := filepath.Join(tmpDir, "created.json")
+		tx := Transaction{Project: project, Files: []JournalFile{{Path: created, After: []byte("{}")}}}
+		data, _ := json.Marshal(tx)
+		if err := os.WriteFile(manager.journalPath(), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(created, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := manager.RollbackTransaction(); err != nil {
+			t.Fatalf("RollbackTransaction() error = %v", err)
+		}
+		if _, err := os.Stat(created); !os.IsNotExist(err) {
+			t.Errorf("file created by the transaction should be removed, stat error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"skill-hub/pkg/spec"
+)
+
+// LocalStateFile 项目内状态文件相对项目根目录的路径，可提交到版本库供团队共享
+const LocalStateFile = ".skill-hub/state.json"
+
+// localStateVersion 项目内状态文件格式版本
+const localStateVersion = 1
+
+// localState 项目内状态文件的内容
+//
+// 只保存团队共享的部分：首选目标、语言、启用的技能及其版本、变量、资源文件、变量配置和工作区成员变量。
+// 应用历史和应用记录描述本机目标文件的内容，仍只保存在全局状态中。
+type localState struct {
+	FormatVersion    int                                     `json:"format_version"`
+	PreferredTarget  string                                  `json:"preferred_target,omitempty"`
+	PreferredTargets []string                                `json:"preferred_targets,omitempty"`
+	Locale           string                                  `json:"locale,omitempty"`
+	Skills           map[string]spec.SkillVars               `json:"skills"`
+	Profiles         map[string]spec.Profile                 `json:"profiles,omitempty"`
+	DefaultProfile   string                                  `json:"default_profile,omitempty"`
+	MemberVariables  map[string]map[string]map[string]string `json:"member_variables,omitempty"`
+}
+
+// LocalStatePath 返回项目内状态文件的路径
+func LocalStatePath(projectPath string) string {
+	return filepath.Join(projectPath, filepath.FromSlash(LocalStateFile))
+}
+
+// HasLocalState 检查项目是否使用项目内状态文件
+func HasLocalState(projectPath string) bool {
+	info, err := os.Stat(LocalStatePath(projectPath))
+	return err == nil && !info.IsDir()
+}
+
+// loadLocalState 读取项目内状态文件，文件不存在时返回 nil
+func loadLocalState(projectPath string) (*localState, error) {
+	data, err := os.ReadFile(LocalStatePath(projectPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取项目状态文件失败: %w", err)
+	}
+
+	local := &localState{}
+	if err := json.Unmarshal(data, local); err != nil {
+		return nil, fmt.Errorf("解析项目状态文件 %s 失败: %w", LocalStateFile, err)
+	}
+	if local.Skills == nil {
+		local.Skills = make(map[string]spec.SkillVars)
+	}
+	return local, nil
+}
+
+// marshalLocalState 序列化项目状态中团队共享的部分，作为项目内状态文件的内容
+func marshalLocalState(state *spec.ProjectState) ([]byte, error) {
+	local := localState{
+		FormatVersion:    localStateVersion,
+		PreferredTarget:  state.PreferredTarget,
+		PreferredTargets: state.PreferredTargets,
+		Locale:           state.Locale,
+		Skills:           make(map[string]spec.SkillVars, len(state.Skills)),
+		Profiles:         state.Profiles,
+		DefaultProfile:   state.DefaultProfile,
+		MemberVariables:  state.MemberVariables,
+	}
+	for id, skillVars := range state.Skills {
+		skillVars.History = nil
+		skillVars.Applied = nil
+		local.Skills[id] = skillVars
+	}
+
+	data, err := json.MarshalIndent(local, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化项目状态失败: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// mergeLocalState 合并项目内状态和全局状态：技能列表、版本、变量、资源文件、变量配置、成员变量、首选目标和语言以项目内状态为准，
+// 应用历史和应用记录沿用全局状态中同名技能的记录
+func mergeLocalState(global *spec.ProjectState, local *localState) *spec.ProjectState {
+	merged := &spec.ProjectState{
+		ProjectPath:      global.ProjectPath,
+		PreferredTarget:  global.PreferredTarget,
+		PreferredTargets: global.PreferredTargets,
+		Locale:           global.Locale,
+		Skills:           make(map[string]spec.SkillVars, len(local.Skills)),
+		Profiles:         local.Profiles,
+		DefaultProfile:   local.DefaultProfile,
+		MemberVariables:  local.MemberVariables,
+		LastSync:         global.LastSync,
+		LastApply:        global.LastApply,
+	}
+	if local.PreferredTarget != "" {
+		merged.PreferredTarget = local.PreferredTarget
+		merged.PreferredTargets = local.PreferredTargets
+	}
+	if local.Locale != "" {
+		merged.Locale = local.Locale
+	}
+	for id, skillVars := range local.Skills {
+		skillVars.SkillID = id
+		skillVars.History = global.Skills[id].History
+		skillVars.Applied = global.Skills[id].Applied
+		merged.Skills[id] = skillVars
+	}
+	return merged
+}
+
+// EnableLocalState 将项目当前状态写入项目内状态文件，此后项目状态以该文件为准
+func (m *StateManager) EnableLocalState(projectPath string) (string, error) {
+	unlock, err := acquireLock(m.statePath)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	state, err := m.LoadProjectState(projectPath)
+	if err != nil {
+		return "", err
+	}
+	data, err := marshalLocalState(state)
+	if err != nil {
+		return "", err
+	}
+	if err := m.commit(state.ProjectPath, []fileWrite{{path: LocalStatePath(state.ProjectPath), data: data}}); err != nil {
+		return "", err
+	}
+	return LocalStatePath(state.ProjectPath), nil
+}
+
+// DisableLocalState 将项目内状态合并到全局状态后删除项目内状态文件
+func (m *StateManager) DisableLocalState(projectPath string) error {
+	unlock, err := acquireLock(m.statePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := m.LoadProjectState(projectPath)
+	if err != nil {
+		return err
+	}
+	global, err := m.globalStateWrite(state)
+	if err != nil {
+		return err
+	}
+	path := LocalStatePath(state.ProjectPath)
+	if err := m.commit(state.ProjectPath, []fileWrite{global, {path: path, remove: true}}); err != nil {
+		return err
+	}
+	// 目录中没有其他文件时一并删除
+	os.Remove(filepath.Dir(path))
+	return nil
+}
@@ -0,0 +1,147 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestLocalState(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := &StateManager{statePath: filepath.Join(tmpDir, "repo", "state.json")}
+	project := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.AddSkillToProjectWithTarget(project, "git-expert", "1.0.0", map[string]string{"LANGUAGE": "go"}, spec.TargetCursor); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RecordAppliedRevision(project, "git-expert", spec.TargetCursor, spec.AppliedRevision{Version: "1.0.0", Content: "rendered"}); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := manager.EnableLocalState(project)
+	if err != nil {
+		t.Fatalf("EnableLocalState() error = %v", err)
+	}
+	if !HasLocalState(project) || path != LocalStatePath(project) {
+		t.Fatalf("EnableLocalState() path = %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "rendered") || !strings.Contains(string(data), `"LANGUAGE": "go"`) {
+		t.Errorf("local state should contain variables but not history:\n%s", data)
+	}
+
+	t.Run("local state takes precedence", func(t *testing.T) {
+		// 模拟团队成员修改并提交的项目内状态
+		content := `{"format_version": 1, "preferred_target": "claude_code", "skills": {"git-expert": {"version": "1.1.0", "variables": {"LANGUAGE": "rust"}}, "code-review": {"version": "2.0.0"}}}`
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		state, err := manager.LoadProjectState(project)
+		if err != nil {
+			t.Fatalf("LoadProjectState() error = %v", err)
+		}
+		if state.PreferredTarget != spec.TargetClaudeCode || len(state.Skills) != 2 {
+			t.Fatalf("LoadProjectState() = %+v", state)
+		}
+		skill := state.Skills["git-expert"]
+		if skill.SkillID != "git-expert" || skill.Version != "1.1.0" || skill.Variables["LANGUAGE"] != "rust" {
+			t.Errorf("git-expert = %+v", skill)
+		}
+		if len(skill.History[spec.TargetCursor]) != 1 {
+			t.Errorf("history should be kept from global state: %+v", skill.History)
+		}
+	})
+
+	t.Run("save updates both files", func(t *testing.T) {
+		if err := manager.RemoveSkillFromProject(project, "code-review"); err != nil {
+			t.Fatal(err)
+		}
+		local, err := loadLocalState(project)
+		if err != nil || len(local.Skills) != 1 {
+			t.Fatalf("local state = %+v, %v", local, err)
+		}
+		global, err := manager.loadGlobalProjectState(project)
+		if err != nil || len(global.Skills) != 1 || global.Skills["git-expert"].Version != "1.1.0" {
+			t.Errorf("global state = %+v, %v", global, err)
+		}
+	})
+
+	t.Run("find project by local state", func(t *testing.T) {
+		other := filepath.Join(tmpDir, "shared")
+		if err := os.MkdirAll(filepath.Join(other, "sub"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := manager.EnableLocalState(other); err != nil {
+			t.Fatal(err)
+		}
+		state, err := manager.FindProjectByPath(filepath.Join(other, "sub"))
+		if err != nil || state == nil || state.ProjectPath != other {
+			t.Errorf("FindProjectByPath() = %+v, %v", state, err)
+		}
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		if err := manager.DisableLocalState(project); err != nil {
+			t.Fatalf("DisableLocalState() error = %v", err)
+		}
+		if HasLocalState(project) {
+			t.Error("local state file should be removed")
+		}
+		if _, err := os.Stat(filepath.Join(project, ".skill-hub")); !os.IsNotExist(err) {
+			t.Error("empty .skill-hub directory should be removed")
+		}
+		state, err := manager.LoadProjectState(project)
+		if err != nil || state.PreferredTarget != spec.TargetClaudeCode || state.Skills["git-expert"].Variables["LANGUAGE"] != "rust" {
+			t.Errorf("LoadProjectState() after disable = %+v, %v", state, err)
+		}
+	})
+}
+
+func TestGlobalScopeState(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := filepath.Join(tmpDir, "home")
+	if err := os.MkdirAll(home, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// 主目录中的 .skill-hub/state.json 不应被当作项目内状态文件
+	if err := os.MkdirAll(filepath.Join(home, ".skill-hub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(LocalStatePath(home), []byte(`{"skills": {"other": {}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := filepath.Join(tmpDir, "repo")
+	global := &StateManager{statePath: filepath.Join(repo, GlobalStateFile), global: true}
+	project := &StateManager{statePath: filepath.Join(repo, "state.json")}
+
+	if err := global.AddSkillToProjectWithTarget(home, "git-expert", "1.0.0", nil, spec.TargetCursor); err != nil {
+		t.Fatal(err)
+	}
+	skills, err := global.GetProjectSkills(home)
+	if err != nil || len(skills) != 1 {
+		t.Fatalf("global skills = %v, %v", skills, err)
+	}
+	data, err := os.ReadFile(LocalStatePath(home))
+	if err != nil || strings.Contains(string(data), "git-expert") {
+		t.Errorf("global scope should not write the project-local state file: %s", data)
+	}
+
+	// 全局作用域与项目状态分开记录
+	if skills, err := project.GetProjectSkills(filepath.Join(tmpDir, "project")); err != nil || len(skills) != 0 {
+		t.Errorf("project skills = %v, %v", skills, err)
+	}
+	if _, err := os.Stat(project.statePath); !os.IsNotExist(err) {
+		t.Error("global scope should not write the project state file")
+	}
+}
@@ -0,0 +1,98 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrStateLocked 状态文件正被另一个 skill-hub 实例修改
+var ErrStateLocked = errors.New("另一个 skill-hub 实例正在运行")
+
+// LockTimeout 等待其他实例释放状态文件锁的最长时间
+var LockTimeout = 10 * time.Second
+
+// staleLockAge 锁文件超过该时间未释放时视为遗留的锁（持有锁的进程已异常退出）
+//
+// 锁只在读取、修改和写入状态文件期间持有，正常情况下远小于该时间。
+var staleLockAge = 2 * time.Minute
+
+// lockRetryInterval 等待锁时的重试间隔
+const lockRetryInterval = 50 * time.Millisecond
+
+// acquireLock 获取文件的建议锁（在同目录创建 <path>.lock），返回释放锁的函数
+//
+// 锁文件中记录持有锁的进程号，便于排查。超过 LockTimeout 仍无法获取时返回 ErrStateLocked。
+func acquireLock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("创建锁文件失败: %w", err)
+		}
+
+		// 清理异常退出的进程遗留的锁
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			holder := "未知"
+			if data, readErr := os.ReadFile(lockPath); readErr == nil {
+				if pid, convErr := strconv.Atoi(strings.TrimSpace(string(data))); convErr == nil {
+					holder = strconv.Itoa(pid)
+				}
+			}
+			return nil, fmt.Errorf("%w（进程 %s 持有锁文件 %s 超过 %s），请稍后重试；如果确认没有其他实例在运行，可以删除该锁文件",
+				ErrStateLocked, holder, lockPath, LockTimeout)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// writeFileAtomic 先写入临时文件再重命名，避免其他实例读到写了一半的文件
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// LockPath 返回状态文件锁的路径
+func (m *StateManager) LockPath() string {
+	return m.statePath + ".lock"
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	oldTimeout := LockTimeout
+	LockTimeout = 200 * time.Millisecond
+	defer func() { LockTimeout = oldTimeout }()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	unlock, err := acquireLock(path)
+	if err != nil {
+		t.Fatalf("acquireLock() error = %v", err)
+	}
+
+	// 锁被持有时等待超时
+	if _, err := acquireLock(path); !errors.Is(err, ErrStateLocked) {
+		t.Errorf("acquireLock() while locked error = %v, want ErrStateLocked", err)
+	}
+
+	// 释放后可以再次获取
+	unlock()
+	unlock, err = acquireLock(path)
+	if err != nil {
+		t.Fatalf("acquireLock() after release error = %v", err)
+	}
+	unlock()
+
+	// 遗留的锁文件被清理
+	lockPath := path + ".lock"
+	if err := os.WriteFile(lockPath, []byte("12345\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = acquireLock(path)
+	if err != nil {
+		t.Fatalf("acquireLock() with stale lock error = %v", err)
+	}
+	unlock()
+}
+
+func TestConcurrentStateUpdates(t *testing.T) {
+	tmpDir := t.TempDir()
+	statePath := filepath.Join(tmpDir, "state.json")
+	project := filepath.Join(tmpDir, "project")
+
+	// 每个实例使用独立的状态管理器，模拟同时运行的多个命令
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			manager := &StateManager{statePath: statePath}
+			errs <- manager.AddSkillToProject(project, fmt.Sprintf("skill-%d", i), "1.0.0", nil)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AddSkillToProject() error = %v", err)
+		}
+	}
+
+	skills, err := (&StateManager{statePath: statePath}).GetProjectSkills(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skills) != workers {
+		t.Errorf("got %d skills, want %d: concurrent updates were lost", len(skills), workers)
+	}
+	if _, err := os.Stat(statePath + ".lock"); !os.IsNotExist(err) {
+		t.Error("lock file should be removed after updates")
+	}
+}
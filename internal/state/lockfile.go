@@ -0,0 +1,148 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"skill-hub/pkg/spec"
+)
+
+// LockFileName 项目锁文件名
+const LockFileName = "skill-hub.lock"
+
+// lockFileVersion 锁文件格式版本
+const lockFileVersion = 1
+
+// LockedSkill 锁文件中固定的技能版本
+type LockedSkill struct {
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256,omitempty"` // 固定时技能文件的哈希，用于发现同版本内容变化
+	PinnedAt string `json:"pinned_at"`
+}
+
+// LockFile 项目级版本锁定文件，位于项目根目录，可提交到版本库
+type LockFile struct {
+	FormatVersion int                    `json:"format_version"`
+	Skills        map[string]LockedSkill `json:"skills"`
+
+	path string
+}
+
+// LoadLockFile 加载项目锁文件，不存在时返回空锁文件
+func LoadLockFile(projectPath string) (*LockFile, error) {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+	}
+
+	lock := &LockFile{
+		FormatVersion: lockFileVersion,
+		Skills:        make(map[string]LockedSkill),
+		path:          filepath.Join(absPath, LockFileName),
+	}
+
+	data, err := os.ReadFile(lock.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lock, nil
+		}
+		return nil, fmt.Errorf("读取锁文件失败: %w", err)
+	}
+
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("解析锁文件失败: %w", err)
+	}
+	if lock.Skills == nil {
+		lock.Skills = make(map[string]LockedSkill)
+	}
+	return lock, nil
+}
+
+// GetPath 获取锁文件路径
+func (l *LockFile) GetPath() string {
+	return l.path
+}
+
+// Pin 固定技能版本
+func (l *LockFile) Pin(skillID, version, sha256 string) {
+	l.Skills[skillID] = LockedSkill{
+		Version:  version,
+		SHA256:   sha256,
+		PinnedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// Unpin 取消固定，返回技能之前是否被固定
+func (l *LockFile) Unpin(skillID string) bool {
+	_, exists := l.Skills[skillID]
+	delete(l.Skills, skillID)
+	return exists
+}
+
+// Get 获取技能的固定信息
+func (l *LockFile) Get(skillID string) (LockedSkill, bool) {
+	locked, exists := l.Skills[skillID]
+	return locked, exists
+}
+
+// SkillIDs 返回按名称排序的已固定技能
+func (l *LockFile) SkillIDs() []string {
+	ids := make([]string, 0, len(l.Skills))
+	for id := range l.Skills {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Save 保存锁文件，没有固定的技能时删除锁文件
+func (l *LockFile) Save() error {
+	if len(l.Skills) == 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除锁文件失败: %w", err)
+		}
+		return nil
+	}
+
+	l.FormatVersion = lockFileVersion
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化锁文件失败: %w", err)
+	}
+	if err := os.WriteFile(l.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("写入锁文件失败: %w", err)
+	}
+	return nil
+}
+
+// FindRevisionByVersion 在技能的应用记录中查找指定版本最近一次应用的内容，优先使用指定目标的记录
+func FindRevisionByVersion(skillVars spec.SkillVars, target, version string) (*spec.AppliedRevision, bool) {
+	find := func(history []spec.AppliedRevision) *spec.AppliedRevision {
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].Version == version {
+				return &history[i]
+			}
+		}
+		return nil
+	}
+
+	if rev := find(skillVars.History[target]); rev != nil {
+		return rev, true
+	}
+
+	targets := make([]string, 0, len(skillVars.History))
+	for t := range skillVars.History {
+		targets = append(targets, t)
+	}
+	sort.Strings(targets)
+	for _, t := range targets {
+		if rev := find(skillVars.History[t]); rev != nil {
+			return rev, true
+		}
+	}
+	return nil, false
+}
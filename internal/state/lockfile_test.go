@@ -0,0 +1,79 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestLockFile(t *testing.T) {
+	projectPath := t.TempDir()
+
+	lock, err := LoadLockFile(projectPath)
+	if err != nil {
+		t.Fatalf("LoadLockFile() error = %v", err)
+	}
+	if len(lock.Skills) != 0 {
+		t.Errorf("new lock file should be empty")
+	}
+
+	lock.Pin("demo", "1.2.0", "abc")
+	lock.Pin("other", "2.0.0", "")
+	if err := lock.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := LoadLockFile(projectPath)
+	if err != nil {
+		t.Fatalf("LoadLockFile() error = %v", err)
+	}
+	locked, ok := reloaded.Get("demo")
+	if !ok || locked.Version != "1.2.0" || locked.SHA256 != "abc" || locked.PinnedAt == "" {
+		t.Errorf("Get(demo) = %+v, %v", locked, ok)
+	}
+	if ids := reloaded.SkillIDs(); len(ids) != 2 || ids[0] != "demo" {
+		t.Errorf("SkillIDs() = %v", ids)
+	}
+
+	if !reloaded.Unpin("demo") || reloaded.Unpin("demo") {
+		t.Error("Unpin() should report whether the skill was pinned")
+	}
+	reloaded.Unpin("other")
+	if err := reloaded.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, LockFileName)); !os.IsNotExist(err) {
+		t.Error("empty lock file should be removed")
+	}
+}
+
+func TestFindRevisionByVersion(t *testing.T) {
+	skillVars := spec.SkillVars{
+		History: map[string][]spec.AppliedRevision{
+			spec.TargetCursor:   {{Version: "1.0.0", Content: "cursor-old"}, {Version: "1.0.0", Content: "cursor-new"}},
+			spec.TargetOpenCode: {{Version: "0.9.0", Content: "opencode"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		version string
+		want    string
+		wantOK  bool
+	}{
+		{name: "Same target, latest record", target: spec.TargetCursor, version: "1.0.0", want: "cursor-new", wantOK: true},
+		{name: "Fallback to other target", target: spec.TargetCursor, version: "0.9.0", want: "opencode", wantOK: true},
+		{name: "Missing version", target: spec.TargetCursor, version: "2.0.0", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rev, ok := FindRevisionByVersion(skillVars, tt.target, tt.version)
+			if ok != tt.wantOK || (ok && rev.Content != tt.want) {
+				t.Errorf("FindRevisionByVersion() = %+v, %v; want %q, %v", rev, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
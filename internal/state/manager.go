@@ -1,18 +1,26 @@
 package state
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"skill-hub/internal/config"
+	"skill-hub/internal/secrets"
 	"skill-hub/pkg/spec"
 )
 
 // StateManager 管理项目状态
 type StateManager struct {
 	statePath string
+	global    bool // 管理全局作用域的状态：不使用项目内状态文件和项目标识
 }
 
 // GetStatePath 获取状态文件路径
@@ -36,35 +44,79 @@ func NewStateManager() (*StateManager, error) {
 	return &StateManager{statePath: statePath}, nil
 }
 
+// GlobalStateFile 全局作用域状态文件名，位于技能仓库目录中
+const GlobalStateFile = "global_state.json"
+
+// NewGlobalStateManager 创建全局作用域的状态管理器
+//
+// 安装到 ~/.cursor、~/.claude 等用户级配置中的技能与项目状态分开记录，
+// 以 GlobalScopePath 返回的路径作为项目路径。
+func NewGlobalStateManager() (*StateManager, error) {
+	repoPath, err := config.GetRepoPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StateManager{statePath: filepath.Join(repoPath, GlobalStateFile), global: true}, nil
+}
+
+// GlobalScopePath 返回全局作用域在状态中使用的项目路径（用户主目录）
+func GlobalScopePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return NormalizeProjectPath(homeDir)
+}
+
+// IsGlobal 返回状态管理器是否管理全局作用域的状态
+func (m *StateManager) IsGlobal() bool {
+	return m.global
+}
+
 // LoadProjectState 加载指定项目的状态
+//
+// 项目路径会解析符号链接。项目中存在 .skill-hub/state.json 时，以其中的技能和首选目标为准，应用历史沿用全局状态。
 func (m *StateManager) LoadProjectState(projectPath string) (*spec.ProjectState, error) {
-	absPath, err := filepath.Abs(projectPath)
+	absPath, err := NormalizeProjectPath(projectPath)
 	if err != nil {
-		return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+		return nil, err
 	}
 
-	// 读取状态文件
-	data, err := os.ReadFile(m.statePath)
+	state, err := m.loadGlobalProjectState(absPath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// 文件不存在，返回空状态，默认目标为 open_code
-			return &spec.ProjectState{
-				ProjectPath:     absPath,
-				PreferredTarget: spec.TargetOpenCode,
-				Skills:          make(map[string]spec.SkillVars),
-			}, nil
-		}
-		return nil, fmt.Errorf("读取状态文件失败: %w", err)
+		return nil, err
 	}
 
-	// 解析所有项目状态
-	var allStates map[string]spec.ProjectState
-	if err := json.Unmarshal(data, &allStates); err != nil {
-		return nil, fmt.Errorf("解析状态文件失败: %w", err)
+	if m.global {
+		return state, nil
+	}
+	local, err := loadLocalState(absPath)
+	if err != nil {
+		return nil, err
+	}
+	if local != nil {
+		return mergeLocalState(state, local), nil
+	}
+	return state, nil
+}
+
+// loadGlobalProjectState 从全局状态文件加载项目状态
+//
+// 没有该路径的记录时，查找移动前的记录（见 resolveProjectKey），找到后沿用其状态。
+func (m *StateManager) loadGlobalProjectState(absPath string) (*spec.ProjectState, error) {
+	allStates, err := m.readAllStates()
+	if err != nil {
+		return nil, err
 	}
 
 	// 查找当前项目状态
-	if state, exists := allStates[absPath]; exists {
+	if key, ok := resolveProjectKey(allStates, absPath, m.identity(absPath)); ok {
+		state := allStates[key]
+		state.ProjectPath = absPath
+		if state.Skills == nil {
+			state.Skills = make(map[string]spec.SkillVars)
+		}
 		return &state, nil
 	}
 
@@ -76,80 +128,237 @@ func (m *StateManager) LoadProjectState(projectPath string) (*spec.ProjectState,
 	}, nil
 }
 
-// SaveProjectState 保存项目状态
-func (m *StateManager) SaveProjectState(state *spec.ProjectState) error {
-	// 读取现有所有状态
-	allStates := make(map[string]spec.ProjectState)
+// identity 返回项目所在Git仓库的标识，全局作用域不使用项目标识
+func (m *StateManager) identity(absPath string) *gitIdentity {
+	if m.global {
+		return nil
+	}
+	return lookupGitIdentity(absPath)
+}
 
-	if data, err := os.ReadFile(m.statePath); err == nil {
-		if err := json.Unmarshal(data, &allStates); err != nil {
-			// 如果解析失败，使用空map
-			allStates = make(map[string]spec.ProjectState)
+// readAllStates 读取全局状态文件中的所有项目状态，文件不存在时返回空
+func (m *StateManager) readAllStates() (map[string]spec.ProjectState, error) {
+	allStates := make(map[string]spec.ProjectState)
+	data, err := os.ReadFile(m.statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return allStates, nil
 		}
+		return nil, fmt.Errorf("读取状态文件失败: %w", err)
 	}
+	if err := json.Unmarshal(data, &allStates); err != nil {
+		return nil, fmt.Errorf("解析状态文件失败: %w", err)
+	}
+	if allStates == nil {
+		allStates = make(map[string]spec.ProjectState)
+	}
+	return allStates, nil
+}
 
-	// 更新当前项目状态
-	allStates[state.ProjectPath] = *state
-
-	// 写入文件
+// marshalAllStates 序列化全局状态文件内容
+func marshalAllStates(allStates map[string]spec.ProjectState) ([]byte, error) {
 	data, err := json.MarshalIndent(allStates, "", "  ")
 	if err != nil {
-		return fmt.Errorf("序列化状态失败: %w", err)
+		return nil, fmt.Errorf("序列化状态失败: %w", err)
+	}
+	return data, nil
+}
+
+// resolveProjectKey 在全局状态中查找项目（规范路径为 absPath）的记录，返回记录的键
+//
+// 依次匹配：路径相同；旧版本以符号链接路径记录的同一目录；
+// 项目标识相同且原路径已不存在的记录（项目被移动或重新克隆到其他目录）。
+func resolveProjectKey(allStates map[string]spec.ProjectState, absPath string, identity *gitIdentity) (string, bool) {
+	if _, exists := allStates[absPath]; exists {
+		return absPath, true
 	}
 
-	// 确保目录存在
-	if err := os.MkdirAll(filepath.Dir(m.statePath), 0755); err != nil {
-		return fmt.Errorf("创建目录失败: %w", err)
+	keys := make([]string, 0, len(allStates))
+	for key := range allStates {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	if err := os.WriteFile(m.statePath, data, 0644); err != nil {
-		return fmt.Errorf("写入状态文件失败: %w", err)
+	for _, key := range keys {
+		if normalized, err := NormalizeProjectPath(key); err == nil && normalized == absPath {
+			return key, true
+		}
 	}
 
-	return nil
+	if identity == nil {
+		return "", false
+	}
+	id := identity.projectID(absPath)
+	if id == "" {
+		return "", false
+	}
+	for _, key := range keys {
+		if allStates[key].ProjectID == id && !pathExists(key) {
+			return key, true
+		}
+	}
+	return "", false
 }
 
-// AddSkillToProject 添加技能到项目
-func (m *StateManager) AddSkillToProject(projectPath, skillID, version string, variables map[string]string) error {
-	return m.AddSkillToProjectWithTarget(projectPath, skillID, version, variables, "")
+// SaveProjectState 保存项目状态，项目使用 .skill-hub/state.json 时同时更新该文件
+//
+// 写入期间持有状态文件锁，其他实例同时写入时等待，超时返回 ErrStateLocked。
+func (m *StateManager) SaveProjectState(state *spec.ProjectState) error {
+	unlock, err := acquireLock(m.statePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return m.saveProjectState(state)
 }
 
-// AddSkillToProjectWithTarget 添加技能到项目并指定目标
-func (m *StateManager) AddSkillToProjectWithTarget(projectPath, skillID, version string, variables map[string]string, target string) error {
+// updateProjectState 在状态文件锁内读取、修改并保存项目状态，避免并发的实例覆盖彼此的修改
+func (m *StateManager) updateProjectState(projectPath string, update func(state *spec.ProjectState) error) error {
+	unlock, err := acquireLock(m.statePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	state, err := m.LoadProjectState(projectPath)
 	if err != nil {
 		return err
 	}
+	if err := update(state); err != nil {
+		return err
+	}
+	return m.saveProjectState(state)
+}
 
-	// 如果指定了target且当前没有preferred_target，则设置它
-	if target != "" && state.PreferredTarget == "" {
-		state.PreferredTarget = target
+// saveProjectState 保存项目状态，调用方需持有状态文件锁
+//
+// 项目内状态文件和全局状态文件在同一事务中写入，中途中断不会只更新其中一个。
+func (m *StateManager) saveProjectState(state *spec.ProjectState) error {
+	var writes []fileWrite
+	if !m.global && HasLocalState(state.ProjectPath) {
+		data, err := marshalLocalState(state)
+		if err != nil {
+			return err
+		}
+		writes = append(writes, fileWrite{path: LocalStatePath(state.ProjectPath), data: data})
+	}
+
+	global, err := m.globalStateWrite(state)
+	if err != nil {
+		return err
+	}
+	return m.commit(state.ProjectPath, append(writes, global))
+}
+
+// globalStateWrite 生成写入项目状态后的全局状态文件，调用方需持有状态文件锁
+func (m *StateManager) globalStateWrite(state *spec.ProjectState) (fileWrite, error) {
+	// 读取现有所有状态，状态文件损坏时不覆盖，避免丢失其他项目的状态
+	allStates, err := m.readAllStates()
+	if err != nil {
+		return fileWrite{}, err
 	}
 
-	state.Skills[skillID] = spec.SkillVars{
-		SkillID:   skillID,
-		Version:   version,
-		Variables: variables,
+	if state.ProjectID == "" && !m.global {
+		state.ProjectID = ProjectID(state.ProjectPath)
 	}
 
-	return m.SaveProjectState(state)
+	// 删除同一项目在其他路径下的旧记录（符号链接路径或移动前的路径）
+	for key, other := range allStates {
+		if key == state.ProjectPath {
+			continue
+		}
+		normalized, err := NormalizeProjectPath(key)
+		if (err == nil && normalized == state.ProjectPath) || (state.ProjectID != "" && other.ProjectID == state.ProjectID && !pathExists(key)) {
+			delete(allStates, key)
+		}
+	}
+
+	// 更新当前项目状态
+	allStates[state.ProjectPath] = *state
+
+	data, err := marshalAllStates(allStates)
+	if err != nil {
+		return fileWrite{}, err
+	}
+	return fileWrite{path: m.statePath, data: data}, nil
+}
+
+// AddSkillToProject 添加技能到项目
+func (m *StateManager) AddSkillToProject(projectPath, skillID, version string, variables map[string]string) error {
+	return m.AddSkillToProjectWithTarget(projectPath, skillID, version, variables, "")
+}
+
+// AddSkillToProjectWithTarget 添加技能到项目并指定目标
+func (m *StateManager) AddSkillToProjectWithTarget(projectPath, skillID, version string, variables map[string]string, target string) error {
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		// 如果指定了target且当前没有preferred_target，则设置它
+		if target != "" && state.PreferredTarget == "" {
+			state.PreferredTarget = target
+		}
+
+		state.Skills[skillID] = spec.SkillVars{
+			SkillID:   skillID,
+			Version:   version,
+			Variables: variables,
+			History:   state.Skills[skillID].History,
+			Resources: state.Skills[skillID].Resources,
+			Applied:   state.Skills[skillID].Applied,
+			Priority:  state.Skills[skillID].Priority,
+		}
+		return nil
+	})
 }
 
-// SetPreferredTarget 设置项目的首选目标
+// SetPreferredTarget 设置项目的首选目标，target 为空时清除绑定的目标
 func (m *StateManager) SetPreferredTarget(projectPath, target string) error {
-	state, err := m.LoadProjectState(projectPath)
-	if err != nil {
-		return err
+	var targets []string
+	if target != "" {
+		targets = []string{target}
 	}
+	return m.SetPreferredTargets(projectPath, targets)
+}
 
-	// 验证目标值
-	normalizedTarget := spec.NormalizeTarget(target)
-	if normalizedTarget != spec.TargetCursor && normalizedTarget != spec.TargetClaudeCode && normalizedTarget != spec.TargetOpenCode && normalizedTarget != "" {
-		return fmt.Errorf("无效的目标值: %s，可用选项: %s, %s, %s", target, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode)
+// SetPreferredTargets 按顺序绑定项目的目标，apply、remove、feedback 默认依次处理所有绑定的目标
+//
+// 重复的目标只保留第一次出现的位置；targets 为空时清除绑定的目标。
+func (m *StateManager) SetPreferredTargets(projectPath string, targets []string) error {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		// 验证目标值
+		normalizedTarget := spec.NormalizeTarget(target)
+		if normalizedTarget != spec.TargetCursor && normalizedTarget != spec.TargetClaudeCode && normalizedTarget != spec.TargetOpenCode && normalizedTarget != spec.TargetShell {
+			return fmt.Errorf("无效的目标值: %s，可用选项: %s, %s, %s, %s", target, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell)
+		}
+		if !seen[normalizedTarget] {
+			seen[normalizedTarget] = true
+			normalized = append(normalized, normalizedTarget)
+		}
 	}
 
-	state.PreferredTarget = normalizedTarget
-	return m.SaveProjectState(state)
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		state.PreferredTarget = ""
+		state.PreferredTargets = nil
+		if len(normalized) > 0 {
+			state.PreferredTarget = normalized[0]
+		}
+		if len(normalized) > 1 {
+			state.PreferredTargets = normalized
+		}
+		return nil
+	})
+}
+
+// SetLocale 设置项目的技能提示词语言，locale 为空时使用全局配置
+func (m *StateManager) SetLocale(projectPath, locale string) error {
+	normalized, err := spec.NormalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		state.Locale = normalized
+		return nil
+	})
 }
 
 // GetPreferredTarget 获取项目的首选目标
@@ -163,33 +372,31 @@ func (m *StateManager) GetPreferredTarget(projectPath string) (string, error) {
 
 // FindProjectByPath 通过路径查找项目（支持递归向上查找）
 func (m *StateManager) FindProjectByPath(path string) (*spec.ProjectState, error) {
-	absPath, err := filepath.Abs(path)
+	absPath, err := NormalizeProjectPath(path)
 	if err != nil {
-		return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+		return nil, err
 	}
 
 	// 读取所有项目状态
-	data, err := os.ReadFile(m.statePath)
+	allStates, err := m.readAllStates()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // 文件不存在，返回nil
-		}
-		return nil, fmt.Errorf("读取状态文件失败: %w", err)
-	}
-
-	var allStates map[string]spec.ProjectState
-	if err := json.Unmarshal(data, &allStates); err != nil {
-		return nil, fmt.Errorf("解析状态文件失败: %w", err)
+		return nil, err
 	}
+	identity := m.identity(absPath)
 
-	// 递归向上查找
+	// 递归向上查找，包含 .skill-hub/state.json 的目录也视为项目
 	currentPath := absPath
 	for {
 		// 检查当前路径是否有绑定
-		if state, exists := allStates[currentPath]; exists {
+		_, exists := resolveProjectKey(allStates, currentPath, identity)
+		if exists || (!m.global && HasLocalState(currentPath) && LocalStatePath(currentPath) != m.statePath) {
+			state, err := m.LoadProjectState(currentPath)
+			if err != nil {
+				return nil, err
+			}
 			// 规范化目标类型
 			state.PreferredTarget = spec.NormalizeTarget(state.PreferredTarget)
-			return &state, nil
+			return state, nil
 		}
 
 		// 到达根目录，停止查找
@@ -205,13 +412,10 @@ func (m *StateManager) FindProjectByPath(path string) (*spec.ProjectState, error
 
 // RemoveSkillFromProject 从项目移除技能
 func (m *StateManager) RemoveSkillFromProject(projectPath, skillID string) error {
-	state, err := m.LoadProjectState(projectPath)
-	if err != nil {
-		return err
-	}
-
-	delete(state.Skills, skillID)
-	return m.SaveProjectState(state)
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		delete(state.Skills, skillID)
+		return nil
+	})
 }
 
 // GetProjectSkills 获取项目的所有技能
@@ -236,20 +440,313 @@ func (m *StateManager) ProjectHasSkill(projectPath, skillID string) (bool, error
 
 // UpdateSkillVariables 更新项目中技能的变量值
 func (m *StateManager) UpdateSkillVariables(projectPath, skillID string, variables map[string]string) error {
-	state, err := m.LoadProjectState(projectPath)
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		// 检查技能是否存在
+		skillVars, exists := state.Skills[skillID]
+		if !exists {
+			return fmt.Errorf("技能 '%s' 未在项目中启用", skillID)
+		}
+
+		// 更新变量值
+		skillVars.Variables = variables
+		state.Skills[skillID] = skillVars
+		return nil
+	})
+}
+
+// SetSkillVersion 更新项目中技能的版本，用于反馈后记录项目中的内容已是技能仓库中的新版本
+func (m *StateManager) SetSkillVersion(projectPath, skillID, version string) error {
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		skillVars, exists := state.Skills[skillID]
+		if !exists {
+			return fmt.Errorf("技能 '%s' 未在项目中启用", skillID)
+		}
+		skillVars.Version = version
+		state.Skills[skillID] = skillVars
+		return nil
+	})
+}
+
+// SetSkillPriority 设置技能在项目中的应用顺序优先级，priority 为nil时使用技能声明的优先级
+func (m *StateManager) SetSkillPriority(projectPath, skillID string, priority *int) error {
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		skillVars, exists := state.Skills[skillID]
+		if !exists {
+			return fmt.Errorf("技能 '%s' 未在项目中启用", skillID)
+		}
+		skillVars.Priority = priority
+		state.Skills[skillID] = skillVars
+		return nil
+	})
+}
+
+// SetLastApply 保存项目最近一次 apply 的结果，与已保存的结果只有时间不同时不修改状态文件
+func (m *StateManager) SetLastApply(projectPath string, report *spec.ApplyReport) error {
+	if current, err := m.LoadProjectState(projectPath); err == nil && sameApplyReport(current.LastApply, report) {
+		return nil
+	}
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		state.LastApply = report
+		return nil
+	})
+}
+
+// sameApplyReport 检查两次 apply 的结果除时间外是否相同
+func sameApplyReport(a, b *spec.ApplyReport) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	x, y := *a, *b
+	x.AppliedAt, y.AppliedAt = "", ""
+	return reflect.DeepEqual(x, y)
+}
+
+// SetSkillResources 记录技能安装到项目中的资源文件
+func (m *StateManager) SetSkillResources(projectPath, skillID string, files []string) error {
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		skillVars, exists := state.Skills[skillID]
+		if !exists {
+			return fmt.Errorf("技能 '%s' 未在项目中启用", skillID)
+		}
+
+		skillVars.Resources = files
+		state.Skills[skillID] = skillVars
+		return nil
+	})
+}
+
+// MaxAppliedRevisions 每个技能在每个目标上保留的应用记录数
+const MaxAppliedRevisions = 10
+
+// ContentHash 计算技能内容的哈希，忽略首尾空白
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(content)))
+	return hex.EncodeToString(sum[:])
+}
+
+// setAppliedRecord 更新技能在目标上的当前应用记录
+func setAppliedRecord(skillVars *spec.SkillVars, target string, rev spec.AppliedRevision) {
+	if skillVars.Applied == nil {
+		skillVars.Applied = make(map[string]spec.AppliedRecord)
+	}
+	skillVars.Applied[target] = spec.AppliedRecord{
+		Version:   rev.Version,
+		Hash:      ContentHash(rev.Content),
+		AppliedAt: rev.AppliedAt,
+		Mode:      rev.Mode,
+	}
+}
+
+// RecordAppliedRevision 记录技能应用到目标的渲染内容，并更新当前应用记录
+// （内容与上一次相同时不重复加入应用历史）
+func (m *StateManager) RecordAppliedRevision(projectPath, skillID, target string, rev spec.AppliedRevision) error {
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		skillVars, exists := state.Skills[skillID]
+		if !exists {
+			return fmt.Errorf("技能 '%s' 未在项目中启用", skillID)
+		}
+
+		if skillVars.History == nil {
+			skillVars.History = make(map[string][]spec.AppliedRevision)
+		}
+
+		if rev.AppliedAt == "" {
+			rev.AppliedAt = time.Now().UTC().Format(time.RFC3339)
+		}
+		// 项目模式不记录，与之前的应用记录保持一致
+		if rev.Mode == spec.ModeProject {
+			rev.Mode = ""
+		}
+		setAppliedRecord(&skillVars, target, rev)
+		// 变量引用解析出的值（如密钥）不写入状态文件
+		rev.Content = secrets.Redact(rev.Content, rev.Variables)
+
+		history := skillVars.History[target]
+		if n := len(history); n == 0 || history[n-1].Content != rev.Content || history[n-1].Version != rev.Version {
+			history = append(history, rev)
+			if len(history) > MaxAppliedRevisions {
+				history = history[len(history)-MaxAppliedRevisions:]
+			}
+			skillVars.History[target] = history
+		}
+		state.Skills[skillID] = skillVars
+		return nil
+	})
+}
+
+// RevisionContent 返回应用记录中的渲染内容，将保存时去除的变量引用还原为实际值
+func RevisionContent(rev spec.AppliedRevision) (string, error) {
+	return secrets.Expand(rev.Content)
+}
+
+// AppliedBase 返回技能上次应用到目标的渲染内容，用作三方合并的共同祖先
+//
+// 应用历史中最近的记录与当前应用记录的摘要不一致（如记录已被清理）时返回false。
+func AppliedBase(skillVars spec.SkillVars, target string) (string, bool) {
+	record, ok := skillVars.Applied[target]
+	history := skillVars.History[target]
+	if !ok || len(history) == 0 {
+		return "", false
+	}
+	content, err := RevisionContent(history[len(history)-1])
+	if err != nil || ContentHash(content) != record.Hash {
+		return "", false
+	}
+	return content, true
+}
+
+// AppliedUpToDate 检查技能在目标上的当前应用记录是否与本次要应用的配置模式、版本和渲染内容一致
+func AppliedUpToDate(skillVars spec.SkillVars, target, mode, version, content string) bool {
+	record, ok := skillVars.Applied[target]
+	return ok && record.AppliedMode() == mode && record.Version == version && record.Hash == ContentHash(content)
+}
+
+// FindRollbackRevision 在应用记录中查找回滚目标，返回其索引
+// toVersion为空时返回当前记录的上一条，否则返回最近一条匹配该版本的更早记录
+func FindRollbackRevision(history []spec.AppliedRevision, toVersion string) (int, error) {
+	if len(history) == 0 {
+		return -1, fmt.Errorf("没有应用记录")
+	}
+
+	if toVersion == "" {
+		if len(history) < 2 {
+			return -1, fmt.Errorf("没有更早的应用记录")
+		}
+		return len(history) - 2, nil
+	}
+
+	for i := len(history) - 2; i >= 0; i-- {
+		if history[i].Version == toVersion {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("没有找到版本 %s 的应用记录", toVersion)
+}
+
+// RollbackSkill 将技能在目标上的应用记录回退到指定索引，并同步版本和变量，mode 为回滚写入的配置模式
+func (m *StateManager) RollbackSkill(projectPath, skillID, target string, index int, mode string) error {
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		skillVars, exists := state.Skills[skillID]
+		if !exists {
+			return fmt.Errorf("技能 '%s' 未在项目中启用", skillID)
+		}
+
+		history := skillVars.History[target]
+		if index < 0 || index >= len(history) {
+			return fmt.Errorf("无效的应用记录索引: %d", index)
+		}
+
+		rev := history[index]
+		skillVars.History[target] = history[:index+1]
+		content, err := RevisionContent(rev)
+		if err != nil {
+			content = rev.Content
+		}
+		setAppliedRecord(&skillVars, target, spec.AppliedRevision{
+			Version:   rev.Version,
+			Content:   content,
+			AppliedAt: time.Now().UTC().Format(time.RFC3339),
+			Mode:      mode,
+		})
+		skillVars.Version = rev.Version
+		// 使用配置应用的记录中的变量包含配置的覆盖值，不还原到技能自身的变量
+		if rev.Variables != nil && rev.Profile == "" {
+			skillVars.Variables = rev.Variables
+		}
+		state.Skills[skillID] = skillVars
+		return nil
+	})
+}
+
+// FindProjectsUsingSkill 查找启用了指定技能的所有项目路径
+func (m *StateManager) FindProjectsUsingSkill(skillID string) ([]string, error) {
+	allStates, err := m.readAllStates()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 检查技能是否存在
-	skillVars, exists := state.Skills[skillID]
-	if !exists {
-		return fmt.Errorf("技能 '%s' 未在项目中启用", skillID)
+	var projects []string
+	for path, state := range allStates {
+		if _, exists := state.Skills[skillID]; exists {
+			projects = append(projects, path)
+		}
 	}
+	sort.Strings(projects)
+	return projects, nil
+}
 
-	// 更新变量值
-	skillVars.Variables = variables
-	state.Skills[skillID] = skillVars
+// ProjectPaths 返回全局状态中记录的所有项目路径
+func (m *StateManager) ProjectPaths() ([]string, error) {
+	allStates, err := m.readAllStates()
+	if err != nil {
+		return nil, err
+	}
 
-	return m.SaveProjectState(state)
+	paths := make([]string, 0, len(allStates))
+	for path := range allStates {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// RelocateProject 将项目状态从原路径移到新路径，用于项目目录被移动或重命名后恢复状态
+//
+// 新路径已有启用了技能的项目状态时返回错误，避免覆盖。返回新路径的规范形式。
+func (m *StateManager) RelocateProject(oldPath, newPath string) (string, error) {
+	newKey, err := NormalizeProjectPath(newPath)
+	if err != nil {
+		return "", err
+	}
+
+	unlock, err := acquireLock(m.statePath)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	allStates, err := m.readAllStates()
+	if err != nil {
+		return "", err
+	}
+
+	// 原路径通常已不存在，依次尝试记录时可能使用的形式
+	oldKey := ""
+	candidates := []string{oldPath}
+	if absPath, err := filepath.Abs(oldPath); err == nil {
+		candidates = append(candidates, absPath)
+	}
+	if normalized, err := NormalizeProjectPath(oldPath); err == nil {
+		candidates = append(candidates, normalized)
+	}
+	for _, candidate := range candidates {
+		if _, exists := allStates[candidate]; exists {
+			oldKey = candidate
+			break
+		}
+	}
+	if oldKey == "" {
+		return "", fmt.Errorf("没有找到项目 %s 的状态记录", oldPath)
+	}
+	if oldKey == newKey {
+		return newKey, nil
+	}
+	if existing, exists := allStates[newKey]; exists && len(existing.Skills) > 0 {
+		return "", fmt.Errorf("项目 %s 已有状态记录（%d 个技能），请先移除其中的技能", newKey, len(existing.Skills))
+	}
+
+	state := allStates[oldKey]
+	delete(allStates, oldKey)
+	state.ProjectPath = newKey
+	state.ProjectID = ProjectID(newKey)
+	allStates[newKey] = state
+
+	data, err := marshalAllStates(allStates)
+	if err != nil {
+		return "", err
+	}
+	if err := m.commit(newKey, []fileWrite{{path: m.statePath, data: data}}); err != nil {
+		return "", err
+	}
+	return newKey, nil
 }
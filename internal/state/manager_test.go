@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"skill-hub/pkg/spec"
@@ -410,3 +411,303 @@ func TestStateManager(t *testing.T) {
 		}
 	})
 }
+
+func TestAppliedRevisions(t *testing.T) {
+	manager := &StateManager{statePath: filepath.Join(t.TempDir(), "state.json")}
+	projectPath := t.TempDir()
+
+	if err := manager.AddSkillToProject(projectPath, "demo", "1.0.0", map[string]string{"LANG": "go"}); err != nil {
+		t.Fatalf("AddSkillToProject() error = %v", err)
+	}
+
+	record := func(version, content string) {
+		t.Helper()
+		rev := spec.AppliedRevision{Version: version, Content: content, Variables: map[string]string{"LANG": version}}
+		if err := manager.RecordAppliedRevision(projectPath, "demo", spec.TargetCursor, rev); err != nil {
+			t.Fatalf("RecordAppliedRevision() error = %v", err)
+		}
+	}
+	record("1.0.0", "v1")
+	record("1.0.0", "v1") // 相同内容不重复记录
+	record("1.1.0", "v2")
+	record("2.0.0", "v3")
+
+	skills, _ := manager.GetProjectSkills(projectPath)
+	history := skills["demo"].History[spec.TargetCursor]
+	if len(history) != 3 {
+		t.Fatalf("history length = %d, want 3", len(history))
+	}
+	if history[0].AppliedAt == "" {
+		t.Error("AppliedAt should be set")
+	}
+	applied := skills["demo"].Applied[spec.TargetCursor]
+	if applied.Version != "2.0.0" || applied.Hash != ContentHash("v3") || applied.AppliedAt == "" {
+		t.Errorf("applied record = %+v, want latest revision", applied)
+	}
+	if ContentHash("v3\n") != ContentHash("v3") {
+		t.Error("ContentHash should ignore surrounding whitespace")
+	}
+	if !AppliedUpToDate(skills["demo"], spec.TargetCursor, spec.ModeProject, "2.0.0", "v3\n") {
+		t.Error("AppliedUpToDate() = false, want true for the recorded content")
+	}
+	if AppliedUpToDate(skills["demo"], spec.TargetCursor, spec.ModeProject, "2.0.0", "v4") || AppliedUpToDate(skills["demo"], spec.TargetClaudeCode, spec.ModeProject, "2.0.0", "v3") {
+		t.Error("AppliedUpToDate() = true, want false for different content or target")
+	}
+	if AppliedUpToDate(skills["demo"], spec.TargetCursor, spec.ModeGlobal, "2.0.0", "v3") {
+		t.Error("AppliedUpToDate() = true, want false for a different mode")
+	}
+
+	t.Run("Applied mode", func(t *testing.T) {
+		if mode := skills["demo"].Applied[spec.TargetCursor].Mode; mode != "" {
+			t.Errorf("project mode recorded as %q, want empty", mode)
+		}
+		rev := spec.AppliedRevision{Version: "2.0.0", Content: "v3", Mode: spec.ModeGlobal}
+		if err := manager.RecordAppliedRevision(projectPath, "demo", spec.TargetClaudeCode, rev); err != nil {
+			t.Fatalf("RecordAppliedRevision() error = %v", err)
+		}
+		skills, _ := manager.GetProjectSkills(projectPath)
+		if got := skills["demo"].Applied[spec.TargetClaudeCode].AppliedMode(); got != spec.ModeGlobal {
+			t.Errorf("AppliedMode() = %q, want %q", got, spec.ModeGlobal)
+		}
+		if got := skills["demo"].Applied[spec.TargetCursor].AppliedMode(); got != spec.ModeProject {
+			t.Errorf("AppliedMode() = %q, want %q", got, spec.ModeProject)
+		}
+	})
+
+	t.Run("Find rollback revision", func(t *testing.T) {
+		tests := []struct {
+			to      string
+			want    int
+			wantErr bool
+		}{
+			{to: "", want: 1},
+			{to: "1.0.0", want: 0},
+			{to: "2.0.0", wantErr: true}, // 当前版本不能作为回滚目标
+			{to: "9.9.9", wantErr: true},
+		}
+		for _, tt := range tests {
+			got, err := FindRollbackRevision(history, tt.to)
+			if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+				t.Errorf("FindRollbackRevision(%q) = %d, %v; want %d, wantErr %v", tt.to, got, err, tt.want, tt.wantErr)
+			}
+		}
+		if _, err := FindRollbackRevision(history[:1], ""); err == nil {
+			t.Error("FindRollbackRevision() expected error with single record")
+		}
+	})
+
+	t.Run("Rollback skill", func(t *testing.T) {
+		if err := manager.RollbackSkill(projectPath, "demo", spec.TargetCursor, 0, spec.ModeProject); err != nil {
+			t.Fatalf("RollbackSkill() error = %v", err)
+		}
+		skills, _ := manager.GetProjectSkills(projectPath)
+		skill := skills["demo"]
+		if skill.Version != "1.0.0" || skill.Variables["LANG"] != "1.0.0" {
+			t.Errorf("skill = %+v, want version and variables restored", skill)
+		}
+		if len(skill.History[spec.TargetCursor]) != 1 {
+			t.Errorf("history length = %d, want 1", len(skill.History[spec.TargetCursor]))
+		}
+		if applied := skill.Applied[spec.TargetCursor]; applied.Version != "1.0.0" || applied.Hash != ContentHash("v1") {
+			t.Errorf("applied record = %+v, want rolled back revision", applied)
+		}
+	})
+
+	t.Run("Re-enable keeps history", func(t *testing.T) {
+		if err := manager.AddSkillToProject(projectPath, "demo", "2.0.0", nil); err != nil {
+			t.Fatalf("AddSkillToProject() error = %v", err)
+		}
+		skills, _ := manager.GetProjectSkills(projectPath)
+		if len(skills["demo"].History[spec.TargetCursor]) != 1 {
+			t.Error("history should survive re-enabling the skill")
+		}
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	auditLog := &AuditLog{path: filepath.Join(t.TempDir(), "history.jsonl")}
+
+	t.Run("Read missing log", func(t *testing.T) {
+		entries, err := auditLog.Read(AuditFilter{})
+		if err != nil || len(entries) != 0 {
+			t.Errorf("Read() = %v, %v; want empty", entries, err)
+		}
+	})
+
+	records := []AuditEntry{
+		{Operation: OpApply, Project: "/p1", SkillID: "a", Version: "1.0.0", Adapter: spec.TargetCursor},
+		{Operation: OpApply, Project: "/p2", SkillID: "b", Version: "1.0.0", Adapter: spec.TargetOpenCode},
+		{Operation: OpRemove, Project: "/p1", SkillID: "a", Version: "1.0.0", Adapter: spec.TargetCursor},
+		{Operation: OpUpdate, Detail: "sync"},
+	}
+	for _, entry := range records {
+		if err := auditLog.Append(entry); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	// 损坏的行不影响读取
+	f, _ := os.OpenFile(auditLog.path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("not json\n")
+	f.Close()
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		want   int
+	}{
+		{name: "All", filter: AuditFilter{}, want: 4},
+		{name: "By skill", filter: AuditFilter{SkillID: "a"}, want: 2},
+		{name: "By project", filter: AuditFilter{Project: "/p2"}, want: 1},
+		{name: "By operation", filter: AuditFilter{Operation: OpRemove}, want: 1},
+		{name: "Limit", filter: AuditFilter{Limit: 2}, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := auditLog.Read(tt.filter)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("Read() returned %d entries, want %d", len(entries), tt.want)
+			}
+		})
+	}
+
+	entries, _ := auditLog.Read(AuditFilter{Limit: 1})
+	if entries[0].Operation != OpUpdate {
+		t.Errorf("Limit should keep the most recent entries, got %+v", entries[0])
+	}
+	if entries[0].Time == "" {
+		t.Error("Append() should fill in the timestamp")
+	}
+}
+
+func TestFindProjectsUsingSkill(t *testing.T) {
+	manager := &StateManager{statePath: filepath.Join(t.TempDir(), "state.json")}
+
+	projects, err := manager.FindProjectsUsingSkill("demo")
+	if err != nil || len(projects) != 0 {
+		t.Fatalf("FindProjectsUsingSkill() on missing state = %v, %v", projects, err)
+	}
+
+	p1, p2, p3 := t.TempDir(), t.TempDir(), t.TempDir()
+	manager.AddSkillToProject(p1, "demo", "1.0.0", nil)
+	manager.AddSkillToProject(p2, "other", "1.0.0", nil)
+	manager.AddSkillToProject(p3, "demo", "1.0.0", nil)
+
+	projects, err = manager.FindProjectsUsingSkill("demo")
+	if err != nil {
+		t.Fatalf("FindProjectsUsingSkill() error = %v", err)
+	}
+	if len(projects) != 2 {
+		t.Errorf("FindProjectsUsingSkill() = %v, want %s and %s", projects, p1, p3)
+	}
+}
+
+func TestAppliedRevisionRedactsReferences(t *testing.T) {
+	t.Setenv("SKILL_HUB_TEST_TOKEN", "s3cr3t-token")
+	manager := &StateManager{statePath: filepath.Join(t.TempDir(), "state.json")}
+	projectPath := t.TempDir()
+
+	variables := map[string]string{"TOKEN": "${env:SKILL_HUB_TEST_TOKEN}"}
+	if err := manager.AddSkillToProject(projectPath, "demo", "1.0.0", variables); err != nil {
+		t.Fatal(err)
+	}
+	rev := spec.AppliedRevision{Version: "1.0.0", Variables: variables, Content: "token: s3cr3t-token"}
+	if err := manager.RecordAppliedRevision(projectPath, "demo", spec.TargetCursor, rev); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(manager.statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cr3t-token") {
+		t.Errorf("state file should not contain resolved values:\n%s", data)
+	}
+
+	skills, _ := manager.GetProjectSkills(projectPath)
+	content, err := RevisionContent(skills["demo"].History[spec.TargetCursor][0])
+	if err != nil || content != rev.Content {
+		t.Errorf("RevisionContent() = %q, %v", content, err)
+	}
+	if skills["demo"].Applied[spec.TargetCursor].Hash != ContentHash(rev.Content) {
+		t.Error("applied hash should be computed from the resolved content")
+	}
+}
+
+func TestSetPreferredTargets(t *testing.T) {
+	manager := &StateManager{statePath: filepath.Join(t.TempDir(), "state.json")}
+	project := t.TempDir()
+
+	if err := manager.SetPreferredTargets(project, []string{"cursor", "claude", "cursor"}); err != nil {
+		t.Fatalf("SetPreferredTargets() error = %v", err)
+	}
+	state, err := manager.LoadProjectState(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bound := state.BoundTargets()
+	if state.PreferredTarget != spec.TargetCursor || len(bound) != 2 || bound[1] != spec.TargetClaudeCode {
+		t.Errorf("bound targets = %v (preferred %s), want [cursor claude_code]", bound, state.PreferredTarget)
+	}
+
+	// 绑定单个目标时清除目标列表
+	if err := manager.SetPreferredTarget(project, spec.TargetOpenCode); err != nil {
+		t.Fatal(err)
+	}
+	state, _ = manager.LoadProjectState(project)
+	if len(state.PreferredTargets) != 0 || len(state.BoundTargets()) != 1 || state.BoundTargets()[0] != spec.TargetOpenCode {
+		t.Errorf("after SetPreferredTarget: %v / %v", state.PreferredTarget, state.PreferredTargets)
+	}
+
+	if err := manager.SetPreferredTargets(project, []string{"cursor", "vim"}); err == nil {
+		t.Error("SetPreferredTargets() with invalid target should fail")
+	}
+}
+
+func TestSetLastApply(t *testing.T) {
+	manager := &StateManager{statePath: filepath.Join(t.TempDir(), "state.json")}
+	project := t.TempDir()
+
+	report := func(appliedAt, status string) *spec.ApplyReport {
+		return &spec.ApplyReport{
+			AppliedAt: appliedAt,
+			Mode:      "project",
+			Targets:   []string{spec.TargetCursor},
+			Skills:    []spec.ApplyResult{{SkillID: "demo", Target: spec.TargetCursor, Status: status}},
+			Files:     []string{},
+		}
+	}
+	lastApply := func() *spec.ApplyReport {
+		t.Helper()
+		state, err := manager.LoadProjectState(project)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return state.LastApply
+	}
+
+	if err := manager.SetLastApply(project, report("2026-01-01T00:00:00Z", spec.ApplyApplied)); err != nil {
+		t.Fatalf("SetLastApply() error = %v", err)
+	}
+	if got := lastApply(); got == nil || got.Count(spec.ApplyApplied) != 1 {
+		t.Fatalf("LastApply = %+v, want 1 applied skill", got)
+	}
+
+	// 只有时间不同时不更新项目状态
+	if err := manager.SetLastApply(project, report("2026-01-02T00:00:00Z", spec.ApplyApplied)); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastApply(); got.AppliedAt != "2026-01-01T00:00:00Z" {
+		t.Errorf("AppliedAt = %s, want unchanged", got.AppliedAt)
+	}
+
+	if err := manager.SetLastApply(project, report("2026-01-03T00:00:00Z", spec.ApplyUpToDate)); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastApply(); got.AppliedAt != "2026-01-03T00:00:00Z" || got.Count(spec.ApplyUpToDate) != 1 {
+		t.Errorf("LastApply = %+v, want up_to_date report", got)
+	}
+}
@@ -0,0 +1,155 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"skill-hub/pkg/spec"
+)
+
+// ResolveProfile 返回项目在指定配置下每个技能的变量值
+//
+// name 为空时使用项目的默认配置，没有默认配置时返回技能自身的变量。
+// 返回实际使用的配置名称。配置或其继承的配置不存在、继承形成循环时返回错误。
+func ResolveProfile(state *spec.ProjectState, name string) (string, map[string]map[string]string, error) {
+	if name == "" {
+		name = state.DefaultProfile
+	}
+
+	resolved := make(map[string]map[string]string, len(state.Skills))
+	for skillID, skillVars := range state.Skills {
+		values := make(map[string]string, len(skillVars.Variables))
+		for k, v := range skillVars.Variables {
+			values[k] = v
+		}
+		resolved[skillID] = values
+	}
+	if name == "" {
+		return "", resolved, nil
+	}
+
+	// 从最上层的配置开始依次合并
+	var chain []string
+	visited := make(map[string]bool)
+	for current := name; current != ""; {
+		if visited[current] {
+			return "", nil, fmt.Errorf("配置 %s 的继承关系形成循环: %s -> %s", name, strings.Join(chain, " -> "), current)
+		}
+		profile, ok := state.Profiles[current]
+		if !ok {
+			if current == name {
+				return "", nil, fmt.Errorf("配置 %s 不存在", name)
+			}
+			return "", nil, fmt.Errorf("配置 %s 继承的配置 %s 不存在", chain[len(chain)-1], current)
+		}
+		visited[current] = true
+		chain = append(chain, current)
+		current = profile.Extends
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		for skillID, variables := range state.Profiles[chain[i]].Variables {
+			values, enabled := resolved[skillID]
+			if !enabled {
+				continue
+			}
+			for k, v := range variables {
+				values[k] = v
+			}
+		}
+	}
+	return name, resolved, nil
+}
+
+// ProfileNames 返回项目的配置名称，按名称排序
+func ProfileNames(state *spec.ProjectState) []string {
+	names := make([]string, 0, len(state.Profiles))
+	for name := range state.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SaveProfile 创建或更新项目的配置
+//
+// extends 为 nil 时保留原有的继承关系；variables 中的变量合并到配置中，值为空字符串时删除该变量。
+func (m *StateManager) SaveProfile(projectPath, name string, extends *string, skillID string, variables map[string]string) error {
+	if name == "" {
+		return fmt.Errorf("配置名称不能为空")
+	}
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		if state.Profiles == nil {
+			state.Profiles = make(map[string]spec.Profile)
+		}
+		profile := state.Profiles[name]
+		if extends != nil {
+			profile.Extends = *extends
+		}
+
+		if skillID != "" {
+			if _, enabled := state.Skills[skillID]; !enabled {
+				return fmt.Errorf("技能 '%s' 未在项目中启用", skillID)
+			}
+			if profile.Variables == nil {
+				profile.Variables = make(map[string]map[string]string)
+			}
+			values := profile.Variables[skillID]
+			if values == nil {
+				values = make(map[string]string)
+			}
+			for k, v := range variables {
+				if v == "" {
+					delete(values, k)
+				} else {
+					values[k] = v
+				}
+			}
+			if len(values) == 0 {
+				delete(profile.Variables, skillID)
+			} else {
+				profile.Variables[skillID] = values
+			}
+		}
+
+		state.Profiles[name] = profile
+		// 保存前检查继承关系
+		if _, _, err := ResolveProfile(state, name); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// RemoveProfile 删除项目的配置，仍被其他配置继承时返回错误
+func (m *StateManager) RemoveProfile(projectPath, name string) error {
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		if _, ok := state.Profiles[name]; !ok {
+			return fmt.Errorf("配置 %s 不存在", name)
+		}
+		for _, other := range ProfileNames(state) {
+			if state.Profiles[other].Extends == name {
+				return fmt.Errorf("配置 %s 被配置 %s 继承，不能删除", name, other)
+			}
+		}
+		delete(state.Profiles, name)
+		if state.DefaultProfile == name {
+			state.DefaultProfile = ""
+		}
+		return nil
+	})
+}
+
+// SetDefaultProfile 设置项目的默认配置，name 为空时取消默认配置
+func (m *StateManager) SetDefaultProfile(projectPath, name string) error {
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		if name != "" {
+			if _, ok := state.Profiles[name]; !ok {
+				return fmt.Errorf("配置 %s 不存在", name)
+			}
+		}
+		state.DefaultProfile = name
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestProfiles(t *testing.T) {
+	manager := &StateManager{statePath: filepath.Join(t.TempDir(), "state.json")}
+	project := t.TempDir()
+	if err := manager.AddSkillToProject(project, "git-expert", "1.0.0", map[string]string{"LANGUAGE": "go", "STYLE": "quick"}); err != nil {
+		t.Fatal(err)
+	}
+
+	none := ""
+	base := "backend"
+	if err := manager.SaveProfile(project, "backend", &none, "git-expert", map[string]string{"LANGUAGE": "rust"}); err != nil {
+		t.Fatalf("SaveProfile() error = %v", err)
+	}
+	if err := manager.SaveProfile(project, "strict", &base, "git-expert", map[string]string{"STYLE": "detailed"}); err != nil {
+		t.Fatalf("SaveProfile() error = %v", err)
+	}
+	if err := manager.SaveProfile(project, "backend", nil, "missing", map[string]string{"X": "1"}); err == nil {
+		t.Error("SaveProfile() for a skill that is not enabled should fail")
+	}
+
+	load := func() *spec.ProjectState {
+		t.Helper()
+		state, err := manager.LoadProjectState(project)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return state
+	}
+
+	t.Run("inheritance", func(t *testing.T) {
+		name, vars, err := ResolveProfile(load(), "strict")
+		if err != nil || name != "strict" {
+			t.Fatalf("ResolveProfile() = %s, %v", name, err)
+		}
+		if got := vars["git-expert"]; got["LANGUAGE"] != "rust" || got["STYLE"] != "detailed" {
+			t.Errorf("strict variables = %v", got)
+		}
+		// 技能自身的变量不受影响
+		if _, vars, _ := ResolveProfile(load(), ""); vars["git-expert"]["LANGUAGE"] != "go" {
+			t.Errorf("base variables = %v", vars["git-expert"])
+		}
+	})
+
+	t.Run("default profile", func(t *testing.T) {
+		if err := manager.SetDefaultProfile(project, "backend"); err != nil {
+			t.Fatal(err)
+		}
+		name, vars, err := ResolveProfile(load(), "")
+		if err != nil || name != "backend" || vars["git-expert"]["LANGUAGE"] != "rust" {
+			t.Errorf("ResolveProfile() with default = %s, %v, %v", name, vars, err)
+		}
+		if err := manager.SetDefaultProfile(project, "missing"); err == nil {
+			t.Error("SetDefaultProfile() with unknown profile should fail")
+		}
+	})
+
+	t.Run("cycles and removal", func(t *testing.T) {
+		strict := "strict"
+		if err := manager.SaveProfile(project, "backend", &strict, "", nil); err == nil {
+			t.Error("SaveProfile() creating an inheritance cycle should fail")
+		}
+		if _, _, err := ResolveProfile(load(), "missing"); err == nil {
+			t.Error("ResolveProfile() with unknown profile should fail")
+		}
+		if err := manager.RemoveProfile(project, "backend"); err == nil {
+			t.Error("RemoveProfile() of an inherited profile should fail")
+		}
+		if err := manager.RemoveProfile(project, "strict"); err != nil {
+			t.Fatal(err)
+		}
+		if err := manager.RemoveProfile(project, "backend"); err != nil {
+			t.Fatal(err)
+		}
+		if state := load(); len(state.Profiles) != 0 || state.DefaultProfile != "" {
+			t.Errorf("profiles after removal = %+v, default %q", state.Profiles, state.DefaultProfile)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package state
+
+import (
+	"sort"
+
+	"skill-hub/pkg/spec"
+)
+
+// DanglingSkill 项目状态中引用的、技能仓库中已不存在的技能
+type DanglingSkill struct {
+	Project string   `json:"project"`
+	SkillID string   `json:"skill_id"`
+	Targets []string `json:"targets,omitempty"` // 有应用记录的目标，目标文件中可能仍有该技能的内容
+}
+
+// FindDanglingSkills 查找项目状态中 exists 返回 false 的技能
+//
+// projectPath 为空时检查状态文件中记录的所有项目。结果按项目和技能排序。
+func (m *StateManager) FindDanglingSkills(projectPath string, exists func(skillID string) bool) ([]DanglingSkill, error) {
+	projects := []string{projectPath}
+	if projectPath == "" {
+		var err error
+		if projects, err = m.ProjectPaths(); err != nil {
+			return nil, err
+		}
+	}
+
+	var dangling []DanglingSkill
+	for _, project := range projects {
+		state, err := m.LoadProjectState(project)
+		if err != nil {
+			return nil, err
+		}
+		for skillID, skillVars := range state.Skills {
+			if exists(skillID) {
+				continue
+			}
+			item := DanglingSkill{Project: state.ProjectPath, SkillID: skillID}
+			for target := range skillVars.Applied {
+				item.Targets = append(item.Targets, target)
+			}
+			sort.Strings(item.Targets)
+			dangling = append(dangling, item)
+		}
+	}
+
+	sort.Slice(dangling, func(i, j int) bool {
+		if dangling[i].Project != dangling[j].Project {
+			return dangling[i].Project < dangling[j].Project
+		}
+		return dangling[i].SkillID < dangling[j].SkillID
+	})
+	return dangling, nil
+}
+
+// PruneSkills 从项目状态中删除技能引用，每个项目在一次状态更新中完成
+func (m *StateManager) PruneSkills(dangling []DanglingSkill) error {
+	byProject := make(map[string][]string)
+	var projects []string
+	for _, item := range dangling {
+		if _, seen := byProject[item.Project]; !seen {
+			projects = append(projects, item.Project)
+		}
+		byProject[item.Project] = append(byProject[item.Project], item.SkillID)
+	}
+
+	for _, project := range projects {
+		skillIDs := byProject[project]
+		err := m.updateProjectState(project, func(state *spec.ProjectState) error {
+			for _, skillID := range skillIDs {
+				delete(state.Skills, skillID)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestPruneDanglingSkills(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := &StateManager{statePath: filepath.Join(tmpDir, "repo", "state.json")}
+	projectA := filepath.Join(tmpDir, "a")
+	projectB := filepath.Join(tmpDir, "b")
+	for _, dir := range []string{projectA, projectB} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, add := range []struct{ project, skillID string }{
+		{projectA, "git-expert"},
+		{projectA, "deleted-skill"},
+		{projectB, "deleted-skill"},
+		{projectB, "renamed-skill"},
+	} {
+		if err := manager.AddSkillToProjectWithTarget(add.project, add.skillID, "1.0.0", nil, spec.TargetCursor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := manager.RecordAppliedRevision(projectA, "deleted-skill", spec.TargetCursor, spec.AppliedRevision{Version: "1.0.0", Content: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	exists := func(skillID string) bool { return skillID == "git-expert" }
+
+	dangling, err := manager.FindDanglingSkills(projectA, exists)
+	if err != nil {
+		t.Fatalf("FindDanglingSkills() error = %v", err)
+	}
+	if len(dangling) != 1 || dangling[0].SkillID != "deleted-skill" || len(dangling[0].Targets) != 1 || dangling[0].Targets[0] != spec.TargetCursor {
+		t.Fatalf("FindDanglingSkills(projectA) = %+v", dangling)
+	}
+
+	dangling, err = manager.FindDanglingSkills("", exists)
+	if err != nil {
+		t.Fatalf("FindDanglingSkills() error = %v", err)
+	}
+	if len(dangling) != 3 || dangling[0].Project != projectA || dangling[2].SkillID != "renamed-skill" {
+		t.Fatalf("FindDanglingSkills(all) = %+v", dangling)
+	}
+
+	if err := manager.PruneSkills(dangling); err != nil {
+		t.Fatalf("PruneSkills() error = %v", err)
+	}
+	skillsA, _ := manager.GetProjectSkills(projectA)
+	skillsB, _ := manager.GetProjectSkills(projectB)
+	if len(skillsA) != 1 || len(skillsB) != 0 {
+		t.Errorf("after prune: a = %v, b = %v", skillsA, skillsB)
+	}
+	if _, ok := skillsA["git-expert"]; !ok {
+		t.Errorf("existing skill should be kept: %v", skillsA)
+	}
+}
@@ -0,0 +1,137 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/pkg/spec"
+)
+
+// 项目状态快照的导出格式
+const (
+	SnapshotFormatJSON = "json"
+	SnapshotFormatYAML = "yaml"
+)
+
+// snapshotVersion 项目状态快照格式版本
+const snapshotVersion = 1
+
+// Snapshot 可移植的项目状态快照：启用的技能、版本、变量、首选目标和语言
+//
+// 不包含项目路径和应用历史，团队成员导入后在本机重新应用。
+type Snapshot struct {
+	FormatVersion   int    `json:"format_version" yaml:"format_version"`
+	ExportedAt      string `json:"exported_at,omitempty" yaml:"exported_at,omitempty"`
+	PreferredTarget string `json:"preferred_target,omitempty" yaml:"preferred_target,omitempty"`
+	// 绑定多个目标时的全部首选目标，第一个与 PreferredTarget 相同
+	PreferredTargets []string                 `json:"preferred_targets,omitempty" yaml:"preferred_targets,omitempty"`
+	Locale           string                   `json:"locale,omitempty" yaml:"locale,omitempty"`
+	Skills           map[string]SnapshotSkill `json:"skills" yaml:"skills"`
+}
+
+// SnapshotSkill 快照中的技能配置
+type SnapshotSkill struct {
+	Version   string            `json:"version" yaml:"version"`
+	Variables map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// ExportSnapshot 导出项目状态快照
+func (m *StateManager) ExportSnapshot(projectPath string) (*Snapshot, error) {
+	state, err := m.LoadProjectState(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		FormatVersion:    snapshotVersion,
+		ExportedAt:       time.Now().UTC().Format(time.RFC3339),
+		PreferredTarget:  state.PreferredTarget,
+		PreferredTargets: state.PreferredTargets,
+		Locale:           state.Locale,
+		Skills:           make(map[string]SnapshotSkill, len(state.Skills)),
+	}
+	for id, skillVars := range state.Skills {
+		snapshot.Skills[id] = SnapshotSkill{Version: skillVars.Version, Variables: skillVars.Variables}
+	}
+	return snapshot, nil
+}
+
+// SnapshotFormatForPath 根据文件扩展名判断快照格式，.yaml/.yml 为 YAML，其余为 JSON
+func SnapshotFormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return SnapshotFormatYAML
+	default:
+		return SnapshotFormatJSON
+	}
+}
+
+// MarshalSnapshot 按指定格式序列化快照
+func MarshalSnapshot(snapshot *Snapshot, format string) ([]byte, error) {
+	switch format {
+	case SnapshotFormatJSON:
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化项目状态失败: %w", err)
+		}
+		return append(data, '\n'), nil
+	case SnapshotFormatYAML:
+		data, err := yaml.Marshal(snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("序列化项目状态失败: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("无效的导出格式: %s，可用选项: %s, %s", format, SnapshotFormatJSON, SnapshotFormatYAML)
+	}
+}
+
+// ParseSnapshot 解析 JSON 或 YAML 格式的快照
+func ParseSnapshot(data []byte) (*Snapshot, error) {
+	// JSON 是 YAML 的子集，统一按 YAML 解析
+	snapshot := &Snapshot{}
+	if err := yaml.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("解析项目状态快照失败: %w", err)
+	}
+	if snapshot.FormatVersion == 0 || snapshot.FormatVersion > snapshotVersion {
+		return nil, fmt.Errorf("不支持的项目状态快照版本: %d", snapshot.FormatVersion)
+	}
+	if snapshot.PreferredTarget != "" {
+		snapshot.PreferredTarget = spec.NormalizeTarget(snapshot.PreferredTarget)
+	}
+	for i, target := range snapshot.PreferredTargets {
+		snapshot.PreferredTargets[i] = spec.NormalizeTarget(target)
+	}
+	if snapshot.Skills == nil {
+		snapshot.Skills = make(map[string]SnapshotSkill)
+	}
+	return snapshot, nil
+}
+
+// Targets 返回快照中的首选目标列表
+func (s *Snapshot) Targets() []string {
+	if len(s.PreferredTargets) > 0 {
+		return s.PreferredTargets
+	}
+	if s.PreferredTarget != "" {
+		return []string{s.PreferredTarget}
+	}
+	return nil
+}
+
+// Equal 判断技能配置是否与项目中已启用的配置相同
+func (s SnapshotSkill) Equal(skillVars spec.SkillVars) bool {
+	if s.Version != skillVars.Version || len(s.Variables) != len(skillVars.Variables) {
+		return false
+	}
+	for name, value := range s.Variables {
+		if current, ok := skillVars.Variables[name]; !ok || current != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestSnapshot(t *testing.T) {
+	manager := &StateManager{statePath: filepath.Join(t.TempDir(), "state.json")}
+	project := t.TempDir()
+	if err := manager.AddSkillToProjectWithTarget(project, "git-expert", "1.0.0", map[string]string{"LANGUAGE": "go"}, spec.TargetCursor); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetPreferredTarget(project, spec.TargetCursor); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RecordAppliedRevision(project, "git-expert", spec.TargetCursor, spec.AppliedRevision{Version: "1.0.0", Content: "rendered"}); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := manager.ExportSnapshot(project)
+	if err != nil {
+		t.Fatalf("ExportSnapshot() error = %v", err)
+	}
+
+	for _, format := range []string{SnapshotFormatJSON, SnapshotFormatYAML} {
+		data, err := MarshalSnapshot(snapshot, format)
+		if err != nil {
+			t.Fatalf("MarshalSnapshot(%s) error = %v", format, err)
+		}
+		parsed, err := ParseSnapshot(data)
+		if err != nil {
+			t.Fatalf("ParseSnapshot(%s) error = %v\n%s", format, err, data)
+		}
+		skill := parsed.Skills["git-expert"]
+		if parsed.PreferredTarget != spec.TargetCursor || skill.Version != "1.0.0" || skill.Variables["LANGUAGE"] != "go" {
+			t.Errorf("%s round trip = %+v", format, parsed)
+		}
+	}
+
+	if _, err := MarshalSnapshot(snapshot, "toml"); err == nil {
+		t.Error("MarshalSnapshot() with unknown format should fail")
+	}
+	if _, err := ParseSnapshot([]byte(`{"format_version": 99, "skills": {}}`)); err == nil {
+		t.Error("ParseSnapshot() with newer format version should fail")
+	}
+	if SnapshotFormatForPath("team.yml") != SnapshotFormatYAML || SnapshotFormatForPath("team.json") != SnapshotFormatJSON {
+		t.Error("SnapshotFormatForPath() returned wrong format")
+	}
+
+	skills, _ := manager.GetProjectSkills(project)
+	incoming := snapshot.Skills["git-expert"]
+	if !incoming.Equal(skills["git-expert"]) {
+		t.Error("Equal() should ignore history")
+	}
+	incoming.Variables = map[string]string{"LANGUAGE": "rust"}
+	if incoming.Equal(skills["git-expert"]) {
+		t.Error("Equal() should detect changed variables")
+	}
+}
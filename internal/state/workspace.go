@@ -0,0 +1,94 @@
+package state
+
+import (
+	"fmt"
+
+	"skill-hub/pkg/spec"
+)
+
+// MemberSkills 返回工作区启用的技能在成员项目中的配置：技能变量与成员覆盖的变量合并，后者优先
+//
+// state 为工作区根目录的项目状态，member 为成员相对于根目录的路径。返回的配置不含应用历史和资源文件。
+func MemberSkills(state *spec.ProjectState, member string) map[string]spec.SkillVars {
+	overrides := state.MemberVariables[member]
+	skills := make(map[string]spec.SkillVars, len(state.Skills))
+	for skillID, skillVars := range state.Skills {
+		variables := make(map[string]string, len(skillVars.Variables)+len(overrides[skillID]))
+		for k, v := range skillVars.Variables {
+			variables[k] = v
+		}
+		for k, v := range overrides[skillID] {
+			variables[k] = v
+		}
+		skills[skillID] = spec.SkillVars{SkillID: skillID, Version: skillVars.Version, Variables: variables, Priority: skillVars.Priority}
+	}
+	return skills
+}
+
+// SetMemberVariables 设置工作区成员项目覆盖的技能变量，值为空字符串时删除该变量
+//
+// rootPath 为工作区根目录，member 为成员相对于根目录的路径，技能需要已在工作区启用。
+func (m *StateManager) SetMemberVariables(rootPath, member, skillID string, variables map[string]string) error {
+	if member == "" {
+		return fmt.Errorf("成员路径不能为空")
+	}
+	return m.updateProjectState(rootPath, func(state *spec.ProjectState) error {
+		if _, enabled := state.Skills[skillID]; !enabled {
+			return fmt.Errorf("技能 '%s' 未在工作区启用", skillID)
+		}
+		if state.MemberVariables == nil {
+			state.MemberVariables = make(map[string]map[string]map[string]string)
+		}
+		skills := state.MemberVariables[member]
+		if skills == nil {
+			skills = make(map[string]map[string]string)
+		}
+		values := skills[skillID]
+		if values == nil {
+			values = make(map[string]string)
+		}
+		for k, v := range variables {
+			if v == "" {
+				delete(values, k)
+			} else {
+				values[k] = v
+			}
+		}
+
+		if len(values) == 0 {
+			delete(skills, skillID)
+		} else {
+			skills[skillID] = values
+		}
+		if len(skills) == 0 {
+			delete(state.MemberVariables, member)
+		} else {
+			state.MemberVariables[member] = skills
+		}
+		return nil
+	})
+}
+
+// EnableSkills 在一次状态更新中启用多个技能，已启用的技能保留应用历史、应用记录和资源文件
+//
+// 项目没有首选目标时设置为 target。
+func (m *StateManager) EnableSkills(projectPath string, skills map[string]spec.SkillVars, target string) error {
+	return m.updateProjectState(projectPath, func(state *spec.ProjectState) error {
+		if target != "" && state.PreferredTarget == "" {
+			state.PreferredTarget = target
+		}
+		for skillID, skillVars := range skills {
+			existing := state.Skills[skillID]
+			state.Skills[skillID] = spec.SkillVars{
+				SkillID:   skillID,
+				Version:   skillVars.Version,
+				Variables: skillVars.Variables,
+				History:   existing.History,
+				Resources: existing.Resources,
+				Applied:   existing.Applied,
+				Priority:  existing.Priority,
+			}
+		}
+		return nil
+	})
+}
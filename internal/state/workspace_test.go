@@ -0,0 +1,76 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skill-hub/pkg/spec"
+)
+
+func TestMemberVariables(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := &StateManager{statePath: filepath.Join(tmpDir, "repo", "state.json")}
+	root := filepath.Join(tmpDir, "monorepo")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.AddSkillToProject(root, "git-expert", "1.0.0", map[string]string{"LANGUAGE": "go", "STYLE": "strict"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetMemberVariables(root, "svc/web", "missing", map[string]string{"LANGUAGE": "ts"}); err == nil {
+		t.Error("SetMemberVariables() should fail for a skill not enabled in the workspace")
+	}
+	if err := manager.SetMemberVariables(root, "svc/web", "git-expert", map[string]string{"LANGUAGE": "ts"}); err != nil {
+		t.Fatalf("SetMemberVariables() error = %v", err)
+	}
+
+	rootState, err := manager.LoadProjectState(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web := MemberSkills(rootState, "svc/web")["git-expert"]
+	if web.Variables["LANGUAGE"] != "ts" || web.Variables["STYLE"] != "strict" || web.Version != "1.0.0" {
+		t.Errorf("MemberSkills(svc/web) = %+v", web)
+	}
+	if api := MemberSkills(rootState, "svc/api")["git-expert"]; api.Variables["LANGUAGE"] != "go" {
+		t.Errorf("MemberSkills(svc/api) = %+v", api)
+	}
+	// 合并结果不应修改工作区状态
+	if rootState.Skills["git-expert"].Variables["LANGUAGE"] != "go" {
+		t.Errorf("workspace variables changed: %+v", rootState.Skills["git-expert"])
+	}
+
+	// 空值删除覆盖，成员没有覆盖时删除成员记录
+	if err := manager.SetMemberVariables(root, "svc/web", "git-expert", map[string]string{"LANGUAGE": ""}); err != nil {
+		t.Fatal(err)
+	}
+	rootState, _ = manager.LoadProjectState(root)
+	if len(rootState.MemberVariables) != 0 {
+		t.Errorf("MemberVariables = %v, want empty", rootState.MemberVariables)
+	}
+
+	t.Run("enable skills keeps history", func(t *testing.T) {
+		member := filepath.Join(root, "svc", "api")
+		if err := os.MkdirAll(member, 0755); err != nil {
+			t.Fatal(err)
+		}
+		skills := MemberSkills(rootState, "svc/api")
+		if err := manager.EnableSkills(member, skills, spec.TargetCursor); err != nil {
+			t.Fatalf("EnableSkills() error = %v", err)
+		}
+		if err := manager.RecordAppliedRevision(member, "git-expert", spec.TargetCursor, spec.AppliedRevision{Version: "1.0.0", Content: "x"}); err != nil {
+			t.Fatal(err)
+		}
+		skills["git-expert"] = spec.SkillVars{Version: "1.1.0", Variables: map[string]string{"LANGUAGE": "rust"}}
+		if err := manager.EnableSkills(member, skills, ""); err != nil {
+			t.Fatal(err)
+		}
+		state, _ := manager.LoadProjectState(member)
+		got := state.Skills["git-expert"]
+		if got.Version != "1.1.0" || got.Variables["LANGUAGE"] != "rust" || len(got.History[spec.TargetCursor]) != 1 {
+			t.Errorf("member state = %+v", state)
+		}
+	})
+}
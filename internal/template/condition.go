@@ -0,0 +1,231 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 应用技能时注入模板的内置变量
+const (
+	VarTarget = "Target" // 目标工具，例如 cursor、claude_code、open_code
+	VarMode   = "Mode"   // 配置模式：project 或 global
+)
+
+// ActionPattern 匹配条件区块指令 {{if ...}}、{{else if ...}}、{{else}} 和 {{end}}
+var ActionPattern = regexp.MustCompile(`\{\{\s*(if|else\s+if|else|end)\b([^}]*)\}\}`)
+
+// conditionTokenPattern 将条件拆分为操作符、.变量和带引号的字符串
+var conditionTokenPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|\S+`)
+
+// variableNamePattern 变量名格式，与 VariablePattern 一致
+var variableNamePattern = regexp.MustCompile(`^\w+$`)
+
+// WithContext 返回加入内置变量后的模板数据，内置变量覆盖同名的技能变量
+func WithContext(variables map[string]string, target, mode string) map[string]string {
+	data := make(map[string]string, len(variables)+2)
+	for key, value := range variables {
+		data[key] = value
+	}
+	data[VarTarget] = target
+	data[VarMode] = mode
+	return data
+}
+
+// conditionFunc 条件中可以使用的函数
+type conditionFunc struct {
+	minArgs int // 最少参数个数
+	maxArgs int // 最多参数个数，0表示不限制
+	eval    func(args []string) bool
+}
+
+// conditionFuncs 条件中允许使用的函数白名单，其他函数一律拒绝
+var conditionFuncs = map[string]conditionFunc{
+	// eq 第一个参数等于其余任一参数时成立
+	"eq": {minArgs: 2, eval: func(args []string) bool {
+		for _, value := range args[1:] {
+			if args[0] == value {
+				return true
+			}
+		}
+		return false
+	}},
+	"ne":  {minArgs: 2, maxArgs: 2, eval: func(args []string) bool { return args[0] != args[1] }},
+	"not": {minArgs: 1, maxArgs: 1, eval: func(args []string) bool { return !truthy(args[0]) }},
+}
+
+// conditionFuncNames 返回排序后的可用函数名
+func conditionFuncNames() []string {
+	names := make([]string, 0, len(conditionFuncs))
+	for name := range conditionFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// conditionFrame 一层条件区块的求值状态
+type conditionFrame struct {
+	parentActive bool // 外层区块是否输出
+	active       bool // 当前分支是否输出
+	taken        bool // 是否已有分支成立
+	sawElse      bool // 是否已遇到 {{else}}
+	line         int  // {{if}} 所在行，用于报错
+}
+
+// RenderConditionals 计算内容中的条件区块，只保留条件成立的分支
+//
+// 支持的条件：.Name（值非空且不是 false/0 时成立）、not .Name、
+// eq .Name "a" "b"（等于任一值）和 ne .Name "a"，例如：
+//
+//	{{if eq .Target "cursor"}}...{{else if .STRICT}}...{{else}}...{{end}}
+//
+// 单独占一行的指令连同换行一起删除。变量占位符原样保留，由 Render 渲染。
+func RenderConditionals(content string, data map[string]string) (string, error) {
+	matches := ActionPattern.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content, nil
+	}
+
+	var result strings.Builder
+	var stack []*conditionFrame
+	active := func() bool {
+		return len(stack) == 0 || stack[len(stack)-1].active
+	}
+
+	pos := 0
+	for _, m := range matches {
+		start, end := standaloneLine(content, m[0], m[1])
+		if active() && start > pos {
+			result.WriteString(content[pos:start])
+		}
+		pos = end
+
+		line := strings.Count(content[:m[0]], "\n") + 1
+		keyword := strings.Join(strings.Fields(content[m[2]:m[3]]), " ")
+		condition := strings.TrimSpace(content[m[4]:m[5]])
+
+		switch keyword {
+		case "if":
+			ok, err := evalCondition(condition, data)
+			if err != nil {
+				return "", fmt.Errorf("第 %d 行: %w", line, err)
+			}
+			parentActive := active()
+			stack = append(stack, &conditionFrame{parentActive: parentActive, active: parentActive && ok, taken: ok, line: line})
+		case "else if", "else":
+			if len(stack) == 0 {
+				return "", fmt.Errorf("第 %d 行: {{%s}} 没有对应的 {{if}}", line, keyword)
+			}
+			frame := stack[len(stack)-1]
+			if frame.sawElse {
+				return "", fmt.Errorf("第 %d 行: {{%s}} 不能出现在 {{else}} 之后", line, keyword)
+			}
+			ok := true
+			if keyword == "else if" {
+				var err error
+				if ok, err = evalCondition(condition, data); err != nil {
+					return "", fmt.Errorf("第 %d 行: %w", line, err)
+				}
+			} else if condition != "" {
+				return "", fmt.Errorf("第 %d 行: {{else}} 不能带条件，应使用 {{else if ...}}", line)
+			}
+			frame.active = frame.parentActive && !frame.taken && ok
+			frame.taken = frame.taken || ok
+			frame.sawElse = keyword == "else"
+		case "end":
+			if len(stack) == 0 {
+				return "", fmt.Errorf("第 %d 行: {{end}} 没有对应的 {{if}}", line)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		return "", fmt.Errorf("第 %d 行: {{if}} 缺少对应的 {{end}}", stack[len(stack)-1].line)
+	}
+	if pos < len(content) {
+		result.WriteString(content[pos:])
+	}
+	return result.String(), nil
+}
+
+// standaloneLine 指令单独占一行时，将范围扩展到整行（包括换行）
+func standaloneLine(content string, start, end int) (int, int) {
+	lineStart := strings.LastIndex(content[:start], "\n") + 1
+	if strings.TrimSpace(content[lineStart:start]) != "" {
+		return start, end
+	}
+	lineEnd := strings.Index(content[end:], "\n")
+	if lineEnd < 0 {
+		lineEnd = len(content) - end
+	}
+	if strings.TrimSpace(content[end:end+lineEnd]) != "" {
+		return start, end
+	}
+	if end+lineEnd < len(content) {
+		lineEnd++
+	}
+	return lineStart, end + lineEnd
+}
+
+// evalCondition 计算条件表达式
+func evalCondition(condition string, data map[string]string) (bool, error) {
+	tokens := conditionTokenPattern.FindAllString(condition, -1)
+	if len(tokens) == 0 {
+		return false, fmt.Errorf("{{if}} 缺少条件")
+	}
+
+	operands := make([]string, 0, len(tokens)-1)
+	for _, token := range tokens[1:] {
+		value, err := operandValue(token, data)
+		if err != nil {
+			return false, err
+		}
+		operands = append(operands, value)
+	}
+
+	if fn, ok := conditionFuncs[tokens[0]]; ok {
+		if len(operands) < fn.minArgs || (fn.maxArgs > 0 && len(operands) > fn.maxArgs) {
+			return false, fmt.Errorf("条件 %q 无效: %s 的参数个数不正确", condition, tokens[0])
+		}
+		return fn.eval(operands), nil
+	}
+
+	if !strings.HasPrefix(tokens[0], ".") {
+		return false, fmt.Errorf("条件 %q 使用了不支持的函数 %s，可用函数: %s", condition, tokens[0], strings.Join(conditionFuncNames(), ", "))
+	}
+	if len(tokens) != 1 {
+		return false, fmt.Errorf("条件 %q 无效，比较变量请使用 eq 或 ne", condition)
+	}
+	value, err := operandValue(tokens[0], data)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+// operandValue 返回操作数的值：.NAME 为变量值（未设置时为空），带引号的为字符串字面量
+func operandValue(token string, data map[string]string) (string, error) {
+	if strings.HasPrefix(token, `"`) {
+		value, err := strconv.Unquote(token)
+		if err != nil {
+			return "", fmt.Errorf("无效的字符串 %s", token)
+		}
+		return value, nil
+	}
+	if name := strings.TrimPrefix(token, "."); name != token && variableNamePattern.MatchString(name) {
+		return data[name], nil
+	}
+	return "", fmt.Errorf("无效的参数 %s，应为 .NAME 或带引号的字符串", token)
+}
+
+// truthy 判断值在条件中是否成立
+func truthy(value string) bool {
+	switch strings.ToLower(value) {
+	case "", "false", "0":
+		return false
+	}
+	return true
+}
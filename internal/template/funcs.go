@@ -0,0 +1,222 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDateLayout now 函数未指定格式时使用的日期格式
+const DefaultDateLayout = "2006-01-02"
+
+// Func 技能内容中可以调用的模板函数，例如 {{upper .LANGUAGE}}
+type Func struct {
+	Name        string // 函数名
+	Usage       string // 调用示例
+	Description string // 说明
+	minArgs     int    // 最少参数个数
+	maxArgs     int    // 最多参数个数，-1表示不限制
+	call        func(args []string, ctx FuncContext) (string, error)
+}
+
+// FuncContext 模板函数的运行环境
+type FuncContext struct {
+	Now          func() time.Time // 当前时间，为nil时使用 time.Now
+	EnvAllowlist []string         // env 函数允许读取的环境变量（配置项 template_env）
+}
+
+// funcs 技能内容中允许使用的模板函数，按显示顺序排列，其他函数一律拒绝
+var funcs = []Func{
+	{
+		Name: "upper", Usage: `{{upper .NAME}}`, Description: "转换为大写",
+		minArgs: 1, maxArgs: 1,
+		call: func(args []string, ctx FuncContext) (string, error) { return strings.ToUpper(args[0]), nil },
+	},
+	{
+		Name: "lower", Usage: `{{lower .NAME}}`, Description: "转换为小写",
+		minArgs: 1, maxArgs: 1,
+		call: func(args []string, ctx FuncContext) (string, error) { return strings.ToLower(args[0]), nil },
+	},
+	{
+		Name: "indent", Usage: `{{indent 4 .NAME}}`, Description: "每个非空行前加指定个数的空格，用于在列表或代码块中嵌入多行内容",
+		minArgs: 2, maxArgs: 2,
+		call: func(args []string, ctx FuncContext) (string, error) {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 0 {
+				return "", fmt.Errorf("indent 的缩进应为非负整数: %s", args[0])
+			}
+			lines := strings.Split(args[1], "\n")
+			for i, line := range lines {
+				if strings.TrimSpace(line) != "" {
+					lines[i] = strings.Repeat(" ", n) + line
+				}
+			}
+			return strings.Join(lines, "\n"), nil
+		},
+	},
+	{
+		Name: "default", Usage: `{{default "go" .NAME}}`, Description: "变量为空或未设置时使用第一个参数",
+		minArgs: 2, maxArgs: 2,
+		call: func(args []string, ctx FuncContext) (string, error) {
+			if args[1] == "" {
+				return args[0], nil
+			}
+			return args[1], nil
+		},
+	},
+	{
+		Name: "join", Usage: `{{join ", " .LIST}}`, Description: "用第一个参数连接其余参数，参数中逗号分隔的多个值分别连接，忽略空值",
+		minArgs: 2, maxArgs: -1,
+		call: func(args []string, ctx FuncContext) (string, error) {
+			var items []string
+			for _, arg := range args[1:] {
+				for _, item := range strings.Split(arg, ",") {
+					if item = strings.TrimSpace(item); item != "" {
+						items = append(items, item)
+					}
+				}
+			}
+			return strings.Join(items, args[0]), nil
+		},
+	},
+	{
+		Name: "now", Usage: `{{now "2006-01-02"}}`, Description: "应用时的本地时间，格式使用 Go 的时间格式，默认为日期；内容每次应用都可能变化",
+		minArgs: 0, maxArgs: 1,
+		call: func(args []string, ctx FuncContext) (string, error) {
+			layout := DefaultDateLayout
+			if len(args) == 1 && args[0] != "" {
+				layout = args[0]
+			}
+			now := time.Now
+			if ctx.Now != nil {
+				now = ctx.Now
+			}
+			return now().Format(layout), nil
+		},
+	},
+	{
+		Name: "env", Usage: `{{env "CI_BRANCH"}}`, Description: "环境变量的值（未设置时为空），只能读取配置项 template_env 中列出的环境变量",
+		minArgs: 1, maxArgs: 1,
+		call: func(args []string, ctx FuncContext) (string, error) {
+			for _, name := range ctx.EnvAllowlist {
+				if name == args[0] {
+					return os.Getenv(name), nil
+				}
+			}
+			return "", fmt.Errorf("环境变量 %s 不在允许读取的列表中，在配置项 template_env 中添加后才能使用", args[0])
+		},
+	},
+}
+
+// FuncPattern 匹配模板函数调用，例如 {{upper .NAME}}、{{default "go" .LANGUAGE}}
+var FuncPattern = regexp.MustCompile(`\{\{\s*(` + strings.Join(FuncNames(), "|") + `)\b([^}]*)\}\}`)
+
+// callPattern 匹配任意形如函数调用的指令，用于拒绝不在允许列表中的函数，例如 {{printf "%s" .NAME}}
+var callPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_]\w*)\b([^}]*)\}\}`)
+
+// reservedActions 由其他步骤处理的指令：条件由 RenderConditionals 计算，包含由 ExpandIncludes 展开
+var reservedActions = map[string]bool{"if": true, "else": true, "end": true, "include": true}
+
+// integerPattern 函数参数中的整数字面量
+var integerPattern = regexp.MustCompile(`^-?\d+$`)
+
+// Funcs 返回技能内容中可以使用的模板函数
+func Funcs() []Func {
+	return append([]Func(nil), funcs...)
+}
+
+// FuncNames 返回可用的模板函数名
+func FuncNames() []string {
+	names := make([]string, 0, len(funcs))
+	for _, fn := range funcs {
+		names = append(names, fn.Name)
+	}
+	return names
+}
+
+// lookupFunc 按名称查找模板函数
+func lookupFunc(name string) (Func, bool) {
+	for _, fn := range funcs {
+		if fn.Name == name {
+			return fn, true
+		}
+	}
+	return Func{}, false
+}
+
+// RenderFuncs 计算内容中的模板函数调用，替换为函数的结果
+//
+// 参数可以是 .NAME（变量值）、带引号的字符串或整数，引用未设置的变量时返回错误，default 的参数除外。
+// 调用不在允许列表中的函数时返回错误。变量占位符和条件指令原样保留，分别由 Render 和 RenderConditionals 处理。
+func RenderFuncs(content string, data map[string]string, ctx FuncContext) (string, error) {
+	var renderErr error
+	result := callPattern.ReplaceAllStringFunc(content, func(call string) string {
+		if renderErr != nil {
+			return call
+		}
+		name := callPattern.FindStringSubmatch(call)[1]
+		if reservedActions[name] {
+			return call
+		}
+		value, err := callFunc(call, data, ctx)
+		if err != nil {
+			line := strings.Count(content[:strings.Index(content, call)], "\n") + 1
+			renderErr = fmt.Errorf("第 %d 行: %w", line, err)
+			return call
+		}
+		return value
+	})
+	return result, renderErr
+}
+
+// callFunc 计算一个模板函数调用
+func callFunc(call string, data map[string]string, ctx FuncContext) (string, error) {
+	match := callPattern.FindStringSubmatch(call)
+	fn, ok := lookupFunc(match[1])
+	if !ok {
+		return "", fmt.Errorf("不支持的模板函数 %s，可用的函数: %s", match[1], strings.Join(FuncNames(), ", "))
+	}
+	var args []string
+	for _, token := range conditionTokenPattern.FindAllString(match[2], -1) {
+		if integerPattern.MatchString(token) {
+			args = append(args, token)
+			continue
+		}
+		if name := strings.TrimPrefix(token, "."); name != token && fn.Name != "default" {
+			if _, ok := data[name]; !ok {
+				return "", fmt.Errorf("%s 的参数引用了未设置的变量 %s", fn.Name, name)
+			}
+		}
+		value, err := operandValue(token, data)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, value)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return "", fmt.Errorf("%s 的参数个数不正确，用法: %s", fn.Name, fn.Usage)
+	}
+	return fn.call(args, ctx)
+}
+
+// FuncVariables 返回内容中模板函数的参数引用的变量名，default 的参数有默认值，不需要输入，不包括在内
+func FuncVariables(content string) []string {
+	var variables []string
+	seen := make(map[string]bool)
+	for _, match := range FuncPattern.FindAllStringSubmatch(content, -1) {
+		if match[1] == "default" {
+			continue
+		}
+		for _, token := range conditionTokenPattern.FindAllString(match[2], -1) {
+			name := strings.TrimPrefix(token, ".")
+			if name != token && variableNamePattern.MatchString(name) && !seen[name] {
+				seen[name] = true
+				variables = append(variables, name)
+			}
+		}
+	}
+	return variables
+}
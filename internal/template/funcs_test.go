@@ -0,0 +1,66 @@
+package template
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRenderFuncs(t *testing.T) {
+	t.Setenv("SKILL_HUB_TEST_BRANCH", "main")
+	t.Setenv("SKILL_HUB_TEST_SECRET", "s3cr3t")
+	data := WithContext(map[string]string{"LANGUAGE": "Go", "TOOLS": "git, make,,docker", "CODE": "a\n\nb"}, "cursor", "project")
+	ctx := FuncContext{
+		Now:          func() time.Time { return time.Date(2026, 3, 4, 5, 6, 0, 0, time.Local) },
+		EnvAllowlist: []string{"SKILL_HUB_TEST_BRANCH"},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "大小写", content: "{{upper .LANGUAGE}} {{lower .LANGUAGE}} {{ upper .Target }}", want: "GO go CURSOR"},
+		{name: "缩进多行内容", content: "- 示例:\n{{indent 2 .CODE}}", want: "- 示例:\n  a\n\n  b"},
+		{name: "默认值", content: "{{default \"python\" .MISSING}}/{{default \"python\" .LANGUAGE}}", want: "python/Go"},
+		{name: "连接列表", content: "{{join \" | \" .TOOLS}}；{{join \"-\" .LANGUAGE \"x\"}}", want: "git | make | docker；Go-x"},
+		{name: "当前时间", content: "{{now}} {{now \"15:04\"}}", want: "2026-03-04 05:06"},
+		{name: "允许的环境变量", content: "分支 {{env \"SKILL_HUB_TEST_BRANCH\"}}", want: "分支 main"},
+		{name: "保留变量占位符", content: "{{.LANGUAGE}} {{if .X}}{{end}}", want: "{{.LANGUAGE}} {{if .X}}{{end}}"},
+		{name: "不在允许列表中的环境变量", content: "{{env \"SKILL_HUB_TEST_SECRET\"}}", wantErr: true},
+		{name: "参数个数不正确", content: "{{upper .A .B}}", wantErr: true},
+		{name: "无效的缩进", content: "{{indent \"x\" .CODE}}", wantErr: true},
+		{name: "无效的参数", content: "{{lower LANGUAGE}}", wantErr: true},
+		{name: "参数引用未设置的变量", content: "{{upper .MISSING}}", wantErr: true},
+		{name: "连接未设置的变量", content: "{{join \",\" .LANGUAGE .MISSING}}", wantErr: true},
+		{name: "未知函数", content: "{{printf \"%s\" .LANGUAGE}}", wantErr: true},
+		{name: "未知指令", content: "{{ range .TOOLS }}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderFuncs(tt.content, data, ctx)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("RenderFuncs() expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderFuncs() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderFuncs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuncVariables(t *testing.T) {
+	got := FuncVariables("{{upper .LANGUAGE}} {{.OTHER}} {{default \"x\" .STYLE}} {{join \",\" .LANGUAGE .TOOLS}} {{now}}")
+	want := []string{"LANGUAGE", "TOOLS"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FuncVariables() = %v, want %v", got, want)
+	}
+}
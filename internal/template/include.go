@@ -0,0 +1,76 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IncludePattern 匹配包含指令 {{include "file.md"}}
+var IncludePattern = regexp.MustCompile(`\{\{\s*include\s+"([^"]+)"\s*\}\}`)
+
+// maxIncludeDepth 包含指令的最大嵌套层数
+const maxIncludeDepth = 10
+
+// ExpandIncludes 展开内容中的 {{include "file.md"}} 指令
+//
+// 路径相对于技能目录 skillDir 解析，被包含的文件可以继续包含其他文件，路径同样相对于技能目录。
+// root 限制可包含文件的范围，用于在多个技能间共享片段，为空时只能包含技能目录中的文件。
+// 被包含文件末尾的一个换行会被去掉，使指令可以写在行内。变量占位符原样保留，由 Render 渲染。
+func ExpandIncludes(content, skillDir, root string) (string, error) {
+	if !IncludePattern.MatchString(content) {
+		return content, nil
+	}
+	if root == "" {
+		root = skillDir
+	}
+	return expandIncludes(content, skillDir, root, nil)
+}
+
+func expandIncludes(content, skillDir, root string, stack []string) (string, error) {
+	var expandErr error
+	result := IncludePattern.ReplaceAllStringFunc(content, func(directive string) string {
+		if expandErr != nil {
+			return directive
+		}
+		name := IncludePattern.FindStringSubmatch(directive)[1]
+		included, err := readInclude(name, skillDir, root, stack)
+		if err != nil {
+			expandErr = err
+			return directive
+		}
+		return included
+	})
+	return result, expandErr
+}
+
+// readInclude 读取被包含的文件并展开其中的包含指令
+func readInclude(name, skillDir, root string, stack []string) (string, error) {
+	if filepath.IsAbs(name) {
+		return "", fmt.Errorf("包含文件 %s 必须使用相对路径", name)
+	}
+	path := filepath.Join(skillDir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("包含文件 %s 不在技能目录中", name)
+	}
+
+	for _, parent := range stack {
+		if parent == path {
+			return "", fmt.Errorf("包含文件 %s 存在循环包含", name)
+		}
+	}
+	if len(stack) >= maxIncludeDepth {
+		return "", fmt.Errorf("包含文件 %s 嵌套超过 %d 层", name, maxIncludeDepth)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取包含文件 %s 失败: %w", name, err)
+	}
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	return expandIncludes(content, skillDir, root, append(stack, path))
+}
@@ -3,11 +3,17 @@ package template
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+
+	"skill-hub/internal/diff"
 )
 
-// VariablePattern 匹配模板变量的正则表达式
-var VariablePattern = regexp.MustCompile(`\{\{\.(\w+)\}\}`)
+// VariablePattern 匹配模板变量的正则表达式，花括号内可以有空白，如 {{ .NAME }}
+var VariablePattern = regexp.MustCompile(`\{\{\s*\.(\w+)\s*\}\}`)
+
+// actionPattern 匹配任意模板指令，用于找出渲染后仍未解析的指令
+var actionPattern = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
 
 // ExtractVariables 从模板内容中提取变量名
 func ExtractVariables(template string) []string {
@@ -29,13 +35,40 @@ func ExtractVariables(template string) []string {
 }
 
 // Render 渲染模板内容
+// 未设置的变量保留原样
 func Render(template string, variables map[string]string) string {
-	result := template
-	for key, value := range variables {
-		placeholder := "{{." + key + "}}"
-		result = strings.ReplaceAll(result, placeholder, value)
+	return VariablePattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if value, ok := variables[VariablePattern.FindStringSubmatch(placeholder)[1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// RenderStrict 渲染模板内容，内容引用了未设置的变量时返回错误并列出全部缺失的变量。
+// 值为空字符串的变量视为已设置。条件、函数等指令需要先由 RenderConditionals 和 RenderFuncs 计算，
+// 内容中剩下的其他指令（如 {{printf "%s" .X}}）无法解析，同样返回错误
+func RenderStrict(template string, variables map[string]string) (string, error) {
+	var missing []string
+	for _, name := range ExtractVariables(template) {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
 	}
-	return result
+	if len(missing) > 0 {
+		return "", fmt.Errorf("内容引用了未设置的变量: %s", strings.Join(missing, ", "))
+	}
+
+	var unresolved []string
+	for _, action := range actionPattern.FindAllString(template, -1) {
+		if VariablePattern.FindString(action) != action {
+			unresolved = append(unresolved, action)
+		}
+	}
+	if len(unresolved) > 0 {
+		return "", fmt.Errorf("内容包含无法解析的模板指令: %s", strings.Join(unresolved, ", "))
+	}
+	return Render(template, variables), nil
 }
 
 // ReverseRender 尝试从渲染后的内容反向推导出模板
@@ -184,29 +217,111 @@ func SmartExtract(originalTemplate, modifiedContent string, currentVariables map
 		}
 	}
 
-	// 对于简单的文本替换，我们可以尝试一个更直接的方法
-	// 如果修改只是变量值的变化，而不是模板结构的变化
-	newTemplate := originalTemplate
+	// 将修改后内容中的变量值还原为占位符，保留模板中未修改的行
+	return Unrender(originalTemplate, modifiedContent, currentVariables), updatedVariables, nil
+}
 
-	// 检查是否只是变量值的变化
-	// 通过将修改后的内容与原始模板进行比较
-	// 如果修改后的内容可以通过替换变量值得到，那么模板结构没有变化
-	for _, varName := range templateVars {
-		placeholder := "{{." + varName + "}}"
-		// 如果占位符仍然在修改后的内容中，说明模板结构没有变化
-		if strings.Contains(modifiedContent, placeholder) {
-			// 模板结构没有变化，只是变量值可能变了
-			// 保持模板不变
-		} else {
-			// 占位符被替换成了具体值，需要尝试恢复
-			// 这是一个复杂的问题，暂时保持模板不变
-			fmt.Printf("警告: 变量 %s 的占位符可能被替换了\n", varName)
+// Unrender 将按 variables 渲染后又被修改的内容还原为模板
+//
+// 与渲染结果相同的行使用模板中对应的行，保留其中的占位符；修改和新增的行中出现的变量值
+// 替换回 {{.变量}} 占位符。模板中的行与渲染结果无法逐行对应（变量值包含换行）时，
+// 对整个内容替换变量值。只替换模板中使用的、值不为空的变量。
+func Unrender(template, modified string, variables map[string]string) string {
+	replacer := newValueReplacer(ExtractVariables(template), variables)
+	if replacer == nil {
+		return modified
+	}
+
+	templateLines := strings.Split(strings.TrimSuffix(template, "\n"), "\n")
+	renderedLines := strings.Split(strings.TrimSuffix(Render(template, variables), "\n"), "\n")
+	if len(templateLines) != len(renderedLines) {
+		return replacer.replace(modified)
+	}
+
+	var result []string
+	i := 0
+	for _, line := range diff.Lines(strings.Join(renderedLines, "\n"), modified) {
+		switch line.Kind {
+		case diff.OpEqual:
+			result = append(result, templateLines[i])
+			i++
+		case diff.OpDelete:
+			i++
+		case diff.OpInsert:
+			result = append(result, replacer.replace(line.Text))
 		}
 	}
+	unrendered := strings.Join(result, "\n")
+	if strings.HasSuffix(modified, "\n") {
+		unrendered += "\n"
+	}
+	return unrendered
+}
+
+// valueReplacer 将变量值替换为占位符
+type valueReplacer struct {
+	patterns     []*regexp.Regexp
+	placeholders []string
+}
+
+// newValueReplacer 为值不为空的变量创建替换器，值较长的变量优先，避免较短的值替换掉较长值的一部分；
+// 值以字母或数字开头或结尾时只匹配完整的词。没有可替换的变量时返回nil
+func newValueReplacer(names []string, variables map[string]string) *valueReplacer {
+	var candidates []string
+	for _, name := range names {
+		if strings.TrimSpace(variables[name]) != "" {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(variables[candidates[i]]) > len(variables[candidates[j]])
+	})
+
+	r := &valueReplacer{}
+	for _, name := range candidates {
+		value := variables[name]
+		pattern := regexp.QuoteMeta(value)
+		if isWordByte(value[0]) {
+			pattern = `\b` + pattern
+		}
+		if isWordByte(value[len(value)-1]) {
+			pattern += `\b`
+		}
+		r.patterns = append(r.patterns, regexp.MustCompile(pattern))
+		r.placeholders = append(r.placeholders, "{{."+name+"}}")
+	}
+	return r
+}
+
+// replace 替换一段文本中的变量值，已替换出的占位符不再参与后续替换
+func (r *valueReplacer) replace(text string) string {
+	parts := []string{text}
+	for i, pattern := range r.patterns {
+		var next []string
+		for j, part := range parts {
+			// 奇数位置为已替换出的占位符
+			if j%2 == 1 {
+				next = append(next, part)
+				continue
+			}
+			last := 0
+			for _, loc := range pattern.FindAllStringIndex(part, -1) {
+				next = append(next, part[last:loc[0]], r.placeholders[i])
+				last = loc[1]
+			}
+			next = append(next, part[last:])
+		}
+		parts = next
+	}
+	return strings.Join(parts, "")
+}
 
-	// 返回原始模板和当前变量（暂时不更新变量值）
-	// 在实际使用中，用户应该手动更新变量值
-	return newTemplate, updatedVariables, nil
+// isWordByte 检查字节是否为 \b 使用的单词字符
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
 }
 
 // lineDiff 表示一行的差异
@@ -1,6 +1,9 @@
 package template
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -91,6 +94,60 @@ func TestRender(t *testing.T) {
 	}
 }
 
+func TestRenderStrict(t *testing.T) {
+	variables := map[string]string{"NAME": "demo", "EMPTY": ""}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "变量",
+			template: "{{.NAME}}:{{.EMPTY}}",
+			want:     "demo:",
+		},
+		{
+			name:     "带空白的变量",
+			template: "{{ .NAME }}-{{.NAME }}",
+			want:     "demo-demo",
+		},
+		{
+			name:     "带空白的未设置变量",
+			template: "{{ .MISSING }}",
+			wantErr:  "未设置的变量: MISSING",
+		},
+		{
+			name:     "未知指令",
+			template: `{{.NAME}} {{printf "%s" .NAME}}`,
+			wantErr:  `无法解析的模板指令: {{printf "%s" .NAME}}`,
+		},
+		{
+			name:     "其他指令",
+			template: "{{ range .ITEMS }}{{ end }}",
+			wantErr:  "无法解析的模板指令: {{ range .ITEMS }}, {{ end }}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderStrict(tt.template, variables)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RenderStrict() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderStrict() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderStrict() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSmartExtract_NoChanges(t *testing.T) {
 	template := "Hello {{.name}}"
 	variables := map[string]string{"name": "World"}
@@ -113,3 +170,226 @@ func TestSmartExtract_NoChanges(t *testing.T) {
 		}
 	}
 }
+
+func TestExpandIncludes(t *testing.T) {
+	root := t.TempDir()
+	skillDir := filepath.Join(root, "demo")
+	files := map[string]string{
+		"demo/parts/intro.md":   "你是 {{.LANGUAGE}} 专家\n",
+		"demo/parts/rules.md":   "## 规则\n{{include \"parts/item.md\"}}\n",
+		"demo/parts/item.md":    "- 保持简洁\r\n",
+		"demo/parts/loop.md":    "{{include \"parts/loop.md\"}}",
+		"_partials/shared.md":   "共享片段\n",
+		"demo/parts/missing.md": "{{include \"parts/none.md\"}}",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outside := filepath.Join(filepath.Dir(root), "outside.md")
+
+	tests := []struct {
+		name    string
+		content string
+		root    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "嵌套包含并保留变量",
+			content: "{{include \"parts/intro.md\"}}\n\n{{ include \"parts/rules.md\" }}\n",
+			want:    "你是 {{.LANGUAGE}} 专家\n\n## 规则\n- 保持简洁\n",
+		},
+		{
+			name:    "没有包含指令",
+			content: "Hello {{.name}}",
+			want:    "Hello {{.name}}",
+		},
+		{
+			name:    "共享片段",
+			content: "{{include \"../_partials/shared.md\"}}",
+			root:    root,
+			want:    "共享片段",
+		},
+		{
+			name:    "默认只能包含技能目录中的文件",
+			content: "{{include \"../_partials/shared.md\"}}",
+			wantErr: true,
+		},
+		{
+			name:    "不能包含技能目录之外的文件",
+			content: "{{include \"../../" + filepath.Base(outside) + "\"}}",
+			root:    root,
+			wantErr: true,
+		},
+		{
+			name:    "循环包含",
+			content: "{{include \"parts/loop.md\"}}",
+			wantErr: true,
+		},
+		{
+			name:    "文件不存在",
+			content: "{{include \"parts/missing.md\"}}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandIncludes(tt.content, skillDir, tt.root)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ExpandIncludes() expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandIncludes() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandIncludes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderConditionals(t *testing.T) {
+	data := WithContext(map[string]string{"STRICT": "true", "LANGUAGE": "go"}, "cursor", "project")
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "单独占行的指令整行删除",
+			content: "开始\n{{if eq .Target \"cursor\"}}\nCursor 规则\n{{else}}\n其他工具\n{{end}}\n结束\n",
+			want:    "开始\nCursor 规则\n结束\n",
+		},
+		{
+			name:    "行内条件",
+			content: "使用 {{if ne .Target \"cursor\"}}skills{{else}}rules{{end}} 目录",
+			want:    "使用 rules 目录",
+		},
+		{
+			name:    "else if 与多个候选值",
+			content: "{{if eq .Target \"claude_code\"}}A{{else if eq .Target \"open_code\" \"cursor\"}}B{{else}}C{{end}}",
+			want:    "B",
+		},
+		{
+			name:    "嵌套条件与布尔变量",
+			content: "{{if .STRICT}}严格{{if not .MISSING}}，无缺失{{end}}{{end}}{{if eq .Mode \"global\"}}全局{{end}}",
+			want:    "严格，无缺失",
+		},
+		{
+			name:    "保留变量占位符",
+			content: "{{if eq .LANGUAGE \"go\"}}{{.LANGUAGE}} 规范{{end}}",
+			want:    "{{.LANGUAGE}} 规范",
+		},
+		{
+			name:    "缺少 end",
+			content: "{{if .STRICT}}严格",
+			wantErr: true,
+		},
+		{
+			name:    "多余的 end",
+			content: "内容{{end}}",
+			wantErr: true,
+		},
+		{
+			name:    "else 之后不能再有分支",
+			content: "{{if .STRICT}}a{{else}}b{{else}}c{{end}}",
+			wantErr: true,
+		},
+		{
+			name:    "无效条件",
+			content: "{{if gt .COUNT \"1\"}}a{{end}}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderConditionals(tt.content, data)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("RenderConditionals() expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderConditionals() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderConditionals() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnrender(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		variables map[string]string
+		modified  string
+		want      string
+	}{
+		{
+			name:      "unchanged lines keep placeholders",
+			template:  "# {{.project}}\n\nUse {{.lang}} for {{.project}}.\nRun tests.\n",
+			variables: map[string]string{"project": "demo", "lang": "Go"},
+			modified:  "# demo\n\nUse Go for demo.\nRun tests with -race.\n",
+			want:      "# {{.project}}\n\nUse {{.lang}} for {{.project}}.\nRun tests with -race.\n",
+		},
+		{
+			name:      "values in changed lines",
+			template:  "Project: {{.project}}\n",
+			variables: map[string]string{"project": "demo"},
+			modified:  "Project: demo\nBuild demo with make, not demolition.\n",
+			want:      "Project: {{.project}}\nBuild {{.project}} with make, not demolition.\n",
+		},
+		{
+			name:      "longer values first",
+			template:  "{{.name}} / {{.full}}\n",
+			variables: map[string]string{"name": "api", "full": "api-server"},
+			modified:  "api-server and api\n",
+			want:      "{{.full}} and {{.name}}\n",
+		},
+		{
+			name:      "placeholder not reused as value",
+			template:  "{{.a}} {{.b}}\n",
+			variables: map[string]string{"a": "x", "b": "{{.a}}"},
+			modified:  "x {{.a}} x\n",
+			want:      "{{.a}} {{.b}} {{.a}}\n",
+		},
+		{
+			name:      "multi-line value",
+			template:  "Steps:\n{{.steps}}\nDone {{.project}}\n",
+			variables: map[string]string{"steps": "a\nb", "project": "demo"},
+			modified:  "Steps:\na\nb\nDone demo!\n",
+			want:      "Steps:\n{{.steps}}\nDone {{.project}}!\n",
+		},
+		{
+			name:      "no variables",
+			template:  "plain\n",
+			variables: map[string]string{"project": "demo"},
+			modified:  "plain demo\n",
+			want:      "plain demo\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unrender(tt.template, tt.modified, tt.variables); got != tt.want {
+				t.Errorf("Unrender() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
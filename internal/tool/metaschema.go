@@ -0,0 +1,269 @@
+package tool
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SchemaDialect ToolSpec.InputSchema 使用的 JSON Schema 版本
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaTypeNames JSON Schema 的基本类型
+var schemaTypeNames = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true,
+	"number": true, "string": true, "integer": true,
+}
+
+// 按取值形式分组的关键字，参见 JSON Schema draft 2020-12 元模式
+var (
+	subschemaKeywords = []string{
+		"additionalProperties", "items", "contains", "not", "if", "then", "else",
+		"propertyNames", "unevaluatedProperties", "unevaluatedItems",
+	}
+	schemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+	schemaMapKeywords   = []string{"properties", "patternProperties", "$defs", "dependentSchemas"}
+	numberKeywords      = []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum"}
+	countKeywords       = []string{
+		"minLength", "maxLength", "minItems", "maxItems",
+		"minProperties", "maxProperties", "minContains", "maxContains",
+	}
+	stringKeywords = []string{
+		"$id", "$ref", "$anchor", "$dynamicRef", "$dynamicAnchor", "$comment",
+		"title", "description", "format", "contentEncoding", "contentMediaType",
+	}
+	boolKeywords = []string{"uniqueItems", "deprecated", "readOnly", "writeOnly"}
+)
+
+// ValidateSchema 检查 ToolSpec.InputSchema 本身是否为有效的 JSON Schema (draft 2020-12)
+//
+// 按元模式检查各关键字的取值形式并递归检查子模式；Claude 的工具输入总是对象，
+// 因此顶层的 type 必须为 object。返回的错误列出全部问题。
+func ValidateSchema(schema map[string]interface{}) error {
+	if len(schema) == 0 {
+		return fmt.Errorf("input_schema 不能为空")
+	}
+
+	var problems []string
+	if dialect, ok := schema["$schema"]; ok {
+		if s, isString := dialect.(string); !isString || strings.TrimSuffix(s, "#") != SchemaDialect {
+			problems = append(problems, fmt.Sprintf("input_schema.$schema: 只支持 %s，实际为 %v", SchemaDialect, dialect))
+		}
+	}
+	if types := schemaTypes(schema["type"]); len(types) != 1 || types[0] != "object" {
+		problems = append(problems, "input_schema.type: 工具输入的类型必须为 object")
+	}
+	checkSchema(schema, "input_schema", &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("input_schema 不是有效的 JSON Schema:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// checkSubschema 检查子模式，2020-12 中子模式可以是对象或布尔值
+func checkSubschema(value interface{}, path string, problems *[]string) {
+	switch v := value.(type) {
+	case bool:
+	case map[string]interface{}:
+		checkSchema(v, path, problems)
+	default:
+		*problems = append(*problems, fmt.Sprintf("%s: 应为 schema 对象或布尔值，实际为 %s", path, schemaValueType(value)))
+	}
+}
+
+// checkSchema 检查 schema 对象的各关键字，问题追加到 problems
+func checkSchema(schema map[string]interface{}, path string, problems *[]string) {
+	report := func(keyword, format string, args ...interface{}) {
+		*problems = append(*problems, path+"."+keyword+": "+fmt.Sprintf(format, args...))
+	}
+
+	if value, ok := schema["type"]; ok {
+		checkTypeKeyword(value, func(format string, args ...interface{}) { report("type", format, args...) })
+	}
+
+	for _, keyword := range subschemaKeywords {
+		value, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+		// draft-07 及以前的元组写法在 2020-12 中改为 prefixItems
+		if _, isArray := value.([]interface{}); isArray && keyword == "items" {
+			report(keyword, "draft 2020-12 中 items 必须是单个 schema，元组请使用 prefixItems")
+			continue
+		}
+		checkSubschema(value, path+"."+keyword, problems)
+	}
+
+	for _, keyword := range schemaArrayKeywords {
+		value, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+		list, isArray := value.([]interface{})
+		if !isArray || len(list) == 0 {
+			report(keyword, "应为非空的 schema 数组")
+			continue
+		}
+		for i, item := range list {
+			checkSubschema(item, fmt.Sprintf("%s.%s[%d]", path, keyword, i), problems)
+		}
+	}
+
+	for _, keyword := range schemaMapKeywords {
+		value, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+		entries, isMap := value.(map[string]interface{})
+		if !isMap {
+			report(keyword, "应为对象，实际为 %s", schemaValueType(value))
+			continue
+		}
+		for _, name := range sortedKeys(entries) {
+			if keyword == "patternProperties" {
+				if _, err := regexp.Compile(name); err != nil {
+					report(keyword, "无效的正则表达式 %q: %v", name, err)
+				}
+			}
+			checkSubschema(entries[name], path+"."+keyword+"."+name, problems)
+		}
+	}
+
+	for _, keyword := range numberKeywords {
+		if value, ok := schema[keyword]; ok {
+			if _, isNumber := toFloat(value); !isNumber {
+				report(keyword, "应为数字，实际为 %s", schemaValueType(value))
+			}
+		}
+	}
+	if value, ok := schema["multipleOf"]; ok {
+		if n, isNumber := toFloat(value); !isNumber || n <= 0 {
+			report("multipleOf", "应为大于0的数字，实际为 %v", value)
+		}
+	}
+	for _, keyword := range countKeywords {
+		if value, ok := schema[keyword]; ok {
+			if n, isNumber := toFloat(value); !isNumber || n < 0 || n != math.Trunc(n) {
+				report(keyword, "应为非负整数，实际为 %v", value)
+			}
+		}
+	}
+	for _, keyword := range stringKeywords {
+		if value, ok := schema[keyword]; ok {
+			if _, isString := value.(string); !isString {
+				report(keyword, "应为字符串，实际为 %s", schemaValueType(value))
+			}
+		}
+	}
+	for _, keyword := range boolKeywords {
+		if value, ok := schema[keyword]; ok {
+			if _, isBool := value.(bool); !isBool {
+				report(keyword, "应为布尔值，实际为 %s", schemaValueType(value))
+			}
+		}
+	}
+
+	if value, ok := schema["pattern"]; ok {
+		if pattern, isString := value.(string); !isString {
+			report("pattern", "应为字符串，实际为 %s", schemaValueType(value))
+		} else if _, err := regexp.Compile(pattern); err != nil {
+			report("pattern", "无效的正则表达式 %q: %v", pattern, err)
+		}
+	}
+	if value, ok := schema["enum"]; ok {
+		if _, isArray := value.([]interface{}); !isArray {
+			report("enum", "应为数组，实际为 %s", schemaValueType(value))
+		}
+	}
+	if value, ok := schema["examples"]; ok {
+		if _, isArray := value.([]interface{}); !isArray {
+			report("examples", "应为数组，实际为 %s", schemaValueType(value))
+		}
+	}
+	if value, ok := schema["required"]; ok {
+		checkStringArray(value, func(format string, args ...interface{}) { report("required", format, args...) })
+	}
+	if value, ok := schema["dependentRequired"]; ok {
+		entries, isMap := value.(map[string]interface{})
+		if !isMap {
+			report("dependentRequired", "应为对象，实际为 %s", schemaValueType(value))
+		}
+		for _, name := range sortedKeys(entries) {
+			checkStringArray(entries[name], func(format string, args ...interface{}) {
+				report("dependentRequired."+name, format, args...)
+			})
+		}
+	}
+}
+
+// checkTypeKeyword 检查 type 关键字：类型名称或不重复的类型名称数组
+func checkTypeKeyword(value interface{}, report func(format string, args ...interface{})) {
+	var names []interface{}
+	switch v := value.(type) {
+	case string:
+		names = []interface{}{v}
+	case []interface{}:
+		if len(v) == 0 {
+			report("类型数组不能为空")
+			return
+		}
+		names = v
+	default:
+		report("应为字符串或字符串数组，实际为 %s", schemaValueType(value))
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		s, ok := name.(string)
+		if !ok || !schemaTypeNames[s] {
+			report("未知的类型 %v", name)
+			continue
+		}
+		if seen[s] {
+			report("重复的类型 %s", s)
+		}
+		seen[s] = true
+	}
+}
+
+// checkStringArray 检查不重复的字符串数组，用于 required 和 dependentRequired
+func checkStringArray(value interface{}, report func(format string, args ...interface{})) {
+	list, ok := value.([]interface{})
+	if !ok {
+		report("应为字符串数组，实际为 %s", schemaValueType(value))
+		return
+	}
+	seen := make(map[string]bool)
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			report("应为字符串，实际为 %v", item)
+			continue
+		}
+		if seen[s] {
+			report("重复的字段 %s", s)
+		}
+		seen[s] = true
+	}
+}
+
+// schemaValueType 返回 schema 中值的 JSON 类型名称，schema 可能来自 YAML，数字可能是整数类型
+func schemaValueType(value interface{}) string {
+	if _, ok := toFloat(value); ok {
+		return "number"
+	}
+	return jsonType(value)
+}
+
+// sortedKeys 返回排序后的键，保证问题列表的顺序稳定
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+package tool
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr []string
+	}{
+		{"valid", `
+$schema: https://json-schema.org/draft/2020-12/schema
+type: object
+required: [path]
+additionalProperties: false
+properties:
+  path: {type: string, minLength: 1, pattern: "^[a-z./]+$"}
+  level: {type: [integer, "null"], minimum: 1, maximum: 3}
+  tags: {type: array, items: {type: string}, uniqueItems: true}
+  pair: {type: array, prefixItems: [{type: string}, true]}
+  mode: {anyOf: [{const: fast}, {const: slow}]}
+$defs:
+  name: {type: string}
+`, nil},
+		{"other dialect", "$schema: http://json-schema.org/draft-07/schema#\ntype: object", []string{"input_schema.$schema: 只支持"}},
+		{"not object", "type: string", []string{"input_schema.type: 工具输入的类型必须为 object"}},
+		{"unknown type", "type: object\nproperties:\n  a: {type: str}", []string{"input_schema.properties.a.type: 未知的类型 str"}},
+		{"properties not object", "type: object\nproperties: [a]", []string{"input_schema.properties: 应为对象，实际为 array"}},
+		{"required", "type: object\nrequired: [a, 1, a]", []string{"required: 应为字符串，实际为 1", "required: 重复的字段 a"}},
+		{"tuple items", "type: object\nproperties:\n  p: {type: array, items: [{type: string}]}", []string{"input_schema.properties.p.items: draft 2020-12 中 items 必须是单个 schema"}},
+		{"counts", "type: object\nproperties:\n  s: {type: string, minLength: -1, maxLength: 1.5}", []string{"minLength: 应为非负整数", "maxLength: 应为非负整数"}},
+		{"bad pattern", "type: object\nproperties:\n  s: {pattern: \"(\"}", []string{"input_schema.properties.s.pattern: 无效的正则表达式"}},
+		{"nested", "type: object\nproperties:\n  o:\n    type: object\n    properties:\n      x: {minimum: low}", []string{"input_schema.properties.o.properties.x.minimum: 应为数字，实际为 string"}},
+		{"empty anyOf", "type: object\nanyOf: []", []string{"input_schema.anyOf: 应为非空的 schema 数组"}},
+		{"subschema type", "type: object\nadditionalProperties: yes-please", []string{"input_schema.additionalProperties: 应为 schema 对象或布尔值"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema map[string]interface{}
+			if err := yaml.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("解析schema失败: %v", err)
+			}
+			err := ValidateSchema(schema)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateSchema() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateSchema() should return error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %q, want containing %q", err.Error(), want)
+				}
+			}
+		})
+	}
+
+	t.Run("empty schema", func(t *testing.T) {
+		if err := ValidateSchema(nil); err == nil {
+			t.Error("ValidateSchema() should reject empty schema")
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package tool
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ValidateInput 按 ToolSpec.InputSchema 校验工具输入
+//
+// 支持 JSON Schema 的常用子集：type、enum、properties、required、
+// additionalProperties、items、minimum/maximum、minLength/maxLength 和 minItems/maxItems。
+// input 为 encoding/json 解码得到的值。返回的错误列出全部不符合的字段。
+func ValidateInput(schema map[string]interface{}, input interface{}) error {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	var problems []string
+	validateValue(schema, input, "input", &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("输入不符合 input_schema:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// validateValue 递归校验单个值，问题追加到 problems
+func validateValue(schema map[string]interface{}, value interface{}, path string, problems *[]string) {
+	report := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if matchesType(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			report("类型应为 %s，实际为 %s", strings.Join(types, " | "), jsonType(value))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, candidate := range enum {
+			if equalValue(candidate, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report("值 %v 不在可选值 %v 中", value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateObject(schema, v, path, problems)
+	case []interface{}:
+		if min, ok := toFloat(schema["minItems"]); ok && float64(len(v)) < min {
+			report("元素数量不能少于 %v", min)
+		}
+		if max, ok := toFloat(schema["maxItems"]); ok && float64(len(v)) > max {
+			report("元素数量不能多于 %v", max)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if min, ok := toFloat(schema["minLength"]); ok && length < min {
+			report("长度不能小于 %v", min)
+		}
+		if max, ok := toFloat(schema["maxLength"]); ok && length > max {
+			report("长度不能大于 %v", max)
+		}
+	case float64:
+		if min, ok := toFloat(schema["minimum"]); ok && v < min {
+			report("不能小于 %v", min)
+		}
+		if max, ok := toFloat(schema["maximum"]); ok && v > max {
+			report("不能大于 %v", max)
+		}
+	}
+}
+
+// validateObject 校验对象的必需字段和各属性
+func validateObject(schema map[string]interface{}, obj map[string]interface{}, path string, problems *[]string) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key := fmt.Sprint(name)
+			if _, exists := obj[key]; !exists {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: 缺少必需字段", path, key))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := path + "." + key
+		if property, ok := properties[key].(map[string]interface{}); ok {
+			validateValue(property, obj[key], fieldPath, problems)
+			continue
+		}
+		if _, declared := properties[key]; declared {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*problems = append(*problems, fieldPath+": 不允许的字段")
+			}
+		case map[string]interface{}:
+			validateValue(additional, obj[key], fieldPath, problems)
+		}
+	}
+}
+
+// schemaTypes 解析 type 关键字，支持字符串和字符串数组
+func schemaTypes(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		types := make([]string, 0, len(v))
+		for _, t := range v {
+			types = append(types, fmt.Sprint(t))
+		}
+		return types
+	}
+	return nil
+}
+
+// matchesType 判断值是否为 JSON Schema 类型
+func matchesType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonType(value) == schemaType
+	}
+}
+
+// jsonType 返回值的 JSON 类型名称
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// equalValue 比较 schema 中的值与输入值。schema 来自 YAML，数字可能是整数类型
+func equalValue(expected, actual interface{}) bool {
+	if a, ok := toFloat(expected); ok {
+		b, ok := actual.(float64)
+		return ok && a == b
+	}
+	return fmt.Sprintf("%T:%v", expected, expected) == fmt.Sprintf("%T:%v", actual, actual)
+}
+
+// toFloat 将 YAML 或 JSON 解码得到的数字转换为 float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
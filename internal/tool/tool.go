@@ -0,0 +1,81 @@
+// Package tool 运行 tool 模式的 Claude 技能
+package tool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skill-hub/pkg/spec"
+)
+
+// ModeTool Claude 技能的 tool 模式
+const ModeTool = "tool"
+
+// runtimes 运行时到解释器命令的映射，命令为空表示直接执行入口文件
+var runtimes = map[string][]string{
+	"python":  {"python3"},
+	"python3": {"python3"},
+	"node":    {"node"},
+	"nodejs":  {"node"},
+	"deno":    {"deno", "run"},
+	"bash":    {"bash"},
+	"sh":      {"sh"},
+	"go":      {"go", "run"},
+	"binary":  nil,
+}
+
+// Runtimes 返回支持的运行时名称
+func Runtimes() []string {
+	names := make([]string, 0, len(runtimes))
+	for name := range runtimes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Command 构造运行技能入口的命令
+//
+// 入口路径相对于技能目录，不能指向技能目录之外。未声明运行时时直接执行入口文件。
+func Command(ctx context.Context, cfg *spec.ClaudeConfig, skillDir string) (*exec.Cmd, error) {
+	if cfg == nil || cfg.Mode != ModeTool {
+		return nil, fmt.Errorf("技能不是 tool 模式，请在 SKILL.md 中设置 claude.mode: tool")
+	}
+	if cfg.Entrypoint == "" {
+		return nil, fmt.Errorf("技能未声明 claude.entrypoint")
+	}
+
+	entrypoint := filepath.Clean(filepath.FromSlash(cfg.Entrypoint))
+	if filepath.IsAbs(entrypoint) || entrypoint == ".." || strings.HasPrefix(entrypoint, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("入口 %s 必须是技能目录内的相对路径", cfg.Entrypoint)
+	}
+	entrypoint = filepath.Join(skillDir, entrypoint)
+	if info, err := os.Stat(entrypoint); err != nil {
+		return nil, fmt.Errorf("入口文件不存在: %s", entrypoint)
+	} else if info.IsDir() {
+		return nil, fmt.Errorf("入口 %s 是目录", entrypoint)
+	}
+
+	runtime := strings.ToLower(strings.TrimSpace(cfg.Runtime))
+	if runtime == "" {
+		runtime = "binary"
+	}
+	interpreter, ok := runtimes[runtime]
+	if !ok {
+		return nil, fmt.Errorf("不支持的运行时: %s，可用选项: %s", cfg.Runtime, strings.Join(Runtimes(), ", "))
+	}
+
+	if len(interpreter) == 0 {
+		return exec.CommandContext(ctx, entrypoint), nil
+	}
+	if _, err := exec.LookPath(interpreter[0]); err != nil {
+		return nil, fmt.Errorf("未找到运行时 %s 的命令 %s", runtime, interpreter[0])
+	}
+	args := append(append([]string{}, interpreter[1:]...), entrypoint)
+	return exec.CommandContext(ctx, interpreter[0], args...), nil
+}
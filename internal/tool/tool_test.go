@@ -0,0 +1,145 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"skill-hub/pkg/spec"
+)
+
+func TestValidateInput(t *testing.T) {
+	// schema 与 SKILL.md 中一样从YAML解析，数字为 int 类型
+	var schema map[string]interface{}
+	err := yaml.Unmarshal([]byte(`
+type: object
+required: [path]
+additionalProperties: false
+properties:
+  path:
+    type: string
+    minLength: 1
+  level:
+    type: integer
+    minimum: 1
+    maximum: 3
+  format:
+    enum: [text, json]
+  tags:
+    type: array
+    items:
+      type: string
+`), &schema)
+	if err != nil {
+		t.Fatalf("解析schema失败: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr []string
+	}{
+		{"valid", `{"path": "main.go", "level": 2, "format": "json", "tags": ["a"]}`, nil},
+		{"missing required", `{"level": 1}`, []string{"input.path: 缺少必需字段"}},
+		{"wrong type", `{"path": 1}`, []string{"input.path: 类型应为 string"}},
+		{"not integer", `{"path": "a", "level": 1.5}`, []string{"input.level: 类型应为 integer"}},
+		{"out of range", `{"path": "a", "level": 5}`, []string{"input.level: 不能大于 3"}},
+		{"enum", `{"path": "a", "format": "xml"}`, []string{"input.format: 值 xml 不在可选值"}},
+		{"array items", `{"path": "a", "tags": ["a", 2]}`, []string{"input.tags[1]: 类型应为 string"}},
+		{"additional property", `{"path": "a", "extra": true}`, []string{"input.extra: 不允许的字段"}},
+		{"not object", `[]`, []string{"input: 类型应为 object，实际为 array"}},
+		{"multiple problems", `{"path": "", "level": 0}`, []string{"input.path: 长度不能小于 1", "input.level: 不能小于 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input interface{}
+			if err := json.Unmarshal([]byte(tt.input), &input); err != nil {
+				t.Fatalf("解析输入失败: %v", err)
+			}
+			err := ValidateInput(schema, input)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateInput() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateInput() should return error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %q, want containing %q", err.Error(), want)
+				}
+			}
+		})
+	}
+
+	t.Run("empty schema", func(t *testing.T) {
+		if err := ValidateInput(nil, "anything"); err != nil {
+			t.Errorf("ValidateInput() error = %v", err)
+		}
+	})
+}
+
+func TestCommand(t *testing.T) {
+	skillDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(skillDir, "scripts", "run.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncat\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("runtime", func(t *testing.T) {
+		cmd, err := Command(context.Background(), &spec.ClaudeConfig{Mode: ModeTool, Runtime: "sh", Entrypoint: "scripts/run.sh"}, skillDir)
+		if err != nil {
+			t.Fatalf("Command() error = %v", err)
+		}
+		cmd.Stdin = strings.NewReader(`{"ok":true}`)
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("运行失败: %v", err)
+		}
+		if string(out) != `{"ok":true}` {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("direct execution", func(t *testing.T) {
+		cmd, err := Command(context.Background(), &spec.ClaudeConfig{Mode: ModeTool, Entrypoint: "scripts/run.sh"}, skillDir)
+		if err != nil {
+			t.Fatalf("Command() error = %v", err)
+		}
+		if cmd.Path != script {
+			t.Errorf("cmd.Path = %q, want %q", cmd.Path, script)
+		}
+	})
+
+	errorCases := []struct {
+		name string
+		cfg  *spec.ClaudeConfig
+		want string
+	}{
+		{"nil config", nil, "不是 tool 模式"},
+		{"instruction mode", &spec.ClaudeConfig{Mode: "instruction", Entrypoint: "scripts/run.sh"}, "不是 tool 模式"},
+		{"missing entrypoint", &spec.ClaudeConfig{Mode: ModeTool}, "未声明 claude.entrypoint"},
+		{"escape skill dir", &spec.ClaudeConfig{Mode: ModeTool, Entrypoint: "../run.sh"}, "相对路径"},
+		{"absolute entrypoint", &spec.ClaudeConfig{Mode: ModeTool, Entrypoint: script}, "相对路径"},
+		{"entrypoint not found", &spec.ClaudeConfig{Mode: ModeTool, Entrypoint: "missing.sh"}, "入口文件不存在"},
+		{"unknown runtime", &spec.ClaudeConfig{Mode: ModeTool, Runtime: "cobol", Entrypoint: "scripts/run.sh"}, "不支持的运行时"},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Command(context.Background(), tt.cfg, skillDir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Command() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
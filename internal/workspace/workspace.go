@@ -0,0 +1,235 @@
+// Package workspace 识别包含多个项目的工作区（monorepo）及其成员项目
+package workspace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 工作区类型
+const (
+	KindSkillHub = "skill-hub"    // .skill-hub/workspace.yaml
+	KindGoWork   = "go.work"      // go.work 的 use 指令
+	KindNPM      = "package.json" // package.json 的 workspaces 字段
+)
+
+// MarkerFile 自定义工作区标记文件，相对于工作区根目录
+const MarkerFile = ".skill-hub/workspace.yaml"
+
+// Workspace 工作区根目录及成员项目
+type Workspace struct {
+	Root    string   `json:"root"`
+	Kind    string   `json:"kind"`
+	Members []string `json:"members"` // 成员项目的绝对路径，已排序
+}
+
+// marker 自定义工作区标记文件的内容
+type marker struct {
+	Members []string `yaml:"members"` // 成员目录，相对于工作区根目录，支持 filepath.Match 通配符
+}
+
+// Detect 从 dir 向上查找工作区根目录，没有找到时返回 nil
+//
+// 每一级目录依次检查 .skill-hub/workspace.yaml、go.work 和带 workspaces 字段的 package.json，
+// 使用最先找到的一个。
+func Detect(dir string) (*Workspace, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+	}
+	// 与项目状态使用相同的规范路径
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+	for current := absDir; ; {
+		ws, err := Load(current)
+		if err != nil || ws != nil {
+			return ws, err
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil, nil
+		}
+		current = parent
+	}
+}
+
+// Load 读取 root 目录的工作区定义，root 不是工作区根目录时返回 nil
+func Load(root string) (*Workspace, error) {
+	patterns, kind, err := memberPatterns(root)
+	if err != nil || kind == "" {
+		return nil, err
+	}
+
+	members, err := expandMembers(root, patterns, kind)
+	if err != nil {
+		return nil, err
+	}
+	return &Workspace{Root: root, Kind: kind, Members: members}, nil
+}
+
+// memberPatterns 读取工作区定义中的成员目录模式，不是工作区根目录时 kind 为空
+func memberPatterns(root string) ([]string, string, error) {
+	if data, err := os.ReadFile(filepath.Join(root, MarkerFile)); err == nil {
+		var m marker
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, "", fmt.Errorf("解析 %s 失败: %w", filepath.Join(root, MarkerFile), err)
+		}
+		return m.Members, KindSkillHub, nil
+	}
+
+	if data, err := os.ReadFile(filepath.Join(root, "go.work")); err == nil {
+		return parseGoWork(data), KindGoWork, nil
+	}
+
+	if data, err := os.ReadFile(filepath.Join(root, "package.json")); err == nil {
+		patterns, err := parsePackageWorkspaces(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("解析 %s 失败: %w", filepath.Join(root, "package.json"), err)
+		}
+		if patterns != nil {
+			return patterns, KindNPM, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// parseGoWork 解析 go.work 中 use 指令列出的目录，支持单行和块形式
+func parseGoWork(data []byte) []string {
+	var dirs []string
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			dirs = append(dirs, unquote(line))
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			dirs = append(dirs, unquote(strings.TrimSpace(strings.TrimPrefix(line, "use "))))
+		}
+	}
+	return dirs
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, "\"`")
+}
+
+// parsePackageWorkspaces 解析 package.json 的 workspaces 字段，支持数组和 {"packages": [...]} 两种形式
+//
+// 没有 workspaces 字段时返回 nil。
+func parsePackageWorkspaces(data []byte) ([]string, error) {
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+	if len(pkg.Workspaces) == 0 {
+		return nil, nil
+	}
+
+	var patterns []string
+	if err := json.Unmarshal(pkg.Workspaces, &patterns); err == nil {
+		return append([]string{}, patterns...), nil
+	}
+	var nested struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(pkg.Workspaces, &nested); err != nil {
+		return nil, fmt.Errorf("workspaces 字段格式无效: %w", err)
+	}
+	return append([]string{}, nested.Packages...), nil
+}
+
+// expandMembers 将成员目录模式展开为存在的目录，package.json 工作区只保留包含 package.json 的目录
+func expandMembers(root string, patterns []string, kind string) ([]string, error) {
+	seen := make(map[string]bool)
+	var members []string
+	for _, pattern := range patterns {
+		// npm 的排除模式（!packages/legacy）和递归通配符不支持，排除模式跳过
+		if pattern == "" || strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("无效的成员目录模式 %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || !info.IsDir() {
+				continue
+			}
+			if kind == KindNPM {
+				if _, err := os.Stat(filepath.Join(match, "package.json")); err != nil {
+					continue
+				}
+			}
+			match = filepath.Clean(match)
+			if !seen[match] {
+				seen[match] = true
+				members = append(members, match)
+			}
+		}
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// RelPath 返回成员项目相对于工作区根目录的路径（使用 / 分隔），作为成员变量的键
+func (w *Workspace) RelPath(member string) string {
+	rel, err := filepath.Rel(w.Root, member)
+	if err != nil {
+		return filepath.ToSlash(member)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// MemberOf 返回包含 dir 的成员项目，dir 不在任何成员项目中时返回空字符串
+func (w *Workspace) MemberOf(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	best := ""
+	for _, member := range w.Members {
+		if absDir == member || strings.HasPrefix(absDir, member+string(filepath.Separator)) {
+			// 嵌套的成员取最近的一个
+			if len(member) > len(best) {
+				best = member
+			}
+		}
+	}
+	return best
+}
+
+// Member 按相对路径或绝对路径查找成员项目
+func (w *Workspace) Member(path string) (string, bool) {
+	candidate := path
+	if !filepath.IsAbs(candidate) {
+		candidate = filepath.Join(w.Root, filepath.FromSlash(path))
+	}
+	candidate = filepath.Clean(candidate)
+	for _, member := range w.Members {
+		if member == candidate {
+			return member, true
+		}
+	}
+	return "", false
+}
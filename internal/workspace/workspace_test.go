@@ -0,0 +1,128 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFile 创建文件及其所在目录
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		kind    string
+		members []string
+	}{
+		{
+			name: "go.work",
+			files: map[string]string{
+				"go.work":          "go 1.24\n\nuse (\n\t./svc/api\n\t\"./svc/web\" // web\n)\n\nuse ./tools\n",
+				"svc/api/go.mod":   "module api\n",
+				"svc/web/go.mod":   "module web\n",
+				"tools/go.mod":     "module tools\n",
+				"unused/README.md": "",
+			},
+			kind:    KindGoWork,
+			members: []string{"svc/api", "svc/web", "tools"},
+		},
+		{
+			name: "package.json workspaces",
+			files: map[string]string{
+				"package.json":               `{"name": "root", "workspaces": ["packages/*", "!packages/legacy"]}`,
+				"packages/ui/package.json":   `{"name": "ui"}`,
+				"packages/docs/README.md":    "",
+				"packages/core/package.json": `{"name": "core"}`,
+			},
+			kind:    KindNPM,
+			members: []string{"packages/core", "packages/ui"},
+		},
+		{
+			name: "package.json packages",
+			files: map[string]string{
+				"package.json":           `{"workspaces": {"packages": ["apps/*"]}}`,
+				"apps/site/package.json": `{}`,
+			},
+			kind:    KindNPM,
+			members: []string{"apps/site"},
+		},
+		{
+			name: "marker takes precedence",
+			files: map[string]string{
+				MarkerFile:     "members:\n  - services/*\n",
+				"go.work":      "use ./tools\n",
+				"services/a/x": "",
+				"services/b/x": "",
+				"tools/go.mod": "",
+			},
+			kind:    KindSkillHub,
+			members: []string{"services/a", "services/b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := filepath.EvalSymlinks(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for path, content := range tt.files {
+				writeFile(t, filepath.Join(root, path), content)
+			}
+
+			ws, err := Detect(filepath.Join(root, filepath.FromSlash(tt.members[0])))
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if ws == nil || ws.Root != root || ws.Kind != tt.kind {
+				t.Fatalf("Detect() = %+v", ws)
+			}
+			var members []string
+			for _, member := range ws.Members {
+				members = append(members, ws.RelPath(member))
+			}
+			if !reflect.DeepEqual(members, tt.members) {
+				t.Errorf("members = %v, want %v", members, tt.members)
+			}
+		})
+	}
+}
+
+func TestDetectNoWorkspace(t *testing.T) {
+	root := t.TempDir()
+	// 没有 workspaces 字段的 package.json 不是工作区
+	writeFile(t, filepath.Join(root, "package.json"), `{"name": "app"}`)
+
+	ws, err := Load(root)
+	if err != nil || ws != nil {
+		t.Errorf("Load() = %+v, %v, want nil", ws, err)
+	}
+}
+
+func TestMemberLookup(t *testing.T) {
+	ws := &Workspace{Root: "/repo", Members: []string{"/repo/svc", "/repo/svc/api", "/repo/web"}}
+
+	if got := ws.MemberOf("/repo/svc/api/internal"); got != "/repo/svc/api" {
+		t.Errorf("MemberOf() = %q, want nested member", got)
+	}
+	if got := ws.MemberOf("/repo/docs"); got != "" {
+		t.Errorf("MemberOf() = %q, want empty", got)
+	}
+	if got, ok := ws.Member("web"); !ok || got != "/repo/web" {
+		t.Errorf("Member(web) = %q, %v", got, ok)
+	}
+	if _, ok := ws.Member("docs"); ok {
+		t.Error("Member(docs) should not be found")
+	}
+}
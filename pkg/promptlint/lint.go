@@ -0,0 +1,348 @@
+package promptlint
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxTokens 提示词的默认token上限
+const DefaultMaxTokens = 2000
+
+// 检查项代码
+const (
+	CodeTooLong          = "PROMPT_TOO_LONG"
+	CodeConflict         = "CONFLICTING_INSTRUCTIONS"
+	CodeMissingRole      = "MISSING_ROLE"
+	CodePlaceholder      = "UNREPLACED_PLACEHOLDER"
+	CodeMalformedVar     = "MALFORMED_VARIABLE"
+	CodeDuplicateSection = "DUPLICATE_SECTION"
+)
+
+// Severity 问题级别
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Finding 表示一个提示词质量问题
+type Finding struct {
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Line     int      `json:"line,omitempty"` // 问题所在行（从1开始，0表示整体问题）
+}
+
+// String 返回问题的可读描述
+func (f Finding) String() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("[%s] 第%d行: %s", f.Code, f.Line, f.Message)
+	}
+	return fmt.Sprintf("[%s] %s", f.Code, f.Message)
+}
+
+// Options 检查选项
+type Options struct {
+	MaxTokens int // token上限，0表示使用DefaultMaxTokens
+	// Variables 可用于替换模板占位符的变量；为nil时不检查模板变量是否有值
+	Variables map[string]string
+}
+
+var (
+	templateVarPattern = regexp.MustCompile(`\{\{\.(\w+)\}\}`)
+	anyBracesPattern   = regexp.MustCompile(`\{\{[^}]*\}\}`)
+	actionPattern      = regexp.MustCompile(`^\{\{\s*(if|else|end|include)\b`)
+	todoPattern        = regexp.MustCompile(`(?i)\b(TODO|FIXME|TBD|XXX)\b|\[(INSERT|YOUR)[^\]]*\]|<(INSERT|YOUR)[_ A-Z]*>|待补充|待填写`)
+	rolePattern        = regexp.MustCompile(`(?i)\byou are\b|\byou're\b|\bact as\b|\byour role\b|\bas an? (expert|assistant|senior)|你是|作为一名|作为一个|扮演|你的角色`)
+	headingPattern     = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+
+	positivePattern = regexp.MustCompile(`(?i)\b(always|must)\s+(.+)|(总是|始终|必须|务必|一定要)(.+)`)
+	negativePattern = regexp.MustCompile(`(?i)\b(never|must not|do not|don't)\s+(.+)|(不要|禁止|切勿|不得|不能|绝不)(.+)`)
+)
+
+// Lint 检查单个技能提示词，content可以包含frontmatter
+func Lint(content string, opts Options) []Finding {
+	body, offset := StripFrontmatter(content)
+	lines := strings.Split(body, "\n")
+
+	var findings []Finding
+	findings = append(findings, checkLength(body, opts)...)
+	findings = append(findings, checkRole(body)...)
+	findings = append(findings, checkPlaceholders(lines, offset, opts)...)
+	findings = append(findings, checkConflicts(lines, offset)...)
+	return findings
+}
+
+// EstimateTokens 粗略估算文本的token数：CJK字符按1个token计，其余按4个字符1个token计
+func EstimateTokens(text string) int {
+	cjk, other := 0, 0
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// StripFrontmatter 去除YAML frontmatter，返回正文以及正文之前的行数
+func StripFrontmatter(content string) (string, int) {
+	if !strings.HasPrefix(content, "---\n") {
+		return content, 0
+	}
+	lines := strings.Split(content, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return strings.Join(lines[i+1:], "\n"), i + 1
+		}
+	}
+	return content, 0
+}
+
+// checkLength 检查提示词长度
+func checkLength(body string, opts Options) []Finding {
+	maxTokens := opts.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxTokens
+	}
+	tokens := EstimateTokens(body)
+	if tokens <= maxTokens {
+		return nil
+	}
+	return []Finding{{
+		Code:     CodeTooLong,
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf("提示词约 %d tokens，超过上限 %d，过长的提示词会挤占上下文并稀释关键指令", tokens, maxTokens),
+	}}
+}
+
+// checkRole 检查是否有角色设定
+func checkRole(body string) []Finding {
+	if strings.TrimSpace(body) == "" || rolePattern.MatchString(body) {
+		return nil
+	}
+	return []Finding{{
+		Code:     CodeMissingRole,
+		Severity: SeverityInfo,
+		Message:  "缺少角色设定（如 \"You are ...\" 或 \"你是...\"），明确角色有助于模型稳定输出",
+	}}
+}
+
+// checkPlaceholders 检查未替换的占位符和格式错误的模板变量
+func checkPlaceholders(lines []string, offset int, opts Options) []Finding {
+	var findings []Finding
+	inCode := false
+	for i, line := range lines {
+		lineNum := offset + i + 1
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+
+		for _, braces := range anyBracesPattern.FindAllString(line, -1) {
+			// 条件区块和包含指令不是变量占位符
+			if actionPattern.MatchString(braces) {
+				continue
+			}
+			match := templateVarPattern.FindStringSubmatch(braces)
+			if match == nil {
+				findings = append(findings, Finding{
+					Code:     CodeMalformedVar,
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("%s 不是有效的变量占位符（应为 {{.NAME}} 格式），不会被替换", braces),
+					Line:     lineNum,
+				})
+				continue
+			}
+			if opts.Variables == nil {
+				continue
+			}
+			if value, ok := opts.Variables[match[1]]; !ok || value == "" {
+				findings = append(findings, Finding{
+					Code:     CodePlaceholder,
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("变量 %s 没有值，渲染后将保留占位符", match[1]),
+					Line:     lineNum,
+				})
+			}
+		}
+
+		// 代码块中的TODO通常是示例内容
+		if inCode {
+			continue
+		}
+		if match := todoPattern.FindString(line); match != "" {
+			findings = append(findings, Finding{
+				Code:     CodePlaceholder,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("包含未完成的占位内容 %q", match),
+				Line:     lineNum,
+			})
+		}
+	}
+	return findings
+}
+
+// directive 一条肯定或否定指令
+type directive struct {
+	object string
+	line   int
+}
+
+// checkConflicts 检查相互矛盾的指令（同一内容既被要求又被禁止）
+func checkConflicts(lines []string, offset int) []Finding {
+	positives := map[string]directive{}
+	var negatives []directive
+
+	for i, line := range lines {
+		lineNum := offset + i + 1
+		for _, clause := range splitClauses(line) {
+			if m := negativePattern.FindStringSubmatch(clause); m != nil {
+				negatives = append(negatives, directive{object: normalizeObject(m[2] + m[4]), line: lineNum})
+				continue
+			}
+			if m := positivePattern.FindStringSubmatch(clause); m != nil {
+				obj := normalizeObject(m[2] + m[4])
+				if _, exists := positives[obj]; !exists {
+					positives[obj] = directive{object: obj, line: lineNum}
+				}
+			}
+		}
+	}
+
+	var findings []Finding
+	for _, neg := range negatives {
+		if neg.object == "" {
+			continue
+		}
+		if pos, ok := positives[neg.object]; ok {
+			findings = append(findings, Finding{
+				Code:     CodeConflict,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("与第%d行的指令矛盾: 同时要求和禁止 %q", pos.line, neg.object),
+				Line:     neg.line,
+			})
+		}
+	}
+	return findings
+}
+
+// splitClauses 将一行拆分为句子
+func splitClauses(line string) []string {
+	return strings.FieldsFunc(line, func(r rune) bool {
+		return r == '.' || r == ';' || r == '。' || r == '；' || r == '！' || r == '!'
+	})
+}
+
+// normalizeObject 规范化指令对象以便比较
+func normalizeObject(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// minSectionLength 参与重复检测的章节正文最小长度
+const minSectionLength = 40
+
+// FindDuplicateSections 检查多个技能之间重复的章节，prompts以技能ID为键
+// 返回以技能ID为键的问题列表
+func FindDuplicateSections(prompts map[string]string) map[string][]Finding {
+	type location struct {
+		skillID string
+		title   string
+		line    int
+	}
+	seen := map[[sha256.Size]byte][]location{}
+
+	ids := make([]string, 0, len(prompts))
+	for id := range prompts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		body, offset := StripFrontmatter(prompts[id])
+		for _, sec := range splitSections(body) {
+			normalized := strings.Join(strings.Fields(strings.ToLower(sec.body)), " ")
+			if len(normalized) < minSectionLength {
+				continue
+			}
+			key := sha256.Sum256([]byte(normalized))
+			seen[key] = append(seen[key], location{skillID: id, title: sec.title, line: offset + sec.line})
+		}
+	}
+
+	result := map[string][]Finding{}
+	for _, locs := range seen {
+		for _, loc := range locs {
+			var others []string
+			for _, other := range locs {
+				if other.skillID != loc.skillID {
+					others = append(others, other.skillID)
+				}
+			}
+			if len(others) == 0 {
+				continue
+			}
+			result[loc.skillID] = append(result[loc.skillID], Finding{
+				Code:     CodeDuplicateSection,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("章节 %q 与技能 %s 中的内容重复，同时启用会重复占用上下文", loc.title, strings.Join(others, ", ")),
+				Line:     loc.line,
+			})
+		}
+	}
+	for id := range result {
+		sort.Slice(result[id], func(i, j int) bool { return result[id][i].Line < result[id][j].Line })
+	}
+	return result
+}
+
+// section Markdown章节
+type section struct {
+	title string
+	body  string
+	line  int
+}
+
+// splitSections 按标题拆分Markdown正文（忽略代码块中的#）
+func splitSections(body string) []section {
+	var sections []section
+	var current *section
+	var buf []string
+	inCode := false
+
+	flush := func() {
+		if current != nil {
+			current.body = strings.Join(buf, "\n")
+			sections = append(sections, *current)
+		}
+		buf = nil
+	}
+
+	for i, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+		}
+		if !inCode {
+			if m := headingPattern.FindStringSubmatch(line); m != nil {
+				flush()
+				current = &section{title: m[1], line: i + 1}
+				continue
+			}
+		}
+		if current != nil {
+			buf = append(buf, line)
+		}
+	}
+	flush()
+	return sections
+}
@@ -0,0 +1,153 @@
+package promptlint
+
+import (
+	"strings"
+	"testing"
+)
+
+// codes 提取问题代码
+func codes(findings []Finding) []string {
+	var result []string
+	for _, f := range findings {
+		result = append(result, f.Code)
+	}
+	return result
+}
+
+// hasCode 检查是否包含指定代码的问题
+func hasCode(findings []Finding, code string) bool {
+	for _, f := range findings {
+		if f.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		opts    Options
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "Clean prompt",
+			content: "---\nname: demo\n---\nYou are a Go expert.\n\nAlways write tests.\n",
+			notWant: []string{CodeMissingRole, CodeConflict, CodePlaceholder, CodeTooLong},
+		},
+		{
+			name:    "Missing role",
+			content: "Write tests for every change.\n",
+			want:    []string{CodeMissingRole},
+		},
+		{
+			name:    "Chinese role",
+			content: "你是一名资深的代码审查专家。\n",
+			notWant: []string{CodeMissingRole},
+		},
+		{
+			name:    "Conflicting instructions",
+			content: "You are a helper.\nAlways use tabs.\nNever use tabs.\n",
+			want:    []string{CodeConflict},
+		},
+		{
+			name:    "Chinese conflicting instructions",
+			content: "你是助手。\n必须使用中文回复。\n不要使用中文回复。\n",
+			want:    []string{CodeConflict},
+		},
+		{
+			name:    "Unset variable",
+			content: "You are a helper for {{.PROJECT}} and {{.LANG}}.\n",
+			opts:    Options{Variables: map[string]string{"PROJECT": "demo"}},
+			want:    []string{CodePlaceholder},
+		},
+		{
+			name:    "Variables not checked without values",
+			content: "You are a helper for {{.PROJECT}}.\n",
+			notWant: []string{CodePlaceholder},
+		},
+		{
+			name:    "Malformed variable",
+			content: "You are a helper for {{ .PROJECT }}.\n",
+			want:    []string{CodeMalformedVar},
+		},
+		{
+			name:    "Conditional blocks are not variables",
+			content: "You are a helper.\n{{if eq .Target \"cursor\"}}\nUse rules.\n{{else}}\nUse skills.\n{{end}}\n",
+			notWant: []string{CodeMalformedVar},
+		},
+		{
+			name:    "TODO marker",
+			content: "You are a helper.\nTODO: describe the workflow\n",
+			want:    []string{CodePlaceholder},
+		},
+		{
+			name:    "TODO inside code block is ignored",
+			content: "You are a helper.\n```go\n// TODO: example\n```\n",
+			notWant: []string{CodePlaceholder},
+		},
+		{
+			name:    "Too long",
+			content: "You are a helper.\n" + strings.Repeat("word ", 100),
+			opts:    Options{MaxTokens: 50},
+			want:    []string{CodeTooLong},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Lint(tt.content, tt.opts)
+			for _, code := range tt.want {
+				if !hasCode(findings, code) {
+					t.Errorf("Lint() = %v, want %s", codes(findings), code)
+				}
+			}
+			for _, code := range tt.notWant {
+				if hasCode(findings, code) {
+					t.Errorf("Lint() = %v, should not report %s", codes(findings), code)
+				}
+			}
+		})
+	}
+
+	t.Run("Line numbers include frontmatter", func(t *testing.T) {
+		findings := Lint("---\nname: demo\n---\nYou are a helper.\nTODO\n", Options{})
+		if len(findings) != 1 || findings[0].Line != 5 {
+			t.Errorf("Lint() = %+v, want one finding on line 5", findings)
+		}
+	})
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("abcdefgh"); got != 2 {
+		t.Errorf("EstimateTokens(ascii) = %d, want 2", got)
+	}
+	if got := EstimateTokens("你好世界"); got != 4 {
+		t.Errorf("EstimateTokens(cjk) = %d, want 4", got)
+	}
+}
+
+func TestFindDuplicateSections(t *testing.T) {
+	shared := "## Commit messages\nUse the imperative mood and keep the subject under fifty characters.\n"
+	prompts := map[string]string{
+		"a": "---\nname: a\n---\n# A\nIntro for a.\n" + shared,
+		"b": "# B\nIntro for b.\n" + shared,
+		"c": "# C\n## Commit messages\nshort\n",
+	}
+
+	result := FindDuplicateSections(prompts)
+	if len(result["a"]) != 1 || len(result["b"]) != 1 {
+		t.Fatalf("FindDuplicateSections() = %+v, want one finding for a and b", result)
+	}
+	if len(result["c"]) != 0 {
+		t.Errorf("short section should not be reported: %+v", result["c"])
+	}
+	if result["a"][0].Line != 6 {
+		t.Errorf("line = %d, want 6", result["a"][0].Line)
+	}
+	if !strings.Contains(result["a"][0].Message, "b") {
+		t.Errorf("message should mention the other skill: %s", result["a"][0].Message)
+	}
+}
@@ -0,0 +1,637 @@
+package skillhub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"skill-hub/internal/adapter"
+	"skill-hub/internal/adapter/claude"
+	"skill-hub/internal/adapter/cursor"
+	"skill-hub/internal/adapter/opencode"
+	"skill-hub/internal/adapter/shell"
+	"skill-hub/internal/config"
+	"skill-hub/internal/engine"
+	"skill-hub/internal/resource"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+)
+
+// Project 启用了技能的项目目录
+type Project struct {
+	path string
+	hub  *Manager
+}
+
+// ApplyOptions 应用技能的选项
+type ApplyOptions struct {
+	Target string // 目标工具，为空时使用项目的首选目标
+	Mode   string // 配置模式，为空时为 ModeProject
+	DryRun bool   // 只计算变化，不修改文件和项目状态
+	NoDeps bool   // 不解析依赖，也不自动启用缺少的依赖技能
+	// Profile 使用的变量配置，为空时使用项目的默认配置
+	Profile string
+	// Skills 额外应用的技能（如工作区启用的技能），与项目状态中的技能合并，同名时以此为准；
+	// 不是预览时先在项目状态中启用这些技能
+	Skills map[string]EnabledSkill
+	// Locale 技能提示词的语言，为空时使用项目或全局配置的语言；技能没有对应的语言版本时使用 SKILL.md
+	Locale string
+	// NoMerge 直接覆盖目标文件中的本地修改；默认在技能内容也有变化时以上次应用的内容为共同祖先三方合并
+	NoMerge bool
+	// Only 只应用这些技能及自动启用的依赖技能，为空时应用全部技能；技能需要已启用或在 Skills 中
+	Only []string
+	// Exclude 不应用的技能，目标文件中这些技能的内容保持不变
+	Exclude []string
+}
+
+// AppliedSkill 技能应用到一个目标工具的结果
+type AppliedSkill struct {
+	SkillID  string `json:"skill_id"`
+	Target   string `json:"target"`
+	Version  string `json:"version"`
+	FilePath string `json:"file_path"`
+	Changed  bool   `json:"changed"`
+	Diff     string `json:"diff,omitempty"` // 统一差异格式，没有变化时为空
+	// Merged 目标文件中的本地修改已与新内容三方合并，Conflicts 为写入冲突标记的冲突数
+	Merged    bool `json:"merged,omitempty"`
+	Conflicts int  `json:"conflicts,omitempty"`
+}
+
+// SkippedSkill 没有应用到目标工具的技能及原因
+type SkippedSkill struct {
+	SkillID string `json:"skill_id"`
+	Target  string `json:"target"`
+	Reason  string `json:"reason"`
+}
+
+// ApplyResult 一次应用的结果
+type ApplyResult struct {
+	Applied []AppliedSkill `json:"applied"`
+	Skipped []SkippedSkill `json:"skipped,omitempty"`
+}
+
+// Path 返回项目的绝对路径
+func (p *Project) Path() string {
+	return p.path
+}
+
+// Skills 返回项目中启用的技能
+func (p *Project) Skills() (map[string]EnabledSkill, error) {
+	return p.hub.state.GetProjectSkills(p.path)
+}
+
+// Target 返回项目的首选目标工具
+func (p *Project) Target() (string, error) {
+	target, err := p.hub.state.GetPreferredTarget(p.path)
+	if err != nil {
+		return "", err
+	}
+	return spec.NormalizeTarget(target), nil
+}
+
+// Targets 返回项目绑定的所有首选目标工具，第一个与 Target 相同
+func (p *Project) Targets() ([]string, error) {
+	projectState, err := p.hub.state.LoadProjectState(p.path)
+	if err != nil {
+		return nil, err
+	}
+	return projectState.BoundTargets(), nil
+}
+
+// SetTarget 设置项目的首选目标工具
+func (p *Project) SetTarget(target string) error {
+	target = spec.NormalizeTarget(target)
+	switch target {
+	case spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell:
+	default:
+		return fmt.Errorf("无效的目标工具: %s，可用选项: %s, %s, %s, %s", target, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell)
+	}
+	return p.hub.state.SetPreferredTarget(p.path, target)
+}
+
+// Enable 在项目中启用技能，未设置的变量使用默认值，必填变量缺失或取值无效时返回错误
+//
+// 已启用的技能会用新的变量覆盖原有变量。启用后需要调用 Apply 写入目标工具。
+func (p *Project) Enable(skillID string, variables map[string]string) error {
+	skill, err := p.hub.skills.LoadSkill(skillID)
+	if err != nil {
+		return fmt.Errorf("加载技能失败: %w", err)
+	}
+	values, err := engine.ResolveVariables(skill, variables)
+	if err != nil {
+		return fmt.Errorf("技能 %s 的变量无效: %w", skillID, err)
+	}
+	if err := p.hub.state.AddSkillToProject(p.path, skillID, skill.Version, values); err != nil {
+		return fmt.Errorf("保存项目状态失败: %w", err)
+	}
+	return nil
+}
+
+// Disable 从项目首选目标工具的配置和项目状态中移除技能，并删除已安装的资源文件
+func (p *Project) Disable(skillID string) error {
+	skills, err := p.hub.state.GetProjectSkills(p.path)
+	if err != nil {
+		return err
+	}
+	skillVars, ok := skills[skillID]
+	if !ok {
+		return fmt.Errorf("技能 %s 未在项目中启用", skillID)
+	}
+
+	targets, err := p.Targets()
+	if err != nil {
+		return err
+	}
+	target := strings.Join(targets, ",")
+	if len(targets) > 0 {
+		skill, err := p.hub.skills.LoadSkill(skillID)
+		if err != nil {
+			return fmt.Errorf("加载技能失败: %w", err)
+		}
+		var adapters []adapter.Adapter
+		for _, boundTarget := range targets {
+			adapters = append(adapters, p.adapters(boundTarget, ModeProject)...)
+		}
+		for _, adpt := range adapters {
+			if !skill.SupportsTarget(adapterTarget(adpt)) || !adpt.Supports() {
+				continue
+			}
+			if err := adpt.Remove(skillID); err != nil {
+				return fmt.Errorf("从 %s 移除技能 %s 失败: %w", adapterTarget(adpt), skillID, err)
+			}
+		}
+	}
+
+	if len(skillVars.Resources) > 0 {
+		if err := resource.Remove(p.path, skillVars.Resources); err != nil {
+			return err
+		}
+	}
+	if err := p.hub.state.RemoveSkillFromProject(p.path, skillID); err != nil {
+		return fmt.Errorf("更新项目状态失败: %w", err)
+	}
+	recordAudit(state.AuditEntry{Operation: state.OpRemove, Project: p.path, SkillID: skillID, Version: skillVars.Version, Adapter: target})
+	return nil
+}
+
+// Apply 将项目中启用的技能写入目标工具，规则与 skill-hub apply 相同
+//
+// 被依赖的技能先应用，缺少的依赖技能按默认变量自动启用；锁文件固定的技能使用固定版本的应用记录。
+// 所有写入在同一事务中，任一技能写入失败时回滚全部文件并返回错误。
+// 无法应用的技能（不兼容目标、技能不存在、变量缺失等）记录在 Skipped 中。
+func (p *Project) Apply(opts ApplyOptions) (*ApplyResult, error) {
+	result := &ApplyResult{Applied: []AppliedSkill{}}
+
+	target := spec.NormalizeTarget(opts.Target)
+	targets := []string{target}
+	if target == "" {
+		var err error
+		// 项目绑定了多个目标时全部应用
+		if targets, err = p.Targets(); err != nil {
+			return nil, err
+		}
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("项目 %s 未设置目标工具，请在 ApplyOptions.Target 中指定", p.path)
+		}
+		target = targets[0]
+	}
+	mode := opts.Mode
+	if mode == "" {
+		mode = ModeProject
+	}
+	if mode != ModeProject && mode != ModeGlobal {
+		return nil, fmt.Errorf("无效的配置模式: %s，可用选项: %s, %s", mode, ModeProject, ModeGlobal)
+	}
+	var adapters []adapter.Adapter
+	for _, t := range targets {
+		targetAdapters := p.adapters(t, mode)
+		if len(targetAdapters) == 0 {
+			return nil, fmt.Errorf("无效的目标工具: %s，可用选项: %s, %s, %s, %s, %s", t, spec.TargetCursor, spec.TargetClaudeCode, spec.TargetOpenCode, spec.TargetShell, spec.TargetAll)
+		}
+		adapters = append(adapters, targetAdapters...)
+	}
+
+	projectState, err := p.hub.state.LoadProjectState(p.path)
+	if err != nil {
+		return nil, err
+	}
+	locale := opts.Locale
+	if locale == "" {
+		locale = projectState.Locale
+	}
+	if locale != "" {
+		if locale, err = spec.NormalizeLocale(locale); err != nil {
+			return nil, err
+		}
+		previous := p.hub.skills.Locale()
+		p.hub.skills.SetLocale(locale)
+		defer p.hub.skills.SetLocale(previous)
+	}
+	skills := projectState.Skills
+	if len(opts.Skills) > 0 {
+		if !opts.DryRun {
+			preferred := target
+			if preferred == spec.TargetAll {
+				preferred = ""
+			}
+			if err := p.hub.state.EnableSkills(p.path, opts.Skills, preferred); err != nil {
+				return nil, fmt.Errorf("保存项目状态失败: %w", err)
+			}
+		}
+		for skillID, skillVars := range opts.Skills {
+			existing := skills[skillID]
+			existing.SkillID = skillID
+			existing.Version = skillVars.Version
+			existing.Variables = skillVars.Variables
+			skills[skillID] = existing
+		}
+	}
+	if len(skills) == 0 {
+		return result, nil
+	}
+	for _, skillID := range opts.Only {
+		if _, ok := skills[skillID]; !ok {
+			return nil, fmt.Errorf("技能 '%s' 未在项目 %s 中启用", skillID, p.path)
+		}
+	}
+	profile, profileVars, err := state.ResolveProfile(projectState, opts.Profile)
+	if err != nil {
+		return nil, err
+	}
+	for skillID, variables := range profileVars {
+		skillVars := skills[skillID]
+		skillVars.Variables = variables
+		skills[skillID] = skillVars
+	}
+
+	lock, err := state.LoadLockFile(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[string]bool, len(skills))
+	for skillID := range skills {
+		enabled[skillID] = true
+	}
+	order, err := p.applyOrder(skills, lock, opts)
+	if err != nil {
+		return nil, err
+	}
+	skillIDs := selectSkills(order, opts.Only, opts.Exclude, enabled)
+
+	type appliedRecord struct {
+		skillID  string
+		target   string
+		rev      spec.AppliedRevision
+		upToDate bool // 目标文件和应用记录都已是本次的内容
+	}
+	var records []appliedRecord
+	tx := adapter.NewTransaction()
+
+	for _, adpt := range adapters {
+		adptTarget := adapterTarget(adpt)
+		skip := func(skillID string, err error) {
+			result.Skipped = append(result.Skipped, SkippedSkill{SkillID: skillID, Target: adptTarget, Reason: err.Error()})
+		}
+		adapterApplied := 0
+
+		for _, skillID := range skillIDs {
+			skillVars := skills[skillID]
+			skill, err := p.hub.skills.LoadSkill(skillID)
+			if err != nil {
+				skip(skillID, err)
+				continue
+			}
+			if !skill.SupportsTarget(adptTarget) {
+				skip(skillID, fmt.Errorf("技能 %s 不支持 %s", skillID, adptTarget))
+				continue
+			}
+
+			content, variables, version, err := p.resolveContent(lock, skill, skillVars, adptTarget, mode)
+			if err != nil {
+				skip(skillID, err)
+				continue
+			}
+
+			plan, merge, err := p.plan(adpt, skillVars, skillID, content, variables, opts.NoMerge)
+			if err != nil {
+				skip(skillID, fmt.Errorf("预览技能 %s 到 %s 失败: %w", skillID, adptTarget, err))
+				continue
+			}
+			applied := AppliedSkill{
+				SkillID:   skillID,
+				Target:    adptTarget,
+				Version:   version,
+				FilePath:  plan.FilePath,
+				Changed:   plan.HasChanges(),
+				Merged:    merge.Merged,
+				Conflicts: merge.Conflicts,
+			}
+			if applied.Changed {
+				applied.Diff = plan.Diff()
+			}
+
+			// 目标文件中已是本次的内容时不写入也不备份，应用记录不一致时只更新记录
+			if !opts.DryRun && !applied.Changed {
+				records = append(records, appliedRecord{
+					skillID:  skillID,
+					target:   adptTarget,
+					rev:      spec.AppliedRevision{Version: version, Variables: skillVars.Variables, Profile: profile, Content: content, Mode: mode},
+					upToDate: state.AppliedUpToDate(skillVars, adptTarget, mode, version, content),
+				})
+			} else if !opts.DryRun {
+				// 登记目标文件以便失败时回滚
+				for _, path := range plan.Files() {
+					if err := tx.Track(path); err != nil {
+						tx.Rollback()
+						return nil, fmt.Errorf("备份 %s 失败: %w", path, err)
+					}
+				}
+				if err := adapter.ApplyMerged(adpt, plan, merge, content, variables); err != nil {
+					if rollbackErr := tx.Rollback(); rollbackErr != nil {
+						return nil, fmt.Errorf("应用技能 %s 到 %s 失败: %w（回滚失败: %v）", skillID, adptTarget, err, rollbackErr)
+					}
+					return nil, fmt.Errorf("应用技能 %s 到 %s 失败: %w", skillID, adptTarget, err)
+				}
+				records = append(records, appliedRecord{
+					skillID: skillID,
+					target:  adptTarget,
+					rev:     spec.AppliedRevision{Version: version, Variables: skillVars.Variables, Profile: profile, Content: content, Mode: mode},
+				})
+			}
+			result.Applied = append(result.Applied, applied)
+			adapterApplied++
+		}
+
+		// 按应用顺序排列目标文件中的技能，使生成的文件与技能启用的先后无关
+		if !opts.DryRun && adapterApplied > 0 {
+			if err := p.order(tx, adpt, order); err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					return nil, fmt.Errorf("排列 %s 中的技能失败: %w（回滚失败: %v）", adptTarget, err, rollbackErr)
+				}
+				return nil, fmt.Errorf("排列 %s 中的技能失败: %w", adptTarget, err)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	// 备份清理失败不影响已写入的结果
+	_ = tx.Commit()
+
+	// 记录已应用内容，供 rollback 使用
+	installed := make(map[string]bool)
+	for _, record := range records {
+		if !record.upToDate {
+			if err := p.hub.state.RecordAppliedRevision(p.path, record.skillID, record.target, record.rev); err != nil {
+				return result, fmt.Errorf("记录技能 %s 的应用历史失败: %w", record.skillID, err)
+			}
+			recordAudit(state.AuditEntry{Operation: state.OpApply, Project: p.path, SkillID: record.skillID, Version: record.rev.Version, Adapter: record.target})
+		}
+
+		// 资源文件与目标工具无关，每个技能只安装一次，全局模式下不安装到项目中
+		if mode == ModeGlobal || installed[record.skillID] {
+			continue
+		}
+		installed[record.skillID] = true
+		if err := p.installResources(record.skillID, skills[record.skillID].Resources); err != nil {
+			return result, fmt.Errorf("安装技能 %s 的资源文件失败: %w", record.skillID, err)
+		}
+	}
+	return result, nil
+}
+
+// order 按应用顺序排列适配器目标文件中的技能，顺序需要调整时先在事务中登记目标文件
+func (p *Project) order(tx *adapter.Transaction, adpt adapter.Adapter, skillIDs []string) error {
+	plan, err := adapter.PlanOrder(adpt, skillIDs)
+	if err != nil || plan == nil || !plan.HasChanges() {
+		return err
+	}
+	if err := tx.Track(plan.FilePath); err != nil {
+		return fmt.Errorf("备份 %s 失败: %w", plan.FilePath, err)
+	}
+	return adapter.Order(adpt, skillIDs)
+}
+
+// selectSkills 按 only 和 exclude 筛选要应用的技能，保持 order 中的应用顺序
+//
+// only 为空时选择全部技能；不在 enabled 中的技能是自动启用的依赖技能，没有被排除时总是应用。
+func selectSkills(order, only, exclude []string, enabled map[string]bool) []string {
+	if len(only) == 0 && len(exclude) == 0 {
+		return order
+	}
+	selected := make(map[string]bool, len(only))
+	for _, skillID := range only {
+		selected[skillID] = true
+	}
+	excluded := make(map[string]bool, len(exclude))
+	for _, skillID := range exclude {
+		excluded[skillID] = true
+	}
+
+	var skillIDs []string
+	for _, skillID := range order {
+		if excluded[skillID] {
+			continue
+		}
+		if len(only) == 0 || selected[skillID] || !enabled[skillID] {
+			skillIDs = append(skillIDs, skillID)
+		}
+	}
+	return skillIDs
+}
+
+// plan 计算应用技能的变更计划，目标文件有本地修改且未指定 noMerge 时与新内容三方合并
+func (p *Project) plan(adpt adapter.Adapter, skillVars spec.SkillVars, skillID, content string, variables map[string]string, noMerge bool) (*adapter.Plan, adapter.MergeResult, error) {
+	base, ok := state.AppliedBase(skillVars, adapterTarget(adpt))
+	if noMerge || !ok {
+		plan, err := adpt.Plan(skillID, content, variables)
+		return plan, adapter.MergeResult{}, err
+	}
+	return adapter.PlanMerge(adpt, skillID, base, content, variables)
+}
+
+// applyOrder 确定应用顺序：优先级高的技能先应用，被依赖的技能先应用，缺少的依赖技能按默认变量启用并加入 skills
+func (p *Project) applyOrder(skills map[string]spec.SkillVars, lock *state.LockFile, opts ApplyOptions) ([]string, error) {
+	skillIDs := make([]string, 0, len(skills))
+	for skillID := range skills {
+		skillIDs = append(skillIDs, skillID)
+	}
+	sort.Strings(skillIDs)
+	priorities := make(map[string]int, len(skillIDs))
+	for _, skillID := range skillIDs {
+		skill, _ := p.hub.skills.LoadSkill(skillID)
+		priorities[skillID] = engine.EffectivePriority(skill, skills[skillID])
+	}
+	engine.OrderByPriority(skillIDs, func(skillID string) int { return priorities[skillID] })
+	if opts.NoDeps {
+		return skillIDs, nil
+	}
+
+	// 技能仓库中已不存在的技能不参与解析，应用时记为跳过
+	var roots, missing []string
+	for _, skillID := range skillIDs {
+		if p.hub.skills.SkillExists(skillID) {
+			roots = append(roots, skillID)
+		} else {
+			missing = append(missing, skillID)
+		}
+	}
+	// 已固定的技能按锁定的版本检查依赖约束
+	resolved, err := engine.ResolveDependencies(roots, func(skillID string) (*spec.Skill, error) {
+		skill, err := p.hub.skills.LoadSkill(skillID)
+		if err != nil {
+			return nil, err
+		}
+		if locked, pinned := lock.Get(skillID); pinned {
+			if stored, _, ok, err := p.hub.versions.LoadPinned(skill, locked.Version, ""); err == nil && ok {
+				return stored, nil
+			}
+			pinnedSkill := *skill
+			pinnedSkill.Version = locked.Version
+			return &pinnedSkill, nil
+		}
+		return skill, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, skillID := range resolved {
+		if _, ok := skills[skillID]; ok {
+			continue
+		}
+		skill, err := p.hub.skills.LoadSkill(skillID)
+		if err != nil {
+			return nil, err
+		}
+		variables, err := engine.ResolveVariables(skill, nil)
+		if err != nil {
+			return nil, fmt.Errorf("无法自动启用依赖技能 %s: %w（请先调用 Enable 设置变量）", skillID, err)
+		}
+		if !opts.DryRun {
+			if err := p.hub.state.AddSkillToProject(p.path, skillID, skill.Version, variables); err != nil {
+				return nil, fmt.Errorf("保存项目状态失败: %w", err)
+			}
+		}
+		skills[skillID] = spec.SkillVars{SkillID: skillID, Version: skill.Version, Variables: variables}
+	}
+	return append(resolved, missing...), nil
+}
+
+// resolveContent 按锁文件确定写入目标工具的内容、变量和版本
+//
+// 技能固定在仓库中已不存在的版本时，优先使用版本存储中已安装的该版本，其次使用该版本的应用记录（内容已渲染），
+// 都没有时返回错误。
+func (p *Project) resolveContent(lock *state.LockFile, skill *spec.Skill, skillVars spec.SkillVars, target, mode string) (string, map[string]string, string, error) {
+	if locked, pinned := lock.Get(skill.ID); pinned && locked.Version != skill.Version {
+		stored, prompt, ok, err := p.hub.versions.LoadPinned(skill, locked.Version, p.hub.skills.Locale())
+		if err != nil {
+			return "", nil, "", fmt.Errorf("加载已安装的 %s@%s 失败: %w", skill.ID, locked.Version, err)
+		}
+		if ok {
+			content, err := engine.RenderContent(stored, prompt, skillVars.Variables, target, mode)
+			if err != nil {
+				return "", nil, "", fmt.Errorf("渲染技能 %s 失败: %w", skill.ID, err)
+			}
+			return content, skillVars.Variables, stored.Version, nil
+		}
+		if rev, ok := state.FindRevisionByVersion(skillVars, target, locked.Version); ok {
+			// 应用记录中的密钥等变量引用已去除，写入前还原为实际值
+			content, err := state.RevisionContent(*rev)
+			if err != nil {
+				return "", nil, "", fmt.Errorf("还原技能 %s 的应用记录失败: %w", skill.ID, err)
+			}
+			return content, nil, locked.Version, nil
+		}
+		return "", nil, "", fmt.Errorf("技能 %s 固定在 %s，但仓库版本为 %s，该版本既未安装也没有应用记录，拒绝升级", skill.ID, locked.Version, skill.Version)
+	}
+
+	prompt, err := p.hub.skills.GetSkillPrompt(skill.ID)
+	if err != nil {
+		return "", nil, "", err
+	}
+	content, err := engine.RenderContent(skill, prompt, skillVars.Variables, target, mode)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("渲染技能 %s 失败: %w", skill.ID, err)
+	}
+	return content, skillVars.Variables, skill.Version, nil
+}
+
+// installResources 将技能的资源文件安装到项目中，并删除上次安装后已不存在的文件
+func (p *Project) installResources(skillID string, previous []string) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	files, err := resource.Install(p.hub.skills.GetSkillDir(skillID), p.path, cfg.ResourcesDir, skillID, cfg.ResourcesMode)
+	if err != nil {
+		return err
+	}
+	if stale := resource.Stale(previous, files); len(stale) > 0 {
+		if err := resource.Remove(p.path, stale); err != nil {
+			return err
+		}
+	}
+	if len(files) == 0 && len(previous) == 0 {
+		return nil
+	}
+	return p.hub.state.SetSkillResources(p.path, skillID, files)
+}
+
+// adapters 返回写入项目目录（或全局配置）的适配器
+func (p *Project) adapters(target, mode string) []adapter.Adapter {
+	var adapters []adapter.Adapter
+	if target == spec.TargetAll || target == spec.TargetCursor {
+		a := cursor.NewCursorAdapter().WithProjectPath(p.path)
+		if mode == ModeGlobal {
+			a = a.WithGlobalMode()
+		}
+		adapters = append(adapters, a)
+	}
+	if target == spec.TargetAll || target == spec.TargetClaudeCode {
+		a := claude.NewClaudeAdapter().WithProjectPath(p.path)
+		if mode == ModeGlobal {
+			a = a.WithGlobalMode()
+		}
+		adapters = append(adapters, a)
+	}
+	if target == spec.TargetAll || target == spec.TargetOpenCode {
+		if mode == ModeGlobal {
+			adapters = append(adapters, opencode.NewOpenCodeAdapter().WithGlobalMode())
+		} else {
+			adapters = append(adapters, opencode.NewOpenCodeAdapter().WithProjectPath(p.path))
+		}
+	}
+	if target == spec.TargetAll || target == spec.TargetShell {
+		a := shell.NewShellAdapter().WithProjectPath(p.path)
+		if mode == ModeGlobal {
+			a = a.WithGlobalMode()
+		}
+		adapters = append(adapters, a)
+	}
+	return adapters
+}
+
+// adapterTarget 返回适配器对应的目标工具
+func adapterTarget(adpt adapter.Adapter) string {
+	switch adpt.(type) {
+	case *cursor.CursorAdapter:
+		return spec.TargetCursor
+	case *claude.ClaudeAdapter:
+		return spec.TargetClaudeCode
+	case *opencode.OpenCodeAdapter:
+		return spec.TargetOpenCode
+	case *shell.ShellAdapter:
+		return spec.TargetShell
+	}
+	return spec.TargetUnknown
+}
+
+// recordAudit 写入操作历史，写入失败不影响操作结果
+func recordAudit(entry state.AuditEntry) {
+	if auditLog, err := state.NewAuditLog(); err == nil {
+		_ = auditLog.Append(entry)
+	}
+}
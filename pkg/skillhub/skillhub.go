@@ -0,0 +1,98 @@
+// Package skillhub 提供在 Go 程序中管理技能的接口，不需要调用 skill-hub 命令行
+//
+// 与命令行共用 ~/.skill-hub 中的配置、技能仓库和项目状态，使用前需要先执行 skill-hub init。
+//
+//	hub, err := skillhub.New()
+//	if err != nil {
+//		return err
+//	}
+//	project, err := hub.Project("/path/to/project")
+//	if err != nil {
+//		return err
+//	}
+//	if err := project.Enable("git-expert", map[string]string{"LANGUAGE": "go"}); err != nil {
+//		return err
+//	}
+//	result, err := project.Apply(skillhub.ApplyOptions{Target: skillhub.TargetCursor})
+package skillhub
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"skill-hub/internal/engine"
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+)
+
+// 目标工具
+const (
+	TargetCursor     = spec.TargetCursor
+	TargetClaudeCode = spec.TargetClaudeCode
+	TargetOpenCode   = spec.TargetOpenCode
+	TargetShell      = spec.TargetShell
+	TargetAll        = spec.TargetAll
+)
+
+// 配置模式
+const (
+	ModeProject = spec.ModeProject
+	ModeGlobal  = spec.ModeGlobal
+)
+
+type (
+	// Skill 技能定义
+	Skill = spec.Skill
+	// Variable 技能变量声明
+	Variable = spec.Variable
+	// EnabledSkill 项目中启用的技能及其变量
+	EnabledSkill = spec.SkillVars
+)
+
+// Manager 技能管理入口，不能在多个 goroutine 中同时使用
+type Manager struct {
+	skills   *engine.SkillManager
+	state    *state.StateManager
+	versions *engine.VersionStore
+}
+
+// New 按 ~/.skill-hub/config.yaml 创建技能管理器
+func New() (*Manager, error) {
+	skills, err := engine.NewSkillManager()
+	if err != nil {
+		return nil, fmt.Errorf("创建技能管理器失败: %w", err)
+	}
+	stateMgr, err := state.NewStateManager()
+	if err != nil {
+		return nil, fmt.Errorf("创建状态管理器失败: %w", err)
+	}
+	versions, err := engine.NewVersionStore()
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{skills: skills, state: stateMgr, versions: versions}, nil
+}
+
+// Skills 返回技能仓库和额外技能目录中的全部技能
+func (m *Manager) Skills() ([]*Skill, error) {
+	return m.skills.LoadAllSkills()
+}
+
+// Skill 加载指定ID的技能
+func (m *Manager) Skill(skillID string) (*Skill, error) {
+	return m.skills.LoadSkill(skillID)
+}
+
+// Render 按目标工具渲染技能内容（项目模式），与 apply 写入的内容一致
+func (m *Manager) Render(skillID string, variables map[string]string, target string) (string, error) {
+	return m.skills.RenderSkill(skillID, variables, spec.NormalizeTarget(target), ModeProject)
+}
+
+// Project 返回指定目录的项目，相对路径按当前工作目录解析
+func (m *Manager) Project(path string) (*Project, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("获取绝对路径失败: %w", err)
+	}
+	return &Project{path: absPath, hub: m}, nil
+}
@@ -0,0 +1,304 @@
+package skillhub
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"skill-hub/internal/state"
+	"skill-hub/pkg/spec"
+)
+
+// writeFile 创建文件及其所在目录
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestManager 在临时主目录中创建配置和技能仓库
+//
+// 配置在进程内只加载一次，因此同一个测试进程只能调用一次。
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	repo := filepath.Join(home, ".skill-hub", "repo")
+	writeFile(t, filepath.Join(home, ".skill-hub", "config.yaml"), "repo_path: "+repo+"\n")
+	writeFile(t, filepath.Join(repo, "skills", "base", "SKILL.md"),
+		"---\nname: base\ndescription: Base rules\nversion: 1.0.0\n---\nBase rules.\n")
+	writeFile(t, filepath.Join(repo, "skills", "base", "resources", "notes.md"), "notes\n")
+	writeFile(t, filepath.Join(repo, "skills", "lang", "SKILL.md"),
+		"---\nname: lang\ndescription: Language rules\nversion: 1.2.0\ndependencies:\n  - base\nvariables:\n  - name: LANGUAGE\n    description: Language\n---\nUse {{.LANGUAGE}}.\n{{if eq .Target \"cursor\"}}\nCursor only.\n{{end}}\n")
+
+	hub, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return hub
+}
+
+func TestManager(t *testing.T) {
+	hub := newTestManager(t)
+
+	skills, err := hub.Skills()
+	if err != nil || len(skills) != 2 {
+		t.Fatalf("Skills() = %v, %v", skills, err)
+	}
+
+	content, err := hub.Render("lang", map[string]string{"LANGUAGE": "go"}, TargetCursor)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(content, "Use go.") || !strings.Contains(content, "Cursor only.") {
+		t.Errorf("Render() = %q", content)
+	}
+	if _, err := hub.Render("lang", nil, TargetCursor); err == nil {
+		t.Error("Render() should fail when a required variable is missing")
+	}
+
+	project, err := hub.Project(t.TempDir())
+	if err != nil {
+		t.Fatalf("Project() error = %v", err)
+	}
+
+	t.Run("enable", func(t *testing.T) {
+		if err := project.Enable("missing", nil); err == nil {
+			t.Error("Enable() should fail for unknown skill")
+		}
+		if err := project.Enable("lang", nil); err == nil {
+			t.Error("Enable() should fail when a required variable is missing")
+		}
+		if err := project.Enable("lang", map[string]string{"LANGUAGE": "go"}); err != nil {
+			t.Fatalf("Enable() error = %v", err)
+		}
+		if err := project.SetTarget("vim"); err == nil {
+			t.Error("SetTarget() should reject unknown target")
+		}
+		if err := project.SetTarget(TargetCursor); err != nil {
+			t.Fatalf("SetTarget() error = %v", err)
+		}
+	})
+
+	rulesPath := filepath.Join(project.Path(), ".cursorrules")
+
+	t.Run("dry run", func(t *testing.T) {
+		result, err := project.Apply(ApplyOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if len(result.Applied) != 2 || result.Applied[0].SkillID != "base" || !result.Applied[0].Changed || result.Applied[0].Diff == "" {
+			t.Errorf("Apply() = %+v", result.Applied)
+		}
+		if _, err := os.Stat(rulesPath); !os.IsNotExist(err) {
+			t.Error("dry run should not write files")
+		}
+		skills, _ := project.Skills()
+		if _, ok := skills["base"]; ok {
+			t.Error("dry run should not enable dependencies")
+		}
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		result, err := project.Apply(ApplyOptions{})
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if len(result.Applied) != 2 || result.Applied[1].FilePath != rulesPath {
+			t.Fatalf("Apply() = %+v", result.Applied)
+		}
+		data, err := os.ReadFile(rulesPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "Base rules.") || !strings.Contains(string(data), "Use go.") {
+			t.Errorf(".cursorrules = %q", data)
+		}
+
+		skills, _ := project.Skills()
+		if _, ok := skills["base"]; !ok {
+			t.Error("Apply() should enable missing dependencies")
+		}
+		if len(skills["lang"].History[TargetCursor]) != 1 {
+			t.Errorf("history = %+v", skills["lang"].History)
+		}
+		if len(skills["base"].Resources) != 1 {
+			t.Errorf("resources = %v", skills["base"].Resources)
+		}
+
+		// 再次应用没有变化
+		result, err = project.Apply(ApplyOptions{DryRun: true})
+		if err != nil || result.Applied[0].Changed || result.Applied[1].Changed {
+			t.Errorf("Apply() again = %+v, %v", result, err)
+		}
+
+		// 已是最新时不写入目标文件
+		old := time.Now().Add(-time.Hour).Truncate(time.Second)
+		if err := os.Chtimes(rulesPath, old, old); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := project.Apply(ApplyOptions{}); err != nil {
+			t.Fatalf("Apply() again error = %v", err)
+		}
+		if info, err := os.Stat(rulesPath); err != nil || !info.ModTime().Equal(old) {
+			t.Errorf("Apply() should not rewrite an up-to-date .cursorrules")
+		}
+	})
+
+	t.Run("apply selected skills", func(t *testing.T) {
+		result, err := project.Apply(ApplyOptions{DryRun: true, Only: []string{"lang"}})
+		if err != nil || len(result.Applied) != 1 || result.Applied[0].SkillID != "lang" {
+			t.Errorf("Apply(Only) = %+v, %v", result, err)
+		}
+		result, err = project.Apply(ApplyOptions{DryRun: true, Exclude: []string{"lang"}})
+		if err != nil || len(result.Applied) != 1 || result.Applied[0].SkillID != "base" {
+			t.Errorf("Apply(Exclude) = %+v, %v", result, err)
+		}
+		if _, err := project.Apply(ApplyOptions{DryRun: true, Only: []string{"missing"}}); err == nil {
+			t.Error("Apply() should fail for a skill that is not enabled")
+		}
+	})
+
+	t.Run("apply selected skills leaves others untouched", func(t *testing.T) {
+		data, _ := os.ReadFile(rulesPath)
+		edited := strings.Replace(string(data), "Base rules.", "Base rules, edited locally.", 1)
+		writeFile(t, rulesPath, edited)
+		if err := project.Enable("lang", map[string]string{"LANGUAGE": "rust"}); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			project.Enable("lang", map[string]string{"LANGUAGE": "go"})
+			project.Apply(ApplyOptions{Only: []string{"lang"}, NoDeps: true})
+		}()
+
+		if _, err := project.Apply(ApplyOptions{Only: []string{"lang"}, NoDeps: true}); err != nil {
+			t.Fatalf("Apply(Only) error = %v", err)
+		}
+		data, _ = os.ReadFile(rulesPath)
+		if !strings.Contains(string(data), "Use rust.") || !strings.Contains(string(data), "Base rules, edited locally.") {
+			t.Errorf(".cursorrules = %q, want only lang re-applied", data)
+		}
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		if err := project.Disable("base"); err != nil {
+			t.Fatalf("Disable() error = %v", err)
+		}
+		data, _ := os.ReadFile(rulesPath)
+		if strings.Contains(string(data), "Base rules.") || !strings.Contains(string(data), "Use go.") {
+			t.Errorf(".cursorrules = %q", data)
+		}
+		if _, err := os.Stat(filepath.Join(project.Path(), ".skill-hub")); !os.IsNotExist(err) {
+			t.Error("Disable() should remove installed resources")
+		}
+		if err := project.Disable("base"); err == nil {
+			t.Error("Disable() should fail for a skill that is not enabled")
+		}
+	})
+	t.Run("pinned revision with secret", func(t *testing.T) {
+		t.Setenv("SKILL_HUB_TEST_TOKEN", "s3cr3t")
+		pinned, err := hub.Project(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		variables := map[string]string{"LANGUAGE": "${env:SKILL_HUB_TEST_TOKEN}"}
+		if err := pinned.Enable("lang", variables); err != nil {
+			t.Fatalf("Enable() error = %v", err)
+		}
+		// 固定在仓库中已不存在的版本，只能使用应用记录，记录中的密钥已去除
+		if err := hub.state.RecordAppliedRevision(pinned.Path(), "lang", TargetCursor, spec.AppliedRevision{
+			Version: "1.0.0", Variables: variables, Content: "Use s3cr3t.\n",
+		}); err != nil {
+			t.Fatal(err)
+		}
+		lock, err := state.LoadLockFile(pinned.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		lock.Pin("lang", "1.0.0", "")
+		if err := lock.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := pinned.Apply(ApplyOptions{Target: TargetCursor, NoDeps: true}); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		data, _ := os.ReadFile(filepath.Join(pinned.Path(), ".cursorrules"))
+		if !strings.Contains(string(data), "Use s3cr3t.") || strings.Contains(string(data), "${env:") {
+			t.Errorf(".cursorrules = %q, want the secret expanded", data)
+		}
+	})
+
+	t.Run("pinned installed version", func(t *testing.T) {
+		// 固定的版本只安装在版本存储中，没有应用记录
+		old := filepath.Join(t.TempDir(), "lang")
+		writeFile(t, filepath.Join(old, "SKILL.md"),
+			"---\nname: lang\ndescription: Language rules\nversion: 1.1.0\nvariables:\n  - name: LANGUAGE\n    description: Language\n---\nOld {{.LANGUAGE}} rules.\n")
+		if _, err := hub.versions.Install("lang", "1.1.0", old); err != nil {
+			t.Fatalf("Install() error = %v", err)
+		}
+		pinned, err := hub.Project(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pinned.Enable("lang", map[string]string{"LANGUAGE": "go"}); err != nil {
+			t.Fatalf("Enable() error = %v", err)
+		}
+		lock, err := state.LoadLockFile(pinned.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		lock.Pin("lang", "1.1.0", "")
+		if err := lock.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := pinned.Apply(ApplyOptions{Target: TargetCursor, NoDeps: true})
+		if err != nil || len(result.Applied) != 1 || result.Applied[0].Version != "1.1.0" {
+			t.Fatalf("Apply() = %+v, %v", result, err)
+		}
+		data, _ := os.ReadFile(filepath.Join(pinned.Path(), ".cursorrules"))
+		if !strings.Contains(string(data), "Old go rules.") {
+			t.Errorf(".cursorrules = %q, want the installed 1.1.0 content", data)
+		}
+	})
+
+	t.Run("extra skills", func(t *testing.T) {
+		// 工作区成员项目：技能来自工作区状态，不在成员项目状态中
+		member, err := hub.Project(filepath.Join(project.Path(), "member"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(member.Path(), 0755); err != nil {
+			t.Fatal(err)
+		}
+		extra := map[string]EnabledSkill{"lang": {Version: "1.2.0", Variables: map[string]string{"LANGUAGE": "rust"}}}
+
+		result, err := member.Apply(ApplyOptions{Target: TargetCursor, NoDeps: true, DryRun: true, Skills: extra})
+		if err != nil || len(result.Applied) != 1 || !result.Applied[0].Changed {
+			t.Fatalf("Apply(dry run) = %+v, %v", result, err)
+		}
+		if skills, _ := member.Skills(); len(skills) != 0 {
+			t.Errorf("dry run should not enable extra skills: %v", skills)
+		}
+
+		if _, err := member.Apply(ApplyOptions{Target: TargetCursor, NoDeps: true, Skills: extra}); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		data, _ := os.ReadFile(filepath.Join(member.Path(), ".cursorrules"))
+		if !strings.Contains(string(data), "Use rust.") {
+			t.Errorf(".cursorrules = %q", data)
+		}
+		skills, _ := member.Skills()
+		if skills["lang"].Variables["LANGUAGE"] != "rust" || len(skills["lang"].History[TargetCursor]) != 1 {
+			t.Errorf("member skills = %+v", skills)
+		}
+	})
+}
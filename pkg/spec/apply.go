@@ -0,0 +1,44 @@
+package spec
+
+// 技能应用到一个目标工具的结果
+const (
+	ApplyApplied  = "applied"    // 已写入目标文件（预览时为将写入）
+	ApplyUpToDate = "up_to_date" // 目标文件中已是本次的内容，没有写入
+	ApplySkipped  = "skipped"    // 技能不支持该目标或未通过校验，没有应用
+	ApplyFailed   = "failed"     // 渲染或预览失败，没有应用
+)
+
+// ApplyReport 一次 apply 的结果，最近一次实际应用的结果保存在项目状态中，供 status 查看
+type ApplyReport struct {
+	AppliedAt string        `json:"applied_at"`
+	DryRun    bool          `json:"dry_run,omitempty"`
+	Mode      string        `json:"mode"`
+	Targets   []string      `json:"targets"`
+	Profile   string        `json:"profile,omitempty"`
+	Skills    []ApplyResult `json:"skills"`
+	Files     []string      `json:"files"` // 修改的目标文件（预览时为将修改的文件）
+}
+
+// ApplyResult 技能应用到一个目标工具的结果
+type ApplyResult struct {
+	SkillID   string `json:"skill_id"`
+	Target    string `json:"target"`
+	Version   string `json:"version,omitempty"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"` // 跳过或失败的原因
+	File      string `json:"file,omitempty"`
+	Hash      string `json:"hash,omitempty"` // 渲染内容（去除首尾空白）的 SHA-256
+	Merged    bool   `json:"merged,omitempty"`
+	Conflicts int    `json:"conflicts,omitempty"`
+}
+
+// Count 返回结果为 status 的技能数
+func (r *ApplyReport) Count(status string) int {
+	count := 0
+	for _, result := range r.Skills {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
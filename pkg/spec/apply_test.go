@@ -0,0 +1,18 @@
+package spec
+
+import "testing"
+
+func TestApplyReportCount(t *testing.T) {
+	report := &ApplyReport{Skills: []ApplyResult{
+		{SkillID: "a", Target: TargetCursor, Status: ApplyApplied},
+		{SkillID: "a", Target: TargetClaudeCode, Status: ApplyUpToDate},
+		{SkillID: "b", Target: TargetCursor, Status: ApplySkipped},
+		{SkillID: "b", Target: TargetClaudeCode, Status: ApplyApplied},
+	}}
+	counts := map[string]int{ApplyApplied: 2, ApplyUpToDate: 1, ApplySkipped: 1, ApplyFailed: 0}
+	for status, want := range counts {
+		if got := report.Count(status); got != want {
+			t.Errorf("Count(%s) = %d, want %d", status, got, want)
+		}
+	}
+}
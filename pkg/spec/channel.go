@@ -0,0 +1,43 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 技能发布通道：stable 为正式版本，beta 为预览版本。技能在 metadata.channel 中声明，未声明时为 stable
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+)
+
+// channelRanks 通道的稳定程度，订阅的通道接受不低于其稳定程度的版本：beta 通道同时接受 stable 版本
+var channelRanks = map[string]int{
+	ChannelStable: 0,
+	ChannelBeta:   1,
+}
+
+// NormalizeChannel 规范化通道名称，空值为 stable
+func NormalizeChannel(channel string) (string, error) {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	if channel == "" {
+		return ChannelStable, nil
+	}
+	if _, ok := channelRanks[channel]; !ok {
+		return "", fmt.Errorf("无效的通道: %s，可用通道: %s, %s", channel, ChannelStable, ChannelBeta)
+	}
+	return channel, nil
+}
+
+// ChannelAccepts 检查订阅的通道是否接受发布在 channel 中的版本，无效的通道名称视为不接受
+func ChannelAccepts(subscribed, channel string) bool {
+	subscribed, err := NormalizeChannel(subscribed)
+	if err != nil {
+		return false
+	}
+	channel, err = NormalizeChannel(channel)
+	if err != nil {
+		return false
+	}
+	return channelRanks[channel] <= channelRanks[subscribed]
+}
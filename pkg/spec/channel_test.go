@@ -0,0 +1,43 @@
+package spec
+
+import "testing"
+
+func TestNormalizeChannel(t *testing.T) {
+	tests := []struct {
+		channel string
+		want    string
+		valid   bool
+	}{
+		{"", ChannelStable, true},
+		{"stable", ChannelStable, true},
+		{" Beta ", ChannelBeta, true},
+		{"nightly", "", false},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeChannel(tt.channel)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("NormalizeChannel(%q) = %q, %v, want %q, valid %v", tt.channel, got, err, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestChannelAccepts(t *testing.T) {
+	tests := []struct {
+		subscribed string
+		channel    string
+		want       bool
+	}{
+		{ChannelStable, "", true},
+		{ChannelStable, ChannelStable, true},
+		{ChannelStable, ChannelBeta, false},
+		{"", ChannelBeta, false},
+		{ChannelBeta, ChannelStable, true},
+		{ChannelBeta, ChannelBeta, true},
+		{ChannelBeta, "nightly", false},
+	}
+	for _, tt := range tests {
+		if got := ChannelAccepts(tt.subscribed, tt.channel); got != tt.want {
+			t.Errorf("ChannelAccepts(%q, %q) = %v, want %v", tt.subscribed, tt.channel, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Compatibility 技能兼容的目标工具，键为适配器名称（cursor、claude_code、open_code、shell 等）
+//
+// SKILL.md 中的 compatibility 按 Agent Skills 规范是一段说明文字，Compatibility 是从中识别出的目标工具；
+// 旧版的对象格式 {cursor: true, claude_code: true} 也解码为 Compatibility。
+type Compatibility map[string]bool
+
+// compatibilityTarget 目标工具在兼容性说明中的显示名称和写法
+type compatibilityTarget struct {
+	name    string
+	display string
+	aliases []string // 小写，出现在说明文字中即视为兼容
+	exact   bool     // 只在说明文字的某一项与别名完全相同时视为兼容
+}
+
+// compatibilityTargets 已知的目标工具，按显示顺序排列，新适配器通过 RegisterCompatibility 添加
+var compatibilityTargets = []compatibilityTarget{
+	{TargetCursor, "Cursor", []string{"cursor"}, false},
+	{TargetClaudeCode, "Claude Code", []string{"claude code", "claude_code", "claude-code", "claude"}, false},
+	{TargetOpenCode, "OpenCode", []string{"opencode", "open_code"}, false},
+	// shell 会把技能安装为可执行脚本，必须在列表中单独写出，"Requires a POSIX shell" 之类的描述不算声明
+	{TargetShell, "Shell", []string{"shell"}, true},
+}
+
+// RegisterCompatibility 注册新的目标工具，display 为生成兼容性说明时的名称，aliases 为说明文字中的写法
+//
+// 已注册的目标工具会被覆盖。aliases 为空时使用 name 和 display。
+func RegisterCompatibility(name, display string, aliases ...string) {
+	if len(aliases) == 0 {
+		aliases = []string{name, display}
+	}
+	target := compatibilityTarget{name: name, display: display}
+	for _, alias := range aliases {
+		target.aliases = append(target.aliases, strings.ToLower(alias))
+	}
+	for i := range compatibilityTargets {
+		if compatibilityTargets[i].name == name {
+			compatibilityTargets[i] = target
+			return
+		}
+	}
+	compatibilityTargets = append(compatibilityTargets, target)
+}
+
+// ParseCompatibility 从兼容性说明文字中识别目标工具，例如
+// "Designed for Cursor and Claude Code" 识别为 cursor 和 claude_code
+//
+// 需要精确匹配的目标工具（shell）只在列表中某一项与别名完全相同时识别，
+// 例如 "Designed for Cursor and Shell" 识别 shell，"Requires a POSIX shell" 不识别。
+func ParseCompatibility(text string) Compatibility {
+	textLower := strings.ToLower(text)
+	items := compatibilityItems(textLower)
+	compat := Compatibility{}
+	for _, target := range compatibilityTargets {
+		for _, alias := range target.aliases {
+			if target.exact && items[alias] || !target.exact && strings.Contains(textLower, alias) {
+				compat[target.name] = true
+				break
+			}
+		}
+	}
+	return compat
+}
+
+// compatibilityItems 把小写的兼容性说明拆分为目标工具列表项
+//
+// 去掉括号中的补充说明和开头的 "designed for"，按逗号、分号、斜线、竖线以及 and/or 拆分。
+func compatibilityItems(text string) map[string]bool {
+	var b strings.Builder
+	depth := 0
+	for _, r := range text {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	text = strings.TrimSpace(b.String())
+	text = strings.TrimPrefix(text, "designed for ")
+	items := make(map[string]bool)
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(",;/|", r)
+	}) {
+		words := strings.Fields(part)
+		start := 0
+		for i := 0; i <= len(words); i++ {
+			if i == len(words) || words[i] == "and" || words[i] == "or" {
+				if item := strings.Trim(strings.Join(words[start:i], " "), ".:!"); item != "" {
+					items[item] = true
+				}
+				start = i + 1
+			}
+		}
+	}
+	return items
+}
+
+// CompatibilityFromValue 解析 frontmatter 中已解码的 compatibility 字段
+//
+// 字符串按说明文字识别，对象格式按键读取布尔值，键使用 NormalizeTarget 规范化。
+func CompatibilityFromValue(value interface{}) Compatibility {
+	switch v := value.(type) {
+	case string:
+		return ParseCompatibility(v)
+	case map[string]interface{}:
+		compat := Compatibility{}
+		for key, enabled := range v {
+			if b, ok := enabled.(bool); ok {
+				compat[NormalizeTarget(strings.ToLower(key))] = b
+			}
+		}
+		return compat
+	}
+	return Compatibility{}
+}
+
+// CompatibilityDescription 返回 compatibility 字段的说明文字，对象格式转换为 "Designed for ..." 说明
+func CompatibilityDescription(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return CompatibilityFromValue(value).String()
+}
+
+// UnmarshalYAML 支持说明文字和旧版对象格式两种写法
+func (c *Compatibility) UnmarshalYAML(node *yaml.Node) error {
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return fmt.Errorf("解析compatibility失败: %w", err)
+	}
+	switch value.(type) {
+	case nil, string, map[string]interface{}:
+		*c = CompatibilityFromValue(value)
+		return nil
+	}
+	return fmt.Errorf("compatibility 必须是字符串或对象")
+}
+
+// Supports 检查是否兼容目标工具
+func (c Compatibility) Supports(target string) bool {
+	return c[NormalizeTarget(target)]
+}
+
+// Targets 返回兼容的目标工具，已注册的按注册顺序在前，其余按名称排序
+func (c Compatibility) Targets() []string {
+	var targets []string
+	known := make(map[string]bool)
+	for _, target := range compatibilityTargets {
+		known[target.name] = true
+		if c[target.name] {
+			targets = append(targets, target.name)
+		}
+	}
+	var others []string
+	for name, enabled := range c {
+		if enabled && !known[name] {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	return append(targets, others...)
+}
+
+// String 生成兼容性说明文字，没有兼容的目标工具时返回空字符串
+func (c Compatibility) String() string {
+	var names []string
+	for _, name := range c.Targets() {
+		display := name
+		for _, target := range compatibilityTargets {
+			if target.name == name {
+				display = target.display
+				break
+			}
+		}
+		names = append(names, display)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "Designed for " + strings.Join(names, ", ") + " (or similar AI coding assistants)"
+}
+
+// Compatible 返回技能兼容的目标工具
+func (s *Skill) Compatible() Compatibility {
+	return ParseCompatibility(s.Compatibility)
+}
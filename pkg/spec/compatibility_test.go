@@ -0,0 +1,85 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseCompatibility(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Designed for Claude Code, Cursor, and OpenCode (or similar AI coding assistants)", "cursor,claude_code,open_code"},
+		{"cursor only", "cursor"},
+		{"Requires a POSIX shell", ""},
+		{"Cursor; works with any shell", "cursor"},
+		{"Designed for Shell", "shell"},
+		{"Designed for Cursor, Shell (or similar AI coding assistants)", "cursor,shell"},
+		{"cursor / shell", "cursor,shell"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(ParseCompatibility(tt.text).Targets(), ","); got != tt.want {
+			t.Errorf("ParseCompatibility(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCompatibilityYAML(t *testing.T) {
+	var doc struct {
+		Compatibility Compatibility `yaml:"compatibility"`
+	}
+
+	// 旧版对象格式，键兼容 claude/opencode 写法
+	if err := yaml.Unmarshal([]byte("compatibility:\n  cursor: true\n  claude: true\n  opencode: false\n  windsurf: true\n"), &doc); err != nil {
+		t.Fatalf("Unmarshal(object) error = %v", err)
+	}
+	if !doc.Compatibility.Supports(TargetCursor) || !doc.Compatibility.Supports(TargetClaudeCode) || doc.Compatibility.Supports(TargetOpenCode) {
+		t.Errorf("object compatibility = %v", doc.Compatibility)
+	}
+	if got := strings.Join(doc.Compatibility.Targets(), ","); got != "cursor,claude_code,windsurf" {
+		t.Errorf("Targets() = %q", got)
+	}
+	if got := doc.Compatibility.String(); got != "Designed for Cursor, Claude Code, windsurf (or similar AI coding assistants)" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if err := yaml.Unmarshal([]byte("compatibility: Designed for OpenCode\n"), &doc); err != nil {
+		t.Fatalf("Unmarshal(string) error = %v", err)
+	}
+	if got := strings.Join(doc.Compatibility.Targets(), ","); got != "open_code" {
+		t.Errorf("string compatibility = %q", got)
+	}
+
+	if err := yaml.Unmarshal([]byte("compatibility: [cursor]\n"), &doc); err == nil {
+		t.Error("Unmarshal(list) should fail")
+	}
+}
+
+func TestRegisterCompatibility(t *testing.T) {
+	saved := append([]compatibilityTarget(nil), compatibilityTargets...)
+	defer func() { compatibilityTargets = saved }()
+
+	RegisterCompatibility("windsurf", "Windsurf")
+	compat := ParseCompatibility("Designed for Cursor and Windsurf")
+	if !compat.Supports("windsurf") || !compat.Supports(TargetCursor) {
+		t.Errorf("ParseCompatibility() = %v", compat)
+	}
+	if got := compat.String(); got != "Designed for Cursor, Windsurf (or similar AI coding assistants)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestSkillSupportsTarget(t *testing.T) {
+	undeclared := &Skill{}
+	if !undeclared.SupportsTarget(TargetCursor) || undeclared.SupportsTarget(TargetShell) {
+		t.Error("skills without compatibility should support every target except shell")
+	}
+	declared := &Skill{Compatibility: "Designed for Cursor and Shell"}
+	if !declared.SupportsTarget(TargetShell) || declared.SupportsTarget(TargetClaude) {
+		t.Errorf("SupportsTarget() mismatch for %q", declared.Compatibility)
+	}
+}
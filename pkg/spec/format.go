@@ -0,0 +1,181 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 规范格式中字段的顺序，未列出的字段按原顺序排在已知字段之后
+var (
+	// skillMDFieldOrder 与 MarshalSkillMD 的输出顺序一致
+	skillMDFieldOrder = []string{"name", "description", "license", "compatibility", "allowed-tools", "metadata", "requires", "variables", "claude"}
+	// skillYAMLFieldOrder 与 Skill 的字段顺序一致
+	skillYAMLFieldOrder = []string{"id", "name", "version", "author", "description", "tags", "compatibility", "license", "allowed-tools", "variables", "dependencies", "requires", "claude"}
+
+	variableFieldOrder     = []string{"name", "default", "description", "type", "required", "pattern", "options"}
+	requirementsFieldOrder = []string{"skill_hub", "os"}
+	claudeFieldOrder       = []string{"mode", "runtime", "entrypoint", "tool_spec"}
+	toolSpecFieldOrder     = []string{"name", "description", "input_schema"}
+)
+
+// FormatSkillMD 将 SKILL.md 格式化为规范格式
+//
+// frontmatter 按固定顺序排列字段（metadata 中的字段按字母顺序），缩进为两个空格，
+// 字符串只在必要时加引号，标量列表使用 [a, b] 形式；注释和未知字段保留。
+// 正文只去掉开头的空行并保证以单个换行结尾。
+func FormatSkillMD(content []byte) ([]byte, error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	if len(lines) < 2 || lines[0] != "---" {
+		return nil, fmt.Errorf("无效的SKILL.md格式: 缺少frontmatter")
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "---" {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return nil, fmt.Errorf("无效的SKILL.md格式: frontmatter没有正确结束")
+	}
+
+	frontmatter, err := formatYAMLDocument([]byte(strings.Join(lines[1:end], "\n")+"\n"), skillMDFieldOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(frontmatter)
+	buf.WriteString("---\n")
+	body := strings.TrimRight(strings.TrimLeft(strings.Join(lines[end+1:], "\n"), "\n"), "\n")
+	if body != "" {
+		buf.WriteString(body)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// FormatSkillYAML 将旧格式的 skill.yaml 格式化为规范格式，规则与 FormatSkillMD 的 frontmatter 相同
+func FormatSkillYAML(content []byte) ([]byte, error) {
+	return formatYAMLDocument(bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n")), skillYAMLFieldOrder)
+}
+
+// formatYAMLDocument 按 order 重新排列根对象的字段并以规范样式输出
+func formatYAMLDocument(data []byte, order []string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析YAML失败: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("frontmatter必须是YAML对象")
+	}
+
+	orderMapping(root, order)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i].Value, root.Content[i+1]
+		switch key {
+		case "metadata":
+			if value.Kind == yaml.MappingNode {
+				sortMapping(value)
+			}
+		case "variables":
+			if value.Kind == yaml.SequenceNode {
+				for _, item := range value.Content {
+					orderMapping(item, variableFieldOrder)
+				}
+			}
+		case "requires":
+			orderMapping(value, requirementsFieldOrder)
+		case "claude":
+			orderMapping(value, claudeFieldOrder)
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				if value.Content[j].Value == "tool_spec" {
+					orderMapping(value.Content[j+1], toolSpecFieldOrder)
+				}
+			}
+		}
+	}
+	normalizeStyle(root)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("序列化YAML失败: %w", err)
+	}
+	encoder.Close()
+	return buf.Bytes(), nil
+}
+
+// orderMapping 按 order 排列对象的字段，未列出的字段保持原顺序排在最后
+func orderMapping(node *yaml.Node, order []string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	rank := make(map[string]int, len(order))
+	for i, key := range order {
+		rank[key] = i
+	}
+	sortPairs(node, func(a, b string) bool {
+		ra, knownA := rank[a]
+		rb, knownB := rank[b]
+		if knownA && knownB {
+			return ra < rb
+		}
+		return knownA && !knownB
+	})
+}
+
+// sortMapping 按字母顺序排列对象的字段
+func sortMapping(node *yaml.Node) {
+	sortPairs(node, func(a, b string) bool { return a < b })
+}
+
+// sortPairs 按键稳定排序对象的键值对
+func sortPairs(node *yaml.Node, less func(a, b string) bool) {
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return less(pairs[i][0].Value, pairs[j][0].Value)
+	})
+	node.Content = node.Content[:0]
+	for _, pair := range pairs {
+		node.Content = append(node.Content, pair[0], pair[1])
+	}
+}
+
+// normalizeStyle 统一样式：对象使用块格式，标量列表使用流格式，字符串只在必要时加引号
+func normalizeStyle(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		// 多行文本保留 | 和 > 块格式
+		if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			node.Style = 0
+		}
+	case yaml.MappingNode:
+		node.Style = 0
+	case yaml.SequenceNode:
+		node.Style = yaml.FlowStyle
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode || strings.Contains(item.Value, "\n") || item.HeadComment != "" || item.LineComment != "" {
+				node.Style = 0
+				break
+			}
+		}
+	}
+	for _, child := range node.Content {
+		normalizeStyle(child)
+	}
+}
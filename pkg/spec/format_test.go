@@ -0,0 +1,99 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatSkillMD(t *testing.T) {
+	input := "---\r\n" +
+		"metadata:\r\n" +
+		"    version: \"1.0\"\r\n" +
+		"    author: 'team'\r\n" +
+		"description: \"Git 提交专家\"\r\n" +
+		"custom: keep\r\n" +
+		"name: git-expert\r\n" +
+		"variables:\r\n" +
+		"  - description: 输出语言\r\n" +
+		"    name: LANGUAGE\r\n" +
+		"    options:\r\n" +
+		"      - zh\r\n" +
+		"      - en\r\n" +
+		"# 运行要求\r\n" +
+		"requires: {os: [linux], skill_hub: \">=0.5\"}\r\n" +
+		"---\r\n" +
+		"\r\n" +
+		"# Git\r\n" +
+		"\r\n\r\n"
+
+	want := "---\n" +
+		"name: git-expert\n" +
+		"description: Git 提交专家\n" +
+		"metadata:\n" +
+		"  author: team\n" +
+		"  version: \"1.0\"\n" +
+		"# 运行要求\n" +
+		"requires:\n" +
+		"  skill_hub: '>=0.5'\n" +
+		"  os: [linux]\n" +
+		"variables:\n" +
+		"  - name: LANGUAGE\n" +
+		"    description: 输出语言\n" +
+		"    options: [zh, en]\n" +
+		"custom: keep\n" +
+		"---\n" +
+		"# Git\n"
+
+	got, err := FormatSkillMD([]byte(input))
+	if err != nil {
+		t.Fatalf("FormatSkillMD() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("FormatSkillMD() =\n%s\nwant:\n%s", got, want)
+	}
+
+	again, err := FormatSkillMD(got)
+	if err != nil || string(again) != string(got) {
+		t.Errorf("FormatSkillMD() is not idempotent:\n%s", again)
+	}
+
+	if _, err := FormatSkillMD([]byte("# 没有frontmatter\n")); err == nil {
+		t.Error("expected error for missing frontmatter")
+	}
+}
+
+func TestFormatSkillMDMatchesMarshal(t *testing.T) {
+	skill := &Skill{
+		ID:          "acme/git-expert",
+		Name:        "Git Expert",
+		Version:     "1.2.0",
+		Description: "生成提交说明",
+		License:     "MIT",
+		Requires:    &Requirements{SkillHub: ">=0.5"},
+	}
+	content, err := MarshalSkillMD(skill, "# Git", SkillMDOptions{})
+	if err != nil {
+		t.Fatalf("MarshalSkillMD() error = %v", err)
+	}
+	formatted, err := FormatSkillMD(content)
+	if err != nil {
+		t.Fatalf("FormatSkillMD() error = %v", err)
+	}
+	if string(formatted) != string(content) {
+		t.Errorf("MarshalSkillMD output should already be canonical:\n%s\nformatted:\n%s", content, formatted)
+	}
+}
+
+func TestFormatSkillYAML(t *testing.T) {
+	got, err := FormatSkillYAML([]byte("tags:\n- git\nversion: 1.0.0\nid: git-expert\nname: Git Expert\n"))
+	if err != nil {
+		t.Fatalf("FormatSkillYAML() error = %v", err)
+	}
+	want := "id: git-expert\nname: Git Expert\nversion: 1.0.0\ntags: [git]\n"
+	if string(got) != want {
+		t.Errorf("FormatSkillYAML() = %q, want %q", got, want)
+	}
+	if _, err := FormatSkillYAML([]byte("- a\n- b\n")); err == nil || !strings.Contains(err.Error(), "对象") {
+		t.Errorf("expected error for non-object document, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NamespaceSeparator 命名空间与技能名称之间的分隔符，例如 acme/git-expert
+const NamespaceSeparator = "/"
+
+// skillNamePattern 技能名称和命名空间的格式：小写字母、数字和连字符，不以连字符开头或结尾
+var skillNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// SplitSkillID 将技能ID拆分为命名空间和名称，没有命名空间时 namespace 为空
+func SplitSkillID(id string) (namespace, name string) {
+	if i := strings.Index(id, NamespaceSeparator); i >= 0 {
+		return id[:i], id[i+1:]
+	}
+	return "", id
+}
+
+// JoinSkillID 组合命名空间和名称，namespace 为空时直接返回名称
+func JoinSkillID(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + NamespaceSeparator + name
+}
+
+// ValidateSkillID 检查技能ID格式，支持 name 和 namespace/name 两种形式
+func ValidateSkillID(id string) error {
+	namespace, name := SplitSkillID(id)
+	if strings.Contains(id, NamespaceSeparator) && !skillNamePattern.MatchString(namespace) {
+		return fmt.Errorf("无效的命名空间 '%s'：只能包含小写字母、数字和连字符，且不能以连字符开头或结尾", namespace)
+	}
+	if !skillNamePattern.MatchString(name) {
+		return fmt.Errorf("无效的技能ID '%s'：只能包含小写字母、数字和连字符，且不能以连字符开头或结尾，命名空间格式为 namespace/name", id)
+	}
+	return nil
+}
+
+// FlatSkillID 返回不含分隔符的技能ID，用于只能使用单个文件名或目录名的目标，
+// 例如 acme/git-expert 变为 acme-git-expert
+func FlatSkillID(id string) string {
+	return strings.ReplaceAll(id, NamespaceSeparator, "-")
+}
+
+// CheckFlatSkillOwner 检查扁平化名称是否属于技能 skillID，owner 为该名称下已有内容记录的技能ID，为空表示没有记录
+//
+// acme/git-expert 与 acme-git-expert 扁平化后相同，已被其他技能占用时返回错误，不能互相覆盖或删除。
+func CheckFlatSkillOwner(skillID, owner string) error {
+	if owner == "" || owner == skillID {
+		return nil
+	}
+	return fmt.Errorf("技能 %s 与 %s 使用相同的名称 %s，已被 %s 占用", skillID, owner, FlatSkillID(skillID), owner)
+}
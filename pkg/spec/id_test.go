@@ -0,0 +1,67 @@
+package spec
+
+import "testing"
+
+func TestSplitSkillID(t *testing.T) {
+	tests := []struct {
+		id, namespace, name string
+	}{
+		{"git-expert", "", "git-expert"},
+		{"acme/git-expert", "acme", "git-expert"},
+	}
+	for _, tt := range tests {
+		namespace, name := SplitSkillID(tt.id)
+		if namespace != tt.namespace || name != tt.name {
+			t.Errorf("SplitSkillID(%q) = (%q, %q), want (%q, %q)", tt.id, namespace, name, tt.namespace, tt.name)
+		}
+		if got := JoinSkillID(namespace, name); got != tt.id {
+			t.Errorf("JoinSkillID(%q, %q) = %q, want %q", namespace, name, got, tt.id)
+		}
+	}
+}
+
+func TestValidateSkillID(t *testing.T) {
+	tests := []struct {
+		id      string
+		wantErr bool
+	}{
+		{"git-expert", false},
+		{"acme/git-expert", false},
+		{"team-1/skill2", false},
+		{"", true},
+		{"Git-Expert", true},
+		{"acme/", true},
+		{"/git-expert", true},
+		{"a/b/c", true},
+		{"acme/../x", true},
+		{"-acme/git", true},
+		{"acme_corp/git", true},
+	}
+	for _, tt := range tests {
+		err := ValidateSkillID(tt.id)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSkillID(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFlatSkillID(t *testing.T) {
+	if got := FlatSkillID("acme/git-expert"); got != "acme-git-expert" {
+		t.Errorf("FlatSkillID() = %q", got)
+	}
+	if got := FlatSkillID("git-expert"); got != "git-expert" {
+		t.Errorf("FlatSkillID() = %q", got)
+	}
+}
+
+func TestCheckFlatSkillOwner(t *testing.T) {
+	if err := CheckFlatSkillOwner("acme/git-expert", ""); err != nil {
+		t.Errorf("CheckFlatSkillOwner() without owner error = %v", err)
+	}
+	if err := CheckFlatSkillOwner("acme/git-expert", "acme/git-expert"); err != nil {
+		t.Errorf("CheckFlatSkillOwner() same owner error = %v", err)
+	}
+	if err := CheckFlatSkillOwner("acme/git-expert", "acme-git-expert"); err == nil {
+		t.Error("CheckFlatSkillOwner() should reject acme-git-expert owning acme/git-expert's name")
+	}
+}
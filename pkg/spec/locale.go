@@ -0,0 +1,88 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 技能提示词语言版本的文件名
+const (
+	localeFilePrefix       = "SKILL."
+	legacyLocaleFilePrefix = "prompt."
+	localeFileSuffix       = ".md"
+)
+
+// localePattern BCP 47 风格的语言标签，例如 zh、zh-CN、pt-BR、zh-Hant-TW
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// NormalizeLocale 规范化语言标签：下划线替换为连字符，语言小写、地区大写，例如 zh_cn 规范化为 zh-CN
+func NormalizeLocale(locale string) (string, error) {
+	locale = strings.TrimSpace(locale)
+	if locale == "" {
+		return "", nil
+	}
+	parts := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			parts[i] = strings.ToUpper(parts[i])
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		}
+	}
+	normalized := strings.Join(parts, "-")
+	if !localePattern.MatchString(normalized) {
+		return "", fmt.Errorf("无效的语言: %s，格式如 zh-CN、en", locale)
+	}
+	return normalized, nil
+}
+
+// LocaleFallbacks 返回依次尝试的语言标签，从完整标签逐级去掉最后一段，例如 zh-Hant-TW -> zh-Hant-TW, zh-Hant, zh
+func LocaleFallbacks(locale string) []string {
+	var candidates []string
+	for locale != "" {
+		candidates = append(candidates, locale)
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return candidates
+}
+
+// LocaleFileName 返回技能提示词语言版本的文件名，例如 zh-CN 对应 SKILL.zh-CN.md
+//
+// 语言版本与 SKILL.md 放在同一目录，只替换 SKILL.md 的正文，frontmatter 始终以 SKILL.md 为准。
+func LocaleFileName(locale string) string {
+	return localeFilePrefix + locale + localeFileSuffix
+}
+
+// ParseLocaleFileName 从 SKILL.<语言>.md 中取出语言
+func ParseLocaleFileName(name string) (string, bool) {
+	return parseLocaleFileName(name, localeFilePrefix)
+}
+
+// parseLocaleFileName 从 <prefix><语言>.md 中取出语言
+func parseLocaleFileName(name, prefix string) (string, bool) {
+	if len(name) <= len(prefix)+len(localeFileSuffix) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, localeFileSuffix) {
+		return "", false
+	}
+	locale := strings.TrimSuffix(strings.TrimPrefix(name, prefix), localeFileSuffix)
+	if normalized, err := NormalizeLocale(locale); err != nil || normalized == "" {
+		return "", false
+	}
+	return locale, true
+}
+
+// ParseLegacyLocaleFileName 从旧格式的 prompt.<语言>.md 中取出语言
+func ParseLegacyLocaleFileName(name string) (string, bool) {
+	return parseLocaleFileName(name, legacyLocaleFilePrefix)
+}
+
+// LegacyLocaleFileName 返回旧格式技能中提示词语言版本的文件名，例如 prompt.zh-CN.md
+func LegacyLocaleFileName(locale string) string {
+	return legacyLocaleFilePrefix + locale + localeFileSuffix
+}
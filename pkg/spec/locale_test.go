@@ -0,0 +1,40 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"zh_cn", "zh-CN", false},
+		{"EN", "en", false},
+		{"zh-hant-tw", "zh-Hant-TW", false},
+		{"", "", false},
+		{"chinese!", "", true},
+		{"z", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeLocale(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeLocale(%q) = %q, %v", tt.input, got, err)
+		}
+	}
+
+	if got := strings.Join(LocaleFallbacks("zh-Hant-TW"), ","); got != "zh-Hant-TW,zh-Hant,zh" {
+		t.Errorf("LocaleFallbacks() = %q", got)
+	}
+	if locale, ok := ParseLocaleFileName(LocaleFileName("zh-CN")); !ok || locale != "zh-CN" {
+		t.Errorf("ParseLocaleFileName() = %q, %v", locale, ok)
+	}
+	if _, ok := ParseLocaleFileName("SKILL.md"); ok {
+		t.Error("SKILL.md is not a locale variant")
+	}
+	if locale, ok := ParseLegacyLocaleFileName("prompt.en.md"); !ok || locale != "en" {
+		t.Errorf("ParseLegacyLocaleFileName() = %q, %v", locale, ok)
+	}
+}
@@ -0,0 +1,64 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseRegistry 解析 registry.json，兼容旧版本 update 以YAML格式写入的注册表
+func ParseRegistry(data []byte) (*Registry, error) {
+	var registry Registry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		if yamlErr := yaml.Unmarshal(data, &registry); yamlErr != nil {
+			return nil, fmt.Errorf("解析registry.json失败: %w", err)
+		}
+	}
+	return &registry, nil
+}
+
+// Find 查找技能的索引信息
+func (r *Registry) Find(skillID string) (*SkillMetadata, bool) {
+	for i := range r.Skills {
+		if r.Skills[i].ID == skillID {
+			return &r.Skills[i], true
+		}
+	}
+	return nil, false
+}
+
+// CarryStats 从原注册表复制技能的使用统计，重新生成注册表时使用，避免丢失远程仓库维护的数据
+func (r *Registry) CarryStats(previous *Registry) {
+	if previous == nil {
+		return
+	}
+	for i := range r.Skills {
+		if old, ok := previous.Find(r.Skills[i].ID); ok {
+			r.Skills[i].Downloads = old.Downloads
+			r.Skills[i].Rating = old.Rating
+			r.Skills[i].RatingCount = old.RatingCount
+		}
+	}
+}
+
+// Upsert 添加或替换技能的索引信息，替换时保留原有的使用统计，新技能追加到末尾
+func (r *Registry) Upsert(meta SkillMetadata) {
+	if old, ok := r.Find(meta.ID); ok {
+		meta.Downloads, meta.Rating, meta.RatingCount = old.Downloads, old.Rating, old.RatingCount
+		*old = meta
+		return
+	}
+	r.Skills = append(r.Skills, meta)
+}
+
+// RatingText 返回评分的显示文本，例如 4.5 (12)，没有评分时为 -
+func (m *SkillMetadata) RatingText() string {
+	if m.RatingCount == 0 && m.Rating == 0 {
+		return "-"
+	}
+	if m.RatingCount == 0 {
+		return fmt.Sprintf("%.1f", m.Rating)
+	}
+	return fmt.Sprintf("%.1f (%d)", m.Rating, m.RatingCount)
+}
@@ -0,0 +1,56 @@
+package spec
+
+import "testing"
+
+func TestRegistryStats(t *testing.T) {
+	previous, err := ParseRegistry([]byte(`{
+  "version": "1.0.0",
+  "skills": [
+    {"id": "git-expert", "version": "1.0.0", "downloads": 120, "rating": 4.5, "rating_count": 12},
+    {"id": "removed", "downloads": 3}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("ParseRegistry() error = %v", err)
+	}
+
+	registry := Registry{Version: "1.0.0", Skills: []SkillMetadata{
+		{ID: "git-expert", Version: "1.1.0"},
+		{ID: "new-skill", Version: "0.1.0"},
+	}}
+	registry.CarryStats(previous)
+
+	git, _ := registry.Find("git-expert")
+	if git.Version != "1.1.0" || git.Downloads != 120 || git.RatingText() != "4.5 (12)" {
+		t.Errorf("git-expert = %+v", git)
+	}
+	fresh, _ := registry.Find("new-skill")
+	if fresh.Downloads != 0 || fresh.RatingText() != "-" {
+		t.Errorf("new-skill = %+v", fresh)
+	}
+	if _, ok := registry.Find("removed"); ok {
+		t.Error("CarryStats() should not add skills missing from the new registry")
+	}
+
+	t.Run("YAML registry", func(t *testing.T) {
+		legacy, err := ParseRegistry([]byte("version: \"1.0\"\nskills:\n  - id: demo\n    downloads: 7\n"))
+		if err != nil {
+			t.Fatalf("ParseRegistry() error = %v", err)
+		}
+		if demo, ok := legacy.Find("demo"); !ok || demo.Downloads != 7 {
+			t.Errorf("Find(demo) = %+v, %v", demo, ok)
+		}
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		registry.Upsert(SkillMetadata{ID: "git-expert", Version: "1.2.0"})
+		registry.Upsert(SkillMetadata{ID: "published", Version: "0.1.0"})
+		if len(registry.Skills) != 3 || registry.Skills[2].ID != "published" {
+			t.Fatalf("Upsert() skills = %+v", registry.Skills)
+		}
+		git, _ := registry.Find("git-expert")
+		if git.Version != "1.2.0" || git.Downloads != 120 || git.RatingCount != 12 {
+			t.Errorf("Upsert() should keep stats, git-expert = %+v", git)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Requirements 技能的运行要求，在 SKILL.md 中声明为
+//
+//	requires:
+//	  skill_hub: ">=0.5"
+//	  os: [linux, darwin]
+//
+// 包含平台相关脚本的技能通过 os 限制可用的操作系统，避免在不支持的系统上静默失效。
+type Requirements struct {
+	SkillHub string   `yaml:"skill_hub,omitempty" json:"skill_hub,omitempty"` // skill-hub 的版本约束，语法与依赖的版本约束相同
+	OS       []string `yaml:"os,omitempty" json:"os,omitempty"`               // 支持的操作系统，取值同 GOOS，为空表示不限
+}
+
+// osAliases 操作系统的常见写法，统一为 GOOS 的取值
+var osAliases = map[string]string{
+	"macos": "darwin",
+	"osx":   "darwin",
+	"mac":   "darwin",
+	"win":   "windows",
+}
+
+// NormalizeOS 规范化操作系统名称，例如 macOS 规范化为 darwin
+func NormalizeOS(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := osAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// SupportsOS 检查是否支持操作系统 goos，未声明 os 时支持所有系统
+func (r *Requirements) SupportsOS(goos string) bool {
+	if r == nil || len(r.OS) == 0 {
+		return true
+	}
+	goos = NormalizeOS(goos)
+	for _, name := range r.OS {
+		if NormalizeOS(name) == goos {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRequirements 解析 frontmatter 中的 requires，os 支持列表和逗号分隔的字符串
+func ParseRequirements(value interface{}) (*Requirements, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("requires 必须是对象，例如 {skill_hub: \">=0.5\", os: [linux, darwin]}")
+	}
+
+	req := &Requirements{}
+	if v, ok := data["skill_hub"]; ok && v != nil {
+		req.SkillHub = strings.TrimSpace(fmt.Sprint(v))
+	}
+	switch v := data["os"].(type) {
+	case nil:
+	case string:
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				req.OS = append(req.OS, name)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if name := strings.TrimSpace(fmt.Sprint(item)); name != "" {
+				req.OS = append(req.OS, name)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("requires.os 必须是操作系统列表，例如 [linux, darwin]")
+	}
+
+	if req.SkillHub == "" && len(req.OS) == 0 {
+		return nil, nil
+	}
+	return req, nil
+}
@@ -9,8 +9,13 @@ type Skill struct {
 	Description   string        `yaml:"description" json:"description"`
 	Tags          []string      `yaml:"tags" json:"tags"`
 	Compatibility string        `yaml:"compatibility,omitempty" json:"compatibility,omitempty"`
+	License       string        `yaml:"license,omitempty" json:"license,omitempty"`
+	Channel       string        `yaml:"channel,omitempty" json:"channel,omitempty"`             // 发布通道：stable（默认）或 beta
+	AllowedTools  []string      `yaml:"allowed-tools,omitempty" json:"allowed_tools,omitempty"` // 技能预先授权的工具，例如 Bash(git:*) Read
+	Priority      int           `yaml:"priority,omitempty" json:"priority,omitempty"`           // 应用顺序优先级，数值大的技能排在目标文件前面，默认 0
 	Variables     []Variable    `yaml:"variables" json:"variables"`
 	Dependencies  []string      `yaml:"dependencies" json:"dependencies"`
+	Requires      *Requirements `yaml:"requires,omitempty" json:"requires,omitempty"`
 	Claude        *ClaudeConfig `yaml:"claude,omitempty" json:"claude,omitempty"`
 }
 
@@ -31,9 +36,13 @@ type ToolSpec struct {
 
 // Variable 表示技能模板中的变量
 type Variable struct {
-	Name        string `yaml:"name" json:"name"`
-	Default     string `yaml:"default" json:"default"`
-	Description string `yaml:"description" json:"description"`
+	Name        string   `yaml:"name" json:"name"`
+	Default     string   `yaml:"default" json:"default"`
+	Description string   `yaml:"description" json:"description"`
+	Type        string   `yaml:"type,omitempty" json:"type,omitempty"`         // string（默认）、int、bool、enum
+	Required    *bool    `yaml:"required,omitempty" json:"required,omitempty"` // 未声明时，没有默认值的变量必须输入
+	Pattern     string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`   // 值需完整匹配的正则表达式
+	Options     []string `yaml:"options,omitempty" json:"options,omitempty"`   // 可选值，enum 类型必须声明
 }
 
 // SkillMetadata 用于技能索引的简化信息
@@ -45,6 +54,15 @@ type SkillMetadata struct {
 	Description   string   `json:"description"`
 	Tags          []string `json:"tags"`
 	Compatibility string   `json:"compatibility,omitempty"`
+	License       string   `json:"license,omitempty"`
+	Channel       string   `json:"channel,omitempty"` // 发布通道：stable（默认）或 beta
+	SHA256        string   `json:"sha256,omitempty"`  // 技能目录的内容摘要，格式为 sha256:<hex>，导入和更新时校验
+	// 使用统计由远程仓库维护，本地重新生成注册表时保留
+	Downloads   int     `json:"downloads,omitempty"`    // 下载次数
+	Rating      float64 `json:"rating,omitempty"`       // 社区评分，0-5
+	RatingCount int     `json:"rating_count,omitempty"` // 评分人数
+	// Archive 静态HTTP注册表中技能归档的地址，相对 index.json 所在目录或完整URL，为空时使用默认路径
+	Archive string `json:"archive,omitempty"`
 }
 
 // Registry 表示技能仓库的索引
@@ -53,6 +71,18 @@ type Registry struct {
 	Skills  []SkillMetadata `json:"skills"`
 }
 
+// Bundle 表示一组预设变量的技能组合，例如团队标准的 go-backend 技能栈
+type Bundle struct {
+	Description string        `yaml:"description,omitempty" json:"description,omitempty"`
+	Skills      []BundleSkill `yaml:"skills" json:"skills"`
+}
+
+// BundleSkill 技能组合中的技能及其预设变量
+type BundleSkill struct {
+	ID        string            `yaml:"id" json:"id"`
+	Variables map[string]string `yaml:"variables,omitempty" json:"variables,omitempty"`
+}
+
 // ProjectConfig 表示项目的配置信息（符合文档设计）
 type ProjectConfig struct {
 	PreferredTarget string            `json:"preferred_target,omitempty"` // cursor, claude_code, 或空
@@ -67,10 +97,17 @@ const (
 	TargetClaudeCode = "claude_code"
 	TargetOpenCode   = "open_code" // OpenCode支持
 	TargetClaude     = "claude"    // 向后兼容
+	TargetShell      = "shell"     // 脚本型技能
 	TargetUnknown    = "unknown"
 	TargetAll        = "all"
 )
 
+// 配置模式常量
+const (
+	ModeProject = "project" // 写入项目目录中的配置文件
+	ModeGlobal  = "global"  // 写入用户目录中的全局配置文件
+)
+
 // NormalizeTarget 规范化目标类型（处理向后兼容）
 func NormalizeTarget(target string) string {
 	if target == TargetClaude {
@@ -82,19 +119,95 @@ func NormalizeTarget(target string) string {
 	return target
 }
 
+// SupportsTarget 检查技能是否兼容目标工具
+//
+// 脚本型技能必须在 compatibility 中显式声明 shell，避免把普通提示词安装为脚本；
+// 其他目标在未声明兼容性时视为兼容。
+func (s *Skill) SupportsTarget(target string) bool {
+	target = NormalizeTarget(target)
+	if s.Compatibility == "" {
+		return target == TargetCursor || target == TargetClaudeCode || target == TargetOpenCode
+	}
+	return s.Compatible().Supports(target)
+}
+
 // ProjectState 表示项目与技能的关联状态（向后兼容）
 type ProjectState struct {
-	ProjectPath     string               `json:"project_path"`
-	PreferredTarget string               `json:"preferred_target,omitempty"` // cursor, claude_code, 或空
-	Skills          map[string]SkillVars `json:"skills"`
-	LastSync        string               `json:"last_sync,omitempty"`
+	ProjectPath     string `json:"project_path"`
+	ProjectID       string `json:"project_id,omitempty"`       // 由Git远程地址和仓库内路径计算的稳定标识，用于识别移动后的项目
+	PreferredTarget string `json:"preferred_target,omitempty"` // cursor, claude_code, 或空
+	// PreferredTargets 按顺序绑定的多个目标，第一个与 PreferredTarget 相同；只绑定一个目标时为空
+	PreferredTargets []string             `json:"preferred_targets,omitempty"`
+	Locale           string               `json:"locale,omitempty"` // 技能提示词的语言，例如 zh-CN；为空时使用全局配置
+	Skills           map[string]SkillVars `json:"skills"`
+	Profiles         map[string]Profile   `json:"profiles,omitempty"`        // 命名的变量配置，apply --profile 选择
+	DefaultProfile   string               `json:"default_profile,omitempty"` // 未指定 --profile 时使用的配置
+	// MemberVariables 工作区根目录的状态中，各成员项目覆盖的技能变量：成员相对路径 -> 技能ID -> 变量名 -> 值
+	MemberVariables map[string]map[string]map[string]string `json:"member_variables,omitempty"`
+	LastSync        string                                  `json:"last_sync,omitempty"`
+	LastApply       *ApplyReport                            `json:"last_apply,omitempty"` // 最近一次 apply 的结果
+}
+
+// BoundTargets 返回项目绑定的目标（已规范化），按绑定顺序排列；未绑定时返回 nil
+func (s *ProjectState) BoundTargets() []string {
+	if len(s.PreferredTargets) > 0 {
+		targets := make([]string, 0, len(s.PreferredTargets))
+		for _, target := range s.PreferredTargets {
+			targets = append(targets, NormalizeTarget(target))
+		}
+		return targets
+	}
+	if s.PreferredTarget != "" {
+		return []string{NormalizeTarget(s.PreferredTarget)}
+	}
+	return nil
+}
+
+// Profile 项目的一组变量配置，覆盖技能的默认变量值
+//
+// 变量按 技能变量 <- 继承的配置 <- 本配置 的顺序合并，后者覆盖前者。
+type Profile struct {
+	Extends   string                       `json:"extends,omitempty"`   // 继承的配置名称
+	Variables map[string]map[string]string `json:"variables,omitempty"` // 技能ID -> 变量名 -> 值
 }
 
 // SkillVars 表示项目中某个技能的变量配置
 type SkillVars struct {
-	SkillID   string            `json:"skill_id"`
+	SkillID   string                       `json:"skill_id"`
+	Version   string                       `json:"version"`
+	Variables map[string]string            `json:"variables"`
+	History   map[string][]AppliedRevision `json:"history,omitempty"`   // 按目标记录的已应用内容，用于回滚
+	Resources []string                     `json:"resources,omitempty"` // 已安装到项目中的资源文件（相对项目根目录）
+	Applied   map[string]AppliedRecord     `json:"applied,omitempty"`   // 按目标记录技能当前应用到目标工具的内容摘要
+	Priority  *int                         `json:"priority,omitempty"`  // 项目覆盖的应用顺序优先级，为空时使用技能声明的优先级
+}
+
+// AppliedRecord 表示技能当前应用在某个目标工具中的内容
+//
+// 与 AppliedRevision 不同，只保存内容的哈希，用于检测目标文件中的手动修改和判断技能实际应用到了哪些目标。
+type AppliedRecord struct {
+	Version   string `json:"version"`
+	Hash      string `json:"hash"` // 渲染内容（去除首尾空白）的 SHA-256
+	AppliedAt string `json:"applied_at"`
+	Mode      string `json:"mode,omitempty"` // 应用时的配置模式，为空表示 project
+}
+
+// AppliedMode 返回技能应用时的配置模式
+func (r AppliedRecord) AppliedMode() string {
+	if r.Mode == "" {
+		return ModeProject
+	}
+	return r.Mode
+}
+
+// AppliedRevision 表示技能某次应用到目标工具的渲染内容
+type AppliedRevision struct {
 	Version   string            `json:"version"`
-	Variables map[string]string `json:"variables"`
+	Variables map[string]string `json:"variables,omitempty"`
+	Profile   string            `json:"profile,omitempty"` // 应用时使用的变量配置
+	Content   string            `json:"content"`           // 渲染后的技能内容
+	AppliedAt string            `json:"applied_at"`
+	Mode      string            `json:"mode,omitempty"` // 应用时的配置模式，为空表示 project
 }
 
 // CreateOptions 创建技能选项
@@ -0,0 +1,148 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Agent Skills 规范对 frontmatter 字段的长度限制
+const (
+	MaxSkillNameLength          = 64
+	MaxSkillDescriptionLength   = 1024
+	MaxSkillCompatibilityLength = 500
+)
+
+// SkillMDOptions 序列化 SKILL.md 的选项
+type SkillMDOptions struct {
+	// Strict 只输出 Agent Skills 规范定义的字段，省略 variables、requires、claude 等 skill-hub 扩展字段
+	Strict bool
+}
+
+// skillMDFrontmatter SKILL.md 的frontmatter，字段顺序即输出顺序
+type skillMDFrontmatter struct {
+	Name          string            `yaml:"name"`
+	Description   string            `yaml:"description"`
+	License       string            `yaml:"license,omitempty"`
+	Compatibility string            `yaml:"compatibility,omitempty"`
+	AllowedTools  string            `yaml:"allowed-tools,omitempty"`
+	Metadata      map[string]string `yaml:"metadata,omitempty"`
+	Variables     []Variable        `yaml:"variables,omitempty"`
+	Requires      *Requirements     `yaml:"requires,omitempty"`
+	Claude        *ClaudeConfig     `yaml:"claude,omitempty"`
+}
+
+// MarshalSkillMD 将技能定义和正文序列化为符合 Agent Skills 规范的 SKILL.md
+//
+// name 使用技能ID中不含命名空间的部分，与技能目录名一致；version、author、tags、dependencies
+// 以及与 name 不同的显示名称和命名空间写入 metadata。allowed-tools 输出为空格分隔的字符串，
+// 工具名包含空格时改用逗号分隔。
+func MarshalSkillMD(skill *Skill, body string, opts SkillMDOptions) ([]byte, error) {
+	id := skill.ID
+	if id == "" {
+		id = skill.Name
+	}
+	namespace, name := SplitSkillID(id)
+	if !skillNamePattern.MatchString(name) || len(name) > MaxSkillNameLength {
+		return nil, fmt.Errorf("无效的技能名称 '%s'：只能包含小写字母、数字和连字符，不能以连字符开头或结尾，且不超过 %d 个字符", name, MaxSkillNameLength)
+	}
+	description := strings.TrimSpace(skill.Description)
+	if description == "" {
+		return nil, fmt.Errorf("技能 '%s' 缺少描述", id)
+	}
+	if utf8.RuneCountInString(description) > MaxSkillDescriptionLength {
+		return nil, fmt.Errorf("技能 '%s' 的描述超过 %d 个字符", id, MaxSkillDescriptionLength)
+	}
+	if utf8.RuneCountInString(skill.Compatibility) > MaxSkillCompatibilityLength {
+		return nil, fmt.Errorf("技能 '%s' 的兼容性说明超过 %d 个字符", id, MaxSkillCompatibilityLength)
+	}
+
+	fm := skillMDFrontmatter{
+		Name:          name,
+		Description:   description,
+		License:       skill.License,
+		Compatibility: skill.Compatibility,
+		AllowedTools:  JoinAllowedTools(skill.AllowedTools),
+		Metadata:      map[string]string{},
+	}
+	if namespace != "" {
+		fm.Metadata["namespace"] = namespace
+	}
+	if skill.Name != "" && skill.Name != name && skill.Name != id {
+		fm.Metadata["display_name"] = skill.Name
+	}
+	if skill.Version != "" {
+		fm.Metadata["version"] = skill.Version
+	}
+	if skill.Author != "" {
+		fm.Metadata["author"] = skill.Author
+	}
+	if len(skill.Tags) > 0 {
+		fm.Metadata["tags"] = strings.Join(skill.Tags, ",")
+	}
+	if len(skill.Dependencies) > 0 {
+		fm.Metadata["dependencies"] = strings.Join(skill.Dependencies, ",")
+	}
+	if !opts.Strict {
+		fm.Variables = skill.Variables
+		fm.Requires = skill.Requires
+		fm.Claude = skill.Claude
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(fm); err != nil {
+		return nil, fmt.Errorf("序列化frontmatter失败: %w", err)
+	}
+	encoder.Close()
+	buf.WriteString("---\n")
+
+	body = strings.TrimLeft(body, "\n")
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	buf.WriteString(body)
+	return buf.Bytes(), nil
+}
+
+// JoinAllowedTools 按规范使用空格分隔工具列表，工具名包含空格（如 Bash(git add:*)）时使用逗号分隔
+func JoinAllowedTools(tools []string) string {
+	separator := " "
+	for _, tool := range tools {
+		if strings.ContainsAny(tool, " \t") {
+			separator = ", "
+			break
+		}
+	}
+	return strings.Join(tools, separator)
+}
+
+// ParseAllowedTools 解析 frontmatter 中的 allowed-tools，支持 YAML 列表、逗号分隔和空格分隔的字符串
+func ParseAllowedTools(value interface{}) []string {
+	var raw []string
+	switch v := value.(type) {
+	case string:
+		if strings.Contains(v, ",") {
+			raw = strings.Split(v, ",")
+		} else {
+			raw = strings.Fields(v)
+		}
+	case []interface{}:
+		for _, item := range v {
+			raw = append(raw, fmt.Sprint(item))
+		}
+	}
+
+	var tools []string
+	for _, tool := range raw {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
@@ -0,0 +1,97 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalSkillMD(t *testing.T) {
+	skill := &Skill{
+		ID:           "acme/git-expert",
+		Name:         "Git Expert",
+		Version:      "1.2.0",
+		Author:       "team",
+		Description:  "生成提交说明",
+		Tags:         []string{"git", "vcs"},
+		License:      "MIT",
+		AllowedTools: []string{"Bash(git:*)", "Read"},
+		Variables:    []Variable{{Name: "LANGUAGE", Default: "zh"}},
+	}
+
+	content, err := MarshalSkillMD(skill, "# Git\n\n使用 {{.LANGUAGE}} 回答", SkillMDOptions{})
+	if err != nil {
+		t.Fatalf("MarshalSkillMD() error = %v", err)
+	}
+	text := string(content)
+	if !strings.HasPrefix(text, "---\nname: git-expert\ndescription: 生成提交说明\nlicense: MIT\n") {
+		t.Errorf("unexpected frontmatter order:\n%s", text)
+	}
+	if !strings.HasSuffix(text, "---\n# Git\n\n使用 {{.LANGUAGE}} 回答\n") {
+		t.Errorf("body not preserved:\n%s", text)
+	}
+
+	frontmatter := strings.SplitN(text, "---\n", 3)[1]
+	var fm map[string]interface{}
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		t.Fatalf("invalid frontmatter: %v", err)
+	}
+	if got := ParseAllowedTools(fm["allowed-tools"]); strings.Join(got, "|") != "Bash(git:*)|Read" {
+		t.Errorf("allowed-tools = %v", got)
+	}
+	metadata, _ := fm["metadata"].(map[string]interface{})
+	if metadata["namespace"] != "acme" || metadata["display_name"] != "Git Expert" || metadata["version"] != "1.2.0" || metadata["tags"] != "git,vcs" {
+		t.Errorf("metadata = %v", metadata)
+	}
+	if _, ok := fm["variables"]; !ok {
+		t.Error("variables should be kept without Strict")
+	}
+
+	strict, err := MarshalSkillMD(skill, "", SkillMDOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("MarshalSkillMD(strict) error = %v", err)
+	}
+	if strings.Contains(string(strict), "variables:") {
+		t.Errorf("strict output should omit extensions:\n%s", strict)
+	}
+}
+
+func TestMarshalSkillMDErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		skill *Skill
+	}{
+		{"invalid name", &Skill{ID: "Git_Expert", Description: "x"}},
+		{"long name", &Skill{ID: strings.Repeat("a", MaxSkillNameLength+1), Description: "x"}},
+		{"missing description", &Skill{ID: "git-expert"}},
+		{"long description", &Skill{ID: "git-expert", Description: strings.Repeat("描", MaxSkillDescriptionLength+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MarshalSkillMD(tt.skill, "", SkillMDOptions{}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseAllowedTools(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{"Bash(git:*) Read", "Bash(git:*)|Read"},
+		{"Bash(git add:*), Read", "Bash(git add:*)|Read"},
+		{[]interface{}{"Read", " Write "}, "Read|Write"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(ParseAllowedTools(tt.value), "|"); got != tt.want {
+			t.Errorf("ParseAllowedTools(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+	if got := JoinAllowedTools([]string{"Bash(git add:*)", "Read"}); got != "Bash(git add:*), Read" {
+		t.Errorf("JoinAllowedTools() = %q", got)
+	}
+}
@@ -0,0 +1,90 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 标签的数量和长度限制
+const (
+	MaxTags      = 10
+	MaxTagLength = 32
+)
+
+// tagPattern 规范的标签：小写字母、数字和连字符，例如 golang、code-review
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// NormalizeTag 规范化标签：去除首尾空白、转为小写，空格和下划线替换为连字符，例如 "Code Review" 规范化为 code-review
+func NormalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	return strings.Join(strings.FieldsFunc(tag, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '\t'
+	}), "-")
+}
+
+// ValidateTag 检查标签是否为规范格式
+func ValidateTag(tag string) error {
+	if len(tag) > MaxTagLength {
+		return fmt.Errorf("标签 '%s' 过长，不能超过 %d 个字符", tag, MaxTagLength)
+	}
+	if !tagPattern.MatchString(tag) {
+		return fmt.Errorf("标签 '%s' 不符合规范：只能包含小写字母、数字和连字符，例如 %s", tag, NormalizeTag(tag))
+	}
+	return nil
+}
+
+// HasTags 检查技能是否包含所有指定的标签，比较时忽略大小写和分隔符差异
+func (s *Skill) HasTags(tags []string) bool {
+	own := make(map[string]bool, len(s.Tags))
+	for _, tag := range s.Tags {
+		own[NormalizeTag(tag)] = true
+	}
+	for _, tag := range tags {
+		if !own[NormalizeTag(tag)] {
+			return false
+		}
+	}
+	return true
+}
+
+// TagCount 标签及使用它的技能
+type TagCount struct {
+	Tag    string   `json:"tag"`
+	Count  int      `json:"count"`
+	Skills []string `json:"skills"`
+}
+
+// CountTags 按规范化的标签汇总技能，按使用次数从多到少排序，次数相同时按标签排序
+func CountTags(skills []*Skill) []TagCount {
+	index := make(map[string]*TagCount)
+	for _, skill := range skills {
+		seen := make(map[string]bool)
+		for _, tag := range skill.Tags {
+			tag = NormalizeTag(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			if index[tag] == nil {
+				index[tag] = &TagCount{Tag: tag}
+			}
+			index[tag].Count++
+			index[tag].Skills = append(index[tag].Skills, skill.ID)
+		}
+	}
+
+	counts := make([]TagCount, 0, len(index))
+	for _, count := range index {
+		sort.Strings(count.Skills)
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts
+}
@@ -0,0 +1,61 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeAndValidateTag(t *testing.T) {
+	tests := []struct {
+		tag        string
+		normalized string
+		valid      bool
+	}{
+		{"golang", "golang", true},
+		{"code-review", "code-review", true},
+		{" Code Review ", "code-review", false},
+		{"unit_testing", "unit-testing", false},
+		{"Go", "go", false},
+		{"-go", "-go", false},
+		{"c++", "c++", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := NormalizeTag(tt.tag); got != tt.normalized {
+				t.Errorf("NormalizeTag() = %q, want %q", got, tt.normalized)
+			}
+			if err := ValidateTag(tt.tag); (err == nil) != tt.valid {
+				t.Errorf("ValidateTag() error = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestSkillTags(t *testing.T) {
+	skills := []*Skill{
+		{ID: "go-test", Tags: []string{"Golang", "testing"}},
+		{ID: "go-lint", Tags: []string{"golang", "lint", "golang"}},
+		{ID: "react", Tags: []string{"frontend", "Testing"}},
+		{ID: "plain"},
+	}
+
+	if !skills[0].HasTags([]string{"golang", "TESTING"}) {
+		t.Error("HasTags() should ignore case")
+	}
+	if skills[1].HasTags([]string{"golang", "testing"}) {
+		t.Error("HasTags() should require all tags")
+	}
+	if !skills[3].HasTags(nil) {
+		t.Error("HasTags() with no tags should match")
+	}
+
+	want := []TagCount{
+		{Tag: "golang", Count: 2, Skills: []string{"go-lint", "go-test"}},
+		{Tag: "testing", Count: 2, Skills: []string{"go-test", "react"}},
+		{Tag: "frontend", Count: 1, Skills: []string{"react"}},
+		{Tag: "lint", Count: 1, Skills: []string{"go-lint"}},
+	}
+	if got := CountTags(skills); !reflect.DeepEqual(got, want) {
+		t.Errorf("CountTags() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,127 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 变量类型
+const (
+	VarTypeString = "string"
+	VarTypeInt    = "int"
+	VarTypeBool   = "bool"
+	VarTypeEnum   = "enum"
+)
+
+// IsRequired 检查变量是否必须有值。未显式声明 required 时，没有默认值的变量必须输入
+func (v Variable) IsRequired() bool {
+	if v.Required != nil {
+		return *v.Required
+	}
+	return v.Default == ""
+}
+
+// Hint 返回提示用户输入时显示的类型说明，string 类型且没有可选值时为空
+func (v Variable) Hint() string {
+	if len(v.Options) > 0 {
+		return strings.Join(v.Options, "|")
+	}
+	switch v.Type {
+	case VarTypeInt, VarTypeBool:
+		return v.Type
+	}
+	return ""
+}
+
+// CheckDefinition 检查变量声明本身是否有效
+func (v Variable) CheckDefinition() error {
+	switch v.Type {
+	case "", VarTypeString, VarTypeInt, VarTypeBool:
+	case VarTypeEnum:
+		if len(v.Options) == 0 {
+			return fmt.Errorf("变量 %s 为 enum 类型，但没有声明 options", v.Name)
+		}
+	default:
+		return fmt.Errorf("变量 %s 的类型 %s 无效，可用类型: string, int, bool, enum", v.Name, v.Type)
+	}
+	if v.Pattern != "" {
+		if _, err := regexp.Compile(v.Pattern); err != nil {
+			return fmt.Errorf("变量 %s 的 pattern 无效: %w", v.Name, err)
+		}
+	}
+	if v.Default != "" {
+		if _, err := v.Normalize(v.Default); err != nil {
+			return fmt.Errorf("变量 %s 的默认值无效: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// Normalize 按变量声明校验值，并返回规范形式：bool 为 true/false，int 为十进制整数
+//
+// 空值只在变量不是必填时有效，原样返回。
+func (v Variable) Normalize(value string) (string, error) {
+	if value == "" {
+		if v.IsRequired() {
+			if v.Default == "" {
+				return "", fmt.Errorf("变量 %s 没有默认值，必须输入", v.Name)
+			}
+			return "", fmt.Errorf("变量 %s 必须输入", v.Name)
+		}
+		return "", nil
+	}
+
+	switch v.Type {
+	case VarTypeInt:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("变量 %s 应为整数，实际为 %q", v.Name, value)
+		}
+		value = strconv.Itoa(n)
+	case VarTypeBool:
+		b, ok := parseBool(value)
+		if !ok {
+			return "", fmt.Errorf("变量 %s 应为布尔值 (true/false)，实际为 %q", v.Name, value)
+		}
+		value = strconv.FormatBool(b)
+	}
+
+	if len(v.Options) > 0 {
+		valid := false
+		for _, option := range v.Options {
+			if option == value {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return "", fmt.Errorf("变量 %s 的值 %q 不在可选值 %s 中", v.Name, value, strings.Join(v.Options, ", "))
+		}
+	}
+
+	if v.Pattern != "" {
+		re, err := regexp.Compile(`^(?:` + v.Pattern + `)$`)
+		if err != nil {
+			return "", fmt.Errorf("变量 %s 的 pattern 无效: %w", v.Name, err)
+		}
+		if !re.MatchString(value) {
+			return "", fmt.Errorf("变量 %s 的值 %q 不匹配格式 %s", v.Name, value, v.Pattern)
+		}
+	}
+
+	return value, nil
+}
+
+// parseBool 解析布尔值，除 strconv.ParseBool 支持的形式外还接受 yes/no、y/n 和 on/off
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "y", "on":
+		return true, true
+	case "no", "n", "off":
+		return false, true
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	return b, err == nil
+}
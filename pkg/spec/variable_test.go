@@ -0,0 +1,61 @@
+package spec
+
+import "testing"
+
+func TestVariableNormalize(t *testing.T) {
+	optional := false
+	tests := []struct {
+		name     string
+		variable Variable
+		value    string
+		want     string
+		wantErr  bool
+	}{
+		{"字符串原样返回", Variable{Name: "A"}, "hello", "hello", false},
+		{"必填变量为空", Variable{Name: "A"}, "", "", true},
+		{"有默认值的变量可为空", Variable{Name: "A", Default: "x"}, "", "", false},
+		{"显式声明非必填", Variable{Name: "A", Required: &optional}, "", "", false},
+		{"整数规范化", Variable{Name: "N", Type: VarTypeInt}, " 007 ", "7", false},
+		{"无效整数", Variable{Name: "N", Type: VarTypeInt}, "seven", "", true},
+		{"布尔值 yes", Variable{Name: "B", Type: VarTypeBool}, "Yes", "true", false},
+		{"布尔值 0", Variable{Name: "B", Type: VarTypeBool}, "0", "false", false},
+		{"无效布尔值", Variable{Name: "B", Type: VarTypeBool}, "maybe", "", true},
+		{"可选值", Variable{Name: "E", Type: VarTypeEnum, Options: []string{"go", "rust"}}, "rust", "rust", false},
+		{"不在可选值中", Variable{Name: "E", Type: VarTypeEnum, Options: []string{"go", "rust"}}, "java", "", true},
+		{"匹配格式", Variable{Name: "P", Pattern: `[a-z]+-\d+`}, "app-1", "app-1", false},
+		{"格式需完整匹配", Variable{Name: "P", Pattern: `[a-z]+-\d+`}, "app-1x", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.variable.Normalize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariableCheckDefinition(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable Variable
+		wantErr  bool
+	}{
+		{"默认字符串", Variable{Name: "A"}, false},
+		{"未知类型", Variable{Name: "A", Type: "float"}, true},
+		{"enum 缺少可选值", Variable{Name: "A", Type: VarTypeEnum}, true},
+		{"无效 pattern", Variable{Name: "A", Pattern: "("}, true},
+		{"默认值类型错误", Variable{Name: "A", Type: VarTypeInt, Default: "x"}, true},
+		{"有效声明", Variable{Name: "A", Type: VarTypeBool, Default: "no"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.variable.CheckDefinition(); (err != nil) != tt.wantErr {
+				t.Errorf("CheckDefinition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -71,14 +71,22 @@ const (
 	// metadata警告
 	WarnMetadataWrongType = "METADATA_WRONG_TYPE_WARNING"
 	WarnMetadataValueType = "METADATA_VALUE_TYPE_WARNING"
+	WarnChannelInvalid    = "CHANNEL_INVALID_WARNING"
 
 	// license警告
 	WarnLicenseWrongType = "LICENSE_WRONG_TYPE_WARNING"
 	WarnLicenseTooLong   = "LICENSE_TOO_LONG_WARNING"
+	WarnLicenseNotSPDX   = "LICENSE_NOT_SPDX_WARNING"
 
 	// allowed-tools警告
 	WarnAllowedToolsWrongType = "ALLOWED_TOOLS_WRONG_TYPE_WARNING"
 
+	// tags警告
+	WarnTagsWrongType    = "TAGS_WRONG_TYPE_WARNING"
+	WarnTagInvalidFormat = "TAG_INVALID_FORMAT_WARNING"
+	WarnTagDuplicate     = "TAG_DUPLICATE_WARNING"
+	WarnTooManyTags      = "TOO_MANY_TAGS_WARNING"
+
 	// 目录结构警告
 	WarnDirectoryMismatch = "DIRECTORY_MISMATCH_WARNING"
 )
@@ -116,9 +124,15 @@ var warningMessages = map[string]string{
 	WarnCompatUnknownType:     "compatibility字段类型未知",
 	WarnMetadataWrongType:     "metadata字段类型可能不符合规范",
 	WarnMetadataValueType:     "metadata值类型可能不符合规范",
+	WarnChannelInvalid:        "发布通道应为 stable 或 beta，update 不会更新到其他通道发布的版本",
 	WarnLicenseWrongType:      "license字段类型可能不符合规范",
 	WarnLicenseTooLong:        "license字段建议保持简短",
+	WarnLicenseNotSPDX:        "license不是有效的SPDX许可证标识符或表达式（如 MIT、Apache-2.0 OR MIT），也没有引用技能目录中的许可证文件",
 	WarnAllowedToolsWrongType: "allowed-tools字段类型可能不符合规范",
+	WarnTagsWrongType:         "tags应该是逗号分隔的字符串或字符串列表",
+	WarnTagInvalidFormat:      "标签应只包含小写字母、数字和连字符，且不超过32个字符",
+	WarnTagDuplicate:          "标签重复（忽略大小写和分隔符差异）",
+	WarnTooManyTags:           "标签过多，建议不超过10个",
 	WarnDirectoryMismatch:     "name字段与目录名不匹配",
 }
 
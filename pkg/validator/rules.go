@@ -1,8 +1,12 @@
 package validator
 
 import (
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+
+	"skill-hub/pkg/spec"
 )
 
 // Rule 校验规则接口
@@ -199,6 +203,11 @@ func (r *MetadataRule) Validate(result *ValidationResult) bool {
 				result.AddWarning(NewWarning(WarnMetadataValueType, "metadata."+key, false))
 			}
 		}
+		if channel, ok := v["channel"].(string); ok {
+			if _, err := spec.NormalizeChannel(channel); err != nil {
+				result.AddWarning(NewWarning(WarnChannelInvalid, "metadata.channel", false))
+			}
+		}
 	default:
 		result.AddWarning(NewWarning(WarnMetadataWrongType, "metadata", false))
 	}
@@ -227,6 +236,10 @@ func (r *LicenseRule) Validate(result *ValidationResult) bool {
 		if len(v) > 200 {
 			result.AddWarning(NewWarning(WarnLicenseTooLong, "license", true))
 		}
+		// 规范允许 SPDX 标识符或对技能目录中许可证文件的引用
+		if !IsSPDXExpression(v) && !referencesLicenseFile(result.FilePath, v) {
+			result.AddWarning(NewWarning(WarnLicenseNotSPDX, "license", false))
+		}
 	default:
 		result.AddWarning(NewWarning(WarnLicenseWrongType, "license", false))
 	}
@@ -234,6 +247,24 @@ func (r *LicenseRule) Validate(result *ValidationResult) bool {
 	return true
 }
 
+// referencesLicenseFile 检查 license 中是否引用了技能目录中存在的文件，例如 "Proprietary. LICENSE.txt has complete terms"
+func referencesLicenseFile(skillPath, license string) bool {
+	if skillPath == "" {
+		return false
+	}
+	dir := filepath.Dir(skillPath)
+	for _, word := range strings.Fields(license) {
+		word = strings.Trim(word, ".,;:()\"'`")
+		if word == "" {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, word)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
 // AllowedToolsRule 检查allowed-tools字段规则
 type AllowedToolsRule struct {
 	BaseRule
@@ -259,3 +290,69 @@ func (r *AllowedToolsRule) Validate(result *ValidationResult) bool {
 
 	return true
 }
+
+// TagsRule 检查tags规则，标签可以写在顶层或 metadata 中
+type TagsRule struct {
+	BaseRule
+}
+
+func NewTagsRule() *TagsRule {
+	return &TagsRule{BaseRule{name: "tags"}}
+}
+
+func (r *TagsRule) Validate(result *ValidationResult) bool {
+	field := "tags"
+	tagsValue, ok := result.Frontmatter["tags"]
+	if !ok {
+		metadata, _ := result.Frontmatter["metadata"].(map[string]interface{})
+		if tagsValue, ok = metadata["tags"]; !ok {
+			// tags是可选的
+			return true
+		}
+		field = "metadata.tags"
+	}
+
+	var tags []string
+	switch v := tagsValue.(type) {
+	case string:
+		tags = strings.Split(v, ",")
+	case []interface{}:
+		for _, item := range v {
+			tag, ok := item.(string)
+			if !ok {
+				result.AddWarning(NewWarning(WarnTagsWrongType, field, false))
+				return true
+			}
+			tags = append(tags, tag)
+		}
+	default:
+		result.AddWarning(NewWarning(WarnTagsWrongType, field, false))
+		return true
+	}
+
+	seen := make(map[string]bool)
+	count := 0
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag == "" {
+			continue
+		}
+		count++
+		if spec.ValidateTag(tag) != nil {
+			warning := NewWarning(WarnTagInvalidFormat, field, false)
+			warning.Message += ": " + tag
+			result.AddWarning(warning)
+		}
+		normalized := spec.NormalizeTag(tag)
+		if seen[normalized] {
+			warning := NewWarning(WarnTagDuplicate, field, false)
+			warning.Message += ": " + tag
+			result.AddWarning(warning)
+		}
+		seen[normalized] = true
+	}
+	if count > spec.MaxTags {
+		result.AddWarning(NewWarning(WarnTooManyTags, field, false))
+	}
+
+	return true
+}
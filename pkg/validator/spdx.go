@@ -0,0 +1,134 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// spdxLicenses 常用的 SPDX 许可证标识符（https://spdx.org/licenses/），按小写索引
+var spdxLicenses = newSPDXIndex(
+	"0BSD", "AAL", "AFL-1.1", "AFL-1.2", "AFL-2.0", "AFL-2.1", "AFL-3.0", "AGPL-1.0-only", "AGPL-1.0-or-later",
+	"AGPL-3.0", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.0", "Apache-1.1", "Apache-2.0", "APSL-2.0",
+	"Artistic-1.0", "Artistic-2.0", "Beerware", "BlueOak-1.0.0", "BSD-1-Clause", "BSD-2-Clause",
+	"BSD-2-Clause-Patent", "BSD-3-Clause", "BSD-3-Clause-Clear", "BSD-4-Clause", "BSL-1.0", "BUSL-1.1",
+	"CAL-1.0", "CC-BY-1.0", "CC-BY-2.0", "CC-BY-2.5", "CC-BY-3.0", "CC-BY-4.0", "CC-BY-NC-4.0",
+	"CC-BY-NC-ND-4.0", "CC-BY-NC-SA-4.0", "CC-BY-ND-4.0", "CC-BY-SA-3.0", "CC-BY-SA-4.0", "CC0-1.0",
+	"CDDL-1.0", "CDDL-1.1", "CECILL-2.1", "CECILL-B", "CECILL-C", "ECL-2.0", "EFL-2.0", "Elastic-2.0",
+	"EPL-1.0", "EPL-2.0", "EUPL-1.1", "EUPL-1.2", "FSFAP", "FTL", "GFDL-1.3-only", "GFDL-1.3-or-later",
+	"GPL-1.0-only", "GPL-1.0-or-later", "GPL-2.0", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0",
+	"GPL-3.0-only", "GPL-3.0-or-later", "HPND", "ICU", "IJG", "Imlib2", "IPA", "IPL-1.0", "ISC", "LGPL-2.0-only",
+	"LGPL-2.0-or-later", "LGPL-2.1", "LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-3.0", "LGPL-3.0-only",
+	"LGPL-3.0-or-later", "LPL-1.02", "LPPL-1.3c", "MIT", "MIT-0", "MIT-CMU", "MirOS", "MPL-1.0", "MPL-1.1",
+	"MPL-2.0", "MPL-2.0-no-copyleft-exception", "MS-PL", "MS-RL", "MulanPSL-1.0", "MulanPSL-2.0", "NCSA",
+	"Nokia", "ODbL-1.0", "OFL-1.0", "OFL-1.1", "OLDAP-2.8", "OpenSSL", "OSL-1.0", "OSL-2.0", "OSL-2.1",
+	"OSL-3.0", "PHP-3.0", "PHP-3.01", "PostgreSQL", "PSF-2.0", "Python-2.0", "QPL-1.0", "RPL-1.5", "RPSL-1.0",
+	"Ruby", "SGI-B-2.0", "SISSL", "Sleepycat", "SSPL-1.0", "TCL", "UCL-1.0", "Unicode-3.0", "Unicode-DFS-2016",
+	"Unlicense", "UPL-1.0", "Vim", "W3C", "WTFPL", "X11", "XFree86-1.1", "Xnet", "Zend-2.0", "Zlib",
+	"zlib-acknowledgement", "ZPL-2.0", "ZPL-2.1",
+)
+
+// spdxExceptions 常用的 SPDX 许可证例外标识符，用于 WITH 表达式
+var spdxExceptions = newSPDXIndex(
+	"Autoconf-exception-3.0", "Bison-exception-2.2", "Classpath-exception-2.0", "Font-exception-2.0",
+	"GCC-exception-3.1", "LLVM-exception", "Linux-syscall-note", "OpenJDK-assembly-exception-1.0",
+	"Qt-GPL-exception-1.0", "Swift-exception", "u-boot-exception-2.0",
+)
+
+// spdxLicenseRef 自定义许可证引用，例如 LicenseRef-Proprietary 或 DocumentRef-spdx:LicenseRef-MyLicense
+var spdxLicenseRef = regexp.MustCompile(`^(DocumentRef-[A-Za-z0-9.-]+:)?LicenseRef-[A-Za-z0-9.-]+$`)
+
+func newSPDXIndex(ids ...string) map[string]bool {
+	index := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		index[strings.ToLower(id)] = true
+	}
+	return index
+}
+
+// IsSPDXExpression 检查 license 是否为 SPDX 许可证表达式
+//
+// 支持单个标识符（MIT）、"+" 后缀、LicenseRef-* 引用、AND/OR/WITH 组合和括号，标识符不区分大小写。
+func IsSPDXExpression(expr string) bool {
+	tokens := tokenizeSPDX(expr)
+	if len(tokens) == 0 {
+		return false
+	}
+	p := &spdxParser{tokens: tokens}
+	return p.parseExpression() && p.pos == len(p.tokens)
+}
+
+// tokenizeSPDX 将表达式拆分为标识符、运算符和括号
+func tokenizeSPDX(expr string) []string {
+	expr = strings.ReplaceAll(expr, "(", " ( ")
+	expr = strings.ReplaceAll(expr, ")", " ) ")
+	return strings.Fields(expr)
+}
+
+// spdxParser SPDX 表达式的递归下降解析器
+//
+//	expression = term { ("AND" | "OR") term }
+//	term       = "(" expression ")" | license [ "WITH" exception ]
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *spdxParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *spdxParser) parseExpression() bool {
+	if !p.parseTerm() {
+		return false
+	}
+	for {
+		switch op := p.peek(); op {
+		case "AND", "and", "OR", "or":
+			p.pos++
+			if !p.parseTerm() {
+				return false
+			}
+		default:
+			return true
+		}
+	}
+}
+
+func (p *spdxParser) parseTerm() bool {
+	token := p.peek()
+	if token == "" {
+		return false
+	}
+	p.pos++
+	if token == "(" {
+		if !p.parseExpression() || p.peek() != ")" {
+			return false
+		}
+		p.pos++
+		return true
+	}
+
+	if !isSPDXLicense(token) {
+		return false
+	}
+	if op := p.peek(); op == "WITH" || op == "with" {
+		p.pos++
+		exception := p.peek()
+		if !spdxExceptions[strings.ToLower(exception)] {
+			return false
+		}
+		p.pos++
+	}
+	return true
+}
+
+// isSPDXLicense 检查单个许可证标识符，允许 "+" 后缀表示该版本或更高版本
+func isSPDXLicense(id string) bool {
+	if spdxLicenseRef.MatchString(id) {
+		return true
+	}
+	return spdxLicenses[strings.ToLower(strings.TrimSuffix(id, "+"))]
+}
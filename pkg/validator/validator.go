@@ -24,6 +24,7 @@ func NewValidator() *Validator {
 			NewMetadataRule(),
 			NewLicenseRule(),
 			NewAllowedToolsRule(),
+			NewTagsRule(),
 		},
 	}
 }
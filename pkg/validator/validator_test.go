@@ -123,6 +123,66 @@ func TestValidator_ValidateSkill(t *testing.T) {
 			wantWarnings: 2, // DIRECTORY_MISMATCH_WARNING + COMPAT_OBJECT_FORMAT
 			wantValid:    true,
 		},
+		{
+			name:      "spdx license",
+			skillName: "test-skill",
+			frontmatter: map[string]interface{}{
+				"name":        "test-skill",
+				"description": "A test skill with a proper description.",
+				"license":     "Apache-2.0 OR MIT",
+			},
+			wantErrors:   0,
+			wantWarnings: 1, // DIRECTORY_MISMATCH_WARNING
+			wantValid:    true,
+		},
+		{
+			name:      "non-spdx license",
+			skillName: "test-skill",
+			frontmatter: map[string]interface{}{
+				"name":        "test-skill",
+				"description": "A test skill with a proper description.",
+				"license":     "Proprietary",
+			},
+			wantErrors:   0,
+			wantWarnings: 2, // DIRECTORY_MISMATCH_WARNING + LICENSE_NOT_SPDX
+			wantValid:    true,
+		},
+		{
+			name:      "metadata tags",
+			skillName: "test-skill",
+			frontmatter: map[string]interface{}{
+				"name":        "test-skill",
+				"description": "A test skill with a proper description.",
+				"metadata":    map[string]interface{}{"tags": "golang, code-review"},
+			},
+			wantErrors:   0,
+			wantWarnings: 1, // DIRECTORY_MISMATCH_WARNING
+			wantValid:    true,
+		},
+		{
+			name:      "invalid tags",
+			skillName: "test-skill",
+			frontmatter: map[string]interface{}{
+				"name":        "test-skill",
+				"description": "A test skill with a proper description.",
+				"tags":        []interface{}{"Go", "go", "code review"},
+			},
+			wantErrors:   0,
+			wantWarnings: 4, // DIRECTORY_MISMATCH_WARNING + 2 x TAG_INVALID_FORMAT + TAG_DUPLICATE
+			wantValid:    true,
+		},
+		{
+			name:      "invalid channel",
+			skillName: "test-skill",
+			frontmatter: map[string]interface{}{
+				"name":        "test-skill",
+				"description": "A test skill with a proper description.",
+				"metadata":    map[string]interface{}{"channel": "nightly"},
+			},
+			wantErrors:   0,
+			wantWarnings: 2, // DIRECTORY_MISMATCH_WARNING + CHANNEL_INVALID
+			wantValid:    true,
+		},
 	}
 
 	v := NewValidator()
@@ -243,3 +303,28 @@ func TestValidator_ValidateWithOptions(t *testing.T) {
 		}
 	})
 }
+
+func TestIsSPDXExpression(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"MIT", true},
+		{"mit", true},
+		{"Apache-2.0 OR MIT", true},
+		{"GPL-2.0-or-later WITH Classpath-exception-2.0", true},
+		{"(MIT AND BSD-3-Clause) OR Apache-2.0", true},
+		{"LGPL-2.1+", true},
+		{"LicenseRef-Internal", true},
+		{"Proprietary", false},
+		{"MIT OR", false},
+		{"(MIT", false},
+		{"MIT WITH Unknown-exception", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsSPDXExpression(tt.expr); got != tt.want {
+			t.Errorf("IsSPDXExpression(%q) = %v, 期望 %v", tt.expr, got, tt.want)
+		}
+	}
+}